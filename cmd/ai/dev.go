@@ -9,12 +9,13 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/recera/gai/core"
 	"github.com/recera/gai/middleware"
-	"github.com/recera/gai/providers/openai"
+	"github.com/recera/gai/obs"
 	"github.com/recera/gai/stream"
 	"github.com/spf13/cobra"
 )
@@ -37,11 +38,20 @@ The server provides:
   - /api/chat - SSE endpoint for streaming chat responses
   - /api/chat/ndjson - NDJSON endpoint for streaming chat responses
   - /api/generate - Non-streaming text generation endpoint
+  - /api/config - Get (GET) or switch (POST) the active provider and model
+  - /api/providers - Lists the supported providers and whether they are configured
   - / - Web interface for testing
 
 Environment variables:
-  OPENAI_API_KEY - Required for OpenAI provider
-  PORT - Server port (default: 8080)`,
+  OPENAI_API_KEY    - Required for the OpenAI provider
+  ANTHROPIC_API_KEY - Required for the Anthropic provider
+  GROQ_API_KEY      - Required for the Groq provider
+  GOOGLE_API_KEY    - Required for the Gemini provider
+  OLLAMA_HOST       - Ollama server (default: http://localhost:11434)
+
+Examples:
+  ai dev serve --provider anthropic --model claude-sonnet-4-20250514
+  ai dev serve --provider groq --model llama-3.1-8b-instant`,
 	RunE: runServe,
 }
 
@@ -56,27 +66,31 @@ func init() {
 	devCmd.AddCommand(serveCmd)
 
 	serveCmd.Flags().StringVarP(&port, "port", "p", "8080", "Port to listen on")
-	serveCmd.Flags().StringVar(&provider, "provider", "openai", "AI provider to use (openai, anthropic, gemini)")
-	serveCmd.Flags().StringVar(&model, "model", "gpt-4o-mini", "Model to use")
+	serveCmd.Flags().StringVar(&provider, "provider", "openai", "AI provider to use (openai, anthropic, groq, gemini, ollama)")
+	serveCmd.Flags().StringVar(&model, "model", "", "Model to use (default: the provider's default model)")
 }
 
-func runServe(cmd *cobra.Command, args []string) error {
-	// Check for API key
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" && provider == "openai" {
-		return fmt.Errorf("OPENAI_API_KEY environment variable is required")
-	}
+// devServer holds the active provider, which can be switched at runtime
+// through /api/config.
+type devServer struct {
+	mu       sync.RWMutex
+	provider core.Provider
+	name     string
+	model    string
+}
 
-	// Create provider with middleware
-	var p core.Provider
-	switch provider {
-	case "openai":
-		p = openai.New(
-			openai.WithAPIKey(apiKey),
-			openai.WithModel(model),
-		)
-	default:
-		return fmt.Errorf("unsupported provider: %s", provider)
+// current returns the active provider with its name and model.
+func (s *devServer) current() (core.Provider, string, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.provider, s.name, s.model
+}
+
+// configure makes the named provider and model active.
+func (s *devServer) configure(name, model string) error {
+	p, model, err := newProvider(name, model)
+	if err != nil {
+		return err
 	}
 
 	// Apply middleware
@@ -93,22 +107,38 @@ func runServe(cmd *cobra.Command, args []string) error {
 		}),
 	)(p)
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.provider, s.name, s.model = p, name, model
+	return nil
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	// Create provider with middleware
+	s := &devServer{}
+	if err := s.configure(provider, model); err != nil {
+		return err
+	}
+	_, name, activeModel := s.current()
+
 	// Set up HTTP routes
 	mux := http.NewServeMux()
 
 	// API endpoints
-	mux.HandleFunc("/api/chat", handleChatSSE(p))
-	mux.HandleFunc("/api/chat/ndjson", handleChatNDJSON(p))
-	mux.HandleFunc("/api/generate", handleGenerate(p))
-	mux.HandleFunc("/api/health", handleHealth)
+	mux.HandleFunc("/api/chat", handleChatSSE(s))
+	mux.HandleFunc("/api/chat/ndjson", handleChatNDJSON(s))
+	mux.HandleFunc("/api/generate", handleGenerate(s))
+	mux.HandleFunc("/api/config", handleConfig(s))
+	mux.HandleFunc("/api/providers", handleProviders(s))
+	mux.HandleFunc("/api/health", handleHealth(s))
 
 	// Web interface
-	mux.HandleFunc("/", handleWebInterface)
+	mux.HandleFunc("/", handleWebInterface(s))
 
 	// Start server
 	srv := &http.Server{
 		Addr:    ":" + port,
-		Handler: logRequests(mux),
+		Handler: logRequests(traceRequests(mux)),
 	}
 
 	// Graceful shutdown
@@ -125,7 +155,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	}()
 
 	log.Printf("🚀 GAI Development Server started on http://localhost:%s", port)
-	log.Printf("   Provider: %s, Model: %s", provider, model)
+	log.Printf("   Provider: %s, Model: %s", name, activeModel)
 	log.Printf("   Press Ctrl+C to stop")
 
 	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
@@ -135,7 +165,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func handleChatSSE(p core.Provider) http.HandlerFunc {
+func handleChatSSE(s *devServer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -158,29 +188,31 @@ func handleChatSSE(p core.Provider) http.HandlerFunc {
 			}, messages...)
 		}
 
-		s, err := p.StreamText(r.Context(), core.Request{
-			Messages:    messages,
-			Temperature: req.Temperature,
-			MaxTokens:   req.MaxTokens,
-			Stream:      true,
+		p, name, activeModel := s.current()
+		ts, err := p.StreamText(r.Context(), core.Request{
+			Messages:        messages,
+			Temperature:     req.Temperature,
+			MaxOutputTokens: req.MaxTokens,
+			Stream:          true,
 		})
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Stream error: %v", err), http.StatusInternalServerError)
 			return
 		}
-		defer s.Close()
+		defer ts.Close()
 
 		// Use the normalized SSE handler
 		config := stream.StreamConfig{
 			RequestID: req.RequestID,
-			Model:     model,
-			Provider:  provider,
+			Model:     activeModel,
+			Provider:  name,
+			Context:   r.Context(),
 		}
-		stream.SSENormalized(w, s, config)
+		stream.SSENormalized(w, ts, config)
 	}
 }
 
-func handleChatNDJSON(p core.Provider) http.HandlerFunc {
+func handleChatNDJSON(s *devServer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -203,29 +235,31 @@ func handleChatNDJSON(p core.Provider) http.HandlerFunc {
 			}, messages...)
 		}
 
-		s, err := p.StreamText(r.Context(), core.Request{
-			Messages:    messages,
-			Temperature: req.Temperature,
-			MaxTokens:   req.MaxTokens,
-			Stream:      true,
+		p, name, activeModel := s.current()
+		ts, err := p.StreamText(r.Context(), core.Request{
+			Messages:        messages,
+			Temperature:     req.Temperature,
+			MaxOutputTokens: req.MaxTokens,
+			Stream:          true,
 		})
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Stream error: %v", err), http.StatusInternalServerError)
 			return
 		}
-		defer s.Close()
+		defer ts.Close()
 
 		// Use the normalized NDJSON handler
 		config := stream.StreamConfig{
 			RequestID: req.RequestID,
-			Model:     model,
-			Provider:  provider,
+			Model:     activeModel,
+			Provider:  name,
+			Context:   r.Context(),
 		}
-		stream.NDJSONNormalized(w, s, config)
+		stream.NDJSONNormalized(w, ts, config)
 	}
 }
 
-func handleGenerate(p core.Provider) http.HandlerFunc {
+func handleGenerate(s *devServer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -248,10 +282,11 @@ func handleGenerate(p core.Provider) http.HandlerFunc {
 			}, messages...)
 		}
 
+		p, _, _ := s.current()
 		result, err := p.GenerateText(r.Context(), core.Request{
-			Messages:    messages,
-			Temperature: req.Temperature,
-			MaxTokens:   req.MaxTokens,
+			Messages:        messages,
+			Temperature:     req.Temperature,
+			MaxOutputTokens: req.MaxTokens,
 		})
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Generation error: %v", err), http.StatusInternalServerError)
@@ -268,21 +303,77 @@ func handleGenerate(p core.Provider) http.HandlerFunc {
 	}
 }
 
-func handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":   "healthy",
-		"provider": provider,
-		"model":    model,
-		"version":  version,
-	})
+func handleConfig(s *devServer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var req ConfigRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			if err := s.configure(req.Provider, req.Model); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		_, name, activeModel := s.current()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ConfigRequest{Provider: name, Model: activeModel})
+	}
 }
 
-func handleWebInterface(w http.ResponseWriter, r *http.Request) {
-	if r.URL.Path != "/" {
-		http.NotFound(w, r)
-		return
+func handleProviders(s *devServer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, active, _ := s.current()
+
+		var providers []ProviderStatus
+		for _, name := range providerNames() {
+			factory := providerFactories[name]
+			providers = append(providers, ProviderStatus{
+				Name:         name,
+				Configured:   factory.Configured(),
+				Active:       name == active,
+				EnvVar:       factory.EnvVar,
+				DefaultModel: factory.DefaultModel,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(providers)
+	}
+}
+
+func handleHealth(s *devServer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, name, activeModel := s.current()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "healthy",
+			"provider": name,
+			"model":    activeModel,
+			"version":  version,
+		})
+	}
+}
+
+func handleWebInterface(s *devServer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		_, name, activeModel := s.current()
+		renderWebInterface(w, name, activeModel)
 	}
+}
+
+func renderWebInterface(w http.ResponseWriter, activeProvider, activeModel string) {
 
 	tmpl := `<!DOCTYPE html>
 <html lang="en">
@@ -334,6 +425,21 @@ func handleWebInterface(w http.ResponseWriter, r *http.Request) {
             color: #555;
             font-weight: 500;
         }
+        .config-group {
+            display: flex;
+            gap: 10px;
+            margin-bottom: 20px;
+        }
+        .config-group select, .config-group input {
+            flex: 1;
+            padding: 10px;
+            border: 2px solid #e0e0e0;
+            border-radius: 8px;
+            font-size: 14px;
+        }
+        .config-group button {
+            flex: 0 0 auto;
+        }
         .input-group input, .input-group textarea {
             width: 100%;
             padding: 12px;
@@ -398,9 +504,14 @@ func handleWebInterface(w http.ResponseWriter, r *http.Request) {
     <div class="container">
         <div class="header">
             <h1>🚀 GAI Development Server</h1>
-            <p>Provider: {{.Provider}} | Model: {{.Model}}</p>
+            <p id="active">Provider: {{.Provider}} | Model: {{.Model}}</p>
         </div>
         <div class="chat-container">
+            <div class="config-group">
+                <select id="provider"></select>
+                <input id="model" type="text" value="{{.Model}}" placeholder="Model">
+                <button onclick="switchProvider()">Switch</button>
+            </div>
             <div class="input-group">
                 <label for="system">System Prompt (optional)</label>
                 <textarea id="system" rows="2" placeholder="You are a helpful assistant..."></textarea>
@@ -424,6 +535,55 @@ func handleWebInterface(w http.ResponseWriter, r *http.Request) {
         const statusEl = document.getElementById('status');
         const systemEl = document.getElementById('system');
         const messageEl = document.getElementById('message');
+        const activeEl = document.getElementById('active');
+        const providerEl = document.getElementById('provider');
+        const modelEl = document.getElementById('model');
+        let providers = [];
+
+        async function loadProviders() {
+            const response = await fetch('/api/providers');
+            providers = await response.json();
+            providerEl.innerHTML = '';
+            for (const p of providers) {
+                const option = document.createElement('option');
+                option.value = p.name;
+                option.textContent = p.configured ? p.name : p.name + ' (set ' + p.env_var + ')';
+                option.disabled = !p.configured;
+                option.selected = p.active;
+                providerEl.appendChild(option);
+            }
+        }
+
+        providerEl.addEventListener('change', () => {
+            const p = providers.find(p => p.name === providerEl.value);
+            if (p) modelEl.value = p.default_model;
+        });
+
+        async function switchProvider() {
+            try {
+                const response = await fetch('/api/config', {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({
+                        provider: providerEl.value,
+                        model: modelEl.value.trim()
+                    })
+                });
+                if (!response.ok) {
+                    showStatus('Error: ' + await response.text(), true);
+                    return;
+                }
+                const config = await response.json();
+                activeEl.textContent = 'Provider: ' + config.provider + ' | Model: ' + config.model;
+                modelEl.value = config.model;
+                showStatus('Switched to ' + config.provider + ' (' + config.model + ')');
+                await loadProviders();
+            } catch (error) {
+                showStatus('Error: ' + error.message, true);
+            }
+        }
+
+        loadProviders();
 
         function showStatus(message, isError = false) {
             statusEl.textContent = message;
@@ -583,8 +743,8 @@ func handleWebInterface(w http.ResponseWriter, r *http.Request) {
 		Provider string
 		Model    string
 	}{
-		Provider: provider,
-		Model:    model,
+		Provider: activeProvider,
+		Model:    activeModel,
 	}
 
 	t, err := template.New("index").Parse(tmpl)
@@ -605,6 +765,15 @@ func logRequests(next http.Handler) http.Handler {
 	})
 }
 
+// traceRequests continues the caller's distributed trace, so that spans
+// created while handling a request are children of the upstream span
+func traceRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := obs.ExtractTraceContext(r.Context(), r.Header)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // ChatRequest represents a chat API request
 type ChatRequest struct {
 	Message     string  `json:"message"`
@@ -614,6 +783,21 @@ type ChatRequest struct {
 	RequestID   string  `json:"request_id,omitempty"`
 }
 
+// ConfigRequest selects the active provider and model
+type ConfigRequest struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+}
+
+// ProviderStatus describes a provider in the /api/providers response
+type ProviderStatus struct {
+	Name         string `json:"name"`
+	Configured   bool   `json:"configured"`
+	Active       bool   `json:"active"`
+	EnvVar       string `json:"env_var,omitempty"`
+	DefaultModel string `json:"default_model"`
+}
+
 // GenerateResponse represents a generate API response
 type GenerateResponse struct {
 	Text  string      `json:"text"`
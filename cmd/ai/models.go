@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/recera/gai/core"
+	"github.com/spf13/cobra"
+)
+
+// modelCmd represents the model command group
+var modelCmd = &cobra.Command{
+	Use:   "model",
+	Short: "Inspect the models offered by providers",
+	Long:  `Tools for discovering the models available on configured providers.`,
+}
+
+// modelListCmd represents the model list command
+var modelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the models available on a provider",
+	Long: `Lists the models available on a provider with their context windows and
+capabilities. With --provider all, every configured provider is queried in
+parallel and the results are merged.
+
+Providers are configured through environment variables:
+  OPENAI_API_KEY    - OpenAI
+  ANTHROPIC_API_KEY - Anthropic
+  GROQ_API_KEY      - Groq
+  OLLAMA_HOST       - Ollama server (default: http://localhost:11434)
+
+Examples:
+  ai model list --provider openai
+  ai model list --provider all --filter vision --filter tool-calling`,
+	RunE: runModelList,
+}
+
+var (
+	modelListProvider string
+	modelListFilters  []string
+	modelListTimeout  time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(modelCmd)
+	modelCmd.AddCommand(modelListCmd)
+
+	modelListCmd.Flags().StringVar(&modelListProvider, "provider", "all", "Provider to query (openai, anthropic, groq, ollama, all)")
+	modelListCmd.Flags().StringSliceVar(&modelListFilters, "filter", nil, "Only show models with this capability (vision, tool-calling, json-mode)")
+	modelListCmd.Flags().DurationVar(&modelListTimeout, "timeout", 30*time.Second, "Timeout for provider requests")
+}
+
+// providerModel is a model together with the provider that offers it.
+type providerModel struct {
+	provider string
+	core.ModelInfo
+}
+
+func runModelList(cmd *cobra.Command, args []string) error {
+	names, err := modelListProviders(modelListProvider)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), modelListTimeout)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		models []providerModel
+		errs   []string
+		count  int
+	)
+	for _, name := range names {
+		factory := providerFactories[name]
+		if !factory.Configured() {
+			if modelListProvider != "all" {
+				return fmt.Errorf("provider %s is not configured (see ai model list --help)", name)
+			}
+			continue
+		}
+		lister, ok := factory.New(factory.DefaultModel).(core.ModelLister)
+		if !ok {
+			if modelListProvider != "all" {
+				return fmt.Errorf("provider %s does not support listing models", name)
+			}
+			continue
+		}
+
+		count++
+		wg.Add(1)
+		go func(name string, lister core.ModelLister) {
+			defer wg.Done()
+			infos, err := lister.Models(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+				return
+			}
+			for _, info := range infos {
+				if matchesFilters(info, modelListFilters) {
+					models = append(models, providerModel{provider: name, ModelInfo: info})
+				}
+			}
+		}(name, lister)
+	}
+	wg.Wait()
+
+	sort.Strings(errs)
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", e)
+	}
+	if count > 0 && len(errs) == count {
+		return fmt.Errorf("no provider could be queried")
+	}
+
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].provider != models[j].provider {
+			return models[i].provider < models[j].provider
+		}
+		return models[i].ID < models[j].ID
+	})
+	printModels(models)
+	return nil
+}
+
+// modelListProviders resolves the --provider flag to provider names.
+func modelListProviders(provider string) ([]string, error) {
+	if provider == "all" {
+		return providerNames(), nil
+	}
+	if _, ok := providerFactories[provider]; !ok {
+		return nil, fmt.Errorf("unsupported provider: %s", provider)
+	}
+	return []string{provider}, nil
+}
+
+// matchesFilters reports whether the model has every requested capability.
+func matchesFilters(info core.ModelInfo, filters []string) bool {
+	for _, f := range filters {
+		if !info.Supports(core.ModelCapability(strings.ToLower(f))) {
+			return false
+		}
+	}
+	return true
+}
+
+// printModels writes models as a table to stdout.
+func printModels(models []providerModel) {
+	if len(models) == 0 {
+		fmt.Println("No models found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tMODEL\tCONTEXT\tCAPABILITIES")
+	for _, m := range models {
+		context := "-"
+		if m.ContextWindow > 0 {
+			context = fmt.Sprintf("%d", m.ContextWindow)
+		}
+		capabilities := "-"
+		if len(m.Capabilities) > 0 {
+			names := make([]string, len(m.Capabilities))
+			for i, c := range m.Capabilities {
+				names[i] = string(c)
+			}
+			capabilities = strings.Join(names, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.provider, m.ID, context, capabilities)
+	}
+	w.Flush()
+}
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"context"
 	"crypto/sha256"
 	"embed"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/recera/gai/prompts"
 	"github.com/spf13/cobra"
@@ -34,6 +41,9 @@ This command checks:
   - Content changes require version bumps
   - No duplicate versions for the same template name
 
+With --diff, the rendered output of each template's newest version is
+diffed against the version before it, for reviewing prompt changes in CI.
+
 Exit codes:
   0 - All templates verified successfully
   1 - Verification failed`,
@@ -55,19 +65,58 @@ var listCmd = &cobra.Command{
 	RunE:  runList,
 }
 
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render a prompt template with JSON data",
+	Long: `Renders a prompt template and prints the result, for testing template
+changes without writing Go code.
+
+Data is a JSON object, given inline or read from a file with @path. With
+--diff the output is compared to the template's previous version, and with
+--watch the template is re-rendered whenever a template file changes.
+
+Examples:
+  ai prompts render --name chat_assistant --data '{"Style":"professional"}'
+  ai prompts render --name chat_assistant --version 1.0.0 --data @data.json --diff
+  ai prompts render --name chat_assistant --embed-dir ./prompts --watch`,
+	RunE: runRender,
+}
+
 var (
 	promptsDir string
 	strict     bool
+	verifyDiff bool
+
+	renderName     string
+	renderVersion  string
+	renderData     string
+	renderEmbedDir string
+	renderDiff     bool
+	renderWatch    bool
 )
 
+// renderPollInterval is how often --watch checks template files for changes.
+const renderPollInterval = 500 * time.Millisecond
+
 func init() {
 	rootCmd.AddCommand(promptsCmd)
 	promptsCmd.AddCommand(verifyCmd)
 	promptsCmd.AddCommand(bumpCmd)
 	promptsCmd.AddCommand(listCmd)
+	promptsCmd.AddCommand(renderCmd)
 
 	promptsCmd.PersistentFlags().StringVar(&promptsDir, "dir", "", "Prompts directory (default: search for embedded templates)")
 	verifyCmd.Flags().BoolVar(&strict, "strict", false, "Strict mode: fail on any warning")
+	verifyCmd.Flags().BoolVar(&verifyDiff, "diff", false, "Show rendered diffs between each template's two newest versions")
+
+	renderCmd.Flags().StringVar(&renderName, "name", "", "Template name (required)")
+	renderCmd.Flags().StringVar(&renderVersion, "version", "", "Template version (default: latest)")
+	renderCmd.Flags().StringVar(&renderData, "data", "", "Template data as a JSON object, or @file to read it from a file")
+	renderCmd.Flags().StringVar(&renderEmbedDir, "embed-dir", "", "Directory of templates (default: --dir or search for a prompts directory)")
+	renderCmd.Flags().BoolVar(&renderDiff, "diff", false, "Show the rendered diff against the previous version")
+	renderCmd.Flags().BoolVar(&renderWatch, "watch", false, "Re-render whenever a template file changes")
+	renderCmd.MarkFlagRequired("name")
 }
 
 func runVerify(cmd *cobra.Command, args []string) error {
@@ -203,6 +252,13 @@ func runVerify(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if verifyDiff {
+		if err := printTemplateDiffs(promptsDir); err != nil {
+			errors = append(errors, fmt.Sprintf("❌ Cannot diff templates: %v", err))
+			fmt.Println("\n" + errors[len(errors)-1])
+		}
+	}
+
 	if len(errors) > 0 || (strict && len(warnings) > 0) {
 		return fmt.Errorf("verification failed")
 	}
@@ -211,6 +267,41 @@ func runVerify(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printTemplateDiffs prints a rendered diff between the two newest versions
+// of every template in dir that has more than one version.
+func printTemplateDiffs(dir string) error {
+	reg, err := prompts.NewRegistry(embed.FS{}, prompts.WithOverrideDir(dir))
+	if err != nil {
+		return err
+	}
+
+	templates := reg.List()
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("\nChanges:")
+	for _, name := range names {
+		versions := templates[name]
+		if len(versions) < 2 {
+			continue
+		}
+		previous := versions[len(versions)-2]
+		diff, err := reg.Diff(name, previous, prompts.LatestVersion)
+		if err != nil {
+			return err
+		}
+		if diff == "" {
+			fmt.Printf("  %s: no rendered changes since %s\n", name, previous)
+			continue
+		}
+		fmt.Println(diff)
+	}
+	return nil
+}
+
 func runBump(cmd *cobra.Command, args []string) error {
 	templateName := args[0]
 	bumpType := args[1]
@@ -359,6 +450,160 @@ func runList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runRender(cmd *cobra.Command, args []string) error {
+	dir := renderEmbedDir
+	if dir == "" {
+		dir = promptsDir
+	}
+	if dir == "" {
+		dir = findPromptsDir()
+	}
+	if dir == "" {
+		return fmt.Errorf("prompts directory not found; use --embed-dir")
+	}
+
+	data, err := loadRenderData(renderData)
+	if err != nil {
+		return err
+	}
+
+	reg, err := prompts.NewRegistry(embed.FS{}, prompts.WithOverrideDir(dir))
+	if err != nil {
+		return err
+	}
+
+	if !renderWatch {
+		return renderTemplate(cmd.Context(), reg, data)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Stop watching cleanly on Ctrl+C, like the dev server
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Poll modification times rather than subscribing to file system events,
+	// which keeps the CLI free of platform-specific watchers
+	fmt.Fprintf(os.Stderr, "Watching %s for changes (Ctrl+C to stop)\n", dir)
+	ticker := time.NewTicker(renderPollInterval)
+	defer ticker.Stop()
+
+	var last map[string]time.Time
+	for {
+		files, err := templateModTimes(dir)
+		if err != nil {
+			return err
+		}
+		// Any added, removed or touched file counts as a change, so that
+		// deletions and restored older copies are picked up too
+		if last == nil || !maps.EqualFunc(files, last, time.Time.Equal) {
+			if last != nil {
+				if err := reg.Reload(); err != nil {
+					return err
+				}
+				fmt.Println("\n--- " + time.Now().Format("15:04:05") + " ---")
+			}
+			last = files
+			if err := renderTemplate(ctx, reg, data); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// loadRenderData parses the --data flag, reading it from a file when it
+// starts with "@".
+func loadRenderData(value string) (map[string]any, error) {
+	if value == "" {
+		return map[string]any{}, nil
+	}
+
+	raw := []byte(value)
+	if path, ok := strings.CutPrefix(value, "@"); ok {
+		var err error
+		if raw, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read data file: %w", err)
+		}
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid data: expected a JSON object: %w", err)
+	}
+	return data, nil
+}
+
+// renderTemplate prints the rendered template, or its diff against the
+// previous version with --diff.
+func renderTemplate(ctx context.Context, reg *prompts.Registry, data map[string]any) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	output, id, err := reg.Render(ctx, renderName, renderVersion, data)
+	if err != nil {
+		return err
+	}
+	if !renderDiff {
+		fmt.Print(output)
+		if !strings.HasSuffix(output, "\n") {
+			fmt.Println()
+		}
+		return nil
+	}
+
+	versions := reg.List()[renderName]
+	previous := ""
+	for i, version := range versions {
+		if version == id.Version && i > 0 {
+			previous = versions[i-1]
+		}
+	}
+	if previous == "" {
+		return fmt.Errorf("template %s@%s has no previous version to diff against", renderName, id.Version)
+	}
+
+	diff, err := reg.DiffWithData(renderName, previous, id.Version, data)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		fmt.Printf("%s: no rendered changes since %s\n", renderName, previous)
+		return nil
+	}
+	fmt.Println(diff)
+	return nil
+}
+
+// templateModTimes returns the modification time of each template file in
+// dir, keyed by file name.
+func templateModTimes(dir string) (map[string]time.Time, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	files := make(map[string]time.Time)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tmpl") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files[entry.Name()] = info.ModTime()
+	}
+	return files, nil
+}
+
 func findPromptsDir() string {
 	// Look for common prompt directory locations
 	candidates := []string{
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/providers/anthropic"
+	"github.com/recera/gai/providers/gemini"
+	"github.com/recera/gai/providers/groq"
+	"github.com/recera/gai/providers/ollama"
+	"github.com/recera/gai/providers/openai"
+)
+
+// ProviderFactory creates a provider configured from the environment.
+type ProviderFactory struct {
+	// EnvVar is the environment variable holding the API key. Empty means
+	// the provider needs no key.
+	EnvVar string
+	// DefaultModel is used when no model is given
+	DefaultModel string
+	// New creates the provider for a model
+	New func(model string) core.Provider
+}
+
+// Configured reports whether the environment holds what the provider needs.
+func (f ProviderFactory) Configured() bool {
+	return f.EnvVar == "" || os.Getenv(f.EnvVar) != ""
+}
+
+// providerFactories maps provider names to their factories.
+var providerFactories = map[string]ProviderFactory{
+	"openai": {
+		EnvVar:       "OPENAI_API_KEY",
+		DefaultModel: openai.GPT4oMini,
+		New: func(model string) core.Provider {
+			return openai.New(openai.WithAPIKey(os.Getenv("OPENAI_API_KEY")), openai.WithModel(model))
+		},
+	},
+	"anthropic": {
+		EnvVar:       "ANTHROPIC_API_KEY",
+		DefaultModel: anthropic.ClaudeSonnet4,
+		New: func(model string) core.Provider {
+			return anthropic.New(anthropic.WithAPIKey(os.Getenv("ANTHROPIC_API_KEY")), anthropic.WithModel(model))
+		},
+	},
+	"groq": {
+		EnvVar:       "GROQ_API_KEY",
+		DefaultModel: groq.Llama318BInstant,
+		New: func(model string) core.Provider {
+			return groq.New(groq.WithAPIKey(os.Getenv("GROQ_API_KEY")), groq.WithModel(model))
+		},
+	},
+	"gemini": {
+		EnvVar:       "GOOGLE_API_KEY",
+		DefaultModel: "gemini-1.5-flash",
+		New: func(model string) core.Provider {
+			return gemini.New(gemini.WithAPIKey(os.Getenv("GOOGLE_API_KEY")), gemini.WithModel(model))
+		},
+	},
+	"ollama": {
+		DefaultModel: "llama3.2",
+		New: func(model string) core.Provider {
+			opts := []ollama.Option{ollama.WithModel(model)}
+			if host := os.Getenv("OLLAMA_HOST"); host != "" {
+				opts = append(opts, ollama.WithBaseURL(host))
+			}
+			return ollama.New(opts...)
+		},
+	},
+}
+
+// providerNames returns the registered provider names in sorted order.
+func providerNames() []string {
+	names := make([]string, 0, len(providerFactories))
+	for name := range providerFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newProvider creates the named provider, using its default model if model
+// is empty. It fails if the provider is unknown or not configured.
+func newProvider(name, model string) (core.Provider, string, error) {
+	factory, ok := providerFactories[name]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported provider: %s", name)
+	}
+	if !factory.Configured() {
+		return nil, "", fmt.Errorf("%s environment variable is required for provider %s", factory.EnvVar, name)
+	}
+	if model == "" {
+		model = factory.DefaultModel
+	}
+	return factory.New(model), model, nil
+}
@@ -0,0 +1,87 @@
+// Package core provides stream accumulation.
+// This file implements StepAccumulator, which assembles the outcome of a
+// stream from its events for code that observes or collects streams.
+
+package core
+
+import "slices"
+
+// StepAccumulator assembles the text, steps and usage of a stream from its
+// events as they arrive. Feed it every event, for example from Tee, and read
+// the outcome with Result. The zero value is ready to use. It is not safe for
+// concurrent use.
+type StepAccumulator struct {
+	result    TextResult
+	step      Step
+	stepUsage Usage
+	finished  bool
+	failed    bool
+	err       error
+}
+
+// Add folds event into the result.
+func (a *StepAccumulator) Add(event Event) {
+	switch event.Type {
+	case EventTextDelta:
+		a.result.Text += event.TextDelta
+		a.step.Text += event.TextDelta
+	case EventToolCall:
+		a.step.ToolCalls = append(a.step.ToolCalls, ToolCall{ID: event.ToolID, Name: event.ToolName, Input: event.ToolInput})
+	case EventToolResult:
+		execution := ToolExecution{ID: event.ToolID, Name: event.ToolName, Result: event.ToolResult}
+		if event.Err != nil {
+			execution.Error = event.Err.Error()
+		}
+		a.step.ToolResults = append(a.step.ToolResults, execution)
+	case EventFinishStep:
+		if event.Usage != nil {
+			a.step.Usage = *event.Usage
+			a.stepUsage.Add(*event.Usage)
+		}
+		a.step.StepNumber = event.StepNumber
+		if a.step.StepNumber == 0 {
+			a.step.StepNumber = len(a.result.Steps) + 1
+		}
+		a.step.Timestamp = event.Timestamp
+		a.result.Steps = append(a.result.Steps, a.step)
+		a.step = Step{}
+	case EventFinish:
+		a.finished = true
+		if event.Usage != nil {
+			a.result.Usage = *event.Usage
+		} else {
+			// Streams that only report usage per step are approximated
+			// by the sum of their steps
+			a.result.Usage = a.stepUsage
+		}
+	case EventError:
+		a.failed = true
+		a.err = event.Err
+	}
+}
+
+// Result returns the result assembled so far, including a step that is
+// still in progress when it called tools or follows earlier steps. Until
+// EventFinish arrives, Usage is the sum of the finished steps.
+func (a *StepAccumulator) Result() *TextResult {
+	result := a.result
+	if !a.finished {
+		result.Usage = a.stepUsage
+	}
+	if len(a.step.ToolCalls) > 0 || (len(result.Steps) > 0 && a.step.Text != "") {
+		step := a.step
+		step.StepNumber = len(result.Steps) + 1
+		result.Steps = append(slices.Clip(result.Steps), step)
+	}
+	return &result
+}
+
+// Finished reports whether the stream's EventFinish has been seen.
+func (a *StepAccumulator) Finished() bool {
+	return a.finished
+}
+
+// Failed reports whether an EventError has been seen, and returns its error.
+func (a *StepAccumulator) Failed() (bool, error) {
+	return a.failed, a.err
+}
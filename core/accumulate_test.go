@@ -0,0 +1,42 @@
+package core
+
+import "testing"
+
+func TestStepAccumulator(t *testing.T) {
+	var acc StepAccumulator
+	for _, e := range []Event{
+		{Type: EventTextDelta, TextDelta: "Checking. "},
+		{Type: EventToolCall, ToolID: "1", ToolName: "lookup"},
+		{Type: EventToolResult, ToolID: "1", ToolName: "lookup", ToolResult: "42"},
+		{Type: EventFinishStep, Usage: &Usage{OutputTokens: 5, CacheReadTokens: 3}},
+		{Type: EventTextDelta, TextDelta: "It is 42."},
+	} {
+		acc.Add(e)
+	}
+
+	result := acc.Result()
+	if result.Text != "Checking. It is 42." {
+		t.Errorf("Text = %q", result.Text)
+	}
+	if len(result.Steps) != 2 || result.Steps[0].ToolCalls[0].Name != "lookup" || result.Steps[1].Text != "It is 42." {
+		t.Fatalf("unexpected steps: %+v", result.Steps)
+	}
+	if result.Steps[0].Usage.OutputTokens != 5 || result.Steps[0].StepNumber != 1 {
+		t.Errorf("unexpected first step: %+v", result.Steps[0])
+	}
+	if acc.Finished() {
+		t.Error("finished before EventFinish")
+	}
+
+	// Without usage on the finish event, the step usage is summed
+	acc.Add(Event{Type: EventFinish})
+	if result := acc.Result(); !acc.Finished() || result.Usage.OutputTokens != 5 || result.Usage.CacheReadTokens != 3 {
+		t.Errorf("finished = %v, usage = %+v", acc.Finished(), result.Usage)
+	}
+	if len(acc.result.Steps) != 1 {
+		t.Errorf("Result changed the accumulated steps: %+v", acc.result.Steps)
+	}
+	if failed, _ := acc.Failed(); failed {
+		t.Error("failed without EventError")
+	}
+}
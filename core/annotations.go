@@ -0,0 +1,83 @@
+// Package core provides citation rendering.
+// This file implements AnnotatedText, which marks the sources cited in a
+// result inline.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AnnotatedText returns result.Text with a citation marker such as "[1]"
+// inserted after each annotated span. Sources are numbered by URL in order of
+// first appearance, so repeated citations of a source share a number.
+// Annotations whose spans lie outside the text are ignored.
+//
+// Example:
+//
+//	fmt.Println(core.AnnotatedText(result))
+//	for i, url := range core.AnnotationSources(result) {
+//		fmt.Printf("[%d] %s\n", i+1, url)
+//	}
+func AnnotatedText(result *TextResult) string {
+	if result == nil {
+		return ""
+	}
+
+	numbers := make(map[string]int)
+	for _, url := range AnnotationSources(result) {
+		numbers[url] = len(numbers) + 1
+	}
+
+	annotations := textAnnotations(result)
+
+	var b strings.Builder
+	pos := 0
+	for i, a := range annotations {
+		// Cite a source once per position
+		if i > 0 && annotations[i-1].EndIndex == a.EndIndex && annotations[i-1].URL == a.URL {
+			continue
+		}
+		b.WriteString(result.Text[pos:a.EndIndex])
+		pos = a.EndIndex
+		fmt.Fprintf(&b, "[%d]", numbers[a.URL])
+	}
+	b.WriteString(result.Text[pos:])
+	return b.String()
+}
+
+// AnnotationSources returns the distinct URLs cited in result, in order of
+// first appearance in the text, so that source i is marked [i+1] by
+// AnnotatedText.
+func AnnotationSources(result *TextResult) []string {
+	if result == nil {
+		return nil
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	for _, a := range textAnnotations(result) {
+		if !seen[a.URL] {
+			seen[a.URL] = true
+			urls = append(urls, a.URL)
+		}
+	}
+	return urls
+}
+
+// textAnnotations returns the annotations whose spans lie within the text,
+// ordered by where they end.
+func textAnnotations(result *TextResult) []Annotation {
+	annotations := make([]Annotation, 0, len(result.Annotations))
+	for _, a := range result.Annotations {
+		if a.StartIndex >= 0 && a.StartIndex <= a.EndIndex && a.EndIndex <= len(result.Text) {
+			annotations = append(annotations, a)
+		}
+	}
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotations[i].EndIndex < annotations[j].EndIndex
+	})
+	return annotations
+}
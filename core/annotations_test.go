@@ -0,0 +1,33 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnnotatedText(t *testing.T) {
+	result := &TextResult{
+		Text: "Go 1.23 added iterators. It shipped in August. Iterators use range.",
+		Annotations: []Annotation{
+			{StartIndex: 0, EndIndex: 24, URL: "https://go.dev/blog/go1.23"},
+			{StartIndex: 0, EndIndex: 24, URL: "https://go.dev/blog/range-functions"},
+			{StartIndex: 25, EndIndex: 46, URL: "https://go.dev/blog/go1.23"},
+			{StartIndex: 47, EndIndex: 67, URL: "https://go.dev/blog/range-functions"},
+			{StartIndex: 0, EndIndex: 500, URL: "https://example.com/out-of-range"},
+		},
+	}
+
+	want := "Go 1.23 added iterators.[1][2] It shipped in August.[1] Iterators use range.[2]"
+	if got := AnnotatedText(result); got != want {
+		t.Errorf("AnnotatedText() = %q, want %q", got, want)
+	}
+
+	wantSources := []string{"https://go.dev/blog/go1.23", "https://go.dev/blog/range-functions"}
+	if got := AnnotationSources(result); !reflect.DeepEqual(got, wantSources) {
+		t.Errorf("AnnotationSources() = %v, want %v", got, wantSources)
+	}
+
+	if got := AnnotatedText(&TextResult{Text: "plain"}); got != "plain" {
+		t.Errorf("AnnotatedText() without annotations = %q", got)
+	}
+}
@@ -0,0 +1,82 @@
+// Package core provides capability-based model selection.
+// This file implements Request.Model "auto": providers pick a model from what
+// the request needs, such as tool calling, vision or a long context.
+
+package core
+
+import "strings"
+
+// AutoModel is the Request.Model value that asks the provider to choose the
+// model (see SelectModel).
+const AutoModel = "auto"
+
+// LongContextThreshold is the estimated input size in tokens above which a
+// request needs a long-context model.
+const LongContextThreshold = 128000
+
+// ModelNeeds describes what a request requires of the model that serves it.
+type ModelNeeds struct {
+	// Tools is true when the request offers tools to the model
+	Tools bool
+	// Vision is true when a message contains an image
+	Vision bool
+	// LongContext is true when EstimatedTokens exceeds LongContextThreshold
+	LongContext bool
+	// EstimatedTokens approximates the input size (see ApproxTokenCounter)
+	EstimatedTokens int
+}
+
+// ModelSelector chooses the model for a request that asked for automatic
+// selection. Returning "" uses the provider's default model.
+type ModelSelector func(req Request, needs ModelNeeds) string
+
+// RequestModelNeeds reports what req requires of a model.
+func RequestModelNeeds(req Request) ModelNeeds {
+	needs := ModelNeeds{
+		Tools:           len(req.Tools) > 0,
+		EstimatedTokens: ApproxTokenCounter.CountTokens(req.Messages),
+	}
+	needs.LongContext = needs.EstimatedTokens > LongContextThreshold
+	for _, msg := range req.Messages {
+		for _, part := range msg.Parts {
+			switch p := part.(type) {
+			case ImageURL:
+				needs.Vision = true
+			case File:
+				if strings.HasPrefix(p.Source.MIME, "image/") {
+					needs.Vision = true
+				}
+			}
+		}
+	}
+	return needs
+}
+
+// SelectModel returns req with Model chosen by selector when req.Model is
+// AutoModel or req.AutoSelectModel is set, and AutoSelectModel cleared so the
+// selection is made only once. Other requests are returned unchanged.
+// Providers call this after resolving model aliases.
+//
+// Example:
+//
+//	req := core.Request{Model: core.AutoModel, Messages: msgs, Tools: handles}
+//	result, err := provider.GenerateText(ctx, req) // OpenAI picks gpt-4o
+func SelectModel(req Request, selector ModelSelector) Request {
+	if req.Model != AutoModel && !req.AutoSelectModel {
+		return req
+	}
+	req.Model = selector(req, RequestModelNeeds(req))
+	req.AutoSelectModel = false
+	return req
+}
+
+// ServedModel returns the model that served req: result.ModelUsed when the
+// provider reported it, otherwise req.Model. Middleware that attributes cost
+// or usage to a model uses it so that automatically selected, aliased and
+// fallback requests are charged to the model that actually ran.
+func ServedModel(req Request, result *TextResult) string {
+	if result != nil && result.ModelUsed != "" {
+		return result.ModelUsed
+	}
+	return req.Model
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequestModelNeeds(t *testing.T) {
+	text := Message{Role: User, Parts: []Part{Text{Text: "Hi"}}}
+	image := Message{Role: User, Parts: []Part{File{Source: BlobRef{Kind: BlobBytes, MIME: "image/png"}}}}
+	long := Message{Role: User, Parts: []Part{Text{Text: strings.Repeat("word ", LongContextThreshold)}}}
+
+	if needs := RequestModelNeeds(Request{Messages: []Message{text}}); needs.Tools || needs.Vision || needs.LongContext {
+		t.Errorf("plain text request needs = %+v", needs)
+	}
+	if needs := RequestModelNeeds(Request{Messages: []Message{text, image}}); !needs.Vision {
+		t.Errorf("expected vision for an image file, got %+v", needs)
+	}
+	if needs := RequestModelNeeds(Request{Messages: []Message{long}}); !needs.LongContext || needs.EstimatedTokens <= LongContextThreshold {
+		t.Errorf("expected long context, got %+v", needs)
+	}
+}
+
+func TestSelectModel(t *testing.T) {
+	selector := func(req Request, needs ModelNeeds) string { return "chosen" }
+
+	if req := SelectModel(Request{Model: "gpt-4o"}, selector); req.Model != "gpt-4o" {
+		t.Errorf("explicit model was replaced with %q", req.Model)
+	}
+	if req := SelectModel(Request{Model: AutoModel}, selector); req.Model != "chosen" {
+		t.Errorf("auto model = %q, want chosen", req.Model)
+	}
+	req := SelectModel(Request{Model: "gpt-4o", AutoSelectModel: true}, selector)
+	if req.Model != "chosen" || req.AutoSelectModel {
+		t.Errorf("AutoSelectModel request = %+v", req)
+	}
+}
+
+func TestServedModel(t *testing.T) {
+	req := Request{Model: AutoModel}
+	if got := ServedModel(req, &TextResult{ModelUsed: "gpt-4o-2024-08-06"}); got != "gpt-4o-2024-08-06" {
+		t.Errorf("ServedModel = %q, want the reported model", got)
+	}
+	if got := ServedModel(Request{Model: "gpt-4o"}, &TextResult{}); got != "gpt-4o" {
+		t.Errorf("ServedModel without ModelUsed = %q, want gpt-4o", got)
+	}
+	if got := ServedModel(Request{Model: "gpt-4o"}, nil); got != "gpt-4o" {
+		t.Errorf("ServedModel(nil) = %q, want gpt-4o", got)
+	}
+}
@@ -0,0 +1,101 @@
+// Package core provides batched tool execution.
+// This file defines the interface for tools that process several calls in a
+// single execution and the helper the agentic loops use to coalesce calls.
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// BatchResult is the outcome of one call executed as part of a batch.
+type BatchResult struct {
+	Result any
+	Err    error
+}
+
+// BatchToolHandle is implemented by tools that can process several inputs in
+// one execution, such as embedding or lookup-by-IDs APIs. When the model calls
+// such a tool more than once in a step, the agentic loop passes all of the
+// inputs to ExecBatch instead of calling Exec once per input.
+type BatchToolHandle interface {
+	ToolHandle
+	// ExecBatch executes the tool once for all inputs and returns one result
+	// per input, in the same order
+	ExecBatch(ctx context.Context, inputs []json.RawMessage, meta interface{}) []BatchResult
+}
+
+// ExecuteBatchedCalls runs the calls to batch-capable tools, coalescing calls
+// to the same tool into a single ExecBatch. Only tools called at least twice
+// are batched. The returned map holds the outcome for each handled call,
+// keyed by its index in calls; the caller executes the remaining calls
+// individually. meta is passed to every ExecBatch.
+func ExecuteBatchedCalls(ctx context.Context, tools []ToolHandle, calls []ToolCall, meta interface{}) map[int]BatchResult {
+	groups := make(map[string][]int)
+	var order []string
+	for i, call := range calls {
+		if _, seen := groups[call.Name]; !seen {
+			order = append(order, call.Name)
+		}
+		groups[call.Name] = append(groups[call.Name], i)
+	}
+
+	results := make(map[int]BatchResult)
+	for _, name := range order {
+		indexes := groups[name]
+		if len(indexes) < 2 {
+			continue
+		}
+		tool := findBatchTool(tools, name)
+		if tool == nil {
+			continue
+		}
+
+		inputs := make([]json.RawMessage, len(indexes))
+		for j, idx := range indexes {
+			inputs[j] = calls[idx].Input
+		}
+		batch := execBatchSafely(ctx, tool, inputs, meta)
+		for j, idx := range indexes {
+			results[idx] = batch[j]
+		}
+	}
+	return results
+}
+
+// findBatchTool returns the named tool if it supports batch execution.
+func findBatchTool(tools []ToolHandle, name string) BatchToolHandle {
+	for _, tool := range tools {
+		if tool.Name() == name {
+			batch, _ := tool.(BatchToolHandle)
+			return batch
+		}
+	}
+	return nil
+}
+
+// execBatchSafely calls ExecBatch, converting a panic or a result count that
+// does not match the inputs into an error for every call.
+func execBatchSafely(ctx context.Context, tool BatchToolHandle, inputs []json.RawMessage, meta interface{}) (results []BatchResult) {
+	failAll := func(err error) []BatchResult {
+		failed := make([]BatchResult, len(inputs))
+		for i := range failed {
+			failed[i].Err = err
+		}
+		return failed
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			results = failAll(fmt.Errorf("tool %s panicked: %v", tool.Name(), r))
+		}
+	}()
+
+	results = tool.ExecBatch(ctx, inputs, meta)
+	if len(results) != len(inputs) {
+		return failAll(fmt.Errorf("tool %s returned %d results for %d inputs", tool.Name(), len(results), len(inputs)))
+	}
+	return results
+}
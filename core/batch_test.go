@@ -0,0 +1,78 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// batchTool is a BatchToolHandle that records how it was called.
+type batchTool struct {
+	name    string
+	batches [][]json.RawMessage
+	results func(inputs []json.RawMessage) []BatchResult
+}
+
+func (b *batchTool) Name() string                   { return b.name }
+func (b *batchTool) Description() string            { return "batch test tool" }
+func (b *batchTool) InSchemaJSON() json.RawMessage  { return json.RawMessage(`{"type":"object"}`) }
+func (b *batchTool) OutSchemaJSON() json.RawMessage { return json.RawMessage(`{"type":"object"}`) }
+
+func (b *batchTool) Exec(ctx context.Context, raw json.RawMessage, meta interface{}) (any, error) {
+	return string(raw), nil
+}
+
+func (b *batchTool) ExecBatch(ctx context.Context, inputs []json.RawMessage, meta interface{}) []BatchResult {
+	b.batches = append(b.batches, inputs)
+	return b.results(inputs)
+}
+
+func echoResults(inputs []json.RawMessage) []BatchResult {
+	results := make([]BatchResult, len(inputs))
+	for i, input := range inputs {
+		results[i].Result = string(input)
+	}
+	return results
+}
+
+func TestExecuteBatchedCalls(t *testing.T) {
+	tool := &batchTool{name: "lookup", results: echoResults}
+	calls := []ToolCall{
+		{ID: "a", Name: "lookup", Input: json.RawMessage(`1`)},
+		{ID: "b", Name: "other", Input: json.RawMessage(`2`)},
+		{ID: "c", Name: "lookup", Input: json.RawMessage(`3`)},
+	}
+
+	results := ExecuteBatchedCalls(context.Background(), []ToolHandle{tool}, calls, nil)
+
+	if len(tool.batches) != 1 || len(tool.batches[0]) != 2 {
+		t.Fatalf("Expected one batch of 2 inputs, got %v", tool.batches)
+	}
+	if len(results) != 2 || results[0].Result != "1" || results[2].Result != "3" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
+
+func TestExecuteBatchedCallsSingleCall(t *testing.T) {
+	tool := &batchTool{name: "lookup", results: echoResults}
+	calls := []ToolCall{{ID: "a", Name: "lookup", Input: json.RawMessage(`1`)}}
+
+	if results := ExecuteBatchedCalls(context.Background(), []ToolHandle{tool}, calls, nil); len(results) != 0 {
+		t.Errorf("Expected a single call not to be batched, got %+v", results)
+	}
+}
+
+func TestExecuteBatchedCallsMismatch(t *testing.T) {
+	tool := &batchTool{name: "lookup", results: func([]json.RawMessage) []BatchResult { return nil }}
+	calls := []ToolCall{
+		{ID: "a", Name: "lookup", Input: json.RawMessage(`1`)},
+		{ID: "b", Name: "lookup", Input: json.RawMessage(`2`)},
+	}
+
+	results := ExecuteBatchedCalls(context.Background(), []ToolHandle{tool}, calls, nil)
+	for idx := range calls {
+		if results[idx].Err == nil {
+			t.Errorf("Expected error for call %d when result count does not match", idx)
+		}
+	}
+}
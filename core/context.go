@@ -0,0 +1,55 @@
+// Package core provides context propagation for request metadata.
+// This file implements helpers that carry Request.Metadata (tenant, user and
+// session identifiers) through the context to middleware, tools and telemetry.
+
+package core
+
+import "context"
+
+// Well-known metadata keys. The tenant, user and session keys are read by
+// the observability package. Use them with SetMetadata and GetMetadata, or
+// as Request.Metadata keys directly.
+const (
+	// MetadataTenantID identifies the tenant making the request
+	MetadataTenantID = "tenant_id"
+	// MetadataUserID identifies the end user making the request
+	MetadataUserID = "user_id"
+	// MetadataSessionID identifies the conversation or session
+	MetadataSessionID = "session_id"
+	// MetadataRequestSource identifies where the request came from, such as
+	// "web", "api" or "batch"
+	MetadataRequestSource = "request_source"
+	// MetadataPriority is the request's scheduling priority
+	MetadataPriority = "priority"
+)
+
+// metadataKey is the context key for request metadata
+type metadataKey struct{}
+
+// WithMetadata returns a context carrying the given request metadata, merged
+// with any metadata already in the context (new values win on conflicts).
+// Providers call this with Request.Metadata at the start of every request so
+// that middleware, tools and observability can read it.
+func WithMetadata(ctx context.Context, meta map[string]any) context.Context {
+	if len(meta) == 0 {
+		return ctx
+	}
+
+	existing := MetadataFromContext(ctx)
+	merged := make(map[string]any, len(existing)+len(meta))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range meta {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, metadataKey{}, merged)
+}
+
+// MetadataFromContext returns the request metadata carried by the context, or nil.
+// The returned map must not be modified.
+func MetadataFromContext(ctx context.Context) map[string]any {
+	meta, _ := ctx.Value(metadataKey{}).(map[string]any)
+	return meta
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithMetadata(t *testing.T) {
+	ctx := context.Background()
+	if meta := MetadataFromContext(ctx); meta != nil {
+		t.Errorf("Expected no metadata, got %v", meta)
+	}
+
+	// Empty metadata leaves the context unchanged
+	if WithMetadata(ctx, nil) != ctx {
+		t.Error("Expected same context for empty metadata")
+	}
+
+	ctx = WithMetadata(ctx, map[string]any{MetadataTenantID: "acme", MetadataUserID: "u1"})
+	ctx = WithMetadata(ctx, map[string]any{MetadataUserID: "u2", MetadataSessionID: "s1"})
+
+	meta := MetadataFromContext(ctx)
+	if meta[MetadataTenantID] != "acme" {
+		t.Errorf("Expected tenant acme, got %v", meta[MetadataTenantID])
+	}
+	if meta[MetadataUserID] != "u2" {
+		t.Errorf("Expected newer user u2 to win, got %v", meta[MetadataUserID])
+	}
+	if meta[MetadataSessionID] != "s1" {
+		t.Errorf("Expected session s1, got %v", meta[MetadataSessionID])
+	}
+}
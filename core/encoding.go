@@ -0,0 +1,127 @@
+// Package core provides JSON encoding for conversation messages.
+// This file implements round-trippable marshaling of the sealed Part
+// interface so that messages can be persisted and reloaded.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes the message with each part tagged by its type, e.g.
+// {"role":"user","parts":[{"type":"text","text":"Hello"}]}.
+func (m Message) MarshalJSON() ([]byte, error) {
+	parts := make([]json.RawMessage, len(m.Parts))
+	for i, part := range m.Parts {
+		data, err := marshalPart(part)
+		if err != nil {
+			return nil, fmt.Errorf("encoding part %d: %w", i, err)
+		}
+		parts[i] = data
+	}
+
+	return json.Marshal(struct {
+		Role       Role              `json:"role"`
+		Parts      []json.RawMessage `json:"parts"`
+		Name       string            `json:"name,omitempty"`
+		ToolCalls  []ToolCall        `json:"tool_calls,omitempty"`
+		ToolCallID string            `json:"tool_call_id,omitempty"`
+		IsError    bool              `json:"is_error,omitempty"`
+	}{
+		Role:       m.Role,
+		Parts:      parts,
+		Name:       m.Name,
+		ToolCalls:  m.ToolCalls,
+		ToolCallID: m.ToolCallID,
+		IsError:    m.IsError,
+	})
+}
+
+// UnmarshalJSON decodes a message encoded by MarshalJSON.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role       Role              `json:"role"`
+		Parts      []json.RawMessage `json:"parts"`
+		Name       string            `json:"name,omitempty"`
+		ToolCalls  []ToolCall        `json:"tool_calls,omitempty"`
+		ToolCallID string            `json:"tool_call_id,omitempty"`
+		IsError    bool              `json:"is_error,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parts := make([]Part, len(raw.Parts))
+	for i, data := range raw.Parts {
+		part, err := unmarshalPart(data)
+		if err != nil {
+			return fmt.Errorf("decoding part %d: %w", i, err)
+		}
+		parts[i] = part
+	}
+
+	m.Role = raw.Role
+	m.Parts = parts
+	m.Name = raw.Name
+	m.ToolCalls = raw.ToolCalls
+	m.ToolCallID = raw.ToolCallID
+	m.IsError = raw.IsError
+	return nil
+}
+
+// marshalPart encodes a part as a JSON object with a "type" field.
+func marshalPart(part Part) ([]byte, error) {
+	if part == nil {
+		return nil, fmt.Errorf("nil part")
+	}
+
+	data, err := json.Marshal(part)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	typ, _ := json.Marshal(part.partType())
+	fields["type"] = typ
+
+	return json.Marshal(fields)
+}
+
+// unmarshalPart decodes a part using its "type" field.
+func unmarshalPart(data []byte) (Part, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	switch header.Type {
+	case "text":
+		var p Text
+		err := json.Unmarshal(data, &p)
+		return p, err
+	case "image_url":
+		var p ImageURL
+		err := json.Unmarshal(data, &p)
+		return p, err
+	case "audio":
+		var p Audio
+		err := json.Unmarshal(data, &p)
+		return p, err
+	case "video":
+		var p Video
+		err := json.Unmarshal(data, &p)
+		return p, err
+	case "file":
+		var p File
+		err := json.Unmarshal(data, &p)
+		return p, err
+	default:
+		return nil, fmt.Errorf("unknown part type %q", header.Type)
+	}
+}
@@ -3,8 +3,10 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 )
@@ -19,7 +21,9 @@ const (
 	ErrorForbidden             ErrorCode = "forbidden"               // No permission
 	ErrorNotFound              ErrorCode = "not_found"               // Resource not found
 	ErrorContextLengthExceeded ErrorCode = "context_length_exceeded" // Input too long
+	ErrorInputTooLarge         ErrorCode = "input_too_large"         // Input exceeds a configured token cap
 	ErrorUnsupported           ErrorCode = "unsupported"             // Feature not available
+	ErrorUnknownModel          ErrorCode = "unknown_model"           // Model not recognized by provider
 
 	// Rate limiting and capacity
 	ErrorRateLimited ErrorCode = "rate_limited" // 429 Too Many Requests
@@ -53,8 +57,18 @@ type AIError struct {
 	Model string `json:"model,omitempty"`
 	// HTTPStatus is the original HTTP status code (if applicable)
 	HTTPStatus int `json:"http_status,omitempty"`
+	// ActualTokens is the measured input size for ErrorInputTooLarge (optional)
+	ActualTokens int `json:"actual_tokens,omitempty"`
+	// MaxTokens is the limit that ActualTokens exceeded (optional)
+	MaxTokens int `json:"max_tokens,omitempty"`
+	// Duration is the timeout that expired for ErrorTimeout errors from
+	// Request.AbortAfter or Request.FirstTokenTimeout (optional)
+	Duration time.Duration `json:"duration,omitempty"`
 	// Raw contains the original provider error for debugging
 	Raw any `json:"raw,omitempty"`
+	// Details carries structured context for logging, such as the key of a
+	// per-user rate limit (optional)
+	Details map[string]any `json:"details,omitempty"`
 	// wrapped allows error chaining
 	wrapped error
 }
@@ -135,6 +149,21 @@ func WithHTTPStatus(status int) ErrorOption {
 	}
 }
 
+// WithTokenCounts sets the measured input size and the limit it exceeded.
+func WithTokenCounts(actual, max int) ErrorOption {
+	return func(e *AIError) {
+		e.ActualTokens = actual
+		e.MaxTokens = max
+	}
+}
+
+// WithDuration sets the timeout that expired.
+func WithDuration(d time.Duration) ErrorOption {
+	return func(e *AIError) {
+		e.Duration = d
+	}
+}
+
 // WithRaw attaches the original provider error.
 func WithRaw(raw any) ErrorOption {
 	return func(e *AIError) {
@@ -142,6 +171,13 @@ func WithRaw(raw any) ErrorOption {
 	}
 }
 
+// WithDetails attaches structured context to the error.
+func WithDetails(details map[string]any) ErrorOption {
+	return func(e *AIError) {
+		e.Details = details
+	}
+}
+
 // WithWrapped wraps another error for chaining.
 func WithWrapped(err error) ErrorOption {
 	return func(e *AIError) {
@@ -191,7 +227,18 @@ func IsBadRequest(err error) bool {
 	if errors.As(err, &aiErr) {
 		return aiErr.Code == ErrorInvalidRequest || 
 		       aiErr.Code == ErrorContextLengthExceeded ||
-		       aiErr.Code == ErrorUnsupported
+		       aiErr.Code == ErrorInputTooLarge ||
+		       aiErr.Code == ErrorUnsupported ||
+		       aiErr.Code == ErrorUnknownModel
+	}
+	return false
+}
+
+// IsUnknownModel returns true if the error is due to an unrecognized model.
+func IsUnknownModel(err error) bool {
+	var aiErr *AIError
+	if errors.As(err, &aiErr) {
+		return aiErr.Code == ErrorUnknownModel
 	}
 	return false
 }
@@ -279,6 +326,45 @@ func FromHTTPStatus(status int, message string, provider string) *AIError {
 	return NewError(code, message, opts...)
 }
 
+// ErrorCodeOf returns the stable error category of err as used in
+// Event.ErrorCode. The code of an AIError in err's chain is used as is;
+// other errors are mapped on a best-effort basis from an HTTP status code
+// (an error with a StatusCode() int method), context deadlines and network
+// errors, and default to "internal". It returns "" for a nil error.
+//
+// Example:
+//
+//	switch event.ErrorCode {
+//	case "rate_limited":
+//		// back off
+//	case "timeout":
+//		// retry with a longer deadline
+//	}
+func ErrorCodeOf(err error) string {
+	if err == nil {
+		return ""
+	}
+	var aiErr *AIError
+	if errors.As(err, &aiErr) {
+		return string(aiErr.Code)
+	}
+	var statusErr interface{ StatusCode() int }
+	if errors.As(err, &statusErr) {
+		return string(httpStatusToErrorCode(statusErr.StatusCode()))
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return string(ErrorTimeout)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return string(ErrorTimeout)
+		}
+		return string(ErrorNetwork)
+	}
+	return string(ErrorInternal)
+}
+
 // httpStatusToErrorCode maps HTTP status codes to error codes.
 func httpStatusToErrorCode(status int) ErrorCode {
 	switch status {
@@ -342,6 +428,18 @@ func WrapError(err error, code ErrorCode, provider string) *AIError {
 	)
 }
 
+// CheckSingleCompletion returns an ErrorUnsupported error when req.N asks
+// for more than one completion. Providers call it from methods that cannot
+// return TextResult.Alternatives.
+func CheckSingleCompletion(req Request, provider string) error {
+	if req.N <= 1 {
+		return nil
+	}
+	return NewError(ErrorUnsupported,
+		fmt.Sprintf("%s does not support multiple completions (N=%d)", provider, req.N),
+		WithProvider(provider), WithModel(req.Model))
+}
+
 // Sentinel errors for common cases
 var (
 	ErrInvalidRequest        = NewError(ErrorInvalidRequest, "invalid request")
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"testing"
 	"time"
@@ -256,6 +259,7 @@ func TestIsBadRequest(t *testing.T) {
 		{NewError(ErrorInvalidRequest, "Invalid"), true},
 		{NewError(ErrorContextLengthExceeded, "Too long"), true},
 		{NewError(ErrorUnsupported, "Unsupported"), true},
+		{NewError(ErrorUnknownModel, "Unknown model"), true},
 		{NewError(ErrorRateLimited, "Rate limited"), false},
 		{fmt.Errorf("random error"), false},
 		{nil, false},
@@ -274,6 +278,31 @@ func TestIsBadRequest(t *testing.T) {
 	}
 }
 
+func TestIsUnknownModel(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{NewError(ErrorUnknownModel, "Unknown model"), true},
+		{fmt.Errorf("wrapped: %w", NewError(ErrorUnknownModel, "Unknown model")), true},
+		{NewError(ErrorNotFound, "Not found"), false},
+		{fmt.Errorf("random error"), false},
+		{nil, false},
+	}
+	
+	for _, tt := range tests {
+		name := "nil"
+		if tt.err != nil {
+			name = tt.err.Error()
+		}
+		t.Run(name, func(t *testing.T) {
+			if got := IsUnknownModel(tt.err); got != tt.expected {
+				t.Errorf("IsUnknownModel(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestIsNotFound(t *testing.T) {
 	tests := []struct {
 		err      error
@@ -504,6 +533,54 @@ func TestFromHTTPStatus(t *testing.T) {
 	}
 }
 
+// statusError is a non-AIError carrying an HTTP status code.
+type statusError struct{ status int }
+
+func (e statusError) Error() string   { return fmt.Sprintf("status %d", e.status) }
+func (e statusError) StatusCode() int { return e.status }
+
+func TestErrorCodeOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"ai error", NewError(ErrorRateLimited, "slow down"), "rate_limited"},
+		{"wrapped ai error", fmt.Errorf("stream: %w", NewError(ErrorOverloaded, "busy")), "overloaded"},
+		{"status code", statusError{http.StatusUnauthorized}, "unauthorized"},
+		{"wrapped status code", fmt.Errorf("request: %w", statusError{http.StatusGatewayTimeout}), "timeout"},
+		{"deadline", fmt.Errorf("reading: %w", context.DeadlineExceeded), "timeout"},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, "network"},
+		{"generic", errors.New("boom"), "internal"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCodeOf(tt.err); got != tt.expected {
+				t.Errorf("ErrorCodeOf() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckSingleCompletion(t *testing.T) {
+	for _, n := range []int{0, 1} {
+		if err := CheckSingleCompletion(Request{N: n}, "anthropic"); err != nil {
+			t.Errorf("N=%d: unexpected error %v", n, err)
+		}
+	}
+
+	err := CheckSingleCompletion(Request{Model: "claude-sonnet-4", N: 3}, "anthropic")
+	if !IsUnsupported(err) {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+	var aiErr *AIError
+	if errors.As(err, &aiErr) && (aiErr.Provider != "anthropic" || aiErr.Model != "claude-sonnet-4") {
+		t.Errorf("unexpected error fields: %+v", aiErr)
+	}
+}
+
 func TestWrapError(t *testing.T) {
 	tests := []struct {
 		name         string
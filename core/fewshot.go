@@ -0,0 +1,91 @@
+// Package core provides few-shot prompting helpers.
+// This file implements a builder for interleaved example exchanges that can
+// be inserted into a request ahead of the conversation.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FewShotBuilder builds example User/Assistant exchanges for few-shot
+// prompting. The zero value is ready to use.
+//
+// Example:
+//
+//	req = new(core.FewShotBuilder).
+//		Add("I loved it", "positive").
+//		Add("Total waste of money", "negative").
+//		PrependTo(req)
+type FewShotBuilder struct {
+	messages []Message
+	err      error
+}
+
+// Add appends a text example: input as a User message and output as the
+// Assistant reply.
+func (b *FewShotBuilder) Add(input, output string) *FewShotBuilder {
+	return b.AddMultiPart([]Part{Text{Text: input}}, []Part{Text{Text: output}})
+}
+
+// AddMultiPart appends an example whose input and output are made of
+// arbitrary parts, such as an image followed by its expected description.
+func (b *FewShotBuilder) AddMultiPart(inputParts, outputParts []Part) *FewShotBuilder {
+	b.messages = append(b.messages,
+		Message{Role: User, Parts: inputParts},
+		Message{Role: Assistant, Parts: outputParts},
+	)
+	return b
+}
+
+// AddJSON appends an example with input and output marshaled to JSON, for
+// few-shotting structured tasks. If either value cannot be marshaled the
+// example is skipped and the error is reported by Err.
+func (b *FewShotBuilder) AddJSON(input, output any) *FewShotBuilder {
+	in, err := json.Marshal(input)
+	if err != nil {
+		b.setErr(fmt.Errorf("few-shot input: %w", err))
+		return b
+	}
+	out, err := json.Marshal(output)
+	if err != nil {
+		b.setErr(fmt.Errorf("few-shot output: %w", err))
+		return b
+	}
+	return b.Add(string(in), string(out))
+}
+
+// setErr records the first error encountered while building.
+func (b *FewShotBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Err returns the first error from AddJSON, if any.
+func (b *FewShotBuilder) Err() error {
+	return b.err
+}
+
+// Messages returns the examples as alternating User and Assistant messages.
+func (b *FewShotBuilder) Messages() []Message {
+	return append([]Message(nil), b.messages...)
+}
+
+// PrependTo returns a copy of req with the examples inserted after any
+// leading system messages and before the rest of the conversation, so the
+// final user message follows the examples.
+func (b *FewShotBuilder) PrependTo(req Request) Request {
+	insert := 0
+	for insert < len(req.Messages) && req.Messages[insert].Role == System {
+		insert++
+	}
+
+	messages := make([]Message, 0, len(req.Messages)+len(b.messages))
+	messages = append(messages, req.Messages[:insert]...)
+	messages = append(messages, b.messages...)
+	messages = append(messages, req.Messages[insert:]...)
+	req.Messages = messages
+	return req
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestFewShotBuilder(t *testing.T) {
+	b := new(FewShotBuilder).
+		Add("I loved it", "positive").
+		AddJSON(map[string]int{"a": 1}, []string{"x"}).
+		AddMultiPart([]Part{ImageURL{URL: "https://example.com/cat.png"}}, []Part{Text{Text: "a cat"}})
+	if err := b.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	messages := b.Messages()
+	if len(messages) != 6 {
+		t.Fatalf("expected 6 messages, got %d", len(messages))
+	}
+	for i, msg := range messages {
+		want := User
+		if i%2 == 1 {
+			want = Assistant
+		}
+		if msg.Role != want {
+			t.Errorf("message %d: expected role %s, got %s", i, want, msg.Role)
+		}
+	}
+	if got := messages[2].Parts[0].(Text).Text; got != `{"a":1}` {
+		t.Errorf("unexpected JSON input: %s", got)
+	}
+	if got := messages[3].Parts[0].(Text).Text; got != `["x"]` {
+		t.Errorf("unexpected JSON output: %s", got)
+	}
+
+	req := Request{Messages: []Message{
+		{Role: System, Parts: []Part{Text{Text: "Classify sentiment"}}},
+		userMessage("Not bad at all"),
+	}}
+	got := b.PrependTo(req)
+	if len(got.Messages) != 8 {
+		t.Fatalf("expected 8 messages, got %d", len(got.Messages))
+	}
+	if got.Messages[0].Role != System || got.Messages[1].Parts[0].(Text).Text != "I loved it" {
+		t.Errorf("examples should follow the system message: %+v", got.Messages[:2])
+	}
+	if last := got.Messages[7]; last.Role != User || last.Parts[0].(Text).Text != "Not bad at all" {
+		t.Errorf("final user message should come last, got %+v", last)
+	}
+	if len(req.Messages) != 2 {
+		t.Errorf("PrependTo modified the original request")
+	}
+
+	if err := new(FewShotBuilder).AddJSON(make(chan int), "x").Err(); err == nil {
+		t.Error("expected error for unmarshalable input")
+	}
+}
@@ -0,0 +1,218 @@
+// Package core provides automatic image fetching.
+// This file implements Request.AutoFetchImages: image URLs are downloaded and
+// inlined as data: URLs for providers that cannot fetch them themselves.
+
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// MaxFetchedImageSize is the largest image in bytes that FetchImages
+// downloads.
+const MaxFetchedImageSize = 20 << 20
+
+// maxImageRedirects is the number of redirects FetchImages follows for one
+// image.
+const maxImageRedirects = 10
+
+// AllowPrivateImageHosts lets FetchImages download images from loopback,
+// private and link-local addresses. It is false by default so that image
+// URLs taken from user input cannot reach internal services; enable it only
+// when every image URL is trusted, such as in tests against a local server.
+var AllowPrivateImageHosts = false
+
+// FetchImages returns req with every HTTPS ImageURL replaced by a data: URL
+// holding the downloaded image, when req.AutoFetchImages is set. Each URL is
+// fetched once per request with client (http.DefaultClient if nil), and the
+// fetches are cancelled with ctx. Redirects are only followed to other HTTPS
+// URLs, and hosts that resolve to loopback, private or link-local addresses
+// are rejected unless AllowPrivateImageHosts is set. AutoFetchImages is cleared so images are
+// fetched only once; req.Messages is not modified. Providers call this
+// through PrepareRequest once the request is known to be valid, passing
+// their own HTTP client so connections are reused.
+//
+// Example:
+//
+//	req := core.Request{
+//		Messages: []core.Message{{Role: core.User, Parts: []core.Part{
+//			core.Text{Text: "What is in this picture?"},
+//			core.ImageURL{URL: "https://example.com/private/photo.png?token=abc"},
+//		}}},
+//		AutoFetchImages: true,
+//	}
+func FetchImages(ctx context.Context, req Request, client *http.Client) (Request, error) {
+	if !req.AutoFetchImages {
+		return req, nil
+	}
+	req.AutoFetchImages = false
+	if client == nil {
+		client = http.DefaultClient
+	}
+	// Copy the client to vet redirects without changing the caller's client
+	guarded := *client
+	checkRedirect := client.CheckRedirect
+	guarded.CheckRedirect = func(r *http.Request, via []*http.Request) error {
+		if len(via) >= maxImageRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxImageRedirects)
+		}
+		if r.URL.Scheme != "https" {
+			return NewError(ErrorInvalidRequest, fmt.Sprintf("image redirected to non-HTTPS URL %q", r.URL.Redacted()))
+		}
+		if err := checkImageHost(r.Context(), r.URL.Hostname()); err != nil {
+			return err
+		}
+		if checkRedirect != nil {
+			return checkRedirect(r, via)
+		}
+		return nil
+	}
+	client = &guarded
+
+	fetched := make(map[string]string)
+	var messages []Message
+	for i, msg := range req.Messages {
+		var parts []Part
+		for j, part := range msg.Parts {
+			image, ok := part.(ImageURL)
+			if !ok || !strings.HasPrefix(image.URL, "https://") {
+				continue
+			}
+			dataURL, ok := fetched[image.URL]
+			if !ok {
+				var err error
+				if dataURL, err = fetchImage(ctx, client, image.URL); err != nil {
+					return req, err
+				}
+				fetched[image.URL] = dataURL
+			}
+
+			// Copy on first write so the caller's messages are unchanged
+			if parts == nil {
+				parts = make([]Part, len(msg.Parts))
+				copy(parts, msg.Parts)
+			}
+			image.URL = dataURL
+			parts[j] = image
+		}
+		if parts == nil {
+			continue
+		}
+		if messages == nil {
+			messages = make([]Message, len(req.Messages))
+			copy(messages, req.Messages)
+		}
+		messages[i].Parts = parts
+	}
+	if messages != nil {
+		req.Messages = messages
+	}
+	return req, nil
+}
+
+// fetchImage downloads an image and encodes it as a data: URL.
+func fetchImage(ctx context.Context, client *http.Client, url string) (string, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", NewError(ErrorInvalidRequest, fmt.Sprintf("invalid image URL %q", url), WithWrapped(err))
+	}
+	if err := checkImageHost(ctx, httpReq.URL.Hostname()); err != nil {
+		return "", err
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		// Rejected redirects are returned wrapped in a *url.Error
+		var aiErr *AIError
+		if errors.As(err, &aiErr) {
+			return "", aiErr
+		}
+		return "", NewError(ErrorNetwork, fmt.Sprintf("fetching image %q", url), WithWrapped(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", NewError(ErrorInvalidRequest,
+			fmt.Sprintf("fetching image %q: unexpected status %d", url, resp.StatusCode),
+			WithHTTPStatus(resp.StatusCode))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxFetchedImageSize+1))
+	if err != nil {
+		return "", NewError(ErrorNetwork, fmt.Sprintf("reading image %q", url), WithWrapped(err))
+	}
+	if len(data) > MaxFetchedImageSize {
+		return "", NewError(ErrorInputTooLarge, fmt.Sprintf("image %q exceeds %d bytes", url, MaxFetchedImageSize))
+	}
+
+	mime := resp.Header.Get("Content-Type")
+	if i := strings.IndexByte(mime, ';'); i >= 0 {
+		mime = mime[:i]
+	}
+	mime = strings.TrimSpace(mime)
+	if !strings.HasPrefix(mime, "image/") {
+		mime = http.DetectContentType(data)
+	}
+	if !strings.HasPrefix(mime, "image/") {
+		return "", NewError(ErrorInvalidRequest, fmt.Sprintf("%q is not an image (%s)", url, mime))
+	}
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// checkImageHost returns an ErrorInvalidRequest error if host is, or
+// resolves to, a loopback, private, link-local or unspecified address,
+// unless AllowPrivateImageHosts is set.
+func checkImageHost(ctx context.Context, host string) error {
+	if AllowPrivateImageHosts {
+		return nil
+	}
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else {
+		resolved, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return NewError(ErrorNetwork, fmt.Sprintf("resolving image host %q", host), WithWrapped(err))
+		}
+		addrs = resolved
+	}
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+			addr.IsLinkLocalMulticast() || addr.IsUnspecified() {
+			return NewError(ErrorInvalidRequest,
+				fmt.Sprintf("image host %q resolves to non-public address %s", host, addr))
+		}
+	}
+	return nil
+}
+
+// ParseDataURL splits a base64 data: URL such as "data:image/png;base64,..."
+// into its MIME type and base64 payload. ok is false for other URLs.
+func ParseDataURL(url string) (mime, data string, ok bool) {
+	rest, found := strings.CutPrefix(url, "data:")
+	if !found {
+		return "", "", false
+	}
+	header, data, found := strings.Cut(rest, ",")
+	if !found {
+		return "", "", false
+	}
+	mime, found = strings.CutSuffix(header, ";base64")
+	if !found {
+		return "", "", false
+	}
+	return mime, data, true
+}
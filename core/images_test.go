@@ -0,0 +1,213 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// pngHeader is enough of a PNG file for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// allowPrivateImageHosts lets FetchImages reach httptest servers on the
+// loopback address for the rest of the test.
+func allowPrivateImageHosts(t *testing.T) {
+	t.Helper()
+	AllowPrivateImageHosts = true
+	t.Cleanup(func() { AllowPrivateImageHosts = false })
+}
+
+func TestFetchImages(t *testing.T) {
+	allowPrivateImageHosts(t)
+	fetches := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		switch r.URL.Path {
+		case "/photo.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(pngHeader)
+		case "/sniffed":
+			w.Write(pngHeader)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	photo := server.URL + "/photo.png"
+	messages := []Message{
+		{Role: User, Parts: []Part{Text{Text: "Compare"}, ImageURL{URL: photo, Detail: "high"}}},
+		{Role: User, Parts: []Part{
+			ImageURL{URL: photo},
+			ImageURL{URL: "http://example.com/plain.png"},
+			ImageURL{URL: "data:image/gif;base64,R0lGOD"},
+		}},
+	}
+	req := Request{Messages: messages, AutoFetchImages: true}
+
+	got, err := FetchImages(context.Background(), req, server.Client())
+	if err != nil {
+		t.Fatalf("FetchImages failed: %v", err)
+	}
+	want := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngHeader)
+	first := got.Messages[0].Parts[1].(ImageURL)
+	if first.URL != want || first.Detail != "high" {
+		t.Errorf("unexpected first image: %+v", first)
+	}
+	if got.Messages[1].Parts[0].(ImageURL).URL != want {
+		t.Errorf("repeated URL not replaced: %v", got.Messages[1].Parts[0])
+	}
+	if got.Messages[1].Parts[1].(ImageURL).URL != "http://example.com/plain.png" {
+		t.Error("non-HTTPS URL should be left unchanged")
+	}
+	if got.Messages[1].Parts[2].(ImageURL).URL != "data:image/gif;base64,R0lGOD" {
+		t.Error("data: URL should be left unchanged")
+	}
+	if fetches != 1 {
+		t.Errorf("expected one fetch for a repeated URL, got %d", fetches)
+	}
+	if got.AutoFetchImages {
+		t.Error("AutoFetchImages should be cleared")
+	}
+	if messages[0].Parts[1].(ImageURL).URL != photo {
+		t.Error("caller's messages were modified")
+	}
+
+	// Without the flag the request is unchanged
+	if got, _ := FetchImages(context.Background(), Request{Messages: messages}, server.Client()); got.Messages[0].Parts[1].(ImageURL).URL != photo {
+		t.Error("images fetched without AutoFetchImages")
+	}
+
+	// Content type is sniffed when the server does not send an image type
+	sniffed := Request{Messages: []Message{{Role: User, Parts: []Part{ImageURL{URL: server.URL + "/sniffed"}}}}, AutoFetchImages: true}
+	if got, err := FetchImages(context.Background(), sniffed, server.Client()); err != nil || !strings.HasPrefix(got.Messages[0].Parts[0].(ImageURL).URL, "data:image/png;base64,") {
+		t.Errorf("sniffing failed: %v", err)
+	}
+}
+
+func TestFetchImagesErrors(t *testing.T) {
+	allowPrivateImageHosts(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/page.html" {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><body>hello</body></html>"))
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	request := func(path string) Request {
+		return Request{
+			Messages:        []Message{{Role: User, Parts: []Part{ImageURL{URL: server.URL + path}}}},
+			AutoFetchImages: true,
+		}
+	}
+
+	_, err := FetchImages(context.Background(), request("/private.png"), server.Client())
+	if !IsBadRequest(err) {
+		t.Errorf("forbidden image: expected invalid request error, got %v", err)
+	}
+	_, err = FetchImages(context.Background(), request("/page.html"), server.Client())
+	if !IsBadRequest(err) {
+		t.Errorf("non-image: expected invalid request error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = FetchImages(ctx, request("/photo.png"), server.Client()); err != context.Canceled {
+		t.Errorf("cancelled context: expected context.Canceled, got %v", err)
+	}
+}
+
+func TestFetchImagesPrivateHosts(t *testing.T) {
+	fetches := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pngHeader)
+	}))
+	defer server.Close()
+
+	for _, url := range []string{
+		server.URL + "/photo.png",
+		"https://localhost/photo.png",
+		"https://10.0.0.1/photo.png",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::ffff:127.0.0.1]/photo.png",
+	} {
+		req := Request{
+			Messages:        []Message{{Role: User, Parts: []Part{ImageURL{URL: url}}}},
+			AutoFetchImages: true,
+		}
+		if _, err := FetchImages(context.Background(), req, server.Client()); !IsBadRequest(err) {
+			t.Errorf("%s: expected invalid request error, got %v", url, err)
+		}
+	}
+	if fetches != 0 {
+		t.Errorf("private hosts were fetched %d times", fetches)
+	}
+}
+
+func TestFetchImagesRedirects(t *testing.T) {
+	allowPrivateImageHosts(t)
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pngHeader)
+	}))
+	defer plain.Close()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/insecure":
+			http.Redirect(w, r, plain.URL+"/photo.png", http.StatusFound)
+		case "/moved":
+			http.Redirect(w, r, "/photo.png", http.StatusFound)
+		default:
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(pngHeader)
+		}
+	}))
+	defer server.Close()
+
+	request := func(path string) Request {
+		return Request{
+			Messages:        []Message{{Role: User, Parts: []Part{ImageURL{URL: server.URL + path}}}},
+			AutoFetchImages: true,
+		}
+	}
+
+	if _, err := FetchImages(context.Background(), request("/insecure"), server.Client()); !IsBadRequest(err) {
+		t.Errorf("redirect to HTTP: expected invalid request error, got %v", err)
+	}
+	got, err := FetchImages(context.Background(), request("/moved"), server.Client())
+	if err != nil {
+		t.Fatalf("redirect to HTTPS failed: %v", err)
+	}
+	if !strings.HasPrefix(got.Messages[0].Parts[0].(ImageURL).URL, "data:image/png;base64,") {
+		t.Errorf("redirected image not inlined: %v", got.Messages[0].Parts[0])
+	}
+}
+
+func TestParseDataURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		mime string
+		data string
+		ok   bool
+	}{
+		{"data:image/png;base64,iVBOR", "image/png", "iVBOR", true},
+		{"data:image/svg+xml,<svg/>", "", "", false},
+		{"https://example.com/a.png", "", "", false},
+		{"data:image/png;base64", "", "", false},
+	}
+
+	for _, tt := range tests {
+		mime, data, ok := ParseDataURL(tt.url)
+		if mime != tt.mime || data != tt.data || ok != tt.ok {
+			t.Errorf("ParseDataURL(%q) = %q, %q, %v; want %q, %q, %v", tt.url, mime, data, ok, tt.mime, tt.data, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package core provides inspection of multi-step results.
+// This file implements helpers for querying the tool calls, results and text
+// recorded in TextResult.Steps, for assertions and post-processing.
+
+package core
+
+import "fmt"
+
+// StepInspector answers common questions about the steps of a multi-step
+// result. Convert a slice to use it:
+//
+//	steps := core.StepInspector(result.Steps)
+//	if steps.WasToolCalled("get_weather") {
+//	    fmt.Println(steps.ToolCallCount(), steps.ToolNames())
+//	}
+type StepInspector []Step
+
+// ToolNames returns the distinct names of the tools called, in the order
+// they were first called.
+func (s StepInspector) ToolNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, step := range s {
+		for _, call := range step.ToolCalls {
+			if !seen[call.Name] {
+				seen[call.Name] = true
+				names = append(names, call.Name)
+			}
+		}
+	}
+	return names
+}
+
+// ToolCallCount returns the total number of tool calls across all steps.
+func (s StepInspector) ToolCallCount() int {
+	count := 0
+	for _, step := range s {
+		count += len(step.ToolCalls)
+	}
+	return count
+}
+
+// ToolResultFor returns the result of the tool call with the given ID.
+func (s StepInspector) ToolResultFor(callID string) (ToolExecution, bool) {
+	for _, step := range s {
+		for _, result := range step.ToolResults {
+			if result.ID == callID {
+				return result, true
+			}
+		}
+	}
+	return ToolExecution{}, false
+}
+
+// WasToolCalled reports whether any step called the named tool.
+func (s StepInspector) WasToolCalled(name string) bool {
+	for _, step := range s {
+		for _, call := range step.ToolCalls {
+			if call.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LastText returns the text of the last step that produced any.
+func (s StepInspector) LastText() string {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].Text != "" {
+			return s[i].Text
+		}
+	}
+	return ""
+}
+
+// Errors returns an error for each tool execution that failed, in order.
+func (s StepInspector) Errors() []error {
+	var errs []error
+	for _, step := range s {
+		for _, result := range step.ToolResults {
+			if result.Error != "" {
+				errs = append(errs, fmt.Errorf("tool %s (call %s): %s", result.Name, result.ID, result.Error))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStepInspector(t *testing.T) {
+	steps := StepInspector{
+		{
+			StepNumber: 1,
+			ToolCalls: []ToolCall{
+				{ID: "call_1", Name: "search"},
+				{ID: "call_2", Name: "weather"},
+			},
+			ToolResults: []ToolExecution{
+				{ID: "call_1", Name: "search", Result: "found"},
+				{ID: "call_2", Name: "weather", Error: "service unavailable"},
+			},
+		},
+		{
+			StepNumber:  2,
+			Text:        "Retrying the forecast",
+			ToolCalls:   []ToolCall{{ID: "call_3", Name: "weather"}},
+			ToolResults: []ToolExecution{{ID: "call_3", Name: "weather", Result: "sunny"}},
+		},
+		{StepNumber: 3},
+	}
+
+	if got := steps.ToolNames(); !reflect.DeepEqual(got, []string{"search", "weather"}) {
+		t.Errorf("ToolNames() = %v", got)
+	}
+	if got := steps.ToolCallCount(); got != 3 {
+		t.Errorf("ToolCallCount() = %d, want 3", got)
+	}
+	if result, ok := steps.ToolResultFor("call_3"); !ok || result.Result != "sunny" {
+		t.Errorf("ToolResultFor(call_3) = %v, %v", result, ok)
+	}
+	if _, ok := steps.ToolResultFor("missing"); ok {
+		t.Error("Expected no result for unknown call ID")
+	}
+	if !steps.WasToolCalled("weather") || steps.WasToolCalled("calculator") {
+		t.Error("WasToolCalled returned wrong results")
+	}
+	if got := steps.LastText(); got != "Retrying the forecast" {
+		t.Errorf("LastText() = %q", got)
+	}
+
+	errs := steps.Errors()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "service unavailable") {
+		t.Errorf("Errors() = %v", errs)
+	}
+
+	var empty StepInspector
+	if empty.ToolNames() != nil || empty.ToolCallCount() != 0 || empty.LastText() != "" || empty.Errors() != nil {
+		t.Error("Expected zero values for no steps")
+	}
+}
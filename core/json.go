@@ -0,0 +1,429 @@
+// Package core provides schema-only structured output generation.
+// This file implements GenerateJSON for callers that have a JSON Schema but
+// no corresponding Go type, along with the validator used to check results.
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// JSONResult represents a structured output result validated against a raw JSON Schema.
+type JSONResult struct {
+	// Value is the generated object
+	Value map[string]any `json:"value"`
+	// RawJSON is the JSON encoding of Value
+	RawJSON json.RawMessage `json:"raw_json"`
+	// Usage tracks token consumption across all attempts
+	Usage Usage `json:"usage"`
+}
+
+// GenerateJSON generates a JSON object conforming to a raw JSON Schema.
+// It is useful when the schema comes from an external source (a database,
+// an OpenAPI fragment) and there is no Go type to pass to GenerateObject.
+//
+// The provider's GenerateObject is used with the schema as-is. The result is
+// validated against the schema and, when validation fails, the request is
+// re-issued with a corrective message up to req.MaxStructuredRetries times
+// (see GenerateObjectWithRetry).
+func GenerateJSON(ctx context.Context, provider Provider, req Request, schema json.RawMessage) (*JSONResult, error) {
+	var schemaObj map[string]any
+	if err := json.Unmarshal(schema, &schemaObj); err != nil {
+		return nil, NewError(ErrorInvalidRequest, fmt.Sprintf("invalid JSON schema: %v", err), WithWrapped(err))
+	}
+
+	var usage Usage
+	var value map[string]any
+	var rawJSON json.RawMessage
+	_, err := GenerateObjectWithRetry(ctx, req, func(ctx context.Context, attemptReq Request) (*ObjectResult[any], error) {
+		// Retries are driven from here, so the provider makes one attempt
+		attemptReq.MaxStructuredRetries = 0
+		result, err := provider.GenerateObject(ctx, attemptReq, schema)
+		if err != nil {
+			return nil, err
+		}
+		usage.Add(result.Usage)
+
+		raw, err := json.Marshal(result.Value)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling result: %w", err)
+		}
+
+		// Round-trip through JSON so providers returning typed values are
+		// validated the same way as those returning decoded JSON
+		var decoded any
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return nil, fmt.Errorf("decoding result: %w", err)
+		}
+		if err := validateSchema(decoded, schemaObj, ""); err != nil {
+			return nil, &ValidationError{Message: err.Error(), Output: string(raw)}
+		}
+		obj, ok := decoded.(map[string]any)
+		if !ok {
+			return nil, &ValidationError{Message: fmt.Sprintf("expected a JSON object, got %s", jsonTypeName(decoded)), Output: string(raw)}
+		}
+
+		value, rawJSON = obj, raw
+		return result, nil
+	})
+
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return nil, NewError(
+			ErrorInvalidRequest,
+			fmt.Sprintf("output failed schema validation after %d attempt(s): %s", max(req.MaxStructuredRetries, 0)+1, ve.Message),
+			WithModel(req.Model),
+			WithWrapped(err),
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &JSONResult{Value: value, RawJSON: rawJSON, Usage: usage}, nil
+}
+
+// ValidateJSON validates JSON data against a JSON Schema, using the same
+// validator as GenerateJSON. It supports the commonly used subset of JSON
+// Schema listed on validateSchema; other keywords, such as $ref, are
+// ignored. Packages that check data against a schema, such as tools and
+// stream, share it so that every schema in the module means the same thing.
+func ValidateJSON(data json.RawMessage, schema []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("empty JSON data")
+	}
+	if len(schema) == 0 {
+		return fmt.Errorf("empty schema")
+	}
+
+	var schemaObj map[string]any
+	if err := json.Unmarshal(schema, &schemaObj); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON data: %w", err)
+	}
+	return validateSchema(value, schemaObj, "")
+}
+
+// validateSchema checks a decoded JSON value against a JSON Schema object.
+// It supports the commonly used subset of JSON Schema: type, enum, const,
+// properties, required, additionalProperties, items, anyOf/oneOf/allOf,
+// format and the numeric, string and array bounds.
+func validateSchema(value any, schema map[string]any, path string) error {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if matchesType(value, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return schemaError(path, "expected %s, got %s", strings.Join(types, " or "), jsonTypeName(value))
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(value, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return schemaError(path, "value %v is not one of %v", value, enum)
+		}
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(value, c) {
+		return schemaError(path, "value %v does not equal %v", value, c)
+	}
+
+	if sub, ok := schema["allOf"].([]any); ok {
+		for _, s := range sub {
+			if err := validateSubschema(value, s, path); err != nil {
+				return err
+			}
+		}
+	}
+	if sub, ok := schema["anyOf"].([]any); ok && countMatches(value, sub, path) == 0 {
+		return schemaError(path, "value does not match any allowed schema")
+	}
+	if sub, ok := schema["oneOf"].([]any); ok && countMatches(value, sub, path) != 1 {
+		return schemaError(path, "value must match exactly one allowed schema")
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return validateObject(v, schema, path)
+	case []any:
+		if minItems, ok := schema["minItems"].(float64); ok && float64(len(v)) < minItems {
+			return schemaError(path, "array has %d items, minimum %v required", len(v), minItems)
+		}
+		if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(v)) > maxItems {
+			return schemaError(path, "array has %d items, maximum %v allowed", len(v), maxItems)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := validateSchema(item, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if minLength, ok := schema["minLength"].(float64); ok && length < minLength {
+			return schemaError(path, "string length %v is less than minimum %v", length, minLength)
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok && length > maxLength {
+			return schemaError(path, "string length %v exceeds maximum %v", length, maxLength)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := compilePattern(pattern)
+			if err != nil {
+				return schemaError(path, "invalid pattern %q: %v", pattern, err)
+			}
+			if !re.MatchString(v) {
+				return schemaError(path, "value %q does not match pattern %q", v, pattern)
+			}
+		}
+		if format, ok := schema["format"].(string); ok && !validFormat(v, format) {
+			return schemaError(path, "value %q is not a valid %s", v, format)
+		}
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok && v < minimum {
+			return schemaError(path, "value %v is less than minimum %v", v, minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && v > maximum {
+			return schemaError(path, "value %v exceeds maximum %v", v, maximum)
+		}
+		if exclusiveMinimum, ok := schema["exclusiveMinimum"].(float64); ok && v <= exclusiveMinimum {
+			return schemaError(path, "value %v must be greater than %v", v, exclusiveMinimum)
+		}
+		if exclusiveMaximum, ok := schema["exclusiveMaximum"].(float64); ok && v >= exclusiveMaximum {
+			return schemaError(path, "value %v must be less than %v", v, exclusiveMaximum)
+		}
+	}
+
+	return nil
+}
+
+// patternCache stores compiled regular expressions for schema patterns.
+var patternCache sync.Map
+
+// compilePattern compiles a schema pattern, caching the result.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// uuidPattern matches the canonical textual form of a UUID.
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validFormat reports whether str matches a JSON Schema format. Unknown
+// formats are treated as annotations and always pass.
+func validFormat(str, format string) bool {
+	switch format {
+	case "uri", "url":
+		u, err := url.Parse(str)
+		return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+	case "email":
+		addr, err := mail.ParseAddress(str)
+		return err == nil && addr.Address == str
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, str)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, str)
+		return err == nil
+	case "time":
+		_, err := time.Parse(time.TimeOnly, str)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(str)
+	case "ipv4":
+		ip := net.ParseIP(str)
+		return ip != nil && ip.To4() != nil
+	case "ipv6":
+		ip := net.ParseIP(str)
+		return ip != nil && ip.To4() == nil
+	}
+	return true
+}
+
+// validateObject checks the object-specific keywords of a schema.
+func validateObject(obj map[string]any, schema map[string]any, path string) error {
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, exists := obj[name]; !exists {
+					return schemaError(path, "missing required field %q", name)
+				}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+
+	// Check fields in a stable order so errors are deterministic
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+
+		if prop, ok := properties[key]; ok {
+			if err := validateSubschema(obj[key], prop, fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return schemaError(path, "unexpected field %q", key)
+			}
+		case map[string]any:
+			if err := validateSchema(obj[key], additional, fieldPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateSubschema validates against a schema that may be a boolean or an object.
+func validateSubschema(value any, schema any, path string) error {
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			return schemaError(path, "value is not allowed")
+		}
+	case map[string]any:
+		return validateSchema(value, s, path)
+	}
+	return nil
+}
+
+// countMatches returns how many of the subschemas accept the value.
+func countMatches(value any, schemas []any, path string) int {
+	n := 0
+	for _, s := range schemas {
+		if validateSubschema(value, s, path) == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// schemaTypes normalizes the "type" keyword, which may be a string or a list.
+func schemaTypes(t any) []string {
+	switch v := t.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		types := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// matchesType reports whether a decoded JSON value has the given schema type.
+func matchesType(value any, t string) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+// jsonTypeName returns the JSON Schema type name for a decoded JSON value.
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// jsonEqual compares two decoded JSON values structurally.
+func jsonEqual(a, b any) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aJSON) == string(bJSON)
+}
+
+// schemaError formats a validation error, prefixed with the field path when known.
+func schemaError(path, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if path == "" {
+		return fmt.Errorf("%s", msg)
+	}
+	return fmt.Errorf("%s: %s", path, msg)
+}
@@ -0,0 +1,214 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// objectProvider returns the queued values from GenerateObject in order.
+type objectProvider struct {
+	values   []any
+	requests []Request
+}
+
+func (p *objectProvider) GenerateText(ctx context.Context, req Request) (*TextResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *objectProvider) StreamText(ctx context.Context, req Request) (TextStream, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *objectProvider) GenerateObject(ctx context.Context, req Request, schema any) (*ObjectResult[any], error) {
+	p.requests = append(p.requests, req)
+	if len(p.values) == 0 {
+		return nil, errors.New("no more values")
+	}
+	value := p.values[0]
+	p.values = p.values[1:]
+	return &ObjectResult[any]{
+		Value: value,
+		Usage: Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+func (p *objectProvider) StreamObject(ctx context.Context, req Request, schema any) (ObjectStream[any], error) {
+	return nil, errors.New("not implemented")
+}
+
+var personSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"age": {"type": "integer", "minimum": 0},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	},
+	"required": ["name", "age"],
+	"additionalProperties": false
+}`)
+
+func TestGenerateJSON(t *testing.T) {
+	provider := &objectProvider{
+		values: []any{map[string]any{"name": "Ada", "age": 36.0}},
+	}
+
+	result, err := GenerateJSON(context.Background(), provider, Request{
+		Messages: []Message{{Role: User, Parts: []Part{Text{Text: "Describe Ada"}}}},
+	}, personSchema)
+	if err != nil {
+		t.Fatalf("GenerateJSON failed: %v", err)
+	}
+
+	if result.Value["name"] != "Ada" {
+		t.Errorf("Expected name Ada, got %v", result.Value["name"])
+	}
+	if string(result.RawJSON) != `{"age":36,"name":"Ada"}` {
+		t.Errorf("Unexpected raw JSON: %s", result.RawJSON)
+	}
+	if result.Usage.TotalTokens != 15 {
+		t.Errorf("Expected 15 total tokens, got %d", result.Usage.TotalTokens)
+	}
+}
+
+func TestGenerateJSONRetries(t *testing.T) {
+	provider := &objectProvider{
+		values: []any{
+			map[string]any{"name": "Ada"},
+			map[string]any{"name": "Ada", "age": 36.0},
+		},
+	}
+
+	messages := []Message{{Role: User, Parts: []Part{Text{Text: "Describe Ada"}}}}
+	result, err := GenerateJSON(context.Background(), provider, Request{
+		Messages:             messages,
+		MaxStructuredRetries: 2,
+	}, personSchema)
+	if err != nil {
+		t.Fatalf("GenerateJSON failed: %v", err)
+	}
+
+	if len(provider.requests) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(provider.requests))
+	}
+	if result.Usage.TotalTokens != 30 {
+		t.Errorf("Expected usage summed across attempts, got %d", result.Usage.TotalTokens)
+	}
+
+	// The retry should include the failed output and a corrective message
+	retry := provider.requests[1].Messages
+	if len(retry) != 3 {
+		t.Fatalf("Expected 3 messages on retry, got %d", len(retry))
+	}
+	if retry[1].Role != Assistant {
+		t.Errorf("Expected previous output as assistant message, got %s", retry[1].Role)
+	}
+	correction := retry[2].Parts[0].(Text).Text
+	if !strings.Contains(correction, `missing required field "age"`) {
+		t.Errorf("Expected corrective message to describe the error, got %q", correction)
+	}
+
+	// The caller's messages must not be modified
+	if len(messages) != 1 {
+		t.Errorf("Caller messages were modified: %d", len(messages))
+	}
+}
+
+func TestGenerateJSONValidationFailure(t *testing.T) {
+	provider := &objectProvider{
+		values: []any{
+			map[string]any{"name": "Ada", "age": -1.0},
+			map[string]any{"name": "Ada", "age": 36.0, "extra": true},
+		},
+	}
+
+	_, err := GenerateJSON(context.Background(), provider, Request{MaxStructuredRetries: 1}, personSchema)
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if !IsBadRequest(err) {
+		t.Errorf("Expected bad request error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `unexpected field "extra"`) {
+		t.Errorf("Expected last validation error in message, got %v", err)
+	}
+
+	if _, err := GenerateJSON(context.Background(), provider, Request{}, json.RawMessage(`not json`)); err == nil {
+		t.Error("Expected error for invalid schema")
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		data    string
+		wantErr bool
+	}{
+		{"type match", `{"type": "string"}`, `"hi"`, false},
+		{"type mismatch", `{"type": "string"}`, `1`, true},
+		{"nullable", `{"type": ["string", "null"]}`, `null`, false},
+		{"integer", `{"type": "integer"}`, `1.5`, true},
+		{"enum", `{"enum": ["a", "b"]}`, `"c"`, true},
+		{"const", `{"const": 3}`, `3`, false},
+		{"nested", `{"type": "object", "properties": {"a": {"type": "object", "properties": {"b": {"type": "number", "maximum": 1}}}}}`, `{"a": {"b": 2}}`, true},
+		{"array items", `{"type": "array", "items": {"type": "integer"}, "maxItems": 3}`, `[1, 2, 3]`, false},
+		{"array too long", `{"type": "array", "maxItems": 1}`, `[1, 2]`, true},
+		{"pattern", `{"type": "string", "pattern": "^[a-z]+$"}`, `"ABC"`, true},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"type": "number"}]}`, `true`, true},
+		{"oneOf", `{"oneOf": [{"type": "number"}, {"type": "integer"}]}`, `1`, true},
+		{"additionalProperties schema", `{"type": "object", "additionalProperties": {"type": "string"}}`, `{"x": 1}`, true},
+		{"format", `{"type": "string", "format": "uuid"}`, `"123e4567"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema map[string]any
+			var data any
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("invalid schema: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.data), &data); err != nil {
+				t.Fatalf("invalid data: %v", err)
+			}
+
+			err := validateSchema(data, schema, "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		value   string
+		wantErr bool
+	}{
+		{"uri", "https://example.com/path", false},
+		{"uri", "mailto:user@example.com", false},
+		{"uri", "/relative/path", true},
+		{"email", "user@example.com", false},
+		{"email", "not-an-email", true},
+		{"date-time", "2024-01-15T10:30:00Z", false},
+		{"date-time", "2024-01-15", true},
+		{"date", "2024-01-15", false},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", false},
+		{"uuid", "123e4567", true},
+		{"ipv4", "192.168.0.1", false},
+		{"ipv4", "::1", true},
+		{"ipv6", "::1", false},
+		{"custom-format", "anything", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.value, func(t *testing.T) {
+			if valid := validFormat(tt.value, tt.format); valid == tt.wantErr {
+				t.Errorf("validFormat(%q, %q) = %v, wantErr %v", tt.value, tt.format, valid, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Package core provides typed access to request metadata.
+// This file implements helpers that read and write Request.Metadata with the
+// well-known keys, so that middleware, tools and applications agree on names
+// instead of repeating string literals.
+
+package core
+
+// MetadataKey is a key in Request.Metadata. The well-known keys are the
+// Metadata* constants, such as MetadataTenantID and MetadataPriority.
+type MetadataKey string
+
+// SetMetadata sets key to value in req.Metadata. The map is copied before it
+// is modified, so maps shared with other requests are left unchanged.
+func SetMetadata(req *Request, key MetadataKey, value any) {
+	meta := make(map[string]any, len(req.Metadata)+1)
+	for k, v := range req.Metadata {
+		meta[k] = v
+	}
+	meta[string(key)] = value
+	req.Metadata = meta
+}
+
+// GetMetadata returns the value of key in req.Metadata. It reports false if
+// the key is missing or its value is not a T.
+func GetMetadata[T any](req Request, key MetadataKey) (T, bool) {
+	value, ok := req.Metadata[string(key)].(T)
+	return value, ok
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSetGetMetadata(t *testing.T) {
+	shared := map[string]any{"existing": "value"}
+	req := Request{Metadata: shared}
+
+	SetMetadata(&req, MetadataUserID, "user-1")
+	SetMetadata(&req, MetadataPriority, 5)
+
+	if _, ok := shared[MetadataUserID]; ok {
+		t.Error("SetMetadata modified the caller's map")
+	}
+	if req.Metadata["existing"] != "value" {
+		t.Error("existing metadata was lost")
+	}
+
+	if user, ok := GetMetadata[string](req, MetadataUserID); !ok || user != "user-1" {
+		t.Errorf("GetMetadata(user) = %q, %v", user, ok)
+	}
+	if priority, ok := GetMetadata[int](req, MetadataPriority); !ok || priority != 5 {
+		t.Errorf("GetMetadata(priority) = %d, %v", priority, ok)
+	}
+
+	// Wrong type and missing keys report false
+	if _, ok := GetMetadata[string](req, MetadataPriority); ok {
+		t.Error("expected false for a value of the wrong type")
+	}
+	if _, ok := GetMetadata[string](Request{}, MetadataTenantID); ok {
+		t.Error("expected false for a missing key")
+	}
+
+	// Values set with SetMetadata are read from the context
+	ctx := WithMetadata(context.Background(), req.Metadata)
+	if MetadataFromContext(ctx)[MetadataUserID] != "user-1" {
+		t.Error("user ID not propagated to the context")
+	}
+}
@@ -0,0 +1,269 @@
+// Package core provides incremental structured output.
+// This file implements ObjectChunks and StreamObject, which parse a JSON
+// response while it is still streaming, so callers can render fields as soon
+// as they arrive.
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ObjectChunk is a snapshot of structured output being streamed as JSON.
+type ObjectChunk struct {
+	// JSON is the output received so far, with open strings and containers
+	// closed so that it parses. On the final chunk it is the whole output.
+	JSON json.RawMessage
+	// Complete is true for the final chunk when the output is valid JSON
+	Complete bool
+	// ParseError is set on the last chunk if the stream failed or the final
+	// output is not valid JSON
+	ParseError error
+}
+
+// StreamableObjectProvider is implemented by providers that can stream
+// structured output constrained to a JSON Schema. The method cannot be named
+// StreamObject, which Provider already uses for ObjectStream.
+type StreamableObjectProvider interface {
+	// StreamObjectChunks streams output conforming to schema, sending an
+	// ObjectChunk with progressively more complete JSON as text arrives.
+	StreamObjectChunks(ctx context.Context, req Request, schema json.RawMessage) (<-chan ObjectChunk, error)
+}
+
+// ObjectChunks reads the JSON text of stream and sends an ObjectChunk each
+// time more of the document can be parsed. The last chunk has Complete or
+// ParseError set. The channel is closed after it, or when ctx is cancelled,
+// and stream is closed when it ends. Providers use it to implement
+// StreamableObjectProvider.
+func ObjectChunks(ctx context.Context, stream TextStream) <-chan ObjectChunk {
+	out := make(chan ObjectChunk, 16)
+	go func() {
+		defer close(out)
+		defer stream.Close()
+
+		send := func(chunk ObjectChunk) bool {
+			select {
+			case out <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var raw strings.Builder
+		var last string
+		for event := range stream.Events() {
+			switch event.Type {
+			case EventTextDelta:
+				raw.WriteString(event.TextDelta)
+				completed := completePartialJSON(raw.String())
+				if completed == "" || completed == last {
+					continue
+				}
+				last = completed
+				if !send(ObjectChunk{JSON: json.RawMessage(completed)}) {
+					return
+				}
+			case EventError:
+				err := event.Err
+				if err == nil {
+					err = errors.New("stream error")
+				}
+				send(ObjectChunk{JSON: json.RawMessage(last), ParseError: err})
+				return
+			}
+		}
+
+		output := raw.String()
+		var value any
+		if err := json.Unmarshal([]byte(output), &value); err != nil {
+			send(ObjectChunk{JSON: json.RawMessage(output), ParseError: NewValidationError(output, err)})
+			return
+		}
+		send(ObjectChunk{JSON: json.RawMessage(output), Complete: true})
+	}()
+	return out
+}
+
+// ObjectAccumulate waits for the final chunk of ch and returns its JSON, for
+// callers that do not need partial results.
+//
+// Example:
+//
+//	chunks, err := provider.StreamObjectChunks(ctx, req, schema)
+//	if err != nil {
+//		return err
+//	}
+//	data, err := core.ObjectAccumulate(chunks)
+func ObjectAccumulate(ch <-chan ObjectChunk) (json.RawMessage, error) {
+	var last ObjectChunk
+	received := false
+	for chunk := range ch {
+		last, received = chunk, true
+	}
+	switch {
+	case !received:
+		return nil, errors.New("object stream ended without output")
+	case last.ParseError != nil:
+		return nil, last.ParseError
+	case !last.Complete:
+		return nil, errors.New("object stream ended before the output was complete")
+	}
+	return last.JSON, nil
+}
+
+// Partial is a snapshot of an object being streamed by StreamObject.
+type Partial[T any] struct {
+	// Value holds every field received so far. A string or number that is
+	// still streaming holds the part received so far.
+	Value T
+	// Raw is the JSON Value was decoded from (see ObjectChunk.JSON)
+	Raw string
+	// Complete is true for the final snapshot, whose Value is the whole object
+	Complete bool
+	// Err is set on the last snapshot if the stream failed or the final JSON
+	// could not be decoded
+	Err error
+}
+
+// StreamObject streams req and decodes the JSON response into T as it
+// arrives, sending a Partial each time the decoded value may have changed.
+// Set req.ResponseSchema so the provider constrains the output to JSON; with
+// OpenAI it is sent as response_format.json_schema and streamed token by
+// token. Providers implementing StreamableObjectProvider are streamed through
+// StreamObjectChunks with that schema. zero is used only to infer T.
+//
+// The last Partial has Complete set, or Err if the stream failed. The channel
+// is closed after it, or when ctx is cancelled.
+//
+// Example:
+//
+//	req.ResponseSchema = tools.Schema[Recipe]()
+//	partials, err := core.StreamObject(ctx, provider, req, Recipe{})
+//	for p := range partials {
+//		render(p.Value)
+//	}
+func StreamObject[T any](ctx context.Context, provider Provider, req Request, zero T) (<-chan Partial[T], error) {
+	var chunks <-chan ObjectChunk
+	if sp, ok := provider.(StreamableObjectProvider); ok && len(req.ResponseSchema) > 0 {
+		var err error
+		if chunks, err = sp.StreamObjectChunks(ctx, req, req.ResponseSchema); err != nil {
+			return nil, err
+		}
+	} else {
+		req.Stream = true
+		stream, err := provider.StreamText(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		chunks = ObjectChunks(ctx, stream)
+	}
+
+	out := make(chan Partial[T], 16)
+	go func() {
+		defer close(out)
+		for chunk := range chunks {
+			partial := Partial[T]{Raw: string(chunk.JSON), Complete: chunk.Complete, Err: chunk.ParseError}
+			if err := json.Unmarshal(chunk.JSON, &partial.Value); err != nil && chunk.ParseError == nil {
+				if !chunk.Complete {
+					continue
+				}
+				partial.Complete = false
+				partial.Err = NewValidationError(string(chunk.JSON), err)
+			}
+			select {
+			case out <- partial:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// completePartialJSON turns a prefix of a JSON document into valid JSON by
+// closing the open string and containers. An incomplete key, literal or
+// trailing separator is dropped, backing off to the last point where the
+// document can be closed. It returns "" if no prefix is valid.
+func completePartialJSON(s string) string {
+	type cut struct {
+		pos     int
+		closers string
+	}
+
+	var (
+		stack    []byte
+		cuts     []cut
+		inString bool
+		escaped  bool
+	)
+	closers := func() string {
+		b := make([]byte, len(stack))
+		for i, open := range stack {
+			if open == '{' {
+				b[len(stack)-1-i] = '}'
+			} else {
+				b[len(stack)-1-i] = ']'
+			}
+		}
+		return string(b)
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+				cuts = append(cuts, cut{i + 1, closers()})
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			stack = append(stack, c)
+			cuts = append(cuts, cut{i + 1, closers()})
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			cuts = append(cuts, cut{i + 1, closers()})
+		case ' ', '\t', '\n', '\r', ',', ':':
+		default:
+			// Part of a number or literal, which may still be incomplete
+			cuts = append(cuts, cut{i + 1, closers()})
+		}
+	}
+
+	// Close a string value that is still streaming, dropping an incomplete
+	// escape sequence at its end
+	if inString {
+		text := s
+		if i := strings.LastIndexByte(s, '\\'); i >= 0 && i >= len(s)-6 {
+			if candidate := s + `"` + closers(); !escaped && json.Valid([]byte(candidate)) {
+				return candidate
+			}
+			text = s[:i]
+		}
+		if candidate := text + `"` + closers(); json.Valid([]byte(candidate)) {
+			return candidate
+		}
+	}
+	for i := len(cuts) - 1; i >= 0; i-- {
+		if candidate := s[:cuts[i].pos] + cuts[i].closers; json.Valid([]byte(candidate)) {
+			return candidate
+		}
+	}
+	return ""
+}
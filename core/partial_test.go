@@ -0,0 +1,174 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// streamingProvider streams the given text deltas from StreamText.
+type streamingProvider struct {
+	objectProvider
+	deltas []string
+	err    error
+	req    Request
+}
+
+func (p *streamingProvider) StreamText(ctx context.Context, req Request) (TextStream, error) {
+	p.req = req
+	events := []Event{{Type: EventStart}}
+	for _, delta := range p.deltas {
+		events = append(events, Event{Type: EventTextDelta, TextDelta: delta})
+	}
+	if p.err != nil {
+		events = append(events, Event{Type: EventError, Err: p.err})
+	} else {
+		events = append(events, Event{Type: EventFinish})
+	}
+	return newChanStream(events...), nil
+}
+
+func TestCompletePartialJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{``, ``},
+		{`{`, `{}`},
+		{`{"na`, `{}`},
+		{`{"name"`, `{}`},
+		{`{"name":`, `{}`},
+		{`{"name":"Pan`, `{"name":"Pan"}`},
+		{`{"name":"Pancakes","servings":`, `{"name":"Pancakes"}`},
+		{`{"name":"Pancakes","servings":4`, `{"name":"Pancakes","servings":4}`},
+		{`{"steps":["Mix","He`, `{"steps":["Mix","He"]}`},
+		{`{"steps":["Mix"],"done":tr`, `{"steps":["Mix"]}`},
+		{`{"quote":"say \"hi`, `{"quote":"say \"hi"}`},
+		{`{"quote":"a\`, `{"quote":"a"}`},
+		{`{"quote":"caf\u00`, `{"quote":"caf"}`},
+		{`{"a":{"b":[1,`, `{"a":{"b":[1]}}`},
+	}
+
+	for _, tt := range tests {
+		if got := completePartialJSON(tt.input); got != tt.want {
+			t.Errorf("completePartialJSON(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestStreamObject(t *testing.T) {
+	type Recipe struct {
+		Name     string   `json:"name"`
+		Servings int      `json:"servings"`
+		Steps    []string `json:"steps"`
+	}
+
+	provider := &streamingProvider{deltas: []string{
+		`{"name":"Pan`, `cakes","serv`, `ings":4,"steps":["Mix",`, `"Cook"]}`,
+	}}
+	partials, err := StreamObject(context.Background(), provider, Request{}, Recipe{})
+	if err != nil {
+		t.Fatalf("StreamObject failed: %v", err)
+	}
+	if !provider.req.Stream {
+		t.Error("expected request to be streamed")
+	}
+
+	var snapshots []Partial[Recipe]
+	for p := range partials {
+		snapshots = append(snapshots, p)
+	}
+
+	if len(snapshots) != 5 {
+		t.Fatalf("expected 5 snapshots, got %d: %+v", len(snapshots), snapshots)
+	}
+	if first := snapshots[0]; first.Value.Name != "Pan" || first.Complete {
+		t.Errorf("first snapshot = %+v, want partial name", first)
+	}
+	if second := snapshots[1]; second.Value.Name != "Pancakes" || second.Value.Servings != 0 {
+		t.Errorf("second snapshot = %+v", second)
+	}
+	final := snapshots[len(snapshots)-1]
+	if !final.Complete || final.Err != nil {
+		t.Fatalf("final snapshot = %+v, want complete", final)
+	}
+	if final.Value.Name != "Pancakes" || final.Value.Servings != 4 || len(final.Value.Steps) != 2 {
+		t.Errorf("final value = %+v", final.Value)
+	}
+}
+
+func TestStreamObjectErrors(t *testing.T) {
+	streamErr := errors.New("connection reset")
+	provider := &streamingProvider{deltas: []string{`{"name":"Pan`}, err: streamErr}
+	partials, err := StreamObject(context.Background(), provider, Request{}, map[string]any{})
+	if err != nil {
+		t.Fatalf("StreamObject failed: %v", err)
+	}
+
+	var last Partial[map[string]any]
+	for p := range partials {
+		last = p
+	}
+	if !errors.Is(last.Err, streamErr) || last.Complete {
+		t.Errorf("last snapshot = %+v, want stream error", last)
+	}
+
+	// A stream that ends mid-object is a validation error
+	provider = &streamingProvider{deltas: []string{`{"name":"Pan`}}
+	partials, _ = StreamObject(context.Background(), provider, Request{}, map[string]any{})
+	for p := range partials {
+		last = p
+	}
+	if !IsValidationError(last.Err) {
+		t.Errorf("last snapshot error = %v, want validation error", last.Err)
+	}
+}
+
+func TestObjectChunks(t *testing.T) {
+	stream := newChanStream(
+		Event{Type: EventTextDelta, TextDelta: `{"tags":["a",`},
+		Event{Type: EventTextDelta, TextDelta: `"b"]}`},
+		Event{Type: EventFinish},
+	)
+
+	var chunks []ObjectChunk
+	for chunk := range ObjectChunks(context.Background(), stream) {
+		chunks = append(chunks, chunk)
+	}
+
+	want := []string{`{"tags":["a"]}`, `{"tags":["a","b"]}`, `{"tags":["a","b"]}`}
+	if len(chunks) != len(want) {
+		t.Fatalf("expected %d chunks, got %+v", len(want), chunks)
+	}
+	for i, chunk := range chunks {
+		if string(chunk.JSON) != want[i] {
+			t.Errorf("chunk %d JSON = %s, want %s", i, chunk.JSON, want[i])
+		}
+	}
+	if last := chunks[len(chunks)-1]; !last.Complete || last.ParseError != nil {
+		t.Errorf("last chunk = %+v, want complete", last)
+	}
+	if !stream.closed {
+		t.Error("expected source stream to be closed")
+	}
+}
+
+func TestObjectAccumulate(t *testing.T) {
+	stream := newChanStream(
+		Event{Type: EventTextDelta, TextDelta: `{"ok":`},
+		Event{Type: EventTextDelta, TextDelta: `true}`},
+	)
+	data, err := ObjectAccumulate(ObjectChunks(context.Background(), stream))
+	if err != nil || string(data) != `{"ok":true}` {
+		t.Errorf("ObjectAccumulate = %s, %v", data, err)
+	}
+
+	streamErr := errors.New("connection reset")
+	stream = newChanStream(
+		Event{Type: EventTextDelta, TextDelta: `{"ok":`},
+		Event{Type: EventError, Err: streamErr},
+	)
+	if _, err := ObjectAccumulate(ObjectChunks(context.Background(), stream)); !errors.Is(err, streamErr) {
+		t.Errorf("ObjectAccumulate error = %v, want stream error", err)
+	}
+}
@@ -0,0 +1,191 @@
+// Package core provides pre-flight request inspection.
+// This file defines the dry-run interface providers implement and the
+// provider-independent checks they share.
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// ToolSummary describes a tool as it would be offered to the model.
+type ToolSummary struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+// InspectedRequest is the result of validating a request without sending it.
+type InspectedRequest struct {
+	// EstimatedInputTokens approximates the prompt size, including tool schemas
+	EstimatedInputTokens int `json:"estimated_input_tokens"`
+	// ValidationErrors lists problems that would make the provider reject the request
+	ValidationErrors []string `json:"validation_errors,omitempty"`
+	// ToolDefinitions summarizes the tools that would be sent
+	ToolDefinitions []ToolSummary `json:"tool_definitions,omitempty"`
+	// Warnings lists settings that would be ignored or adjusted
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Valid reports whether the inspection found no validation errors.
+func (r InspectedRequest) Valid() bool {
+	return len(r.ValidationErrors) == 0
+}
+
+// InspectableProvider is implemented by providers that can validate a request
+// and estimate its size before it is sent. All built-in providers implement it.
+//
+// Example:
+//
+//	if ip, ok := provider.(core.InspectableProvider); ok {
+//		inspected, err := ip.InspectRequest(ctx, req)
+//		if err == nil && !inspected.Valid() {
+//			return fmt.Errorf("invalid request: %v", inspected.ValidationErrors)
+//		}
+//	}
+type InspectableProvider interface {
+	// InspectRequest validates req without sending it. The error is reserved
+	// for failures to inspect; problems with the request are reported in
+	// InspectedRequest.ValidationErrors.
+	InspectRequest(ctx context.Context, req Request) (InspectedRequest, error)
+}
+
+// RequestConstraints describes what a provider or model accepts, for InspectRequest.
+type RequestConstraints struct {
+	// MaxTemperature is the highest accepted temperature (0 means no limit)
+	MaxTemperature float32
+	// MaxOutputTokens is the highest accepted MaxTokens (0 means unknown)
+	MaxOutputTokens int
+	// ContextWindow is the model's context size in tokens (0 means unknown)
+	ContextWindow int
+	// RequireAlternatingRoles requires user and assistant messages to alternate,
+	// starting with a user message; tool results count as user messages
+	RequireAlternatingRoles bool
+	// NoTools reports that the model cannot call tools
+	NoTools bool
+}
+
+// toolNamePattern matches the tool names accepted by the major provider APIs.
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// InspectRequest performs the provider-independent checks on req under the
+// given constraints: messages, parameter bounds, role order and tool
+// definitions. Providers call it from InspectRequest and add their own checks.
+func InspectRequest(req Request, constraints RequestConstraints) InspectedRequest {
+	req = ApplySystemPrompt(req)
+	var r InspectedRequest
+	fail := func(format string, args ...any) {
+		r.ValidationErrors = append(r.ValidationErrors, fmt.Sprintf(format, args...))
+	}
+	warn := func(format string, args ...any) {
+		r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	if len(req.Messages) == 0 {
+		fail("request has no messages")
+	}
+
+	// Parameter bounds
+	if req.Temperature < 0 {
+		fail("temperature %.2f is negative", req.Temperature)
+	} else if constraints.MaxTemperature > 0 && req.Temperature > constraints.MaxTemperature {
+		fail("temperature %.2f exceeds the maximum of %.1f", req.Temperature, constraints.MaxTemperature)
+	}
+	maxTokens := OutputTokenLimit(req)
+	if req.MaxOutputTokens < 0 {
+		fail("max output tokens %d is negative", req.MaxOutputTokens)
+	} else if req.MaxTokens < 0 {
+		fail("max tokens %d is negative", req.MaxTokens)
+	} else if constraints.MaxOutputTokens > 0 && maxTokens > constraints.MaxOutputTokens {
+		warn("max output tokens %d exceeds the model's output limit of %d", maxTokens, constraints.MaxOutputTokens)
+	}
+
+	if constraints.RequireAlternatingRoles {
+		checkRoleOrder(req.Messages, fail)
+	}
+
+	// Tool definitions
+	if len(req.Tools) > 0 && constraints.NoTools {
+		fail("model does not support tool calling")
+	}
+	seen := make(map[string]bool, len(req.Tools))
+	toolTokens := 0
+	for i, tool := range req.Tools {
+		if tool == nil {
+			fail("tool %d is nil", i)
+			continue
+		}
+		name := tool.Name()
+		if !toolNamePattern.MatchString(name) {
+			fail("tool %d has invalid name %q (use 1-64 letters, digits, underscores or hyphens)", i, name)
+		}
+		if seen[name] {
+			fail("tool %q is defined more than once", name)
+		}
+		seen[name] = true
+		if tool.Description() == "" {
+			warn("tool %q has no description", name)
+		}
+
+		schema := tool.InSchemaJSON()
+		var obj map[string]any
+		if err := json.Unmarshal(schema, &obj); err != nil {
+			fail("tool %q input schema is not a JSON object: %v", name, err)
+			schema = nil
+		}
+		toolTokens += (len(name) + len(tool.Description()) + len(schema) + 3) / 4
+		r.ToolDefinitions = append(r.ToolDefinitions, ToolSummary{
+			Name:        name,
+			Description: tool.Description(),
+			Parameters:  json.RawMessage(schema),
+		})
+	}
+
+	switch req.ToolChoice {
+	case ToolSpecific:
+		if !seen[req.SpecificTool] {
+			fail("tool choice names %q, which is not among the request's tools", req.SpecificTool)
+		}
+	case ToolRequired:
+		if len(req.Tools) == 0 {
+			fail("tool choice requires a tool call but the request has no tools")
+		}
+	}
+
+	r.EstimatedInputTokens = ApproxTokenCounter.CountTokens(req.Messages) + toolTokens
+	if constraints.ContextWindow > 0 {
+		if r.EstimatedInputTokens > constraints.ContextWindow {
+			fail("estimated %d input tokens exceed the context window of %d", r.EstimatedInputTokens, constraints.ContextWindow)
+		} else if r.EstimatedInputTokens+maxTokens > constraints.ContextWindow {
+			warn("estimated input plus max tokens exceeds the context window of %d", constraints.ContextWindow)
+		}
+	}
+
+	return r
+}
+
+// checkRoleOrder reports messages that break user/assistant alternation.
+// System messages are skipped, tool results count as user messages and
+// consecutive tool results for the same assistant turn are allowed.
+func checkRoleOrder(messages []Message, fail func(string, ...any)) {
+	var prev, prevRole Role
+	for i, msg := range messages {
+		role := msg.Role
+		switch role {
+		case System:
+			continue
+		case Tool:
+			role = User
+		}
+		switch {
+		case prev == "" && role != User:
+			fail("message %d: conversation must start with a user message, got %s", i, msg.Role)
+		case role == prev && !(msg.Role == Tool && prevRole == Tool):
+			fail("message %d: %s message follows another %s message; roles must alternate", i, msg.Role, prev)
+		}
+		prev, prevRole = role, msg.Role
+	}
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// schemaTool is a ToolHandle with a configurable name and input schema.
+type schemaTool struct {
+	name   string
+	schema string
+}
+
+func (s schemaTool) Name() string                  { return s.name }
+func (s schemaTool) Description() string           { return "test tool" }
+func (s schemaTool) InSchemaJSON() json.RawMessage { return json.RawMessage(s.schema) }
+func (s schemaTool) Exec(ctx context.Context, raw json.RawMessage, meta interface{}) (any, error) {
+	return nil, nil
+}
+
+func hasMessage(list []string, substr string) bool {
+	for _, msg := range list {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInspectRequestValid(t *testing.T) {
+	req := Request{
+		Messages: []Message{userMessage("What is the weather in Paris today?")},
+		Tools:    []ToolHandle{schemaTool{name: "get_weather", schema: `{"type":"object"}`}},
+	}
+
+	r := InspectRequest(req, RequestConstraints{MaxTemperature: 2})
+	if !r.Valid() {
+		t.Fatalf("Expected valid request, got %v", r.ValidationErrors)
+	}
+	if r.EstimatedInputTokens <= 0 {
+		t.Error("Expected a positive token estimate")
+	}
+	if len(r.ToolDefinitions) != 1 || r.ToolDefinitions[0].Name != "get_weather" {
+		t.Errorf("Unexpected tool definitions: %+v", r.ToolDefinitions)
+	}
+}
+
+func TestInspectRequestErrors(t *testing.T) {
+	req := Request{
+		Temperature: 1.5,
+		Tools: []ToolHandle{
+			schemaTool{name: "bad name", schema: `{"type":"object"}`},
+			schemaTool{name: "lookup", schema: `not json`},
+			schemaTool{name: "lookup", schema: `{"type":"object"}`},
+		},
+		ToolChoice:   ToolSpecific,
+		SpecificTool: "missing",
+	}
+
+	r := InspectRequest(req, RequestConstraints{MaxTemperature: 1})
+	for _, want := range []string{"no messages", "temperature", "invalid name", "not a JSON object", "more than once", "missing"} {
+		if !hasMessage(r.ValidationErrors, want) {
+			t.Errorf("Expected a validation error mentioning %q, got %v", want, r.ValidationErrors)
+		}
+	}
+}
+
+func TestInspectRequestRoleOrder(t *testing.T) {
+	assistant := Message{Role: Assistant, Parts: []Part{Text{Text: "ok"}}}
+	toolResult := Message{Role: Tool, Parts: []Part{Text{Text: "{}"}}}
+	system := Message{Role: System, Parts: []Part{Text{Text: "Be brief"}}}
+
+	valid := []Message{system, userMessage("hi"), assistant, toolResult, toolResult, assistant, userMessage("thanks")}
+	if r := InspectRequest(Request{Messages: valid}, RequestConstraints{RequireAlternatingRoles: true}); !r.Valid() {
+		t.Errorf("Expected alternating conversation to be valid, got %v", r.ValidationErrors)
+	}
+
+	invalid := []Message{assistant, userMessage("hi"), userMessage("again")}
+	r := InspectRequest(Request{Messages: invalid}, RequestConstraints{RequireAlternatingRoles: true})
+	if len(r.ValidationErrors) != 2 {
+		t.Errorf("Expected 2 role order errors, got %v", r.ValidationErrors)
+	}
+
+	if r := InspectRequest(Request{Messages: invalid}, RequestConstraints{}); !r.Valid() {
+		t.Errorf("Expected role order to be unchecked by default, got %v", r.ValidationErrors)
+	}
+}
+
+func TestInspectRequestLimits(t *testing.T) {
+	req := Request{
+		Messages:  []Message{userMessage(strings.Repeat("word ", 400))},
+		MaxTokens: 5000,
+		Tools:     []ToolHandle{schemaTool{name: "lookup", schema: `{"type":"object"}`}},
+	}
+
+	r := InspectRequest(req, RequestConstraints{MaxOutputTokens: 4096, ContextWindow: 100, NoTools: true})
+	if !hasMessage(r.Warnings, "output limit") {
+		t.Errorf("Expected output limit warning, got %v", r.Warnings)
+	}
+	if !hasMessage(r.ValidationErrors, "context window") || !hasMessage(r.ValidationErrors, "tool calling") {
+		t.Errorf("Expected context window and tool errors, got %v", r.ValidationErrors)
+	}
+}
@@ -0,0 +1,94 @@
+// Package core provides the request setup shared by providers.
+// This file implements PrepareRequest, the steps every provider entry point
+// runs before building its API request.
+
+package core
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestSetup describes how a provider prepares requests with
+// PrepareRequest.
+type RequestSetup struct {
+	// Provider is the provider name used to resolve model aliases and in
+	// errors, such as "openai"
+	Provider string
+	// Selector chooses the model for requests that ask for automatic
+	// selection. Nil leaves Request.Model as it is.
+	Selector ModelSelector
+	// Validator checks Request.Model overrides before any network call. Nil
+	// skips the check.
+	Validator ModelValidator
+	// MultipleCompletions is set by entry points that can return
+	// TextResult.Alternatives. Others reject Request.N > 1.
+	MultipleCompletions bool
+	// Client downloads images for Request.AutoFetchImages. Nil uses
+	// http.DefaultClient.
+	Client *http.Client
+}
+
+// PrepareRequest runs the setup every provider entry point needs before
+// building its API request, in order:
+//
+//   - the request metadata is attached to ctx (see WithMetadata) so
+//     middleware, tools and observability can read it
+//   - Request.SystemPrompt is moved into the messages (see ApplySystemPrompt)
+//   - model aliases are resolved (see ResolveModel) and, when the request
+//     asks for it, the model is chosen by setup.Selector (see SelectModel)
+//   - a Request.Model override is checked with setup.Validator
+//   - Request.N > 1 is rejected unless setup.MultipleCompletions is set
+//     (see CheckSingleCompletion)
+//   - image URLs are inlined when Request.AutoFetchImages is set (see
+//     FetchImages)
+//   - the request's retry policy is attached to ctx (see WithRetryPolicy)
+//
+// Images are fetched last so that invalid requests fail without any network
+// call. It returns the context and request to use for the rest of the call.
+//
+// Example:
+//
+//	ctx, req, err := core.PrepareRequest(ctx, req, core.RequestSetup{
+//		Provider: "openai",
+//		Selector: p.modelSelector,
+//		Client:   p.client,
+//	})
+//	if err != nil {
+//		return nil, err
+//	}
+func PrepareRequest(ctx context.Context, req Request, setup RequestSetup) (context.Context, Request, error) {
+	ctx = WithMetadata(ctx, req.Metadata)
+	req = ApplySystemPrompt(req)
+	req = PrepareModel(ctx, req, setup)
+	if setup.Validator != nil && req.Model != "" {
+		if err := setup.Validator.ValidateModel(req.Model); err != nil {
+			return ctx, req, err
+		}
+	}
+	if !setup.MultipleCompletions {
+		if err := CheckSingleCompletion(req, setup.Provider); err != nil {
+			return ctx, req, err
+		}
+	}
+	if req.AutoFetchImages {
+		fetched, err := FetchImages(ctx, req, setup.Client)
+		if err != nil {
+			return ctx, req, err
+		}
+		req = fetched
+	}
+	return WithRetryPolicy(ctx, req), req, nil
+}
+
+// PrepareModel runs the model steps of PrepareRequest on req: aliases are
+// resolved and, when the request asks for it, the model is chosen by
+// setup.Selector. It makes no network call and does not validate the model,
+// so providers use it to implement ModelResolver.
+func PrepareModel(ctx context.Context, req Request, setup RequestSetup) Request {
+	req.Model = ResolveModel(ctx, setup.Provider, req.Model)
+	if setup.Selector != nil {
+		req = SelectModel(req, setup.Selector)
+	}
+	return req
+}
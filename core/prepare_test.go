@@ -0,0 +1,95 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrepareRequest(t *testing.T) {
+	registry := NewModelRegistry()
+	registry.Register("fast", "openai", "gpt-4o-mini")
+	ctx := WithModelRegistry(context.Background(), registry)
+
+	req := Request{
+		Model:        "fast",
+		SystemPrompt: "Be brief.",
+		Messages:     []Message{{Role: User, Parts: []Part{Text{Text: "hi"}}}},
+		Metadata:     map[string]any{"tenant_id": "acme"},
+		MaxRetries:   2,
+	}
+	ctx, got, err := PrepareRequest(ctx, req, RequestSetup{Provider: "openai"})
+	if err != nil {
+		t.Fatalf("PrepareRequest failed: %v", err)
+	}
+	if got.Model != "gpt-4o-mini" {
+		t.Errorf("Model = %q, want the resolved alias", got.Model)
+	}
+	if got.SystemPrompt != "" || len(got.Messages) != 2 || got.Messages[0].Role != System {
+		t.Errorf("system prompt not applied: %+v", got)
+	}
+	if MetadataFromContext(ctx)["tenant_id"] != "acme" {
+		t.Error("metadata not attached to the context")
+	}
+	if policy, ok := RetryPolicyFromContext(ctx); !ok || policy.MaxRetries != 2 {
+		t.Errorf("retry policy = %+v, %v; want MaxRetries 2", policy, ok)
+	}
+
+	// Automatic selection uses the selector when one is set
+	selector := func(Request, ModelNeeds) string { return "chosen" }
+	_, got, _ = PrepareRequest(context.Background(), Request{Model: AutoModel}, RequestSetup{Provider: "openai", Selector: selector})
+	if got.Model != "chosen" {
+		t.Errorf("selected model = %q, want chosen", got.Model)
+	}
+	_, got, _ = PrepareRequest(context.Background(), Request{Model: AutoModel}, RequestSetup{Provider: "gemini"})
+	if got.Model != AutoModel {
+		t.Errorf("model without a selector = %q, want it unchanged", got.Model)
+	}
+
+	// Multiple completions are rejected unless the entry point supports them
+	_, _, err = PrepareRequest(context.Background(), Request{N: 2}, RequestSetup{Provider: "gemini"})
+	var aiErr *AIError
+	if !errors.As(err, &aiErr) || aiErr.Code != ErrorUnsupported {
+		t.Errorf("N=2 error = %v, want ErrorUnsupported", err)
+	}
+	if _, _, err := PrepareRequest(context.Background(), Request{N: 2}, RequestSetup{Provider: "openai", MultipleCompletions: true}); err != nil {
+		t.Errorf("N=2 with MultipleCompletions failed: %v", err)
+	}
+}
+
+// rejectModels is a ModelValidator that rejects every model.
+type rejectModels struct{}
+
+func (rejectModels) ValidateModel(model string) error {
+	return NewError(ErrorUnknownModel, "unknown model "+model)
+}
+
+func TestPrepareRequestValidatesBeforeFetching(t *testing.T) {
+	allowPrivateImageHosts(t)
+	fetches := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pngHeader)
+	}))
+	defer server.Close()
+
+	req := Request{
+		Model:           "nope",
+		Messages:        []Message{{Role: User, Parts: []Part{ImageURL{URL: server.URL + "/photo.png"}}}},
+		AutoFetchImages: true,
+	}
+	setup := RequestSetup{Provider: "openai", Validator: rejectModels{}, Client: server.Client()}
+	if _, _, err := PrepareRequest(context.Background(), req, setup); err == nil {
+		t.Error("expected the unknown model to be rejected")
+	}
+	req.Model, req.N = "", 2
+	if _, _, err := PrepareRequest(context.Background(), req, setup); err == nil {
+		t.Error("expected N=2 to be rejected")
+	}
+	if fetches != 0 {
+		t.Errorf("images fetched %d times for invalid requests", fetches)
+	}
+}
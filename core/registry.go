@@ -0,0 +1,104 @@
+// Package core provides model aliasing.
+// This file implements a registry that maps application-level model aliases
+// such as "fast" or "smart" to a provider and provider-specific model name.
+
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// modelRoute is the provider and model an alias resolves to
+type modelRoute struct {
+	provider string
+	model    string
+}
+
+// ModelRegistry maps model aliases to a provider and model name, so that
+// business logic can refer to "fast" or "smart" instead of hard-coded model
+// strings. Providers resolve Request.Model against the registry in the
+// context (see WithModelRegistry) and then DefaultModelRegistry before
+// dispatching. It is safe for concurrent use.
+type ModelRegistry struct {
+	mu     sync.RWMutex
+	routes map[string]modelRoute
+}
+
+// DefaultModelRegistry holds application-wide aliases. It is consulted when
+// the context carries no registry or the alias is not registered there.
+var DefaultModelRegistry = NewModelRegistry()
+
+// NewModelRegistry creates an empty model registry.
+func NewModelRegistry() *ModelRegistry {
+	return &ModelRegistry{routes: make(map[string]modelRoute)}
+}
+
+// Register maps alias to modelName on the named provider (e.g. "openai").
+// An empty providerName lets any provider resolve the alias. Registering an
+// existing alias replaces it.
+//
+// Example:
+//
+//	core.DefaultModelRegistry.Register("fast", "openai", "gpt-4o-mini")
+//	result, err := provider.GenerateText(ctx, core.Request{Model: "fast", ...})
+func (r *ModelRegistry) Register(alias, providerName, modelName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[alias] = modelRoute{provider: providerName, model: modelName}
+}
+
+// Unregister removes an alias from the registry.
+func (r *ModelRegistry) Unregister(alias string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.routes, alias)
+}
+
+// Resolve returns the provider and model registered for alias.
+func (r *ModelRegistry) Resolve(alias string) (providerName, modelName string, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	route, ok := r.routes[alias]
+	return route.provider, route.model, ok
+}
+
+// modelRegistryKey is the context key for the model registry
+type modelRegistryKey struct{}
+
+// WithModelRegistry returns a context carrying registry. Its aliases take
+// precedence over DefaultModelRegistry for requests made with the context.
+func WithModelRegistry(ctx context.Context, registry *ModelRegistry) context.Context {
+	return context.WithValue(ctx, modelRegistryKey{}, registry)
+}
+
+// ModelRegistryFromContext returns the registry carried by the context, or nil.
+func ModelRegistryFromContext(ctx context.Context) *ModelRegistry {
+	registry, _ := ctx.Value(modelRegistryKey{}).(*ModelRegistry)
+	return registry
+}
+
+// ResolveModel returns the model name that model resolves to for the named
+// provider, checking the context registry first and then DefaultModelRegistry.
+// Models that are not registered, or that are registered for a different
+// provider, are returned unchanged. Providers call this before dispatching.
+func ResolveModel(ctx context.Context, providerName, model string) string {
+	if model == "" {
+		return model
+	}
+
+	for _, registry := range []*ModelRegistry{ModelRegistryFromContext(ctx), DefaultModelRegistry} {
+		if registry == nil {
+			continue
+		}
+		provider, resolved, ok := registry.Resolve(model)
+		if !ok {
+			continue
+		}
+		if provider == "" || strings.EqualFold(provider, providerName) {
+			return resolved
+		}
+	}
+	return model
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"context"
+	"testing"
+)
+
+func TestModelRegistry(t *testing.T) {
+	r := NewModelRegistry()
+	if _, _, ok := r.Resolve("fast"); ok {
+		t.Error("Expected unregistered alias not to resolve")
+	}
+
+	r.Register("fast", "openai", "gpt-4o-mini")
+	provider, model, ok := r.Resolve("fast")
+	if !ok || provider != "openai" || model != "gpt-4o-mini" {
+		t.Errorf("Resolve(fast) = %q, %q, %v", provider, model, ok)
+	}
+
+	r.Register("fast", "groq", "llama-3.1-8b-instant")
+	if provider, _, _ := r.Resolve("fast"); provider != "groq" {
+		t.Errorf("Expected re-registration to replace alias, got provider %q", provider)
+	}
+
+	r.Unregister("fast")
+	if _, _, ok := r.Resolve("fast"); ok {
+		t.Error("Expected alias to be removed")
+	}
+}
+
+func TestResolveModel(t *testing.T) {
+	original := DefaultModelRegistry
+	defer func() { DefaultModelRegistry = original }()
+
+	DefaultModelRegistry = NewModelRegistry()
+	DefaultModelRegistry.Register("fast", "openai", "gpt-4o-mini")
+	DefaultModelRegistry.Register("smart", "", "any-smart-model")
+
+	ctx := context.Background()
+	tests := []struct {
+		provider string
+		model    string
+		want     string
+	}{
+		{"openai", "fast", "gpt-4o-mini"},
+		{"OpenAI", "fast", "gpt-4o-mini"},
+		{"anthropic", "fast", "fast"},
+		{"anthropic", "smart", "any-smart-model"},
+		{"openai", "gpt-4o", "gpt-4o"},
+		{"openai", "", ""},
+	}
+	for _, tt := range tests {
+		if got := ResolveModel(ctx, tt.provider, tt.model); got != tt.want {
+			t.Errorf("ResolveModel(%q, %q) = %q, want %q", tt.provider, tt.model, got, tt.want)
+		}
+	}
+
+	// The context registry takes precedence over the default
+	scoped := NewModelRegistry()
+	scoped.Register("fast", "openai", "gpt-4.1-nano")
+	ctx = WithModelRegistry(ctx, scoped)
+	if ModelRegistryFromContext(ctx) != scoped {
+		t.Error("Expected registry from context")
+	}
+	if got := ResolveModel(ctx, "openai", "fast"); got != "gpt-4.1-nano" {
+		t.Errorf("Expected context registry alias, got %q", got)
+	}
+
+	// Aliases missing from the context registry fall back to the default
+	if got := ResolveModel(ctx, "openai", "smart"); got != "any-smart-model" {
+		t.Errorf("Expected default registry fallback, got %q", got)
+	}
+}
@@ -0,0 +1,105 @@
+// Package core provides per-request retry configuration.
+// This file implements the policy built from Request.MaxRetries and
+// Request.RetryOn, which providers apply in their HTTP retry loops.
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// RetryPolicy is the per-request retry configuration taken from
+// Request.MaxRetries and Request.RetryOn.
+type RetryPolicy struct {
+	// MaxRetries overrides the provider's retry count when positive;
+	// a negative value disables retries
+	MaxRetries int
+	// RetryOn decides whether a failed attempt is retried (nil uses the
+	// default transient, rate limit and timeout classification)
+	RetryOn func(error) bool
+}
+
+// retryPolicyKey is the context key for the request retry policy
+type retryPolicyKey struct{}
+
+// RetryPolicyFromRequest returns the request's retry policy, if it sets
+// MaxRetries or RetryOn.
+func RetryPolicyFromRequest(req Request) (RetryPolicy, bool) {
+	if req.MaxRetries == 0 && req.RetryOn == nil {
+		return RetryPolicy{}, false
+	}
+	return RetryPolicy{MaxRetries: req.MaxRetries, RetryOn: req.RetryOn}, true
+}
+
+// WithRetryPolicy returns a context carrying the request's retry policy, or
+// ctx unchanged if the request has none. Providers call this at the start of
+// every request so their HTTP retry loops can apply it.
+func WithRetryPolicy(ctx context.Context, req Request) context.Context {
+	policy, ok := RetryPolicyFromRequest(req)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// RetryPolicyFromContext returns the retry policy carried by the context.
+func RetryPolicyFromContext(ctx context.Context) (RetryPolicy, bool) {
+	policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy)
+	return policy, ok
+}
+
+// Retries returns the number of retries to allow, falling back to
+// defaultRetries when the policy does not set a count.
+func (p RetryPolicy) Retries(defaultRetries int) int {
+	switch {
+	case p.MaxRetries > 0:
+		return p.MaxRetries
+	case p.MaxRetries < 0:
+		return 0
+	default:
+		return defaultRetries
+	}
+}
+
+// ShouldRetry reports whether a failed attempt should be retried. Errors
+// that are not AIErrors, such as transport failures, are presented to
+// RetryOn as temporary ErrorNetwork errors. Context cancellation is never retried.
+func (p RetryPolicy) ShouldRetry(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var aiErr *AIError
+	if !errors.As(err, &aiErr) {
+		err = NewError(ErrorNetwork, err.Error(), WithTemporary(true), WithWrapped(err))
+	}
+
+	if p.RetryOn != nil {
+		return p.RetryOn(err)
+	}
+	return IsTransient(err) || IsRateLimited(err) || IsTimeout(err)
+}
+
+// ShouldRetryResponse reports whether an HTTP response should be retried.
+// For error statuses the body is read to build the AIError passed to
+// ShouldRetry, which is returned as well. Responses that are not retried
+// keep a readable body.
+func (p RetryPolicy) ShouldRetryResponse(resp *http.Response, provider string) (bool, error) {
+	if resp.StatusCode < http.StatusBadRequest {
+		return false, nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	err := FromHTTPStatus(resp.StatusCode, string(body), provider)
+	if p.ShouldRetry(err) {
+		return true, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return false, err
+}
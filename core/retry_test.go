@@ -0,0 +1,92 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRetryPolicyFromRequest(t *testing.T) {
+	if _, ok := RetryPolicyFromRequest(Request{}); ok {
+		t.Error("Expected no policy for a request without retry settings")
+	}
+
+	ctx := WithRetryPolicy(context.Background(), Request{MaxRetries: 2})
+	policy, ok := RetryPolicyFromContext(ctx)
+	if !ok || policy.MaxRetries != 2 {
+		t.Errorf("RetryPolicyFromContext = %+v, %v", policy, ok)
+	}
+
+	if _, ok := RetryPolicyFromContext(WithRetryPolicy(context.Background(), Request{})); ok {
+		t.Error("Expected context without retry settings to carry no policy")
+	}
+}
+
+func TestRetryPolicyRetries(t *testing.T) {
+	tests := []struct {
+		maxRetries int
+		want       int
+	}{
+		{maxRetries: 0, want: 3},
+		{maxRetries: 5, want: 5},
+		{maxRetries: -1, want: 0},
+	}
+	for _, tt := range tests {
+		if got := (RetryPolicy{MaxRetries: tt.maxRetries}).Retries(3); got != tt.want {
+			t.Errorf("Retries(3) with MaxRetries=%d = %d, want %d", tt.maxRetries, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	var policy RetryPolicy
+	if !policy.ShouldRetry(FromHTTPStatus(http.StatusServiceUnavailable, "unavailable", "test")) {
+		t.Error("Expected 503 to be retried by default")
+	}
+	if policy.ShouldRetry(FromHTTPStatus(http.StatusBadRequest, "bad request", "test")) {
+		t.Error("Expected 400 not to be retried by default")
+	}
+	if !policy.ShouldRetry(errors.New("connection reset")) {
+		t.Error("Expected transport errors to be retried by default")
+	}
+	if policy.ShouldRetry(context.Canceled) {
+		t.Error("Expected context cancellation never to be retried")
+	}
+
+	var seen error
+	policy.RetryOn = func(err error) bool {
+		seen = err
+		return false
+	}
+	if policy.ShouldRetry(errors.New("connection reset")) {
+		t.Error("Expected RetryOn to override the default classification")
+	}
+	if !IsNetwork(seen) {
+		t.Errorf("Expected RetryOn to receive an ErrorNetwork error, got %v", seen)
+	}
+}
+
+func TestRetryPolicyShouldRetryResponse(t *testing.T) {
+	policy := RetryPolicy{RetryOn: func(err error) bool { return IsRateLimited(err) }}
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Body: io.NopCloser(strings.NewReader("slow down"))}
+	if retry, err := policy.ShouldRetryResponse(resp, "test"); !retry || !IsRateLimited(err) {
+		t.Errorf("ShouldRetryResponse(429) = %v, %v", retry, err)
+	}
+
+	resp = &http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(strings.NewReader("boom"))}
+	if retry, _ := policy.ShouldRetryResponse(resp, "test"); retry {
+		t.Error("Expected 500 not to be retried")
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != "boom" {
+		t.Errorf("Expected body to be readable after no retry, got %q", body)
+	}
+
+	resp = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}
+	if retry, err := policy.ShouldRetryResponse(resp, "test"); retry || err != nil {
+		t.Errorf("ShouldRetryResponse(200) = %v, %v", retry, err)
+	}
+}
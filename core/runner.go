@@ -6,8 +6,8 @@ package core
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -77,6 +77,9 @@ func NewRunner(provider Provider, opts ...RunnerOption) *Runner {
 func (r *Runner) ExecuteRequest(ctx context.Context, req Request) (*TextResult, error) {
 	startTime := time.Now()
 	
+	// Make request metadata available to tools executed by the runner
+	ctx = WithMetadata(ctx, req.Metadata)
+	
 	// If no tools or stop condition, delegate to single-shot provider
 	if len(req.Tools) == 0 || req.StopWhen == nil {
 		return r.provider.GenerateText(ctx, req)
@@ -122,6 +125,7 @@ func (r *Runner) ExecuteRequest(ctx context.Context, req Request) (*TextResult,
 			ToolCalls:  toolCalls,
 			StepNumber: stepNum,
 			Timestamp:  time.Now(),
+			Usage:      result.Usage,
 		}
 		
 		// If there are tool calls, execute them
@@ -138,11 +142,12 @@ func (r *Runner) ExecuteRequest(ctx context.Context, req Request) (*TextResult,
 				Parts: []Part{
 					Text{Text: result.Text},
 				},
+				ToolCalls: toolCalls,
 			})
 			
 			// Append tool results as messages
 			for _, result := range toolResults {
-				messages = append(messages, r.toolResultToMessage(result))
+				messages = append(messages, toolResultToMessage(result))
 			}
 		} else {
 			// No tools called, append assistant response
@@ -192,8 +197,9 @@ func (r *Runner) ExecuteRequest(ctx context.Context, req Request) (*TextResult,
 	
 	// Calculate total usage
 	totalUsage := Usage{}
-	// Note: Individual step usage would need to be tracked by provider
-	// This is a simplified aggregation for Phase 1
+	for _, step := range steps {
+		totalUsage.Add(step.Usage)
+	}
 	
 	return &TextResult{
 		Text:  finalText,
@@ -221,6 +227,30 @@ func (r *Runner) executeTools(ctx context.Context, tools []ToolHandle, calls []T
 	
 	results := make([]ToolExecution, len(calls))
 	
+	// Coalesce repeated calls to batch-capable tools into one execution each
+	batchCtx := ctx
+	if r.toolTimeout > 0 {
+		var cancel context.CancelFunc
+		batchCtx, cancel = context.WithTimeout(ctx, r.toolTimeout)
+		defer cancel()
+	}
+	batchStart := time.Now()
+	batched := ExecuteBatchedCalls(batchCtx, tools, calls, map[string]interface{}{
+		"messages":    messages,
+		"step_number": len(messages),
+	})
+	batchDuration := time.Since(batchStart)
+	for idx, b := range batched {
+		results[idx] = ToolExecution{ID: calls[idx].ID, Name: calls[idx].Name, Result: b.Result}
+		if b.Err != nil {
+			results[idx].Result = nil
+			results[idx].Error = fmt.Errorf("tool execution failed: %w", b.Err).Error()
+		}
+		if r.metrics != nil {
+			r.metrics.RecordToolExecution(calls[idx].Name, batchDuration, b.Err)
+		}
+	}
+	
 	// Use a semaphore to limit parallelism
 	sem := make(chan struct{}, r.maxParallel)
 	
@@ -233,10 +263,26 @@ func (r *Runner) executeTools(ctx context.Context, tools []ToolHandle, calls []T
 	// Error channel for collecting errors
 	errChan := make(chan error, len(calls))
 	
+	// Each tool learns its position among the calls issued in this step;
+	// with a parallelism of one they run sequentially
+	parallelCount := len(calls) - len(batched)
+	if r.maxParallel <= 1 {
+		parallelCount = 1
+	}
+	parallelIndex := 0
+	
 	for i, call := range calls {
+		if _, ok := batched[i]; ok {
+			continue
+		}
 		wg.Add(1)
+		index := 0
+		if parallelCount > 1 {
+			index = parallelIndex
+			parallelIndex++
+		}
 		
-		go func(idx int, tc ToolCall) {
+		go func(idx int, tc ToolCall, parallelIdx int) {
 			defer wg.Done()
 			
 			// Check if we should exit early
@@ -275,7 +321,7 @@ func (r *Runner) executeTools(ctx context.Context, tools []ToolHandle, calls []T
 			
 			// Execute the tool
 			startTime := time.Now()
-			result, err := r.executeTool(toolCtx, tool, tc, messages)
+			result, err := r.executeTool(toolCtx, tool, tc, messages, parallelIdx, parallelCount)
 			duration := time.Since(startTime)
 			
 			// Record metrics
@@ -296,7 +342,7 @@ func (r *Runner) executeTools(ctx context.Context, tools []ToolHandle, calls []T
 					Result: result,
 				}
 			}
-		}(i, call)
+		}(i, call, index)
 	}
 	
 	// Wait for all tools to complete
@@ -324,7 +370,9 @@ func (r *Runner) findTool(tools []ToolHandle, name string) ToolHandle {
 }
 
 // executeTool executes a single tool with proper error recovery.
-func (r *Runner) executeTool(ctx context.Context, tool ToolHandle, call ToolCall, messages []Message) (result any, err error) {
+// parallelIndex and parallelCount place the call among the calls issued in
+// its step.
+func (r *Runner) executeTool(ctx context.Context, tool ToolHandle, call ToolCall, messages []Message, parallelIndex, parallelCount int) (result any, err error) {
 	// Defer panic recovery
 	defer func() {
 		if r := recover(); r != nil {
@@ -336,9 +384,11 @@ func (r *Runner) executeTool(ctx context.Context, tool ToolHandle, call ToolCall
 	// The meta type is defined in the tools package, but we pass it as interface{}
 	// to avoid circular dependencies
 	meta := map[string]interface{}{
-		"call_id":     call.ID,
-		"messages":    messages,
-		"step_number": len(messages), // Approximate step number based on message count
+		"call_id":             call.ID,
+		"messages":            messages,
+		"step_number":         len(messages), // Approximate step number based on message count
+		"parallel_call_index": parallelIndex,
+		"parallel_call_count": parallelCount,
 	}
 	
 	// Execute the tool using its Exec method
@@ -351,27 +401,16 @@ func (r *Runner) executeTool(ctx context.Context, tool ToolHandle, call ToolCall
 }
 
 // toolResultToMessage converts a tool execution result to a message.
-func (r *Runner) toolResultToMessage(result ToolExecution) Message {
-	// Serialize the result
-	var content string
-	if result.Error != "" {
-		content = fmt.Sprintf("Error executing %s: %s", result.Name, result.Error)
-	} else {
-		// Marshal result to JSON for inclusion in message
-		data, err := json.Marshal(result.Result)
-		if err != nil {
-			content = fmt.Sprintf("Error serializing result for %s: %v", result.Name, err)
-		} else {
-			content = string(data)
-		}
-	}
-	
+func toolResultToMessage(result ToolExecution) Message {
+	// Serialize the result, wrapping errors as {"error": "..."}
 	return Message{
 		Role: Tool,
 		Parts: []Part{
-			Text{Text: content},
+			Text{Text: result.ToolResult().Content()},
 		},
-		Name: result.Name,
+		Name:       result.Name,
+		ToolCallID: result.ID,
+		IsError:    result.Error != "",
 	}
 }
 
@@ -429,6 +468,7 @@ func (r *Runner) createMultiStepStream(ctx context.Context, req Request) (TextSt
 		copy(messages, req.Messages)
 		
 		stepNum := 0
+		var totalUsage Usage
 		providerReq := req
 		providerReq.StopWhen = nil
 		
@@ -459,17 +499,25 @@ func (r *Runner) createMultiStepStream(ctx context.Context, req Request) (TextSt
 			}
 			
 			// Collect events and build step
-			var stepText string
+			var stepText strings.Builder
+			var stepUsage *Usage
 			var toolCalls []ToolCall
 			
 			for event := range providerStream.Events() {
+				// The provider's finish only ends this step; its usage is
+				// reported on EventFinishStep so consumers keep reading
+				if event.Type == EventFinish {
+					stepUsage = event.Usage
+					continue
+				}
+				
 				// Forward most events
 				stream.events <- event
 				
 				// Collect data for step
 				switch event.Type {
 				case EventTextDelta:
-					stepText += event.TextDelta
+					stepText.WriteString(event.TextDelta)
 				case EventToolCall:
 					toolCalls = append(toolCalls, ToolCall{
 						ID:    event.ToolID,
@@ -485,11 +533,15 @@ func (r *Runner) createMultiStepStream(ctx context.Context, req Request) (TextSt
 			
 			// Create step
 			step := Step{
-				Text:       stepText,
+				Text:       stepText.String(),
 				ToolCalls:  toolCalls,
 				StepNumber: stepNum,
 				Timestamp:  time.Now(),
 			}
+			if stepUsage != nil {
+				step.Usage = *stepUsage
+				totalUsage.Add(*stepUsage)
+			}
 			
 			// Execute tools if any
 			if len(toolCalls) > 0 {
@@ -509,6 +561,7 @@ func (r *Runner) createMultiStepStream(ctx context.Context, req Request) (TextSt
 				for _, result := range toolResults {
 					stream.events <- Event{
 						Type:       EventToolResult,
+						ToolID:     result.ID,
 						ToolName:   result.Name,
 						ToolResult: result.Result,
 						Timestamp:  time.Now(),
@@ -517,18 +570,19 @@ func (r *Runner) createMultiStepStream(ctx context.Context, req Request) (TextSt
 				
 				// Update messages
 				messages = append(messages, Message{
-					Role:  Assistant,
-					Parts: []Part{Text{Text: stepText}},
+					Role:      Assistant,
+					Parts:     []Part{Text{Text: step.Text}},
+					ToolCalls: toolCalls,
 				})
 				
 				for _, result := range toolResults {
-					messages = append(messages, r.toolResultToMessage(result))
+					messages = append(messages, toolResultToMessage(result))
 				}
 			} else {
-				if stepText != "" {
+				if step.Text != "" {
 					messages = append(messages, Message{
 						Role:  Assistant,
-						Parts: []Part{Text{Text: stepText}},
+						Parts: []Part{Text{Text: step.Text}},
 					})
 				}
 			}
@@ -537,6 +591,7 @@ func (r *Runner) createMultiStepStream(ctx context.Context, req Request) (TextSt
 			stream.events <- Event{
 				Type:       EventFinishStep,
 				StepNumber: stepNum,
+				Usage:      stepUsage,
 				Timestamp:  time.Now(),
 			}
 			
@@ -561,9 +616,10 @@ func (r *Runner) createMultiStepStream(ctx context.Context, req Request) (TextSt
 			}
 		}
 		
-		// Send finish event
+		// Send finish event with the usage summed over all steps
 		stream.events <- Event{
 			Type:      EventFinish,
+			Usage:     &totalUsage,
 			Timestamp: time.Now(),
 		}
 	}()
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// scriptedProvider streams one scripted event sequence per call.
+type scriptedProvider struct {
+	echoProvider
+	steps [][]Event
+}
+
+func (p *scriptedProvider) StreamText(ctx context.Context, req Request) (TextStream, error) {
+	p.requests = append(p.requests, req)
+	events := make(chan Event, len(p.steps[0]))
+	for _, e := range p.steps[0] {
+		events <- e
+	}
+	close(events)
+	p.steps = p.steps[1:]
+	return &sliceStream{events: events}, nil
+}
+
+func TestStreamExecuteRequestStepText(t *testing.T) {
+	provider := &scriptedProvider{steps: [][]Event{
+		{
+			{Type: EventStart},
+			{Type: EventTextDelta, TextDelta: "Let me "},
+			{Type: EventTextDelta, TextDelta: "check."},
+			{Type: EventToolCall, ToolID: "call_1", ToolName: "lookup", ToolInput: json.RawMessage(`{"q":"x"}`)},
+			{Type: EventFinish, Usage: &Usage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15}},
+		},
+		{
+			{Type: EventStart},
+			{Type: EventTextDelta, TextDelta: "Done."},
+			{Type: EventFinish, Usage: &Usage{InputTokens: 20, OutputTokens: 2, TotalTokens: 22}},
+		},
+	}}
+	tool := &batchTool{name: "lookup", results: echoResults}
+
+	stream, err := NewRunner(provider).StreamExecuteRequest(context.Background(), Request{
+		Messages: []Message{userMessage("Look up x")},
+		Tools:    []ToolHandle{tool},
+		StopWhen: NoMoreTools(),
+	})
+	if err != nil {
+		t.Fatalf("StreamExecuteRequest failed: %v", err)
+	}
+	defer stream.Close()
+
+	var (
+		text     string
+		steps    []string
+		usages   []*Usage
+		total    *Usage
+		finishes int
+		resultID string
+	)
+	for event := range stream.Events() {
+		if finishes > 0 {
+			t.Fatalf("event %v after EventFinish", event.Type)
+		}
+		switch event.Type {
+		case EventTextDelta:
+			text += event.TextDelta
+		case EventToolResult:
+			resultID = event.ToolID
+		case EventFinishStep:
+			steps = append(steps, text)
+			usages = append(usages, event.Usage)
+			text = ""
+		case EventFinish:
+			total = event.Usage
+			finishes++
+		case EventError:
+			t.Fatalf("stream error: %v", event.Err)
+		}
+	}
+
+	if finishes != 1 {
+		t.Errorf("expected 1 EventFinish, got %d", finishes)
+	}
+	if len(steps) != 2 || steps[0] != "Let me check." || steps[1] != "Done." {
+		t.Errorf("unexpected step text: %q", steps)
+	}
+	if len(usages) != 2 || usages[0] == nil || usages[0].TotalTokens != 15 || usages[1] == nil || usages[1].TotalTokens != 22 {
+		t.Errorf("expected provider usage on each EventFinishStep, got %v", usages)
+	}
+	if total == nil || total.InputTokens != 30 || total.OutputTokens != 7 || total.TotalTokens != 37 {
+		t.Errorf("expected EventFinish to carry the summed step usage, got %v", total)
+	}
+	if resultID != "call_1" {
+		t.Errorf("expected tool result ID call_1, got %q", resultID)
+	}
+}
+
+// metaTool returns the parallel call position from its meta.
+type metaTool struct{ schemaTool }
+
+func (m metaTool) Exec(ctx context.Context, raw json.RawMessage, meta interface{}) (any, error) {
+	mm := meta.(map[string]interface{})
+	return [2]int{mm["parallel_call_index"].(int), mm["parallel_call_count"].(int)}, nil
+}
+
+func TestExecuteToolsParallelMeta(t *testing.T) {
+	tool := metaTool{schemaTool{name: "probe", schema: `{"type":"object"}`}}
+	calls := []ToolCall{
+		{ID: "a", Name: "probe", Input: json.RawMessage(`{}`)},
+		{ID: "b", Name: "probe", Input: json.RawMessage(`{}`)},
+		{ID: "c", Name: "probe", Input: json.RawMessage(`{}`)},
+	}
+
+	results, err := NewRunner(&echoProvider{}).executeTools(context.Background(), []ToolHandle{tool}, calls, nil)
+	if err != nil {
+		t.Fatalf("executeTools failed: %v", err)
+	}
+	for i, r := range results {
+		if got, want := r.Result, [2]int{i, 3}; got != want {
+			t.Errorf("call %d: got (index, count) %v, want %v", i, got, want)
+		}
+	}
+
+	// With a parallelism of one, calls run sequentially
+	results, err = NewRunner(&echoProvider{}, WithMaxParallel(1)).executeTools(context.Background(), []ToolHandle{tool}, calls, nil)
+	if err != nil {
+		t.Fatalf("executeTools failed: %v", err)
+	}
+	for i, r := range results {
+		if got, want := r.Result, [2]int{0, 1}; got != want {
+			t.Errorf("sequential call %d: got (index, count) %v, want %v", i, got, want)
+		}
+	}
+}
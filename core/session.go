@@ -0,0 +1,277 @@
+// Package core provides conversation session persistence.
+// This file implements a Provider wrapper that loads conversation history
+// from a SessionStore before each request and saves the new exchange after.
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// SessionStore persists conversation history by session ID.
+// Implementations must be safe for concurrent use.
+type SessionStore interface {
+	// Save replaces the stored history for the session
+	Save(ctx context.Context, id string, messages []Message) error
+	// Load returns the stored history for the session, or nil if there is none
+	Load(ctx context.Context, id string) ([]Message, error)
+}
+
+// sessionProvider wraps a Provider with session persistence.
+type sessionProvider struct {
+	provider  Provider
+	store     SessionStore
+	sessionID string
+	mu        sync.Mutex
+}
+
+// WithSession wraps a provider so that every request continues the
+// conversation stored under sessionID. The stored history is prepended to
+// the request messages, and after a successful response the new messages,
+// the assistant response and any tool calls and results are appended to the
+// session. The session ID is also added to the request metadata.
+//
+// Requests through the same wrapper are serialized so that concurrent calls
+// don't lose each other's exchanges; a stream holds the session until it
+// finishes or is closed.
+//
+// Example:
+//
+//	chat := core.WithSession(provider, memory.InMemorySessionStore(), "user-123")
+//	result, err := chat.GenerateText(ctx, core.Request{
+//	    Messages: []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hi"}}}},
+//	})
+func WithSession(provider Provider, store SessionStore, sessionID string) Provider {
+	return &sessionProvider{
+		provider:  provider,
+		store:     store,
+		sessionID: sessionID,
+	}
+}
+
+// prepare loads the session history and returns the request to send.
+func (s *sessionProvider) prepare(ctx context.Context, req Request) (Request, []Message, error) {
+	history, err := s.store.Load(ctx, s.sessionID)
+	if err != nil {
+		return req, nil, fmt.Errorf("loading session %s: %w", s.sessionID, err)
+	}
+
+	messages := make([]Message, 0, len(history)+len(req.Messages))
+	messages = append(messages, history...)
+	messages = append(messages, req.Messages...)
+	req.Messages = messages
+
+	if _, ok := req.Metadata[MetadataSessionID]; !ok {
+		metadata := make(map[string]any, len(req.Metadata)+1)
+		for k, v := range req.Metadata {
+			metadata[k] = v
+		}
+		metadata[MetadataSessionID] = s.sessionID
+		req.Metadata = metadata
+	}
+
+	return req, history, nil
+}
+
+// save appends the exchange to the session history.
+func (s *sessionProvider) save(ctx context.Context, history, request, response []Message) error {
+	messages := make([]Message, 0, len(history)+len(request)+len(response))
+	messages = append(messages, history...)
+	messages = append(messages, request...)
+	messages = append(messages, response...)
+
+	if err := s.store.Save(ctx, s.sessionID, messages); err != nil {
+		return fmt.Errorf("saving session %s: %w", s.sessionID, err)
+	}
+	return nil
+}
+
+// GenerateText implements Provider with session persistence.
+func (s *sessionProvider) GenerateText(ctx context.Context, req Request) (*TextResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessionReq, history, err := s.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.provider.GenerateText(ctx, sessionReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.save(ctx, history, req.Messages, resultMessages(result)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamText implements Provider with session persistence. The exchange is
+// saved when the stream finishes successfully.
+func (s *sessionProvider) StreamText(ctx context.Context, req Request) (TextStream, error) {
+	s.mu.Lock()
+
+	sessionReq, history, err := s.prepare(ctx, req)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	stream, err := s.provider.StreamText(ctx, sessionReq)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	ss := &sessionStream{
+		stream: stream,
+		events: make(chan Event, 100),
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer s.mu.Unlock()
+		defer close(ss.events)
+
+		response, finish := ss.forward()
+		if finish == nil {
+			return
+		}
+		if err := s.save(context.WithoutCancel(ctx), history, req.Messages, response); err != nil {
+			ss.send(Event{Type: EventError, Err: err})
+			return
+		}
+		ss.send(*finish)
+	}()
+
+	return ss, nil
+}
+
+// GenerateObject implements Provider with session persistence. The object is
+// stored in the session as the assistant's JSON response.
+func (s *sessionProvider) GenerateObject(ctx context.Context, req Request, schema any) (*ObjectResult[any], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessionReq, history, err := s.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.provider.GenerateObject(ctx, sessionReq, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := json.Marshal(result.Value)
+	if err != nil {
+		return nil, fmt.Errorf("encoding object for session: %w", err)
+	}
+	response := []Message{{Role: Assistant, Parts: []Part{Text{Text: string(output)}}}}
+	if err := s.save(ctx, history, req.Messages, response); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamObject implements Provider with the session history prepended.
+// Streamed objects are not saved to the session.
+func (s *sessionProvider) StreamObject(ctx context.Context, req Request, schema any) (ObjectStream[any], error) {
+	sessionReq, _, err := s.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.provider.StreamObject(ctx, sessionReq, schema)
+}
+
+// resultMessages converts a TextResult into the messages to store: one
+// assistant message per step, each followed by its tool results.
+func resultMessages(result *TextResult) []Message {
+	if len(result.Steps) == 0 {
+		return []Message{{Role: Assistant, Parts: []Part{Text{Text: result.Text}}}}
+	}
+
+	var messages []Message
+	for _, step := range result.Steps {
+		messages = append(messages, stepMessages(step)...)
+	}
+
+	// Include the final answer if the steps don't already end with it
+	last := result.Steps[len(result.Steps)-1]
+	if result.Text != "" && (last.Text != result.Text || len(last.ToolResults) > 0) {
+		messages = append(messages, Message{Role: Assistant, Parts: []Part{Text{Text: result.Text}}})
+	}
+	return messages
+}
+
+// stepMessages converts a step into an assistant message holding its text
+// and tool calls, followed by one tool message per result.
+func stepMessages(step Step) []Message {
+	if step.Text == "" && len(step.ToolCalls) == 0 {
+		return nil
+	}
+
+	assistant := Message{Role: Assistant, ToolCalls: step.ToolCalls}
+	if step.Text != "" {
+		assistant.Parts = []Part{Text{Text: step.Text}}
+	}
+	messages := []Message{assistant}
+	for _, result := range step.ToolResults {
+		messages = append(messages, toolResultToMessage(result))
+	}
+	return messages
+}
+
+// sessionStream forwards events from the wrapped stream while recording the
+// assistant response.
+type sessionStream struct {
+	stream    TextStream
+	events    chan Event
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Events implements TextStream.
+func (s *sessionStream) Events() <-chan Event {
+	return s.events
+}
+
+// Close implements TextStream.
+func (s *sessionStream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	return s.stream.Close()
+}
+
+// forward relays events and returns the response messages together with the
+// finish event, which is held back until the session has been saved. finish
+// is nil if the stream failed or was closed before finishing.
+func (s *sessionStream) forward() (response []Message, finish *Event) {
+	var steps StepAccumulator
+	for event := range s.stream.Events() {
+		steps.Add(event)
+		if event.Type == EventFinish {
+			return resultMessages(steps.Result()), &event
+		}
+
+		if !s.send(event) || event.Type == EventError {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// send delivers an event unless the stream has been closed.
+func (s *sessionStream) send(event Event) bool {
+	select {
+	case s.events <- event:
+		return true
+	case <-s.done:
+		return false
+	}
+}
@@ -0,0 +1,274 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// mapSessionStore is a minimal SessionStore for tests.
+type mapSessionStore struct {
+	mu       sync.Mutex
+	sessions map[string][]Message
+}
+
+func (s *mapSessionStore) Save(ctx context.Context, id string, messages []Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = make(map[string][]Message)
+	}
+	s.sessions[id] = messages
+	return nil
+}
+
+func (s *mapSessionStore) Load(ctx context.Context, id string) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[id], nil
+}
+
+// echoProvider replies with a fixed result and records requests.
+type echoProvider struct {
+	result   *TextResult
+	events   []Event
+	requests []Request
+}
+
+func (p *echoProvider) GenerateText(ctx context.Context, req Request) (*TextResult, error) {
+	p.requests = append(p.requests, req)
+	return p.result, nil
+}
+
+func (p *echoProvider) StreamText(ctx context.Context, req Request) (TextStream, error) {
+	p.requests = append(p.requests, req)
+	events := make(chan Event, len(p.events))
+	for _, e := range p.events {
+		events <- e
+	}
+	close(events)
+	return &sliceStream{events: events}, nil
+}
+
+func (p *echoProvider) GenerateObject(ctx context.Context, req Request, schema any) (*ObjectResult[any], error) {
+	p.requests = append(p.requests, req)
+	return &ObjectResult[any]{Value: map[string]any{"ok": true}}, nil
+}
+
+func (p *echoProvider) StreamObject(ctx context.Context, req Request, schema any) (ObjectStream[any], error) {
+	return nil, errors.New("not implemented")
+}
+
+type sliceStream struct {
+	events chan Event
+}
+
+func (s *sliceStream) Events() <-chan Event { return s.events }
+func (s *sliceStream) Close() error         { return nil }
+
+func userMessage(text string) Message {
+	return Message{Role: User, Parts: []Part{Text{Text: text}}}
+}
+
+func TestWithSessionGenerateText(t *testing.T) {
+	store := &mapSessionStore{}
+	provider := &echoProvider{result: &TextResult{Text: "Hi Ada"}}
+	chat := WithSession(provider, store, "s1")
+
+	if _, err := chat.GenerateText(context.Background(), Request{Messages: []Message{userMessage("I'm Ada")}}); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if _, err := chat.GenerateText(context.Background(), Request{Messages: []Message{userMessage("Who am I?")}}); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	// The second request should include the first exchange
+	second := provider.requests[1]
+	if len(second.Messages) != 3 {
+		t.Fatalf("Expected history plus new message, got %d messages", len(second.Messages))
+	}
+	if second.Messages[1].Role != Assistant || second.Messages[1].Parts[0].(Text).Text != "Hi Ada" {
+		t.Errorf("Expected assistant reply in history, got %+v", second.Messages[1])
+	}
+	if second.Metadata[MetadataSessionID] != "s1" {
+		t.Errorf("Expected session ID in metadata, got %v", second.Metadata)
+	}
+
+	if stored := store.sessions["s1"]; len(stored) != 4 {
+		t.Errorf("Expected 4 stored messages, got %d", len(stored))
+	}
+}
+
+func TestWithSessionToolSteps(t *testing.T) {
+	store := &mapSessionStore{}
+	provider := &echoProvider{result: &TextResult{
+		Text: "It's sunny",
+		Steps: []Step{
+			{
+				ToolCalls:   []ToolCall{{ID: "1", Name: "weather", Input: json.RawMessage(`{"city":"Paris"}`)}},
+				ToolResults: []ToolExecution{{ID: "1", Name: "weather", Result: map[string]any{"sky": "sunny"}}},
+			},
+			{Text: "It's sunny"},
+		},
+	}}
+	chat := WithSession(provider, store, "s1")
+
+	if _, err := chat.GenerateText(context.Background(), Request{Messages: []Message{userMessage("Weather?")}}); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	stored := store.sessions["s1"]
+	roles := []Role{User, Assistant, Tool, Assistant}
+	if len(stored) != len(roles) {
+		t.Fatalf("Expected %d stored messages, got %d", len(roles), len(stored))
+	}
+	for i, role := range roles {
+		if stored[i].Role != role {
+			t.Errorf("Message %d: expected role %s, got %s", i, role, stored[i].Role)
+		}
+	}
+	if calls := stored[1].ToolCalls; len(calls) != 1 || calls[0].ID != "1" || string(calls[0].Input) != `{"city":"Paris"}` {
+		t.Errorf("Expected the assistant message to keep its tool call, got %+v", stored[1])
+	}
+	if stored[2].Name != "weather" || stored[2].ToolCallID != "1" || stored[2].Parts[0].(Text).Text != `{"sky":"sunny"}` {
+		t.Errorf("Unexpected tool message: %+v", stored[2])
+	}
+}
+
+func TestWithSessionToolError(t *testing.T) {
+	store := &mapSessionStore{}
+	provider := &echoProvider{result: &TextResult{
+		Text: "The lookup failed",
+		Steps: []Step{
+			{
+				ToolCalls:   []ToolCall{{ID: "1", Name: "lookup", Input: json.RawMessage(`{}`)}},
+				ToolResults: []ToolExecution{{ID: "1", Name: "lookup", Error: "service unavailable"}},
+			},
+			{Text: "The lookup failed"},
+		},
+	}}
+	chat := WithSession(provider, store, "s1")
+
+	if _, err := chat.GenerateText(context.Background(), Request{Messages: []Message{userMessage("Look up")}}); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	// Stored failures read the same as the runner's tool messages
+	stored := store.sessions["s1"]
+	if len(stored) != 4 {
+		t.Fatalf("Expected 4 stored messages, got %d", len(stored))
+	}
+	if !stored[2].IsError || stored[2].Parts[0].(Text).Text != `{"error":"service unavailable"}` {
+		t.Errorf("Unexpected tool message: %+v", stored[2])
+	}
+}
+
+func TestWithSessionStreamText(t *testing.T) {
+	store := &mapSessionStore{}
+	provider := &echoProvider{events: []Event{
+		{Type: EventStart},
+		{Type: EventTextDelta, TextDelta: "Hello "},
+		{Type: EventTextDelta, TextDelta: "there"},
+		{Type: EventFinish},
+	}}
+	chat := WithSession(provider, store, "s1")
+
+	stream, err := chat.StreamText(context.Background(), Request{Messages: []Message{userMessage("Hi")}})
+	if err != nil {
+		t.Fatalf("StreamText failed: %v", err)
+	}
+	var types []EventType
+	for event := range stream.Events() {
+		types = append(types, event.Type)
+	}
+	stream.Close()
+
+	if len(types) != 4 || types[3] != EventFinish {
+		t.Errorf("Expected all events with finish last, got %v", types)
+	}
+
+	// The session is saved before the finish event is delivered
+	stored := store.sessions["s1"]
+	if len(stored) != 2 || stored[1].Parts[0].(Text).Text != "Hello there" {
+		t.Errorf("Unexpected stored session: %+v", stored)
+	}
+}
+
+func TestWithSessionGenerateObject(t *testing.T) {
+	store := &mapSessionStore{}
+	chat := WithSession(&echoProvider{}, store, "s1")
+
+	if _, err := chat.GenerateObject(context.Background(), Request{Messages: []Message{userMessage("Check")}}, nil); err != nil {
+		t.Fatalf("GenerateObject failed: %v", err)
+	}
+
+	stored := store.sessions["s1"]
+	if len(stored) != 2 || stored[1].Parts[0].(Text).Text != `{"ok":true}` {
+		t.Errorf("Unexpected stored session: %+v", stored)
+	}
+}
+
+func TestMessageJSONRoundTrip(t *testing.T) {
+	original := Message{
+		Role: User,
+		Name: "ada",
+		Parts: []Part{
+			Text{Text: "Look at this"},
+			ImageURL{URL: "https://example.com/cat.png", Detail: "high"},
+			Audio{Source: BlobRef{Kind: BlobBytes, Bytes: []byte("abc"), MIME: "audio/wav"}},
+			File{Source: BlobRef{Kind: BlobProviderFile, FileID: "file-1"}, Name: "doc.pdf"},
+		},
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded Message
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if decoded.Role != original.Role || decoded.Name != original.Name || len(decoded.Parts) != len(original.Parts) {
+		t.Fatalf("Decoded message differs: %+v", decoded)
+	}
+	if img, ok := decoded.Parts[1].(ImageURL); !ok || img.Detail != "high" {
+		t.Errorf("Expected ImageURL part, got %#v", decoded.Parts[1])
+	}
+	if audio, ok := decoded.Parts[2].(Audio); !ok || string(audio.Source.Bytes) != "abc" {
+		t.Errorf("Expected Audio part, got %#v", decoded.Parts[2])
+	}
+
+	// Tool results keep the call they answer and their error flag
+	data, err = json.Marshal(Message{Role: Tool, Parts: []Part{Text{Text: "boom"}}, ToolCallID: "call_1", IsError: true})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var result Message
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if result.ToolCallID != "call_1" || !result.IsError {
+		t.Errorf("Expected ToolCallID and IsError to round-trip, got %+v", result)
+	}
+
+	// Assistant messages keep the tool calls they made
+	data, err = json.Marshal(Message{Role: Assistant, ToolCalls: []ToolCall{{ID: "call_1", Name: "lookup", Input: json.RawMessage(`{"q":"x"}`)}}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var call Message
+	if err := json.Unmarshal(data, &call); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(call.ToolCalls) != 1 || call.ToolCalls[0].ID != "call_1" || string(call.ToolCalls[0].Input) != `{"q":"x"}` {
+		t.Errorf("Expected ToolCalls to round-trip, got %+v", call.ToolCalls)
+	}
+
+	if err := json.Unmarshal([]byte(`{"role":"user","parts":[{"type":"hologram"}]}`), &decoded); err == nil {
+		t.Error("Expected error for unknown part type")
+	}
+}
@@ -0,0 +1,158 @@
+// Package core provides retry handling for structured output generation.
+// This file implements the corrective retry loop that providers use when a
+// model's structured output fails to parse or validate, and the merging of
+// Request.ResponseSchema into GenerateObject schemas.
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ValidationError indicates that a model's structured output could not be
+// parsed or did not conform to the requested schema.
+type ValidationError struct {
+	// Field is the JSON field that failed validation (empty if not field-specific)
+	Field string `json:"field,omitempty"`
+	// Message describes the validation failure
+	Message string `json:"message"`
+	// Output is the raw model output that failed validation
+	Output string `json:"output,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("validation failed for field %s: %s", e.Field, e.Message)
+	}
+	return fmt.Sprintf("validation failed: %s", e.Message)
+}
+
+// NewValidationError creates a ValidationError from a JSON decoding error.
+// The field is extracted when err is a *json.UnmarshalTypeError.
+func NewValidationError(output string, err error) *ValidationError {
+	ve := &ValidationError{
+		Message: err.Error(),
+		Output:  output,
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		ve.Field = typeErr.Field
+	}
+
+	return ve
+}
+
+// IsValidationError returns true if the error is a structured output validation error.
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// GenerateObjectWithRetry runs generate and, while it fails with a
+// ValidationError, re-issues the request with a corrective user message up to
+// req.MaxStructuredRetries times. Each failed attempt is recorded as a Step
+// ahead of the final result's own steps. When all retries are exhausted the
+// last ValidationError is returned.
+//
+// Providers call this from GenerateObject so that every backend shares the
+// same retry behavior.
+func GenerateObjectWithRetry(ctx context.Context, req Request, generate func(context.Context, Request) (*ObjectResult[any], error)) (*ObjectResult[any], error) {
+	result, err := generate(ctx, req)
+	if req.MaxStructuredRetries <= 0 {
+		return result, err
+	}
+
+	// Copy messages so corrective turns don't leak into the caller's request
+	messages := make([]Message, len(req.Messages), len(req.Messages)+2*req.MaxStructuredRetries)
+	copy(messages, req.Messages)
+
+	var steps []Step
+	for attempt := 1; attempt <= req.MaxStructuredRetries; attempt++ {
+		var ve *ValidationError
+		if !errors.As(err, &ve) {
+			break
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		steps = append(steps, Step{
+			Text:       ve.Output,
+			StepNumber: attempt,
+			Timestamp:  time.Now(),
+		})
+
+		// Show the model its previous answer and what was wrong with it
+		if ve.Output != "" {
+			messages = append(messages, Message{Role: Assistant, Parts: []Part{Text{Text: ve.Output}}})
+		}
+		messages = append(messages, Message{Role: User, Parts: []Part{Text{Text: fmt.Sprintf(
+			"Your previous response failed JSON validation: %s. Please correct it.", ve.Message)}}})
+
+		retryReq := req
+		retryReq.Messages = messages
+		result, err = generate(ctx, retryReq)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return result, nil
+	}
+
+	// Record the successful attempt after the failed ones
+	if len(result.Steps) == 0 {
+		output, _ := json.Marshal(result.Value)
+		result.Steps = []Step{{Text: string(output), Timestamp: time.Now()}}
+	}
+	for i, step := range result.Steps {
+		step.StepNumber = len(steps) + i + 1
+		steps = append(steps, step)
+	}
+	result.Steps = steps
+
+	return result, nil
+}
+
+// MergeResponseSchema returns the schema GenerateObject should use: schema
+// with the keys of override applied on top. Properties are merged by name,
+// so an override can refine individual fields; every other key, including
+// "required", is replaced. If override is empty, schema is returned as JSON
+// unchanged. Both must be JSON objects when override is set.
+func MergeResponseSchema(schema any, override json.RawMessage) (json.RawMessage, error) {
+	base, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling schema: %w", err)
+	}
+	if len(override) == 0 {
+		return base, nil
+	}
+
+	var merged, extra map[string]any
+	if err := json.Unmarshal(base, &merged); err != nil || merged == nil {
+		return nil, fmt.Errorf("schema must be a JSON object to merge a response schema")
+	}
+	if err := json.Unmarshal(override, &extra); err != nil {
+		return nil, fmt.Errorf("response schema must be a JSON object: %w", err)
+	}
+
+	for key, value := range extra {
+		props, ok := value.(map[string]any)
+		baseProps, baseOK := merged[key].(map[string]any)
+		if key == "properties" && ok && baseOK {
+			for name, prop := range props {
+				baseProps[name] = prop
+			}
+			continue
+		}
+		merged[key] = value
+	}
+	return json.Marshal(merged)
+}
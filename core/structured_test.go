@@ -0,0 +1,178 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	var target struct {
+		Count int `json:"count"`
+	}
+	err := json.Unmarshal([]byte(`{"count": "three"}`), &target)
+	ve := NewValidationError(`{"count": "three"}`, err)
+
+	if ve.Field != "count" {
+		t.Errorf("Expected field count, got %q", ve.Field)
+	}
+	if !strings.HasPrefix(ve.Error(), "validation failed for field count") {
+		t.Errorf("Unexpected error string: %s", ve.Error())
+	}
+	if !IsValidationError(WrapError(ve, ErrorInternal, "test")) {
+		t.Error("Expected IsValidationError to see through wrapping")
+	}
+
+	err = json.Unmarshal([]byte(`{"count": `), &target)
+	if ve := NewValidationError("", err); ve.Field != "" {
+		t.Errorf("Expected no field for syntax error, got %q", ve.Field)
+	}
+}
+
+func TestGenerateObjectWithRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxRetries    int
+		failures      int
+		expectedCalls int
+		wantErr       bool
+		expectedSteps int
+	}{
+		{"success without retries", 0, 0, 1, false, 0},
+		{"failure without retries", 0, 1, 1, true, 0},
+		{"success after retry", 2, 1, 2, false, 2},
+		{"retries exhausted", 2, 5, 3, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []Request
+			generate := func(ctx context.Context, req Request) (*ObjectResult[any], error) {
+				requests = append(requests, req)
+				if len(requests) <= tt.failures {
+					return nil, &ValidationError{Message: "unexpected end of JSON input", Output: `{"a":`}
+				}
+				return &ObjectResult[any]{Value: map[string]any{"a": 1.0}}, nil
+			}
+
+			req := Request{
+				Messages:             []Message{{Role: User, Parts: []Part{Text{Text: "go"}}}},
+				MaxStructuredRetries: tt.maxRetries,
+			}
+			result, err := GenerateObjectWithRetry(context.Background(), req, generate)
+
+			if len(requests) != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, len(requests))
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				var ve *ValidationError
+				if !errors.As(err, &ve) {
+					t.Errorf("Expected ValidationError, got %T", err)
+				}
+				return
+			}
+			if len(result.Steps) != tt.expectedSteps {
+				t.Errorf("Expected %d steps, got %d", tt.expectedSteps, len(result.Steps))
+			}
+			for i, step := range result.Steps {
+				if step.StepNumber != i+1 {
+					t.Errorf("Step %d has number %d", i, step.StepNumber)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateObjectWithRetryMessages(t *testing.T) {
+	var requests []Request
+	generate := func(ctx context.Context, req Request) (*ObjectResult[any], error) {
+		requests = append(requests, req)
+		if len(requests) == 1 {
+			return nil, &ValidationError{Message: "unexpected end of JSON input", Output: `{"a":`}
+		}
+		return &ObjectResult[any]{Value: "ok"}, nil
+	}
+
+	messages := []Message{{Role: User, Parts: []Part{Text{Text: "go"}}}}
+	_, err := GenerateObjectWithRetry(context.Background(), Request{
+		Messages:             messages,
+		MaxStructuredRetries: 1,
+	}, generate)
+	if err != nil {
+		t.Fatalf("GenerateObjectWithRetry failed: %v", err)
+	}
+
+	retry := requests[1].Messages
+	if len(retry) != 3 {
+		t.Fatalf("Expected 3 messages on retry, got %d", len(retry))
+	}
+	if retry[1].Role != Assistant || retry[1].Parts[0].(Text).Text != `{"a":` {
+		t.Errorf("Expected failed output as assistant message, got %+v", retry[1])
+	}
+	expected := "Your previous response failed JSON validation: unexpected end of JSON input. Please correct it."
+	if got := retry[2].Parts[0].(Text).Text; got != expected {
+		t.Errorf("Unexpected corrective message: %q", got)
+	}
+	if len(messages) != 1 {
+		t.Errorf("Caller messages were modified: %d", len(messages))
+	}
+
+	// Non-validation errors are not retried
+	requests = nil
+	_, err = GenerateObjectWithRetry(context.Background(), Request{MaxStructuredRetries: 3}, func(ctx context.Context, req Request) (*ObjectResult[any], error) {
+		requests = append(requests, req)
+		return nil, NewError(ErrorRateLimited, "slow down")
+	})
+	if err == nil || len(requests) != 1 {
+		t.Errorf("Expected a single attempt for non-validation errors, got %d (err=%v)", len(requests), err)
+	}
+}
+
+func TestMergeResponseSchema(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+			"age":  map[string]any{"type": "integer"},
+		},
+		"required": []string{"name"},
+	}
+
+	merged, err := MergeResponseSchema(schema, json.RawMessage(`{"properties":{"age":{"type":"integer","minimum":0}},"required":["name","age"]}`))
+	if err != nil {
+		t.Fatalf("MergeResponseSchema failed: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(merged, &got); err != nil {
+		t.Fatalf("Merged schema is not valid JSON: %v", err)
+	}
+	props := got["properties"].(map[string]any)
+	if props["name"] == nil {
+		t.Error("Expected name property to be kept")
+	}
+	if age := props["age"].(map[string]any); age["minimum"] != float64(0) {
+		t.Errorf("Expected age override to apply, got %v", age)
+	}
+	if required := got["required"].([]any); len(required) != 2 {
+		t.Errorf("Expected required to be replaced, got %v", required)
+	}
+
+	// Without an override the schema is returned unchanged
+	plain, err := MergeResponseSchema(schema, nil)
+	if err != nil {
+		t.Fatalf("MergeResponseSchema failed: %v", err)
+	}
+	if !strings.Contains(string(plain), `"required":["name"]`) {
+		t.Errorf("Expected schema unchanged, got %s", plain)
+	}
+
+	if _, err := MergeResponseSchema(schema, json.RawMessage(`[1]`)); err == nil {
+		t.Error("Expected error for non-object response schema")
+	}
+}
@@ -0,0 +1,204 @@
+// Package core provides conversation compression for long histories.
+// This file implements token counting, LLM-based summarization of older
+// messages, and trimming a request to fit a token budget.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TokenCounter estimates the number of input tokens used by messages.
+type TokenCounter interface {
+	CountTokens(messages []Message) int
+}
+
+// TokenCounterFunc adapts a function to the TokenCounter interface.
+type TokenCounterFunc func(messages []Message) int
+
+// CountTokens implements TokenCounter.
+func (f TokenCounterFunc) CountTokens(messages []Message) int {
+	return f(messages)
+}
+
+// ApproxTokenCounter estimates tokens without a tokenizer: about four
+// characters per token for text, a fixed cost for images and a small
+// overhead per message. It is intended for budgeting, not billing.
+var ApproxTokenCounter TokenCounter = TokenCounterFunc(approxTokens)
+
+// approxTokens implements ApproxTokenCounter.
+func approxTokens(messages []Message) int {
+	const (
+		perMessage = 4
+		perImage   = 85
+	)
+
+	total := 0
+	for _, msg := range messages {
+		total += perMessage
+		for _, part := range msg.Parts {
+			switch p := part.(type) {
+			case Text:
+				total += (len(p.Text) + 3) / 4
+			case ImageURL:
+				total += perImage
+			}
+		}
+	}
+	return total
+}
+
+// MessageSummarizer condenses a sequence of messages into a single message.
+type MessageSummarizer interface {
+	Summarize(ctx context.Context, messages []Message) (Message, error)
+}
+
+// DefaultSummaryPrompt is the system prompt used by NewLLMSummarizer when
+// none is given.
+const DefaultSummaryPrompt = "Summarize the following conversation so it can replace the original messages. " +
+	"Keep names, facts, decisions, open questions and tool results that later turns may rely on. " +
+	"Be concise and write in the third person."
+
+// LLMSummarizer summarizes messages with a provider call.
+type LLMSummarizer struct {
+	provider     Provider
+	model        string
+	systemPrompt string
+}
+
+// NewLLMSummarizer creates a summarizer that asks provider to summarize the
+// conversation. A cheaper model than the one used for the conversation, such
+// as gpt-4o-mini, is usually sufficient. An empty systemPrompt uses
+// DefaultSummaryPrompt.
+func NewLLMSummarizer(provider Provider, model string, systemPrompt string) *LLMSummarizer {
+	if systemPrompt == "" {
+		systemPrompt = DefaultSummaryPrompt
+	}
+	return &LLMSummarizer{
+		provider:     provider,
+		model:        model,
+		systemPrompt: systemPrompt,
+	}
+}
+
+// Summarize implements MessageSummarizer. The returned message is a system
+// message containing the summary.
+func (s *LLMSummarizer) Summarize(ctx context.Context, messages []Message) (Message, error) {
+	result, err := s.provider.GenerateText(ctx, Request{
+		Model: s.model,
+		Messages: []Message{
+			{Role: System, Parts: []Part{Text{Text: s.systemPrompt}}},
+			{Role: User, Parts: []Part{Text{Text: transcript(messages)}}},
+		},
+	})
+	if err != nil {
+		return Message{}, fmt.Errorf("summarizing conversation: %w", err)
+	}
+
+	return Message{
+		Role:  System,
+		Parts: []Part{Text{Text: "Summary of the earlier conversation:\n" + strings.TrimSpace(result.Text)}},
+	}, nil
+}
+
+// transcript renders messages as plain text for summarization.
+func transcript(messages []Message) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		b.WriteString(string(msg.Role))
+		if msg.Name != "" {
+			b.WriteString(" (" + msg.Name + ")")
+		}
+		b.WriteString(": ")
+		for i, part := range msg.Parts {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			switch p := part.(type) {
+			case Text:
+				b.WriteString(p.Text)
+			default:
+				b.WriteString("[" + part.partType() + "]")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// TrimWithSummary fits req.Messages within maxTokens as measured by counter.
+// Requests that already fit are returned unchanged. Otherwise leading system
+// messages and as many recent messages as fit in three quarters of
+// maxTokens are kept, and the older messages are replaced by a single
+// summary from summarizer, inserted after the system messages. The remaining
+// quarter is left for the summary, so summarizer is called once.
+//
+// Tool results are never separated from the turn that requested them: a kept
+// history never starts with a tool message. An ErrorContextLengthExceeded
+// error is returned if the system messages and latest turn alone exceed
+// maxTokens, or if the summary does not fit in the space left for it.
+func TrimWithSummary(ctx context.Context, req Request, counter TokenCounter, maxTokens int, summarizer MessageSummarizer) (Request, error) {
+	if counter == nil {
+		counter = ApproxTokenCounter
+	}
+	if counter.CountTokens(req.Messages) <= maxTokens {
+		return req, nil
+	}
+
+	// Leading system messages are always kept
+	split := 0
+	for split < len(req.Messages) && req.Messages[split].Role == System {
+		split++
+	}
+	system := req.Messages[:split]
+	conversation := req.Messages[split:]
+	if len(conversation) < 2 {
+		return req, NewError(ErrorContextLengthExceeded,
+			fmt.Sprintf("messages use %d tokens, more than the limit of %d", counter.CountTokens(req.Messages), maxTokens))
+	}
+
+	// Find the longest recent suffix that fits in the space not reserved
+	// for the summary
+	budget := maxTokens * 3 / 4
+	start := len(conversation) - 1
+	for start > 1 && counter.CountTokens(concatMessages(system, nil, conversation[start-1:])) <= budget {
+		start--
+	}
+	// Keep tool results together with the assistant turn that requested them
+	for start > 1 && conversation[start].Role == Tool {
+		start--
+	}
+	if used := counter.CountTokens(concatMessages(system, nil, conversation[start:])); used > maxTokens {
+		return req, NewError(ErrorContextLengthExceeded,
+			fmt.Sprintf("system messages and latest turn use %d tokens, more than the limit of %d", used, maxTokens))
+	}
+
+	summary, err := summarizer.Summarize(ctx, conversation[:start])
+	if err != nil {
+		return req, err
+	}
+	messages := concatMessages(system, []Message{summary}, conversation[start:])
+	if used := counter.CountTokens(messages); used > maxTokens {
+		return req, NewError(ErrorContextLengthExceeded,
+			fmt.Sprintf("summarized messages use %d tokens, more than the limit of %d", used, maxTokens))
+	}
+
+	trimmed := req
+	trimmed.Messages = messages
+	return trimmed, nil
+}
+
+// concatMessages concatenates message slices into a new slice.
+func concatMessages(parts ...[]Message) []Message {
+	n := 0
+	for _, p := range parts {
+		n += len(p)
+	}
+	messages := make([]Message, 0, n)
+	for _, p := range parts {
+		messages = append(messages, p...)
+	}
+	return messages
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// messageCounter counts one token per message.
+var messageCounter = TokenCounterFunc(func(messages []Message) int { return len(messages) })
+
+// fakeSummarizer records the messages it was asked to summarize.
+type fakeSummarizer struct {
+	calls [][]Message
+	err   error
+}
+
+func (s *fakeSummarizer) Summarize(ctx context.Context, messages []Message) (Message, error) {
+	s.calls = append(s.calls, messages)
+	if s.err != nil {
+		return Message{}, s.err
+	}
+	return Message{Role: System, Parts: []Part{Text{Text: "summary"}}}, nil
+}
+
+func conversationOf(roles ...Role) []Message {
+	messages := make([]Message, len(roles))
+	for i, role := range roles {
+		messages[i] = Message{Role: role, Parts: []Part{Text{Text: string(role)}}}
+	}
+	return messages
+}
+
+func TestTrimWithSummary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fits", func(t *testing.T) {
+		summarizer := &fakeSummarizer{}
+		req := Request{Messages: conversationOf(System, User, Assistant)}
+		got, err := TrimWithSummary(ctx, req, messageCounter, 3, summarizer)
+		if err != nil || len(got.Messages) != 3 || len(summarizer.calls) != 0 {
+			t.Errorf("Expected request unchanged without summarizing, got %d messages, %d calls, %v",
+				len(got.Messages), len(summarizer.calls), err)
+		}
+	})
+
+	t.Run("summarizes oldest", func(t *testing.T) {
+		summarizer := &fakeSummarizer{}
+		req := Request{Messages: conversationOf(System, User, Assistant, User, Assistant, User)}
+		got, err := TrimWithSummary(ctx, req, messageCounter, 4, summarizer)
+		if err != nil {
+			t.Fatalf("TrimWithSummary failed: %v", err)
+		}
+
+		// system + summary + 2 recent
+		if len(got.Messages) != 4 {
+			t.Fatalf("Expected 4 messages, got %d", len(got.Messages))
+		}
+		if got.Messages[1].Parts[0].(Text).Text != "summary" {
+			t.Errorf("Expected summary after system message, got %+v", got.Messages[1])
+		}
+		if len(summarizer.calls) != 1 || len(summarizer.calls[0]) != 3 {
+			t.Errorf("Expected one summary of the 3 oldest messages, got calls %v", summarizer.calls)
+		}
+		if len(req.Messages) != 6 {
+			t.Error("Original request was modified")
+		}
+	})
+
+	t.Run("keeps tool results with their call", func(t *testing.T) {
+		summarizer := &fakeSummarizer{}
+		req := Request{Messages: conversationOf(User, Assistant, User, Assistant, Tool, Tool)}
+		got, err := TrimWithSummary(ctx, req, messageCounter, 4, summarizer)
+		if err != nil {
+			t.Fatalf("TrimWithSummary failed: %v", err)
+		}
+		if got.Messages[1].Role != Assistant {
+			t.Errorf("Expected kept history to start with the assistant call, got %s", got.Messages[1].Role)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		summarizer := &fakeSummarizer{}
+		req := Request{Messages: conversationOf(System, System, User, Assistant)}
+		_, err := TrimWithSummary(ctx, req, messageCounter, 2, summarizer)
+		if !IsContextSizeExceeded(err) {
+			t.Errorf("Expected context length error, got %v", err)
+		}
+		if len(summarizer.calls) != 0 {
+			t.Errorf("Expected no summary when the latest turn cannot fit, got %d calls", len(summarizer.calls))
+		}
+	})
+
+	t.Run("summary too large", func(t *testing.T) {
+		summarizer := &fakeSummarizer{}
+		req := Request{Messages: conversationOf(System, User, Assistant, User)}
+		// Summaries count as 10 tokens, more than the space left for them
+		counter := TokenCounterFunc(func(messages []Message) int {
+			total := len(messages)
+			for _, msg := range messages {
+				if msg.Parts[0].(Text).Text == "summary" {
+					total += 9
+				}
+			}
+			return total
+		})
+		_, err := TrimWithSummary(ctx, req, counter, 3, summarizer)
+		if !IsContextSizeExceeded(err) {
+			t.Errorf("Expected context length error, got %v", err)
+		}
+		if len(summarizer.calls) != 1 {
+			t.Errorf("Expected one summary, got %d calls", len(summarizer.calls))
+		}
+	})
+
+	t.Run("summarizer error", func(t *testing.T) {
+		summarizer := &fakeSummarizer{err: errors.New("boom")}
+		req := Request{Messages: conversationOf(User, Assistant, User)}
+		if _, err := TrimWithSummary(ctx, req, messageCounter, 2, summarizer); !errors.Is(err, summarizer.err) {
+			t.Errorf("Expected summarizer error, got %v", err)
+		}
+	})
+}
+
+func TestLLMSummarizer(t *testing.T) {
+	provider := &echoProvider{result: &TextResult{Text: " Ada asked about the weather. "}}
+	summarizer := NewLLMSummarizer(provider, "gpt-4o-mini", "")
+
+	summary, err := summarizer.Summarize(context.Background(), []Message{
+		{Role: User, Name: "ada", Parts: []Part{Text{Text: "What's the weather?"}, ImageURL{URL: "https://example.com/sky.png"}}},
+	})
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+
+	req := provider.requests[0]
+	if req.Model != "gpt-4o-mini" || req.Messages[0].Parts[0].(Text).Text != DefaultSummaryPrompt {
+		t.Errorf("Unexpected summary request: %+v", req)
+	}
+	if got := req.Messages[1].Parts[0].(Text).Text; got != "user (ada): What's the weather? [image_url]\n" {
+		t.Errorf("Unexpected transcript: %q", got)
+	}
+	if summary.Role != System || !strings.HasSuffix(summary.Parts[0].(Text).Text, "\nAda asked about the weather.") {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+}
+
+func TestApproxTokenCounter(t *testing.T) {
+	messages := []Message{{Role: User, Parts: []Part{Text{Text: "12345678"}, ImageURL{URL: "x"}}}}
+	if got := ApproxTokenCounter.CountTokens(messages); got != 4+2+85 {
+		t.Errorf("Expected 91 tokens, got %d", got)
+	}
+}
@@ -0,0 +1,46 @@
+// Package core provides the Request.SystemPrompt shorthand.
+// This file implements how a system prompt is merged into request messages.
+
+package core
+
+// ApplySystemPrompt returns req with SystemPrompt moved into Messages: it is
+// prepended as a System message, or, when the first message is already a
+// System message, appended to that message's text after a blank line.
+// SystemPrompt is cleared so the prompt is applied only once. req.Messages
+// is not modified. Providers call this at the start of every request.
+func ApplySystemPrompt(req Request) Request {
+	if req.SystemPrompt == "" {
+		return req
+	}
+	prompt := req.SystemPrompt
+	req.SystemPrompt = ""
+
+	if len(req.Messages) == 0 || req.Messages[0].Role != System {
+		messages := make([]Message, 0, len(req.Messages)+1)
+		messages = append(messages, Message{Role: System, Parts: []Part{Text{Text: prompt}}})
+		req.Messages = append(messages, req.Messages...)
+		return req
+	}
+
+	// Providers read the first text part of system messages, so extend it
+	// rather than adding a part
+	first := req.Messages[0]
+	parts := make([]Part, 0, len(first.Parts)+1)
+	if len(first.Parts) > 0 {
+		if text, ok := first.Parts[0].(Text); ok && text.Text != "" {
+			parts = append(parts, Text{Text: text.Text + "\n\n" + prompt})
+			parts = append(parts, first.Parts[1:]...)
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, Text{Text: prompt})
+		parts = append(parts, first.Parts...)
+	}
+	first.Parts = parts
+
+	messages := make([]Message, len(req.Messages))
+	copy(messages, req.Messages)
+	messages[0] = first
+	req.Messages = messages
+	return req
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestApplySystemPrompt(t *testing.T) {
+	user := Message{Role: User, Parts: []Part{Text{Text: "Hi"}}}
+
+	// Prepended when there is no leading system message
+	req := ApplySystemPrompt(Request{SystemPrompt: "Be brief.", Messages: []Message{user}})
+	if req.SystemPrompt != "" {
+		t.Errorf("SystemPrompt should be cleared, got %q", req.SystemPrompt)
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Role != System {
+		t.Fatalf("expected a leading system message, got %+v", req.Messages)
+	}
+	if got := req.Messages[0].Parts[0].(Text).Text; got != "Be brief." {
+		t.Errorf("unexpected system text %q", got)
+	}
+
+	// Appended to an existing leading system message without changing the caller's slice
+	messages := []Message{{Role: System, Parts: []Part{Text{Text: "You are helpful."}}}, user}
+	req = ApplySystemPrompt(Request{SystemPrompt: "Be brief.", Messages: messages})
+	if len(req.Messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(req.Messages))
+	}
+	if got := req.Messages[0].Parts[0].(Text).Text; got != "You are helpful.\n\nBe brief." {
+		t.Errorf("unexpected merged system text %q", got)
+	}
+	if got := messages[0].Parts[0].(Text).Text; got != "You are helpful." {
+		t.Errorf("caller's messages were modified: %q", got)
+	}
+
+	// No-op without a system prompt
+	req = ApplySystemPrompt(Request{Messages: messages})
+	if len(req.Messages) != 2 || &req.Messages[0] != &messages[0] {
+		t.Error("expected messages to be unchanged")
+	}
+}
@@ -0,0 +1,114 @@
+// Package core provides stream observation.
+// This file implements Tee, which lets middleware and observability code
+// inspect stream events without consuming the stream.
+
+package core
+
+import "sync"
+
+// Tee returns a stream that delivers every event from stream to its consumer
+// and also passes each event to fn, without buffering the response. fn runs
+// synchronously in the goroutine that forwards events, before the event is
+// delivered, so it must not block; panics and slow work in fn are the caller's
+// responsibility. Closing the returned stream closes stream, and the returned
+// stream's events channel is closed without waiting for stream to end.
+//
+// Tee is a function rather than a TextStream method so that existing stream
+// implementations and wrappers keep satisfying the interface.
+//
+// Example:
+//
+//	s, err := provider.StreamText(ctx, req)
+//	s = core.Tee(s, func(e core.Event) {
+//		if e.Type == core.EventTextDelta {
+//			moderator.Observe(e.TextDelta)
+//		}
+//	})
+func Tee(stream TextStream, fn func(Event)) TextStream {
+	t := newTee(stream)
+	go t.forward(nil, func(e Event) Event {
+		fn(e)
+		return e
+	}, nil)
+	return t
+}
+
+// teeStream forwards events from a source stream, observing each one.
+type teeStream struct {
+	source    TextStream
+	events    chan Event
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newTee returns a teeStream for source; call forward to start it.
+func newTee(source TextStream) *teeStream {
+	return &teeStream{
+		source: source,
+		events: make(chan Event, 100),
+		done:   make(chan struct{}),
+	}
+}
+
+// forward delivers the source's events to the consumer, each passed through
+// fn first, until the source ends, stop is closed (a nil stop never is) or
+// the stream is closed. When the source ends or stop is closed, end (if not
+// nil) is called with whether stop ended the stream, and the event it
+// returns, if any, is delivered last. Tee and the request timeouts are built
+// on it.
+func (t *teeStream) forward(stop <-chan struct{}, fn func(Event) Event, end func(stopped bool) (Event, bool)) {
+	defer close(t.events)
+	source := t.source.Events()
+	for {
+		select {
+		case event, ok := <-source:
+			if !ok {
+				t.end(end, false)
+				return
+			}
+			if !t.send(fn(event)) {
+				return
+			}
+		case <-stop:
+			t.end(end, true)
+			return
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// end delivers the final event returned by end, if any.
+func (t *teeStream) end(end func(stopped bool) (Event, bool), stopped bool) {
+	if end == nil {
+		return
+	}
+	if event, ok := end(stopped); ok {
+		t.send(event)
+	}
+}
+
+// send delivers an event unless the stream has been closed.
+func (t *teeStream) send(event Event) bool {
+	select {
+	case t.events <- event:
+		return true
+	case <-t.done:
+		return false
+	}
+}
+
+// Events implements TextStream.
+func (t *teeStream) Events() <-chan Event {
+	return t.events
+}
+
+// Close implements TextStream.
+func (t *teeStream) Close() error {
+	var err error
+	t.closeOnce.Do(func() {
+		close(t.done)
+		err = t.source.Close()
+	})
+	return err
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+// chanStream is a TextStream over a fixed set of events.
+type chanStream struct {
+	events chan Event
+	closed bool
+}
+
+func newChanStream(events ...Event) *chanStream {
+	s := &chanStream{events: make(chan Event, len(events))}
+	for _, e := range events {
+		s.events <- e
+	}
+	close(s.events)
+	return s
+}
+
+func (s *chanStream) Events() <-chan Event { return s.events }
+
+func (s *chanStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestTee(t *testing.T) {
+	source := newChanStream(
+		Event{Type: EventStart},
+		Event{Type: EventTextDelta, TextDelta: "Hello"},
+		Event{Type: EventTextDelta, TextDelta: ", world"},
+		Event{Type: EventFinish},
+	)
+
+	var observed string
+	var count int
+	stream := Tee(source, func(e Event) {
+		count++
+		observed += e.TextDelta
+	})
+
+	var received string
+	var delivered int
+	for e := range stream.Events() {
+		delivered++
+		received += e.TextDelta
+	}
+
+	if delivered != 4 || count != 4 {
+		t.Errorf("Expected 4 events observed and delivered, got %d and %d", count, delivered)
+	}
+	if observed != "Hello, world" || received != "Hello, world" {
+		t.Errorf("Unexpected text: observed %q, received %q", observed, received)
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !source.closed {
+		t.Error("Expected Close to close the source stream")
+	}
+	stream.Close() // idempotent
+}
+
+// openStream is a TextStream whose events channel stays open until it is
+// closed.
+type openStream struct {
+	events chan Event
+}
+
+func (s *openStream) Events() <-chan Event { return s.events }
+
+func (s *openStream) Close() error { return nil }
+
+func TestTeeCloseDoesNotWaitForSource(t *testing.T) {
+	stream := Tee(&openStream{events: make(chan Event)}, func(Event) {})
+	stream.Close()
+	select {
+	case _, ok := <-stream.Events():
+		if ok {
+			t.Error("unexpected event after Close")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("events channel was not closed after Close")
+	}
+}
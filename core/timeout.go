@@ -0,0 +1,260 @@
+// Package core provides per-request hard timeouts.
+// This file implements Request.AbortAfter and Request.FirstTokenTimeout,
+// which providers enforce around each call.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// requestTimeout is the context cause recorded when a request timeout
+// expires.
+type requestTimeout struct {
+	message  string
+	duration time.Duration
+}
+
+// Error implements the error interface.
+func (t *requestTimeout) Error() string {
+	return t.message
+}
+
+// aiError returns the ErrorTimeout AIError reported for the timeout,
+// wrapping err, the error the provider returned when it was interrupted.
+func (t *requestTimeout) aiError(err error) *AIError {
+	opts := []ErrorOption{WithDuration(t.duration)}
+	if err != nil {
+		opts = append(opts, WithWrapped(err))
+	}
+	return NewError(ErrorTimeout, t.message, opts...)
+}
+
+// timeoutCause returns the request timeout that ended ctx, if any.
+func timeoutCause(ctx context.Context) (*requestTimeout, bool) {
+	t, ok := context.Cause(ctx).(*requestTimeout)
+	return t, ok
+}
+
+// timeoutError returns err, or an ErrorTimeout AIError wrapping it when ctx
+// ended because a request timeout expired.
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if t, ok := timeoutCause(ctx); ok {
+		return t.aiError(err)
+	}
+	return err
+}
+
+// startTimeout cancels ctx with a requestTimeout cause after d. It returns
+// nil if d is not positive.
+func startTimeout(d time.Duration, cancel context.CancelCauseFunc, format string) *time.Timer {
+	if d <= 0 {
+		return nil
+	}
+	t := &requestTimeout{message: fmt.Sprintf(format, d), duration: d}
+	return time.AfterFunc(d, func() { cancel(t) })
+}
+
+// stopTimer stops t if it is not nil.
+func stopTimer(t *time.Timer) {
+	if t != nil {
+		t.Stop()
+	}
+}
+
+const (
+	abortAfterFormat        = "request did not complete within %v (Request.AbortAfter)"
+	firstTokenTimeoutFormat = "stream produced no output within %v (Request.FirstTokenTimeout)"
+)
+
+// RunWithTimeout calls call with ctx bounded by req.AbortAfter. If the
+// timeout expires and call fails, the error is replaced by an ErrorTimeout
+// AIError whose Duration is AbortAfter, wrapping the original error.
+// Providers wrap GenerateText and GenerateObject in it.
+//
+// Example:
+//
+//	func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+//		return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.TextResult, error) {
+//			return p.generateText(ctx, req)
+//		})
+//	}
+func RunWithTimeout[T any](ctx context.Context, req Request, call func(context.Context) (T, error)) (T, error) {
+	if req.AbortAfter <= 0 {
+		return call(ctx)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	timer := startTimeout(req.AbortAfter, cancel, abortAfterFormat)
+	defer timer.Stop()
+
+	result, err := call(ctx)
+	return result, timeoutError(ctx, err)
+}
+
+// StreamWithTimeout calls call with ctx bounded by req.AbortAfter and
+// req.FirstTokenTimeout, and returns its stream wrapped so the timeouts last
+// until the stream ends or is closed. The first-token timer stops at the
+// first event other than EventStart. When a timeout expires the provider's
+// request is aborted and the stream ends with an EventError carrying an
+// ErrorTimeout AIError. Providers wrap StreamText in it.
+func StreamWithTimeout(ctx context.Context, req Request, call func(context.Context) (TextStream, error)) (TextStream, error) {
+	if req.AbortAfter <= 0 && req.FirstTokenTimeout <= 0 {
+		return call(ctx)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	abort := startTimeout(req.AbortAfter, cancel, abortAfterFormat)
+	firstToken := startTimeout(req.FirstTokenTimeout, cancel, firstTokenTimeoutFormat)
+
+	stream, err := call(ctx)
+	if err != nil {
+		stopTimer(abort)
+		stopTimer(firstToken)
+		err = timeoutError(ctx, err)
+		cancel(nil)
+		return nil, err
+	}
+	s := newTimeoutStream(ctx, stream, cancel, abort)
+	s.start(firstToken)
+	return s, nil
+}
+
+// StreamObjectWithTimeout is StreamWithTimeout for object streams. Only
+// req.AbortAfter applies, since the object may be read with Final without
+// observing its events; Final also reports an expired timeout as an
+// ErrorTimeout AIError. Providers wrap StreamObject in it.
+func StreamObjectWithTimeout(ctx context.Context, req Request, call func(context.Context) (ObjectStream[any], error)) (ObjectStream[any], error) {
+	if req.AbortAfter <= 0 {
+		return call(ctx)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	abort := startTimeout(req.AbortAfter, cancel, abortAfterFormat)
+
+	stream, err := call(ctx)
+	if err != nil {
+		abort.Stop()
+		err = timeoutError(ctx, err)
+		cancel(nil)
+		return nil, err
+	}
+	s := &timeoutObjectStream{ObjectStream: stream, ctx: ctx, forwarder: newTimeoutStream(ctx, stream, cancel, abort)}
+	s.stop = context.AfterFunc(ctx, func() {
+		if _, ok := timeoutCause(ctx); ok {
+			stream.Close()
+		}
+	})
+	return s, nil
+}
+
+// timeoutStream forwards a stream's events until it ends, is closed, or a
+// request timeout expires.
+type timeoutStream struct {
+	*teeStream
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	abort  *time.Timer
+}
+
+// newTimeoutStream returns a timeoutStream for source; call start to start
+// it.
+func newTimeoutStream(ctx context.Context, source TextStream, cancel context.CancelCauseFunc, abort *time.Timer) *timeoutStream {
+	return &timeoutStream{teeStream: newTee(source), ctx: ctx, cancel: cancel, abort: abort}
+}
+
+// start forwards events to the consumer (see teeStream.forward), reporting
+// an expired timeout as the stream's final error. firstToken, if not nil, is
+// stopped at the first event other than EventStart.
+func (s *timeoutStream) start(firstToken *time.Timer) {
+	ctx := s.ctx
+	// Only an expired timeout ends the stream early; when the caller's
+	// context ends, the provider reports that itself
+	expired := make(chan struct{})
+	stopWatching := context.AfterFunc(ctx, func() {
+		if _, ok := timeoutCause(ctx); ok {
+			close(expired)
+		}
+	})
+
+	reported := false
+	fn := func(event Event) Event {
+		if firstToken != nil && event.Type != EventStart {
+			firstToken.Stop()
+			firstToken = nil
+		}
+		if event.Type == EventError {
+			reported = true
+			event.Err = timeoutError(ctx, event.Err)
+			if _, ok := timeoutCause(ctx); ok {
+				event.ErrorCode = string(ErrorTimeout)
+			}
+		}
+		return event
+	}
+	end := func(stopped bool) (Event, bool) {
+		stopWatching()
+		stopTimer(firstToken)
+		t, timedOut := timeoutCause(ctx)
+		s.release()
+		// A provider that ended its stream when the timeout cancelled its
+		// request may not have reported an error itself
+		if !timedOut || (reported && !stopped) {
+			return Event{}, false
+		}
+		s.source.Close()
+		return Event{Type: EventError, Err: t.aiError(nil), ErrorCode: string(ErrorTimeout), Timestamp: time.Now()}, true
+	}
+	go func() {
+		s.forward(expired, fn, end)
+		// Closing the stream skips end
+		stopWatching()
+		stopTimer(firstToken)
+	}()
+}
+
+// release stops the abort timer and cancels the request context.
+func (s *timeoutStream) release() {
+	stopTimer(s.abort)
+	s.cancel(nil)
+}
+
+// Close implements TextStream.
+func (s *timeoutStream) Close() error {
+	s.release()
+	return s.teeStream.Close()
+}
+
+// timeoutObjectStream enforces Request.AbortAfter on an object stream. Its
+// events are forwarded only once the consumer asks for them, so callers
+// that only call Final do not block the stream.
+type timeoutObjectStream struct {
+	ObjectStream[any]
+	ctx       context.Context
+	forwarder *timeoutStream
+	stop      func() bool
+	once      sync.Once
+}
+
+// Events implements TextStream.
+func (s *timeoutObjectStream) Events() <-chan Event {
+	s.once.Do(func() { s.forwarder.start(nil) })
+	return s.forwarder.Events()
+}
+
+// Final implements ObjectStream, reporting an expired timeout as an
+// ErrorTimeout AIError.
+func (s *timeoutObjectStream) Final() (*any, error) {
+	result, err := s.ObjectStream.Final()
+	return result, timeoutError(s.ctx, err)
+}
+
+// Close implements TextStream.
+func (s *timeoutObjectStream) Close() error {
+	s.stop()
+	return s.forwarder.Close()
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitCall blocks until ctx ends, like a provider call interrupted by its
+// context.
+func waitCall(ctx context.Context) (*TextResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestRunWithTimeout(t *testing.T) {
+	req := Request{AbortAfter: 20 * time.Millisecond}
+	_, err := RunWithTimeout(context.Background(), req, waitCall)
+
+	var aiErr *AIError
+	if !errors.As(err, &aiErr) || aiErr.Code != ErrorTimeout {
+		t.Fatalf("expected ErrorTimeout AIError, got %v", err)
+	}
+	if aiErr.Duration != req.AbortAfter {
+		t.Errorf("Duration = %v, want %v", aiErr.Duration, req.AbortAfter)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the interrupted call's error to be wrapped, got %v", err)
+	}
+
+	// Completed calls and other errors are returned unchanged
+	result, err := RunWithTimeout(context.Background(), req, func(ctx context.Context) (*TextResult, error) {
+		return &TextResult{Text: "done"}, nil
+	})
+	if err != nil || result.Text != "done" {
+		t.Errorf("unexpected result %v, %v", result, err)
+	}
+	boom := errors.New("boom")
+	if _, err := RunWithTimeout(context.Background(), req, func(ctx context.Context) (*TextResult, error) {
+		return nil, boom
+	}); err != boom {
+		t.Errorf("expected error unchanged, got %v", err)
+	}
+
+	// The caller's own deadline is not reported as AbortAfter
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := RunWithTimeout(ctx, Request{AbortAfter: time.Minute}, waitCall); err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+// blockingStream emits its events and then stays open until its context
+// ends or it is closed.
+func blockingStream(ctx context.Context, events ...Event) TextStream {
+	ch := make(chan Event, len(events))
+	for _, e := range events {
+		ch <- e
+	}
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return &sliceStream{events: ch}
+}
+
+func TestStreamWithTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		sent []Event
+		want time.Duration
+	}{
+		{
+			name: "abort after",
+			req:  Request{AbortAfter: 20 * time.Millisecond},
+			sent: []Event{{Type: EventStart}, {Type: EventTextDelta, TextDelta: "Hi"}},
+			want: 20 * time.Millisecond,
+		},
+		{
+			name: "first token timeout",
+			req:  Request{FirstTokenTimeout: 20 * time.Millisecond, AbortAfter: time.Minute},
+			sent: []Event{{Type: EventStart}},
+			want: 20 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := StreamWithTimeout(context.Background(), tt.req, func(ctx context.Context) (TextStream, error) {
+				return blockingStream(ctx, tt.sent...), nil
+			})
+			if err != nil {
+				t.Fatalf("StreamWithTimeout failed: %v", err)
+			}
+			defer stream.Close()
+
+			var received []Event
+			for e := range stream.Events() {
+				received = append(received, e)
+			}
+			if len(received) != len(tt.sent)+1 {
+				t.Fatalf("expected %d events, got %+v", len(tt.sent)+1, received)
+			}
+			last := received[len(received)-1]
+			var aiErr *AIError
+			if last.Type != EventError || !errors.As(last.Err, &aiErr) || aiErr.Code != ErrorTimeout {
+				t.Fatalf("expected a timeout error event, got %+v", last)
+			}
+			if aiErr.Duration != tt.want || last.ErrorCode != string(ErrorTimeout) {
+				t.Errorf("got Duration %v and code %q, want %v and timeout", aiErr.Duration, last.ErrorCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamWithTimeoutFirstTokenArrives(t *testing.T) {
+	req := Request{FirstTokenTimeout: 20 * time.Millisecond}
+	stream, err := StreamWithTimeout(context.Background(), req, func(ctx context.Context) (TextStream, error) {
+		ch := make(chan Event)
+		go func() {
+			defer close(ch)
+			ch <- Event{Type: EventStart}
+			ch <- Event{Type: EventTextDelta, TextDelta: "Hi"}
+			// Slower than FirstTokenTimeout, but after the first token
+			time.Sleep(50 * time.Millisecond)
+			ch <- Event{Type: EventFinish}
+		}()
+		return &sliceStream{events: ch}, nil
+	})
+	if err != nil {
+		t.Fatalf("StreamWithTimeout failed: %v", err)
+	}
+	defer stream.Close()
+
+	var types []EventType
+	for e := range stream.Events() {
+		types = append(types, e.Type)
+	}
+	if len(types) != 3 || types[2] != EventFinish {
+		t.Errorf("expected the stream to finish, got %v", types)
+	}
+}
+
+func TestStreamWithTimeoutCallError(t *testing.T) {
+	req := Request{AbortAfter: 20 * time.Millisecond}
+	_, err := StreamWithTimeout(context.Background(), req, func(ctx context.Context) (TextStream, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if !IsTimeout(err) {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+// blockingObjectStream is an object stream whose Final waits for its
+// context.
+type blockingObjectStream struct {
+	TextStream
+	ctx context.Context
+}
+
+func (s *blockingObjectStream) Final() (*any, error) {
+	<-s.ctx.Done()
+	return nil, s.ctx.Err()
+}
+
+func TestStreamObjectWithTimeout(t *testing.T) {
+	req := Request{AbortAfter: 20 * time.Millisecond}
+	stream, err := StreamObjectWithTimeout(context.Background(), req, func(ctx context.Context) (ObjectStream[any], error) {
+		return &blockingObjectStream{TextStream: blockingStream(ctx), ctx: ctx}, nil
+	})
+	if err != nil {
+		t.Fatalf("StreamObjectWithTimeout failed: %v", err)
+	}
+	defer stream.Close()
+
+	if _, err := stream.Final(); !IsTimeout(err) {
+		t.Errorf("expected timeout error from Final, got %v", err)
+	}
+}
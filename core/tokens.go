@@ -0,0 +1,15 @@
+// Package core provides the Request.MaxOutputTokens limit.
+// This file resolves MaxOutputTokens against its deprecated MaxTokens alias.
+
+package core
+
+// OutputTokenLimit returns the maximum number of tokens req lets the model
+// generate: MaxOutputTokens, or the deprecated MaxTokens when
+// MaxOutputTokens is 0. It returns 0 when neither is set. Providers and
+// middleware read the limit through this function so both fields work.
+func OutputTokenLimit(req Request) int {
+	if req.MaxOutputTokens > 0 {
+		return req.MaxOutputTokens
+	}
+	return req.MaxTokens
+}
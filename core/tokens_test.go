@@ -0,0 +1,23 @@
+package core
+
+import "testing"
+
+func TestOutputTokenLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want int
+	}{
+		{"unset", Request{}, 0},
+		{"max output tokens", Request{MaxOutputTokens: 100}, 100},
+		{"deprecated max tokens", Request{MaxTokens: 200}, 200},
+		{"max output tokens wins", Request{MaxOutputTokens: 100, MaxTokens: 200}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OutputTokenLimit(tt.req); got != tt.want {
+				t.Errorf("OutputTokenLimit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
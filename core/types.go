@@ -109,6 +109,17 @@ type Message struct {
 	Role  Role   `json:"role"`
 	Parts []Part `json:"parts"`
 	Name  string `json:"name,omitempty"` // Optional participant name
+
+	// ToolCalls lists the tool calls an Assistant message made. Providers
+	// send them as the model's tool use, so the Tool messages that follow
+	// can answer them by ToolCallID.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolCallID links a Tool message to the ToolCall.ID it answers, so
+	// providers match results to calls regardless of message order. Set it
+	// only when the preceding Assistant message lists the call in ToolCalls.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	// IsError marks a Tool message that reports a failed tool call
+	IsError bool `json:"is_error,omitempty"`
 }
 
 // ToolChoice specifies how the model should use tools.
@@ -125,6 +136,28 @@ const (
 	ToolSpecific
 )
 
+// ToolChoiceValue is a tool choice together with the tool it names, if any.
+// Providers translate it to their native format, such as OpenAI's
+// {"type": "function", "function": {"name": ...}} or Anthropic's
+// {"type": "tool", "name": ...}.
+type ToolChoiceValue struct {
+	// Choice is how the model should use tools
+	Choice ToolChoice
+	// Tool names the tool to call when Choice is ToolSpecific
+	Tool string
+}
+
+// ToolForce returns a tool choice that makes the model call the named tool.
+func ToolForce(name string) ToolChoiceValue {
+	return ToolChoiceValue{Choice: ToolSpecific, Tool: name}
+}
+
+// Apply sets req's ToolChoice and SpecificTool from v.
+func (v ToolChoiceValue) Apply(req *Request) {
+	req.ToolChoice = v.Choice
+	req.SpecificTool = v.Tool
+}
+
 // SafetyLevel represents content safety thresholds.
 type SafetyLevel string
 
@@ -156,13 +189,27 @@ type Request struct {
 	RequestID string `json:"request_id,omitempty"`
 	// IdempotencyKey enables request deduplication (client-supplied)
 	IdempotencyKey string `json:"idempotency_key,omitempty"`
-	// Model specifies which model to use
+	// Model specifies which model to use. AutoModel ("auto") lets the
+	// provider choose one from the request's needs (see SelectModel).
 	Model string `json:"model,omitempty"`
+	// AutoSelectModel lets the provider choose the model, as if Model were
+	// AutoModel. A Model set alongside it is ignored.
+	AutoSelectModel bool `json:"auto_select_model,omitempty"`
 	// Messages contains the conversation history
 	Messages []Message `json:"messages"`
+	// SystemPrompt is shorthand for a leading system message. Providers
+	// prepend it to Messages, or append it to the first message if that is
+	// already a system message (see ApplySystemPrompt).
+	SystemPrompt string `json:"system_prompt,omitempty"`
 	// Temperature controls randomness (0.0 = deterministic, 2.0 = very random)
 	Temperature float32 `json:"temperature,omitempty"`
-	// MaxTokens limits the response length
+	// MaxOutputTokens limits the number of tokens the model may generate.
+	// It does not count input tokens.
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+	// MaxTokens limits the response length.
+	//
+	// Deprecated: MaxTokens counts output tokens only; use MaxOutputTokens.
+	// It is used when MaxOutputTokens is 0 (see OutputTokenLimit).
 	MaxTokens int `json:"max_tokens,omitempty"`
 	// Tools available for the model to use
 	Tools []ToolHandle `json:"tools,omitempty"`
@@ -182,6 +229,62 @@ type Request struct {
 	Metadata map[string]any `json:"metadata,omitempty"`
 	// Stream enables streaming responses
 	Stream bool `json:"stream"`
+	// MaxRetries is the maximum number of times a failed API call (for
+	// example, a transient error) is retried. When positive it overrides the
+	// provider's retry count; a negative value disables provider retries.
+	// Structured output validation retries use MaxStructuredRetries.
+	MaxRetries int `json:"max_retries,omitempty"`
+	// RetryOn decides whether a failed API call is retried, overriding the
+	// provider's default classification. Setting MaxRetries or RetryOn makes
+	// the provider handle retries and the retry middleware pass requests through.
+	RetryOn func(error) bool `json:"-"`
+	// MaxStructuredRetries is the number of times GenerateObject and
+	// GenerateJSON re-issue the request when the model's output fails JSON
+	// validation (default 0)
+	MaxStructuredRetries int `json:"max_structured_retries,omitempty"`
+	// ResponseSchema is a JSON Schema the response must conform to. When set,
+	// GenerateText and StreamText request structured output using it, and
+	// GenerateObject merges it over the schema it was given (see
+	// MergeResponseSchema).
+	ResponseSchema json.RawMessage `json:"response_schema,omitempty"`
+	// ExtendedThinking asks models that support it (Anthropic Claude) to
+	// reason before answering. The reasoning is returned in
+	// TextResult.ThinkingBlocks; providers without extended thinking ignore it.
+	ExtendedThinking bool `json:"extended_thinking,omitempty"`
+	// PredictedOutput is the expected response, such as the current contents
+	// of a file being edited. Providers that support predicted outputs
+	// (OpenAI) use it to reduce latency; others ignore it.
+	PredictedOutput string `json:"predicted_output,omitempty"`
+	// TopLogprobs requests token log probabilities from providers that
+	// support them (OpenAI), returned in TextResult.TokenLogprobs. Nil
+	// disables them; 0 to 20 also returns that many most likely alternatives
+	// per token. Logprobs make responses several times larger, which adds
+	// latency and bandwidth, so enable them only where they are used.
+	TopLogprobs *int `json:"top_logprobs,omitempty"`
+	// N is the number of completions to generate in one call; zero means 1.
+	// Providers that support it (OpenAI) return the additional completions
+	// in TextResult.Alternatives from GenerateText. Other providers and
+	// methods fail with ErrorUnsupported when N > 1, so callers can fall
+	// back to separate requests.
+	N int `json:"n,omitempty"`
+	// AutoFetchImages makes providers download HTTPS ImageURL parts and send
+	// them inline as base64 data: URLs, for images behind auth-gated URLs
+	// that the provider cannot fetch itself. See FetchImages.
+	AutoFetchImages bool `json:"auto_fetch_images,omitempty"`
+	// AbortAfter is a hard timeout for the whole request, including tool
+	// steps and retries; zero means none. For streams it runs from the
+	// StreamText or StreamObject call until the stream ends. When it expires
+	// the request fails with an ErrorTimeout AIError whose Duration is
+	// AbortAfter, instead of context.DeadlineExceeded.
+	AbortAfter time.Duration `json:"abort_after,omitempty"`
+	// FirstTokenTimeout fails a StreamText request with an ErrorTimeout
+	// AIError if the stream delivers nothing beyond its start event within
+	// this time; zero means none. Unlike AbortAfter it does not limit how
+	// long the rest of the stream takes.
+	FirstTokenTimeout time.Duration `json:"first_token_timeout,omitempty"`
+	// DisableAliasResolution keeps Model unchanged by model alias middleware
+	// such as middleware.WithModelAliasResolution
+	DisableAliasResolution bool `json:"disable_alias_resolution,omitempty"`
 }
 
 // ToolHandle represents a tool that can be executed by the AI.
@@ -193,18 +296,38 @@ type ToolHandle interface {
 	// Description returns a human-readable description of what the tool does
 	Description() string
 	// InSchemaJSON returns the JSON Schema for the tool's input parameters
-	InSchemaJSON() []byte
-	// OutSchemaJSON returns the JSON Schema for the tool's output
-	OutSchemaJSON() []byte
+	InSchemaJSON() json.RawMessage
 	// Exec executes the tool with raw JSON input and returns the result
 	Exec(ctx context.Context, raw json.RawMessage, meta interface{}) (any, error)
 }
 
+// ToolHandleWithOutputSchema is implemented by tool handles that describe
+// their result. It is optional; tools.NewCoreAdapter implements it when the
+// wrapped tool has an output schema.
+type ToolHandleWithOutputSchema interface {
+	ToolHandle
+	// OutSchemaJSON returns the JSON Schema for the tool's output
+	OutSchemaJSON() json.RawMessage
+}
+
 // Usage tracks token consumption for a request.
 type Usage struct {
 	InputTokens  int `json:"input_tokens"`
 	OutputTokens int `json:"output_tokens"`
 	TotalTokens  int `json:"total_tokens"`
+	// CacheReadTokens counts input tokens served from the provider's prompt cache
+	CacheReadTokens int `json:"cache_read_tokens,omitempty"`
+	// CacheCreationTokens counts input tokens written to the provider's prompt cache
+	CacheCreationTokens int `json:"cache_creation_tokens,omitempty"`
+}
+
+// Add adds the token counts of other to u.
+func (u *Usage) Add(other Usage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.TotalTokens += other.TotalTokens
+	u.CacheReadTokens += other.CacheReadTokens
+	u.CacheCreationTokens += other.CacheCreationTokens
 }
 
 // ToolCall represents a request to execute a tool.
@@ -212,6 +335,18 @@ type ToolCall struct {
 	ID    string          `json:"id,omitempty"`
 	Name  string          `json:"name"`
 	Input json.RawMessage `json:"input"`
+	// ProviderMeta carries provider-specific details of the call that have
+	// no dedicated field, such as "openai.index". Keys are prefixed with the
+	// provider name; read them with ToolCallMeta.
+	ProviderMeta map[string]any `json:"provider_meta,omitempty"`
+}
+
+// ToolCallMeta returns the provider-specific value stored under key in
+// call.ProviderMeta, and whether it was present. It is safe to call on
+// calls without metadata.
+func ToolCallMeta(call ToolCall, key string) (any, bool) {
+	value, ok := call.ProviderMeta[key]
+	return value, ok
 }
 
 // ToolExecution represents the result of executing a tool.
@@ -222,6 +357,54 @@ type ToolExecution struct {
 	Error  string `json:"error,omitempty"`
 }
 
+// ToolResult returns the execution as the result sent back to the model.
+func (e ToolExecution) ToolResult() ToolResult {
+	return ToolResult{
+		CallID:  e.ID,
+		Result:  e.Result,
+		Error:   e.Error,
+		IsError: e.Error != "",
+	}
+}
+
+// ToolResult is the outcome of a tool call as sent back to the model.
+// Providers with a dedicated error flag (Anthropic's is_error) send IsError
+// with the error text; others send Content, which wraps errors as JSON so
+// the model can tell them apart from results.
+type ToolResult struct {
+	// CallID is the ID of the tool call this result answers
+	CallID string `json:"call_id"`
+	// Result is the value returned by the tool
+	Result any `json:"result,omitempty"`
+	// Error is the error returned by the tool, if any
+	Error string `json:"error,omitempty"`
+	// IsError reports whether the tool failed
+	IsError bool `json:"is_error,omitempty"`
+}
+
+// NewToolResult creates the result of a tool call from the values returned
+// by ToolHandle.Exec, setting IsError when err is non-nil.
+func NewToolResult(callID string, result any, err error) ToolResult {
+	if err != nil {
+		return ToolResult{CallID: callID, Error: err.Error(), IsError: true}
+	}
+	return ToolResult{CallID: callID, Result: result}
+}
+
+// Content returns the result serialized as JSON, or {"error": "..."} if the
+// tool failed.
+func (r ToolResult) Content() string {
+	if r.IsError {
+		data, _ := json.Marshal(map[string]string{"error": r.Error})
+		return string(data)
+	}
+	data, err := json.Marshal(r.Result)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"error": fmt.Sprintf("failed to marshal result: %v", err)})
+	}
+	return string(data)
+}
+
 // Step represents one step in a multi-step execution.
 type Step struct {
 	// Text output from this step
@@ -234,6 +417,9 @@ type Step struct {
 	StepNumber int `json:"step_number"`
 	// Timestamp when the step completed
 	Timestamp time.Time `json:"timestamp"`
+	// Usage is the token consumption of this step's model call. Providers
+	// that only report usage at the end of a stream leave it zero.
+	Usage Usage `json:"usage"`
 }
 
 // TextResult represents the complete result of a text generation request.
@@ -246,6 +432,71 @@ type TextResult struct {
 	Usage Usage `json:"usage"`
 	// Raw contains provider-specific response data
 	Raw any `json:"raw,omitempty"`
+	// FromCache is true when the result was served from a response cache
+	FromCache bool `json:"from_cache,omitempty"`
+	// Cost is the estimated price of the request in US dollars, set by cost
+	// middleware such as middleware.WithCost (zero otherwise)
+	Cost float64 `json:"cost,omitempty"`
+	// ThinkingBlocks holds the model's extended reasoning, in order, when
+	// Request.ExtendedThinking was set. Text never includes it.
+	ThinkingBlocks []ThinkingBlock `json:"thinking_blocks,omitempty"`
+	// RejectedTokens is the number of Request.PredictedOutput tokens the
+	// model did not use. They are still billed as output tokens.
+	RejectedTokens int `json:"rejected_tokens,omitempty"`
+	// TokenLogprobs holds the log probability of each generated token when
+	// Request.TopLogprobs was set
+	TokenLogprobs []TokenLogprob `json:"token_logprobs,omitempty"`
+	// Annotations link spans of Text to their sources, such as the web
+	// search results the model cited. See AnnotatedText.
+	Annotations []Annotation `json:"annotations,omitempty"`
+	// Alternatives holds the completions after the first when Request.N > 1,
+	// in the provider's order. Usage covers all completions, so the
+	// alternatives' own Usage is zero.
+	Alternatives []TextResult `json:"alternatives,omitempty"`
+	// ModelUsed is the model that served the request as reported by the
+	// provider's response, which may differ from Request.Model after alias
+	// resolution, automatic selection or fallback, or when the provider
+	// resolves a model family to a dated version. Empty if not reported.
+	ModelUsed string `json:"model_used,omitempty"`
+}
+
+// TokenLogprob is the log probability of one generated token.
+type TokenLogprob struct {
+	// Token is the generated token
+	Token string `json:"token"`
+	// Logprob is the natural log of the token's probability
+	Logprob float64 `json:"logprob"`
+	// Bytes is the token's UTF-8 encoding, which may split a character
+	// across tokens
+	Bytes []byte `json:"bytes,omitempty"`
+	// TopLogprobs are the most likely tokens at this position, up to
+	// Request.TopLogprobs of them
+	TopLogprobs []TopLogprob `json:"top_logprobs,omitempty"`
+}
+
+// TopLogprob is a likely alternative at a token position.
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []byte  `json:"bytes,omitempty"`
+}
+
+// Annotation links a span of TextResult.Text to a source.
+type Annotation struct {
+	// Type is the kind of annotation (e.g. "web_search_result_location")
+	Type string `json:"type"`
+	// StartIndex and EndIndex are the byte offsets of the span in Text
+	StartIndex int `json:"start_index"`
+	EndIndex   int `json:"end_index"`
+	// URL and Title identify the source
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+}
+
+// ThinkingBlock is one block of a model's extended reasoning.
+type ThinkingBlock struct {
+	// Content is the reasoning text
+	Content string `json:"content"`
 }
 
 // ObjectResult represents a structured output result with a typed value.
@@ -373,10 +624,30 @@ type Event struct {
 	Raw any `json:"raw,omitempty"`
 	// Err contains error information (EventError)
 	Err error `json:"error,omitempty"`
+	// ErrorCode is the stable category of Err, such as "rate_limited" or
+	// "timeout" (EventError). See ErrorCodeOf.
+	ErrorCode string `json:"error_code,omitempty"`
+	// Metadata carries provider-specific data that has no dedicated field.
+	// Use the EventMeta* keys where they apply so consumers can rely on them
+	// across providers; other keys should be prefixed with the provider name
+	// (e.g. "anthropic.stop_sequence").
+	Metadata map[string]any `json:"metadata,omitempty"`
 	// Timestamp of the event
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// Well-known Event.Metadata keys.
+const (
+	// EventMetaToolInputDelta is a fragment of a tool call's JSON arguments
+	// as they stream in (string)
+	EventMetaToolInputDelta = "tool_input_delta"
+	// EventMetaRefusal is refusal text returned instead of content (string)
+	EventMetaRefusal = "refusal"
+	// EventMetaLogprobs holds token log probabilities for a text delta
+	// ([]TokenLogprob) when Request.TopLogprobs was set
+	EventMetaLogprobs = "logprobs"
+)
+
 // TextStream represents a stream of events from a provider.
 type TextStream interface {
 	// Events returns a channel of events
@@ -404,6 +675,126 @@ type Provider interface {
 	StreamObject(ctx context.Context, req Request, schema any) (ObjectStream[any], error)
 }
 
+// ModelValidator is implemented by providers that can check a model name
+// before any network call is made. Providers validate Request.Model overrides
+// against their known model list and return an AIError with ErrorUnknownModel
+// for unrecognized names.
+type ModelValidator interface {
+	// ValidateModel returns an error if the model is not recognized
+	ValidateModel(model string) error
+}
+
+// ModelResolver is implemented by providers that can report which model a
+// request will run on without sending it: Request.Model after alias
+// resolution and automatic selection, or the provider's default model when
+// it is empty. Middleware that works per model, such as concurrency limits,
+// uses it to key requests by the model that actually runs.
+type ModelResolver interface {
+	// RequestModel returns the model req would run on
+	RequestModel(ctx context.Context, req Request) string
+}
+
+// ModelCapability names a feature supported by a model.
+type ModelCapability string
+
+const (
+	// CapabilityToolCalling indicates the model can call tools
+	CapabilityToolCalling ModelCapability = "tool-calling"
+	// CapabilityVision indicates the model accepts image input
+	CapabilityVision ModelCapability = "vision"
+	// CapabilityJSONMode indicates the model can be constrained to JSON output
+	CapabilityJSONMode ModelCapability = "json-mode"
+)
+
+// ModelInfo describes a model available from a provider.
+type ModelInfo struct {
+	// ID is the model name to use in Request.Model
+	ID string `json:"id"`
+	// ContextWindow is the context size in tokens (0 if unknown)
+	ContextWindow int `json:"context_window,omitempty"`
+	// Capabilities lists the features the model is known to support
+	Capabilities []ModelCapability `json:"capabilities,omitempty"`
+}
+
+// Supports reports whether the model is known to support capability.
+func (m ModelInfo) Supports(capability ModelCapability) bool {
+	for _, c := range m.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// ModelLister is implemented by providers that can enumerate the models
+// available to the configured account or server. Context windows and
+// capabilities come from the provider's API where it reports them and from
+// built-in model tables otherwise; fields are left empty when unknown.
+type ModelLister interface {
+	// Models returns the available models
+	Models(ctx context.Context) ([]ModelInfo, error)
+}
+
+// FineTuneHyperparams configures a fine-tuning job. Zero values let the
+// provider choose.
+type FineTuneHyperparams struct {
+	// Epochs is the number of passes over the training data
+	Epochs int `json:"epochs,omitempty"`
+	// BatchSize is the number of examples per training batch
+	BatchSize int `json:"batch_size,omitempty"`
+	// LearningRateMultiplier scales the provider's default learning rate
+	LearningRateMultiplier float64 `json:"learning_rate_multiplier,omitempty"`
+}
+
+// FineTuneOpts describes a fine-tuning job to create.
+type FineTuneOpts struct {
+	// TrainingFile is the provider file ID of the training data (required)
+	TrainingFile string `json:"training_file"`
+	// ValidationFile is the provider file ID of the validation data (optional)
+	ValidationFile string `json:"validation_file,omitempty"`
+	// BaseModel is the model to fine-tune (required)
+	BaseModel string `json:"base_model"`
+	// Hyperparameters tune the training run
+	Hyperparameters FineTuneHyperparams `json:"hyperparameters,omitempty"`
+}
+
+// FineTuneJob reports the state of a fine-tuning job.
+type FineTuneJob struct {
+	// ID identifies the job
+	ID string `json:"id"`
+	// Status is the provider's job status, such as "queued", "running",
+	// "succeeded", "failed" or "cancelled"
+	Status string `json:"status"`
+	// BaseModel is the model being fine-tuned
+	BaseModel string `json:"base_model"`
+	// FineTunedModel is the resulting model, set once the job succeeds. It
+	// can be used directly as Request.Model.
+	FineTunedModel string `json:"fine_tuned_model,omitempty"`
+	// TrainingFile and ValidationFile are the data files used by the job
+	TrainingFile   string `json:"training_file,omitempty"`
+	ValidationFile string `json:"validation_file,omitempty"`
+	// Hyperparameters are the values used, including provider-chosen ones
+	Hyperparameters FineTuneHyperparams `json:"hyperparameters,omitempty"`
+	// CreatedAt is when the job was created
+	CreatedAt time.Time `json:"created_at"`
+	// FinishedAt is when the job finished (zero while running)
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+	// Error describes why the job failed
+	Error string `json:"error,omitempty"`
+}
+
+// FineTuneProvider is implemented by providers that can fine-tune models.
+type FineTuneProvider interface {
+	// CreateFineTuneJob starts a fine-tuning job
+	CreateFineTuneJob(ctx context.Context, opts FineTuneOpts) (FineTuneJob, error)
+	// GetFineTuneJob returns the current state of a job
+	GetFineTuneJob(ctx context.Context, id string) (FineTuneJob, error)
+	// ListFineTuneJobs returns the account's jobs, most recent first
+	ListFineTuneJobs(ctx context.Context) ([]FineTuneJob, error)
+	// CancelFineTuneJob stops a running job
+	CancelFineTuneJob(ctx context.Context, id string) error
+}
+
 // StopCondition defines when to stop multi-step execution.
 type StopCondition interface {
 	// ShouldStop returns true if execution should stop
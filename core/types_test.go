@@ -2,6 +2,8 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -295,6 +297,45 @@ func TestUsageAggregation(t *testing.T) {
 	}
 }
 
+func TestToolResult(t *testing.T) {
+	failed := NewToolResult("call_1", nil, fmt.Errorf(`bad "input"`))
+	if !failed.IsError || failed.Error != `bad "input"` {
+		t.Errorf("unexpected failed result: %+v", failed)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal([]byte(failed.Content()), &payload); err != nil || payload["error"] != `bad "input"` {
+		t.Errorf("Content() = %s, want an error object", failed.Content())
+	}
+
+	ok := NewToolResult("call_2", map[string]int{"n": 1}, nil)
+	if ok.IsError || ok.Content() != `{"n":1}` {
+		t.Errorf("unexpected result: %+v, content %s", ok, ok.Content())
+	}
+
+	exec := ToolExecution{ID: "call_3", Name: "lookup", Error: "timeout"}
+	if r := exec.ToolResult(); !r.IsError || r.CallID != "call_3" {
+		t.Errorf("ToolExecution.ToolResult() = %+v", r)
+	}
+}
+
+func TestToolCallMeta(t *testing.T) {
+	call := ToolCall{ID: "call_1", Name: "lookup", ProviderMeta: map[string]any{"openai.index": 1}}
+	if v, ok := ToolCallMeta(call, "openai.index"); !ok || v != 1 {
+		t.Errorf("ToolCallMeta = %v, %v; want 1, true", v, ok)
+	}
+	if _, ok := ToolCallMeta(call, "anthropic.content_index"); ok {
+		t.Error("expected missing key to report false")
+	}
+	if _, ok := ToolCallMeta(ToolCall{Name: "lookup"}, "openai.index"); ok {
+		t.Error("expected a call without metadata to report false")
+	}
+
+	data, err := json.Marshal(ToolCall{Name: "lookup"})
+	if err != nil || strings.Contains(string(data), "provider_meta") {
+		t.Errorf("empty ProviderMeta should be omitted, got %s", data)
+	}
+}
+
 func TestEventStructure(t *testing.T) {
 	now := time.Now()
 	
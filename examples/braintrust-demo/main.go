@@ -582,11 +582,11 @@ func (ta *ToolAdapter) Description() string {
 	return ta.handle.Description()
 }
 
-func (ta *ToolAdapter) InSchemaJSON() []byte {
+func (ta *ToolAdapter) InSchemaJSON() json.RawMessage {
 	return ta.handle.InSchemaJSON()
 }
 
-func (ta *ToolAdapter) OutSchemaJSON() []byte {
+func (ta *ToolAdapter) OutSchemaJSON() json.RawMessage {
 	return ta.handle.OutSchemaJSON()
 }
 
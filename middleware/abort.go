@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/recera/gai/core"
+)
+
+// abortOnCancelMiddleware ties the lifetime of streams to the request context.
+type abortOnCancelMiddleware struct {
+	baseMiddleware
+}
+
+// WithAbortOnContextCancel creates middleware that terminates streams as soon
+// as the request context is cancelled, for example when the client of an
+// HTTP handler disconnects. The wrapped provider's stream is closed and its
+// HTTP request aborted, and the caller's event channel is closed without
+// waiting for the provider, so cancelled requests stop consuming tokens and
+// rate limit capacity. Closing the stream also aborts the request.
+//
+// Example:
+//
+//	provider = middleware.WithAbortOnContextCancel()(provider)
+//	stream, _ := provider.StreamText(r.Context(), req)
+func WithAbortOnContextCancel() Middleware {
+	return func(provider core.Provider) core.Provider {
+		return &abortOnCancelMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+		}
+	}
+}
+
+// StreamText implements the Provider interface, aborting the stream when ctx
+// is cancelled.
+func (m *abortOnCancelMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := m.provider.StreamText(ctx, req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// Closing a Tee closes its event channel without waiting for the
+	// provider, which may be blocked
+	s := &abortableStream{TextStream: core.Tee(stream, func(core.Event) {}), cancel: cancel}
+	s.stop = context.AfterFunc(ctx, func() {
+		s.TextStream.Close()
+	})
+	return s, nil
+}
+
+// StreamObject implements the Provider interface, closing the stream when
+// ctx is cancelled.
+func (m *abortOnCancelMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := m.provider.StreamObject(ctx, req, schema)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	s := &abortableObjectStream{ObjectStream: stream, cancel: cancel}
+	s.stop = context.AfterFunc(ctx, func() {
+		stream.Close()
+	})
+	return s, nil
+}
+
+// abortableStream closes a text stream when its context is cancelled.
+type abortableStream struct {
+	core.TextStream
+	cancel context.CancelFunc
+	stop   func() bool
+}
+
+// Close implements core.TextStream.
+func (s *abortableStream) Close() error {
+	s.stop()
+	s.cancel()
+	return s.TextStream.Close()
+}
+
+// abortableObjectStream closes an object stream when its context is
+// cancelled.
+type abortableObjectStream struct {
+	core.ObjectStream[any]
+	cancel context.CancelFunc
+	stop   func() bool
+}
+
+// Close implements core.TextStream.
+func (s *abortableObjectStream) Close() error {
+	s.stop()
+	s.cancel()
+	return s.ObjectStream.Close()
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// blockingStream is a stream whose provider never finishes on its own.
+type blockingStream struct {
+	events chan core.Event
+	closed atomic.Bool
+}
+
+func (s *blockingStream) Events() <-chan core.Event { return s.events }
+
+func (s *blockingStream) Close() error {
+	s.closed.Store(true)
+	return nil
+}
+
+func TestWithAbortOnContextCancel_StreamText(t *testing.T) {
+	inner := &blockingStream{events: make(chan core.Event, 1)}
+	inner.events <- core.Event{Type: core.EventTextDelta, TextDelta: "partial"}
+
+	var providerCtx context.Context
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			providerCtx = ctx
+			return inner, nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := WithAbortOnContextCancel()(mock).StreamText(ctx, core.Request{})
+	if err != nil {
+		t.Fatalf("StreamText failed: %v", err)
+	}
+	defer stream.Close()
+
+	if event := <-stream.Events(); event.TextDelta != "partial" {
+		t.Fatalf("unexpected first event: %+v", event)
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-stream.Events():
+		if ok {
+			t.Error("expected the event channel to close after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream was not terminated after cancellation")
+	}
+	if !inner.closed.Load() {
+		t.Error("underlying stream was not closed")
+	}
+	if providerCtx.Err() == nil {
+		t.Error("provider context was not cancelled")
+	}
+}
+
+func TestWithAbortOnContextCancel_Completes(t *testing.T) {
+	inner := &blockingStream{events: make(chan core.Event, 2)}
+	inner.events <- core.Event{Type: core.EventTextDelta, TextDelta: "hello"}
+	inner.events <- core.Event{Type: core.EventFinish}
+	close(inner.events)
+
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			return inner, nil
+		},
+	}
+
+	stream, err := WithAbortOnContextCancel()(mock).StreamText(context.Background(), core.Request{})
+	if err != nil {
+		t.Fatalf("StreamText failed: %v", err)
+	}
+	defer stream.Close()
+
+	count := 0
+	for range stream.Events() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("got %d events, want 2", count)
+	}
+}
+
+func TestWithAbortOnContextCancel_StreamObject(t *testing.T) {
+	inner := &mockObjectStream{}
+	closed := make(chan struct{})
+	mock := &mockProvider{
+		streamObjectFunc: func(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+			go func() {
+				<-ctx.Done()
+				close(closed)
+			}()
+			return inner, nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := WithAbortOnContextCancel()(mock).StreamObject(ctx, core.Request{}, nil)
+	if err != nil {
+		t.Fatalf("StreamObject failed: %v", err)
+	}
+	defer stream.Close()
+
+	cancel()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("provider context was not cancelled")
+	}
+}
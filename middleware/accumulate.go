@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+
+	"github.com/recera/gai/core"
+)
+
+// StreamHook inspects the complete result of a stream before it is delivered.
+type StreamHook func(result *core.TextResult) error
+
+// streamAccumulatorMiddleware buffers streams so a hook can see the full result.
+type streamAccumulatorMiddleware struct {
+	baseMiddleware
+	hook StreamHook
+}
+
+// WithStreamAccumulator creates middleware that makes the complete result of
+// a StreamText call available to hook before the caller sees any of it. The
+// stream is read to the end and buffered, hook runs with the assembled text,
+// steps and usage, and the buffered events are then replayed to the caller.
+// If hook returns an error, the caller receives a single EventError carrying
+// it instead of the buffered events. Streams that end with an error are
+// replayed without running hook.
+//
+// Buffering trades time to first token for the ability to check the whole
+// output, for example with content moderation.
+//
+// Example:
+//
+//	provider = middleware.WithStreamAccumulator(func(result *core.TextResult) error {
+//		return moderator.Check(result.Text)
+//	})(provider)
+func WithStreamAccumulator(hook StreamHook) Middleware {
+	return func(provider core.Provider) core.Provider {
+		return &streamAccumulatorMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			hook:           hook,
+		}
+	}
+}
+
+// StreamText implements the Provider interface, buffering the stream.
+func (m *streamAccumulatorMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	stream, err := m.provider.StreamText(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &accumulatedStream{
+		events: make(chan core.Event),
+		done:   make(chan struct{}),
+	}
+	s.stream = core.Tee(stream, func(event core.Event) {
+		s.buffered = append(s.buffered, event)
+		s.steps.Add(event)
+	})
+	go s.run(m.hook)
+	return s, nil
+}
+
+// accumulatedStream replays a stream after it has been read completely.
+type accumulatedStream struct {
+	stream    core.TextStream
+	buffered  []core.Event
+	steps     core.StepAccumulator
+	events    chan core.Event
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Events implements core.TextStream.
+func (s *accumulatedStream) Events() <-chan core.Event {
+	return s.events
+}
+
+// Close implements core.TextStream.
+func (s *accumulatedStream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	return s.stream.Close()
+}
+
+// run reads the wrapped stream to the end, runs hook and replays the
+// buffered events.
+func (s *accumulatedStream) run(hook StreamHook) {
+	defer close(s.events)
+
+	for range s.stream.Events() {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+	}
+
+	buffered := s.buffered
+	if failed, _ := s.steps.Failed(); !failed && hook != nil {
+		if err := hook(s.steps.Result()); err != nil {
+			buffered = []core.Event{{Type: core.EventError, Err: err, ErrorCode: core.ErrorCodeOf(err)}}
+		}
+	}
+
+	for _, event := range buffered {
+		select {
+		case s.events <- event:
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+func streamOf(events ...core.Event) *mockProvider {
+	return &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			ch := make(chan core.Event, len(events))
+			for _, e := range events {
+				ch <- e
+			}
+			close(ch)
+			return &mockTextStream{events: ch}, nil
+		},
+	}
+}
+
+func collectEvents(t *testing.T, provider core.Provider) []core.Event {
+	t.Helper()
+	stream, err := provider.StreamText(context.Background(), core.Request{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	var events []core.Event
+	for e := range stream.Events() {
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestStreamAccumulator_ReplaysAfterHook(t *testing.T) {
+	mock := streamOf(
+		core.Event{Type: core.EventStart},
+		core.Event{Type: core.EventTextDelta, TextDelta: "Hello"},
+		core.Event{Type: core.EventTextDelta, TextDelta: " there"},
+		core.Event{Type: core.EventFinish, Usage: &core.Usage{TotalTokens: 7}},
+	)
+
+	var seen *core.TextResult
+	provider := WithStreamAccumulator(func(result *core.TextResult) error {
+		seen = result
+		return nil
+	})(mock)
+
+	events := collectEvents(t, provider)
+	if len(events) != 4 {
+		t.Fatalf("expected 4 replayed events, got %d", len(events))
+	}
+	if seen == nil || seen.Text != "Hello there" || seen.Usage.TotalTokens != 7 {
+		t.Errorf("unexpected hook result: %+v", seen)
+	}
+}
+
+func TestStreamAccumulator_HookError(t *testing.T) {
+	mock := streamOf(
+		core.Event{Type: core.EventTextDelta, TextDelta: "forbidden"},
+		core.Event{Type: core.EventFinish},
+	)
+	blocked := errors.New("blocked by moderation")
+	provider := WithStreamAccumulator(func(result *core.TextResult) error {
+		return blocked
+	})(mock)
+
+	events := collectEvents(t, provider)
+	if len(events) != 1 || events[0].Type != core.EventError || !errors.Is(events[0].Err, blocked) {
+		t.Errorf("expected a single error event, got %+v", events)
+	}
+}
+
+func TestStreamAccumulator_StreamErrorSkipsHook(t *testing.T) {
+	mock := streamOf(
+		core.Event{Type: core.EventTextDelta, TextDelta: "partial"},
+		core.Event{Type: core.EventError, Err: errors.New("connection lost")},
+	)
+	called := false
+	provider := WithStreamAccumulator(func(result *core.TextResult) error {
+		called = true
+		return nil
+	})(mock)
+
+	events := collectEvents(t, provider)
+	if called {
+		t.Error("expected hook not to run for a failed stream")
+	}
+	if len(events) != 2 {
+		t.Errorf("expected failed stream to be replayed, got %d events", len(events))
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/recera/gai/core"
+)
+
+// aliasMiddleware rewrites request models using a fixed alias table.
+type aliasMiddleware struct {
+	baseMiddleware
+	aliases map[string]string
+}
+
+// WithModelAliasResolution creates middleware that replaces Request.Model
+// when it matches a key in aliases, so the same code can run against
+// different models per environment. Requests with an empty model or with
+// DisableAliasResolution set are passed through unchanged. Aliases are not
+// applied recursively.
+//
+// Example:
+//
+//	// Staging: serve gpt-4o requests with the cheaper mini model
+//	provider = middleware.WithModelAliasResolution(map[string]string{
+//	    "gpt-4o": "gpt-4o-mini",
+//	})(provider)
+func WithModelAliasResolution(aliases map[string]string) Middleware {
+	// Copy aliases so later changes by the caller don't affect requests
+	fixed := make(map[string]string, len(aliases))
+	for k, v := range aliases {
+		fixed[k] = v
+	}
+
+	return func(provider core.Provider) core.Provider {
+		return &aliasMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			aliases:        fixed,
+		}
+	}
+}
+
+// resolve returns req with its model alias replaced.
+func (m *aliasMiddleware) resolve(req core.Request) core.Request {
+	if req.DisableAliasResolution || req.Model == "" {
+		return req
+	}
+	if model, ok := m.aliases[req.Model]; ok {
+		req.Model = model
+	}
+	return req
+}
+
+// GenerateText implements the Provider interface with alias resolution.
+func (m *aliasMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	return m.provider.GenerateText(ctx, m.resolve(req))
+}
+
+// StreamText implements the Provider interface with alias resolution.
+func (m *aliasMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	return m.provider.StreamText(ctx, m.resolve(req))
+}
+
+// GenerateObject implements the Provider interface with alias resolution.
+func (m *aliasMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	return m.provider.GenerateObject(ctx, m.resolve(req), schema)
+}
+
+// StreamObject implements the Provider interface with alias resolution.
+func (m *aliasMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	return m.provider.StreamObject(ctx, m.resolve(req), schema)
+}
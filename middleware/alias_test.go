@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+func TestModelAliasResolution(t *testing.T) {
+	var got string
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			got = req.Model
+			return &core.TextResult{Text: "success"}, nil
+		},
+	}
+
+	aliases := map[string]string{"gpt-4o": "gpt-4o-mini", "gpt-4o-mini": "gpt-3.5-turbo"}
+	provider := WithModelAliasResolution(aliases)(mock)
+
+	// Changes after construction must not leak into requests
+	aliases["gpt-4o"] = "other"
+
+	tests := []struct {
+		name string
+		req  core.Request
+		want string
+	}{
+		{"alias", core.Request{Model: "gpt-4o"}, "gpt-4o-mini"},
+		{"no match", core.Request{Model: "claude-3"}, "claude-3"},
+		{"empty model", core.Request{}, ""},
+		{"opt out", core.Request{Model: "gpt-4o", DisableAliasResolution: true}, "gpt-4o"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := provider.GenerateText(context.Background(), tt.req); err != nil {
+				t.Fatalf("GenerateText failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("model = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
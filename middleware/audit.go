@@ -0,0 +1,374 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// AuditLogVersion is the version of the audit record format, written to every
+// record. Fields may be added within a version; removing or renaming a field,
+// or changing its type or meaning, increments the version.
+const AuditLogVersion = 1
+
+// Audit record finish reasons.
+const (
+	// AuditFinishStop means the model finished its response
+	AuditFinishStop = "stop"
+	// AuditFinishToolCalls means the run ended while the model was calling
+	// tools, for example because a stop condition was met
+	AuditFinishToolCalls = "tool_calls"
+	// AuditFinishError means the request failed
+	AuditFinishError = "error"
+	// AuditFinishIncomplete means the stream was closed before it finished
+	AuditFinishIncomplete = "incomplete"
+)
+
+// AuditLogOpts configures WithAuditLog. The zero value records metadata only.
+type AuditLogOpts struct {
+	// IncludeContent records the text of the input messages and the output
+	IncludeContent bool
+	// HashContent records SHA-256 hashes of the input and output, so content
+	// can later be verified against the log without the log storing it
+	HashContent bool
+	// Exclude lists record fields, by JSON name, to leave out (for example
+	// "request_id")
+	Exclude []string
+}
+
+// AuditRecord is one line of the audit log.
+type AuditRecord struct {
+	Version   int       `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	// DurationMS is the time from the request to its completion
+	DurationMS int64  `json:"duration_ms"`
+	RequestID  string `json:"request_id,omitempty"`
+	// Operation is "generate_text", "stream_text", "generate_object" or
+	// "stream_object"
+	Operation    string `json:"operation"`
+	Model        string `json:"model,omitempty"`
+	MessageCount int    `json:"message_count"`
+	// Messages and Output are only recorded with IncludeContent
+	Messages []AuditMessage `json:"messages,omitempty"`
+	Output   string         `json:"output,omitempty"`
+	// InputHash and OutputHash are only recorded with HashContent
+	InputHash  string `json:"input_hash,omitempty"`
+	OutputHash string `json:"output_hash,omitempty"`
+	// ToolsCalled lists the tools the model called, in order of first use
+	ToolsCalled  []string `json:"tools_called,omitempty"`
+	OutputTokens int      `json:"output_tokens"`
+	// FinishReason is one of the AuditFinish* values
+	FinishReason string `json:"finish_reason"`
+	Error        string `json:"error,omitempty"`
+}
+
+// AuditMessage is the text content of an input message.
+type AuditMessage struct {
+	Role string `json:"role"`
+	Text string `json:"text"`
+}
+
+// auditLogMiddleware writes an audit record for every request.
+type auditLogMiddleware struct {
+	baseMiddleware
+	log *auditLog
+}
+
+// auditLog serializes records to the writer.
+type auditLog struct {
+	mu      sync.Mutex
+	writer  io.Writer
+	opts    AuditLogOpts
+	exclude map[string]bool
+}
+
+// WithAuditLog creates middleware that writes one JSON line per request to
+// writer, for compliance audit trails. Each AuditRecord holds the time,
+// request ID, model, number of input messages, tools called, output tokens,
+// finish reason and error. Message content is recorded only when
+// opts.IncludeContent is set; opts.HashContent records hashes instead, so
+// the log can prove what was sent without storing personal data. Streams are
+// recorded when they finish or are closed.
+//
+// Writes are serialized, so writer need not be safe for concurrent use.
+// Write errors never fail requests, so use a durable writer such as an
+// append-only file.
+//
+// Example:
+//
+//	f, _ := os.OpenFile("audit.jsonl", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+//	provider = middleware.WithAuditLog(f, middleware.AuditLogOpts{HashContent: true})(provider)
+func WithAuditLog(writer io.Writer, opts AuditLogOpts) Middleware {
+	log := &auditLog{
+		writer:  writer,
+		opts:    opts,
+		exclude: make(map[string]bool, len(opts.Exclude)),
+	}
+	for _, field := range opts.Exclude {
+		log.exclude[field] = true
+	}
+
+	return func(provider core.Provider) core.Provider {
+		return &auditLogMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			log:            log,
+		}
+	}
+}
+
+// GenerateText implements the Provider interface with audit logging.
+func (m *auditLogMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	record := m.log.start("generate_text", req)
+	result, err := m.provider.GenerateText(ctx, req)
+	if err == nil && result != nil {
+		m.log.finish(record, result.Text, result.Usage.OutputTokens, toolsCalled(result.Steps), finishReason(result.Steps), nil)
+	} else {
+		m.log.finish(record, "", 0, nil, AuditFinishError, err)
+	}
+	return result, err
+}
+
+// GenerateObject implements the Provider interface with audit logging.
+func (m *auditLogMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	record := m.log.start("generate_object", req)
+	result, err := m.provider.GenerateObject(ctx, req, schema)
+	if err == nil && result != nil {
+		m.log.finish(record, objectOutput(result.Value), result.Usage.OutputTokens, toolsCalled(result.Steps), finishReason(result.Steps), nil)
+	} else {
+		m.log.finish(record, "", 0, nil, AuditFinishError, err)
+	}
+	return result, err
+}
+
+// StreamText implements the Provider interface, logging the stream when it
+// ends.
+func (m *auditLogMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	record := m.log.start("stream_text", req)
+	stream, err := m.provider.StreamText(ctx, req)
+	if err != nil {
+		m.log.finish(record, "", 0, nil, AuditFinishError, err)
+		return nil, err
+	}
+
+	s := &auditedStream{log: m.log, record: record}
+	s.TextStream = core.Tee(stream, s.observe)
+	return s, nil
+}
+
+// StreamObject implements the Provider interface, logging the stream when its
+// final value is read or it is closed.
+func (m *auditLogMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	record := m.log.start("stream_object", req)
+	stream, err := m.provider.StreamObject(ctx, req, schema)
+	if err != nil {
+		m.log.finish(record, "", 0, nil, AuditFinishError, err)
+		return nil, err
+	}
+	return &auditedObjectStream{ObjectStream: stream, log: m.log, record: record}, nil
+}
+
+// start creates the record for a request.
+func (l *auditLog) start(operation string, req core.Request) *AuditRecord {
+	record := &AuditRecord{
+		Version:      AuditLogVersion,
+		Timestamp:    time.Now().UTC(),
+		RequestID:    req.RequestID,
+		Operation:    operation,
+		Model:        req.Model,
+		MessageCount: len(req.Messages),
+	}
+
+	if l.opts.IncludeContent || l.opts.HashContent {
+		messages := auditMessages(req.Messages)
+		if l.opts.IncludeContent {
+			record.Messages = messages
+		}
+		if l.opts.HashContent {
+			data, _ := json.Marshal(messages)
+			record.InputHash = contentHash(data)
+		}
+	}
+	return record
+}
+
+// finish completes record with the outcome of the request and writes it.
+func (l *auditLog) finish(record *AuditRecord, output string, outputTokens int, tools []string, reason string, err error) {
+	record.DurationMS = time.Since(record.Timestamp).Milliseconds()
+	record.OutputTokens = outputTokens
+	record.ToolsCalled = tools
+	record.FinishReason = reason
+	if err != nil {
+		record.FinishReason = AuditFinishError
+		record.Error = err.Error()
+	} else {
+		if l.opts.IncludeContent {
+			record.Output = output
+		}
+		if l.opts.HashContent {
+			record.OutputHash = contentHash([]byte(output))
+		}
+	}
+	l.write(record)
+}
+
+// write writes record as one JSON line, leaving out excluded fields.
+func (l *auditLog) write(record *AuditRecord) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	if len(l.exclude) > 0 {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return
+		}
+		for field := range l.exclude {
+			delete(fields, field)
+		}
+		if data, err = json.Marshal(fields); err != nil {
+			return
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writer.Write(append(data, '\n'))
+}
+
+// auditMessages returns the text content of messages.
+func auditMessages(messages []core.Message) []AuditMessage {
+	result := make([]AuditMessage, len(messages))
+	for i, msg := range messages {
+		var text strings.Builder
+		for _, part := range msg.Parts {
+			if t, ok := part.(core.Text); ok {
+				text.WriteString(t.Text)
+			}
+		}
+		result[i] = AuditMessage{Role: string(msg.Role), Text: text.String()}
+	}
+	return result
+}
+
+// contentHash returns the SHA-256 hash of data.
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// objectOutput serializes a generated object for the log.
+func objectOutput(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// toolsCalled returns the names of the tools called in steps, in order of
+// first use.
+func toolsCalled(steps []core.Step) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, step := range steps {
+		for _, call := range step.ToolCalls {
+			if !seen[call.Name] {
+				seen[call.Name] = true
+				names = append(names, call.Name)
+			}
+		}
+	}
+	return names
+}
+
+// finishReason derives the finish reason of a successful run from its steps.
+func finishReason(steps []core.Step) string {
+	if len(steps) > 0 && len(steps[len(steps)-1].ToolCalls) > 0 {
+		return AuditFinishToolCalls
+	}
+	return AuditFinishStop
+}
+
+// auditedStream logs a text stream when it finishes, fails or is closed,
+// whichever happens first.
+type auditedStream struct {
+	core.TextStream
+	log       *auditLog
+	record    *AuditRecord
+	writeOnce sync.Once
+
+	mu    sync.Mutex // guards steps, which Close may read mid-stream
+	steps core.StepAccumulator
+}
+
+// observe records each event and writes the record at the end of the
+// stream.
+func (s *auditedStream) observe(event core.Event) {
+	s.mu.Lock()
+	s.steps.Add(event)
+	s.mu.Unlock()
+	if event.Type == core.EventFinish || event.Type == core.EventError {
+		s.finish()
+	}
+}
+
+// Close implements core.TextStream.
+func (s *auditedStream) Close() error {
+	s.finish()
+	return s.TextStream.Close()
+}
+
+// finish writes the record once.
+func (s *auditedStream) finish() {
+	s.writeOnce.Do(func() {
+		s.mu.Lock()
+		result := s.steps.Result()
+		reason := AuditFinishIncomplete
+		failed, err := s.steps.Failed()
+		switch {
+		case failed:
+			reason = AuditFinishError
+		case s.steps.Finished():
+			reason = finishReason(result.Steps)
+		}
+		s.mu.Unlock()
+		s.log.finish(s.record, result.Text, result.Usage.OutputTokens, toolsCalled(result.Steps), reason, err)
+	})
+}
+
+// auditedObjectStream logs an object stream when its final value is read or
+// it is closed, whichever happens first.
+type auditedObjectStream struct {
+	core.ObjectStream[any]
+	log       *auditLog
+	record    *AuditRecord
+	writeOnce sync.Once
+}
+
+// Final implements core.ObjectStream.
+func (s *auditedObjectStream) Final() (*any, error) {
+	value, err := s.ObjectStream.Final()
+	s.writeOnce.Do(func() {
+		var output string
+		if value != nil {
+			output = objectOutput(*value)
+		}
+		s.log.finish(s.record, output, 0, nil, AuditFinishStop, err)
+	})
+	return value, err
+}
+
+// Close implements core.TextStream.
+func (s *auditedObjectStream) Close() error {
+	s.writeOnce.Do(func() {
+		s.log.finish(s.record, "", 0, nil, AuditFinishIncomplete, nil)
+	})
+	return s.ObjectStream.Close()
+}
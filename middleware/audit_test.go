@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+// auditRequest is a request with personal data in its content.
+var auditRequest = core.Request{
+	RequestID: "req_1",
+	Model:     "gpt-4o-mini",
+	Messages: []core.Message{
+		{Role: core.User, Parts: []core.Part{core.Text{Text: "My SSN is 123-45-6789"}}},
+	},
+}
+
+// readAuditRecords parses the JSON lines written to buf.
+func readAuditRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid audit line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestWithAuditLog_GenerateText(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return &core.TextResult{
+				Text: "Done",
+				Steps: []core.Step{
+					{ToolCalls: []core.ToolCall{{Name: "lookup"}, {Name: "lookup"}}},
+					{Text: "Done"},
+				},
+				Usage: core.Usage{OutputTokens: 12},
+			}, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	provider := WithAuditLog(&buf, AuditLogOpts{})(mock)
+	if _, err := provider.GenerateText(context.Background(), auditRequest); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	records := readAuditRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	r := records[0]
+	if r["version"] != float64(AuditLogVersion) || r["request_id"] != "req_1" || r["model"] != "gpt-4o-mini" ||
+		r["operation"] != "generate_text" || r["message_count"] != float64(1) || r["output_tokens"] != float64(12) ||
+		r["finish_reason"] != AuditFinishStop {
+		t.Errorf("unexpected record: %v", r)
+	}
+	if tools, _ := r["tools_called"].([]any); len(tools) != 1 || tools[0] != "lookup" {
+		t.Errorf("tools_called = %v, want [lookup]", r["tools_called"])
+	}
+	if strings.Contains(buf.String(), "123-45-6789") || strings.Contains(buf.String(), "Done") {
+		t.Error("content recorded without IncludeContent")
+	}
+}
+
+func TestWithAuditLog_ContentOptions(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return &core.TextResult{Text: "Noted"}, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	provider := WithAuditLog(&buf, AuditLogOpts{HashContent: true, Exclude: []string{"request_id"}})(mock)
+	provider.GenerateText(context.Background(), auditRequest)
+
+	r := readAuditRecords(t, &buf)[0]
+	if strings.Contains(buf.String(), "123-45-6789") {
+		t.Error("content recorded with HashContent only")
+	}
+	if hash, _ := r["input_hash"].(string); !strings.HasPrefix(hash, "sha256:") {
+		t.Errorf("input_hash = %v", r["input_hash"])
+	}
+	if _, ok := r["request_id"]; ok {
+		t.Error("excluded field request_id was recorded")
+	}
+
+	buf.Reset()
+	provider = WithAuditLog(&buf, AuditLogOpts{IncludeContent: true})(mock)
+	provider.GenerateText(context.Background(), auditRequest)
+
+	r = readAuditRecords(t, &buf)[0]
+	if r["output"] != "Noted" || !strings.Contains(buf.String(), "123-45-6789") {
+		t.Errorf("content not recorded with IncludeContent: %v", r)
+	}
+}
+
+func TestWithAuditLog_Error(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return nil, errors.New("rate limited")
+		},
+	}
+
+	var buf bytes.Buffer
+	WithAuditLog(&buf, AuditLogOpts{})(mock).GenerateText(context.Background(), auditRequest)
+
+	r := readAuditRecords(t, &buf)[0]
+	if r["finish_reason"] != AuditFinishError || r["error"] != "rate limited" {
+		t.Errorf("unexpected record: %v", r)
+	}
+}
+
+func TestWithAuditLog_StreamText(t *testing.T) {
+	events := make(chan core.Event, 3)
+	events <- core.Event{Type: core.EventTextDelta, TextDelta: "Hi"}
+	events <- core.Event{Type: core.EventFinish, Usage: &core.Usage{OutputTokens: 2}}
+	close(events)
+
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			return &mockTextStream{events: events}, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	stream, err := WithAuditLog(&buf, AuditLogOpts{IncludeContent: true})(mock).StreamText(context.Background(), auditRequest)
+	if err != nil {
+		t.Fatalf("StreamText failed: %v", err)
+	}
+	for range stream.Events() {
+	}
+	stream.Close()
+
+	r := readAuditRecords(t, &buf)[0]
+	if r["operation"] != "stream_text" || r["output"] != "Hi" || r["output_tokens"] != float64(2) || r["finish_reason"] != AuditFinishStop {
+		t.Errorf("unexpected record: %v", r)
+	}
+}
@@ -0,0 +1,249 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/recera/gai/core"
+)
+
+// BudgetExceededError is returned by WithTokenBudgetPerModel when a request
+// would take a model past its output token budget. It is not transient, so
+// WithRetry does not retry it.
+type BudgetExceededError struct {
+	// Model is the request's model, "" for the provider's default model
+	Model string
+	// Budget is the model's output token budget
+	Budget int
+	// Used is the number of output tokens consumed since the last reset
+	Used int
+	// Requested is the request's output token limit, or 0 if the budget was already spent
+	Requested int
+}
+
+// Error implements the error interface.
+func (e *BudgetExceededError) Error() string {
+	if e.Requested > 0 {
+		return fmt.Sprintf("token budget exceeded for model %q: %d of %d output tokens used, request allows %d more",
+			e.Model, e.Used, e.Budget, e.Requested)
+	}
+	return fmt.Sprintf("token budget exceeded for model %q: %d of %d output tokens used", e.Model, e.Used, e.Budget)
+}
+
+// TokenBudgetReporter is implemented by providers wrapped with
+// WithTokenBudgetPerModel.
+type TokenBudgetReporter interface {
+	// Remaining returns the output tokens left in the model's budget, or -1
+	// if the model has no budget
+	Remaining(model string) int
+	// ResetBudget sets the model's consumed output tokens back to zero
+	ResetBudget(model string)
+}
+
+// tokenBudgetMiddleware rejects requests once a model's output token budget
+// is spent.
+type tokenBudgetMiddleware struct {
+	baseMiddleware
+	budgets map[string]int
+	used    map[string]*atomic.Int64
+}
+
+// WithTokenBudgetPerModel creates middleware that caps the cumulative output
+// tokens of each model. Requests are keyed by core.Request.Model; requests
+// that leave Model empty use the provider's default model and are keyed by
+// the empty string "". Models that are not present in budgets, or that have a
+// budget of zero or less, flow through unrestricted.
+//
+// A request fails with *BudgetExceededError when the budget is already spent,
+// or when its MaxTokens would take the model past the budget. Output tokens
+// are counted from the response usage; for streams, from the usage on the
+// finish event. Text results are charged to the model that served them
+// (see core.ServedModel), so a dated version such as "gpt-4o-2024-08-06"
+// counts against the "gpt-4o" budget and an automatically selected model
+// against its own budget; results from a model with no budget, and streams,
+// are charged to the request's model. Counters are kept until ResetBudget is called, so the caller
+// decides the window, e.g. by resetting on a daily timer.
+//
+// The wrapped provider implements TokenBudgetReporter.
+//
+// Example:
+//
+//	provider = middleware.WithTokenBudgetPerModel(map[string]int{
+//	    "gpt-4o":      100_000,
+//	    "gpt-4o-mini": 1_000_000,
+//	})(provider)
+//	remaining := provider.(middleware.TokenBudgetReporter).Remaining("gpt-4o")
+func WithTokenBudgetPerModel(budgets map[string]int) Middleware {
+	return func(provider core.Provider) core.Provider {
+		// Copy budgets so later changes by the caller don't race with requests
+		m := &tokenBudgetMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			budgets:        make(map[string]int, len(budgets)),
+			used:           make(map[string]*atomic.Int64, len(budgets)),
+		}
+		for model, budget := range budgets {
+			if budget > 0 {
+				m.budgets[model] = budget
+				m.used[model] = new(atomic.Int64)
+			}
+		}
+		return m
+	}
+}
+
+// Remaining implements TokenBudgetReporter.
+func (m *tokenBudgetMiddleware) Remaining(model string) int {
+	budget, ok := m.budgets[model]
+	if !ok {
+		return -1
+	}
+	return max(budget-int(m.used[model].Load()), 0)
+}
+
+// ResetBudget implements TokenBudgetReporter.
+func (m *tokenBudgetMiddleware) ResetBudget(model string) {
+	if used, ok := m.used[model]; ok {
+		used.Store(0)
+	}
+}
+
+// check returns a BudgetExceededError if req cannot run within its model's
+// budget.
+func (m *tokenBudgetMiddleware) check(req core.Request) error {
+	budget, ok := m.budgets[req.Model]
+	if !ok {
+		return nil
+	}
+	used := int(m.used[req.Model].Load())
+	requested := core.OutputTokenLimit(req)
+	if used >= budget || (requested > 0 && used+requested > budget) {
+		return &BudgetExceededError{Model: req.Model, Budget: budget, Used: used, Requested: requested}
+	}
+	return nil
+}
+
+// budgetKey returns the budget that covers model: an exact match, or the
+// longest budgeted model that model extends with a dated version suffix,
+// such as "gpt-4o" for "gpt-4o-2024-08-06". It returns false if no budget
+// covers model.
+func (m *tokenBudgetMiddleware) budgetKey(model string) (string, bool) {
+	if _, ok := m.budgets[model]; ok {
+		return model, true
+	}
+	key, found := "", false
+	for k := range m.budgets {
+		if len(k) <= len(key) || len(model) < len(k)+2 || model[:len(k)] != k {
+			continue
+		}
+		if model[len(k)] == '-' && model[len(k)+1] >= '0' && model[len(k)+1] <= '9' {
+			key, found = k, true
+		}
+	}
+	return key, found
+}
+
+// served returns the budget key to charge for result: the budget covering
+// the model that served req, or req.Model if that model has no budget.
+func (m *tokenBudgetMiddleware) served(req core.Request, result *core.TextResult) string {
+	if key, ok := m.budgetKey(core.ServedModel(req, result)); ok {
+		return key
+	}
+	return req.Model
+}
+
+// consume adds output tokens to the model's counter.
+func (m *tokenBudgetMiddleware) consume(model string, tokens int) {
+	if used, ok := m.used[model]; ok && tokens > 0 {
+		used.Add(int64(tokens))
+	}
+}
+
+// observer returns a Tee callback that consumes the usage on a finish event.
+func (m *tokenBudgetMiddleware) observer(model string) func(core.Event) {
+	return func(e core.Event) {
+		if e.Type == core.EventFinish && e.Usage != nil {
+			m.consume(model, e.Usage.OutputTokens)
+		}
+	}
+}
+
+// GenerateText implements the Provider interface with per-model token budgets.
+func (m *tokenBudgetMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	result, err := m.provider.GenerateText(ctx, req)
+	if result != nil {
+		m.consume(m.served(req, result), result.Usage.OutputTokens)
+	}
+	return result, err
+}
+
+// StreamText implements the Provider interface with per-model token budgets.
+func (m *tokenBudgetMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	stream, err := m.provider.StreamText(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := m.budgets[req.Model]; !ok {
+		return stream, nil
+	}
+	return core.Tee(stream, m.observer(req.Model)), nil
+}
+
+// GenerateObject implements the Provider interface with per-model token budgets.
+func (m *tokenBudgetMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	result, err := m.provider.GenerateObject(ctx, req, schema)
+	if result != nil {
+		m.consume(req.Model, result.Usage.OutputTokens)
+	}
+	return result, err
+}
+
+// StreamObject implements the Provider interface with per-model token budgets.
+func (m *tokenBudgetMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	stream, err := m.provider.StreamObject(ctx, req, schema)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := m.budgets[req.Model]; !ok {
+		return stream, nil
+	}
+	return &teeObjectStream{ObjectStream: stream, observe: m.observer(req.Model)}, nil
+}
+
+// teeObjectStream passes an object stream's events to observe. The
+// events are only observed if the consumer reads them, so callers that only
+// call Final are not slowed down by an unread buffer.
+type teeObjectStream struct {
+	core.ObjectStream[any]
+	observe func(core.Event)
+	once    sync.Once
+	tee     core.TextStream
+}
+
+// Events returns the stream's events, observing each one.
+func (s *teeObjectStream) Events() <-chan core.Event {
+	s.once.Do(func() { s.tee = core.Tee(s.ObjectStream, s.observe) })
+	return s.tee.Events()
+}
+
+// Close closes the underlying stream.
+func (s *teeObjectStream) Close() error {
+	s.once.Do(func() {})
+	if s.tee != nil {
+		return s.tee.Close()
+	}
+	return s.ObjectStream.Close()
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+func TestTokenBudgetPerModel_GenerateText(t *testing.T) {
+	calls := 0
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			calls++
+			return &core.TextResult{Text: "ok", Usage: core.Usage{InputTokens: 50, OutputTokens: 40}}, nil
+		},
+	}
+	provider := WithTokenBudgetPerModel(map[string]int{"gpt-4o": 100})(mock)
+	reporter := provider.(TokenBudgetReporter)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := provider.GenerateText(ctx, core.Request{Model: "gpt-4o"}); err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+	if got := reporter.Remaining("gpt-4o"); got != 0 {
+		t.Errorf("Remaining = %d, want 0", got)
+	}
+
+	_, err := provider.GenerateText(ctx, core.Request{Model: "gpt-4o"})
+	var budgetErr *BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("expected BudgetExceededError, got %v", err)
+	}
+	if budgetErr.Model != "gpt-4o" || budgetErr.Budget != 100 || budgetErr.Used != 120 {
+		t.Errorf("unexpected error fields: %+v", budgetErr)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 provider calls, got %d", calls)
+	}
+
+	// Models without a budget are unrestricted
+	if _, err := provider.GenerateText(ctx, core.Request{Model: "gpt-4o-mini"}); err != nil {
+		t.Errorf("unbudgeted model failed: %v", err)
+	}
+	if got := reporter.Remaining("gpt-4o-mini"); got != -1 {
+		t.Errorf("Remaining for unbudgeted model = %d, want -1", got)
+	}
+
+	reporter.ResetBudget("gpt-4o")
+	if got := reporter.Remaining("gpt-4o"); got != 100 {
+		t.Errorf("Remaining after reset = %d, want 100", got)
+	}
+	if _, err := provider.GenerateText(ctx, core.Request{Model: "gpt-4o"}); err != nil {
+		t.Errorf("request after reset failed: %v", err)
+	}
+}
+
+func TestTokenBudgetPerModel_ServedModel(t *testing.T) {
+	served := ""
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return &core.TextResult{Text: "ok", ModelUsed: served, Usage: core.Usage{OutputTokens: 10}}, nil
+		},
+	}
+	provider := WithTokenBudgetPerModel(map[string]int{"gpt-4o": 100, "gpt-4o-mini": 100, "": 100})(mock)
+	reporter := provider.(TokenBudgetReporter)
+	ctx := context.Background()
+
+	tests := []struct {
+		model  string
+		served string
+		want   string
+	}{
+		{"gpt-4o", "gpt-4o-2024-08-06", "gpt-4o"},
+		{core.AutoModel, "gpt-4o-mini-2024-07-18", "gpt-4o-mini"},
+		{"", "gpt-4o", "gpt-4o"},
+		{"", "o1-preview", ""},
+	}
+	for _, tt := range tests {
+		served = tt.served
+		before := reporter.Remaining(tt.want)
+		if _, err := provider.GenerateText(ctx, core.Request{Model: tt.model}); err != nil {
+			t.Fatalf("GenerateText(%q) failed: %v", tt.model, err)
+		}
+		if got := reporter.Remaining(tt.want); got != before-10 {
+			t.Errorf("served %q: Remaining(%q) = %d, want %d", tt.served, tt.want, got, before-10)
+		}
+	}
+}
+
+func TestTokenBudgetPerModel_MaxTokens(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return &core.TextResult{Usage: core.Usage{OutputTokens: 60}}, nil
+		},
+	}
+	provider := WithTokenBudgetPerModel(map[string]int{"": 100})(mock)
+	ctx := context.Background()
+
+	if _, err := provider.GenerateText(ctx, core.Request{MaxTokens: 60}); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	_, err := provider.GenerateText(ctx, core.Request{MaxTokens: 50})
+	var budgetErr *BudgetExceededError
+	if !errors.As(err, &budgetErr) || budgetErr.Requested != 50 {
+		t.Fatalf("expected BudgetExceededError for MaxTokens, got %v", err)
+	}
+	if _, err := provider.GenerateText(ctx, core.Request{MaxTokens: 40}); err != nil {
+		t.Errorf("request within budget failed: %v", err)
+	}
+}
+
+func TestTokenBudgetPerModel_StreamText(t *testing.T) {
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			events := make(chan core.Event, 2)
+			events <- core.Event{Type: core.EventTextDelta, TextDelta: "hi"}
+			events <- core.Event{Type: core.EventFinish, Usage: &core.Usage{OutputTokens: 30}}
+			close(events)
+			return &mockTextStream{events: events}, nil
+		},
+	}
+	provider := WithTokenBudgetPerModel(map[string]int{"gpt-4o": 100})(mock)
+
+	stream, err := provider.StreamText(context.Background(), core.Request{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("StreamText failed: %v", err)
+	}
+	for range stream.Events() {
+	}
+	stream.Close()
+
+	if got := provider.(TokenBudgetReporter).Remaining("gpt-4o"); got != 70 {
+		t.Errorf("Remaining = %d, want 70", got)
+	}
+}
+
+func TestTokenBudgetPerModel_Concurrent(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return &core.TextResult{Usage: core.Usage{OutputTokens: 1}}, nil
+		},
+	}
+	provider := WithTokenBudgetPerModel(map[string]int{"gpt-4o": 1000})(mock)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			provider.GenerateText(context.Background(), core.Request{Model: "gpt-4o"})
+		}()
+	}
+	wg.Wait()
+
+	if got := provider.(TokenBudgetReporter).Remaining("gpt-4o"); got != 900 {
+		t.Errorf("Remaining = %d, want 900", got)
+	}
+}
+
+func TestTokenBudgetPerModel_NotRetried(t *testing.T) {
+	err := &BudgetExceededError{Model: "gpt-4o", Budget: 10, Used: 10}
+	if core.IsTransient(err) || core.IsRateLimited(err) {
+		t.Error("BudgetExceededError should not be retryable")
+	}
+}
@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// ResponseCacheStore stores text results for WithResponseCache.
+// Implementations must be safe for concurrent use.
+type ResponseCacheStore interface {
+	// Get returns the cached result for key, if present and not expired
+	Get(key string) (*core.TextResult, bool)
+	// Set stores result under key; a ttl of zero or less never expires
+	Set(key string, result *core.TextResult, ttl time.Duration)
+}
+
+// DefaultCacheKey returns the SHA-256 of the request with the fields that do
+// not affect the response left out: request IDs, metadata, retry and timeout
+// settings, Stream and AutoFetchImages. Tools are keyed by name, description
+// and input schema, and the system prompt and deprecated MaxTokens are
+// normalized so equivalent requests share a key. Fields added to
+// core.Request are part of the key unless excluded here.
+func DefaultCacheKey(req core.Request) string {
+	type toolKey struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Schema      json.RawMessage `json:"schema,omitempty"`
+	}
+
+	normalized := core.ApplySystemPrompt(req)
+	normalized.MaxOutputTokens = core.OutputTokenLimit(req)
+	normalized.MaxTokens = 0
+	normalized.RequestID = ""
+	normalized.IdempotencyKey = ""
+	normalized.Metadata = nil
+	normalized.Stream = false
+	normalized.MaxRetries = 0
+	normalized.MaxStructuredRetries = 0
+	normalized.AbortAfter = 0
+	normalized.FirstTokenTimeout = 0
+	normalized.AutoFetchImages = false
+	normalized.Tools = nil
+
+	key := struct {
+		core.Request
+		ToolDefinitions []toolKey `json:"tool_definitions,omitempty"`
+	}{Request: normalized}
+	for _, tool := range req.Tools {
+		key.ToolDefinitions = append(key.ToolDefinitions, toolKey{
+			Name:        tool.Name(),
+			Description: tool.Description(),
+			Schema:      tool.InSchemaJSON(),
+		})
+	}
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		// Requests that cannot be serialized are not cached
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// responseCacheMiddleware serves repeated GenerateText requests from a cache.
+type responseCacheMiddleware struct {
+	baseMiddleware
+	store ResponseCacheStore
+	keyFn func(core.Request) string
+	ttl   time.Duration
+}
+
+// WithResponseCache creates middleware that caches GenerateText results by
+// exact request match, which suits deterministic requests such as those with
+// temperature 0. keyFn computes the cache key (DefaultCacheKey if nil); an
+// empty key skips the cache for that request. Cached results are returned
+// with FromCache set. Streaming and object requests are not cached.
+//
+// Example:
+//
+//	provider = middleware.WithResponseCache(
+//	    middleware.InMemoryResponseCache(1000), nil, time.Hour,
+//	)(provider)
+func WithResponseCache(store ResponseCacheStore, keyFn func(core.Request) string, ttl time.Duration) Middleware {
+	if keyFn == nil {
+		keyFn = DefaultCacheKey
+	}
+
+	return func(provider core.Provider) core.Provider {
+		return &responseCacheMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			store:          store,
+			keyFn:          keyFn,
+			ttl:            ttl,
+		}
+	}
+}
+
+// GenerateText implements the Provider interface with response caching.
+func (m *responseCacheMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	key := m.keyFn(req)
+	if key == "" {
+		return m.provider.GenerateText(ctx, req)
+	}
+
+	if cached, ok := m.store.Get(key); ok {
+		result := *cached
+		result.FromCache = true
+		return &result, nil
+	}
+
+	result, err := m.provider.GenerateText(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := *result
+	stored.FromCache = false
+	m.store.Set(key, &stored, m.ttl)
+	return result, nil
+}
+
+// MemoryResponseCache is an in-memory ResponseCacheStore that evicts the
+// least recently used entry when full.
+type MemoryResponseCache struct {
+	mu      sync.Mutex
+	maxSize int
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+// cacheEntry is a cached result and its expiry.
+type cacheEntry struct {
+	key       string
+	result    *core.TextResult
+	expiresAt time.Time // zero means no expiry
+}
+
+// InMemoryResponseCache creates an LRU cache holding at most maxSize results
+// (1000 if maxSize is zero or less).
+func InMemoryResponseCache(maxSize int) *MemoryResponseCache {
+	if maxSize <= 0 {
+		maxSize = 1000
+	}
+	return &MemoryResponseCache{
+		maxSize: maxSize,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get implements ResponseCacheStore.
+func (c *MemoryResponseCache) Get(key string) (*core.TextResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.result, true
+}
+
+// Set implements ResponseCacheStore.
+func (c *MemoryResponseCache) Set(key string, result *core.TextResult, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, result: result}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of cached entries, including expired ones not yet removed.
+func (c *MemoryResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+func cacheRequest(text string) core.Request {
+	return core.Request{
+		Model: "test-model",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: text}}},
+		},
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	provider := &mockProvider{}
+	cached := WithResponseCache(InMemoryResponseCache(10), nil, time.Minute)(provider)
+	ctx := context.Background()
+
+	first, err := cached.GenerateText(ctx, cacheRequest("hello"))
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if first.FromCache {
+		t.Error("Expected first result not to come from cache")
+	}
+
+	second, err := cached.GenerateText(ctx, cacheRequest("hello"))
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if !second.FromCache || second.Text != first.Text {
+		t.Errorf("Expected cached result, got %+v", second)
+	}
+	if provider.getCallCount() != 1 {
+		t.Errorf("Expected 1 provider call, got %d", provider.getCallCount())
+	}
+
+	// Metadata does not affect the default key
+	req := cacheRequest("hello")
+	req.Metadata = map[string]any{"request": "other"}
+	if result, _ := cached.GenerateText(ctx, req); !result.FromCache {
+		t.Error("Expected metadata to be ignored by the cache key")
+	}
+
+	// A different message misses the cache
+	if result, _ := cached.GenerateText(ctx, cacheRequest("goodbye")); result.FromCache {
+		t.Error("Expected different request to miss the cache")
+	}
+	if provider.getCallCount() != 2 {
+		t.Errorf("Expected 2 provider calls, got %d", provider.getCallCount())
+	}
+}
+
+func TestDefaultCacheKey(t *testing.T) {
+	base := DefaultCacheKey(cacheRequest("hello"))
+	logprobs := 3
+
+	// Fields that change the response change the key
+	differs := map[string]func(*core.Request){
+		"response schema":   func(r *core.Request) { r.ResponseSchema = json.RawMessage(`{"type":"object"}`) },
+		"n":                 func(r *core.Request) { r.N = 2 },
+		"top logprobs":      func(r *core.Request) { r.TopLogprobs = &logprobs },
+		"extended thinking": func(r *core.Request) { r.ExtendedThinking = true },
+		"predicted output":  func(r *core.Request) { r.PredictedOutput = "draft" },
+		"auto select model": func(r *core.Request) { r.AutoSelectModel = true },
+	}
+	for name, change := range differs {
+		req := cacheRequest("hello")
+		change(&req)
+		if DefaultCacheKey(req) == base {
+			t.Errorf("%s: expected a different key", name)
+		}
+	}
+
+	// Fields that only identify or bound the call do not
+	same := map[string]func(*core.Request){
+		"request id":  func(r *core.Request) { r.RequestID = "req_1" },
+		"metadata":    func(r *core.Request) { r.Metadata = map[string]any{"user": "ada"} },
+		"max retries": func(r *core.Request) { r.MaxRetries = 5 },
+		"abort after": func(r *core.Request) { r.AbortAfter = time.Minute },
+	}
+	for name, change := range same {
+		req := cacheRequest("hello")
+		change(&req)
+		if DefaultCacheKey(req) != base {
+			t.Errorf("%s: expected the same key", name)
+		}
+	}
+
+	// The deprecated MaxTokens and MaxOutputTokens are equivalent
+	legacy, current := cacheRequest("hello"), cacheRequest("hello")
+	legacy.MaxTokens = 100
+	current.MaxOutputTokens = 100
+	if DefaultCacheKey(legacy) != DefaultCacheKey(current) {
+		t.Error("Expected MaxTokens and MaxOutputTokens to share a key")
+	}
+}
+
+func TestResponseCacheSkipsErrorsAndEmptyKeys(t *testing.T) {
+	calls := 0
+	provider := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.New("temporary failure")
+			}
+			return &core.TextResult{Text: "ok"}, nil
+		},
+	}
+	store := InMemoryResponseCache(10)
+	keyFn := func(req core.Request) string {
+		if req.Temperature > 0 {
+			return ""
+		}
+		return DefaultCacheKey(req)
+	}
+	cached := WithResponseCache(store, keyFn, 0)(provider)
+	ctx := context.Background()
+
+	if _, err := cached.GenerateText(ctx, cacheRequest("hello")); err == nil {
+		t.Fatal("Expected error")
+	}
+	if store.Len() != 0 {
+		t.Error("Expected errors not to be cached")
+	}
+
+	req := cacheRequest("hello")
+	req.Temperature = 0.7
+	cached.GenerateText(ctx, req)
+	cached.GenerateText(ctx, req)
+	if calls != 3 || store.Len() != 0 {
+		t.Errorf("Expected empty key to bypass the cache, got %d calls and %d entries", calls, store.Len())
+	}
+}
+
+func TestInMemoryResponseCache(t *testing.T) {
+	cache := InMemoryResponseCache(2)
+	cache.Set("a", &core.TextResult{Text: "a"}, 0)
+	cache.Set("b", &core.TextResult{Text: "b"}, 0)
+
+	// Using a makes b the least recently used entry
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+	cache.Set("c", &core.TextResult{Text: "c"}, 0)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if result, ok := cache.Get(key); !ok || result.Text != key {
+			t.Errorf("Expected %s to be cached, got %v", key, result)
+		}
+	}
+
+	cache.Set("d", &core.TextResult{Text: "d"}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.Get("d"); ok {
+		t.Error("Expected expired entry to be removed")
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Expected 1 entry after expiry, got %d", cache.Len())
+	}
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+
+	"github.com/recera/gai/core"
+)
+
+// concurrencyMiddleware limits the number of in-flight requests per model.
+type concurrencyMiddleware struct {
+	baseMiddleware
+	semaphores map[string]chan struct{}
+}
+
+// WithConcurrencyLimitPerModel creates middleware that caps the number of
+// concurrent requests for each model. Requests are keyed by the model they
+// run on: when the wrapped provider implements core.ModelResolver, aliases,
+// automatic selection and an empty Model (the provider's default) are
+// resolved first, so they share the limit of the model they map to. Other
+// providers are keyed by core.Request.Model as given. Models that are not
+// present in limits, or that have a limit of zero or less, flow through
+// unrestricted.
+//
+// For streaming calls the slot is held until the stream finishes or fails,
+// the final object is read, or the stream is closed, whichever comes first.
+//
+// Example:
+//
+//	provider = middleware.WithConcurrencyLimitPerModel(map[string]int{
+//	    "gpt-4o":      4,
+//	    "gpt-4o-mini": 16,
+//	})(provider)
+func WithConcurrencyLimitPerModel(limits map[string]int) Middleware {
+	// Copy limits so later changes by the caller don't race with requests
+	semaphores := make(map[string]chan struct{}, len(limits))
+	for model, limit := range limits {
+		if limit > 0 {
+			semaphores[model] = make(chan struct{}, limit)
+		}
+	}
+
+	return func(provider core.Provider) core.Provider {
+		return &concurrencyMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			semaphores:     semaphores,
+		}
+	}
+}
+
+// acquire reserves a slot for the request's model, blocking until one is free
+// or the context is done. The returned function releases the slot and may be
+// called more than once.
+func (m *concurrencyMiddleware) acquire(ctx context.Context, req core.Request) (func(), error) {
+	sem, ok := m.semaphores[requestModel(ctx, m.provider, req)]
+	if !ok {
+		return func() {}, nil
+	}
+
+	select {
+	case sem <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-sem }) }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// GenerateText implements the Provider interface with per-model concurrency limits.
+func (m *concurrencyMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	release, err := m.acquire(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return m.provider.GenerateText(ctx, req)
+}
+
+// StreamText implements the Provider interface with per-model concurrency limits.
+func (m *concurrencyMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	release, err := m.acquire(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := m.provider.StreamText(ctx, req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// Release as soon as the stream ends rather than waiting for Close
+	stream = core.Tee(stream, func(event core.Event) {
+		if event.Type == core.EventFinish || event.Type == core.EventError {
+			release()
+		}
+	})
+	return &concurrencyTextStream{TextStream: stream, release: release}, nil
+}
+
+// GenerateObject implements the Provider interface with per-model concurrency limits.
+func (m *concurrencyMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	release, err := m.acquire(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return m.provider.GenerateObject(ctx, req, schema)
+}
+
+// StreamObject implements the Provider interface with per-model concurrency limits.
+func (m *concurrencyMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	release, err := m.acquire(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := m.provider.StreamObject(ctx, req, schema)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return &concurrencyObjectStream{ObjectStream: stream, release: release}, nil
+}
+
+// concurrencyTextStream releases its concurrency slot when closed, if the
+// end of the stream has not released it already.
+type concurrencyTextStream struct {
+	core.TextStream
+	release func()
+}
+
+// Close closes the underlying stream and releases the slot.
+func (s *concurrencyTextStream) Close() error {
+	defer s.release()
+	return s.TextStream.Close()
+}
+
+// concurrencyObjectStream releases its concurrency slot when the final
+// object is read or the stream is closed.
+type concurrencyObjectStream struct {
+	core.ObjectStream[any]
+	release func()
+}
+
+// Final returns the final object and releases the slot.
+func (s *concurrencyObjectStream) Final() (*any, error) {
+	defer s.release()
+	return s.ObjectStream.Final()
+}
+
+// Close closes the underlying stream and releases the slot.
+func (s *concurrencyObjectStream) Close() error {
+	defer s.release()
+	return s.ObjectStream.Close()
+}
+
+// requestModel returns the model req runs on, as reported by the innermost
+// provider that implements core.ModelResolver, looking through middleware
+// from this package. It is req.Model when no provider reports one.
+func requestModel(ctx context.Context, provider core.Provider, req core.Request) string {
+	for {
+		if r, ok := provider.(core.ModelResolver); ok {
+			return r.RequestModel(ctx, req)
+		}
+		w, ok := provider.(interface{ wrapped() core.Provider })
+		if !ok {
+			return req.Model
+		}
+		provider = w.wrapped()
+	}
+}
@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+func TestConcurrencyLimitPerModel_EnforcesLimits(t *testing.T) {
+	var mu sync.Mutex
+	inFlight := map[string]int{}
+	maxInFlight := map[string]int{}
+
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			mu.Lock()
+			inFlight[req.Model]++
+			if inFlight[req.Model] > maxInFlight[req.Model] {
+				maxInFlight[req.Model] = inFlight[req.Model]
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			inFlight[req.Model]--
+			mu.Unlock()
+			return &core.TextResult{Text: "success"}, nil
+		},
+	}
+
+	provider := WithConcurrencyLimitPerModel(map[string]int{
+		"gpt-4o":      2,
+		"gpt-4o-mini": 4,
+		"":            1,
+	})(mock)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for _, model := range []string{"gpt-4o", "gpt-4o-mini", "", "unlimited"} {
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(model string) {
+				defer wg.Done()
+				if _, err := provider.GenerateText(ctx, core.Request{Model: model}); err != nil {
+					t.Errorf("request for %q failed: %v", model, err)
+				}
+			}(model)
+		}
+	}
+	wg.Wait()
+
+	tests := []struct {
+		model string
+		max   int
+	}{
+		{"gpt-4o", 2},
+		{"gpt-4o-mini", 4},
+		{"", 1},
+	}
+	for _, tt := range tests {
+		if got := maxInFlight[tt.model]; got > tt.max {
+			t.Errorf("model %q: expected at most %d in flight, got %d", tt.model, tt.max, got)
+		}
+	}
+
+	// Models without a configured limit are not throttled
+	if got := maxInFlight["unlimited"]; got < 2 {
+		t.Errorf("expected unlimited model to run concurrently, max in flight was %d", got)
+	}
+}
+
+func TestConcurrencyLimitPerModel_ContextCancellation(t *testing.T) {
+	block := make(chan struct{})
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			<-block
+			return &core.TextResult{Text: "success"}, nil
+		},
+	}
+
+	provider := WithConcurrencyLimitPerModel(map[string]int{"gpt-4o": 1})(mock)
+
+	// Occupy the only slot
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		provider.GenerateText(context.Background(), core.Request{Model: "gpt-4o"})
+	}()
+
+	// Wait for the first request to reach the provider
+	for i := 0; i < 100 && mock.getCallCount() == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := provider.GenerateText(ctx, core.Request{Model: "gpt-4o"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	close(block)
+	<-done
+}
+
+func TestConcurrencyLimitPerModel_StreamHoldsSlotUntilClose(t *testing.T) {
+	var calls int32
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			atomic.AddInt32(&calls, 1)
+			return &mockTextStream{}, nil
+		},
+	}
+
+	provider := WithConcurrencyLimitPerModel(map[string]int{"gpt-4o": 1})(mock)
+
+	stream, err := provider.StreamText(context.Background(), core.Request{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("first stream failed: %v", err)
+	}
+
+	// Second stream should block while the first is open
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := provider.StreamText(ctx, core.Request{Model: "gpt-4o"}); err == nil {
+		t.Fatal("expected second stream to block until the first is closed")
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	// Closing twice must not release the slot twice
+	stream.Close()
+
+	stream, err = provider.StreamText(context.Background(), core.Request{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("stream after close failed: %v", err)
+	}
+	stream.Close()
+
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("expected 2 provider calls, got %d", calls)
+	}
+}
+
+func TestConcurrencyLimitPerModel_StreamReleasesSlotOnFinish(t *testing.T) {
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			events := make(chan core.Event, 1)
+			events <- core.Event{Type: core.EventFinish}
+			close(events)
+			return &mockTextStream{events: events}, nil
+		},
+	}
+
+	provider := WithConcurrencyLimitPerModel(map[string]int{"gpt-4o": 1})(mock)
+
+	stream, err := provider.StreamText(context.Background(), core.Request{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("first stream failed: %v", err)
+	}
+	for range stream.Events() {
+	}
+
+	// The finished stream no longer holds the slot, although it is not closed
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	second, err := provider.StreamText(ctx, core.Request{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("expected the slot to be released at EventFinish, got %v", err)
+	}
+	second.Close()
+	stream.Close()
+}
+
+// resolvingProvider reports the model requests run on, like real providers.
+type resolvingProvider struct {
+	*mockProvider
+	defaultModel string
+}
+
+func (p *resolvingProvider) RequestModel(ctx context.Context, req core.Request) string {
+	if req.Model == "" {
+		return p.defaultModel
+	}
+	return req.Model
+}
+
+func TestConcurrencyLimitPerModel_ResolvesDefaultModel(t *testing.T) {
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			return &mockTextStream{}, nil
+		},
+	}
+	inner := &resolvingProvider{mockProvider: mock, defaultModel: "gpt-4o"}
+
+	// Resolution looks through other middleware from this package
+	provider := Chain(
+		WithConcurrencyLimitPerModel(map[string]int{"gpt-4o": 1}),
+		WithStreamAccumulator(nil),
+	)(inner)
+
+	stream, err := provider.StreamText(context.Background(), core.Request{Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("first stream failed: %v", err)
+	}
+	defer stream.Close()
+
+	// A request for the default model shares the gpt-4o limit
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := provider.StreamText(ctx, core.Request{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a default-model request to wait for the gpt-4o slot, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+)
+
+// costMiddleware prices completed requests.
+type costMiddleware struct {
+	baseMiddleware
+	pricing obs.CostModel
+}
+
+// WithCost creates middleware that sets TextResult.Cost to the estimated
+// price of each GenerateText call, computed from the result's usage and the
+// model that served it (see core.ServedModel) with pricing, and records it on
+// the current span. A nil pricing uses obs.DefaultCostModel.
+//
+// Example:
+//
+//	provider = middleware.WithCost(obs.DefaultCostModel)(provider)
+//	result, _ := provider.GenerateText(ctx, req)
+//	fmt.Printf("cost: $%.6f\n", result.Cost)
+func WithCost(pricing obs.CostModel) Middleware {
+	if pricing == nil {
+		pricing = obs.DefaultCostModel
+	}
+
+	return func(provider core.Provider) core.Provider {
+		return &costMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			pricing:        pricing,
+		}
+	}
+}
+
+// GenerateText implements the Provider interface with cost estimation.
+func (m *costMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	result, err := m.provider.GenerateText(ctx, req)
+	if err != nil || result == nil {
+		return result, err
+	}
+
+	result.Cost = m.pricing.Cost(core.ServedModel(req, result), result.Usage)
+	obs.RecordCost(obs.SpanFromContext(ctx), result.Cost)
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+)
+
+func TestWithCost(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return &core.TextResult{Text: "ok", Usage: core.Usage{InputTokens: 1000, OutputTokens: 500}}, nil
+		},
+	}
+
+	var pricedModel string
+	pricing := obs.CostModelFunc(func(model string, usage core.Usage) float64 {
+		pricedModel = model
+		return float64(usage.InputTokens)*0.000001 + float64(usage.OutputTokens)*0.000002
+	})
+
+	result, err := WithCost(pricing)(mock).GenerateText(context.Background(), core.Request{Model: "gpt-4o-mini"})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if result.Cost != 0.002 {
+		t.Errorf("Cost = %v, want 0.002", result.Cost)
+	}
+	if pricedModel != "gpt-4o-mini" {
+		t.Errorf("priced model = %q, want gpt-4o-mini", pricedModel)
+	}
+
+	// Automatically selected requests are priced by the model that served them
+	mock.generateTextFunc = func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+		return &core.TextResult{Text: "ok", ModelUsed: "gpt-4o-2024-08-06"}, nil
+	}
+	if _, err := WithCost(pricing)(mock).GenerateText(context.Background(), core.Request{Model: core.AutoModel}); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if pricedModel != "gpt-4o-2024-08-06" {
+		t.Errorf("priced model = %q, want the served model", pricedModel)
+	}
+
+	// Without cost middleware the field stays zero
+	result, _ = mock.GenerateText(context.Background(), core.Request{})
+	if result.Cost != 0 {
+		t.Errorf("Cost without middleware = %v, want 0", result.Cost)
+	}
+}
@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// HealthCheckFn checks that a provider is reachable and responding.
+type HealthCheckFn func(ctx context.Context, provider core.Provider) error
+
+// HealthReporter is implemented by providers wrapped with WithHealthCheck.
+type HealthReporter interface {
+	// Healthy reports whether the most recent health check succeeded
+	Healthy() bool
+	// LastError returns the error from the most recent health check, or nil
+	LastError() error
+	// Close stops the background health checks
+	Close() error
+}
+
+// DefaultHealthCheck sends a minimal one-token request to the provider.
+func DefaultHealthCheck(ctx context.Context, provider core.Provider) error {
+	_, err := provider.GenerateText(ctx, core.Request{
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "ping"}}},
+		},
+		MaxOutputTokens: 1,
+	})
+	return err
+}
+
+// healthCheckMiddleware rejects requests while the provider is unhealthy.
+type healthCheckMiddleware struct {
+	baseMiddleware
+	check    HealthCheckFn
+	interval time.Duration
+
+	mu      sync.RWMutex
+	lastErr error
+
+	startOnce sync.Once
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// WithHealthCheck creates middleware that runs check against the provider in
+// a background goroutine, once on first use and then every interval (30s if
+// interval is zero or less). Each check gets a timeout equal to the interval.
+// While the last check failed, requests fail immediately with an
+// ErrorProviderUnavailable error instead of waiting for a timeout. A nil
+// check uses DefaultHealthCheck.
+//
+// The goroutine starts with the first request or the first call to Healthy
+// or LastError, so building a middleware chain that is never used starts
+// nothing. The wrapped provider implements HealthReporter; call Close to
+// stop checking.
+//
+// Example:
+//
+//	provider = middleware.WithHealthCheck(middleware.DefaultHealthCheck, time.Minute)(provider)
+//	defer provider.(middleware.HealthReporter).Close()
+func WithHealthCheck(check HealthCheckFn, interval time.Duration) Middleware {
+	if check == nil {
+		check = DefaultHealthCheck
+	}
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return func(provider core.Provider) core.Provider {
+		m := &healthCheckMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			check:          check,
+			interval:       interval,
+			stop:           make(chan struct{}),
+		}
+		return m
+	}
+}
+
+// start starts the background health checks the first time it is called,
+// unless Close was called first.
+func (m *healthCheckMiddleware) start() {
+	m.startOnce.Do(func() {
+		select {
+		case <-m.stop:
+		default:
+			go m.run()
+		}
+	})
+}
+
+// run performs health checks until Close is called.
+func (m *healthCheckMiddleware) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.runCheck()
+		select {
+		case <-ticker.C:
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// runCheck performs a single health check and records the result.
+func (m *healthCheckMiddleware) runCheck() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.interval)
+	defer cancel()
+
+	// Stop waiting on the check if the middleware is closed
+	go func() {
+		select {
+		case <-m.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := m.check(ctx, m.provider)
+
+	m.mu.Lock()
+	m.lastErr = err
+	m.mu.Unlock()
+}
+
+// Healthy reports whether the most recent health check succeeded.
+// The provider is considered healthy until the first check completes.
+func (m *healthCheckMiddleware) Healthy() bool {
+	return m.LastError() == nil
+}
+
+// LastError returns the error from the most recent health check, or nil.
+func (m *healthCheckMiddleware) LastError() error {
+	m.start()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastErr
+}
+
+// Close stops the background health checks.
+func (m *healthCheckMiddleware) Close() error {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+	return nil
+}
+
+// checkHealthy returns an error if the provider failed its last health check.
+// It starts the health checks on first use.
+func (m *healthCheckMiddleware) checkHealthy() error {
+	err := m.LastError()
+	if err == nil {
+		return nil
+	}
+	return core.NewError(
+		core.ErrorProviderUnavailable,
+		fmt.Sprintf("provider failed health check: %v", err),
+		core.WithProvider("middleware"),
+		core.WithTemporary(true),
+		core.WithWrapped(err),
+	)
+}
+
+// GenerateText implements the Provider interface with health checking.
+func (m *healthCheckMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	if err := m.checkHealthy(); err != nil {
+		return nil, err
+	}
+	return m.provider.GenerateText(ctx, req)
+}
+
+// StreamText implements the Provider interface with health checking.
+func (m *healthCheckMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	if err := m.checkHealthy(); err != nil {
+		return nil, err
+	}
+	return m.provider.StreamText(ctx, req)
+}
+
+// GenerateObject implements the Provider interface with health checking.
+func (m *healthCheckMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	if err := m.checkHealthy(); err != nil {
+		return nil, err
+	}
+	return m.provider.GenerateObject(ctx, req, schema)
+}
+
+// StreamObject implements the Provider interface with health checking.
+func (m *healthCheckMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	if err := m.checkHealthy(); err != nil {
+		return nil, err
+	}
+	return m.provider.StreamObject(ctx, req, schema)
+}
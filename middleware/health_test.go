@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+func TestHealthCheckMiddleware(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return &core.TextResult{Text: "ok"}, nil
+		},
+	}
+
+	var failing atomic.Bool
+	var checks atomic.Int32
+	check := func(ctx context.Context, p core.Provider) error {
+		checks.Add(1)
+		if failing.Load() {
+			return errors.New("connection reset")
+		}
+		return nil
+	}
+
+	provider := WithHealthCheck(check, 10*time.Millisecond)(mock)
+	reporter := provider.(HealthReporter)
+	defer reporter.Close()
+
+	waitFor := func(cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatal("timed out waiting for health check")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// Checks start on first use
+	time.Sleep(20 * time.Millisecond)
+	if checks.Load() != 0 {
+		t.Fatal("Expected no health checks before first use")
+	}
+	reporter.Healthy()
+	waitFor(func() bool { return checks.Load() > 0 })
+	if !reporter.Healthy() {
+		t.Fatalf("Expected healthy provider, last error: %v", reporter.LastError())
+	}
+	if _, err := provider.GenerateText(context.Background(), core.Request{}); err != nil {
+		t.Fatalf("Expected request to succeed: %v", err)
+	}
+
+	// Unhealthy providers fail fast with ErrorProviderUnavailable
+	failing.Store(true)
+	waitFor(func() bool { return !reporter.Healthy() })
+
+	_, err := provider.GenerateText(context.Background(), core.Request{})
+	var aiErr *core.AIError
+	if !errors.As(err, &aiErr) || aiErr.Code != core.ErrorProviderUnavailable {
+		t.Fatalf("Expected provider unavailable error, got %v", err)
+	}
+	if _, err := provider.StreamText(context.Background(), core.Request{}); err == nil {
+		t.Error("Expected StreamText to fail while unhealthy")
+	}
+	if mock.getCallCount() != 1 {
+		t.Errorf("Expected unhealthy requests not to reach the provider, got %d calls", mock.getCallCount())
+	}
+
+	// Recovery is picked up by the next check
+	failing.Store(false)
+	waitFor(reporter.Healthy)
+
+	// No more checks run after Close
+	reporter.Close()
+	time.Sleep(20 * time.Millisecond)
+	count := checks.Load()
+	time.Sleep(30 * time.Millisecond)
+	if checks.Load() != count {
+		t.Error("Expected health checks to stop after Close")
+	}
+}
+
+func TestHealthCheckMiddlewareClosedBeforeUse(t *testing.T) {
+	var checks atomic.Int32
+	check := func(ctx context.Context, p core.Provider) error {
+		checks.Add(1)
+		return nil
+	}
+
+	provider := WithHealthCheck(check, 10*time.Millisecond)(&mockProvider{})
+	reporter := provider.(HealthReporter)
+	reporter.Close()
+	reporter.Healthy()
+	time.Sleep(30 * time.Millisecond)
+	if checks.Load() != 0 {
+		t.Errorf("Expected no health checks after Close, got %d", checks.Load())
+	}
+}
+
+func TestDefaultHealthCheck(t *testing.T) {
+	var got core.Request
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			got = req
+			return nil, errors.New("unauthorized")
+		},
+	}
+
+	if err := DefaultHealthCheck(context.Background(), mock); err == nil {
+		t.Error("Expected provider error to be returned")
+	}
+	if got.MaxOutputTokens != 1 || len(got.Messages) != 1 {
+		t.Errorf("Expected minimal one-token request, got %+v", got)
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/recera/gai/core"
+)
+
+// RequestBodyLimitOpts configures input token caps.
+type RequestBodyLimitOpts struct {
+	// MaxInputTokens is the cap for models not listed in PerModel (0 means no cap)
+	MaxInputTokens int
+	// PerModel overrides the cap for specific models, keyed by core.Request.Model
+	PerModel map[string]int
+	// Counter estimates input tokens (defaults to core.ApproxTokenCounter)
+	Counter core.TokenCounter
+}
+
+// requestBodyLimitMiddleware rejects requests whose input exceeds a token cap.
+type requestBodyLimitMiddleware struct {
+	baseMiddleware
+	opts RequestBodyLimitOpts
+}
+
+// WithRequestBodyLimit creates middleware that rejects any request whose
+// estimated input exceeds maxInputTokens, before it reaches the provider.
+// See WithRequestBodyLimitOpts.
+func WithRequestBodyLimit(maxInputTokens int) Middleware {
+	return WithRequestBodyLimitOpts(RequestBodyLimitOpts{MaxInputTokens: maxInputTokens})
+}
+
+// WithRequestBodyLimitOpts creates middleware that enforces input token caps.
+// The input of each request is estimated with opts.Counter and, if it exceeds
+// the cap for the request's model, the call fails with a
+// core.ErrorInputTooLarge error carrying ActualTokens and MaxTokens. Nothing
+// is trimmed: unlike core.TrimWithSummary this is a hard guard rail against
+// oversized or runaway contexts.
+//
+// Example:
+//
+//	provider = middleware.WithRequestBodyLimitOpts(middleware.RequestBodyLimitOpts{
+//	    MaxInputTokens: 8000,
+//	    PerModel: map[string]int{
+//	        "gpt-4o": 100000,
+//	    },
+//	})(provider)
+func WithRequestBodyLimitOpts(opts RequestBodyLimitOpts) Middleware {
+	// Copy limits so later changes by the caller don't race with requests
+	perModel := make(map[string]int, len(opts.PerModel))
+	for model, limit := range opts.PerModel {
+		perModel[model] = limit
+	}
+	opts.PerModel = perModel
+	if opts.Counter == nil {
+		opts.Counter = core.ApproxTokenCounter
+	}
+
+	return func(provider core.Provider) core.Provider {
+		return &requestBodyLimitMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			opts:           opts,
+		}
+	}
+}
+
+// check returns an error if req exceeds the cap for its model.
+func (m *requestBodyLimitMiddleware) check(req core.Request) error {
+	limit, ok := m.opts.PerModel[req.Model]
+	if !ok {
+		limit = m.opts.MaxInputTokens
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	tokens := m.opts.Counter.CountTokens(core.ApplySystemPrompt(req).Messages)
+	if tokens <= limit {
+		return nil
+	}
+	return core.NewError(core.ErrorInputTooLarge,
+		fmt.Sprintf("estimated %d input tokens exceed the limit of %d", tokens, limit),
+		core.WithModel(req.Model),
+		core.WithTokenCounts(tokens, limit))
+}
+
+// GenerateText implements the Provider interface with input token caps.
+func (m *requestBodyLimitMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	return m.provider.GenerateText(ctx, req)
+}
+
+// StreamText implements the Provider interface with input token caps.
+func (m *requestBodyLimitMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	return m.provider.StreamText(ctx, req)
+}
+
+// GenerateObject implements the Provider interface with input token caps.
+func (m *requestBodyLimitMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	return m.provider.GenerateObject(ctx, req, schema)
+}
+
+// StreamObject implements the Provider interface with input token caps.
+func (m *requestBodyLimitMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	return m.provider.StreamObject(ctx, req, schema)
+}
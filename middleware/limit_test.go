@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+func TestRequestBodyLimit_RejectsOversizedInput(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return &core.TextResult{Text: "ok"}, nil
+		},
+	}
+	provider := WithRequestBodyLimit(100)(mock)
+
+	small := core.Request{Messages: []core.Message{
+		{Role: core.User, Parts: []core.Part{core.Text{Text: "hello"}}},
+	}}
+	if _, err := provider.GenerateText(context.Background(), small); err != nil {
+		t.Fatalf("small request failed: %v", err)
+	}
+
+	large := core.Request{Model: "gpt-4o", Messages: []core.Message{
+		{Role: core.User, Parts: []core.Part{core.Text{Text: strings.Repeat("x", 2000)}}},
+	}}
+	_, err := provider.GenerateText(context.Background(), large)
+
+	var aiErr *core.AIError
+	if !errors.As(err, &aiErr) {
+		t.Fatalf("expected AIError, got %v", err)
+	}
+	if aiErr.Code != core.ErrorInputTooLarge {
+		t.Errorf("code = %s, want %s", aiErr.Code, core.ErrorInputTooLarge)
+	}
+	if aiErr.MaxTokens != 100 || aiErr.ActualTokens <= 100 {
+		t.Errorf("token counts = %d/%d, want actual > max = 100", aiErr.ActualTokens, aiErr.MaxTokens)
+	}
+	if aiErr.Model != "gpt-4o" {
+		t.Errorf("model = %q, want gpt-4o", aiErr.Model)
+	}
+	if mock.getCallCount() != 1 {
+		t.Errorf("expected only the small request to reach the provider, got %d calls", mock.getCallCount())
+	}
+}
+
+func TestRequestBodyLimit_PerModel(t *testing.T) {
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			return &mockTextStream{}, nil
+		},
+	}
+	counter := core.TokenCounterFunc(func(messages []core.Message) int { return 500 })
+	provider := WithRequestBodyLimitOpts(RequestBodyLimitOpts{
+		MaxInputTokens: 100,
+		PerModel:       map[string]int{"big": 1000, "unlimited": 0},
+		Counter:        counter,
+	})(mock)
+
+	tests := []struct {
+		model   string
+		wantErr bool
+	}{
+		{"small", true},
+		{"big", false},
+		{"unlimited", false},
+	}
+	for _, tt := range tests {
+		_, err := provider.StreamText(context.Background(), core.Request{Model: tt.model})
+		if got := err != nil; got != tt.wantErr {
+			t.Errorf("model %q: err = %v, wantErr %v", tt.model, err, tt.wantErr)
+		}
+		if tt.wantErr && !core.IsBadRequest(err) {
+			t.Errorf("model %q: expected a bad request error, got %v", tt.model, err)
+		}
+	}
+}
@@ -58,4 +58,8 @@ func (m *baseMiddleware) GenerateObject(ctx context.Context, req core.Request, s
 // StreamObject delegates to the wrapped provider.
 func (m *baseMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
 	return m.provider.StreamObject(ctx, req, schema)
-}
\ No newline at end of file
+}
+// wrapped returns the provider this middleware delegates to.
+func (m *baseMiddleware) wrapped() core.Provider {
+	return m.provider
+}
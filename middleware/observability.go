@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"sync"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// observabilityMiddleware records a request span and metrics for every call.
+type observabilityMiddleware struct {
+	baseMiddleware
+	name string
+}
+
+// WithObservability wires OpenTelemetry into the framework in one place. It
+// installs tp and mp as the global providers used by the obs package (nil
+// leaves the current provider in place) and returns middleware that wraps
+// every call in a request span, records token usage and error metrics, and
+// passes the span's context to the wrapped provider so its spans nest under
+// it. Streams are recorded until they finish or are closed. Usage is
+// attributed to the model that served the request when the provider reports
+// it.
+//
+// The provider name on spans and metrics is taken from the wrapped
+// provider's package, such as "openai".
+//
+// Example:
+//
+//	provider = middleware.WithObservability(tracerProvider, meterProvider)(provider)
+func WithObservability(tp trace.TracerProvider, mp metric.MeterProvider) Middleware {
+	if tp != nil {
+		obs.SetGlobalTracerProvider(tp)
+	}
+	if mp != nil {
+		obs.SetGlobalMeterProvider(mp)
+	}
+
+	return func(provider core.Provider) core.Provider {
+		return &observabilityMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			name:           providerName(provider),
+		}
+	}
+}
+
+// providerName derives a telemetry name for provider from the package of its
+// concrete type, looking through middleware from this package.
+func providerName(provider core.Provider) string {
+	for {
+		w, ok := provider.(interface{ wrapped() core.Provider })
+		if !ok {
+			break
+		}
+		provider = w.wrapped()
+	}
+
+	t := reflect.TypeOf(provider)
+	if t == nil {
+		return "unknown"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.PkgPath() == "" {
+		return "unknown"
+	}
+	return path.Base(t.PkgPath())
+}
+
+// GenerateText implements the Provider interface with request tracing.
+func (m *observabilityMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	c := obs.NewProviderCollector(ctx, m.name, req)
+	result, err := m.provider.GenerateText(c.Context(), req)
+	if err == nil && result != nil {
+		c.SetResponseModel(result.ModelUsed)
+	}
+	c.Complete(err == nil, resultUsage(result, err), err)
+	return result, err
+}
+
+// GenerateObject implements the Provider interface with request tracing.
+func (m *observabilityMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	c := obs.NewProviderCollector(ctx, m.name, req)
+	result, err := m.provider.GenerateObject(c.Context(), req, schema)
+	var usage *core.Usage
+	if err == nil && result != nil {
+		usage = &result.Usage
+	}
+	c.Complete(err == nil, usage, err)
+	return result, err
+}
+
+// StreamText implements the Provider interface, recording the stream until
+// it ends.
+func (m *observabilityMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	c := obs.NewProviderCollector(ctx, m.name, req)
+	stream, err := m.provider.StreamText(c.Context(), req)
+	if err != nil {
+		c.Complete(false, nil, err)
+		return nil, err
+	}
+
+	s := &observedStream{collector: c}
+	s.TextStream = core.Tee(stream, s.observe)
+	return s, nil
+}
+
+// StreamObject implements the Provider interface, recording the stream until
+// its final value is read or it is closed.
+func (m *observabilityMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	c := obs.NewProviderCollector(ctx, m.name, req)
+	stream, err := m.provider.StreamObject(c.Context(), req, schema)
+	if err != nil {
+		c.Complete(false, nil, err)
+		return nil, err
+	}
+	return &observedObjectStream{ObjectStream: stream, collector: c}, nil
+}
+
+// resultUsage returns the usage of a successful text result.
+func resultUsage(result *core.TextResult, err error) *core.Usage {
+	if err != nil || result == nil {
+		return nil
+	}
+	return &result.Usage
+}
+
+// observedStream completes its collector with the stream's usage when the
+// stream finishes, fails or is closed, whichever happens first.
+type observedStream struct {
+	core.TextStream
+	collector    *obs.IntegratedCollector
+	completeOnce sync.Once
+
+	mu    sync.Mutex // guards steps, which Close may read mid-stream
+	steps core.StepAccumulator
+}
+
+// observe records each event and completes the collector at the end of the
+// stream.
+func (s *observedStream) observe(event core.Event) {
+	s.mu.Lock()
+	s.steps.Add(event)
+	s.mu.Unlock()
+	if event.Type == core.EventFinish || event.Type == core.EventError {
+		s.complete()
+	}
+}
+
+// Close implements core.TextStream.
+func (s *observedStream) Close() error {
+	s.complete()
+	return s.TextStream.Close()
+}
+
+// complete records the outcome of the stream once.
+func (s *observedStream) complete() {
+	s.completeOnce.Do(func() {
+		s.mu.Lock()
+		var usage *core.Usage
+		if s.steps.Finished() {
+			usage = &s.steps.Result().Usage
+		}
+		failed, err := s.steps.Failed()
+		s.mu.Unlock()
+		s.collector.Complete(!failed, usage, err)
+	})
+}
+
+// observedObjectStream completes its collector when the final object is read
+// or the stream is closed, whichever happens first.
+type observedObjectStream struct {
+	core.ObjectStream[any]
+	collector    *obs.IntegratedCollector
+	completeOnce sync.Once
+}
+
+// Final implements core.ObjectStream.
+func (s *observedObjectStream) Final() (*any, error) {
+	value, err := s.ObjectStream.Final()
+	s.complete(err)
+	return value, err
+}
+
+// Close implements core.TextStream.
+func (s *observedObjectStream) Close() error {
+	s.complete(nil)
+	return s.ObjectStream.Close()
+}
+
+// complete records the outcome of the stream once.
+func (s *observedObjectStream) complete(err error) {
+	s.completeOnce.Do(func() {
+		s.collector.Complete(err == nil, nil, err)
+	})
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+	"go.opentelemetry.io/otel/attribute"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// setupObservability installs an in-memory exporter through WithObservability.
+func setupObservability(t *testing.T) (*tracetest.InMemoryExporter, Middleware) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	mw := WithObservability(tp, metricnoop.NewMeterProvider())
+	t.Cleanup(func() {
+		obs.SetGlobalTracerProvider(tracenoop.NewTracerProvider())
+		_ = tp.Shutdown(context.Background())
+	})
+	return exporter, mw
+}
+
+// spanInt returns an integer attribute of span.
+func spanInt(span tracetest.SpanStub, key string) (int64, bool) {
+	for _, attr := range span.Attributes {
+		if attr.Key == attribute.Key(key) {
+			return attr.Value.AsInt64(), true
+		}
+	}
+	return 0, false
+}
+
+func TestWithObservability_GenerateText(t *testing.T) {
+	exporter, mw := setupObservability(t)
+
+	var innerSpan trace.SpanContext
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			innerSpan = trace.SpanFromContext(ctx).SpanContext()
+			return &core.TextResult{Text: "ok", Usage: core.Usage{InputTokens: 12, OutputTokens: 5}}, nil
+		},
+	}
+
+	if _, err := mw(mock).GenerateText(context.Background(), core.Request{Model: "test-model"}); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if !innerSpan.IsValid() || innerSpan.SpanID() != spans[0].SpanContext.SpanID() {
+		t.Error("wrapped provider did not receive the request span in its context")
+	}
+	if v, ok := spanInt(spans[0], "usage.input_tokens"); !ok || v != 12 {
+		t.Errorf("usage.input_tokens = %d, want 12", v)
+	}
+	if v, ok := spanInt(spans[0], "usage.output_tokens"); !ok || v != 5 {
+		t.Errorf("usage.output_tokens = %d, want 5", v)
+	}
+}
+
+func TestWithObservability_Error(t *testing.T) {
+	exporter, mw := setupObservability(t)
+
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return nil, errors.New("boom")
+		},
+	}
+
+	if _, err := mw(mock).GenerateText(context.Background(), core.Request{}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if len(spans[0].Events) == 0 {
+		t.Error("expected the error to be recorded on the span")
+	}
+}
+
+func TestWithObservability_StreamText(t *testing.T) {
+	exporter, mw := setupObservability(t)
+
+	events := make(chan core.Event, 3)
+	events <- core.Event{Type: core.EventTextDelta, TextDelta: "hi"}
+	events <- core.Event{Type: core.EventFinish, Usage: &core.Usage{InputTokens: 3, OutputTokens: 7}}
+	close(events)
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			return &mockTextStream{events: events}, nil
+		},
+	}
+
+	stream, err := mw(mock).StreamText(context.Background(), core.Request{})
+	if err != nil {
+		t.Fatalf("StreamText failed: %v", err)
+	}
+	count := 0
+	for range stream.Events() {
+		count++
+	}
+	stream.Close()
+	if count != 2 {
+		t.Errorf("got %d events, want 2", count)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if v, ok := spanInt(spans[0], "usage.output_tokens"); !ok || v != 7 {
+		t.Errorf("usage.output_tokens = %d, want 7", v)
+	}
+}
+
+func TestWithObservability_StreamTextClosedEarly(t *testing.T) {
+	exporter, mw := setupObservability(t)
+
+	events := make(chan core.Event, 1)
+	events <- core.Event{Type: core.EventTextDelta, TextDelta: "hi"}
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			return &mockTextStream{events: events}, nil
+		},
+	}
+
+	stream, err := mw(mock).StreamText(context.Background(), core.Request{})
+	if err != nil {
+		t.Fatalf("StreamText failed: %v", err)
+	}
+	<-stream.Events()
+	if spans := exporter.GetSpans(); len(spans) != 0 {
+		t.Fatalf("got %d spans before the stream ended, want 0", len(spans))
+	}
+	stream.Close()
+	if spans := exporter.GetSpans(); len(spans) != 1 {
+		t.Fatalf("got %d spans after Close, want 1", len(spans))
+	}
+}
+
+func TestProviderName(t *testing.T) {
+	mock := &mockProvider{}
+	if got := providerName(mock); got != "middleware" {
+		t.Errorf("providerName = %q, want middleware", got)
+	}
+	// Middleware from this package is looked through
+	if got := providerName(WithCost(nil)(mock)); got != "middleware" {
+		t.Errorf("providerName through middleware = %q, want middleware", got)
+	}
+}
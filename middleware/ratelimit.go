@@ -24,6 +24,9 @@ type RateLimitOpts struct {
 	PerMethod map[string]*RateLimitConfig
 	// OnRateLimited is called when a request is rate limited (for observability).
 	OnRateLimited func(method string, waitTime time.Duration)
+	// IdleTimeout is how long an unused bucket is kept by WithRateLimitPerUser
+	// before it is discarded. Defaults to 10 minutes.
+	IdleTimeout time.Duration
 }
 
 // RateLimitConfig specifies rate limit settings for a specific method.
@@ -52,7 +55,15 @@ type rateLimitMiddleware struct {
 
 // WithRateLimit creates middleware that enforces rate limits using a token bucket algorithm.
 func WithRateLimit(opts RateLimitOpts) Middleware {
-	// Validate options
+	opts = normalizeRateLimitOpts(opts)
+
+	return func(provider core.Provider) core.Provider {
+		return newRateLimitMiddleware(provider, opts)
+	}
+}
+
+// normalizeRateLimitOpts fills in defaults for missing or inconsistent limits.
+func normalizeRateLimitOpts(opts RateLimitOpts) RateLimitOpts {
 	if opts.RPS <= 0 {
 		opts.RPS = 10
 	}
@@ -62,26 +73,28 @@ func WithRateLimit(opts RateLimitOpts) Middleware {
 	if opts.Burst < int(opts.RPS) {
 		opts.Burst = int(opts.RPS) // Burst should be at least RPS
 	}
+	return opts
+}
 
-	return func(provider core.Provider) core.Provider {
-		m := &rateLimitMiddleware{
-			baseMiddleware: baseMiddleware{provider: provider},
-			opts:          opts,
-			globalLimit:   rate.NewLimiter(rate.Limit(opts.RPS), opts.Burst),
-			methodLimits:  make(map[string]*rate.Limiter),
-		}
+// newRateLimitMiddleware creates a rate limiter with fresh token buckets.
+func newRateLimitMiddleware(provider core.Provider, opts RateLimitOpts) *rateLimitMiddleware {
+	m := &rateLimitMiddleware{
+		baseMiddleware: baseMiddleware{provider: provider},
+		opts:          opts,
+		globalLimit:   rate.NewLimiter(rate.Limit(opts.RPS), opts.Burst),
+		methodLimits:  make(map[string]*rate.Limiter),
+	}
 
-		// Initialize per-method limiters if configured
-		if opts.PerMethod != nil {
-			for method, config := range opts.PerMethod {
-				if config.RPS > 0 && config.Burst > 0 {
-					m.methodLimits[method] = rate.NewLimiter(rate.Limit(config.RPS), config.Burst)
-				}
+	// Initialize per-method limiters if configured
+	if opts.PerMethod != nil {
+		for method, config := range opts.PerMethod {
+			if config.RPS > 0 && config.Burst > 0 {
+				m.methodLimits[method] = rate.NewLimiter(rate.Limit(config.RPS), config.Burst)
 			}
 		}
-
-		return m
 	}
+
+	return m
 }
 
 // getLimiter returns the appropriate rate limiter for the given method.
@@ -97,7 +110,8 @@ func (m *rateLimitMiddleware) getLimiter(method string) *rate.Limiter {
 }
 
 // waitForToken waits for a rate limit token or returns an error if the wait times out.
-func (m *rateLimitMiddleware) waitForToken(ctx context.Context, method string) error {
+// errOpts are added to any rate limit error returned.
+func (m *rateLimitMiddleware) waitForToken(ctx context.Context, method string, errOpts ...core.ErrorOption) error {
 	limiter := m.getLimiter(method)
 
 	// Create a context with timeout if configured
@@ -123,8 +137,10 @@ func (m *rateLimitMiddleware) waitForToken(ctx context.Context, method string) e
 		return core.NewError(
 			core.ErrorRateLimited,
 			fmt.Sprintf("rate limit exceeded, would need to wait %v", waitTime),
-			core.WithProvider("middleware"),
-			core.WithRetryAfter(waitTime),
+			append([]core.ErrorOption{
+				core.WithProvider("middleware"),
+				core.WithRetryAfter(waitTime),
+			}, errOpts...)...,
 		)
 	}
 
@@ -145,8 +161,10 @@ func (m *rateLimitMiddleware) waitForToken(ctx context.Context, method string) e
 			return core.NewError(
 				core.ErrorRateLimited,
 				fmt.Sprintf("rate limit wait timeout after %v", m.opts.WaitTimeout),
-				core.WithProvider("middleware"),
-				core.WithRetryAfter(waitTime),
+				append([]core.ErrorOption{
+					core.WithProvider("middleware"),
+					core.WithRetryAfter(waitTime),
+				}, errOpts...)...,
 			)
 		}
 		return waitCtx.Err()
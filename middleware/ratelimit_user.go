@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// defaultRateLimitIdleTimeout is how long an unused per-user bucket is kept.
+const defaultRateLimitIdleTimeout = 10 * time.Minute
+
+// perUserRateLimitMiddleware keeps a separate set of token buckets per key.
+type perUserRateLimitMiddleware struct {
+	baseMiddleware
+	keyFn     func(core.Request) string
+	opts      RateLimitOpts
+	mu        sync.Mutex
+	buckets   map[string]*userBucket
+	lastSweep time.Time
+}
+
+// userBucket is the rate limiter for one key and when it was last used.
+type userBucket struct {
+	limiter  *rateLimitMiddleware
+	lastUsed time.Time
+}
+
+// WithRateLimitPerUser creates middleware that enforces limits separately for
+// each key returned by keyFn, such as a user or tenant ID, so one caller
+// cannot exhaust the capacity of others. Requests with an empty key share a
+// single bucket. Buckets unused for limits.IdleTimeout are discarded.
+// Rate limit errors include the key in Details["rate_limit_key"].
+func WithRateLimitPerUser(keyFn func(core.Request) string, limits RateLimitOpts) Middleware {
+	limits = normalizeRateLimitOpts(limits)
+	if limits.IdleTimeout <= 0 {
+		limits.IdleTimeout = defaultRateLimitIdleTimeout
+	}
+
+	return func(provider core.Provider) core.Provider {
+		return &perUserRateLimitMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			keyFn:          keyFn,
+			opts:           limits,
+			buckets:        make(map[string]*userBucket),
+			lastSweep:      time.Now(),
+		}
+	}
+}
+
+// limiterFor returns the rate limiter for key, creating it if needed, and
+// discards buckets that have been idle for longer than the idle timeout.
+func (m *perUserRateLimitMiddleware) limiterFor(key string) *rateLimitMiddleware {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(m.lastSweep) >= m.opts.IdleTimeout {
+		for k, b := range m.buckets {
+			if now.Sub(b.lastUsed) >= m.opts.IdleTimeout {
+				delete(m.buckets, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &userBucket{limiter: newRateLimitMiddleware(nil, m.opts)}
+		m.buckets[key] = b
+	}
+	b.lastUsed = now
+	return b.limiter
+}
+
+// waitForToken waits for a token from the bucket of the request's key.
+func (m *perUserRateLimitMiddleware) waitForToken(ctx context.Context, req core.Request, method string) error {
+	key := ""
+	if m.keyFn != nil {
+		key = m.keyFn(req)
+	}
+	return m.limiterFor(key).waitForToken(ctx, method,
+		core.WithDetails(map[string]any{"rate_limit_key": key}))
+}
+
+// GenerateText implements the Provider interface with per-user rate limiting.
+func (m *perUserRateLimitMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	if err := m.waitForToken(ctx, req, "GenerateText"); err != nil {
+		return nil, err
+	}
+	return m.provider.GenerateText(ctx, req)
+}
+
+// StreamText implements the Provider interface with per-user rate limiting.
+func (m *perUserRateLimitMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	if err := m.waitForToken(ctx, req, "StreamText"); err != nil {
+		return nil, err
+	}
+	return m.provider.StreamText(ctx, req)
+}
+
+// GenerateObject implements the Provider interface with per-user rate limiting.
+func (m *perUserRateLimitMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	if err := m.waitForToken(ctx, req, "GenerateObject"); err != nil {
+		return nil, err
+	}
+	return m.provider.GenerateObject(ctx, req, schema)
+}
+
+// StreamObject implements the Provider interface with per-user rate limiting.
+func (m *perUserRateLimitMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	if err := m.waitForToken(ctx, req, "StreamObject"); err != nil {
+		return nil, err
+	}
+	return m.provider.StreamObject(ctx, req, schema)
+}
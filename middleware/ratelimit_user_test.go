@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+func tenantKey(req core.Request) string {
+	tenant, _ := req.Metadata["tenant"].(string)
+	return tenant
+}
+
+func tenantRequest(tenant string) core.Request {
+	return core.Request{Metadata: map[string]any{"tenant": tenant}}
+}
+
+func TestRateLimitPerUser(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return &core.TextResult{Text: "ok"}, nil
+		},
+	}
+	provider := WithRateLimitPerUser(tenantKey, RateLimitOpts{
+		RPS:         1,
+		Burst:       1,
+		WaitTimeout: 10 * time.Millisecond,
+	})(mock)
+	ctx := context.Background()
+
+	if _, err := provider.GenerateText(ctx, tenantRequest("a")); err != nil {
+		t.Fatalf("first request for a failed: %v", err)
+	}
+
+	// a's bucket is empty, so its next request is limited
+	_, err := provider.GenerateText(ctx, tenantRequest("a"))
+	var aiErr *core.AIError
+	if !errors.As(err, &aiErr) || aiErr.Code != core.ErrorRateLimited {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if got := aiErr.Details["rate_limit_key"]; got != "a" {
+		t.Errorf("expected rate_limit_key a, got %v", got)
+	}
+
+	// b has its own bucket
+	if _, err := provider.GenerateText(ctx, tenantRequest("b")); err != nil {
+		t.Errorf("request for b should not be limited by a: %v", err)
+	}
+}
+
+func TestRateLimitPerUserIdleBuckets(t *testing.T) {
+	provider := WithRateLimitPerUser(tenantKey, RateLimitOpts{
+		RPS:         1,
+		Burst:       1,
+		IdleTimeout: 20 * time.Millisecond,
+	})(&mockProvider{})
+	m := provider.(*perUserRateLimitMiddleware)
+	ctx := context.Background()
+
+	for _, tenant := range []string{"a", "b"} {
+		if _, err := m.GenerateText(ctx, tenantRequest(tenant)); err != nil {
+			t.Fatalf("request for %s failed: %v", tenant, err)
+		}
+	}
+	if len(m.buckets) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(m.buckets))
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, err := m.GenerateText(ctx, tenantRequest("c")); err != nil {
+		t.Fatalf("request for c failed: %v", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.buckets) != 1 || m.buckets["c"] == nil {
+		t.Errorf("expected only c's bucket to remain, got %d buckets", len(m.buckets))
+	}
+}
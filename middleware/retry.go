@@ -120,8 +120,14 @@ func (m *retryMiddleware) waitWithContext(ctx context.Context, delay time.Durati
 	}
 }
 
-// retryOperation executes an operation with retry logic.
-func (m *retryMiddleware) retryOperation(ctx context.Context, operation func() error) error {
+// retryOperation executes an operation with retry logic. Requests that set
+// their own retry policy (MaxRetries or RetryOn) are retried by the provider,
+// so they run once here.
+func (m *retryMiddleware) retryOperation(ctx context.Context, req core.Request, operation func() error) error {
+	if _, ok := core.RetryPolicyFromRequest(req); ok {
+		return operation()
+	}
+
 	var lastErr error
 
 	for attempt := 0; attempt <= m.opts.MaxAttempts; attempt++ {
@@ -166,7 +172,7 @@ func (m *retryMiddleware) retryOperation(ctx context.Context, operation func() e
 func (m *retryMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
 	var result *core.TextResult
 	
-	err := m.retryOperation(ctx, func() error {
+	err := m.retryOperation(ctx, req, func() error {
 		var err error
 		result, err = m.provider.GenerateText(ctx, req)
 		return err
@@ -181,7 +187,7 @@ func (m *retryMiddleware) GenerateText(ctx context.Context, req core.Request) (*
 func (m *retryMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
 	var stream core.TextStream
 	
-	err := m.retryOperation(ctx, func() error {
+	err := m.retryOperation(ctx, req, func() error {
 		var err error
 		stream, err = m.provider.StreamText(ctx, req)
 		return err
@@ -194,7 +200,7 @@ func (m *retryMiddleware) StreamText(ctx context.Context, req core.Request) (cor
 func (m *retryMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
 	var result *core.ObjectResult[any]
 	
-	err := m.retryOperation(ctx, func() error {
+	err := m.retryOperation(ctx, req, func() error {
 		var err error
 		result, err = m.provider.GenerateObject(ctx, req, schema)
 		return err
@@ -209,7 +215,7 @@ func (m *retryMiddleware) GenerateObject(ctx context.Context, req core.Request,
 func (m *retryMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
 	var stream core.ObjectStream[any]
 	
-	err := m.retryOperation(ctx, func() error {
+	err := m.retryOperation(ctx, req, func() error {
 		var err error
 		stream, err = m.provider.StreamObject(ctx, req, schema)
 		return err
@@ -150,6 +150,25 @@ func TestRetryMiddleware_RetryOnTransientError(t *testing.T) {
 	}
 }
 
+func TestRetryMiddleware_RequestRetryPolicyPassesThrough(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return nil, core.NewError(core.ErrorProviderUnavailable, "transient error", core.WithProvider("test"))
+		},
+	}
+
+	provider := WithRetry(RetryOpts{MaxAttempts: 3, BaseDelay: time.Millisecond})(mock)
+
+	_, err := provider.GenerateText(context.Background(), core.Request{MaxRetries: 2})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	// The provider applies the request's own retry settings
+	if mock.getCallCount() != 1 {
+		t.Errorf("expected 1 call, got %d", mock.getCallCount())
+	}
+}
+
 func TestRetryMiddleware_NoRetryOnBadRequest(t *testing.T) {
 	mock := &mockProvider{
 		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
@@ -311,6 +311,7 @@ func (s *safetyStream) processStream() {
 						fmt.Sprintf("stopped due to safety event: %s", event.Safety.Category),
 						core.WithProvider("middleware"),
 					),
+					ErrorCode: string(core.ErrorSafetyBlocked),
 				}
 				return
 			}
@@ -322,8 +323,9 @@ func (s *safetyStream) processStream() {
 			if err := s.safety.checkBlocked(fullText); err != nil {
 				// Send error instead of finish
 				s.events <- core.Event{
-					Type: core.EventError,
-					Err:  err,
+					Type:      core.EventError,
+					Err:       err,
+					ErrorCode: core.ErrorCodeOf(err),
 				}
 				return
 			}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// AnomalyDetector decides whether requests look anomalous, such as after a
+// cost spike, an unusual tool call pattern or an abnormally long response.
+// Implementations must be safe for concurrent use. obs.StatisticalSentinel
+// is a simple threshold-based implementation; rolling statistics and other
+// detectors can be implemented outside this package.
+type AnomalyDetector interface {
+	// Observe is called with each completed request and its result
+	Observe(req core.Request, result *core.TextResult)
+	// IsAnomaly is called before each request; returning true blocks it
+	IsAnomaly(req core.Request) bool
+}
+
+// DurationObserver is optionally implemented by an AnomalyDetector that
+// also wants to know how long each completed request took.
+type DurationObserver interface {
+	// ObserveDuration is called after Observe with the request's duration
+	ObserveDuration(req core.Request, duration time.Duration)
+}
+
+// AnomalyError is returned by WithSentinel when the detector flags a
+// request. It is not transient, so WithRetry does not retry it.
+type AnomalyError struct {
+	// Model is the request's model, "" for the provider's default model
+	Model string
+	// Reason describes the anomaly if the detector has a Reason() string
+	// method, such as obs.StatisticalSentinel
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *AnomalyError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("anomaly detected for model %q: %s", e.Model, e.Reason)
+	}
+	return fmt.Sprintf("anomaly detected for model %q", e.Model)
+}
+
+// sentinelMiddleware blocks requests that an AnomalyDetector flags.
+type sentinelMiddleware struct {
+	baseMiddleware
+	detector AnomalyDetector
+}
+
+// WithSentinel creates middleware that asks detector before each request
+// whether it is anomalous, failing flagged requests with *AnomalyError
+// without calling the provider. Completed requests are passed to
+// detector.Observe: streams once their finish event has been read, with a
+// result built from the streamed text, tool calls and usage; objects with
+// their steps and usage. Failed requests are not observed.
+//
+// Example:
+//
+//	sentinel := &obs.StatisticalSentinel{MaxTokens: 4096, MaxToolCalls: 25}
+//	provider = middleware.WithSentinel(sentinel)(provider)
+//
+//	_, err := provider.GenerateText(ctx, req)
+//	var anomaly *middleware.AnomalyError
+//	if errors.As(err, &anomaly) {
+//	    alert(anomaly.Reason)
+//	}
+func WithSentinel(detector AnomalyDetector) Middleware {
+	return func(provider core.Provider) core.Provider {
+		return &sentinelMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			detector:       detector,
+		}
+	}
+}
+
+// check returns an AnomalyError if the detector flags req.
+func (m *sentinelMiddleware) check(req core.Request) error {
+	if !m.detector.IsAnomaly(req) {
+		return nil
+	}
+	err := &AnomalyError{Model: req.Model}
+	if r, ok := m.detector.(interface{ Reason() string }); ok {
+		err.Reason = r.Reason()
+	}
+	return err
+}
+
+// observe passes a completed request to the detector.
+func (m *sentinelMiddleware) observe(req core.Request, result *core.TextResult, start time.Time) {
+	m.detector.Observe(req, result)
+	if d, ok := m.detector.(DurationObserver); ok {
+		d.ObserveDuration(req, time.Since(start))
+	}
+}
+
+// observer returns a Tee callback that rebuilds a stream's result and
+// observes it on the finish event.
+func (m *sentinelMiddleware) observer(req core.Request, start time.Time) func(core.Event) {
+	result := &core.TextResult{}
+	var text []byte
+	var calls []core.ToolCall
+	return func(e core.Event) {
+		switch e.Type {
+		case core.EventTextDelta:
+			text = append(text, e.TextDelta...)
+		case core.EventToolCall:
+			calls = append(calls, core.ToolCall{ID: e.ToolID, Name: e.ToolName, Input: e.ToolInput})
+		case core.EventFinish:
+			result.Text = string(text)
+			if len(calls) > 0 {
+				result.Steps = []core.Step{{Text: result.Text, ToolCalls: calls, StepNumber: 1}}
+			}
+			if e.Usage != nil {
+				result.Usage = *e.Usage
+			}
+			m.observe(req, result, start)
+		}
+	}
+}
+
+// GenerateText implements the Provider interface with anomaly detection.
+func (m *sentinelMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	result, err := m.provider.GenerateText(ctx, req)
+	if err == nil && result != nil {
+		m.observe(req, result, start)
+	}
+	return result, err
+}
+
+// StreamText implements the Provider interface with anomaly detection.
+func (m *sentinelMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	stream, err := m.provider.StreamText(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return core.Tee(stream, m.observer(req, start)), nil
+}
+
+// GenerateObject implements the Provider interface with anomaly detection.
+func (m *sentinelMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	result, err := m.provider.GenerateObject(ctx, req, schema)
+	if err == nil && result != nil {
+		m.observe(req, &core.TextResult{Steps: result.Steps, Usage: result.Usage}, start)
+	}
+	return result, err
+}
+
+// StreamObject implements the Provider interface with anomaly detection.
+func (m *sentinelMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	if err := m.check(req); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	stream, err := m.provider.StreamObject(ctx, req, schema)
+	if err != nil {
+		return nil, err
+	}
+	return &teeObjectStream{ObjectStream: stream, observe: m.observer(req, start)}, nil
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+)
+
+// recordingDetector flags requests for blocked models and records observations.
+type recordingDetector struct {
+	mu        sync.Mutex
+	blocked   string
+	observed  []*core.TextResult
+	durations []time.Duration
+}
+
+func (d *recordingDetector) Observe(req core.Request, result *core.TextResult) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.observed = append(d.observed, result)
+}
+
+func (d *recordingDetector) ObserveDuration(req core.Request, duration time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.durations = append(d.durations, duration)
+}
+
+func (d *recordingDetector) IsAnomaly(req core.Request) bool {
+	return req.Model == d.blocked
+}
+
+func TestSentinel_GenerateText(t *testing.T) {
+	calls := 0
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			calls++
+			return &core.TextResult{Text: "ok", Usage: core.Usage{TotalTokens: 10}}, nil
+		},
+	}
+	detector := &recordingDetector{blocked: "suspicious"}
+	provider := WithSentinel(detector)(mock)
+	ctx := context.Background()
+
+	if _, err := provider.GenerateText(ctx, core.Request{Model: "gpt-4o"}); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if len(detector.observed) != 1 || detector.observed[0].Text != "ok" {
+		t.Errorf("expected one observed result, got %+v", detector.observed)
+	}
+	if len(detector.durations) != 1 {
+		t.Errorf("expected one observed duration, got %d", len(detector.durations))
+	}
+
+	_, err := provider.GenerateText(ctx, core.Request{Model: "suspicious"})
+	var anomaly *AnomalyError
+	if !errors.As(err, &anomaly) {
+		t.Fatalf("expected AnomalyError, got %v", err)
+	}
+	if anomaly.Model != "suspicious" {
+		t.Errorf("Model = %q, want suspicious", anomaly.Model)
+	}
+	if calls != 1 {
+		t.Errorf("expected flagged request not to reach the provider, got %d calls", calls)
+	}
+}
+
+func TestSentinel_StreamText(t *testing.T) {
+	mock := &mockProvider{
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			events := make(chan core.Event, 4)
+			events <- core.Event{Type: core.EventTextDelta, TextDelta: "he"}
+			events <- core.Event{Type: core.EventTextDelta, TextDelta: "llo"}
+			events <- core.Event{Type: core.EventToolCall, ToolID: "call_1", ToolName: "search"}
+			events <- core.Event{Type: core.EventFinish, Usage: &core.Usage{TotalTokens: 42}}
+			close(events)
+			return &mockTextStream{events: events}, nil
+		},
+	}
+	detector := &recordingDetector{blocked: "suspicious"}
+	provider := WithSentinel(detector)(mock)
+
+	stream, err := provider.StreamText(context.Background(), core.Request{})
+	if err != nil {
+		t.Fatalf("StreamText failed: %v", err)
+	}
+	for range stream.Events() {
+	}
+	stream.Close()
+
+	if len(detector.observed) != 1 {
+		t.Fatalf("expected one observed result, got %d", len(detector.observed))
+	}
+	result := detector.observed[0]
+	if result.Text != "hello" || result.Usage.TotalTokens != 42 {
+		t.Errorf("unexpected observed result: %+v", result)
+	}
+	if len(result.Steps) != 1 || len(result.Steps[0].ToolCalls) != 1 || result.Steps[0].ToolCalls[0].Name != "search" {
+		t.Errorf("expected the streamed tool call to be observed, got %+v", result.Steps)
+	}
+}
+
+func TestSentinel_StatisticalSentinel(t *testing.T) {
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			return &core.TextResult{Usage: core.Usage{OutputTokens: 5000}}, nil
+		},
+	}
+	sentinel := &obs.StatisticalSentinel{MaxTokens: 1000}
+	provider := WithSentinel(sentinel)(mock)
+	ctx := context.Background()
+
+	// The first oversized response trips the sentinel
+	if _, err := provider.GenerateText(ctx, core.Request{}); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	_, err := provider.GenerateText(ctx, core.Request{})
+	var anomaly *AnomalyError
+	if !errors.As(err, &anomaly) {
+		t.Fatalf("expected AnomalyError, got %v", err)
+	}
+	if anomaly.Reason == "" {
+		t.Error("expected the sentinel's reason on the error")
+	}
+	if core.IsTransient(err) {
+		t.Error("AnomalyError should not be transient")
+	}
+
+	sentinel.Reset()
+	if _, err := provider.GenerateText(ctx, core.Request{}); err != nil {
+		t.Errorf("request after reset failed: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+)
+
+// tagsMiddleware adds custom telemetry tags to the request context.
+type tagsMiddleware struct {
+	baseMiddleware
+	tagFn func(req core.Request) map[string]string
+}
+
+// WithMetricsTags creates middleware that adds the given key-value pairs as
+// attributes to every span and metric recorded by the wrapped provider.
+// Tags are merged with those added by other tag middleware in the chain;
+// inner middleware win on key conflicts.
+//
+// Example:
+//
+//	provider = middleware.WithMetricsTags(map[string]string{
+//	    "environment": "production",
+//	    "feature":     "summaries",
+//	})(provider)
+func WithMetricsTags(tags map[string]string) Middleware {
+	// Copy tags so later changes by the caller don't affect requests
+	fixed := make(map[string]string, len(tags))
+	for k, v := range tags {
+		fixed[k] = v
+	}
+
+	return WithMetricsTagFn(func(core.Request) map[string]string {
+		return fixed
+	})
+}
+
+// WithMetricsTagFn creates middleware that computes telemetry tags per request,
+// for example from a tenant ID in the request metadata. The returned tags are
+// added to every span and metric recorded by the wrapped provider.
+//
+// Example:
+//
+//	provider = middleware.WithMetricsTagFn(func(req core.Request) map[string]string {
+//	    tenant, _ := req.Metadata["tenant_id"].(string)
+//	    return map[string]string{"tenant_id": tenant}
+//	})(provider)
+func WithMetricsTagFn(fn func(req core.Request) map[string]string) Middleware {
+	return func(provider core.Provider) core.Provider {
+		return &tagsMiddleware{
+			baseMiddleware: baseMiddleware{provider: provider},
+			tagFn:          fn,
+		}
+	}
+}
+
+// withTags returns a context carrying the tags for req.
+func (m *tagsMiddleware) withTags(ctx context.Context, req core.Request) context.Context {
+	if m.tagFn == nil {
+		return ctx
+	}
+	return obs.ContextWithTags(ctx, m.tagFn(req))
+}
+
+// GenerateText implements the Provider interface with telemetry tags.
+func (m *tagsMiddleware) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	return m.provider.GenerateText(m.withTags(ctx, req), req)
+}
+
+// StreamText implements the Provider interface with telemetry tags.
+func (m *tagsMiddleware) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	return m.provider.StreamText(m.withTags(ctx, req), req)
+}
+
+// GenerateObject implements the Provider interface with telemetry tags.
+func (m *tagsMiddleware) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	return m.provider.GenerateObject(m.withTags(ctx, req), req, schema)
+}
+
+// StreamObject implements the Provider interface with telemetry tags.
+func (m *tagsMiddleware) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	return m.provider.StreamObject(m.withTags(ctx, req), req, schema)
+}
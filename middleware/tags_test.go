@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+)
+
+func TestMetricsTags_Chain(t *testing.T) {
+	var got map[string]string
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			got = obs.TagsFromContext(ctx)
+			return &core.TextResult{Text: "success"}, nil
+		},
+	}
+
+	tags := map[string]string{"environment": "production", "feature": "summaries"}
+	provider := Chain(
+		WithMetricsTags(tags),
+		WithMetricsTagFn(func(req core.Request) map[string]string {
+			tenant, _ := req.Metadata["tenant_id"].(string)
+			return map[string]string{"tenant_id": tenant, "feature": "chat"}
+		}),
+	)(mock)
+
+	// Changes after construction must not leak into requests
+	tags["environment"] = "staging"
+
+	_, err := provider.GenerateText(context.Background(), core.Request{
+		Metadata: map[string]any{"tenant_id": "acme"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"environment": "production",
+		"tenant_id":   "acme",
+		"feature":     "chat", // inner middleware wins
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected tags %v, got %v", expected, got)
+	}
+	for k, v := range expected {
+		if got[k] != v {
+			t.Errorf("tag %s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestMetricsTags_AllMethods(t *testing.T) {
+	var calls int
+	check := func(ctx context.Context) {
+		calls++
+		if obs.TagsFromContext(ctx)["tenant_id"] != "acme" {
+			t.Errorf("missing tenant tag in call %d", calls)
+		}
+	}
+	mock := &mockProvider{
+		generateTextFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			check(ctx)
+			return &core.TextResult{}, nil
+		},
+		streamTextFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			check(ctx)
+			return &mockTextStream{}, nil
+		},
+		generateObjectFunc: func(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+			check(ctx)
+			return &core.ObjectResult[any]{}, nil
+		},
+		streamObjectFunc: func(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+			check(ctx)
+			return &mockObjectStream{}, nil
+		},
+	}
+
+	provider := WithMetricsTags(map[string]string{"tenant_id": "acme"})(mock)
+	ctx := context.Background()
+	provider.GenerateText(ctx, core.Request{})
+	provider.StreamText(ctx, core.Request{})
+	provider.GenerateObject(ctx, core.Request{}, nil)
+	provider.StreamObject(ctx, core.Request{}, nil)
+
+	if calls != 4 {
+		t.Errorf("expected 4 calls, got %d", calls)
+	}
+}
@@ -0,0 +1,252 @@
+package obs
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// UsageRecord is a single request recorded by a UsageAccumulator.
+// Messages, Tools, Text and Steps are only populated when content
+// recording is enabled.
+type UsageRecord struct {
+	Timestamp time.Time
+	Model     string
+	Usage     core.Usage
+	// HasToolCalls reports whether the response called any tools
+	HasToolCalls bool
+
+	// Messages is the conversation sent to the model
+	Messages []core.Message
+	// Tools are the tools offered to the model
+	Tools []ToolDefinition
+	// Text is the final response text
+	Text string
+	// Steps contains tool calls and results for multi-step responses
+	Steps []core.Step
+}
+
+// ToolDefinition describes a tool offered to the model.
+type ToolDefinition struct {
+	Name        string
+	Description string
+	Parameters  json.RawMessage
+}
+
+// UsageAccumulator records requests and their usage in memory, for example
+// to build fine-tuning datasets with ExportToJSONL. It is safe for
+// concurrent use.
+type UsageAccumulator struct {
+	mu            sync.RWMutex
+	records       []UsageRecord
+	recordContent bool
+}
+
+// AccumulatorOption configures a UsageAccumulator.
+type AccumulatorOption func(*UsageAccumulator)
+
+// WithContentRecording enables recording full message content, tools and
+// responses. It is off by default because content may contain personal data.
+func WithContentRecording(enabled bool) AccumulatorOption {
+	return func(a *UsageAccumulator) {
+		a.recordContent = enabled
+	}
+}
+
+// NewUsageAccumulator creates an empty accumulator.
+func NewUsageAccumulator(opts ...AccumulatorOption) *UsageAccumulator {
+	a := &UsageAccumulator{}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// RecordsContent reports whether the accumulator records message content.
+func (a *UsageAccumulator) RecordsContent() bool {
+	return a.recordContent
+}
+
+// Record adds a completed request to the accumulator.
+func (a *UsageAccumulator) Record(req core.Request, result *core.TextResult) {
+	if result == nil {
+		return
+	}
+
+	record := UsageRecord{
+		Timestamp: time.Now(),
+		Model:     req.Model,
+		Usage:     result.Usage,
+	}
+	for _, step := range result.Steps {
+		if len(step.ToolCalls) > 0 {
+			record.HasToolCalls = true
+			break
+		}
+	}
+
+	if a.recordContent {
+		record.Messages = append([]core.Message(nil), req.Messages...)
+		record.Text = result.Text
+		record.Steps = append([]core.Step(nil), result.Steps...)
+		for _, tool := range req.Tools {
+			record.Tools = append(record.Tools, ToolDefinition{
+				Name:        tool.Name(),
+				Description: tool.Description(),
+				Parameters:  json.RawMessage(tool.InSchemaJSON()),
+			})
+		}
+	}
+
+	a.mu.Lock()
+	a.records = append(a.records, record)
+	a.mu.Unlock()
+}
+
+// Records returns a copy of the recorded requests in the order they completed.
+func (a *UsageAccumulator) Records() []UsageRecord {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return append([]UsageRecord(nil), a.records...)
+}
+
+// TotalUsage returns the summed usage of all recorded requests.
+func (a *UsageAccumulator) TotalUsage() core.Usage {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var total core.Usage
+	for _, r := range a.records {
+		total.InputTokens += r.Usage.InputTokens
+		total.OutputTokens += r.Usage.OutputTokens
+		total.TotalTokens += r.Usage.TotalTokens
+		total.CacheReadTokens += r.Usage.CacheReadTokens
+		total.CacheCreationTokens += r.Usage.CacheCreationTokens
+	}
+	return total
+}
+
+// Reset removes all recorded requests.
+func (a *UsageAccumulator) Reset() {
+	a.mu.Lock()
+	a.records = nil
+	a.mu.Unlock()
+}
+
+// UsageAccumulatorMiddleware returns provider middleware that records every
+// successful GenerateText call and completed StreamText call in acc.
+//
+// Example:
+//
+//	acc := obs.NewUsageAccumulator(obs.WithContentRecording(true))
+//	provider = obs.UsageAccumulatorMiddleware(acc)(provider)
+func UsageAccumulatorMiddleware(acc *UsageAccumulator) func(core.Provider) core.Provider {
+	return func(provider core.Provider) core.Provider {
+		return &accumulatingProvider{Provider: provider, acc: acc}
+	}
+}
+
+// accumulatingProvider records text generation in a UsageAccumulator.
+type accumulatingProvider struct {
+	core.Provider
+	acc *UsageAccumulator
+}
+
+// GenerateText implements core.Provider.
+func (p *accumulatingProvider) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	result, err := p.Provider.GenerateText(ctx, req)
+	if err == nil {
+		p.acc.Record(req, result)
+	}
+	return result, err
+}
+
+// StreamText implements core.Provider. The request is recorded when the
+// stream delivers its finish event.
+func (p *accumulatingProvider) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	stream, err := p.Provider.StreamText(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &accumulatingStream{
+		TextStream: stream,
+		events:     make(chan core.Event, 100),
+		done:       make(chan struct{}),
+	}
+	go s.forward(func(result *core.TextResult) { p.acc.Record(req, result) })
+	return s, nil
+}
+
+// accumulatingStream forwards events while building the result to record.
+type accumulatingStream struct {
+	core.TextStream
+	events    chan core.Event
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Events implements core.TextStream.
+func (s *accumulatingStream) Events() <-chan core.Event {
+	return s.events
+}
+
+// Close implements core.TextStream.
+func (s *accumulatingStream) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return s.TextStream.Close()
+}
+
+// forward relays events and calls record with the assembled result on finish.
+func (s *accumulatingStream) forward(record func(*core.TextResult)) {
+	defer close(s.events)
+
+	result := &core.TextResult{}
+	var step core.Step
+	var stepUsage core.Usage
+	for event := range s.TextStream.Events() {
+		switch event.Type {
+		case core.EventTextDelta:
+			result.Text += event.TextDelta
+			step.Text += event.TextDelta
+		case core.EventToolCall:
+			step.ToolCalls = append(step.ToolCalls, core.ToolCall{ID: event.ToolID, Name: event.ToolName, Input: event.ToolInput})
+		case core.EventToolResult:
+			execution := core.ToolExecution{ID: event.ToolID, Name: event.ToolName, Result: event.ToolResult}
+			if event.Err != nil {
+				execution.Error = event.Err.Error()
+			}
+			step.ToolResults = append(step.ToolResults, execution)
+		case core.EventFinishStep:
+			if event.Usage != nil {
+				step.Usage = *event.Usage
+				stepUsage.Add(*event.Usage)
+			}
+			step.StepNumber = len(result.Steps) + 1
+			result.Steps = append(result.Steps, step)
+			step = core.Step{}
+		case core.EventFinish:
+			if len(step.ToolCalls) > 0 || (len(result.Steps) > 0 && step.Text != "") {
+				step.StepNumber = len(result.Steps) + 1
+				result.Steps = append(result.Steps, step)
+			}
+			if event.Usage != nil {
+				result.Usage = *event.Usage
+			} else {
+				// Streams that only report usage per step are approximated
+				// by the sum of their steps
+				result.Usage = stepUsage
+			}
+			record(result)
+		}
+
+		select {
+		case s.events <- event:
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package obs
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// OpenTelemetry GenAI semantic convention attribute keys. Use these instead of
+// string literals so that typos are caught at compile time, for example
+// span.SetAttributes(obs.AttrGenAIRequestModel.String(model)).
+const (
+	// AttrGenAISystem identifies the GenAI provider (e.g. "openai", "anthropic")
+	AttrGenAISystem = attribute.Key("gen_ai.system")
+	// AttrGenAIOperationName is the operation performed (e.g. "chat", "embeddings")
+	AttrGenAIOperationName = attribute.Key("gen_ai.operation.name")
+	// AttrGenAIOutputType is the requested output type (e.g. "text", "json")
+	AttrGenAIOutputType = attribute.Key("gen_ai.output.type")
+	// AttrGenAIConversationID identifies the conversation or session
+	AttrGenAIConversationID = attribute.Key("gen_ai.conversation.id")
+	// AttrGenAIDataSourceID identifies the data source used for retrieval
+	AttrGenAIDataSourceID = attribute.Key("gen_ai.data_source.id")
+
+	// AttrGenAIRequestModel is the model name requested
+	AttrGenAIRequestModel = attribute.Key("gen_ai.request.model")
+	// AttrGenAIRequestTemperature is the requested sampling temperature
+	AttrGenAIRequestTemperature = attribute.Key("gen_ai.request.temperature")
+	// AttrGenAIRequestMaxTokens is the requested maximum number of output tokens
+	AttrGenAIRequestMaxTokens = attribute.Key("gen_ai.request.max_tokens")
+	// AttrGenAIRequestTopP is the requested nucleus sampling probability
+	AttrGenAIRequestTopP = attribute.Key("gen_ai.request.top_p")
+	// AttrGenAIRequestTopK is the requested top-k sampling setting
+	AttrGenAIRequestTopK = attribute.Key("gen_ai.request.top_k")
+	// AttrGenAIRequestFrequencyPenalty is the requested frequency penalty
+	AttrGenAIRequestFrequencyPenalty = attribute.Key("gen_ai.request.frequency_penalty")
+	// AttrGenAIRequestPresencePenalty is the requested presence penalty
+	AttrGenAIRequestPresencePenalty = attribute.Key("gen_ai.request.presence_penalty")
+	// AttrGenAIRequestStopSequences lists the requested stop sequences
+	AttrGenAIRequestStopSequences = attribute.Key("gen_ai.request.stop_sequences")
+	// AttrGenAIRequestSeed is the requested random seed
+	AttrGenAIRequestSeed = attribute.Key("gen_ai.request.seed")
+	// AttrGenAIRequestChoiceCount is the requested number of choices
+	AttrGenAIRequestChoiceCount = attribute.Key("gen_ai.request.choice.count")
+	// AttrGenAIRequestEncodingFormats lists the requested embedding encodings
+	AttrGenAIRequestEncodingFormats = attribute.Key("gen_ai.request.encoding_formats")
+	// AttrGenAIVisionDetail is the requested image detail level ("low", "high" or "auto")
+	AttrGenAIVisionDetail = attribute.Key("gen_ai.vision.detail")
+
+	// AttrGenAIResponseID is the provider's identifier for the completion
+	AttrGenAIResponseID = attribute.Key("gen_ai.response.id")
+	// AttrGenAIResponseModel is the model that generated the response
+	AttrGenAIResponseModel = attribute.Key("gen_ai.response.model")
+	// AttrGenAIResponseFinishReasons lists why generation stopped for each choice
+	AttrGenAIResponseFinishReasons = attribute.Key("gen_ai.response.finish_reasons")
+
+	// AttrGenAIUsageInputTokens is the number of tokens in the prompt
+	AttrGenAIUsageInputTokens = attribute.Key("gen_ai.usage.input_tokens")
+	// AttrGenAIUsageOutputTokens is the number of tokens in the response
+	AttrGenAIUsageOutputTokens = attribute.Key("gen_ai.usage.output_tokens")
+	// AttrGenAIUsageCacheReadInputTokens is the number of prompt tokens read from the cache
+	AttrGenAIUsageCacheReadInputTokens = attribute.Key("gen_ai.usage.cache_read_input_tokens")
+	// AttrGenAIUsageCacheCreationInputTokens is the number of prompt tokens written to the cache
+	AttrGenAIUsageCacheCreationInputTokens = attribute.Key("gen_ai.usage.cache_creation_input_tokens")
+	// AttrGenAITokenType is the token type of a usage metric ("input" or "output")
+	AttrGenAITokenType = attribute.Key("gen_ai.token.type")
+
+	// AttrGenAIAgentID identifies the agent
+	AttrGenAIAgentID = attribute.Key("gen_ai.agent.id")
+	// AttrGenAIAgentName is the human-readable agent name
+	AttrGenAIAgentName = attribute.Key("gen_ai.agent.name")
+	// AttrGenAIAgentDescription describes the agent
+	AttrGenAIAgentDescription = attribute.Key("gen_ai.agent.description")
+
+	// AttrGenAIToolName is the name of the tool being executed
+	AttrGenAIToolName = attribute.Key("gen_ai.tool.name")
+	// AttrGenAIToolCallID identifies the tool call
+	AttrGenAIToolCallID = attribute.Key("gen_ai.tool.call.id")
+	// AttrGenAIToolDescription describes the tool
+	AttrGenAIToolDescription = attribute.Key("gen_ai.tool.description")
+	// AttrGenAIToolType is the tool type (e.g. "function", "extension")
+	AttrGenAIToolType = attribute.Key("gen_ai.tool.type")
+)
+
+// Legacy and GAI-specific GenAI attribute keys. The prompt, completion and
+// usage keys are the older convention still read by platforms such as
+// Braintrust; the stream keys describe streaming responses.
+const (
+	// AttrGenAIPrompt is the prompt content
+	AttrGenAIPrompt = attribute.Key("gen_ai.prompt")
+	// AttrGenAIPromptJSON is the full prompt as a JSON array of messages
+	AttrGenAIPromptJSON = attribute.Key("gen_ai.prompt_json")
+	// AttrGenAICompletion is the completion content
+	AttrGenAICompletion = attribute.Key("gen_ai.completion")
+	// AttrGenAICompletionFinishReason is why the completion stopped
+	AttrGenAICompletionFinishReason = attribute.Key("gen_ai.completion.finish_reason")
+	// AttrGenAIRequestMessageCount is the number of messages in the request
+	AttrGenAIRequestMessageCount = attribute.Key("gen_ai.request.message_count")
+	// AttrGenAITools lists the names of the tools offered to the model
+	AttrGenAITools = attribute.Key("gen_ai.tools")
+	// AttrGenAIUsagePromptTokens is the number of prompt tokens
+	AttrGenAIUsagePromptTokens = attribute.Key("gen_ai.usage.prompt_tokens")
+	// AttrGenAIUsageCompletionTokens is the number of completion tokens
+	AttrGenAIUsageCompletionTokens = attribute.Key("gen_ai.usage.completion_tokens")
+	// AttrGenAIUsageTotalTokens is the total number of tokens
+	AttrGenAIUsageTotalTokens = attribute.Key("gen_ai.usage.total_tokens")
+	// AttrGenAICostUSD is the estimated request cost in US dollars
+	AttrGenAICostUSD = attribute.Key("gen_ai.cost.usd")
+	// AttrGenAICitationsCount is the number of source citations in the response
+	AttrGenAICitationsCount = attribute.Key("gen_ai.citations.count")
+	// AttrGenAIStepsCount is the number of steps in a multi-step result
+	AttrGenAIStepsCount = attribute.Key("gen_ai.steps.count")
+
+	// AttrGenAIStreamTotalChunks is the number of chunks streamed
+	AttrGenAIStreamTotalChunks = attribute.Key("gen_ai.stream.total_chunks")
+	// AttrGenAIStreamTotalBytes is the number of bytes streamed
+	AttrGenAIStreamTotalBytes = attribute.Key("gen_ai.stream.total_bytes")
+	// AttrGenAIStreamDurationMs is the stream duration in milliseconds
+	AttrGenAIStreamDurationMs = attribute.Key("gen_ai.stream.duration_ms")
+	// AttrGenAIStreamBytesPerSecond is the stream throughput
+	AttrGenAIStreamBytesPerSecond = attribute.Key("gen_ai.stream.bytes_per_second")
+)
+
+// GenAI span event names.
+const (
+	EventGenAISystemMessage    = "gen_ai.system.message"
+	EventGenAIUserMessage      = "gen_ai.user.message"
+	EventGenAIAssistantMessage = "gen_ai.assistant.message"
+	EventGenAIToolMessage      = "gen_ai.tool.message"
+	EventGenAIChoice           = "gen_ai.choice"
+	EventGenAIStreamStart      = "gen_ai.stream.start"
+	EventGenAIStreamChunk      = "gen_ai.stream.chunk"
+	EventGenAIStreamError      = "gen_ai.stream.error"
+	EventGenAIStreamComplete   = "gen_ai.stream.complete"
+)
+
+// GenAIPromptRoleKey returns the key for the role of the prompt message at index i.
+func GenAIPromptRoleKey(i int) attribute.Key {
+	return attribute.Key(fmt.Sprintf("gen_ai.prompt.%d.role", i))
+}
+
+// GenAIPromptContentKey returns the key for the content of the prompt message at index i.
+func GenAIPromptContentKey(i int) attribute.Key {
+	return attribute.Key(fmt.Sprintf("gen_ai.prompt.%d.content", i))
+}
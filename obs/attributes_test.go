@@ -0,0 +1,31 @@
+package obs
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestGenAIAttributeKeys(t *testing.T) {
+	tests := []struct {
+		key  attribute.Key
+		want string
+	}{
+		{AttrGenAISystem, "gen_ai.system"},
+		{AttrGenAIRequestModel, "gen_ai.request.model"},
+		{AttrGenAIUsageInputTokens, "gen_ai.usage.input_tokens"},
+		{AttrGenAIResponseFinishReasons, "gen_ai.response.finish_reasons"},
+		{GenAIPromptRoleKey(2), "gen_ai.prompt.2.role"},
+		{GenAIPromptContentKey(0), "gen_ai.prompt.0.content"},
+	}
+	for _, tt := range tests {
+		if string(tt.key) != tt.want {
+			t.Errorf("got key %q, want %q", tt.key, tt.want)
+		}
+	}
+
+	kv := AttrGenAIRequestModel.String("gpt-4o-mini")
+	if kv.Key != "gen_ai.request.model" || kv.Value.AsString() != "gpt-4o-mini" {
+		t.Errorf("unexpected attribute: %v", kv)
+	}
+}
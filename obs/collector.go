@@ -16,15 +16,48 @@ type Collector struct {
 	provider        string
 	model           string
 	usageCollector  *UsageCollector
+	opts            CollectorOpts
 }
 
-// NewCollector creates a new metrics collector
-func NewCollector(ctx context.Context, provider, model string) *Collector {
+// CollectorOpts controls which usage breakdowns a Collector records. Each
+// breakdown adds a map update per recorded request or tool call.
+type CollectorOpts struct {
+	// BreakdownByModel adds token usage to ProviderUsage.ModelUsage, keyed by
+	// the collector's model
+	BreakdownByModel bool
+	// BreakdownByTool adds tool executions to ProviderUsage.ToolUsage
+	BreakdownByTool bool
+}
+
+// DefaultCollectorOpts are used by NewCollector when no options are given:
+// usage is broken down by model but not by tool.
+var DefaultCollectorOpts = CollectorOpts{BreakdownByModel: true}
+
+// NewCollector creates a new metrics collector. Options default to
+// DefaultCollectorOpts; the breakdowns are returned by GetUsage.
+//
+// Example:
+//
+//	collector := obs.NewCollector(ctx, "openai", "gpt-4o", obs.CollectorOpts{
+//	    BreakdownByModel: true,
+//	    BreakdownByTool:  true,
+//	})
+//	runner := core.NewRunner(provider, core.WithMetrics(collector))
+//	// ...
+//	for name, tool := range collector.GetUsage().ToolUsage {
+//	    fmt.Printf("%s: %d calls, %.1fms avg\n", name, tool.Calls, tool.AvgDurationMs)
+//	}
+func NewCollector(ctx context.Context, provider, model string, opts ...CollectorOpts) *Collector {
+	o := DefaultCollectorOpts
+	if len(opts) > 0 {
+		o = opts[0]
+	}
 	return &Collector{
 		ctx:            ctx,
 		provider:       provider,
 		model:          model,
 		usageCollector: GlobalUsageCollector(),
+		opts:           o,
 	}
 }
 
@@ -43,6 +76,7 @@ func (c *Collector) RecordStep(step core.Step, duration time.Duration) {
 			HasToolCalls: len(step.ToolCalls) > 0,
 			ToolCount:    len(step.ToolCalls),
 			TextLength:   len(step.Text),
+			Usage:        &step.Usage,
 		})
 		defer stepSpan.End()
 		
@@ -77,6 +111,9 @@ func (c *Collector) RecordToolExecution(name string, duration time.Duration, err
 	
 	// Record metrics
 	RecordToolExecution(toolCtx, name, success, duration)
+	if c.opts.BreakdownByTool && c.usageCollector != nil {
+		c.usageCollector.RecordTool(c.provider, name, duration, err)
+	}
 }
 
 // RecordTotalExecution records metrics for the total execution
@@ -95,7 +132,14 @@ func (c *Collector) RecordTotalExecution(steps int, duration time.Duration) {
 
 // RecordUsageMetrics records token usage metrics
 func (c *Collector) RecordUsageMetrics(inputTokens, outputTokens int) {
-	RecordUsageData(c.ctx, c.provider, c.model, inputTokens, outputTokens)
+	if c.usageCollector != nil {
+		c.usageCollector.record(c.provider, c.model, Usage{
+			InputTokens:             inputTokens,
+			OutputTokens:            outputTokens,
+			TotalTokens:             inputTokens + outputTokens,
+			EstimatedCostMicrocents: EstimateCost(c.model, inputTokens, outputTokens),
+		}, c.opts.BreakdownByModel)
+	}
 	RecordTokens(c.ctx, c.provider, c.model, inputTokens, outputTokens)
 	
 	// Add to span if available
@@ -119,7 +163,14 @@ func (c *Collector) EndRequest() {
 	DecrementActiveRequests(c.ctx, c.provider)
 }
 
-// GetUsage returns the current usage data for the provider
+// Context returns the collector's context, which carries the request span
+// when one was started
+func (c *Collector) Context() context.Context {
+	return c.ctx
+}
+
+// GetUsage returns the current usage data for the provider, including the
+// per-model and per-tool breakdowns enabled by CollectorOpts
 func (c *Collector) GetUsage() *ProviderUsage {
 	if c.usageCollector != nil {
 		return c.usageCollector.GetProviderUsage(c.provider)
@@ -141,8 +192,15 @@ type IntegratedCollector struct {
 
 // NewIntegratedCollector creates a new integrated metrics collector
 func NewIntegratedCollector(ctx context.Context, req core.Request) *IntegratedCollector {
-	// Extract provider and model from request (these would normally come from provider config)
-	provider := "unknown"
+	return NewProviderCollector(ctx, "unknown", req)
+}
+
+// NewProviderCollector creates an integrated metrics collector for a request
+// sent to the named provider
+func NewProviderCollector(ctx context.Context, provider string, req core.Request) *IntegratedCollector {
+	if provider == "" {
+		provider = "unknown"
+	}
 	model := req.Model
 	if model == "" {
 		model = "unknown"
@@ -153,7 +211,7 @@ func NewIntegratedCollector(ctx context.Context, req core.Request) *IntegratedCo
 		Provider:     provider,
 		Model:        model,
 		Temperature:  req.Temperature,
-		MaxTokens:    req.MaxTokens,
+		MaxTokens:    core.OutputTokenLimit(req),
 		Stream:       req.Stream,
 		ToolCount:    len(req.Tools),
 		MessageCount: len(req.Messages),
@@ -174,6 +232,19 @@ func NewIntegratedCollector(ctx context.Context, req core.Request) *IntegratedCo
 	}
 }
 
+// SetResponseModel attributes the request's usage, cost and metrics to model,
+// the model that served it (core.TextResult.ModelUsed), and records it on the
+// request span when it differs from the requested model. An empty model is
+// ignored. Call it before Complete.
+func (ic *IntegratedCollector) SetResponseModel(model string) {
+	if model == "" {
+		return
+	}
+	RecordResponseModel(ic.requestSpan, ic.model, model)
+	ic.model = model
+	ic.Collector.model = model
+}
+
 // Complete finalizes the metrics collection
 func (ic *IntegratedCollector) Complete(success bool, usage *core.Usage, err error) {
 	defer ic.EndRequest()
@@ -181,6 +252,7 @@ func (ic *IntegratedCollector) Complete(success bool, usage *core.Usage, err err
 	// Record usage if available
 	if usage != nil {
 		ic.RecordUsageMetrics(usage.InputTokens, usage.OutputTokens)
+		RecordCacheUsage(ic.requestSpan, usage.CacheReadTokens, usage.CacheCreationTokens)
 	}
 	
 	// Record error if present
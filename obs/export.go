@@ -0,0 +1,210 @@
+package obs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/recera/gai/core"
+)
+
+// FineTuningFilter selects which records ExportToJSONL writes.
+type FineTuningFilter struct {
+	// MinTokens skips records with fewer total tokens (0 means no minimum)
+	MinTokens int
+	// MaxTokens skips records with more total tokens (0 means no maximum)
+	MaxTokens int
+	// ExcludeToolCalls skips records whose conversation or response involves tools
+	ExcludeToolCalls bool
+}
+
+// matches reports whether the record passes the filter.
+func (f FineTuningFilter) matches(r UsageRecord) bool {
+	if f.MinTokens > 0 && r.Usage.TotalTokens < f.MinTokens {
+		return false
+	}
+	if f.MaxTokens > 0 && r.Usage.TotalTokens > f.MaxTokens {
+		return false
+	}
+	if f.ExcludeToolCalls {
+		if r.HasToolCalls {
+			return false
+		}
+		for _, msg := range r.Messages {
+			if msg.Role == core.Tool {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// fineTuningRecord is one line of an OpenAI chat fine-tuning file.
+type fineTuningRecord struct {
+	Messages []fineTuningMessage `json:"messages"`
+	Model    string              `json:"model,omitempty"`
+	Tools    []fineTuningTool    `json:"tools,omitempty"`
+}
+
+type fineTuningMessage struct {
+	Role       string               `json:"role"`
+	Content    any                  `json:"content"`
+	Name       string               `json:"name,omitempty"`
+	ToolCalls  []fineTuningToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string               `json:"tool_call_id,omitempty"`
+}
+
+type fineTuningToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type fineTuningTool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Parameters  json.RawMessage `json:"parameters,omitempty"`
+	} `json:"function"`
+}
+
+// ExportToJSONL writes the accumulator's records to path in OpenAI chat
+// fine-tuning format, one {"messages": [...], "model": "...", "tools": [...]}
+// object per line. Each line contains the full conversation sent to the model
+// followed by the response, including assistant tool calls and tool results,
+// so multi-turn structure is preserved.
+//
+// Records are written only if they pass every filter. The accumulator must
+// have been created with WithContentRecording(true).
+func ExportToJSONL(ctx context.Context, accumulator *UsageAccumulator, path string, filters ...FineTuningFilter) error {
+	if !accumulator.RecordsContent() {
+		return errors.New("obs: accumulator does not record content; create it with WithContentRecording(true)")
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating export file: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+records:
+	for _, record := range accumulator.Records() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, filter := range filters {
+			if !filter.matches(record) {
+				continue records
+			}
+		}
+		if err := encoder.Encode(toFineTuningRecord(record)); err != nil {
+			return fmt.Errorf("encoding record: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("writing export file: %w", err)
+	}
+	return f.Close()
+}
+
+// toFineTuningRecord converts a record to the OpenAI fine-tuning format.
+func toFineTuningRecord(r UsageRecord) fineTuningRecord {
+	out := fineTuningRecord{Model: r.Model}
+
+	for _, msg := range r.Messages {
+		out.Messages = append(out.Messages, fineTuningMessage{
+			Role:       string(msg.Role),
+			Content:    fineTuningContent(msg.Parts),
+			Name:       msg.Name,
+			ToolCallID: msg.ToolCallID,
+		})
+	}
+
+	if len(r.Steps) == 0 {
+		out.Messages = append(out.Messages, fineTuningMessage{Role: string(core.Assistant), Content: r.Text})
+	}
+	for _, step := range r.Steps {
+		if len(step.ToolCalls) == 0 {
+			if step.Text != "" {
+				out.Messages = append(out.Messages, fineTuningMessage{Role: string(core.Assistant), Content: step.Text})
+			}
+			continue
+		}
+
+		assistant := fineTuningMessage{Role: string(core.Assistant)}
+		if step.Text != "" {
+			assistant.Content = step.Text
+		}
+		for _, call := range step.ToolCalls {
+			tc := fineTuningToolCall{ID: call.ID, Type: "function"}
+			tc.Function.Name = call.Name
+			tc.Function.Arguments = string(call.Input)
+			assistant.ToolCalls = append(assistant.ToolCalls, tc)
+		}
+		out.Messages = append(out.Messages, assistant)
+
+		for _, result := range step.ToolResults {
+			content := "Error: " + result.Error
+			if result.Error == "" {
+				data, _ := json.Marshal(result.Result)
+				content = string(data)
+			}
+			out.Messages = append(out.Messages, fineTuningMessage{
+				Role:       string(core.Tool),
+				Content:    content,
+				ToolCallID: result.ID,
+			})
+		}
+	}
+
+	for _, tool := range r.Tools {
+		ft := fineTuningTool{Type: "function"}
+		ft.Function.Name = tool.Name
+		ft.Function.Description = tool.Description
+		ft.Function.Parameters = tool.Parameters
+		out.Tools = append(out.Tools, ft)
+	}
+
+	return out
+}
+
+// fineTuningContent returns a plain string for text-only messages and an
+// array of content parts when images are present.
+func fineTuningContent(parts []core.Part) any {
+	var texts []string
+	var contentParts []map[string]any
+	hasImage := false
+
+	for _, part := range parts {
+		switch p := part.(type) {
+		case core.Text:
+			texts = append(texts, p.Text)
+			contentParts = append(contentParts, map[string]any{"type": "text", "text": p.Text})
+		case core.ImageURL:
+			hasImage = true
+			image := map[string]any{"url": p.URL}
+			if p.Detail != "" {
+				image["detail"] = p.Detail
+			}
+			contentParts = append(contentParts, map[string]any{"type": "image_url", "image_url": image})
+		}
+	}
+
+	if hasImage {
+		return contentParts
+	}
+	return strings.Join(texts, "\n")
+}
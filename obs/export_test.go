@@ -0,0 +1,200 @@
+package obs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+// staticProvider returns a fixed result from GenerateText.
+type staticProvider struct {
+	core.Provider
+	result *core.TextResult
+}
+
+func (p *staticProvider) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	return p.result, nil
+}
+
+// lookupTool is a minimal core.ToolHandle.
+type lookupTool struct{}
+
+func (lookupTool) Name() string                   { return "lookup" }
+func (lookupTool) Description() string            { return "Look up an order" }
+func (lookupTool) InSchemaJSON() json.RawMessage  { return []byte(`{"type":"object"}`) }
+func (lookupTool) OutSchemaJSON() json.RawMessage { return []byte(`{}`) }
+func (lookupTool) Exec(ctx context.Context, raw json.RawMessage, meta interface{}) (any, error) {
+	return nil, nil
+}
+
+func readJSONL(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open export: %v", err)
+	}
+	defer f.Close()
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid JSONL line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestExportToJSONL(t *testing.T) {
+	acc := NewUsageAccumulator(WithContentRecording(true))
+	ctx := context.Background()
+
+	// A plain multi-turn exchange
+	plain := UsageAccumulatorMiddleware(acc)(&staticProvider{result: &core.TextResult{
+		Text:  "Paris",
+		Usage: core.Usage{TotalTokens: 50},
+	}})
+	plain.GenerateText(ctx, core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.System, Parts: []core.Part{core.Text{Text: "Be brief"}}},
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Capital of France?"}}},
+		},
+	})
+
+	// A tool-calling exchange
+	withTools := UsageAccumulatorMiddleware(acc)(&staticProvider{result: &core.TextResult{
+		Text:  "Shipped",
+		Usage: core.Usage{TotalTokens: 200},
+		Steps: []core.Step{
+			{
+				ToolCalls:   []core.ToolCall{{ID: "call_1", Name: "lookup", Input: json.RawMessage(`{"id":7}`)}},
+				ToolResults: []core.ToolExecution{{ID: "call_1", Name: "lookup", Result: map[string]any{"status": "shipped"}}},
+			},
+			{Text: "Shipped"},
+		},
+	}})
+	withTools.GenerateText(ctx, core.Request{
+		Model:    "gpt-4o",
+		Messages: []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Where is order 7?"}}}},
+		Tools:    []core.ToolHandle{lookupTool{}},
+	})
+
+	path := filepath.Join(t.TempDir(), "train.jsonl")
+	if err := ExportToJSONL(ctx, acc, path); err != nil {
+		t.Fatalf("ExportToJSONL failed: %v", err)
+	}
+
+	lines := readJSONL(t, path)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	first := lines[0]["messages"].([]any)
+	if len(first) != 3 || first[2].(map[string]any)["content"] != "Paris" || lines[0]["model"] != "gpt-4o-mini" {
+		t.Errorf("Unexpected first record: %v", lines[0])
+	}
+
+	// user, assistant tool call, tool result, final assistant answer
+	second := lines[1]["messages"].([]any)
+	if len(second) != 4 {
+		t.Fatalf("Expected 4 messages in tool record, got %v", second)
+	}
+	call := second[1].(map[string]any)["tool_calls"].([]any)[0].(map[string]any)
+	if call["id"] != "call_1" || call["function"].(map[string]any)["arguments"] != `{"id":7}` {
+		t.Errorf("Unexpected tool call: %v", call)
+	}
+	if result := second[2].(map[string]any); result["tool_call_id"] != "call_1" || result["content"] != `{"status":"shipped"}` {
+		t.Errorf("Unexpected tool result: %v", result)
+	}
+	if tools := lines[1]["tools"].([]any); len(tools) != 1 {
+		t.Errorf("Expected tool definitions, got %v", tools)
+	}
+
+	// Filters
+	if err := ExportToJSONL(ctx, acc, path, FineTuningFilter{ExcludeToolCalls: true}); err != nil {
+		t.Fatalf("ExportToJSONL failed: %v", err)
+	}
+	if lines := readJSONL(t, path); len(lines) != 1 || lines[0]["model"] != "gpt-4o-mini" {
+		t.Errorf("Expected only the plain record, got %v", lines)
+	}
+	if err := ExportToJSONL(ctx, acc, path, FineTuningFilter{MinTokens: 100}); err != nil {
+		t.Fatalf("ExportToJSONL failed: %v", err)
+	}
+	if lines := readJSONL(t, path); len(lines) != 1 || lines[0]["model"] != "gpt-4o" {
+		t.Errorf("Expected only the tool record, got %v", lines)
+	}
+}
+
+func TestExportToJSONLRequiresContent(t *testing.T) {
+	acc := NewUsageAccumulator()
+	acc.Record(core.Request{Messages: []core.Message{{Role: core.User}}}, &core.TextResult{Usage: core.Usage{TotalTokens: 3}})
+
+	if records := acc.Records(); len(records) != 1 || records[0].Messages != nil {
+		t.Errorf("Expected usage-only record, got %+v", records)
+	}
+	if total := acc.TotalUsage(); total.TotalTokens != 3 {
+		t.Errorf("Expected 3 total tokens, got %d", total.TotalTokens)
+	}
+
+	err := ExportToJSONL(context.Background(), acc, filepath.Join(t.TempDir(), "out.jsonl"))
+	if err == nil {
+		t.Error("Expected error when content recording is disabled")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	acc = NewUsageAccumulator(WithContentRecording(true))
+	acc.Record(core.Request{}, &core.TextResult{})
+	if err := ExportToJSONL(ctx, acc, filepath.Join(t.TempDir(), "out.jsonl")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context cancellation, got %v", err)
+	}
+}
+
+// chanStream is a TextStream over a pre-filled channel.
+type chanStream struct {
+	events chan core.Event
+}
+
+func (s *chanStream) Events() <-chan core.Event { return s.events }
+func (s *chanStream) Close() error              { return nil }
+
+func (p *staticProvider) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	events := make(chan core.Event, 3)
+	events <- core.Event{Type: core.EventTextDelta, TextDelta: "Hel"}
+	events <- core.Event{Type: core.EventTextDelta, TextDelta: "lo"}
+	events <- core.Event{Type: core.EventFinish, Usage: &core.Usage{TotalTokens: 9}}
+	close(events)
+	return &chanStream{events: events}, nil
+}
+
+func TestUsageAccumulatorStream(t *testing.T) {
+	acc := NewUsageAccumulator(WithContentRecording(true))
+	provider := UsageAccumulatorMiddleware(acc)(&staticProvider{})
+
+	stream, err := provider.StreamText(context.Background(), core.Request{Model: "m"})
+	if err != nil {
+		t.Fatalf("StreamText failed: %v", err)
+	}
+	count := 0
+	for range stream.Events() {
+		count++
+	}
+	stream.Close()
+
+	if count != 3 {
+		t.Errorf("Expected all 3 events forwarded, got %d", count)
+	}
+	records := acc.Records()
+	if len(records) != 1 || records[0].Text != "Hello" || records[0].Usage.TotalTokens != 9 {
+		t.Errorf("Unexpected records: %+v", records)
+	}
+}
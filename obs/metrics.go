@@ -165,8 +165,8 @@ func RecordRequest(ctx context.Context, provider, model string, success bool, du
 		attribute.Bool("success", success),
 	}
 	
-	requestCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
-	requestDuration.Record(ctx, float64(duration.Milliseconds()), metric.WithAttributes(attrs...))
+	requestCounter.Add(ctx, 1, withTags(ctx, attrs...))
+	requestDuration.Record(ctx, float64(duration.Milliseconds()), withTags(ctx, attrs...))
 }
 
 // RecordTokens records token usage metrics
@@ -176,7 +176,7 @@ func RecordTokens(ctx context.Context, provider, model string, inputTokens, outp
 	}
 	
 	if inputTokens > 0 {
-		tokenCounter.Add(ctx, int64(inputTokens), metric.WithAttributes(
+		tokenCounter.Add(ctx, int64(inputTokens), withTags(ctx, 
 			attribute.String("provider", provider),
 			attribute.String("model", model),
 			attribute.String("type", "input"),
@@ -184,7 +184,7 @@ func RecordTokens(ctx context.Context, provider, model string, inputTokens, outp
 	}
 	
 	if outputTokens > 0 {
-		tokenCounter.Add(ctx, int64(outputTokens), metric.WithAttributes(
+		tokenCounter.Add(ctx, int64(outputTokens), withTags(ctx, 
 			attribute.String("provider", provider),
 			attribute.String("model", model),
 			attribute.String("type", "output"),
@@ -203,8 +203,8 @@ func RecordToolExecution(ctx context.Context, toolName string, success bool, dur
 		attribute.Bool("success", success),
 	}
 	
-	toolExecutionCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
-	toolDuration.Record(ctx, float64(duration.Milliseconds()), metric.WithAttributes(attrs...))
+	toolExecutionCounter.Add(ctx, 1, withTags(ctx, attrs...))
+	toolDuration.Record(ctx, float64(duration.Milliseconds()), withTags(ctx, attrs...))
 }
 
 // RecordError records an error metric
@@ -213,7 +213,7 @@ func RecordErrorMetric(ctx context.Context, errorType, provider, model string) {
 		return
 	}
 	
-	errorCounter.Add(ctx, 1, metric.WithAttributes(
+	errorCounter.Add(ctx, 1, withTags(ctx, 
 		attribute.String("type", errorType),
 		attribute.String("provider", provider),
 		attribute.String("model", model),
@@ -226,7 +226,7 @@ func RecordStreamEvent(ctx context.Context, eventType, provider string) {
 		return
 	}
 	
-	streamEventCounter.Add(ctx, 1, metric.WithAttributes(
+	streamEventCounter.Add(ctx, 1, withTags(ctx, 
 		attribute.String("type", eventType),
 		attribute.String("provider", provider),
 	))
@@ -238,7 +238,7 @@ func IncrementActiveRequests(ctx context.Context, provider string) {
 		return
 	}
 	
-	activeRequests.Add(ctx, 1, metric.WithAttributes(
+	activeRequests.Add(ctx, 1, withTags(ctx, 
 		attribute.String("provider", provider),
 	))
 }
@@ -249,7 +249,7 @@ func DecrementActiveRequests(ctx context.Context, provider string) {
 		return
 	}
 	
-	activeRequests.Add(ctx, -1, metric.WithAttributes(
+	activeRequests.Add(ctx, -1, withTags(ctx, 
 		attribute.String("provider", provider),
 	))
 }
@@ -265,7 +265,7 @@ func RecordCacheHit(ctx context.Context, cacheType string, hit bool) {
 		ratio = 1.0
 	}
 	
-	cacheHitRatio.Record(ctx, ratio, metric.WithAttributes(
+	cacheHitRatio.Record(ctx, ratio, withTags(ctx, 
 		attribute.String("type", cacheType),
 	))
 }
@@ -276,7 +276,7 @@ func RecordPromptRender(ctx context.Context, name, version string, cacheHit bool
 		return
 	}
 	
-	promptRenderDuration.Record(ctx, float64(duration.Milliseconds()), metric.WithAttributes(
+	promptRenderDuration.Record(ctx, float64(duration.Milliseconds()), withTags(ctx, 
 		attribute.String("name", name),
 		attribute.String("version", version),
 		attribute.Bool("cache_hit", cacheHit),
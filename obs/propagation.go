@@ -0,0 +1,39 @@
+package obs
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// traceContext propagates spans using the W3C Trace Context headers
+// "traceparent" and "tracestate".
+var traceContext = propagation.TraceContext{}
+
+// ExtractTraceContext returns ctx carrying the remote span described by the
+// W3C trace context headers in carrier, so that spans started from it are
+// children of the upstream service's trace. If carrier holds no valid
+// traceparent header, ctx is returned unchanged.
+//
+// Example:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		ctx := obs.ExtractTraceContext(r.Context(), r.Header)
+//		result, err := provider.GenerateText(ctx, req)
+//	}
+func ExtractTraceContext(ctx context.Context, carrier http.Header) context.Context {
+	return traceContext.Extract(ctx, propagation.HeaderCarrier(carrier))
+}
+
+// InjectTraceContext writes the W3C trace context headers for the span in ctx
+// to carrier, so that a downstream service can continue the trace. It does
+// nothing if ctx holds no valid span.
+//
+// Example:
+//
+//	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+//	obs.InjectTraceContext(ctx, req.Header)
+func InjectTraceContext(ctx context.Context, carrier http.Header) {
+	traceContext.Inject(ctx, propagation.HeaderCarrier(carrier))
+}
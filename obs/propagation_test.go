@@ -0,0 +1,64 @@
+package obs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTraceContextPropagation(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+
+	// Downstream service starts a span as a child of the incoming request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := ExtractTraceContext(r.Context(), r.Header)
+		_, span := Tracer().Start(ctx, "downstream")
+		span.End()
+	}))
+	defer server.Close()
+
+	// Upstream service propagates its span on the outgoing request
+	ctx, parent := Tracer().Start(context.Background(), "upstream")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	InjectTraceContext(ctx, req.Header)
+	if req.Header.Get("traceparent") == "" {
+		t.Fatal("expected traceparent header to be injected")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	child := spans[0]
+	if child.Name != "downstream" {
+		t.Fatalf("expected downstream span first, got %s", child.Name)
+	}
+	if child.SpanContext.TraceID() != parent.SpanContext().TraceID() {
+		t.Error("child span is not in the upstream trace")
+	}
+	if child.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("child span's parent is not the upstream span")
+	}
+	if !child.Parent.IsRemote() {
+		t.Error("expected child span's parent to be remote")
+	}
+}
+
+func TestExtractTraceContextWithoutHeaders(t *testing.T) {
+	ctx := context.Background()
+	if got := ExtractTraceContext(ctx, http.Header{}); got != ctx {
+		t.Error("expected context to be unchanged without traceparent header")
+	}
+}
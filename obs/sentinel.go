@@ -0,0 +1,127 @@
+package obs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// DefaultSentinelCooldown is how long a tripped StatisticalSentinel blocks
+// requests when its Cooldown is zero.
+const DefaultSentinelCooldown = 5 * time.Minute
+
+// StatisticalSentinel is a threshold-based anomaly detector for use with
+// middleware.WithSentinel. Once a response exceeds any threshold, the
+// sentinel trips and flags every request as anomalous for Cooldown, so a
+// runaway loop or an abused endpoint stops spending tokens for a while. The
+// trip clears on its own once Cooldown has passed, or earlier with Reset. A
+// request whose output token limit exceeds MaxTokens is flagged without
+// tripping the sentinel.
+//
+// Zero thresholds are disabled. A StatisticalSentinel must not be copied
+// after first use.
+//
+// Example:
+//
+//	sentinel := &obs.StatisticalSentinel{MaxTokens: 4096, MaxToolCalls: 25, MaxDuration: 2 * time.Minute}
+//	provider = middleware.WithSentinel(sentinel)(provider)
+type StatisticalSentinel struct {
+	// MaxTokens is the largest number of output tokens in a single
+	// response, compared with Usage.OutputTokens and with the request's
+	// output token limit
+	MaxTokens int
+	// MaxToolCalls is the largest number of tool calls in a single response
+	MaxToolCalls int
+	// MaxDuration is the longest a single request may take
+	MaxDuration time.Duration
+	// Cooldown is how long a trip blocks requests, DefaultSentinelCooldown
+	// if zero
+	Cooldown time.Duration
+
+	mu        sync.Mutex
+	reason    string
+	trippedAt time.Time
+}
+
+// Observe checks a completed request's result against the token and tool
+// call thresholds.
+func (s *StatisticalSentinel) Observe(req core.Request, result *core.TextResult) {
+	if result == nil {
+		return
+	}
+	if s.MaxTokens > 0 && result.Usage.OutputTokens > s.MaxTokens {
+		s.trip(fmt.Sprintf("response used %d output tokens, limit is %d", result.Usage.OutputTokens, s.MaxTokens))
+	}
+	if s.MaxToolCalls > 0 {
+		calls := 0
+		for _, step := range result.Steps {
+			calls += len(step.ToolCalls)
+		}
+		if calls > s.MaxToolCalls {
+			s.trip(fmt.Sprintf("response made %d tool calls, limit is %d", calls, s.MaxToolCalls))
+		}
+	}
+}
+
+// ObserveDuration checks how long a completed request took against
+// MaxDuration.
+func (s *StatisticalSentinel) ObserveDuration(req core.Request, duration time.Duration) {
+	if s.MaxDuration > 0 && duration > s.MaxDuration {
+		s.trip(fmt.Sprintf("request took %v, limit is %v", duration.Round(time.Millisecond), s.MaxDuration))
+	}
+}
+
+// IsAnomaly reports whether req should be blocked: the sentinel tripped less
+// than Cooldown ago, or req asks for more than MaxTokens output tokens.
+func (s *StatisticalSentinel) IsAnomaly(req core.Request) bool {
+	if s.MaxTokens > 0 && core.OutputTokenLimit(req) > s.MaxTokens {
+		return true
+	}
+	return s.Tripped()
+}
+
+// Tripped reports whether an observed response exceeded a threshold less
+// than Cooldown ago.
+func (s *StatisticalSentinel) Tripped() bool {
+	return s.Reason() != ""
+}
+
+// Reason describes the first threshold that was exceeded, or "" if the
+// sentinel is not tripped.
+func (s *StatisticalSentinel) Reason() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	return s.reason
+}
+
+// Reset clears a tripped sentinel so requests flow again.
+func (s *StatisticalSentinel) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reason = ""
+}
+
+// trip records the first exceeded threshold since the last trip expired.
+func (s *StatisticalSentinel) trip(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	if s.reason == "" {
+		s.reason = reason
+		s.trippedAt = time.Now()
+	}
+}
+
+// expire clears a trip older than the cooldown. s.mu must be held.
+func (s *StatisticalSentinel) expire() {
+	cooldown := s.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultSentinelCooldown
+	}
+	if s.reason != "" && time.Since(s.trippedAt) >= cooldown {
+		s.reason = ""
+	}
+}
@@ -0,0 +1,86 @@
+package obs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+func TestStatisticalSentinel(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  *core.TextResult
+		elapsed time.Duration
+		reason  string
+	}{
+		{"within limits", &core.TextResult{Usage: core.Usage{OutputTokens: 100, TotalTokens: 5000}}, time.Second, ""},
+		{"nil result", nil, 0, ""},
+		{"tokens", &core.TextResult{Usage: core.Usage{OutputTokens: 1001}}, 0, "1001 output tokens"},
+		{"tool calls", &core.TextResult{Steps: []core.Step{
+			{ToolCalls: []core.ToolCall{{Name: "a"}, {Name: "b"}}},
+			{ToolCalls: []core.ToolCall{{Name: "c"}}},
+		}}, 0, "3 tool calls"},
+		{"duration", &core.TextResult{}, 2 * time.Minute, "took 2m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &StatisticalSentinel{MaxTokens: 1000, MaxToolCalls: 2, MaxDuration: time.Minute}
+			s.Observe(core.Request{}, tt.result)
+			s.ObserveDuration(core.Request{}, tt.elapsed)
+
+			if got := s.IsAnomaly(core.Request{}); got != (tt.reason != "") {
+				t.Errorf("IsAnomaly = %v, want %v", got, tt.reason != "")
+			}
+			if !strings.Contains(s.Reason(), tt.reason) || (tt.reason == "" && s.Reason() != "") {
+				t.Errorf("Reason = %q, want it to contain %q", s.Reason(), tt.reason)
+			}
+
+			s.Reset()
+			if s.Tripped() || s.IsAnomaly(core.Request{}) {
+				t.Error("expected sentinel to be clear after Reset")
+			}
+		})
+	}
+}
+
+func TestStatisticalSentinelRequestMaxTokens(t *testing.T) {
+	s := &StatisticalSentinel{MaxTokens: 1000}
+	if !s.IsAnomaly(core.Request{MaxTokens: 5000}) {
+		t.Error("expected a request asking for more than MaxTokens to be flagged")
+	}
+	if s.Tripped() {
+		t.Error("flagging a request should not trip the sentinel")
+	}
+	if s.IsAnomaly(core.Request{MaxTokens: 500}) {
+		t.Error("expected a request within MaxTokens to pass")
+	}
+
+	var disabled StatisticalSentinel
+	disabled.Observe(core.Request{}, &core.TextResult{Usage: core.Usage{OutputTokens: 1 << 20}})
+	disabled.ObserveDuration(core.Request{}, time.Hour)
+	if disabled.IsAnomaly(core.Request{MaxTokens: 1 << 20}) {
+		t.Error("zero thresholds should be disabled")
+	}
+}
+
+func TestStatisticalSentinelCooldown(t *testing.T) {
+	s := &StatisticalSentinel{MaxTokens: 1000, Cooldown: 20 * time.Millisecond}
+	s.Observe(core.Request{}, &core.TextResult{Usage: core.Usage{OutputTokens: 2000}})
+	if !s.IsAnomaly(core.Request{}) {
+		t.Fatal("expected the sentinel to trip")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if s.Tripped() || s.IsAnomaly(core.Request{}) {
+		t.Error("expected the trip to clear after Cooldown")
+	}
+
+	// A new oversized response trips it again
+	s.Observe(core.Request{}, &core.TextResult{Usage: core.Usage{OutputTokens: 3000}})
+	if !strings.Contains(s.Reason(), "3000") {
+		t.Errorf("Reason = %q, want the new trip", s.Reason())
+	}
+}
@@ -0,0 +1,185 @@
+package obs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/recera/gai/core"
+)
+
+// CostModel prices token usage in US dollars.
+type CostModel interface {
+	// Cost returns the price of usage on the named model
+	Cost(model string, usage core.Usage) float64
+}
+
+// CostModelFunc adapts a function to a CostModel.
+type CostModelFunc func(model string, usage core.Usage) float64
+
+// Cost implements CostModel.
+func (f CostModelFunc) Cost(model string, usage core.Usage) float64 {
+	return f(model, usage)
+}
+
+// DefaultCostModel prices usage with the built-in estimates used by EstimateCost.
+var DefaultCostModel CostModel = CostModelFunc(func(model string, usage core.Usage) float64 {
+	return float64(EstimateCost(model, usage.InputTokens, usage.OutputTokens)) / 1000000.0
+})
+
+// sessionTurn is a single GenerateText call recorded by a SessionCollector.
+type sessionTurn struct {
+	model    string
+	usage    core.Usage
+	hasTools bool
+}
+
+// SessionCollector aggregates usage across the turns of one multi-turn
+// conversation, identified by the core.MetadataSessionID request metadata
+// (set automatically by core.WithSession). It is safe for concurrent use.
+type SessionCollector struct {
+	sessionID string
+
+	mu       sync.RWMutex
+	turns    []sessionTurn
+	messages int
+}
+
+// NewSessionCollector creates a collector for the conversation with sessionID.
+func NewSessionCollector(sessionID string) *SessionCollector {
+	return &SessionCollector{sessionID: sessionID}
+}
+
+// SessionID returns the conversation the collector aggregates.
+func (c *SessionCollector) SessionID() string {
+	return c.sessionID
+}
+
+// Record adds a completed turn to the session. The turn is priced by the
+// model that served it (see core.ServedModel).
+func (c *SessionCollector) Record(req core.Request, result *core.TextResult) {
+	if result == nil {
+		return
+	}
+
+	turn := sessionTurn{model: core.ServedModel(req, result), usage: result.Usage}
+	for _, step := range result.Steps {
+		if len(step.ToolCalls) > 0 {
+			turn.hasTools = true
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.turns = append(c.turns, turn)
+	// Requests carry the conversation so far; count it plus the response
+	if n := len(req.Messages) + 1; n > c.messages {
+		c.messages = n
+	}
+}
+
+// TotalCost returns the cost of every turn priced with model, or
+// DefaultCostModel if model is nil.
+func (c *SessionCollector) TotalCost(model CostModel) float64 {
+	if model == nil {
+		model = DefaultCostModel
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var total float64
+	for _, turn := range c.turns {
+		total += model.Cost(turn.model, turn.usage)
+	}
+	return total
+}
+
+// TotalUsage returns the summed usage of all turns.
+func (c *SessionCollector) TotalUsage() core.Usage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var total core.Usage
+	for _, turn := range c.turns {
+		total.InputTokens += turn.usage.InputTokens
+		total.OutputTokens += turn.usage.OutputTokens
+		total.TotalTokens += turn.usage.TotalTokens
+		total.CacheReadTokens += turn.usage.CacheReadTokens
+		total.CacheCreationTokens += turn.usage.CacheCreationTokens
+	}
+	return total
+}
+
+// Turns returns the number of recorded GenerateText calls.
+func (c *SessionCollector) Turns() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.turns)
+}
+
+// MessageCount returns the number of messages in the conversation so far:
+// the messages of the longest request plus its response.
+func (c *SessionCollector) MessageCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.messages
+}
+
+// TurnsWithTools returns the number of turns in which the model called tools.
+func (c *SessionCollector) TurnsWithTools() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	count := 0
+	for _, turn := range c.turns {
+		if turn.hasTools {
+			count++
+		}
+	}
+	return count
+}
+
+// SessionCollectorMiddleware returns provider middleware that records every
+// successful GenerateText call belonging to the collector's session. The
+// session ID is read from Request.Metadata, then from metadata in the context.
+//
+// Example:
+//
+//	collector := obs.NewSessionCollector("conv-42")
+//	provider = obs.SessionCollectorMiddleware(collector)(provider)
+//	provider = core.WithSession(provider, store, "conv-42")
+//	// ...
+//	fmt.Printf("conversation cost: $%.4f\n", collector.TotalCost(nil))
+func SessionCollectorMiddleware(collector *SessionCollector) func(core.Provider) core.Provider {
+	return func(provider core.Provider) core.Provider {
+		return &sessionCollectingProvider{Provider: provider, collector: collector}
+	}
+}
+
+// sessionCollectingProvider records session turns in a SessionCollector.
+type sessionCollectingProvider struct {
+	core.Provider
+	collector *SessionCollector
+}
+
+// GenerateText implements core.Provider.
+func (p *sessionCollectingProvider) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	result, err := p.Provider.GenerateText(ctx, req)
+	if err == nil && requestSessionID(ctx, req) == p.collector.sessionID {
+		p.collector.Record(req, result)
+	}
+	return result, err
+}
+
+// requestSessionID returns the session ID from the request or context metadata.
+func requestSessionID(ctx context.Context, req core.Request) string {
+	if id, ok := req.Metadata[core.MetadataSessionID]; ok {
+		return fmt.Sprint(id)
+	}
+	if id, ok := core.MetadataFromContext(ctx)[core.MetadataSessionID]; ok {
+		return fmt.Sprint(id)
+	}
+	return ""
+}
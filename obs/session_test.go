@@ -0,0 +1,73 @@
+package obs
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+func TestSessionCollectorMiddleware(t *testing.T) {
+	collector := NewSessionCollector("conv-1")
+	usage := core.Usage{InputTokens: 1000, OutputTokens: 500, TotalTokens: 1500}
+
+	plain := SessionCollectorMiddleware(collector)(&staticProvider{
+		result: &core.TextResult{Text: "hi", Usage: usage},
+	})
+	withTools := SessionCollectorMiddleware(collector)(&staticProvider{
+		result: &core.TextResult{Usage: usage, Steps: []core.Step{{ToolCalls: []core.ToolCall{{Name: "lookup"}}}}},
+	})
+
+	user := core.Message{Role: core.User, Parts: []core.Part{core.Text{Text: "hello"}}}
+	meta := map[string]any{core.MetadataSessionID: "conv-1"}
+
+	// First turn identifies the session in the request, second in the context
+	if _, err := plain.GenerateText(context.Background(), core.Request{Model: "gpt-4o", Messages: []core.Message{user}, Metadata: meta}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := core.WithMetadata(context.Background(), meta)
+	if _, err := withTools.GenerateText(ctx, core.Request{Model: "gpt-4o", Messages: []core.Message{user, user, user}}); err != nil {
+		t.Fatal(err)
+	}
+	// Other sessions are ignored
+	other := map[string]any{core.MetadataSessionID: "conv-2"}
+	if _, err := plain.GenerateText(context.Background(), core.Request{Model: "gpt-4o", Metadata: other}); err != nil {
+		t.Fatal(err)
+	}
+
+	if collector.Turns() != 2 {
+		t.Errorf("Turns() = %d, want 2", collector.Turns())
+	}
+	if collector.TurnsWithTools() != 1 {
+		t.Errorf("TurnsWithTools() = %d, want 1", collector.TurnsWithTools())
+	}
+	if collector.MessageCount() != 4 {
+		t.Errorf("MessageCount() = %d, want 4", collector.MessageCount())
+	}
+	if total := collector.TotalUsage(); total.TotalTokens != 3000 {
+		t.Errorf("TotalUsage().TotalTokens = %d, want 3000", total.TotalTokens)
+	}
+
+	flat := CostModelFunc(func(model string, usage core.Usage) float64 {
+		return float64(usage.TotalTokens) / 1000
+	})
+	if cost := collector.TotalCost(flat); math.Abs(cost-3) > 1e-9 {
+		t.Errorf("TotalCost(flat) = %v, want 3", cost)
+	}
+	if cost := collector.TotalCost(nil); cost <= 0 {
+		t.Errorf("TotalCost(nil) = %v, want a positive default estimate", cost)
+	}
+
+	// Turns are priced by the model that served them
+	auto := NewSessionCollector("conv-1")
+	auto.Record(core.Request{Model: core.AutoModel}, &core.TextResult{ModelUsed: "gpt-4o-mini", Usage: usage})
+	var priced string
+	auto.TotalCost(CostModelFunc(func(model string, usage core.Usage) float64 {
+		priced = model
+		return 0
+	}))
+	if priced != "gpt-4o-mini" {
+		t.Errorf("turn priced as %q, want gpt-4o-mini", priced)
+	}
+}
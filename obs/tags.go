@@ -0,0 +1,138 @@
+package obs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/recera/gai/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tagsKey is the context key for custom telemetry tags
+type tagsKey struct{}
+
+// ContextWithTags returns a context carrying custom tags that are added as
+// attributes to every span and metric recorded with it. Tags already in the
+// context are kept; on key conflicts the new value wins.
+//
+// This is typically used by middleware.WithMetricsTags to attribute telemetry
+// to a tenant, environment or feature flag.
+func ContextWithTags(ctx context.Context, tags map[string]string) context.Context {
+	if len(tags) == 0 {
+		return ctx
+	}
+
+	existing := TagsFromContext(ctx)
+	merged := make(map[string]string, len(existing)+len(tags))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, tagsKey{}, merged)
+}
+
+// TagsFromContext returns the custom tags carried by the context, or nil.
+// The returned map must not be modified.
+func TagsFromContext(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(tagsKey{}).(map[string]string)
+	return tags
+}
+
+// promptVariantKey is the type of PromptVariantKey
+type promptVariantKey struct{}
+
+// PromptVariantKey is the context key for the prompt variant chosen by an
+// A/B test such as prompts.PromptABTest. Its value is a string; spans
+// started with the context get it as the prompt.variant attribute.
+var PromptVariantKey = promptVariantKey{}
+
+// ContextWithPromptVariant returns a context recording the chosen prompt
+// variant under PromptVariantKey.
+func ContextWithPromptVariant(ctx context.Context, variant string) context.Context {
+	return context.WithValue(ctx, PromptVariantKey, variant)
+}
+
+// PromptVariantFromContext returns the prompt variant recorded in the
+// context, or "".
+func PromptVariantFromContext(ctx context.Context) string {
+	variant, _ := ctx.Value(PromptVariantKey).(string)
+	return variant
+}
+
+// metadataAttributes maps well-known request metadata keys to span attributes
+var metadataAttributes = []struct {
+	key  string
+	attr attribute.Key
+}{
+	{core.MetadataTenantID, "tenant.id"},
+	{core.MetadataUserID, "enduser.id"},
+	{core.MetadataSessionID, "session.id"},
+}
+
+// tagAttributes converts the context tags to attributes in a stable order
+func tagAttributes(ctx context.Context) []attribute.KeyValue {
+	tags := TagsFromContext(ctx)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]attribute.KeyValue, len(keys))
+	for i, k := range keys {
+		attrs[i] = attribute.String(k, tags[k])
+	}
+	return attrs
+}
+
+// identityAttributes returns tenant, user and session attributes from the
+// request metadata carried by the context (see core.WithMetadata)
+func identityAttributes(ctx context.Context) []attribute.KeyValue {
+	meta := core.MetadataFromContext(ctx)
+	if len(meta) == 0 {
+		return nil
+	}
+
+	var attrs []attribute.KeyValue
+	for _, m := range metadataAttributes {
+		if v, ok := meta[m.key]; ok && v != nil {
+			if s := fmt.Sprint(v); s != "" {
+				attrs = append(attrs, m.attr.String(s))
+			}
+		}
+	}
+	return attrs
+}
+
+// startSpan starts a span with the context tags, request identity and prompt
+// variant added to its attributes. These come first so attributes set by the
+// caller take precedence.
+func startSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	attrs := append(identityAttributes(ctx), tagAttributes(ctx)...)
+	if variant := PromptVariantFromContext(ctx); variant != "" {
+		attrs = append(attrs, attribute.String("prompt.variant", variant))
+	}
+	if len(attrs) > 0 {
+		opts = append([]trace.SpanStartOption{trace.WithAttributes(attrs...)}, opts...)
+	}
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// withTags builds metric attributes from the context tags and the given attributes.
+// The given attributes take precedence over tags with the same key.
+func withTags(ctx context.Context, attrs ...attribute.KeyValue) metric.MeasurementOption {
+	if tagAttrs := tagAttributes(ctx); len(tagAttrs) > 0 {
+		attrs = append(tagAttrs, attrs...)
+	}
+	return metric.WithAttributes(attrs...)
+}
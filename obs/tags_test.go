@@ -0,0 +1,93 @@
+package obs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+func TestContextWithTags(t *testing.T) {
+	ctx := context.Background()
+	if tags := TagsFromContext(ctx); tags != nil {
+		t.Errorf("expected no tags, got %v", tags)
+	}
+	
+	ctx = ContextWithTags(ctx, map[string]string{"tenant": "acme", "env": "prod"})
+	ctx = ContextWithTags(ctx, map[string]string{"env": "staging"})
+	
+	tags := TagsFromContext(ctx)
+	if tags["tenant"] != "acme" || tags["env"] != "staging" {
+		t.Errorf("unexpected merged tags: %v", tags)
+	}
+}
+
+func TestSpansIncludeTags(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+	
+	ctx := ContextWithTags(context.Background(), map[string]string{
+		"tenant_id": "acme",
+		"tool.name": "overridden",
+	})
+	
+	_, span := StartToolSpan(ctx, ToolSpanOptions{ToolName: "get_weather"})
+	span.End()
+	
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	
+	attrs := spans[0].Attributes
+	checkAttribute(t, attrs, "tenant_id", "acme")
+	// Span attributes take precedence over tags
+	checkAttribute(t, attrs, "tool.name", "get_weather")
+}
+
+func TestSpansIncludeRequestIdentity(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+	
+	ctx := core.WithMetadata(context.Background(), map[string]any{
+		core.MetadataTenantID: "acme",
+		core.MetadataUserID:   42,
+		"other":               "ignored",
+	})
+	
+	_, span := StartRequestSpan(ctx, RequestSpanOptions{Provider: "openai", Model: "gpt-4o"})
+	span.End()
+	
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	
+	attrs := spans[0].Attributes
+	checkAttribute(t, attrs, "tenant.id", "acme")
+	checkAttribute(t, attrs, "enduser.id", "42")
+	for _, attr := range attrs {
+		if attr.Key == "session.id" || attr.Key == "other" {
+			t.Errorf("unexpected attribute %s", attr.Key)
+		}
+	}
+}
+
+func TestSpansIncludePromptVariant(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+
+	ctx := ContextWithPromptVariant(context.Background(), "2.0.0")
+	if got := PromptVariantFromContext(ctx); got != "2.0.0" {
+		t.Errorf("PromptVariantFromContext = %q, want 2.0.0", got)
+	}
+
+	_, span := StartRequestSpan(ctx, RequestSpanOptions{Provider: "openai", Model: "gpt-4o"})
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	checkAttribute(t, spans[0].Attributes, "prompt.variant", "2.0.0")
+}
@@ -164,7 +164,7 @@ func StartRequestSpan(ctx context.Context, opts RequestSpanOptions) (context.Con
 	}
 
 	// Create span with basic attributes
-	ctx, span := Tracer().Start(ctx, spanName,
+	ctx, span := startSpan(ctx, spanName,
 		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(
 			// Legacy GAI attributes (backward compatibility)
@@ -192,23 +192,23 @@ func StartRequestSpan(ctx context.Context, opts RequestSpanOptions) (context.Con
 		}
 
 		span.SetAttributes(
-			attribute.String("gen_ai.system", system),
-			attribute.String("gen_ai.operation.name", opts.Operation),
-			attribute.String("gen_ai.request.model", opts.Model),
+			AttrGenAISystem.String(system),
+			AttrGenAIOperationName.String(opts.Operation),
+			AttrGenAIRequestModel.String(opts.Model),
 		)
 
 		// Add optional GenAI attributes
 		if opts.Temperature > 0 {
-			span.SetAttributes(attribute.Float64("gen_ai.request.temperature", float64(opts.Temperature)))
+			span.SetAttributes(AttrGenAIRequestTemperature.Float64(float64(opts.Temperature)))
 		}
 		if opts.MaxTokens > 0 {
-			span.SetAttributes(attribute.Int("gen_ai.request.max_tokens", opts.MaxTokens))
+			span.SetAttributes(AttrGenAIRequestMaxTokens.Int(opts.MaxTokens))
 		}
 		if opts.ConversationID != "" {
-			span.SetAttributes(attribute.String("gen_ai.conversation.id", opts.ConversationID))
+			span.SetAttributes(AttrGenAIConversationID.String(opts.ConversationID))
 		}
 		if opts.ToolCount > 0 {
-			span.SetAttributes(attribute.StringSlice("gen_ai.tools", extractToolNames(opts.ToolCount)))
+			span.SetAttributes(AttrGenAITools.StringSlice(extractToolNames(opts.ToolCount)))
 		}
 	}
 
@@ -216,6 +216,9 @@ func StartRequestSpan(ctx context.Context, opts RequestSpanOptions) (context.Con
 	if len(opts.Messages) > 0 && opts.ContentCapture != ContentCaptureNone {
 		captureMessageContent(span, opts.Messages, opts.ContentCapture, opts.Provider)
 	}
+	if detail := visionDetail(opts.Messages); detail != "" {
+		span.SetAttributes(AttrGenAIVisionDetail.String(detail))
+	}
 
 	// Add provider-specific options as attributes
 	for k, v := range opts.ProviderOptions {
@@ -239,33 +242,33 @@ func StartRequestSpan(ctx context.Context, opts RequestSpanOptions) (context.Con
 func StartGenAISpan(ctx context.Context, opts GenAIRequestSpanOptions) (context.Context, trace.Span) {
 	spanName := fmt.Sprintf("%s %s", opts.Operation, opts.Model)
 
-	ctx, span := Tracer().Start(ctx, spanName,
+	ctx, span := startSpan(ctx, spanName,
 		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(
-			attribute.String("gen_ai.system", opts.System),
-			attribute.String("gen_ai.operation.name", opts.Operation),
-			attribute.String("gen_ai.request.model", opts.Model),
+			AttrGenAISystem.String(opts.System),
+			AttrGenAIOperationName.String(opts.Operation),
+			AttrGenAIRequestModel.String(opts.Model),
 		),
 	)
 
 	// Add optional request parameters
 	if opts.Temperature != nil {
-		span.SetAttributes(attribute.Float64("gen_ai.request.temperature", float64(*opts.Temperature)))
+		span.SetAttributes(AttrGenAIRequestTemperature.Float64(float64(*opts.Temperature)))
 	}
 	if opts.MaxTokens != nil {
-		span.SetAttributes(attribute.Int("gen_ai.request.max_tokens", *opts.MaxTokens))
+		span.SetAttributes(AttrGenAIRequestMaxTokens.Int(*opts.MaxTokens))
 	}
 	if opts.TopP != nil {
-		span.SetAttributes(attribute.Float64("gen_ai.request.top_p", float64(*opts.TopP)))
+		span.SetAttributes(AttrGenAIRequestTopP.Float64(float64(*opts.TopP)))
 	}
 	if opts.TopK != nil {
-		span.SetAttributes(attribute.Int("gen_ai.request.top_k", *opts.TopK))
+		span.SetAttributes(AttrGenAIRequestTopK.Int(*opts.TopK))
 	}
 	if len(opts.Tools) > 0 {
-		span.SetAttributes(attribute.StringSlice("gen_ai.tools", opts.Tools))
+		span.SetAttributes(AttrGenAITools.StringSlice(opts.Tools))
 	}
 	if opts.ConversationID != "" {
-		span.SetAttributes(attribute.String("gen_ai.conversation.id", opts.ConversationID))
+		span.SetAttributes(AttrGenAIConversationID.String(opts.ConversationID))
 	}
 	if opts.UserID != "" {
 		span.SetAttributes(attribute.String("user.id", opts.UserID))
@@ -275,6 +278,9 @@ func StartGenAISpan(ctx context.Context, opts GenAIRequestSpanOptions) (context.
 	if len(opts.Messages) > 0 && opts.ContentCapture != ContentCaptureNone {
 		captureMessageContent(span, opts.Messages, opts.ContentCapture, opts.System)
 	}
+	if detail := visionDetail(opts.Messages); detail != "" {
+		span.SetAttributes(AttrGenAIVisionDetail.String(detail))
+	}
 
 	// Add custom metadata
 	for k, v := range opts.Metadata {
@@ -284,6 +290,71 @@ func StartGenAISpan(ctx context.Context, opts GenAIRequestSpanOptions) (context.
 	return ctx, span
 }
 
+// WithGenAIAttributes returns a span start option carrying the gen_ai.*
+// attributes that describe req: the requested model, temperature, max
+// tokens, output type, tool names, message count, conversation ID and image
+// detail. Unset fields are omitted. Use it to keep attribute names
+// consistent when starting spans directly:
+//
+//	ctx, span := obs.Tracer().Start(ctx, "chat "+req.Model, obs.WithGenAIAttributes(req))
+func WithGenAIAttributes(req core.Request) trace.SpanStartOption {
+	return trace.WithAttributes(genAIRequestAttributes(req)...)
+}
+
+// genAIRequestAttributes builds the gen_ai.* attributes for req.
+func genAIRequestAttributes(req core.Request) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		AttrGenAIRequestMessageCount.Int(len(req.Messages)),
+	}
+	if req.Model != "" {
+		attrs = append(attrs, AttrGenAIRequestModel.String(req.Model))
+	}
+	if req.Temperature > 0 {
+		attrs = append(attrs, AttrGenAIRequestTemperature.Float64(float64(req.Temperature)))
+	}
+	if maxTokens := core.OutputTokenLimit(req); maxTokens > 0 {
+		attrs = append(attrs, AttrGenAIRequestMaxTokens.Int(maxTokens))
+	}
+	if len(req.ResponseSchema) > 0 {
+		attrs = append(attrs, AttrGenAIOutputType.String("json"))
+	}
+	if names := requestToolNames(req.Tools); len(names) > 0 {
+		attrs = append(attrs, AttrGenAITools.StringSlice(names))
+	}
+	if session, ok := req.Metadata[core.MetadataSessionID].(string); ok && session != "" {
+		attrs = append(attrs, AttrGenAIConversationID.String(session))
+	}
+	if detail := visionDetail(req.Messages); detail != "" {
+		attrs = append(attrs, AttrGenAIVisionDetail.String(detail))
+	}
+	return attrs
+}
+
+// requestToolNames returns the names of tools.
+func requestToolNames(tools []core.ToolHandle) []string {
+	if len(tools) == 0 {
+		return nil
+	}
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name()
+	}
+	return names
+}
+
+// visionDetail returns the detail level of the first image in messages that
+// sets one, or "" if none does.
+func visionDetail(messages []core.Message) string {
+	for _, msg := range messages {
+		for _, part := range msg.Parts {
+			if img, ok := part.(core.ImageURL); ok && img.Detail != "" {
+				return img.Detail
+			}
+		}
+	}
+	return ""
+}
+
 // captureMessageContent captures message content as attributes and/or events
 func captureMessageContent(span trace.Span, messages []core.Message, mode ContentCaptureMode, system string) {
 	if span == nil || !span.IsRecording() {
@@ -306,21 +377,21 @@ func captureMessageAttributes(span trace.Span, messages []core.Message) {
 	// Support both individual attributes and JSON format
 	for i, msg := range messages {
 		span.SetAttributes(
-			attribute.String(fmt.Sprintf("gen_ai.prompt.%d.role", i), string(msg.Role)),
+			GenAIPromptRoleKey(i).String(string(msg.Role)),
 		)
 
 		// Extract text content from message parts
 		content := extractTextContent(msg)
 		if content != "" {
 			span.SetAttributes(
-				attribute.String(fmt.Sprintf("gen_ai.prompt.%d.content", i), content),
+				GenAIPromptContentKey(i).String(content),
 			)
 		}
 	}
 
 	// Also provide JSON format for compatibility
 	if jsonMessages, err := json.Marshal(convertMessagesToJSON(messages)); err == nil {
-		span.SetAttributes(attribute.String("gen_ai.prompt_json", string(jsonMessages)))
+		span.SetAttributes(AttrGenAIPromptJSON.String(string(jsonMessages)))
 	}
 }
 
@@ -330,13 +401,13 @@ func captureMessageEvents(span trace.Span, messages []core.Message, system strin
 		var eventName string
 		switch msg.Role {
 		case core.System:
-			eventName = "gen_ai.system.message"
+			eventName = EventGenAISystemMessage
 		case core.User:
-			eventName = "gen_ai.user.message"
+			eventName = EventGenAIUserMessage
 		case core.Assistant:
-			eventName = "gen_ai.assistant.message"
+			eventName = EventGenAIAssistantMessage
 		case core.Tool:
-			eventName = "gen_ai.tool.message"
+			eventName = EventGenAIToolMessage
 		default:
 			continue // Skip unknown roles
 		}
@@ -344,7 +415,7 @@ func captureMessageEvents(span trace.Span, messages []core.Message, system strin
 		content := extractTextContent(msg)
 		if content != "" {
 			span.AddEvent(eventName, trace.WithAttributes(
-				attribute.String("gen_ai.system", system),
+				AttrGenAISystem.String(system),
 				attribute.String("role", string(msg.Role)),
 				attribute.String("content", content),
 			))
@@ -395,10 +466,10 @@ func RecordGenAICompletion(span trace.Span, text string, inputTokens, outputToke
 	if span != nil && span.IsRecording() {
 		// GenAI semantic conventions for completion and usage
 		span.SetAttributes(
-			attribute.String("gen_ai.completion", text),
-			attribute.Int("gen_ai.usage.prompt_tokens", inputTokens),
-			attribute.Int("gen_ai.usage.completion_tokens", outputTokens),
-			attribute.Int("gen_ai.usage.total_tokens", totalTokens),
+			AttrGenAICompletion.String(text),
+			AttrGenAIUsagePromptTokens.Int(inputTokens),
+			AttrGenAIUsageCompletionTokens.Int(outputTokens),
+			AttrGenAIUsageTotalTokens.Int(totalTokens),
 		)
 
 		// Legacy attributes for backward compatibility
@@ -409,8 +480,8 @@ func RecordGenAICompletion(span trace.Span, text string, inputTokens, outputToke
 // RecordGenAICompletionEvent records a completion as an OpenTelemetry event
 func RecordGenAICompletionEvent(span trace.Span, text string, system string) {
 	if span != nil && span.IsRecording() && text != "" {
-		span.AddEvent("gen_ai.choice", trace.WithAttributes(
-			attribute.String("gen_ai.system", system),
+		span.AddEvent(EventGenAIChoice, trace.WithAttributes(
+			AttrGenAISystem.String(system),
 			attribute.Int("index", 0),
 			attribute.String("finish_reason", "stop"),
 			attribute.String("content", text),
@@ -424,18 +495,29 @@ type StepSpanOptions struct {
 	HasToolCalls bool
 	ToolCount    int
 	TextLength   int
+	// Usage is the token usage of the step's model call, if known
+	Usage *core.Usage
 }
 
 // StartStepSpan starts a new span for a multi-step execution step
 func StartStepSpan(ctx context.Context, opts StepSpanOptions) (context.Context, trace.Span) {
-	ctx, span := Tracer().Start(ctx, fmt.Sprintf("ai.step.%d", opts.StepNumber),
+	attrs := []attribute.KeyValue{
+		attribute.Int("step.number", opts.StepNumber),
+		attribute.Bool("step.has_tool_calls", opts.HasToolCalls),
+		attribute.Int("step.tool_count", opts.ToolCount),
+		attribute.Int("step.text_length", opts.TextLength),
+	}
+	if opts.Usage != nil {
+		attrs = append(attrs,
+			AttrGenAIUsageInputTokens.Int(opts.Usage.InputTokens),
+			AttrGenAIUsageOutputTokens.Int(opts.Usage.OutputTokens),
+			AttrGenAIUsageTotalTokens.Int(opts.Usage.TotalTokens),
+		)
+	}
+
+	ctx, span := startSpan(ctx, fmt.Sprintf("ai.step.%d", opts.StepNumber),
 		trace.WithSpanKind(trace.SpanKindInternal),
-		trace.WithAttributes(
-			attribute.Int("step.number", opts.StepNumber),
-			attribute.Bool("step.has_tool_calls", opts.HasToolCalls),
-			attribute.Int("step.tool_count", opts.ToolCount),
-			attribute.Int("step.text_length", opts.TextLength),
-		),
+		trace.WithAttributes(attrs...),
 	)
 	return ctx, span
 }
@@ -449,11 +531,38 @@ type ToolSpanOptions struct {
 	Parallel   bool
 	RetryCount int
 	Timeout    time.Duration
+	// Input is the raw tool input; when set it is recorded on the span
+	// immediately for Braintrust display
+	Input json.RawMessage
+	// Description is the tool's description
+	Description string
+	// InputSchema is the tool's input JSON schema, recorded truncated to
+	// maxToolSchemaAttrLen bytes
+	InputSchema []byte
 }
 
-// StartToolSpan starts a new span for a tool execution
+// maxToolSchemaAttrLen caps the size of the tool.schema_json attribute.
+const maxToolSchemaAttrLen = 1024
+
+// StartToolSpan starts a new span for a tool execution.
+// If opts.Input is set, the input content is recorded right away; pair this
+// with RecordToolResultContent after execution to record the output.
 func StartToolSpan(ctx context.Context, opts ToolSpanOptions) (context.Context, trace.Span) {
-	ctx, span := Tracer().Start(ctx, fmt.Sprintf("ai.tool.%s", opts.ToolName),
+	if opts.InputSize == 0 {
+		opts.InputSize = len(opts.Input)
+	}
+	
+	// Grouped tools record the name the model called; the group is not
+	// passed on to tools the tool itself runs
+	group, _ := ctx.Value(toolGroupKey{}).(string)
+	if group != "" {
+		if !strings.HasPrefix(opts.ToolName, group+ToolGroupSeparator) {
+			opts.ToolName = group + ToolGroupSeparator + opts.ToolName
+		}
+		ctx = context.WithValue(ctx, toolGroupKey{}, "")
+	}
+
+	ctx, span := startSpan(ctx, fmt.Sprintf("ai.tool.%s", opts.ToolName),
 		trace.WithSpanKind(trace.SpanKindInternal),
 		trace.WithAttributes(
 			attribute.String("tool.name", opts.ToolName),
@@ -465,9 +574,76 @@ func StartToolSpan(ctx context.Context, opts ToolSpanOptions) (context.Context,
 			attribute.Float64("tool.timeout_seconds", opts.Timeout.Seconds()),
 		),
 	)
+	if group != "" {
+		span.SetAttributes(attribute.String("tool.group", group))
+	}
+	if opts.Description != "" {
+		span.SetAttributes(attribute.String("tool.description", opts.Description))
+	}
+	if len(opts.InputSchema) > 0 {
+		schema := opts.InputSchema
+		if len(schema) > maxToolSchemaAttrLen {
+			schema = schema[:maxToolSchemaAttrLen]
+		}
+		span.SetAttributes(attribute.String("tool.schema_json", string(schema)))
+	}
+	recordToolInput(span, opts.Input)
 	return ctx, span
 }
 
+// toolGroupKey is the context key for the group of the tool being executed
+type toolGroupKey struct{}
+
+// ToolGroupSeparator joins a tool's group to its name, as in
+// "crm__get_customer". Provider APIs only accept letters, digits, "_" and "-"
+// in tool names, so the separator is limited to those.
+const ToolGroupSeparator = "__"
+
+// ContextWithToolGroup returns a context marking the next tool span as
+// belonging to group, for tool wrappers whose inner handle does not know its
+// group-prefixed name. The span's tool.name is prefixed with the group and
+// tool.group is recorded. Groups set by nested wrappers are joined with
+// ToolGroupSeparator.
+func ContextWithToolGroup(ctx context.Context, group string) context.Context {
+	if outer, ok := ctx.Value(toolGroupKey{}).(string); ok && outer != "" {
+		group = outer + ToolGroupSeparator + group
+	}
+	return context.WithValue(ctx, toolGroupKey{}, group)
+}
+
+// ToolDescriptor is the part of a tool handle that describes it.
+// tools.Handle satisfies it.
+type ToolDescriptor interface {
+	Name() string
+	Description() string
+	InSchemaJSON() []byte
+}
+
+// StartToolSpanFromHandle starts a tool span with the name, description and
+// input schema taken from the handle, so tool implementations don't need to
+// instrument themselves. If the handle has a Timeout() int method (seconds),
+// the timeout is recorded too.
+func StartToolSpanFromHandle(ctx context.Context, handle ToolDescriptor, callID string, input json.RawMessage) (context.Context, trace.Span) {
+	opts := ToolSpanOptions{
+		ToolName:    handle.Name(),
+		ToolID:      callID,
+		Input:       input,
+		Description: handle.Description(),
+		InputSchema: handle.InSchemaJSON(),
+	}
+	if t, ok := handle.(interface{ Timeout() int }); ok {
+		opts.Timeout = time.Duration(t.Timeout()) * time.Second
+	}
+	return StartToolSpan(ctx, opts)
+}
+
+// RecordToolStep records the multi-step execution step a tool runs in.
+func RecordToolStep(span trace.Span, stepNumber int) {
+	if span != nil && span.IsRecording() {
+		span.SetAttributes(attribute.Int("tool.step_number", stepNumber))
+	}
+}
+
 // PromptSpanOptions contains options for creating a prompt span
 type PromptSpanOptions struct {
 	Name        string
@@ -480,7 +656,7 @@ type PromptSpanOptions struct {
 
 // StartPromptSpan starts a new span for prompt rendering
 func StartPromptSpan(ctx context.Context, opts PromptSpanOptions) (context.Context, trace.Span) {
-	ctx, span := Tracer().Start(ctx, fmt.Sprintf("ai.prompt.%s", opts.Name),
+	ctx, span := startSpan(ctx, fmt.Sprintf("ai.prompt.%s", opts.Name),
 		trace.WithSpanKind(trace.SpanKindInternal),
 		trace.WithAttributes(
 			attribute.String("prompt.name", opts.Name),
@@ -506,7 +682,7 @@ type StreamingSpanOptions struct {
 
 // StartStreamingSpan starts a new span for streaming operations
 func StartStreamingSpan(ctx context.Context, opts StreamingSpanOptions) (context.Context, trace.Span) {
-	ctx, span := Tracer().Start(ctx, "ai.streaming",
+	ctx, span := startSpan(ctx, "ai.streaming",
 		trace.WithSpanKind(trace.SpanKindProducer),
 		trace.WithAttributes(
 			attribute.String("streaming.provider", opts.Provider),
@@ -527,15 +703,15 @@ func RecordStreamingMetrics(span trace.Span, eventCount, bytesCount int, duratio
 			attribute.Int("streaming.bytes_count", bytesCount),
 			attribute.Float64("streaming.duration_ms", float64(duration.Milliseconds())),
 			// GenAI streaming attributes  
-			attribute.Int("gen_ai.stream.total_chunks", eventCount),
-			attribute.Int("gen_ai.stream.total_bytes", bytesCount),
-			attribute.Float64("gen_ai.stream.duration_ms", float64(duration.Milliseconds())),
+			AttrGenAIStreamTotalChunks.Int(eventCount),
+			AttrGenAIStreamTotalBytes.Int(bytesCount),
+			AttrGenAIStreamDurationMs.Float64(float64(duration.Milliseconds())),
 		)
 		
 		// Add bytes per second if duration > 0
 		if duration.Seconds() > 0 {
 			span.SetAttributes(
-				attribute.Float64("gen_ai.stream.bytes_per_second", float64(bytesCount)/duration.Seconds()),
+				AttrGenAIStreamBytesPerSecond.Float64(float64(bytesCount)/duration.Seconds()),
 			)
 		}
 	}
@@ -552,6 +728,46 @@ func RecordUsage(span trace.Span, inputTokens, outputTokens, totalTokens int) {
 	}
 }
 
+// RecordCacheUsage adds prompt cache token counts to a span. Zero counts are
+// not recorded, so it is safe to call for providers without prompt caching.
+func RecordCacheUsage(span trace.Span, cacheReadTokens, cacheCreationTokens int) {
+	if span == nil || !span.IsRecording() {
+		return
+	}
+	if cacheReadTokens > 0 {
+		span.SetAttributes(
+			attribute.Int("usage.cache_read_tokens", cacheReadTokens),
+			AttrGenAIUsageCacheReadInputTokens.Int(cacheReadTokens),
+		)
+	}
+	if cacheCreationTokens > 0 {
+		span.SetAttributes(
+			attribute.Int("usage.cache_creation_tokens", cacheCreationTokens),
+			AttrGenAIUsageCacheCreationInputTokens.Int(cacheCreationTokens),
+		)
+	}
+}
+
+// RecordCost adds the estimated request cost in US dollars to a span. A zero
+// cost is not recorded, so it is safe to call when no cost was computed.
+func RecordCost(span trace.Span, costUSD float64) {
+	if span == nil || !span.IsRecording() || costUSD == 0 {
+		return
+	}
+	span.SetAttributes(AttrGenAICostUSD.Float64(costUSD))
+}
+
+// RecordResponseModel adds gen_ai.response.model to a span when the model
+// that served the request (core.TextResult.ModelUsed) differs from the
+// requested model, such as after a fallback or when a model family resolved
+// to a dated version. An empty or matching used model is not recorded.
+func RecordResponseModel(span trace.Span, requested, used string) {
+	if span == nil || !span.IsRecording() || used == "" || used == requested {
+		return
+	}
+	span.SetAttributes(AttrGenAIResponseModel.String(used))
+}
+
 // RecordError records an error on a span with proper status
 func RecordError(span trace.Span, err error, description string) {
 	if span != nil && span.IsRecording() && err != nil {
@@ -580,28 +796,46 @@ func RecordToolResult(span trace.Span, success bool, outputSize int, duration ti
 
 // RecordToolContent records tool input and output content for Braintrust display
 func RecordToolContent(span trace.Span, toolName string, input json.RawMessage, output interface{}, err error) {
-	if span == nil || !span.IsRecording() {
+	recordToolInput(span, input)
+	recordToolOutput(span, toolName, output, err)
+}
+
+// RecordToolResultContent records a tool's output (or error) for Braintrust display.
+// It complements StartToolSpan with ToolSpanOptions.Input, which records the input.
+func RecordToolResultContent(span trace.Span, result any, err error) {
+	recordToolOutput(span, "", result, err)
+}
+
+// recordToolInput sets the tool input content on a span
+func recordToolInput(span trace.Span, input json.RawMessage) {
+	if span == nil || !span.IsRecording() || len(input) == 0 {
 		return
 	}
 
-	// Set tool input content - use braintrust namespace for better compatibility
-	if len(input) > 0 {
-		span.SetAttributes(
-			attribute.String("braintrust.input_json", string(input)),
-			attribute.String("gen_ai.prompt", string(input)), // Also set GenAI format
-		)
+	// Use braintrust namespace for better compatibility
+	span.SetAttributes(
+		attribute.String("braintrust.input_json", string(input)),
+		AttrGenAIPrompt.String(string(input)), // Also set GenAI format
+	)
+}
+
+// recordToolOutput sets the tool output content and status on a span
+func recordToolOutput(span trace.Span, toolName string, output interface{}, err error) {
+	if span == nil || !span.IsRecording() {
+		return
 	}
 
-	// Set tool output content
 	if err != nil {
 		errorOutput := map[string]interface{}{
 			"error": err.Error(),
-			"tool":  toolName,
+		}
+		if toolName != "" {
+			errorOutput["tool"] = toolName
 		}
 		if outputJSON, marshalErr := json.Marshal(errorOutput); marshalErr == nil {
 			span.SetAttributes(
 				attribute.String("braintrust.output_json", string(outputJSON)),
-				attribute.String("gen_ai.completion", string(outputJSON)),
+				AttrGenAICompletion.String(string(outputJSON)),
 			)
 		}
 		span.RecordError(err)
@@ -610,7 +844,7 @@ func RecordToolContent(span trace.Span, toolName string, input json.RawMessage,
 		if outputJSON, marshalErr := json.Marshal(output); marshalErr == nil {
 			span.SetAttributes(
 				attribute.String("braintrust.output_json", string(outputJSON)),
-				attribute.String("gen_ai.completion", string(outputJSON)),
+				AttrGenAICompletion.String(string(outputJSON)),
 			)
 		}
 		span.SetStatus(codes.Ok, "Tool executed successfully")
@@ -700,7 +934,7 @@ func RecordSTTMetrics(span trace.Span, provider string, durationMs int, wordCoun
 
 // WithSpan is a helper to execute a function within a span
 func WithSpan(ctx context.Context, name string, fn func(context.Context, trace.Span) error) error {
-	ctx, span := Tracer().Start(ctx, name)
+	ctx, span := startSpan(ctx, name)
 	defer span.End()
 
 	err := fn(ctx, span)
@@ -785,11 +1019,11 @@ func RecordBraintrustCompletion(span trace.Span, result *core.TextResult, system
 
 	// Record as GenAI completion (primary method for Braintrust)
 	if result.Text != "" {
-		span.SetAttributes(attribute.String("gen_ai.completion", result.Text))
+		span.SetAttributes(AttrGenAICompletion.String(result.Text))
 
 		// Also add as completion event for comprehensive capture
-		span.AddEvent("gen_ai.choice", trace.WithAttributes(
-			attribute.String("gen_ai.system", system),
+		span.AddEvent(EventGenAIChoice, trace.WithAttributes(
+			AttrGenAISystem.String(system),
 			attribute.Int("index", 0),
 			attribute.String("finish_reason", "stop"),
 			attribute.String("content", result.Text),
@@ -799,10 +1033,15 @@ func RecordBraintrustCompletion(span trace.Span, result *core.TextResult, system
 	// Record usage following GenAI semantic conventions
 	if result.Usage.TotalTokens > 0 {
 		span.SetAttributes(
-			attribute.Int("gen_ai.usage.prompt_tokens", result.Usage.InputTokens),
-			attribute.Int("gen_ai.usage.completion_tokens", result.Usage.OutputTokens),
-			attribute.Int("gen_ai.usage.total_tokens", result.Usage.TotalTokens),
+			AttrGenAIUsagePromptTokens.Int(result.Usage.InputTokens),
+			AttrGenAIUsageCompletionTokens.Int(result.Usage.OutputTokens),
+			AttrGenAIUsageTotalTokens.Int(result.Usage.TotalTokens),
 		)
+		RecordCacheUsage(span, result.Usage.CacheReadTokens, result.Usage.CacheCreationTokens)
+	}
+	RecordCost(span, result.Cost)
+	if len(result.Annotations) > 0 {
+		span.SetAttributes(AttrGenAICitationsCount.Int(len(result.Annotations)))
 	}
 
 	// Determine finish reason from steps if available
@@ -812,7 +1051,30 @@ func RecordBraintrustCompletion(span trace.Span, result *core.TextResult, system
 		if len(lastStep.ToolCalls) > 0 {
 			finishReason = "tool_calls"
 		}
-		span.SetAttributes(attribute.String("gen_ai.completion.finish_reason", finishReason))
+		span.SetAttributes(AttrGenAICompletionFinishReason.String(finishReason))
+	}
+}
+
+// RecordAgentCompletion records a multi-step result, where the tool call
+// sequence matters as much as the final text. Besides everything
+// RecordBraintrustCompletion records, including the final text as
+// gen_ai.completion, it sets gen_ai.steps.count and, for each step N
+// (1-based) that made tool calls, gen_ai.step.N.tool_calls holding the calls
+// as JSON. WithGenAIObservability uses it for results with more than one step.
+func RecordAgentCompletion(span trace.Span, result *core.TextResult, system string) {
+	if span == nil || !span.IsRecording() || result == nil {
+		return
+	}
+
+	RecordBraintrustCompletion(span, result, system)
+	span.SetAttributes(AttrGenAIStepsCount.Int(len(result.Steps)))
+	for i, step := range result.Steps {
+		if len(step.ToolCalls) == 0 {
+			continue
+		}
+		if callsJSON, err := json.Marshal(step.ToolCalls); err == nil {
+			span.SetAttributes(attribute.String(fmt.Sprintf("gen_ai.step.%d.tool_calls", i+1), string(callsJSON)))
+		}
 	}
 }
 
@@ -838,9 +1100,9 @@ func ConfigureBraintrustSpan(span trace.Span, provider, model, operation string,
 
 	// Set core GenAI attributes (Critical Fix #1 & #2)
 	span.SetAttributes(
-		attribute.String("gen_ai.system", system), // Correct system mapping
-		attribute.String("gen_ai.operation.name", operation),
-		attribute.String("gen_ai.request.model", model),
+		AttrGenAISystem.String(system), // Correct system mapping
+		AttrGenAIOperationName.String(operation),
+		AttrGenAIRequestModel.String(model),
 	)
 
 	// Capture message content as attributes (Critical Fix #1)
@@ -904,19 +1166,12 @@ func WithGenAIObservability(ctx context.Context, provider, model string, operati
 		temp := request.Temperature
 		opts.Temperature = &temp
 	}
-	if request.MaxTokens > 0 {
-		maxTokens := request.MaxTokens
+	if maxTokens := core.OutputTokenLimit(request); maxTokens > 0 {
 		opts.MaxTokens = &maxTokens
 	}
 
 	// Add tools if present
-	if len(request.Tools) > 0 {
-		toolNames := make([]string, len(request.Tools))
-		for i, tool := range request.Tools {
-			toolNames[i] = tool.Name()
-		}
-		opts.Tools = toolNames
-	}
+	opts.Tools = requestToolNames(request.Tools)
 
 	// Start span
 	ctx, span := StartGenAISpan(ctx, opts)
@@ -931,9 +1186,15 @@ func WithGenAIObservability(ctx context.Context, provider, model string, operati
 		return nil, err
 	}
 
-	// Record successful completion
+	// Record successful completion, with the tool call sequence for
+	// multi-step results
 	if result != nil {
-		RecordBraintrustCompletion(span, result, system)
+		if len(result.Steps) > 1 {
+			RecordAgentCompletion(span, result, system)
+		} else {
+			RecordBraintrustCompletion(span, result, system)
+		}
+		RecordResponseModel(span, model, result.ModelUsed)
 	}
 
 	return result, nil
@@ -971,8 +1232,8 @@ func WithGenAIStreamingObservability(ctx context.Context, provider, model string
 	defer span.End()
 
 	// Record start of streaming
-	span.AddEvent("gen_ai.stream.start", trace.WithAttributes(
-		attribute.String("gen_ai.system", system),
+	span.AddEvent(EventGenAIStreamStart, trace.WithAttributes(
+		AttrGenAISystem.String(system),
 		attribute.String("stream.type", operation.Name),
 	))
 
@@ -981,15 +1242,15 @@ func WithGenAIStreamingObservability(ctx context.Context, provider, model string
 
 	if err != nil {
 		RecordError(span, err, fmt.Sprintf("Streaming %s failed", operation.Description))
-		span.AddEvent("gen_ai.stream.error", trace.WithAttributes(
+		span.AddEvent(EventGenAIStreamError, trace.WithAttributes(
 			attribute.String("error", err.Error()),
 		))
 		return nil, err
 	}
 
 	// Record successful stream completion
-	span.AddEvent("gen_ai.stream.complete", trace.WithAttributes(
-		attribute.String("gen_ai.system", system),
+	span.AddEvent(EventGenAIStreamComplete, trace.WithAttributes(
+		AttrGenAISystem.String(system),
 	))
 
 	return result, nil
@@ -1001,8 +1262,8 @@ func RecordStreamingChunk(span trace.Span, chunkText string, chunkIndex int, sys
 		return
 	}
 
-	span.AddEvent("gen_ai.stream.chunk", trace.WithAttributes(
-		attribute.String("gen_ai.system", system),
+	span.AddEvent(EventGenAIStreamChunk, trace.WithAttributes(
+		AttrGenAISystem.String(system),
 		attribute.Int("chunk.index", chunkIndex),
 		attribute.String("chunk.content", chunkText),
 		attribute.Int("chunk.length", len(chunkText)),
@@ -2,11 +2,14 @@ package obs
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/recera/gai/core"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -129,6 +132,69 @@ func TestStartRequestSpan(t *testing.T) {
 	checkAttribute(t, attrs, "metadata.user_id", "test123")
 }
 
+func TestRequestSpanVisionDetail(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+
+	messages := []core.Message{{
+		Role: core.User,
+		Parts: []core.Part{
+			core.Text{Text: "Describe these"},
+			core.ImageURL{URL: "https://example.com/a.jpg"},
+			core.ImageURL{URL: "https://example.com/b.jpg", Detail: "low"},
+		},
+	}}
+	_, span := StartGenAISpan(context.Background(), GenAIRequestSpanOptions{
+		System:    "openai",
+		Model:     "gpt-4o",
+		Operation: GenAIOperationChatCompletion,
+		Messages:  messages,
+	})
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	checkAttribute(t, spans[0].Attributes, "gen_ai.vision.detail", "low")
+}
+
+func TestWithGenAIAttributes(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+
+	req := core.Request{
+		Model:       "gpt-4o",
+		Temperature: 0.5,
+		MaxTokens:   256,
+		Messages: []core.Message{
+			{Role: core.System, Parts: []core.Part{core.Text{Text: "Be brief"}}},
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Find an order"}}},
+		},
+		Tools:    []core.ToolHandle{lookupTool{}},
+		Metadata: map[string]any{core.MetadataSessionID: "conv-1"},
+	}
+	_, span := Tracer().Start(context.Background(), "chat gpt-4o", WithGenAIAttributes(req))
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	attrs := spans[0].Attributes
+	checkAttribute(t, attrs, "gen_ai.request.model", "gpt-4o")
+	checkAttribute(t, attrs, "gen_ai.request.temperature", float64(0.5))
+	checkAttribute(t, attrs, "gen_ai.request.max_tokens", int64(256))
+	checkAttribute(t, attrs, "gen_ai.request.message_count", int64(2))
+	checkAttribute(t, attrs, "gen_ai.conversation.id", "conv-1")
+	checkAttribute(t, attrs, "gen_ai.tools", []string{"lookup"})
+	for _, attr := range attrs {
+		if attr.Key == AttrGenAIOutputType {
+			t.Error("gen_ai.output.type should be unset without a response schema")
+		}
+	}
+}
+
 func TestStartStepSpan(t *testing.T) {
 	exporter, cleanup := setupTestTracer()
 	defer cleanup()
@@ -161,6 +227,27 @@ func TestStartStepSpan(t *testing.T) {
 	checkAttribute(t, attrs, "step.text_length", int64(150))
 }
 
+func TestStartStepSpanUsage(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+
+	_, span := StartStepSpan(context.Background(), StepSpanOptions{
+		StepNumber: 1,
+		Usage:      &core.Usage{InputTokens: 40, OutputTokens: 10, TotalTokens: 50},
+	})
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	attrs := spans[0].Attributes
+	checkAttribute(t, attrs, string(AttrGenAIUsageInputTokens), int64(40))
+	checkAttribute(t, attrs, string(AttrGenAIUsageOutputTokens), int64(10))
+	checkAttribute(t, attrs, string(AttrGenAIUsageTotalTokens), int64(50))
+}
+
 func TestStartToolSpan(t *testing.T) {
 	exporter, cleanup := setupTestTracer()
 	defer cleanup()
@@ -199,6 +286,43 @@ func TestStartToolSpan(t *testing.T) {
 	checkAttribute(t, attrs, "tool.timeout_seconds", 30.0)
 }
 
+// testToolHandle is a minimal tool handle for span tests
+type testToolHandle struct {
+	schema string
+}
+
+func (h testToolHandle) Name() string         { return "search" }
+func (h testToolHandle) Description() string  { return "Search the web" }
+func (h testToolHandle) InSchemaJSON() []byte { return []byte(h.schema) }
+func (h testToolHandle) Timeout() int         { return 10 }
+
+func TestStartToolSpanFromHandle(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+
+	longSchema := `{"type":"object","description":"` + strings.Repeat("x", 2000) + `"}`
+	_, span := StartToolSpanFromHandle(context.Background(), testToolHandle{schema: longSchema}, "call_1", json.RawMessage(`{"q":"go"}`))
+	RecordToolStep(span, 2)
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name != "ai.tool.search" {
+		t.Errorf("expected span name 'ai.tool.search', got %s", spans[0].Name)
+	}
+
+	attrs := spans[0].Attributes
+	checkAttribute(t, attrs, "tool.name", "search")
+	checkAttribute(t, attrs, "tool.id", "call_1")
+	checkAttribute(t, attrs, "tool.description", "Search the web")
+	checkAttribute(t, attrs, "tool.schema_json", longSchema[:1024])
+	checkAttribute(t, attrs, "tool.input_size", int64(10))
+	checkAttribute(t, attrs, "tool.step_number", int64(2))
+	checkAttribute(t, attrs, "tool.timeout_seconds", 10.0)
+}
+
 func TestStartPromptSpan(t *testing.T) {
 	exporter, cleanup := setupTestTracer()
 	defer cleanup()
@@ -255,6 +379,118 @@ func TestRecordUsage(t *testing.T) {
 	checkAttribute(t, attrs, "usage.total_tokens", int64(300))
 }
 
+func TestRecordCost(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+
+	_, span := startSpan(context.Background(), "priced")
+	RecordCost(span, 0.0025)
+	span.End()
+	_, span = startSpan(context.Background(), "unpriced")
+	RecordCost(span, 0)
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	checkAttribute(t, spans[0].Attributes, "gen_ai.cost.usd", 0.0025)
+	for _, attr := range spans[1].Attributes {
+		if attr.Key == AttrGenAICostUSD {
+			t.Error("zero cost should not be recorded")
+		}
+	}
+}
+
+func TestRecordResponseModel(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+
+	_, span := startSpan(context.Background(), "fallback")
+	RecordResponseModel(span, "gpt-4o", "gpt-4o-mini")
+	span.End()
+	for _, used := range []string{"gpt-4o", ""} {
+		_, span = startSpan(context.Background(), "same")
+		RecordResponseModel(span, "gpt-4o", used)
+		span.End()
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	checkAttribute(t, spans[0].Attributes, "gen_ai.response.model", "gpt-4o-mini")
+	for _, s := range spans[1:] {
+		for _, attr := range s.Attributes {
+			if attr.Key == AttrGenAIResponseModel {
+				t.Errorf("response model recorded for %q", attr.Value.AsString())
+			}
+		}
+	}
+}
+
+func TestRecordAgentCompletion(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+
+	multiStep := &core.TextResult{
+		Text: "It is sunny in Paris.",
+		Steps: []core.Step{
+			{ToolCalls: []core.ToolCall{{ID: "call_1", Name: "get_weather", Input: json.RawMessage(`{"city":"Paris"}`)}}},
+			{Text: "It is sunny in Paris."},
+		},
+	}
+	singleStep := &core.TextResult{Text: "Hello", Steps: []core.Step{{Text: "Hello"}}}
+	for _, result := range []*core.TextResult{multiStep, singleStep} {
+		_, err := WithGenAIObservability(context.Background(), "openai", "gpt-4o", GenAIOpChatCompletion, core.Request{},
+			func(ctx context.Context) (*core.TextResult, error) { return result, nil })
+		if err != nil {
+			t.Fatalf("WithGenAIObservability failed: %v", err)
+		}
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	checkAttribute(t, spans[0].Attributes, "gen_ai.completion", "It is sunny in Paris.")
+	checkAttribute(t, spans[0].Attributes, "gen_ai.steps.count", int64(2))
+	checkAttribute(t, spans[0].Attributes, "gen_ai.step.1.tool_calls", `[{"id":"call_1","name":"get_weather","input":{"city":"Paris"}}]`)
+	for _, attr := range spans[0].Attributes {
+		if attr.Key == "gen_ai.step.2.tool_calls" {
+			t.Error("step without tool calls should not be recorded")
+		}
+	}
+
+	// Single-step results are recorded without the step sequence
+	checkAttribute(t, spans[1].Attributes, "gen_ai.completion", "Hello")
+	for _, attr := range spans[1].Attributes {
+		if attr.Key == AttrGenAIStepsCount {
+			t.Error("steps count recorded for a single-step result")
+		}
+	}
+}
+
+func TestRecordCompletionCitations(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+
+	_, span := startSpan(context.Background(), "cited")
+	RecordBraintrustCompletion(span, &core.TextResult{
+		Text: "Go 1.23 was released in August 2024.",
+		Annotations: []core.Annotation{
+			{Type: "web_search_result_location", EndIndex: 36, URL: "https://go.dev/blog/go1.23"},
+		},
+	}, "anthropic")
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	checkAttribute(t, spans[0].Attributes, "gen_ai.citations.count", int64(1))
+}
+
 func TestRecordError(t *testing.T) {
 	exporter, cleanup := setupTestTracer()
 	defer cleanup()
@@ -316,6 +552,44 @@ func TestRecordToolResult(t *testing.T) {
 	}
 }
 
+func TestToolSpanContent(t *testing.T) {
+	exporter, cleanup := setupTestTracer()
+	defer cleanup()
+	
+	ctx := context.Background()
+	input := json.RawMessage(`{"location":"Paris"}`)
+	
+	// Input is recorded at span start, output after execution
+	_, span := StartToolSpan(ctx, ToolSpanOptions{ToolName: "get_weather", Input: input})
+	RecordToolResultContent(span, map[string]any{"temp": 21}, nil)
+	span.End()
+	
+	// Errors are recorded as error output
+	_, span = StartToolSpan(ctx, ToolSpanOptions{ToolName: "get_weather", Input: input})
+	RecordToolResultContent(span, nil, errors.New("service unavailable"))
+	span.End()
+	
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	
+	attrs := spans[0].Attributes
+	checkAttribute(t, attrs, "tool.input_size", int64(len(input)))
+	checkAttribute(t, attrs, "braintrust.input_json", `{"location":"Paris"}`)
+	checkAttribute(t, attrs, "braintrust.output_json", `{"temp":21}`)
+	if spans[0].Status.Code != codes.Ok {
+		t.Errorf("expected OK status, got %v", spans[0].Status.Code)
+	}
+	
+	attrs = spans[1].Attributes
+	checkAttribute(t, attrs, "braintrust.input_json", `{"location":"Paris"}`)
+	checkAttribute(t, attrs, "braintrust.output_json", `{"error":"service unavailable"}`)
+	if spans[1].Status.Code != codes.Error {
+		t.Errorf("expected Error status, got %v", spans[1].Status.Code)
+	}
+}
+
 func TestRecordProviderLatency(t *testing.T) {
 	exporter, cleanup := setupTestTracer()
 	defer cleanup()
@@ -37,6 +37,8 @@ type ProviderUsage struct {
 	
 	// Per-model breakdown
 	ModelUsage map[string]*ModelUsage
+	// Per-tool breakdown, recorded by collectors with BreakdownByTool
+	ToolUsage map[string]*ToolUsage
 }
 
 // ModelUsage tracks usage for a specific model
@@ -49,6 +51,15 @@ type ModelUsage struct {
 	LastUpdated      time.Time
 }
 
+// ToolUsage tracks executions of a specific tool
+type ToolUsage struct {
+	Tool          string
+	Calls         int64
+	Errors        int64
+	AvgDurationMs float64
+	LastUpdated   time.Time
+}
+
 // NewUsageCollector creates a new usage collector with the specified window
 func NewUsageCollector(window time.Duration) *UsageCollector {
 	return &UsageCollector{
@@ -60,23 +71,16 @@ func NewUsageCollector(window time.Duration) *UsageCollector {
 
 // Record records usage for a request
 func (c *UsageCollector) Record(ctx context.Context, provider, model string, usage Usage) {
+	c.record(provider, model, usage, true)
+}
+
+// record records usage for a request, adding it to the model breakdown if
+// byModel is set
+func (c *UsageCollector) record(provider, model string, usage Usage, byModel bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	
-	// Check if we need to reset the window
-	if time.Since(c.lastReset) > c.window {
-		c.resetLocked()
-	}
-	
-	// Get or create provider usage
-	pu, exists := c.usage[provider]
-	if !exists {
-		pu = &ProviderUsage{
-			Provider:   provider,
-			ModelUsage: make(map[string]*ModelUsage),
-		}
-		c.usage[provider] = pu
-	}
+	pu := c.providerLocked(provider)
 	
 	// Update provider totals
 	pu.TotalRequests++
@@ -85,6 +89,10 @@ func (c *UsageCollector) Record(ctx context.Context, provider, model string, usa
 	pu.TotalCostMicrocents += usage.EstimatedCostMicrocents
 	pu.LastUpdated = time.Now()
 	
+	if !byModel {
+		return
+	}
+	
 	// Get or create model usage
 	mu, exists := pu.ModelUsage[model]
 	if !exists {
@@ -102,6 +110,49 @@ func (c *UsageCollector) Record(ctx context.Context, provider, model string, usa
 	mu.LastUpdated = time.Now()
 }
 
+// RecordTool records a tool execution for a provider
+func (c *UsageCollector) RecordTool(provider, tool string, duration time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pu := c.providerLocked(provider)
+	pu.LastUpdated = time.Now()
+
+	tu, exists := pu.ToolUsage[tool]
+	if !exists {
+		tu = &ToolUsage{Tool: tool}
+		pu.ToolUsage[tool] = tu
+	}
+	tu.Calls++
+	if err != nil {
+		tu.Errors++
+	}
+	// Running mean, so no per-call history is kept
+	ms := float64(duration) / float64(time.Millisecond)
+	tu.AvgDurationMs += (ms - tu.AvgDurationMs) / float64(tu.Calls)
+	tu.LastUpdated = pu.LastUpdated
+}
+
+// providerLocked returns the usage of a provider, creating it and resetting
+// an expired window first (must be called with lock held)
+func (c *UsageCollector) providerLocked(provider string) *ProviderUsage {
+	// Check if we need to reset the window
+	if time.Since(c.lastReset) > c.window {
+		c.resetLocked()
+	}
+
+	pu, exists := c.usage[provider]
+	if !exists {
+		pu = &ProviderUsage{
+			Provider:   provider,
+			ModelUsage: make(map[string]*ModelUsage),
+			ToolUsage:  make(map[string]*ToolUsage),
+		}
+		c.usage[provider] = pu
+	}
+	return pu
+}
+
 // GetProviderUsage returns usage for a specific provider
 func (c *UsageCollector) GetProviderUsage(provider string) *ProviderUsage {
 	c.mu.RLock()
@@ -149,6 +200,7 @@ func (c *UsageCollector) copyProviderUsage(pu *ProviderUsage) *ProviderUsage {
 		TotalCostMicrocents: pu.TotalCostMicrocents,
 		LastUpdated:         pu.LastUpdated,
 		ModelUsage:          make(map[string]*ModelUsage, len(pu.ModelUsage)),
+		ToolUsage:           make(map[string]*ToolUsage, len(pu.ToolUsage)),
 	}
 	
 	for k, v := range pu.ModelUsage {
@@ -162,6 +214,11 @@ func (c *UsageCollector) copyProviderUsage(pu *ProviderUsage) *ProviderUsage {
 		}
 	}
 	
+	for k, v := range pu.ToolUsage {
+		tu := *v
+		result.ToolUsage[k] = &tu
+	}
+	
 	return result
 }
 
@@ -194,11 +251,13 @@ var modelCosts = map[string]struct {
 func EstimateCost(model string, inputTokens, outputTokens int) int64 {
 	costs, exists := modelCosts[model]
 	if !exists {
-		// Try to find a partial match
+		// Try the longest model the name starts with, so dated versions
+		// such as "gpt-4o-mini-2024-07-18" price as "gpt-4o-mini"
+		matched := 0
 		for key, val := range modelCosts {
-			if len(key) > 0 && len(model) >= len(key) && model[:len(key)] == key {
-				costs = val
-				break
+			if len(key) > matched && len(model) >= len(key) && model[:len(key)] == key {
+				costs, matched = val, len(key)
+				exists = true
 			}
 		}
 		// If still not found, use default
@@ -2,6 +2,7 @@ package obs
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -221,6 +222,60 @@ func TestUsageCollectorWindowReset(t *testing.T) {
 	}
 }
 
+func TestUsageCollectorRecordTool(t *testing.T) {
+	collector := NewUsageCollector(time.Hour)
+	
+	collector.RecordTool("openai", "search", 10*time.Millisecond, nil)
+	collector.RecordTool("openai", "search", 30*time.Millisecond, errors.New("timeout"))
+	collector.RecordTool("openai", "calculator", time.Millisecond, nil)
+	
+	pu := collector.GetProviderUsage("openai")
+	if pu == nil || len(pu.ToolUsage) != 2 {
+		t.Fatalf("expected 2 tools, got %+v", pu)
+	}
+	search := pu.ToolUsage["search"]
+	if search.Calls != 2 || search.Errors != 1 || search.AvgDurationMs != 20 {
+		t.Errorf("unexpected search usage: %+v", search)
+	}
+	// Tool executions are not requests
+	if pu.TotalRequests != 0 {
+		t.Errorf("expected no requests, got %d", pu.TotalRequests)
+	}
+}
+
+func TestCollectorBreakdown(t *testing.T) {
+	ctx := context.Background()
+	provider := "breakdown-test"
+	
+	for _, model := range []string{"gpt-4o", "gpt-4o-mini", "gpt-4o-mini"} {
+		NewCollector(ctx, provider, model, CollectorOpts{BreakdownByModel: true}).RecordUsageMetrics(100, 50)
+	}
+	collector := NewCollector(ctx, provider, "gpt-4o", CollectorOpts{BreakdownByTool: true})
+	collector.RecordUsageMetrics(10, 5)
+	collector.RecordToolExecution("search", 5*time.Millisecond, nil)
+	
+	usage := collector.GetUsage()
+	if usage.TotalRequests != 4 || usage.TotalInputTokens != 310 {
+		t.Errorf("unexpected totals: %+v", usage)
+	}
+	// The collector without BreakdownByModel is only counted in the totals
+	if mini := usage.ModelUsage["gpt-4o-mini"]; mini == nil || mini.Requests != 2 || mini.OutputTokens != 100 {
+		t.Errorf("unexpected gpt-4o-mini usage: %+v", mini)
+	}
+	if gpt4o := usage.ModelUsage["gpt-4o"]; gpt4o == nil || gpt4o.Requests != 1 {
+		t.Errorf("unexpected gpt-4o usage: %+v", gpt4o)
+	}
+	if search := usage.ToolUsage["search"]; search == nil || search.Calls != 1 {
+		t.Errorf("unexpected search usage: %+v", search)
+	}
+	
+	// Without options only the model breakdown is recorded
+	NewCollector(ctx, provider, "gpt-4o").RecordToolExecution("calculator", time.Millisecond, nil)
+	if _, ok := collector.GetUsage().ToolUsage["calculator"]; ok {
+		t.Error("tool breakdown should be disabled by default")
+	}
+}
+
 func TestEstimateCost(t *testing.T) {
 	tests := []struct {
 		model        string
@@ -243,6 +298,10 @@ func TestEstimateCost(t *testing.T) {
 		{"gemini-1.5-pro", 1000, 1000, 350 + 1050},  // $0.0035 + $0.0105 = $0.014 = 14000 microcents
 		{"gemini-1.5-flash", 1000, 1000, 7 + 21},    // $0.00007 + $0.00021 = $0.00028 = 280 microcents
 		
+		// Dated versions price as the longest matching model
+		{"gpt-4o-2024-08-06", 1000, 1000, 500 + 1500},
+		{"gpt-4o-mini-2024-07-18", 1000, 1000, 15 + 60},
+		
 		// Unknown model (should use default)
 		{"unknown-model", 1000, 1000, 100 + 200},    // $0.001 + $0.002 = $0.003 = 3000 microcents
 		
@@ -0,0 +1,60 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/recera/gai/obs"
+)
+
+// PromptVariantKey is the context key under which PromptABTest records the
+// chosen variant (a string). It is obs.PromptVariantKey, so spans started
+// with the returned context get a prompt.variant attribute.
+var PromptVariantKey = obs.PromptVariantKey
+
+// PromptABTest renders one of several versions of a template for prompt
+// experiments. router is called once per call to pick the version, which
+// must be one of variants; use UniformRouter for an even random split, or a
+// hash of a user ID for a consistent assignment per user. The returned
+// context records the chosen version under PromptVariantKey, so pass it to
+// the provider call to attribute its telemetry to the variant.
+//
+// Example:
+//
+//	variants := []string{"1.0.0", "2.0.0"}
+//	ctx, prompt, id, err := prompts.PromptABTest(ctx, reg, "support_agent", variants, data, prompts.UniformRouter(variants))
+//	if err != nil {
+//		return err
+//	}
+//	result, err := provider.GenerateText(ctx, core.Request{SystemPrompt: prompt, Messages: msgs})
+//	log.Printf("answered with %s@%s", id.Name, id.Version)
+func PromptABTest(ctx context.Context, reg *Registry, name string, variants []string, data any, router func() string) (context.Context, string, *TemplateID, error) {
+	if len(variants) == 0 {
+		return ctx, "", nil, fmt.Errorf("A/B test for template %q has no variants", name)
+	}
+	variant := router()
+	if !slices.Contains(variants, variant) {
+		return ctx, "", nil, fmt.Errorf("router chose %q, which is not a variant of template %q", variant, name)
+	}
+
+	ctx = obs.ContextWithPromptVariant(ctx, variant)
+	rendered, id, err := reg.render(ctx, name, variant, data)
+	if err != nil {
+		return ctx, "", nil, err
+	}
+	return ctx, rendered, id, nil
+}
+
+// UniformRouter returns a router for PromptABTest that picks one of
+// variants at random with equal probability.
+func UniformRouter(variants []string) func() string {
+	variants = slices.Clone(variants)
+	return func() string {
+		if len(variants) == 0 {
+			return ""
+		}
+		return variants[rand.IntN(len(variants))]
+	}
+}
@@ -0,0 +1,66 @@
+package prompts
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPromptABTest(t *testing.T) {
+	reg, err := NewRegistry(testFS)
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+	variants := []string{"1.0.0", "1.1.0"}
+	data := map[string]any{"Name": "Ada"}
+
+	ctx, rendered, id, err := PromptABTest(context.Background(), reg, "greet", variants, data, func() string { return "1.1.0" })
+	if err != nil {
+		t.Fatalf("PromptABTest failed: %v", err)
+	}
+	if rendered != "Greetings, Ada! Welcome to version 1.1.0." {
+		t.Errorf("unexpected render: %q", rendered)
+	}
+	if id.Version != "1.1.0" {
+		t.Errorf("TemplateID.Version = %q, want 1.1.0", id.Version)
+	}
+	if got, _ := ctx.Value(PromptVariantKey).(string); got != "1.1.0" {
+		t.Errorf("context variant = %q, want 1.1.0", got)
+	}
+
+	// Struct data works as well as maps
+	_, rendered, _, err = PromptABTest(context.Background(), reg, "greet", variants, struct{ Name string }{"Lin"}, func() string { return "1.0.0" })
+	if err != nil || rendered != "Hello, Lin!" {
+		t.Errorf("struct data: got %q, %v", rendered, err)
+	}
+
+	if _, _, _, err := PromptABTest(context.Background(), reg, "greet", variants, data, func() string { return "9.9.9" }); err == nil {
+		t.Error("expected an error when the router picks an unknown variant")
+	}
+	if _, _, _, err := PromptABTest(context.Background(), reg, "greet", nil, data, func() string { return "1.0.0" }); err == nil {
+		t.Error("expected an error without variants")
+	}
+}
+
+func TestUniformRouter(t *testing.T) {
+	variants := []string{"a", "b", "c"}
+	router := UniformRouter(variants)
+	variants[0] = "changed"
+
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		counts[router()]++
+	}
+	for _, v := range []string{"a", "b", "c"} {
+		// Expect about 1000 each; the bounds are far outside random variation
+		if counts[v] < 800 || counts[v] > 1200 {
+			t.Errorf("variant %q chosen %d times of 3000", v, counts[v])
+		}
+	}
+	if counts["changed"] != 0 {
+		t.Error("router should not see later changes to the variants slice")
+	}
+
+	if got := UniformRouter(nil)(); got != "" {
+		t.Errorf("empty router returned %q", got)
+	}
+}
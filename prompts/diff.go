@@ -0,0 +1,201 @@
+package prompts
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// LatestVersion can be passed as a version to Diff to select the newest
+// version of a template.
+const LatestVersion = "latest"
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// Diff renders two versions of a template with identical minimal data (an
+// empty map, so missing fields render as "<no value>") and returns a unified
+// diff of the outputs. An empty string means the renders are identical. A
+// version of "latest" or "" selects the newest version. Use DiffWithData for
+// templates that need real data to render.
+func (r *Registry) Diff(name, versionA, versionB string) (string, error) {
+	return r.DiffWithData(name, versionA, versionB, nil)
+}
+
+// DiffWithData is like Diff but renders both versions with data.
+func (r *Registry) DiffWithData(name, versionA, versionB string, data map[string]any) (string, error) {
+	if data == nil {
+		data = map[string]any{}
+	}
+
+	a, err := r.renderVersion(name, versionA, data)
+	if err != nil {
+		return "", err
+	}
+	b, err := r.renderVersion(name, versionB, data)
+	if err != nil {
+		return "", err
+	}
+
+	return unifiedDiff(name+"@"+a.Version, name+"@"+b.Version, a.output, b.output), nil
+}
+
+// renderedVersion is a template version and its rendered output.
+type renderedVersion struct {
+	*Template
+	output string
+}
+
+// renderVersion renders an exact template version without recording render
+// statistics, so diffs don't skew production timings.
+func (r *Registry) renderVersion(name, version string, data map[string]any) (renderedVersion, error) {
+	if version == LatestVersion || version == "" {
+		versions := r.List()[name]
+		if len(versions) == 0 {
+			return renderedVersion{}, fmt.Errorf("template %q not found", name)
+		}
+		version = versions[len(versions)-1]
+	}
+
+	tmpl, err := r.Get(name, version)
+	if err != nil {
+		return renderedVersion{}, err
+	}
+
+	t, err := template.New(name).Funcs(r.funcMap).Parse(tmpl.Content)
+	if err != nil {
+		return renderedVersion{}, fmt.Errorf("failed to parse %s@%s: %w", name, version, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return renderedVersion{}, fmt.Errorf("failed to render %s@%s: %w", name, version, err)
+	}
+	return renderedVersion{Template: tmpl, output: buf.String()}, nil
+}
+
+// diffOp is one line of an edit script.
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// unifiedDiff returns a unified diff between a and b, or "" if they are
+// equal.
+func unifiedDiff(nameA, nameB, a, b string) string {
+	if a == b {
+		return ""
+	}
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk until changes are more than 2*diffContext apart
+		from := max(start-diffContext, 0)
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+		to := min(end+diffContext, len(ops))
+
+		// Line numbers are 1-based positions in each input
+		lineA, lineB := 1, 1
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				lineA++
+			}
+			if op.kind != '-' {
+				lineB++
+			}
+		}
+		countA, countB := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				countA++
+			}
+			if op.kind != '-' {
+				countB++
+			}
+		}
+		if countA == 0 {
+			lineA--
+		}
+		if countB == 0 {
+			lineB--
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", lineA, countA, lineB, countB)
+		for _, op := range ops[from:to] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			out.WriteByte('\n')
+		}
+		start = to
+	}
+
+	return out.String()
+}
+
+// splitLines splits s into lines without their trailing newlines.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes a line edit script from a to b using the longest
+// common subsequence. Prompts are small, so the quadratic table is fine.
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
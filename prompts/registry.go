@@ -66,6 +66,12 @@ type Registry struct {
 
 	// strictVersioning requires exact version matches
 	strictVersioning bool
+
+	// renderStats maps template names to their *renderRecorder
+	renderStats sync.Map
+
+	// slowRenderThreshold is the median render time reported as slow by ValidateWithWarnings
+	slowRenderThreshold time.Duration
 }
 
 // Option configures a Registry.
@@ -102,6 +108,8 @@ func NewRegistry(embedFS embed.FS, opts ...Option) (*Registry, error) {
 		templates:    make(map[string]*Template),
 		versionIndex: make(map[string][]string),
 		funcMap:      defaultFuncMap(),
+
+		slowRenderThreshold: defaultSlowRenderThreshold,
 	}
 
 	for _, opt := range opts {
@@ -248,12 +256,21 @@ func (r *Registry) loadOverrides() error {
 
 // Render renders a template with the given data.
 func (r *Registry) Render(ctx context.Context, name, version string, data map[string]any) (string, *TemplateID, error) {
+	return r.render(ctx, name, version, data)
+}
+
+// render renders a template with data of any type. Data keys are only
+// recorded for map data.
+func (r *Registry) render(ctx context.Context, name, version string, data any) (string, *TemplateID, error) {
 	startTime := time.Now()
 	
 	// Extract data keys for observability
-	dataKeys := make([]string, 0, len(data))
-	for k := range data {
-		dataKeys = append(dataKeys, k)
+	var dataKeys []string
+	if m, ok := data.(map[string]any); ok {
+		dataKeys = make([]string, 0, len(m))
+		for k := range m {
+			dataKeys = append(dataKeys, k)
+		}
 	}
 	
 	r.mu.RLock()
@@ -303,12 +320,14 @@ func (r *Registry) Render(ctx context.Context, name, version string, data map[st
 	t, err := template.New(name).Funcs(r.funcMap).Parse(tmpl.Content)
 	if err != nil {
 		obs.RecordError(span, err, "Template parsing failed")
+		r.recorder(name).errors.Add(1)
 		return "", nil, fmt.Errorf("failed to parse template: %w", err)
 	}
 
 	var buf bytes.Buffer
 	if err := t.Execute(&buf, data); err != nil {
 		obs.RecordError(span, err, "Template execution failed")
+		r.recorder(name).errors.Add(1)
 		return "", nil, fmt.Errorf("failed to execute template: %w", err)
 	}
 
@@ -319,6 +338,7 @@ func (r *Registry) Render(ctx context.Context, name, version string, data map[st
 	}
 
 	// Record metrics
+	r.recorder(name).record(time.Since(startTime))
 	obs.RecordPromptRender(ctx, name, tmpl.Version, cacheHit, time.Since(startTime))
 	obs.RecordCacheHit(ctx, "prompt", cacheHit)
 
@@ -551,7 +571,8 @@ func (r *Registry) Export(w io.Writer) error {
 	return enc.Encode(data)
 }
 
-// Stats returns statistics about the registry.
+// Stats returns statistics about the registry. The "render" entry holds a
+// map[string]RenderStats with render timings and errors per template name.
 func (r *Registry) Stats() map[string]any {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -572,5 +593,6 @@ func (r *Registry) Stats() map[string]any {
 		"embedded":        embeddedCount,
 		"overrides":       overrideCount,
 		"override_dir":    r.overrideDir,
+		"render":          r.RenderStats(),
 	}
 }
\ No newline at end of file
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -417,6 +418,88 @@ func TestExport(t *testing.T) {
 	}
 }
 
+// TestSnapshotRoundTrip tests reloading snapshots in both formats.
+func TestSnapshotRoundTrip(t *testing.T) {
+	reg, err := NewRegistry(testFS)
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	_, want, err := reg.Render(context.Background(), "greet", "1.0.0", map[string]any{"Name": "Ada"})
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+
+	for _, format := range []string{SnapshotJSON, SnapshotTar} {
+		t.Run(format, func(t *testing.T) {
+			data, err := reg.Snapshot(format)
+			if err != nil {
+				t.Fatalf("failed to snapshot: %v", err)
+			}
+
+			loaded, err := NewRegistryFromSnapshot(data)
+			if err != nil {
+				t.Fatalf("failed to load snapshot: %v", err)
+			}
+			if !reflect.DeepEqual(loaded.List(), reg.List()) {
+				t.Errorf("loaded templates %v, want %v", loaded.List(), reg.List())
+			}
+
+			_, got, err := loaded.Render(context.Background(), "greet", "1.0.0", map[string]any{"Name": "Ada"})
+			if err != nil {
+				t.Fatalf("failed to render loaded template: %v", err)
+			}
+			if got.Fingerprint != want.Fingerprint {
+				t.Errorf("fingerprint = %s, want %s", got.Fingerprint, want.Fingerprint)
+			}
+		})
+	}
+}
+
+// TestSnapshotFingerprint tests that JSON snapshots record render
+// fingerprints and that tampered content is rejected.
+func TestSnapshotFingerprint(t *testing.T) {
+	reg, err := NewRegistry(testFS)
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+	_, id, err := reg.Render(context.Background(), "greet", "1.0.0", map[string]any{"Name": "Ada"})
+	if err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+
+	data, err := reg.Snapshot(SnapshotJSON)
+	if err != nil {
+		t.Fatalf("failed to snapshot: %v", err)
+	}
+
+	var doc snapshotDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	found := false
+	for i, tmpl := range doc.Templates {
+		if tmpl.Name == "greet" && tmpl.Version == "1.0.0" {
+			found = true
+			if tmpl.Fingerprint != id.Fingerprint {
+				t.Errorf("exported fingerprint %s, rendered %s", tmpl.Fingerprint, id.Fingerprint)
+			}
+			doc.Templates[i].Content = []byte("tampered")
+		}
+	}
+	if !found {
+		t.Fatal("greet@1.0.0 missing from snapshot")
+	}
+
+	tampered, _ := json.Marshal(doc)
+	if _, err := NewRegistryFromSnapshot(tampered); err == nil {
+		t.Error("expected tampered snapshot to be rejected")
+	}
+	if _, err := reg.Snapshot("zip"); err == nil {
+		t.Error("expected unsupported format to fail")
+	}
+}
+
 // TestStats tests registry statistics.
 func TestStats(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -452,6 +535,79 @@ func TestStats(t *testing.T) {
 	}
 }
 
+// TestRenderStats tests render timing and error statistics.
+func TestRenderStats(t *testing.T) {
+	reg, err := NewRegistry(testFS, WithSlowRenderThreshold(time.Nanosecond))
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < minSlowRenderSamples; i++ {
+		if _, _, err := reg.Render(ctx, "greet", "1.0.0", map[string]any{"Name": "Alice"}); err != nil {
+			t.Fatalf("render failed: %v", err)
+		}
+	}
+
+	// A template that fails to execute counts as a render error
+	reg.mu.Lock()
+	reg.templates["broken@1.0.0"] = &Template{Name: "broken", Version: "1.0.0", Content: "{{.Name.Missing}}", Source: "test"}
+	reg.versionIndex["broken"] = []string{"1.0.0"}
+	reg.mu.Unlock()
+	if _, _, err := reg.Render(ctx, "broken", "1.0.0", map[string]any{"Name": "Alice"}); err == nil {
+		t.Fatal("expected render error")
+	}
+
+	render, ok := reg.Stats()["render"].(map[string]RenderStats)
+	if !ok {
+		t.Fatalf("expected render stats, got %T", reg.Stats()["render"])
+	}
+	greet := render["greet"]
+	if greet.Renders != minSlowRenderSamples || greet.RenderErrors != 0 {
+		t.Errorf("greet stats = %+v", greet)
+	}
+	if greet.P50RenderMs <= 0 || greet.P50RenderMs > greet.P95RenderMs || greet.P95RenderMs > greet.MaxRenderMs {
+		t.Errorf("expected ordered positive percentiles, got %+v", greet)
+	}
+	if render["broken"].RenderErrors != 1 {
+		t.Errorf("broken stats = %+v", render["broken"])
+	}
+
+	// Consistently slow templates are reported as warnings
+	warnings, err := reg.ValidateWithWarnings("greet", "1.0.0")
+	if err != nil {
+		t.Fatalf("validation failed: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "greet") {
+		t.Errorf("expected slow render warning, got %v", warnings)
+	}
+
+	reg.ResetStats()
+	if len(reg.RenderStats()) != 0 {
+		t.Error("expected no render stats after reset")
+	}
+	if warnings, _ := reg.ValidateWithWarnings("greet", "1.0.0"); len(warnings) != 0 {
+		t.Errorf("expected no warnings after reset, got %v", warnings)
+	}
+}
+
+// TestRenderStatsWindow tests that percentiles cover only recent renders.
+func TestRenderStatsWindow(t *testing.T) {
+	var rr renderRecorder
+	rr.record(time.Second)
+	for i := 0; i < renderWindowSize; i++ {
+		rr.record(time.Millisecond)
+	}
+
+	stats := rr.stats()
+	if stats.Renders != renderWindowSize+1 {
+		t.Errorf("Renders = %d, want %d", stats.Renders, renderWindowSize+1)
+	}
+	if stats.MaxRenderMs != 1 || stats.P50RenderMs != 1 {
+		t.Errorf("expected old render to leave the window, got %+v", stats)
+	}
+}
+
 // TestConcurrentAccess tests thread-safe operations.
 func TestConcurrentAccess(t *testing.T) {
 	reg, err := NewRegistry(testFS)
@@ -540,4 +696,52 @@ func TestCustomHelperFunc(t *testing.T) {
 	if result != "custom: test" {
 		t.Errorf("result = %q, want %q", result, "custom: test")
 	}
-}
\ No newline at end of file
+}
+// TestDiff tests diffing rendered template versions.
+func TestDiff(t *testing.T) {
+	reg, err := NewRegistry(testFS)
+	if err != nil {
+		t.Fatalf("failed to create registry: %v", err)
+	}
+
+	diff, err := reg.DiffWithData("greet", "1.0.0", LatestVersion, map[string]any{"Name": "Ada"})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	expected := "--- greet@1.0.0\n+++ greet@1.1.0\n@@ -1,1 +1,1 @@\n-Hello, Ada!\n+Greetings, Ada! Welcome to version 1.1.0.\n"
+	if diff != expected {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+
+	// Minimal data renders missing fields identically in both versions
+	diff, err = reg.Diff("greet", "1.0.0", "1.1.0")
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !strings.Contains(diff, "-Hello, <no value>!") {
+		t.Errorf("expected minimal data render, got:\n%s", diff)
+	}
+
+	if diff, err := reg.Diff("greet", "1.1.0", "latest"); err != nil || diff != "" {
+		t.Errorf("expected empty diff for the same version, got %q, %v", diff, err)
+	}
+	if _, err := reg.Diff("greet", "1.0.0", "9.9.9"); err == nil {
+		t.Error("expected error for unknown version")
+	}
+	if _, err := reg.Diff("missing", "latest", "latest"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+// TestUnifiedDiff tests hunk construction for multi-line changes.
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+
+	expected := "--- a\n+++ b\n" +
+		"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+		"@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n"
+	if diff := unifiedDiff("a", "b", a, b); diff != expected {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+}
@@ -0,0 +1,227 @@
+package prompts
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+)
+
+// Snapshot formats accepted by Registry.Snapshot.
+const (
+	// SnapshotJSON is a JSON document listing every template
+	SnapshotJSON = "json"
+	// SnapshotTar is a gzip-compressed tar archive of template files
+	SnapshotTar = "tar"
+)
+
+// snapshotDocument is the JSON snapshot format.
+type snapshotDocument struct {
+	Templates []snapshotTemplate `json:"templates"`
+	Exported  time.Time          `json:"exported"`
+}
+
+// snapshotTemplate is a template in a JSON snapshot. Content is encoded as
+// base64 by encoding/json.
+type snapshotTemplate struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Fingerprint string `json:"fingerprint"`
+	Source      string `json:"source"`
+	Content     []byte `json:"content"`
+}
+
+// Snapshot returns a snapshot of every loaded template, including overrides,
+// for compliance records and rollback. Unlike Export, it records each
+// template's fingerprint and can be reloaded. Format "json" produces a JSON
+// document listing each template's name, version, fingerprint and
+// base64-encoded content; "tar" produces a tar.gz archive of
+// "name@version.tmpl" files. Fingerprints match the TemplateID returned by
+// Render. Either format can be loaded with NewRegistryFromSnapshot.
+func (r *Registry) Snapshot(format string) ([]byte, error) {
+	templates := r.sortedTemplates()
+
+	switch format {
+	case SnapshotJSON:
+		doc := snapshotDocument{
+			Templates: make([]snapshotTemplate, len(templates)),
+			Exported:  time.Now(),
+		}
+		for i, tmpl := range templates {
+			doc.Templates[i] = snapshotTemplate{
+				Name:        tmpl.Name,
+				Version:     tmpl.Version,
+				Fingerprint: tmpl.Fingerprint,
+				Source:      tmpl.Source,
+				Content:     []byte(tmpl.Content),
+			}
+		}
+		return json.MarshalIndent(doc, "", "  ")
+	case SnapshotTar:
+		return snapshotTar(templates)
+	default:
+		return nil, fmt.Errorf("unsupported snapshot format %q", format)
+	}
+}
+
+// sortedTemplates returns copies of all templates ordered by name and version.
+func (r *Registry) sortedTemplates() []Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := make([]Template, 0, len(r.templates))
+	for _, tmpl := range r.templates {
+		templates = append(templates, *tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Name != templates[j].Name {
+			return templates[i].Name < templates[j].Name
+		}
+		return compareVersions(templates[i].Version, templates[j].Version) < 0
+	})
+	return templates
+}
+
+// snapshotTar writes templates to a tar.gz archive.
+func snapshotTar(templates []Template) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for _, tmpl := range templates {
+		hdr := &tar.Header{
+			Name:    fmt.Sprintf("%s@%s.tmpl", tmpl.Name, tmpl.Version),
+			Mode:    0644,
+			Size:    int64(len(tmpl.Content)),
+			ModTime: tmpl.LoadedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", hdr.Name, err)
+		}
+		if _, err := io.WriteString(tw, tmpl.Content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", hdr.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// NewRegistryFromSnapshot creates a registry holding the templates in data, a
+// snapshot produced by Registry.Snapshot in either format. The format is
+// detected automatically. JSON snapshots are rejected if a template's content
+// does not match its recorded fingerprint. Loaded templates are treated as
+// embedded, so Reload keeps them and WithOverrideDir layers overrides on top.
+func NewRegistryFromSnapshot(data []byte, opts ...Option) (*Registry, error) {
+	r := &Registry{
+		templates:    make(map[string]*Template),
+		versionIndex: make(map[string][]string),
+		funcMap:      defaultFuncMap(),
+
+		slowRenderThreshold: defaultSlowRenderThreshold,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	var err error
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		err = r.loadTarSnapshot(data)
+	} else {
+		err = r.loadJSONSnapshot(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	for name := range r.versionIndex {
+		sortVersions(r.versionIndex[name])
+	}
+
+	// Load override templates on top of the snapshot
+	if r.overrideDir != "" {
+		if err := r.loadOverrides(); err != nil {
+			// Non-fatal: overrides are optional
+		}
+	}
+
+	return r, nil
+}
+
+// loadJSONSnapshot loads templates from a JSON snapshot.
+func (r *Registry) loadJSONSnapshot(data []byte) error {
+	var doc snapshotDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	for _, t := range doc.Templates {
+		if fp := computeFingerprint(t.Content); fp != t.Fingerprint {
+			return fmt.Errorf("template %s@%s: fingerprint mismatch", t.Name, t.Version)
+		}
+		r.addSnapshot(t.Name, t.Version, t.Content)
+	}
+	return nil
+}
+
+// loadTarSnapshot loads templates from a tar.gz snapshot.
+func (r *Registry) loadTarSnapshot(data []byte) error {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		matches := versionPattern.FindStringSubmatch(path.Base(hdr.Name))
+		if matches == nil {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		r.addSnapshot(matches[1], matches[2], content)
+	}
+}
+
+// addSnapshot adds a template loaded from a snapshot.
+func (r *Registry) addSnapshot(name, version string, content []byte) {
+	key := fmt.Sprintf("%s@%s", name, version)
+	if _, exists := r.templates[key]; !exists {
+		r.versionIndex[name] = append(r.versionIndex[name], version)
+	}
+	r.templates[key] = &Template{
+		Name:        name,
+		Version:     version,
+		Content:     string(content),
+		Fingerprint: computeFingerprint(content),
+		Source:      "embedded",
+		LoadedAt:    time.Now(),
+	}
+}
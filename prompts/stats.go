@@ -0,0 +1,137 @@
+package prompts
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// renderWindowSize is the number of recent render durations kept per template.
+const renderWindowSize = 256
+
+// minSlowRenderSamples is the number of renders needed before a template can
+// be reported as consistently slow.
+const minSlowRenderSamples = 10
+
+// defaultSlowRenderThreshold is the median render time above which Validate
+// warns about a template.
+const defaultSlowRenderThreshold = 100 * time.Millisecond
+
+// RenderStats summarizes recent render performance for one template name.
+type RenderStats struct {
+	// Renders is the total number of successful renders
+	Renders int
+	// P50RenderMs, P95RenderMs and MaxRenderMs cover the most recent renders
+	P50RenderMs float64
+	P95RenderMs float64
+	MaxRenderMs float64
+	// RenderErrors counts renders that failed to parse or execute
+	RenderErrors int
+}
+
+// renderRecorder stores recent render durations in a lock-free ring buffer.
+type renderRecorder struct {
+	durations [renderWindowSize]atomic.Int64
+	renders   atomic.Int64
+	errors    atomic.Int64
+}
+
+// record adds a successful render duration.
+func (rr *renderRecorder) record(d time.Duration) {
+	i := rr.renders.Add(1) - 1
+	rr.durations[i%renderWindowSize].Store(int64(d))
+}
+
+// stats computes percentiles over the recorded window.
+func (rr *renderRecorder) stats() RenderStats {
+	renders := rr.renders.Load()
+	s := RenderStats{
+		Renders:      int(renders),
+		RenderErrors: int(rr.errors.Load()),
+	}
+
+	n := int(min(renders, renderWindowSize))
+	if n == 0 {
+		return s
+	}
+	window := make([]int64, n)
+	for i := range window {
+		window[i] = rr.durations[i].Load()
+	}
+	sort.Slice(window, func(i, j int) bool { return window[i] < window[j] })
+
+	s.P50RenderMs = durationMs(window[percentileIndex(n, 50)])
+	s.P95RenderMs = durationMs(window[percentileIndex(n, 95)])
+	s.MaxRenderMs = durationMs(window[n-1])
+	return s
+}
+
+// percentileIndex returns the index of the p-th percentile in n sorted values.
+func percentileIndex(n, p int) int {
+	i := (n*p+99)/100 - 1
+	return max(i, 0)
+}
+
+// durationMs converts nanoseconds to fractional milliseconds.
+func durationMs(ns int64) float64 {
+	return float64(ns) / float64(time.Millisecond)
+}
+
+// WithSlowRenderThreshold sets the median render time above which
+// ValidateWithWarnings reports a template as slow (default 100ms).
+func WithSlowRenderThreshold(d time.Duration) Option {
+	return func(r *Registry) {
+		r.slowRenderThreshold = d
+	}
+}
+
+// recorder returns the render recorder for a template name, creating it if needed.
+func (r *Registry) recorder(name string) *renderRecorder {
+	if rr, ok := r.renderStats.Load(name); ok {
+		return rr.(*renderRecorder)
+	}
+	rr, _ := r.renderStats.LoadOrStore(name, &renderRecorder{})
+	return rr.(*renderRecorder)
+}
+
+// RenderStats returns render performance for each template name that has
+// been rendered since the registry was created or ResetStats was called.
+func (r *Registry) RenderStats() map[string]RenderStats {
+	stats := make(map[string]RenderStats)
+	r.renderStats.Range(func(name, rr any) bool {
+		stats[name.(string)] = rr.(*renderRecorder).stats()
+		return true
+	})
+	return stats
+}
+
+// ResetStats discards all recorded render timings and error counts.
+func (r *Registry) ResetStats() {
+	r.renderStats.Clear()
+}
+
+// ValidateWithWarnings checks that a template parses, like Validate, and
+// also returns warnings for templates whose median render time over at
+// least 10 recent renders exceeds the slow render threshold.
+func (r *Registry) ValidateWithWarnings(name, version string) ([]string, error) {
+	tmpl, err := r.Get(name, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Validate(name, version); err != nil {
+		return nil, err
+	}
+
+	rr, ok := r.renderStats.Load(tmpl.Name)
+	if !ok {
+		return nil, nil
+	}
+	stats := rr.(*renderRecorder).stats()
+	threshold := durationMs(int64(r.slowRenderThreshold))
+	if stats.Renders < minSlowRenderSamples || stats.P50RenderMs <= threshold {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf("template %q renders slowly: p50 %.1fms, p95 %.1fms (threshold %.1fms)",
+		tmpl.Name, stats.P50RenderMs, stats.P95RenderMs, threshold)}, nil
+}
@@ -14,6 +14,20 @@ import (
 // GenerateText implements the core.Provider interface for text generation.
 // It supports multi-step tool execution when tools are provided.
 func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.TextResult, error) {
+		return p.generateText(ctx, req)
+	})
+}
+
+// generateText implements GenerateText within the request's timeouts.
+func (p *Provider) generateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	model := p.getModel(req)
 
 	// Use comprehensive GenAI observability wrapper
@@ -29,6 +43,9 @@ func (p *Provider) executeGenerateText(ctx context.Context, req core.Request) (*
 		return p.generateWithTools(ctx, req)
 	}
 
+	// Describe the response schema and prefill the reply
+	req, prefill := applyResponseSchema(req)
+
 	// Simple single-shot generation
 	apiReq, err := p.convertRequest(req)
 	if err != nil {
@@ -52,19 +69,17 @@ func (p *Provider) executeGenerateText(ctx context.Context, req core.Request) (*
 
 	// Convert to core.TextResult
 	result := &core.TextResult{
-		Usage: core.Usage{
-			InputTokens:  apiResp.Usage.InputTokens,
-			OutputTokens: apiResp.Usage.OutputTokens,
-			TotalTokens:  apiResp.Usage.InputTokens + apiResp.Usage.OutputTokens,
-		},
-		Raw: apiResp,
+		Usage:          apiResp.Usage.toCore(),
+		Raw:            apiResp,
+		ThinkingBlocks: thinkingBlocks(apiResp.Content),
+		ModelUsed:      apiResp.Model,
 	}
 
 	// Extract text and tool calls from content blocks
 	var textParts []string
 	var toolCalls []core.ToolCall
 
-	for _, block := range apiResp.Content {
+	for i, block := range apiResp.Content {
 		switch block.Type {
 		case "text":
 			textParts = append(textParts, block.Text)
@@ -79,17 +94,21 @@ func (p *Provider) executeGenerateText(ctx context.Context, req core.Request) (*
 				ID:    block.ID,
 				Name:  block.Name,
 				Input: json.RawMessage(inputJSON),
+				// Matches the index of the block's streaming events
+				ProviderMeta: map[string]any{"anthropic.content_index": i},
 			})
 		}
 	}
 
 	// Combine text parts
+	result.Text = prefill
 	for i, part := range textParts {
 		if i > 0 {
 			result.Text += "\n"
 		}
 		result.Text += part
 	}
+	result.Annotations = annotations(apiResp.Content, prefill)
 
 	// If there were tool calls, create a step
 	if len(toolCalls) > 0 {
@@ -98,6 +117,7 @@ func (p *Provider) executeGenerateText(ctx context.Context, req core.Request) (*
 			ToolCalls:  toolCalls,
 			StepNumber: 0,
 			Timestamp:  time.Now(),
+			Usage:      result.Usage,
 		}
 		result.Steps = append(result.Steps, step)
 	}
@@ -107,26 +127,46 @@ func (p *Provider) executeGenerateText(ctx context.Context, req core.Request) (*
 
 // generateWithTools handles multi-step execution with tools.
 func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	// Tools are offered, so the response schema is only described
+	req, _ = applyResponseSchema(req)
+
 	messages := make([]core.Message, len(req.Messages))
 	copy(messages, req.Messages)
 	
+	// turns holds the tool use and tool result turns in Anthropic format,
+	// sent after req.Messages so tool results keep their tool_use_id and
+	// is_error flag
+	var turns []message
 	var steps []core.Step
 	var totalUsage core.Usage
+	var reasoning []core.ThinkingBlock
+	var modelUsed string
 	stepCount := 0
 	maxSteps := 10 // Safety limit
 
 	for stepCount < maxSteps {
+		// Forcing a tool call on every step would never let the model answer
+		toolChoice := req.ToolChoice
+		if stepCount > 0 && (toolChoice == core.ToolRequired || toolChoice == core.ToolSpecific) {
+			toolChoice = core.ToolAuto
+		}
+
 		// Convert current conversation to API request
 		apiReq, err := p.convertRequest(core.Request{
-			Model:       req.Model,
-			Messages:    messages,
-			Temperature: req.Temperature,
-			MaxTokens:   req.MaxTokens,
-			Tools:       req.Tools,
+			Model:            req.Model,
+			Messages:         req.Messages,
+			Temperature:      req.Temperature,
+			MaxTokens:        core.OutputTokenLimit(req),
+			Tools:            req.Tools,
+			ToolChoice:       toolChoice,
+			SpecificTool:     req.SpecificTool,
+			ExtendedThinking: req.ExtendedThinking,
+			ProviderOptions:  req.ProviderOptions,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("converting request for step %d: %w", stepCount, err)
 		}
+		apiReq.Messages = append(apiReq.Messages, turns...)
 
 		// Make API request
 		resp, err := p.doRequest(ctx, "POST", "/v1/messages", apiReq)
@@ -145,15 +185,16 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 		}
 
 		// Update usage
-		totalUsage.InputTokens += apiResp.Usage.InputTokens
-		totalUsage.OutputTokens += apiResp.Usage.OutputTokens
-		totalUsage.TotalTokens = totalUsage.InputTokens + totalUsage.OutputTokens
+		stepUsage := apiResp.Usage.toCore()
+		totalUsage.Add(stepUsage)
+		modelUsed = apiResp.Model
+		reasoning = append(reasoning, thinkingBlocks(apiResp.Content)...)
 
 		// Process response content
 		var textParts []string
 		var toolCalls []core.ToolCall
 
-		for _, block := range apiResp.Content {
+		for i, block := range apiResp.Content {
 			switch block.Type {
 			case "text":
 				textParts = append(textParts, block.Text)
@@ -168,6 +209,8 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 					ID:    block.ID,
 					Name:  block.Name,
 					Input: json.RawMessage(inputJSON),
+					// Matches the index of the block's streaming events
+					ProviderMeta: map[string]any{"anthropic.content_index": i},
 				})
 			}
 		}
@@ -187,12 +230,19 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 			ToolCalls:  toolCalls,
 			StepNumber: stepCount,
 			Timestamp:  time.Now(),
+			Usage:      stepUsage,
 		}
 
 		// Add assistant message to conversation
 		if stepText != "" || len(toolCalls) > 0 {
-			// Build content blocks for the assistant message
+			// Build content blocks for the assistant message, keeping
+			// thinking blocks as the API requires when tools are used
 			var assistantContent []contentBlock
+			for _, block := range apiResp.Content {
+				if block.Type == "thinking" || block.Type == "redacted_thinking" {
+					assistantContent = append(assistantContent, block)
+				}
+			}
 			
 			if stepText != "" {
 				assistantContent = append(assistantContent, NewTextContent(stepText))
@@ -205,6 +255,7 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 				}
 				assistantContent = append(assistantContent, NewToolUseContent(tc.ID, tc.Name, input))
 			}
+			turns = append(turns, message{Role: "assistant", Content: assistantContent})
 
 			assistantMessage := core.Message{
 				Role: core.Assistant,
@@ -225,18 +276,13 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 			// In Anthropic format, we add a user message with tool result content blocks
 			var resultContent []contentBlock
 			for _, result := range toolResults {
-				var content interface{}
-				if result.Error != "" {
-					content = result.Error
-				} else {
-					content = result.Result
-				}
-				
-				resultContent = append(resultContent, NewToolResultContent(result.ID, content, result.Error != ""))
+				resultContent = append(resultContent, toolResultContent(result.ToolResult()))
 			}
 
 			// Convert to a user message (Anthropic expects tool results in user messages)
 			if len(resultContent) > 0 {
+				turns = append(turns, message{Role: "user", Content: resultContent})
+
 				toolResultMessage := core.Message{
 					Role:  core.User,
 					Parts: []core.Part{core.Text{Text: p.formatToolResults(toolResults)}},
@@ -266,9 +312,11 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 	}
 
 	return &core.TextResult{
-		Text:  finalText,
-		Steps: steps,
-		Usage: totalUsage,
+		Text:           finalText,
+		Steps:          steps,
+		Usage:          totalUsage,
+		ThinkingBlocks: reasoning,
+		ModelUsed:      modelUsed,
 	}, nil
 }
 
@@ -276,6 +324,11 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 func (p *Provider) executeTools(ctx context.Context, tools []core.ToolHandle, calls []core.ToolCall, messages []core.Message) ([]core.ToolExecution, error) {
 	results := make([]core.ToolExecution, len(calls))
 	
+	// Coalesce repeated calls to batch-capable tools into one execution each
+	batched := core.ExecuteBatchedCalls(ctx, tools, calls, map[string]interface{}{
+		"messages": messages,
+	})
+	
 	// Execute tools sequentially for now (can be parallelized)
 	for i, call := range calls {
 		tool := p.findTool(tools, call.Name)
@@ -288,11 +341,17 @@ func (p *Provider) executeTools(ctx context.Context, tools []core.ToolHandle, ca
 			continue
 		}
 
-		// Execute tool
-		result, err := tool.Exec(ctx, call.Input, map[string]interface{}{
-			"messages": messages,
-			"call_id":  call.ID,
-		})
+		// Execute tool, using the batch result if the call was coalesced
+		var result any
+		var err error
+		if b, ok := batched[i]; ok {
+			result, err = b.Result, b.Err
+		} else {
+			result, err = tool.Exec(ctx, call.Input, map[string]interface{}{
+				"messages": messages,
+				"call_id":  call.ID,
+			})
+		}
 		
 		if err != nil {
 			results[i] = core.ToolExecution{
@@ -322,6 +381,18 @@ func (p *Provider) findTool(tools []core.ToolHandle, name string) core.ToolHandl
 	return nil
 }
 
+// toolResultContent converts a tool result to a tool_result block. Errors
+// are sent as text with is_error set; string results are sent unquoted.
+func toolResultContent(result core.ToolResult) contentBlock {
+	if result.IsError {
+		return NewToolResultContent(result.CallID, result.Error, true)
+	}
+	if text, ok := result.Result.(string); ok {
+		return NewToolResultContent(result.CallID, text, false)
+	}
+	return NewToolResultContent(result.CallID, result.Content(), false)
+}
+
 // formatToolResults formats tool execution results for inclusion in messages.
 func (p *Provider) formatToolResults(results []core.ToolExecution) string {
 	var parts []string
@@ -357,7 +428,29 @@ func joinParts(parts []string, sep string) string {
 }
 
 // GenerateObject generates a structured object conforming to the provided schema.
+// Outputs that fail JSON validation are retried up to req.MaxStructuredRetries times.
 func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.ObjectResult[any], error) {
+		return p.generateObjectWithRetry(ctx, req, schema)
+	})
+}
+
+// generateObjectWithRetry implements GenerateObject within the request's timeouts.
+func (p *Provider) generateObjectWithRetry(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.GenerateObjectWithRetry(ctx, req, func(ctx context.Context, req core.Request) (*core.ObjectResult[any], error) {
+		return p.generateObject(ctx, req, schema)
+	})
+}
+
+// generateObject performs a single structured output attempt.
+func (p *Provider) generateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
 	model := p.getModel(req)
 
 	// Convert ObjectResult to TextResult for observability compatibility
@@ -398,14 +491,15 @@ func (p *Provider) executeGenerateObject(ctx context.Context, req core.Request,
 	// For Anthropic, we need to include instructions in the system prompt or user message
 	// to produce JSON output, as they don't have a dedicated structured output mode like OpenAI
 	
-	// Convert schema to a description
-	schemaJSON, err := json.Marshal(schema)
+	// Convert schema to a description, applying any ResponseSchema override
+	schemaJSON, err := core.MergeResponseSchema(schema, req.ResponseSchema)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling schema: %w", err)
+		return nil, err
 	}
 
 	// Add JSON formatting instructions to the request
 	modifiedReq := req
+	modifiedReq.ResponseSchema = nil
 	
 	// Add instruction to produce JSON output
 	jsonInstructions := fmt.Sprintf(`Please respond with a valid JSON object that conforms to this schema:
@@ -462,7 +556,7 @@ Respond with only the JSON object, no additional text.`, string(schemaJSON))
 	// Parse the JSON response
 	var value any
 	if err := json.Unmarshal([]byte(textResult.Text), &value); err != nil {
-		return nil, fmt.Errorf("parsing JSON response: %w", err)
+		return nil, core.NewValidationError(textResult.Text, err)
 	}
 
 	// TODO: Validate against the provided schema
@@ -0,0 +1,150 @@
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/recera/gai/core"
+)
+
+// Named model constants for the Anthropic Messages API.
+const (
+	// Claude 4 family
+	ClaudeOpus41  = "claude-opus-4-1-20250805"
+	ClaudeOpus4   = "claude-opus-4-20250514"
+	ClaudeSonnet4 = "claude-sonnet-4-20250514"
+
+	// Claude 3.7 and 3.5 family
+	Claude37Sonnet = "claude-3-7-sonnet-20250219"
+	Claude35Sonnet = "claude-3-5-sonnet-20241022"
+	Claude35Haiku  = "claude-3-5-haiku-20241022"
+
+	// Claude 3 family
+	Claude3Opus   = "claude-3-opus-20240229"
+	Claude3Sonnet = "claude-3-sonnet-20240229"
+	Claude3Haiku  = "claude-3-haiku-20240307"
+)
+
+// knownModels is the set of models accepted by ValidateModel.
+var knownModels = map[string]struct{}{
+	ClaudeOpus41:   {},
+	ClaudeOpus4:    {},
+	ClaudeSonnet4:  {},
+	Claude37Sonnet: {},
+	Claude35Sonnet: {},
+	Claude35Haiku:  {},
+	Claude3Opus:    {},
+	Claude3Sonnet:  {},
+	Claude3Haiku:   {},
+}
+
+// WithAllowUnknownModels permits model names outside the built-in constant list.
+// Enable this for new or preview models that have not yet been added.
+func WithAllowUnknownModels(allow bool) Option {
+	return func(p *Provider) {
+		p.allowUnknownModels = allow
+	}
+}
+
+// WithModelSelector replaces DefaultModelSelector as the way models are
+// chosen for requests with Model set to core.AutoModel or AutoSelectModel set.
+// A nil selector restores the default.
+func WithModelSelector(selector core.ModelSelector) Option {
+	return func(p *Provider) {
+		if selector == nil {
+			selector = DefaultModelSelector
+		}
+		p.modelSelector = selector
+	}
+}
+
+// DefaultModelSelector chooses Claude Sonnet 4 for requests with tools or
+// inputs above core.LongContextThreshold, and Claude 3 Haiku otherwise. Every
+// Claude model accepts images, so vision does not change the choice.
+func DefaultModelSelector(req core.Request, needs core.ModelNeeds) string {
+	if needs.Tools || needs.LongContext {
+		return ClaudeSonnet4
+	}
+	return Claude3Haiku
+}
+
+// ValidateModel implements core.ModelValidator.
+// It returns an AIError with ErrorUnknownModel for names not in the known model list,
+// unless unknown models were allowed with WithAllowUnknownModels.
+func (p *Provider) ValidateModel(model string) error {
+	if model == "" {
+		return core.NewError(core.ErrorUnknownModel, "model name is empty",
+			core.WithProvider("anthropic"))
+	}
+	if _, ok := knownModels[model]; ok || p.allowUnknownModels {
+		return nil
+	}
+	return core.NewError(core.ErrorUnknownModel,
+		fmt.Sprintf("unknown model %q (use WithAllowUnknownModels to permit it)", model),
+		core.WithProvider("anthropic"),
+		core.WithModel(model))
+}
+
+// claudeContextWindow is the context size of current Claude models.
+const claudeContextWindow = 200000
+
+// Models implements core.ModelLister using the /v1/models endpoint. All
+// Claude models accept tools and images and have a 200K token context.
+func (p *Provider) Models(ctx context.Context) ([]core.ModelInfo, error) {
+	var models []core.ModelInfo
+	afterID := ""
+	for {
+		path := "/v1/models?limit=1000"
+		if afterID != "" {
+			path += "&after_id=" + url.QueryEscape(afterID)
+		}
+
+		page, err := p.modelsPage(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range page.Data {
+			models = append(models, core.ModelInfo{
+				ID:            m.ID,
+				ContextWindow: claudeContextWindow,
+				Capabilities:  []core.ModelCapability{core.CapabilityToolCalling, core.CapabilityVision},
+			})
+		}
+
+		if !page.HasMore || page.LastID == "" {
+			return models, nil
+		}
+		afterID = page.LastID
+	}
+}
+
+// modelsPageResponse is one page of the /v1/models response.
+type modelsPageResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+	HasMore bool   `json:"has_more"`
+	LastID  string `json:"last_id"`
+}
+
+// modelsPage fetches one page of the model list.
+func (p *Provider) modelsPage(ctx context.Context, path string) (*modelsPageResponse, error) {
+	resp, err := p.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, p.parseError(resp)
+	}
+
+	var page modelsPageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("decoding models response: %w", err)
+	}
+	return &page, nil
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,6 +33,10 @@ type Provider struct {
 	retryDelay  time.Duration
 	version     string
 	collector   core.MetricsCollector
+	// allowUnknownModels skips known-model validation for Request.Model overrides
+	allowUnknownModels bool
+	// modelSelector chooses the model for requests with Model "auto"
+	modelSelector core.ModelSelector
 	mu          sync.RWMutex
 }
 
@@ -102,6 +107,8 @@ func New(opts ...Option) *Provider {
 		maxRetries: 3,
 		retryDelay: 100 * time.Millisecond,
 		version:    defaultVersion,
+
+		modelSelector: DefaultModelSelector,
 	}
 
 	for _, opt := range opts {
@@ -122,6 +129,22 @@ func New(opts ...Option) *Provider {
 	return p
 }
 
+// requestSetup returns how the provider prepares requests (see
+// core.PrepareRequest).
+func (p *Provider) requestSetup() core.RequestSetup {
+	return core.RequestSetup{
+		Provider:  "anthropic",
+		Selector:  p.modelSelector,
+		Validator: p,
+		Client:    p.client,
+	}
+}
+
+// RequestModel implements core.ModelResolver.
+func (p *Provider) RequestModel(ctx context.Context, req core.Request) string {
+	return p.getModel(core.PrepareModel(ctx, req, p.requestSetup()))
+}
+
 // getModel returns the model to use for the request.
 func (p *Provider) getModel(req core.Request) string {
 	if req.Model != "" {
@@ -134,7 +157,7 @@ func (p *Provider) getModel(req core.Request) string {
 func (p *Provider) convertRequest(req core.Request) (*messagesRequest, error) {
 	ar := &messagesRequest{
 		Model:     p.getModel(req),
-		MaxTokens: req.MaxTokens,
+		MaxTokens: core.OutputTokenLimit(req),
 	}
 
 	// Set default max tokens if not specified
@@ -160,16 +183,103 @@ func (p *Provider) convertRequest(req core.Request) (*messagesRequest, error) {
 	// Convert tools if present
 	if len(req.Tools) > 0 {
 		ar.Tools = p.convertTools(req.Tools)
+		ar.ToolChoice = convertToolChoice(req.ToolChoice, req.SpecificTool)
 	}
 
 	// Handle provider-specific options
-	if opts, ok := req.ProviderOptions["anthropic"].(map[string]interface{}); ok {
+	opts, _ := req.ProviderOptions["anthropic"].(map[string]interface{})
+	if opts != nil {
 		p.applyProviderOptions(ar, opts)
 	}
 
+	if req.ExtendedThinking {
+		applyThinking(ar, opts)
+	}
+
 	return ar, nil
 }
 
+// defaultThinkingBudget is the extended thinking budget in tokens when the
+// "thinking_budget_tokens" provider option is not set (the API minimum).
+const defaultThinkingBudget = 1024
+
+// applyThinking enables extended thinking. The API requires max_tokens to
+// exceed the budget and does not accept a custom temperature, so max_tokens
+// is raised when needed and the temperature is dropped.
+func applyThinking(ar *messagesRequest, opts map[string]interface{}) {
+	budget := defaultThinkingBudget
+	if v, ok := opts["thinking_budget_tokens"].(int); ok && v > 0 {
+		budget = v
+	}
+	ar.Thinking = &thinking{Type: "enabled", BudgetTokens: budget}
+	if ar.MaxTokens <= budget {
+		ar.MaxTokens = budget + 4096
+	}
+	ar.Temperature = nil
+}
+
+// thinkingBlocks returns the thinking blocks in content.
+func thinkingBlocks(content []contentBlock) []core.ThinkingBlock {
+	var blocks []core.ThinkingBlock
+	for _, block := range content {
+		if block.Type == "thinking" {
+			blocks = append(blocks, core.ThinkingBlock{Content: block.Thinking})
+		}
+	}
+	return blocks
+}
+
+// responseSchemaPrefill starts the assistant's reply so that it continues a
+// JSON object.
+const responseSchemaPrefill = "{"
+
+// applyResponseSchema adds a system instruction describing req.ResponseSchema
+// and, when no tools are offered, prefills the assistant's reply with the
+// start of a JSON object. It returns the prefill, which the caller must put
+// back in front of the response text.
+func applyResponseSchema(req core.Request) (core.Request, string) {
+	if len(req.ResponseSchema) == 0 {
+		return req, ""
+	}
+
+	instruction := core.Message{
+		Role: core.System,
+		Parts: []core.Part{core.Text{Text: fmt.Sprintf(
+			"Respond only with a JSON object that conforms to this JSON schema, with no additional text:\n%s", req.ResponseSchema)}},
+	}
+	messages := make([]core.Message, 0, len(req.Messages)+2)
+	messages = append(messages, instruction)
+	messages = append(messages, req.Messages...)
+
+	// A prefilled reply can't start with a tool call
+	prefill := ""
+	if len(req.Tools) == 0 {
+		messages = append(messages, core.Message{
+			Role:  core.Assistant,
+			Parts: []core.Part{core.Text{Text: responseSchemaPrefill}},
+		})
+		prefill = responseSchemaPrefill
+	}
+
+	req.Messages = messages
+	return req, prefill
+}
+
+// convertToolChoice converts core tool choice to Anthropic format. The
+// default of auto is left unset.
+func convertToolChoice(choice core.ToolChoice, specificTool string) *toolChoice {
+	switch choice {
+	case core.ToolNone:
+		return &toolChoice{Type: "none"}
+	case core.ToolRequired:
+		return &toolChoice{Type: "any"}
+	case core.ToolSpecific:
+		return &toolChoice{Type: "tool", Name: specificTool}
+	default:
+		return nil
+	}
+}
+
 // convertMessages converts core messages to Anthropic format.
 // Anthropic requires system messages to be in a separate field, not in the messages array.
 func (p *Provider) convertMessages(messages []core.Message) ([]message, string, error) {
@@ -196,6 +306,13 @@ func (p *Provider) convertMessages(messages []core.Message) ([]message, string,
 				return nil, "", err
 			}
 
+			if msg.Role == core.Assistant && len(msg.ToolCalls) > 0 {
+				content, err = toolUseContent(content, msg.ToolCalls)
+				if err != nil {
+					return nil, "", err
+				}
+			}
+
 			am := message{
 				Role:    string(msg.Role),
 				Content: content,
@@ -203,6 +320,18 @@ func (p *Provider) convertMessages(messages []core.Message) ([]message, string,
 
 			result = append(result, am)
 		case core.Tool:
+			if msg.ToolCallID != "" {
+				// Results for one assistant turn share a user message, each
+				// linked to its tool_use block by ID
+				block := NewToolResultContent(msg.ToolCallID, messageText(msg), msg.IsError)
+				if n := len(result); n > 0 && isToolResultMessage(result[n-1]) {
+					result[n-1].Content = append(result[n-1].Content.([]contentBlock), block)
+				} else {
+					result = append(result, message{Role: "user", Content: []contentBlock{block}})
+				}
+				continue
+			}
+
 			// Tool messages are handled differently in Anthropic
 			// They become tool_result blocks in the previous assistant message
 			// For now, we'll convert them to user messages with tool result content
@@ -223,6 +352,61 @@ func (p *Provider) convertMessages(messages []core.Message) ([]message, string,
 	return result, systemPrompt, nil
 }
 
+// toolUseContent appends a tool_use block for each call to the converted
+// content of an assistant message. Empty text is dropped, since Anthropic
+// rejects empty text blocks.
+func toolUseContent(content interface{}, calls []core.ToolCall) ([]contentBlock, error) {
+	var blocks []contentBlock
+	switch c := content.(type) {
+	case string:
+		if c != "" {
+			blocks = append(blocks, NewTextContent(c))
+		}
+	case []contentBlock:
+		for _, block := range c {
+			if block.Type != "text" || block.Text != "" {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+
+	for _, call := range calls {
+		input := map[string]interface{}{}
+		if len(call.Input) > 0 {
+			if err := json.Unmarshal(call.Input, &input); err != nil {
+				return nil, fmt.Errorf("decoding input of tool call %s: %w", call.ID, err)
+			}
+		}
+		blocks = append(blocks, NewToolUseContent(call.ID, call.Name, input))
+	}
+	return blocks, nil
+}
+
+// messageText returns the text parts of msg joined together.
+func messageText(msg core.Message) string {
+	var text strings.Builder
+	for _, part := range msg.Parts {
+		if t, ok := part.(core.Text); ok {
+			text.WriteString(t.Text)
+		}
+	}
+	return text.String()
+}
+
+// isToolResultMessage reports whether msg holds only tool_result blocks.
+func isToolResultMessage(msg message) bool {
+	blocks, ok := msg.Content.([]contentBlock)
+	if !ok || msg.Role != "user" || len(blocks) == 0 {
+		return false
+	}
+	for _, block := range blocks {
+		if block.Type != "tool_result" {
+			return false
+		}
+	}
+	return true
+}
+
 // convertParts converts message parts to Anthropic content format.
 func (p *Provider) convertParts(parts []core.Part) (interface{}, error) {
 	if len(parts) == 0 {
@@ -246,14 +430,15 @@ func (p *Provider) convertParts(parts []core.Part) (interface{}, error) {
 				Text: p.Text,
 			})
 		case core.ImageURL:
-			// Anthropic uses a different format for images
+			// Inline images (such as those from Request.AutoFetchImages) are
+			// sent as base64; other URLs are fetched by Anthropic
+			source := &imageSource{Type: "url", URL: p.URL}
+			if mime, data, ok := core.ParseDataURL(p.URL); ok {
+				source = &imageSource{Type: "base64", MediaType: mime, Data: data}
+			}
 			content = append(content, contentBlock{
-				Type: "image",
-				Source: &imageSource{
-					Type:      "base64",
-					MediaType: "image/jpeg", // Default, should ideally parse from URL
-					Data:      p.URL,        // This would need to be base64 encoded data
-				},
+				Type:   "image",
+				Source: source,
 			})
 		case core.Audio, core.Video, core.File:
 			// Anthropic doesn't support these content types in messages
@@ -301,13 +486,58 @@ func (p *Provider) applyProviderOptions(req *messagesRequest, opts map[string]in
 	if v, ok := opts["stop_sequences"].([]string); ok {
 		req.StopSequences = v
 	}
+	if v, ok := opts["web_search_max_uses"].(int); ok && v > 0 {
+		req.Tools = append(req.Tools, tool{Type: webSearchToolType, Name: "web_search", MaxUses: v})
+	}
+}
+
+// webSearchToolType is the version of Anthropic's server-side web search tool.
+const webSearchToolType = "web_search_20250305"
+
+// annotations returns the web search citations of the text blocks in
+// content, with offsets into the text produced by joining the blocks with
+// newlines after prefix.
+func annotations(content []contentBlock, prefix string) []core.Annotation {
+	var result []core.Annotation
+	offset := len(prefix)
+	first := true
+	for _, block := range content {
+		if block.Type != "text" {
+			continue
+		}
+		if !first {
+			offset++ // Newline separating text blocks
+		}
+		first = false
+
+		for _, c := range block.Citations {
+			if c.URL == "" {
+				continue
+			}
+			result = append(result, core.Annotation{
+				Type:       c.Type,
+				StartIndex: offset,
+				EndIndex:   offset + len(block.Text),
+				URL:        c.URL,
+				Title:      c.Title,
+			})
+		}
+		offset += len(block.Text)
+	}
+	return result
 }
 
 // doRequest performs an HTTP request with retry logic.
 func (p *Provider) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	var lastErr error
 
-	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+	maxRetries := p.maxRetries
+	policy, hasPolicy := core.RetryPolicyFromContext(ctx)
+	if hasPolicy {
+		maxRetries = policy.Retries(p.maxRetries)
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			// Exponential backoff with jitter
 			delay := p.retryDelay * time.Duration(1<<uint(attempt-1))
@@ -320,14 +550,28 @@ func (p *Provider) doRequest(ctx context.Context, method, path string, body inte
 
 		resp, err := p.doRequestOnce(ctx, method, path, body)
 		if err != nil {
+			if hasPolicy && !policy.ShouldRetry(err) {
+				return nil, err
+			}
 			lastErr = err
 			continue
 		}
 
+		// Per-request retry settings replace the status code checks below
+		if hasPolicy {
+			if attempt < maxRetries {
+				if retry, apiErr := policy.ShouldRetryResponse(resp, "anthropic"); retry {
+					lastErr = apiErr
+					continue
+				}
+			}
+			return resp, nil
+		}
+
 		// Check if we should retry based on status code
 		if p.shouldRetry(resp.StatusCode) {
 			// Only retry if we have attempts left
-			if attempt < p.maxRetries {
+			if attempt < maxRetries {
 				bodyBytes, _ := io.ReadAll(resp.Body)
 				resp.Body.Close()
 				lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, bodyBytes)
@@ -338,7 +582,7 @@ func (p *Provider) doRequest(ctx context.Context, method, path string, body inte
 		return resp, nil
 	}
 
-	return nil, fmt.Errorf("after %d retries: %w", p.maxRetries, lastErr)
+	return nil, fmt.Errorf("after %d retries: %w", maxRetries, lastErr)
 }
 
 // doRequestOnce performs a single HTTP request.
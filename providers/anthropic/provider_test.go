@@ -2,7 +2,9 @@ package anthropic
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -237,6 +239,38 @@ func TestConvertMessages(t *testing.T) {
 	}
 }
 
+func TestConvertMessagesToolCallID(t *testing.T) {
+	p := New()
+
+	// Results arrive in a different order than the calls were made
+	msgs, _, err := p.convertMessages([]core.Message{
+		{Role: core.User, Parts: []core.Part{core.Text{Text: "Compare the weather"}}},
+		{Role: core.Tool, Parts: []core.Part{core.Text{Text: `"Rainy"`}}, ToolCallID: "toolu_2"},
+		{Role: core.Tool, Parts: []core.Part{core.Text{Text: `"Sunny"`}}, ToolCallID: "toolu_1"},
+		{Role: core.Tool, Parts: []core.Part{core.Text{Text: "station offline"}}, ToolCallID: "toolu_3", IsError: true},
+	})
+	if err != nil {
+		t.Fatalf("convertMessages failed: %v", err)
+	}
+
+	if len(msgs) != 2 {
+		t.Fatalf("expected tool results in one message, got %d messages", len(msgs))
+	}
+	blocks, ok := msgs[1].Content.([]contentBlock)
+	if !ok || msgs[1].Role != "user" || len(blocks) != 3 {
+		t.Fatalf("unexpected tool result message: %+v", msgs[1])
+	}
+	want := map[string]string{"toolu_1": `"Sunny"`, "toolu_2": `"Rainy"`, "toolu_3": "station offline"}
+	for _, block := range blocks {
+		if block.Type != "tool_result" || want[block.ToolUseID] != block.Content {
+			t.Errorf("unexpected tool result block: %+v", block)
+		}
+		if block.IsError != (block.ToolUseID == "toolu_3") {
+			t.Errorf("block %s: IsError = %v", block.ToolUseID, block.IsError)
+		}
+	}
+}
+
 func TestConvertTools(t *testing.T) {
 	p := New()
 	
@@ -269,6 +303,28 @@ func TestConvertTools(t *testing.T) {
 	}
 }
 
+func TestConvertToolChoice(t *testing.T) {
+	tests := []struct {
+		name   string
+		choice core.ToolChoiceValue
+		want   *toolChoice
+	}{
+		{"auto", core.ToolChoiceValue{Choice: core.ToolAuto}, nil},
+		{"none", core.ToolChoiceValue{Choice: core.ToolNone}, &toolChoice{Type: "none"}},
+		{"required", core.ToolChoiceValue{Choice: core.ToolRequired}, &toolChoice{Type: "any"}},
+		{"force", core.ToolForce("test_tool"), &toolChoice{Type: "tool", Name: "test_tool"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertToolChoice(tt.choice.Choice, tt.choice.Tool)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("convertToolChoice = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGenerateTextBasic(t *testing.T) {
 	// Create a mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -408,6 +464,413 @@ func TestGenerateTextWithToolUse(t *testing.T) {
 	}
 }
 
+func TestGenerateTextToolError(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+
+		response := messagesResponse{
+			ID:         "msg_final",
+			Type:       "message",
+			Role:       "assistant",
+			Content:    []contentBlock{{Type: "text", Text: "The lookup failed."}},
+			StopReason: "end_turn",
+		}
+		if len(requests) == 1 {
+			response.Content = []contentBlock{{
+				Type:  "tool_use",
+				ID:    "tool_123",
+				Name:  "lookup",
+				Input: map[string]interface{}{"q": "x"},
+			}}
+			response.StopReason = "tool_use"
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	type lookupInput struct {
+		Q string `json:"q"`
+	}
+	lookup := tools.New[lookupInput, string]("lookup", "Look something up",
+		func(ctx context.Context, in lookupInput, meta tools.Meta) (string, error) {
+			return "", fmt.Errorf("service unavailable")
+		},
+	)
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	result, err := p.GenerateText(context.Background(), core.Request{
+		Messages: []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Look up x"}}}},
+		Tools:    []core.ToolHandle{tools.NewCoreAdapter(lookup)},
+		StopWhen: core.NoMoreTools(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Steps) != 2 || !result.Steps[0].ToolResults[0].ToolResult().IsError {
+		t.Fatalf("expected a failed tool result in the first of 2 steps, got %+v", result.Steps)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	// The second request replays the tool use and its failed result
+	messages := requests[1]["messages"].([]interface{})
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+	assistant := messages[1].(map[string]interface{})["content"].([]interface{})
+	if use := assistant[0].(map[string]interface{}); use["type"] != "tool_use" || use["id"] != "tool_123" {
+		t.Errorf("unexpected assistant content: %v", assistant)
+	}
+	content := messages[2].(map[string]interface{})["content"].([]interface{})
+	block := content[0].(map[string]interface{})
+	if block["type"] != "tool_result" || block["tool_use_id"] != "tool_123" || block["is_error"] != true {
+		t.Errorf("unexpected tool result block: %v", block)
+	}
+	if text, _ := block["content"].(string); !strings.Contains(text, "service unavailable") {
+		t.Errorf("tool result content = %v, want the tool error", block["content"])
+	}
+}
+
+func TestRunnerToolUse(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+
+		response := messagesResponse{
+			ID:         "msg_final",
+			Type:       "message",
+			Role:       "assistant",
+			Content:    []contentBlock{{Type: "text", Text: "Found it."}},
+			StopReason: "end_turn",
+		}
+		if len(requests) == 1 {
+			response.Content = []contentBlock{
+				{Type: "text", Text: "Let me look."},
+				{Type: "tool_use", ID: "tool_123", Name: "lookup", Input: map[string]interface{}{"q": "x"}},
+			}
+			response.StopReason = "tool_use"
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	type lookupInput struct {
+		Q string `json:"q"`
+	}
+	lookup := tools.New[lookupInput, string]("lookup", "Look something up",
+		func(ctx context.Context, in lookupInput, meta tools.Meta) (string, error) {
+			return "result for " + in.Q, nil
+		},
+	)
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	_, err := core.NewRunner(p).ExecuteRequest(context.Background(), core.Request{
+		Messages: []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Look up x"}}}},
+		Tools:    []core.ToolHandle{tools.NewCoreAdapter(lookup)},
+		StopWhen: core.NoMoreTools(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	// The runner's second step replays the tool_use its tool_result answers
+	messages := requests[1]["messages"].([]interface{})
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+	assistant := messages[1].(map[string]interface{})["content"].([]interface{})
+	if len(assistant) != 2 {
+		t.Fatalf("expected text and tool_use blocks, got %v", assistant)
+	}
+	use := assistant[1].(map[string]interface{})
+	if use["type"] != "tool_use" || use["id"] != "tool_123" || use["name"] != "lookup" {
+		t.Errorf("unexpected tool_use block: %v", use)
+	}
+	if input, _ := use["input"].(map[string]interface{}); input["q"] != "x" {
+		t.Errorf("tool_use input = %v, want q=x", use["input"])
+	}
+	content := messages[2].(map[string]interface{})["content"].([]interface{})
+	block := content[0].(map[string]interface{})
+	if block["type"] != "tool_result" || block["tool_use_id"] != "tool_123" {
+		t.Errorf("unexpected tool result block: %v", block)
+	}
+}
+
+func TestGenerateTextToolCallProviderMeta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(messagesResponse{
+			ID:   "msg_tools",
+			Type: "message",
+			Role: "assistant",
+			Content: []contentBlock{
+				{Type: "text", Text: "Checking both cities."},
+				{Type: "tool_use", ID: "toolu_1", Name: "get_weather", Input: map[string]interface{}{"city": "Paris"}},
+				{Type: "tool_use", ID: "toolu_2", Name: "get_weather", Input: map[string]interface{}{"city": "Rome"}},
+			},
+			StopReason: "tool_use",
+		})
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	result, err := p.GenerateText(context.Background(), core.Request{
+		Messages: []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Weather in Paris and Rome?"}}}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	if len(result.Steps) != 1 || len(result.Steps[0].ToolCalls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %+v", result.Steps)
+	}
+	for i, call := range result.Steps[0].ToolCalls {
+		if index, ok := core.ToolCallMeta(call, "anthropic.content_index"); !ok || index != i+1 {
+			t.Errorf("call %d content index = %v, %v; want %d", i, index, ok, i+1)
+		}
+	}
+}
+
+func TestAutoFetchImages(t *testing.T) {
+	// The image server listens on the loopback address
+	core.AllowPrivateImageHosts = true
+	defer func() { core.AllowPrivateImageHosts = false }()
+
+	images := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png-bytes"))
+	}))
+	defer images.Close()
+
+	var sources []*imageSource
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var sent messagesRequest
+		json.NewDecoder(r.Body).Decode(&sent)
+		sources = nil
+		for _, msg := range sent.Messages {
+			if blocks, ok := msg.Content.([]interface{}); ok {
+				for _, b := range blocks {
+					raw, _ := json.Marshal(b)
+					var block contentBlock
+					json.Unmarshal(raw, &block)
+					if block.Source != nil {
+						sources = append(sources, block.Source)
+					}
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(messagesResponse{
+			ID:         "msg_image",
+			Type:       "message",
+			Role:       "assistant",
+			Content:    []contentBlock{{Type: "text", Text: "A cat"}},
+			StopReason: "end_turn",
+		})
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL), WithHTTPClient(images.Client()))
+	req := core.Request{
+		Messages: []core.Message{{Role: core.User, Parts: []core.Part{
+			core.Text{Text: "What is this?"},
+			core.ImageURL{URL: images.URL + "/cat.png"},
+		}}},
+	}
+
+	// Without AutoFetchImages the URL is passed through for Anthropic to fetch
+	if _, err := p.GenerateText(context.Background(), req); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if len(sources) != 1 || sources[0].Type != "url" || sources[0].URL != images.URL+"/cat.png" {
+		t.Errorf("unexpected image source: %+v", sources)
+	}
+
+	req.AutoFetchImages = true
+	if _, err := p.GenerateText(context.Background(), req); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	want := base64.StdEncoding.EncodeToString([]byte("png-bytes"))
+	if len(sources) != 1 || sources[0].Type != "base64" || sources[0].MediaType != "image/png" || sources[0].Data != want {
+		t.Errorf("unexpected image source: %+v", sources)
+	}
+}
+
+func TestGenerateTextModelUsed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(messagesResponse{
+			ID:         "msg_model",
+			Type:       "message",
+			Role:       "assistant",
+			Model:      "claude-sonnet-4-20250514",
+			Content:    []contentBlock{{Type: "text", Text: "Hi"}},
+			StopReason: "end_turn",
+		})
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	result, err := p.GenerateText(context.Background(), core.Request{
+		Messages: []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if result.ModelUsed != "claude-sonnet-4-20250514" {
+		t.Errorf("ModelUsed = %q, want claude-sonnet-4-20250514", result.ModelUsed)
+	}
+}
+
+func TestMultipleCompletionsUnsupported(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	req := core.Request{
+		Messages: []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}}},
+		N:        2,
+	}
+
+	if _, err := p.GenerateText(context.Background(), req); !core.IsUnsupported(err) {
+		t.Errorf("GenerateText: expected unsupported error, got %v", err)
+	}
+	if _, err := p.StreamText(context.Background(), req); !core.IsUnsupported(err) {
+		t.Errorf("StreamText: expected unsupported error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no API calls, got %d", calls)
+	}
+}
+
+func TestConvertRequestMaxOutputTokens(t *testing.T) {
+	p := New(WithAPIKey("test-key"))
+	msgs := []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}}}
+	tests := []struct {
+		name string
+		req  core.Request
+		want int
+	}{
+		{"max output tokens", core.Request{Messages: msgs, MaxOutputTokens: 100}, 100},
+		{"deprecated alias", core.Request{Messages: msgs, MaxTokens: 200}, 200},
+		{"max output tokens wins", core.Request{Messages: msgs, MaxOutputTokens: 100, MaxTokens: 200}, 100},
+		{"default", core.Request{Messages: msgs}, 4096},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ar, err := p.convertRequest(tt.req)
+			if err != nil {
+				t.Fatalf("convertRequest failed: %v", err)
+			}
+			if ar.MaxTokens != tt.want {
+				t.Errorf("max_tokens = %d, want %d", ar.MaxTokens, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTextAutoModel(t *testing.T) {
+	var models []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var sent messagesRequest
+		json.NewDecoder(r.Body).Decode(&sent)
+		models = append(models, sent.Model)
+		json.NewEncoder(w).Encode(messagesResponse{
+			ID:         "msg_auto",
+			Type:       "message",
+			Role:       "assistant",
+			Content:    []contentBlock{{Type: "text", Text: "Done"}},
+			StopReason: "end_turn",
+		})
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	messages := []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}}}
+	weather := tools.New[struct{}, string]("get_weather", "Get current weather",
+		func(ctx context.Context, in struct{}, meta tools.Meta) (string, error) {
+			return "Sunny", nil
+		})
+
+	requests := []core.Request{
+		{Model: core.AutoModel, Messages: messages},
+		{AutoSelectModel: true, Messages: messages, Tools: []core.ToolHandle{tools.NewCoreAdapter(weather)}},
+	}
+	for _, req := range requests {
+		if _, err := p.GenerateText(context.Background(), req); err != nil {
+			t.Fatalf("GenerateText failed: %v", err)
+		}
+	}
+
+	want := []string{Claude3Haiku, ClaudeSonnet4}
+	if len(models) != len(want) || models[0] != want[0] || models[1] != want[1] {
+		t.Errorf("models = %v, want %v", models, want)
+	}
+}
+
+func TestGenerateTextWebSearchCitations(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		json.NewEncoder(w).Encode(messagesResponse{
+			ID:   "msg_search",
+			Type: "message",
+			Role: "assistant",
+			Content: []contentBlock{
+				{Type: "server_tool_use", ID: "srvtoolu_1", Name: "web_search", Input: map[string]interface{}{"query": "go 1.23"}},
+				{Type: "web_search_tool_result", ToolUseID: "srvtoolu_1", Content: []interface{}{}},
+				{Type: "text", Text: "According to the release notes,"},
+				{Type: "text", Text: "Go 1.23 added range-over-func iterators.", Citations: []citation{{
+					Type:      "web_search_result_location",
+					URL:       "https://go.dev/doc/go1.23",
+					Title:     "Go 1.23 Release Notes",
+					CitedText: "range-over-func iterators",
+				}}},
+			},
+			StopReason: "end_turn",
+		})
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	result, err := p.GenerateText(context.Background(), core.Request{
+		Messages:        []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "What's new in Go 1.23?"}}}},
+		ProviderOptions: map[string]any{"anthropic": map[string]interface{}{"web_search_max_uses": 3}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	sentTools, _ := sent["tools"].([]interface{})
+	if len(sentTools) != 1 || sentTools[0].(map[string]interface{})["type"] != webSearchToolType {
+		t.Errorf("expected web search tool in request, got %v", sent["tools"])
+	}
+
+	if len(result.Annotations) != 1 {
+		t.Fatalf("expected 1 annotation, got %+v", result.Annotations)
+	}
+	a := result.Annotations[0]
+	if a.URL != "https://go.dev/doc/go1.23" || a.Title != "Go 1.23 Release Notes" {
+		t.Errorf("unexpected annotation: %+v", a)
+	}
+	if cited := result.Text[a.StartIndex:a.EndIndex]; cited != "Go 1.23 added range-over-func iterators." {
+		t.Errorf("annotation spans %q", cited)
+	}
+	want := "According to the release notes,\nGo 1.23 added range-over-func iterators.[1]"
+	if got := core.AnnotatedText(result); got != want {
+		t.Errorf("AnnotatedText() = %q, want %q", got, want)
+	}
+}
+
 func TestStreamText(t *testing.T) {
 	// Create a mock streaming server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -505,6 +968,132 @@ func TestStreamText(t *testing.T) {
 	}
 }
 
+func TestGenerateTextResponseSchema(t *testing.T) {
+	var got messagesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(messagesResponse{
+			Type:    "message",
+			Role:    "assistant",
+			Content: []contentBlock{{Type: "text", Text: `"name": "Ada"}`}},
+		})
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	result, err := p.GenerateText(context.Background(), core.Request{
+		Messages:       []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Who wrote the first program?"}}}},
+		ResponseSchema: json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}}}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(got.System, `"properties":{"name"`) {
+		t.Errorf("system prompt does not describe the schema: %q", got.System)
+	}
+	last := got.Messages[len(got.Messages)-1]
+	if last.Role != "assistant" {
+		t.Fatalf("expected an assistant prefill, got role %q", last.Role)
+	}
+	if result.Text != `{"name": "Ada"}` {
+		t.Errorf("result.Text = %q, expected the prefill to be restored", result.Text)
+	}
+}
+
+func TestGenerateTextExtendedThinking(t *testing.T) {
+	var got messagesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(messagesResponse{
+			Type: "message",
+			Role: "assistant",
+			Content: []contentBlock{
+				{Type: "thinking", Thinking: "17 is only divisible by 1 and itself.", Signature: "sig"},
+				{Type: "text", Text: "Yes, 17 is prime."},
+			},
+		})
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	result, err := p.GenerateText(context.Background(), core.Request{
+		Messages:         []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Is 17 prime?"}}}},
+		Temperature:      0.5,
+		MaxTokens:        1000,
+		ExtendedThinking: true,
+		ProviderOptions:  map[string]any{"anthropic": map[string]interface{}{"thinking_budget_tokens": 2048}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Thinking == nil || got.Thinking.Type != "enabled" || got.Thinking.BudgetTokens != 2048 {
+		t.Errorf("unexpected thinking config: %+v", got.Thinking)
+	}
+	if got.MaxTokens <= 2048 || got.Temperature != nil {
+		t.Errorf("max_tokens must exceed the budget and temperature must be unset, got %d, %v", got.MaxTokens, got.Temperature)
+	}
+	if result.Text != "Yes, 17 is prime." {
+		t.Errorf("result.Text = %q, thinking must not be included", result.Text)
+	}
+	if len(result.ThinkingBlocks) != 1 || result.ThinkingBlocks[0].Content != "17 is only divisible by 1 and itself." {
+		t.Errorf("unexpected thinking blocks: %+v", result.ThinkingBlocks)
+	}
+}
+
+func TestStreamTextCacheUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+
+		events := []string{
+			`data: {"type": "message_start", "message": {"id": "msg_123", "type": "message", "role": "assistant", "content": [], "model": "claude-sonnet-4-20250514", "usage": {"input_tokens": 12, "output_tokens": 1, "cache_creation_input_tokens": 0, "cache_read_input_tokens": 2048}}}`,
+			`data: {"type": "content_block_start", "index": 0, "content_block": {"type": "text", "text": ""}}`,
+			`data: {"type": "content_block_delta", "index": 0, "delta": {"type": "text_delta", "text": "Hi"}}`,
+			`data: {"type": "content_block_stop", "index": 0}`,
+			`data: {"type": "message_delta", "delta": {"stop_reason": "end_turn"}, "usage": {"output_tokens": 7, "cache_creation_input_tokens": 512}}`,
+			`data: {"type": "message_stop"}`,
+		}
+		for _, event := range events {
+			w.Write([]byte(event + "\n\n"))
+			flusher.Flush()
+		}
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	stream, err := p.StreamText(context.Background(), core.Request{
+		Messages: []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}}},
+		Stream:   true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	var usage *core.Usage
+	for event := range stream.Events() {
+		if event.Type == core.EventFinish {
+			usage = event.Usage
+		}
+	}
+	if usage == nil {
+		t.Fatal("expected usage on finish event")
+	}
+
+	want := core.Usage{
+		InputTokens:         12,
+		OutputTokens:        7,
+		TotalTokens:         19,
+		CacheReadTokens:     2048,
+		CacheCreationTokens: 512,
+	}
+	if *usage != want {
+		t.Errorf("usage = %+v, want %+v", *usage, want)
+	}
+}
+
 func TestErrorMapping(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -632,6 +1221,42 @@ func TestErrorMapping(t *testing.T) {
 	}
 }
 
+func TestValidateModel(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	
+	if err := p.ValidateModel(ClaudeSonnet4); err != nil {
+		t.Errorf("ValidateModel(%q) unexpected error: %v", ClaudeSonnet4, err)
+	}
+	if err := p.ValidateModel("claude-unknown"); !core.IsUnknownModel(err) {
+		t.Errorf("ValidateModel(claude-unknown) = %v, expected unknown model error", err)
+	}
+	
+	_, err := p.GenerateText(context.Background(), core.Request{
+		Model: "claude-unknown",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}},
+		},
+	})
+	if !core.IsUnknownModel(err) {
+		t.Errorf("GenerateText() error = %v, expected unknown model error", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no HTTP calls for unknown model, got %d", calls)
+	}
+	
+	permissive := New(WithAPIKey("test-key"), WithAllowUnknownModels(true))
+	if err := permissive.ValidateModel("claude-preview"); err != nil {
+		t.Errorf("ValidateModel with unknown models allowed: unexpected error: %v", err)
+	}
+}
+
 func TestShouldRetry(t *testing.T) {
 	p := New()
 
@@ -659,4 +1284,34 @@ func TestShouldRetry(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestInspectRequest(t *testing.T) {
+	p := New(WithAPIKey("test-key"))
+	var provider core.Provider = p
+	inspector, ok := provider.(core.InspectableProvider)
+	if !ok {
+		t.Fatal("Provider does not implement core.InspectableProvider")
+	}
+
+	user := core.Message{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}}
+	valid, err := inspector.InspectRequest(context.Background(), core.Request{Messages: []core.Message{user}})
+	if err != nil {
+		t.Fatalf("InspectRequest failed: %v", err)
+	}
+	if !valid.Valid() || valid.EstimatedInputTokens == 0 {
+		t.Errorf("Expected valid request with a token estimate, got %+v", valid)
+	}
+
+	invalid, err := inspector.InspectRequest(context.Background(), core.Request{
+		Model:       "claude-unknown",
+		Temperature: 1.5,
+		Messages:    []core.Message{user, user},
+	})
+	if err != nil {
+		t.Fatalf("InspectRequest failed: %v", err)
+	}
+	// Temperature, role alternation and unknown model
+	if len(invalid.ValidationErrors) != 3 {
+		t.Errorf("Expected 3 validation errors, got %v", invalid.ValidationErrors)
+	}
+}
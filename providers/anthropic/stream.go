@@ -30,6 +30,9 @@ type textStream struct {
 	contentBlocks       map[int]*contentBlockAccumulator
 	currentMessage      *messagesResponse
 	totalUsage          core.Usage
+	// prefill is the start of the reply sent with the request, emitted
+	// ahead of the first text delta
+	prefill string
 }
 
 // contentBlockAccumulator accumulates content block data across streaming chunks.
@@ -42,6 +45,20 @@ type contentBlockAccumulator struct {
 
 // StreamText implements streaming text generation.
 func (p *Provider) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	return core.StreamWithTimeout(ctx, req, func(ctx context.Context) (core.TextStream, error) {
+		return p.streamText(ctx, req)
+	})
+}
+
+// streamText implements StreamText within the request's timeouts.
+func (p *Provider) streamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	model := p.getModel(req)
 
 	// Use streaming GenAI observability wrapper
@@ -58,6 +75,9 @@ func (p *Provider) StreamText(ctx context.Context, req core.Request) (core.TextS
 
 // executeStreamText handles the actual streaming logic (extracted for observability)
 func (p *Provider) executeStreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	// Describe the response schema and prefill the reply
+	req, prefill := applyResponseSchema(req)
+
 	// Convert request
 	apiReq, err := p.convertRequest(req)
 	if err != nil {
@@ -91,6 +111,7 @@ func (p *Provider) executeStreamText(ctx context.Context, req core.Request) (cor
 		resp:          resp,
 		done:          make(chan struct{}),
 		contentBlocks: make(map[int]*contentBlockAccumulator),
+		prefill:       prefill,
 	}
 
 	// Start processing in background
@@ -160,6 +181,7 @@ func (s *textStream) process(ctx context.Context, collector core.MetricsCollecto
 				s.sendEvent(core.Event{
 					Type:      core.EventError,
 					Err:       err,
+					ErrorCode: core.ErrorCodeOf(err),
 					Timestamp: time.Now(),
 				})
 				s.err = err
@@ -213,6 +235,7 @@ func (s *textStream) processEvent(event streamEvent) {
 	case "message_start":
 		if event.messageStartEvent != nil && event.messageStartEvent.Message != nil {
 			s.currentMessage = event.messageStartEvent.Message
+			s.currentMessage.Usage.mergeInto(&s.totalUsage)
 		}
 
 	case "content_block_start":
@@ -240,9 +263,7 @@ func (s *textStream) processEvent(event streamEvent) {
 	case "message_delta":
 		if event.messageDeltaEvent != nil {
 			if event.messageDeltaEvent.Usage != nil {
-				s.totalUsage.InputTokens = event.messageDeltaEvent.Usage.InputTokens
-				s.totalUsage.OutputTokens = event.messageDeltaEvent.Usage.OutputTokens
-				s.totalUsage.TotalTokens = s.totalUsage.InputTokens + s.totalUsage.OutputTokens
+				event.messageDeltaEvent.Usage.mergeInto(&s.totalUsage)
 			}
 		}
 
@@ -257,6 +278,7 @@ func (s *textStream) processEvent(event streamEvent) {
 			s.sendEvent(core.Event{
 				Type:      core.EventError,
 				Err:       fmt.Errorf("API error: %s", event.errorEvent.Error.Message),
+				ErrorCode: string(mapErrorType(event.errorEvent.Error.Type, event.errorEvent.Error.Message, 0)),
 				Timestamp: time.Now(),
 			})
 		}
@@ -275,12 +297,14 @@ func (s *textStream) processContentBlockDelta(delta *contentBlockDeltaEvent) {
 		switch delta.Delta.Type {
 		case "text_delta":
 			if delta.Delta.Text != "" {
-				acc.textBuffer.WriteString(delta.Delta.Text)
+				text := s.prefill + delta.Delta.Text
+				s.prefill = ""
+				acc.textBuffer.WriteString(text)
 				
 				// Send text delta event
 				s.sendEvent(core.Event{
 					Type:      core.EventTextDelta,
-					TextDelta: delta.Delta.Text,
+					TextDelta: text,
 					Timestamp: time.Now(),
 				})
 			}
@@ -377,6 +401,20 @@ type objectStream struct {
 
 // StreamObject implements streaming generation of structured objects.
 func (p *Provider) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	return core.StreamObjectWithTimeout(ctx, req, func(ctx context.Context) (core.ObjectStream[any], error) {
+		return p.streamObject(ctx, req, schema)
+	})
+}
+
+// streamObject implements StreamObject within the request's timeouts.
+func (p *Provider) streamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	model := p.getModel(req)
 
 	// Use streaming GenAI observability wrapper
@@ -394,13 +432,14 @@ func (p *Provider) StreamObject(ctx context.Context, req core.Request, schema an
 // executeStreamObject handles the actual streaming object logic (extracted for observability)
 func (p *Provider) executeStreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
 	// For Anthropic, we need to modify the request to include JSON formatting instructions
-	schemaJSON, err := json.Marshal(schema)
+	schemaJSON, err := core.MergeResponseSchema(schema, req.ResponseSchema)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling schema: %w", err)
+		return nil, err
 	}
 
 	// Add JSON formatting instructions to the request
 	modifiedReq := req
+	modifiedReq.ResponseSchema = nil
 	
 	// Add instruction to produce JSON output
 	jsonInstructions := fmt.Sprintf(`Please respond with a valid JSON object that conforms to this schema:
@@ -555,6 +594,7 @@ func (s *objectStream) processObject(ctx context.Context, collector core.Metrics
 				s.sendEvent(core.Event{
 					Type:      core.EventError,
 					Err:       err,
+					ErrorCode: core.ErrorCodeOf(err),
 					Timestamp: time.Now(),
 				})
 				s.err = err
@@ -608,6 +648,7 @@ func (s *objectStream) processObjectEvent(event streamEvent) {
 	case "message_start":
 		if event.messageStartEvent != nil && event.messageStartEvent.Message != nil {
 			s.currentMessage = event.messageStartEvent.Message
+			s.currentMessage.Usage.mergeInto(&s.totalUsage)
 		}
 
 	case "content_block_start":
@@ -640,9 +681,7 @@ func (s *objectStream) processObjectEvent(event streamEvent) {
 	case "message_delta":
 		if event.messageDeltaEvent != nil {
 			if event.messageDeltaEvent.Usage != nil {
-				s.totalUsage.InputTokens = event.messageDeltaEvent.Usage.InputTokens
-				s.totalUsage.OutputTokens = event.messageDeltaEvent.Usage.OutputTokens
-				s.totalUsage.TotalTokens = s.totalUsage.InputTokens + s.totalUsage.OutputTokens
+				event.messageDeltaEvent.Usage.mergeInto(&s.totalUsage)
 			}
 		}
 
@@ -652,6 +691,7 @@ func (s *objectStream) processObjectEvent(event streamEvent) {
 			s.sendEvent(core.Event{
 				Type:      core.EventError,
 				Err:       s.finalErr,
+				ErrorCode: core.ErrorCodeOf(s.finalErr),
 				Timestamp: time.Now(),
 			})
 		}
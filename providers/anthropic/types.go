@@ -2,6 +2,8 @@ package anthropic
 
 import (
 	"encoding/json"
+
+	"github.com/recera/gai/core"
 )
 
 // messagesRequest represents the request structure for Anthropic's Messages API.
@@ -15,7 +17,21 @@ type messagesRequest struct {
 	TopK          *int        `json:"top_k,omitempty"`
 	StopSequences []string    `json:"stop_sequences,omitempty"`
 	Tools         []tool      `json:"tools,omitempty"`
+	ToolChoice    *toolChoice `json:"tool_choice,omitempty"`
 	Stream        bool        `json:"stream,omitempty"`
+	Thinking      *thinking   `json:"thinking,omitempty"`
+}
+
+// toolChoice controls whether and which tools the model calls.
+type toolChoice struct {
+	Type string `json:"type"` // "auto", "any", "tool" or "none"
+	Name string `json:"name,omitempty"`
+}
+
+// thinking enables extended thinking with a token budget.
+type thinking struct {
+	Type         string `json:"type"` // "enabled"
+	BudgetTokens int    `json:"budget_tokens"`
 }
 
 // message represents a message in the conversation.
@@ -26,36 +42,56 @@ type message struct {
 
 // contentBlock represents a block of content within a message.
 type contentBlock struct {
-	Type string `json:"type"` // "text", "image", "tool_use", "tool_result"
+	Type string `json:"type"` // "text", "image", "tool_use", "tool_result", "thinking"
 
 	// Text content
 	Text string `json:"text,omitempty"`
 
+	// Thinking content
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+
 	// Image content
 	Source *imageSource `json:"source,omitempty"`
 
 	// Tool use content
-	ID    string                 `json:"id,omitempty"`    // For tool_use and tool_result
+	ID    string                 `json:"id,omitempty"`    // For tool_use
 	Name  string                 `json:"name,omitempty"`  // For tool_use
 	Input map[string]interface{} `json:"input,omitempty"` // For tool_use
 
 	// Tool result content
-	Content interface{} `json:"content,omitempty"` // For tool_result - can be string or content blocks
-	IsError bool        `json:"is_error,omitempty"` // For tool_result
+	ToolUseID string      `json:"tool_use_id,omitempty"` // For tool_result
+	Content   interface{} `json:"content,omitempty"`     // For tool_result - can be string or content blocks
+	IsError   bool        `json:"is_error,omitempty"`    // For tool_result
+
+	// Sources cited by a text block
+	Citations []citation `json:"citations,omitempty"`
+}
+
+// citation is a source cited by a text block. Web search results have type
+// "web_search_result_location".
+type citation struct {
+	Type      string `json:"type"`
+	URL       string `json:"url,omitempty"`
+	Title     string `json:"title,omitempty"`
+	CitedText string `json:"cited_text,omitempty"`
 }
 
 // imageSource represents an image source in Anthropic format.
 type imageSource struct {
-	Type      string `json:"type"`       // "base64"
-	MediaType string `json:"media_type"` // "image/jpeg", "image/png", etc.
-	Data      string `json:"data"`       // Base64 encoded image data
+	Type      string `json:"type"`                 // "base64" or "url"
+	MediaType string `json:"media_type,omitempty"` // "image/jpeg", "image/png", etc.
+	Data      string `json:"data,omitempty"`       // Base64 encoded image data
+	URL       string `json:"url,omitempty"`        // Publicly reachable image URL
 }
 
 // tool represents a tool definition in Anthropic format.
 type tool struct {
+	Type        string                 `json:"type,omitempty"` // Set for server tools such as web search
 	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	InputSchema map[string]interface{} `json:"input_schema"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"input_schema,omitempty"`
+	MaxUses     int                    `json:"max_uses,omitempty"` // For web search
 }
 
 // messagesResponse represents the response structure from Anthropic's Messages API.
@@ -72,8 +108,40 @@ type messagesResponse struct {
 
 // usage represents token usage information in Anthropic format.
 type usage struct {
-	InputTokens  int `json:"input_tokens"`
-	OutputTokens int `json:"output_tokens"`
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+}
+
+// toCore converts Anthropic usage to core.Usage.
+func (u usage) toCore() core.Usage {
+	return core.Usage{
+		InputTokens:         u.InputTokens,
+		OutputTokens:        u.OutputTokens,
+		TotalTokens:         u.InputTokens + u.OutputTokens,
+		CacheReadTokens:     u.CacheReadInputTokens,
+		CacheCreationTokens: u.CacheCreationInputTokens,
+	}
+}
+
+// mergeInto updates dst with the counts reported in u. Streaming responses
+// report input and cache counts in message_start and cumulative counts in
+// message_delta, so fields that are zero in u leave dst unchanged.
+func (u usage) mergeInto(dst *core.Usage) {
+	if u.InputTokens > 0 {
+		dst.InputTokens = u.InputTokens
+	}
+	if u.OutputTokens > 0 {
+		dst.OutputTokens = u.OutputTokens
+	}
+	if u.CacheReadInputTokens > 0 {
+		dst.CacheReadTokens = u.CacheReadInputTokens
+	}
+	if u.CacheCreationInputTokens > 0 {
+		dst.CacheCreationTokens = u.CacheCreationInputTokens
+	}
+	dst.TotalTokens = dst.InputTokens + dst.OutputTokens
 }
 
 // Streaming types
@@ -244,10 +312,10 @@ func NewToolUseContent(id, name string, input map[string]interface{}) contentBlo
 // NewToolResultContent creates a tool result content block.
 func NewToolResultContent(toolUseID string, content interface{}, isError bool) contentBlock {
 	return contentBlock{
-		Type:    "tool_result",
-		ID:      toolUseID,
-		Content: content,
-		IsError: isError,
+		Type:      "tool_result",
+		ToolUseID: toolUseID,
+		Content:   content,
+		IsError:   isError,
 	}
 }
 
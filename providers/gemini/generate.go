@@ -27,6 +27,7 @@ func (p *Provider) runRequest(ctx context.Context, req core.Request) (*core.Text
 
 	steps := []core.Step{}
 	totalUsage := core.Usage{}
+	modelUsed := ""
 	
 	for stepNum := 0; stepNum < 10; stepNum++ { // Max 10 steps to prevent infinite loops
 		// Generate with current messages
@@ -42,19 +43,22 @@ func (p *Provider) runRequest(ctx context.Context, req core.Request) (*core.Text
 		totalUsage.InputTokens += resp.Usage.InputTokens
 		totalUsage.OutputTokens += resp.Usage.OutputTokens
 		totalUsage.TotalTokens += resp.Usage.TotalTokens
+		modelUsed = resp.ModelUsed
 
 		// Check for tool calls
 		toolCalls := extractToolCalls(resp.Raw)
 		if len(toolCalls) == 0 {
 			// No more tools, we're done
 			steps = append(steps, core.Step{
-				Text: resp.Text,
+				Text:  resp.Text,
+				Usage: resp.Usage,
 			})
 			return &core.TextResult{
-				Text:  resp.Text,
-				Steps: steps,
-				Usage: totalUsage,
-				Raw:   resp.Raw,
+				Text:      resp.Text,
+				Steps:     steps,
+				Usage:     totalUsage,
+				Raw:       resp.Raw,
+				ModelUsed: modelUsed,
 			}, nil
 		}
 
@@ -66,6 +70,7 @@ func (p *Provider) runRequest(ctx context.Context, req core.Request) (*core.Text
 			Text:        resp.Text,
 			ToolCalls:   toolCalls,
 			ToolResults: toolResults,
+			Usage:       resp.Usage,
 		})
 
 		// Add assistant message with tool calls
@@ -101,9 +106,10 @@ func (p *Provider) runRequest(ctx context.Context, req core.Request) (*core.Text
 	}
 
 	return &core.TextResult{
-		Text:  finalText,
-		Steps: steps,
-		Usage: totalUsage,
+		Text:      finalText,
+		Steps:     steps,
+		Usage:     totalUsage,
+		ModelUsed: modelUsed,
 	}, nil
 }
 
@@ -122,7 +128,13 @@ func (p *Provider) generateOnce(ctx context.Context, req core.Request) (*core.Te
 	var resp *GenerateContentResponse
 	var lastErr error
 
-	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+	maxRetries := p.maxRetries
+	policy, hasPolicy := core.RetryPolicyFromContext(ctx)
+	if hasPolicy {
+		maxRetries = policy.Retries(p.maxRetries)
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			// Exponential backoff
 			delay := p.retryDelay * time.Duration(1<<(attempt-1))
@@ -133,13 +145,17 @@ func (p *Provider) generateOnce(ctx context.Context, req core.Request) (*core.Te
 			}
 		}
 
-		resp, lastErr = p.doRequest(ctx, body)
+		resp, lastErr = p.doRequest(ctx, p.getModel(req), body)
 		if lastErr == nil {
 			break
 		}
 
-		// Check if error is retryable
-		if !isRetryable(lastErr) {
+		// Check if error is retryable, preferring per-request retry settings
+		retryable := isRetryable(lastErr)
+		if hasPolicy {
+			retryable = policy.ShouldRetry(lastErr)
+		}
+		if !retryable {
 			break
 		}
 	}
@@ -153,12 +169,7 @@ func (p *Provider) generateOnce(ctx context.Context, req core.Request) (*core.Te
 }
 
 // doRequest performs the actual HTTP request.
-func (p *Provider) doRequest(ctx context.Context, body []byte) (*GenerateContentResponse, error) {
-	model := p.model
-	if model == "" {
-		model = "gemini-1.5-flash"
-	}
-
+func (p *Provider) doRequest(ctx context.Context, model string, body []byte) (*GenerateContentResponse, error) {
 	url := fmt.Sprintf("%s/%s/models/%s:generateContent?key=%s",
 		p.baseURL, apiVersion, model, p.apiKey)
 
@@ -240,8 +251,8 @@ func (p *Provider) convertRequest(req core.Request) *GenerateContentRequest {
 		temp := float32(req.Temperature)
 		geminiReq.GenerationConfig.Temperature = &temp
 	}
-	if req.MaxTokens > 0 {
-		maxTokens := int32(req.MaxTokens)
+	if limit := core.OutputTokenLimit(req); limit > 0 {
+		maxTokens := int32(limit)
 		geminiReq.GenerationConfig.MaxOutputTokens = &maxTokens
 	}
 
@@ -288,13 +299,13 @@ func (p *Provider) convertRequest(req core.Request) *GenerateContentRequest {
 			},
 		}
 		
-		if req.ToolChoice != core.ToolNone {
-			geminiReq.ToolConfig = &ToolConfig{
-				FunctionCallingConfig: &FunctionCallingConfig{
-					Mode: convertToolChoice(req.ToolChoice),
-				},
-			}
+		callingConfig := &FunctionCallingConfig{
+			Mode: convertToolChoice(req.ToolChoice),
+		}
+		if req.ToolChoice == core.ToolSpecific {
+			callingConfig.AllowedFunctionNames = []string{req.SpecificTool}
 		}
+		geminiReq.ToolConfig = &ToolConfig{FunctionCallingConfig: callingConfig}
 	}
 
 	// Handle response schema for structured outputs
@@ -386,8 +397,9 @@ func (p *Provider) convertTools(tools []core.ToolHandle) []FunctionDeclaration {
 func (p *Provider) convertResponse(resp *GenerateContentResponse) *core.TextResult {
 	if len(resp.Candidates) == 0 {
 		return &core.TextResult{
-			Text:  "",
-			Usage: core.Usage{},
+			Text:      "",
+			Usage:     core.Usage{},
+			ModelUsed: resp.ModelVersion,
 		}
 	}
 
@@ -410,9 +422,10 @@ func (p *Provider) convertResponse(resp *GenerateContentResponse) *core.TextResu
 	}
 
 	return &core.TextResult{
-		Text:  text.String(),
-		Usage: usage,
-		Raw:   resp,
+		Text:      text.String(),
+		Usage:     usage,
+		Raw:       resp,
+		ModelUsed: resp.ModelVersion,
 	}
 }
 
@@ -440,6 +453,9 @@ func extractToolCalls(raw any) []core.ToolCall {
 func (p *Provider) executeTools(ctx context.Context, calls []core.ToolCall, handles []core.ToolHandle, messages []core.Message) []core.ToolExecution {
 	results := make([]core.ToolExecution, len(calls))
 	
+	// Coalesce repeated calls to batch-capable tools into one execution each
+	batched := core.ExecuteBatchedCalls(ctx, handles, calls, nil)
+	
 	// Find and execute each tool
 	for i, call := range calls {
 		var handle core.ToolHandle
@@ -458,8 +474,14 @@ func (p *Provider) executeTools(ctx context.Context, calls []core.ToolCall, hand
 			continue
 		}
 
-		// Execute tool
-		result, err := handle.Exec(ctx, call.Input, nil)
+		// Execute tool, using the batch result if the call was coalesced
+		var result any
+		var err error
+		if b, ok := batched[i]; ok {
+			result, err = b.Result, b.Err
+		} else {
+			result, err = handle.Exec(ctx, call.Input, nil)
+		}
 		if err != nil {
 			results[i] = core.ToolExecution{
 				Name:   call.Name,
@@ -478,8 +500,7 @@ func (p *Provider) executeTools(ctx context.Context, calls []core.ToolCall, hand
 
 // formatToolResult formats a tool result as text.
 func formatToolResult(exec core.ToolExecution) string {
-	data, _ := json.Marshal(exec.Result)
-	return string(data)
+	return exec.ToolResult().Content()
 }
 
 // isRetryable checks if an error should be retried.
@@ -0,0 +1,25 @@
+package gemini
+
+import (
+	"context"
+
+	"github.com/recera/gai/core"
+)
+
+// InspectRequest implements core.InspectableProvider. It checks req the way
+// GenerateText would, including validating a per-request model, without
+// sending it.
+func (p *Provider) InspectRequest(ctx context.Context, req core.Request) (core.InspectedRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return core.InspectedRequest{}, err
+	}
+	req.Model = core.ResolveModel(ctx, "gemini", req.Model)
+
+	inspected := core.InspectRequest(req, core.RequestConstraints{MaxTemperature: 2})
+	if req.Model != "" {
+		if err := p.ValidateModel(req.Model); err != nil {
+			inspected.ValidationErrors = append(inspected.ValidationErrors, err.Error())
+		}
+	}
+	return inspected, nil
+}
@@ -0,0 +1,91 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/recera/gai/core"
+)
+
+// Named model constants for the Gemini API.
+const (
+	// Gemini 2.5 family
+	Gemini25Pro       = "gemini-2.5-pro"
+	Gemini25Flash     = "gemini-2.5-flash"
+	Gemini25FlashLite = "gemini-2.5-flash-lite"
+
+	// Gemini 2.0 family
+	Gemini20Flash    = "gemini-2.0-flash"
+	Gemini20FlashExp = "gemini-2.0-flash-exp"
+
+	// Gemini 1.5 family
+	Gemini15Pro     = "gemini-1.5-pro"
+	Gemini15Flash   = "gemini-1.5-flash"
+	Gemini15Flash8B = "gemini-1.5-flash-8b"
+)
+
+// defaultModel is used when neither the provider nor the request specifies a model.
+const defaultModel = Gemini15Flash
+
+// knownModels is the set of models accepted by ValidateModel.
+var knownModels = map[string]struct{}{
+	Gemini25Pro:       {},
+	Gemini25Flash:     {},
+	Gemini25FlashLite: {},
+	Gemini20Flash:     {},
+	Gemini20FlashExp:  {},
+	Gemini15Pro:       {},
+	Gemini15Flash:     {},
+	Gemini15Flash8B:   {},
+}
+
+// WithAllowUnknownModels permits model names outside the built-in constant list.
+// Enable this for new or preview models that have not yet been added.
+func WithAllowUnknownModels(allow bool) Option {
+	return func(p *Provider) {
+		p.allowUnknownModels = allow
+	}
+}
+
+// ValidateModel implements core.ModelValidator.
+// It returns an AIError with ErrorUnknownModel for names not in the known model list,
+// unless unknown models were allowed with WithAllowUnknownModels.
+func (p *Provider) ValidateModel(model string) error {
+	if model == "" {
+		return core.NewError(core.ErrorUnknownModel, "model name is empty",
+			core.WithProvider("gemini"))
+	}
+	if _, ok := knownModels[model]; ok || p.allowUnknownModels {
+		return nil
+	}
+	return core.NewError(core.ErrorUnknownModel,
+		fmt.Sprintf("unknown model %q (use WithAllowUnknownModels to permit it)", model),
+		core.WithProvider("gemini"),
+		core.WithModel(model))
+}
+
+// requestSetup returns how the provider prepares requests (see
+// core.PrepareRequest).
+func (p *Provider) requestSetup() core.RequestSetup {
+	return core.RequestSetup{
+		Provider:  "gemini",
+		Validator: p,
+		Client:    p.client,
+	}
+}
+
+// RequestModel implements core.ModelResolver.
+func (p *Provider) RequestModel(ctx context.Context, req core.Request) string {
+	return p.getModel(core.PrepareModel(ctx, req, p.requestSetup()))
+}
+
+// getModel returns the model to use for the request.
+func (p *Provider) getModel(req core.Request) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	if p.model != "" {
+		return p.model
+	}
+	return defaultModel
+}
@@ -35,6 +35,8 @@ type Provider struct {
 	collector      core.MetricsCollector
 	fileStore      *FileStore // For managing uploaded files
 	defaultSafety  *core.SafetyConfig
+	// allowUnknownModels skips known-model validation for Request.Model overrides
+	allowUnknownModels bool
 	mu             sync.RWMutex
 }
 
@@ -130,8 +132,22 @@ func New(opts ...Option) *Provider {
 
 // GenerateText generates text with optional multi-step tool execution.
 func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.TextResult, error) {
+		return p.generateText(ctx, req)
+	})
+}
+
+// generateText implements GenerateText within the request's timeouts.
+func (p *Provider) generateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	// Handle file uploads if needed
-	req, err := p.processFiles(ctx, req)
+	req, err = p.processFiles(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process files: %w", err)
 	}
@@ -147,8 +163,22 @@ func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.Te
 
 // StreamText streams text generation with events.
 func (p *Provider) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	return core.StreamWithTimeout(ctx, req, func(ctx context.Context) (core.TextStream, error) {
+		return p.streamText(ctx, req)
+	})
+}
+
+// streamText implements StreamText within the request's timeouts.
+func (p *Provider) streamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	// Handle file uploads if needed
-	req, err := p.processFiles(ctx, req)
+	req, err = p.processFiles(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process files: %w", err)
 	}
@@ -163,7 +193,29 @@ func (p *Provider) StreamText(ctx context.Context, req core.Request) (core.TextS
 }
 
 // GenerateObject generates a structured object conforming to the provided schema.
+// Outputs that fail JSON validation are retried up to req.MaxStructuredRetries times.
 func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.ObjectResult[any], error) {
+		return p.generateObjectWithRetry(ctx, req, schema)
+	})
+}
+
+// generateObjectWithRetry implements GenerateObject within the request's timeouts.
+func (p *Provider) generateObjectWithRetry(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.GenerateObjectWithRetry(ctx, req, func(ctx context.Context, req core.Request) (*core.ObjectResult[any], error) {
+		return p.generateObject(ctx, req, schema)
+	})
+}
+
+// generateObject performs a single structured output attempt.
+func (p *Provider) generateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
 	// Add response schema to request
 	reqWithSchema := req
 	if reqWithSchema.ProviderOptions == nil {
@@ -183,7 +235,7 @@ func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema
 		// Try to repair JSON if needed
 		repaired := repairJSON(result.Text)
 		if err := json.Unmarshal([]byte(repaired), &obj); err != nil {
-			return nil, fmt.Errorf("failed to parse object: %w", err)
+			return nil, core.NewValidationError(result.Text, err)
 		}
 	}
 
@@ -197,6 +249,20 @@ func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema
 
 // StreamObject streams a structured object generation.
 func (p *Provider) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	return core.StreamObjectWithTimeout(ctx, req, func(ctx context.Context) (core.ObjectStream[any], error) {
+		return p.streamObject(ctx, req, schema)
+	})
+}
+
+// streamObject implements StreamObject within the request's timeouts.
+func (p *Provider) streamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	// Add response schema to request
 	reqWithSchema := req
 	if reqWithSchema.ProviderOptions == nil {
@@ -475,6 +475,50 @@ func TestGenerateText(t *testing.T) {
 	}
 }
 
+func TestRequestModelOverride(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		handleGenerateContent(w, r)
+	}))
+	defer server.Close()
+
+	provider := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(0),
+	)
+
+	req := core.Request{
+		Model: Gemini15Pro,
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}},
+		},
+	}
+
+	if _, err := provider.GenerateText(context.Background(), req); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if !strings.Contains(gotPath, "/models/"+Gemini15Pro+":generateContent") {
+		t.Errorf("Expected request for %s, got path %s", Gemini15Pro, gotPath)
+	}
+
+	gotPath = ""
+	req.Model = "gemini-unknown"
+	_, err := provider.GenerateText(context.Background(), req)
+	if !core.IsUnknownModel(err) {
+		t.Errorf("Expected unknown model error, got %v", err)
+	}
+	if gotPath != "" {
+		t.Error("Expected no HTTP request for an unknown model")
+	}
+
+	permissive := New(WithAPIKey("test-key"), WithAllowUnknownModels(true))
+	if err := permissive.ValidateModel("gemini-preview"); err != nil {
+		t.Errorf("Expected unknown model to be allowed, got %v", err)
+	}
+}
+
 func TestStreamText(t *testing.T) {
 	server := mockGeminiServer()
 	defer server.Close()
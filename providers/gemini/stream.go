@@ -37,7 +37,7 @@ func (p *Provider) createStream(ctx context.Context, req core.Request) (core.Tex
 	streamCtx, cancel := context.WithCancel(ctx)
 
 	// Make streaming request
-	stream, err := p.doStreamRequest(streamCtx, body)
+	stream, err := p.doStreamRequest(streamCtx, p.getModel(req), body)
 	if err != nil {
 		cancel()
 		return nil, err
@@ -50,12 +50,7 @@ func (p *Provider) createStream(ctx context.Context, req core.Request) (core.Tex
 }
 
 // doStreamRequest performs the streaming HTTP request.
-func (p *Provider) doStreamRequest(ctx context.Context, body []byte) (*geminiStream, error) {
-	model := p.model
-	if model == "" {
-		model = "gemini-1.5-flash"
-	}
-
+func (p *Provider) doStreamRequest(ctx context.Context, model string, body []byte) (*geminiStream, error) {
 	// Use streamGenerateContent endpoint with alt=sse for SSE streaming
 	url := fmt.Sprintf("%s/%s/models/%s:streamGenerateContent?alt=sse&key=%s",
 		p.baseURL, apiVersion, model, p.apiKey)
@@ -145,6 +140,7 @@ func (s *geminiStream) processEvents() {
 				s.events <- core.Event{
 					Type:      core.EventError,
 					Err:       fmt.Errorf("failed to parse chunk: %w", err),
+					ErrorCode: core.ErrorCodeOf(err),
 					Timestamp: time.Now(),
 				}
 				continue
@@ -242,6 +238,7 @@ func (s *geminiStream) processEvents() {
 		s.events <- core.Event{
 			Type:      core.EventError,
 			Err:       err,
+			ErrorCode: core.ErrorCodeOf(err),
 			Timestamp: time.Now(),
 		}
 		return
@@ -78,6 +78,8 @@ type ToolConfig struct {
 // FunctionCallingConfig controls function calling behavior.
 type FunctionCallingConfig struct {
 	Mode string `json:"mode"` // AUTO, ANY, NONE
+	// AllowedFunctionNames limits which functions may be called in ANY mode
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
 }
 
 // SafetySetting configures safety thresholds.
@@ -103,6 +105,7 @@ type GenerateContentResponse struct {
 	Candidates     []Candidate     `json:"candidates"`
 	PromptFeedback *PromptFeedback `json:"promptFeedback,omitempty"`
 	UsageMetadata  *UsageMetadata  `json:"usageMetadata,omitempty"`
+	ModelVersion   string          `json:"modelVersion,omitempty"`
 }
 
 // Candidate represents a generation candidate.
@@ -278,7 +281,7 @@ func convertToolChoice(choice core.ToolChoice) string {
 		return "AUTO"
 	case core.ToolNone:
 		return "NONE"
-	case core.ToolRequired:
+	case core.ToolRequired, core.ToolSpecific:
 		return "ANY"
 	default:
 		return "AUTO"
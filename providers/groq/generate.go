@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/recera/gai/core"
@@ -14,6 +13,20 @@ import (
 
 // GenerateText generates text with optional multi-step tool execution.
 func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.TextResult, error) {
+		return p.generateText(ctx, req)
+	})
+}
+
+// generateText implements GenerateText within the request's timeouts.
+func (p *Provider) generateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	if p.apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
@@ -134,9 +147,8 @@ func (p *Provider) executeMultiStep(ctx context.Context, req core.Request, model
 	
 	// Calculate total usage
 	totalUsage := core.Usage{}
-	for range steps {
-		// Usage would be accumulated from each step - simplified for now
-		totalUsage.TotalTokens += 100 // Placeholder
+	for _, step := range steps {
+		totalUsage.Add(step.Usage)
 	}
 	
 	return &core.TextResult{
@@ -156,6 +168,11 @@ func (p *Provider) processStepResponse(groqResp chatCompletionResponse, messages
 	step := core.Step{
 		StepNumber: stepNumber,
 		Timestamp:  time.Now(),
+		Usage: core.Usage{
+			InputTokens:  groqResp.Usage.PromptTokens,
+			OutputTokens: groqResp.Usage.CompletionTokens,
+			TotalTokens:  groqResp.Usage.TotalTokens,
+		},
 	}
 	
 	newMessages := make([]core.Message, len(messages))
@@ -163,21 +180,14 @@ func (p *Provider) processStepResponse(groqResp chatCompletionResponse, messages
 
 	// Handle tool calls if present
 	if len(choice.Message.ToolCalls) > 0 {
-		// First, add the assistant message with tool calls to the conversation
+		// Text the assistant sent along with its tool calls
 		content := ""
 		if choice.Message.Content != nil {
 			if s, ok := choice.Message.Content.(string); ok {
 				content = s
 			}
 		}
-		assistantMsg := core.Message{
-			Role: core.Assistant,
-			Parts: []core.Part{
-				core.Text{Text: content},
-			},
-		}
-		newMessages = append(newMessages, assistantMsg)
-		
+
 		// Convert tool calls
 		for _, tc := range choice.Message.ToolCalls {
 			step.ToolCalls = append(step.ToolCalls, core.ToolCall{
@@ -186,6 +196,16 @@ func (p *Provider) processStepResponse(groqResp chatCompletionResponse, messages
 				Input: json.RawMessage(tc.Function.Arguments),
 			})
 		}
+
+		// Add the assistant message with its tool calls to the conversation
+		assistantMsg := core.Message{
+			Role: core.Assistant,
+			Parts: []core.Part{
+				core.Text{Text: content},
+			},
+			ToolCalls: step.ToolCalls,
+		}
+		newMessages = append(newMessages, assistantMsg)
 		
 		// Execute tools and add their results
 		for _, toolCall := range step.ToolCalls {
@@ -221,10 +241,9 @@ func (p *Provider) processStepResponse(groqResp chatCompletionResponse, messages
 				newMessages = append(newMessages, core.Message{
 					Role: core.Tool,
 					Parts: []core.Part{
-						core.Text{Text: fmt.Sprintf("Error: %s", err.Error())},
+						core.Text{Text: core.NewToolResult(toolCall.ID, nil, err).Content()},
 					},
-					// Store tool call ID for Groq API compatibility
-					Name: fmt.Sprintf("tool_call_id:%s", toolCall.ID),
+					ToolCallID: toolCall.ID,
 				})
 			} else {
 				step.ToolResults = append(step.ToolResults, core.ToolExecution{
@@ -240,8 +259,7 @@ func (p *Provider) processStepResponse(groqResp chatCompletionResponse, messages
 					Parts: []core.Part{
 						core.Text{Text: string(resultJSON)},
 					},
-					// Store the tool call ID that this message responds to
-					Name: fmt.Sprintf("tool_call_id:%s", toolCall.ID),
+					ToolCallID: toolCall.ID,
 				})
 			}
 		}
@@ -280,14 +298,12 @@ func (p *Provider) convertRequest(req core.Request, modelInfo ModelInfo) (*chatC
 	}
 
 	// Handle token limits with model-specific logic
-	if req.MaxTokens > 0 {
-		if req.MaxTokens > modelInfo.MaxCompletionTokens {
+	if maxTokens := core.OutputTokenLimit(req); maxTokens > 0 {
+		if maxTokens > modelInfo.MaxCompletionTokens {
 			// Cap at model maximum
-			maxTokens := modelInfo.MaxCompletionTokens
-			groqReq.MaxTokens = &maxTokens
-		} else {
-			groqReq.MaxTokens = &req.MaxTokens
+			maxTokens = modelInfo.MaxCompletionTokens
 		}
+		groqReq.MaxTokens = &maxTokens
 	}
 
 	// Convert messages with special handling for tool responses
@@ -300,7 +316,7 @@ func (p *Provider) convertRequest(req core.Request, modelInfo ModelInfo) (*chatC
 	// Convert tools if present and supported
 	if len(req.Tools) > 0 && modelInfo.SupportsTools {
 		groqReq.Tools = p.convertTools(req.Tools)
-		groqReq.ToolChoice = p.convertToolChoice(req.ToolChoice)
+		groqReq.ToolChoice = p.convertToolChoice(req.ToolChoice, req.SpecificTool)
 		
 		// Enable parallel tool calls if supported
 		if modelInfo.PerformanceClass == "ultra-fast" || modelInfo.PerformanceClass == "fast" {
@@ -344,6 +360,17 @@ func (p *Provider) convertMessages(messages []core.Message) ([]chatMessage, erro
 					cm.Content = text.Text
 				}
 			}
+			for _, call := range msg.ToolCalls {
+				args := string(call.Input)
+				if args == "" {
+					args = "{}"
+				}
+				cm.ToolCalls = append(cm.ToolCalls, toolCall{
+					ID:       call.ID,
+					Type:     "function",
+					Function: functionCall{Name: call.Name, Arguments: args},
+				})
+			}
 			
 		case core.Tool:
 			// Tool messages need tool_call_id - CRITICAL for Groq compatibility
@@ -351,9 +378,8 @@ func (p *Provider) convertMessages(messages []core.Message) ([]chatMessage, erro
 				if text, ok := msg.Parts[0].(core.Text); ok {
 					cm.Content = text.Text
 					
-					// Extract tool_call_id from the message name if stored there
-					if strings.HasPrefix(msg.Name, "tool_call_id:") {
-						cm.ToolCallID = strings.TrimPrefix(msg.Name, "tool_call_id:")
+					if msg.ToolCallID != "" {
+						cm.ToolCallID = msg.ToolCallID
 					} else {
 						// Fallback: generate a reasonable tool call ID
 						// In production, this should be properly tracked
@@ -442,7 +468,7 @@ func (p *Provider) convertTools(tools []core.ToolHandle) []chatTool {
 }
 
 // convertToolChoice converts core tool choice to Groq format.
-func (p *Provider) convertToolChoice(choice core.ToolChoice) interface{} {
+func (p *Provider) convertToolChoice(choice core.ToolChoice, specificTool string) interface{} {
 	switch choice {
 	case core.ToolAuto:
 		return "auto"
@@ -450,6 +476,11 @@ func (p *Provider) convertToolChoice(choice core.ToolChoice) interface{} {
 		return "none"
 	case core.ToolRequired:
 		return "required"
+	case core.ToolSpecific:
+		return map[string]interface{}{
+			"type":     "function",
+			"function": map[string]string{"name": specificTool},
+		}
 	default:
 		return "auto"
 	}
@@ -516,6 +547,7 @@ func (p *Provider) convertTextResponse(groqResp chatCompletionResponse, original
 				OutputTokens: groqResp.Usage.CompletionTokens,
 				TotalTokens:  groqResp.Usage.TotalTokens,
 			},
+			ModelUsed: groqResp.Model,
 		}
 	}
 
@@ -535,5 +567,6 @@ func (p *Provider) convertTextResponse(groqResp chatCompletionResponse, original
 			OutputTokens: groqResp.Usage.CompletionTokens,
 			TotalTokens:  groqResp.Usage.TotalTokens,
 		},
+		ModelUsed: groqResp.Model,
 	}
 }
\ No newline at end of file
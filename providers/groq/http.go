@@ -17,7 +17,13 @@ import (
 func (p *Provider) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	var lastErr error
 	
-	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+	maxRetries := p.maxRetries
+	policy, hasPolicy := core.RetryPolicyFromContext(ctx)
+	if hasPolicy {
+		maxRetries = policy.Retries(p.maxRetries)
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			// Fast exponential backoff optimized for Groq's speed
 			delay := p.retryDelay * time.Duration(1<<uint(attempt-1))
@@ -37,12 +43,26 @@ func (p *Provider) doRequest(ctx context.Context, method, path string, body inte
 
 		resp, err := p.doRequestOnce(ctx, method, path, body)
 		if err != nil {
+			if hasPolicy && !policy.ShouldRetry(err) {
+				return nil, err
+			}
 			lastErr = err
 			continue
 		}
 
+		// Per-request retry settings replace the status code checks below
+		if hasPolicy {
+			if attempt < maxRetries {
+				if retry, apiErr := policy.ShouldRetryResponse(resp, "groq"); retry {
+					lastErr = apiErr
+					continue
+				}
+			}
+			return resp, nil
+		}
+
 		// Check if we should retry based on status code
-		if p.shouldRetry(resp.StatusCode) && attempt < p.maxRetries {
+		if p.shouldRetry(resp.StatusCode) && attempt < maxRetries {
 			// Read and close body before retry
 			io.ReadAll(resp.Body)
 			resp.Body.Close()
@@ -53,7 +73,7 @@ func (p *Provider) doRequest(ctx context.Context, method, path string, body inte
 		return resp, nil
 	}
 
-	return nil, fmt.Errorf("after %d retries: %w", p.maxRetries, lastErr)
+	return nil, fmt.Errorf("after %d retries: %w", maxRetries, lastErr)
 }
 
 // doRequestOnce performs a single HTTP request.
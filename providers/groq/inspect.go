@@ -0,0 +1,38 @@
+package groq
+
+import (
+	"context"
+
+	"github.com/recera/gai/core"
+)
+
+// InspectRequest implements core.InspectableProvider. It checks req the way
+// GenerateText would, using the model catalog for tool support and token
+// limits, without sending it.
+func (p *Provider) InspectRequest(ctx context.Context, req core.Request) (core.InspectedRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return core.InspectedRequest{}, err
+	}
+	req.Model = core.ResolveModel(ctx, "groq", req.Model)
+	model := p.getModel(req)
+	info := p.getModelInfo(model)
+
+	inspected := core.InspectRequest(req, core.RequestConstraints{
+		MaxTemperature:  2,
+		MaxOutputTokens: info.MaxCompletionTokens,
+		ContextWindow:   info.ContextWindow,
+		NoTools:         !info.SupportsTools,
+	})
+	if info.IsDeprecated {
+		inspected.Warnings = append(inspected.Warnings, "model "+model+" is deprecated")
+	}
+	if req.Model != "" {
+		if err := p.ValidateModel(req.Model); err != nil {
+			inspected.ValidationErrors = append(inspected.ValidationErrors, err.Error())
+		}
+	}
+	if _, err := p.convertRequest(req, info); err != nil {
+		inspected.ValidationErrors = append(inspected.ValidationErrors, err.Error())
+	}
+	return inspected, nil
+}
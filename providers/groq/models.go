@@ -0,0 +1,83 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/recera/gai/core"
+)
+
+// Named model constants for Groq-hosted models.
+const (
+	// Meta Llama models
+	Llama3370BVersatile = "llama-3.3-70b-versatile"
+	Llama318BInstant    = "llama-3.1-8b-instant"
+	Llama4Scout         = "meta-llama/llama-4-scout-17b-16e-instruct"
+	Llama4Maverick      = "meta-llama/llama-4-maverick-17b-128e-instruct"
+
+	// OpenAI open-weight models
+	GPTOSS20B  = "openai/gpt-oss-20b"
+	GPTOSS120B = "openai/gpt-oss-120b"
+
+	// Reasoning models
+	DeepSeekR1DistillLlama70B = "deepseek-r1-distill-llama-70b"
+	KimiK2Instruct            = "moonshotai/kimi-k2-instruct"
+	Qwen332B                  = "qwen/qwen3-32b"
+
+	// Google models
+	Gemma29BIT = "gemma2-9b-it"
+
+	// Groq compound systems
+	CompoundBeta     = "compound-beta"
+	CompoundBetaMini = "compound-beta-mini"
+)
+
+// WithAllowUnknownModels permits model names outside the built-in model catalog.
+// Enable this for new or preview models that have not yet been added.
+func WithAllowUnknownModels(allow bool) Option {
+	return func(p *Provider) {
+		p.allowUnknownModels = allow
+	}
+}
+
+// ValidateModel implements core.ModelValidator.
+// It returns an AIError with ErrorUnknownModel for names not in the model catalog,
+// unless unknown models were allowed with WithAllowUnknownModels.
+func (p *Provider) ValidateModel(model string) error {
+	if model == "" {
+		return core.NewError(core.ErrorUnknownModel, "model name is empty",
+			core.WithProvider("groq"))
+	}
+	if _, ok := modelDB[model]; ok || p.allowUnknownModels {
+		return nil
+	}
+	return core.NewError(core.ErrorUnknownModel,
+		fmt.Sprintf("unknown model %q (use WithAllowUnknownModels to permit it)", model),
+		core.WithProvider("groq"),
+		core.WithModel(model))
+}
+
+// Models implements core.ModelLister. The model list comes from the /models
+// endpoint, enriched with capabilities from the built-in model catalog.
+func (p *Provider) Models(ctx context.Context) ([]core.ModelInfo, error) {
+	available, err := p.GetModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]core.ModelInfo, 0, len(available))
+	for _, m := range available {
+		info := core.ModelInfo{ID: m.ID, ContextWindow: m.ContextWindow}
+		if m.SupportsTools {
+			info.Capabilities = append(info.Capabilities, core.CapabilityToolCalling)
+		}
+		if m.SupportsVision {
+			info.Capabilities = append(info.Capabilities, core.CapabilityVision)
+		}
+		if m.SupportsJSON {
+			info.Capabilities = append(info.Capabilities, core.CapabilityJSONMode)
+		}
+		models = append(models, info)
+	}
+	return models, nil
+}
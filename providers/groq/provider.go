@@ -4,6 +4,7 @@
 package groq
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
@@ -30,6 +31,8 @@ type Provider struct {
 	collector      core.MetricsCollector
 	customHeaders  map[string]string
 	serviceTier    string // "on_demand" or "flex"
+	// allowUnknownModels skips catalog validation for Request.Model overrides
+	allowUnknownModels bool
 	mu             sync.RWMutex
 }
 
@@ -244,141 +247,142 @@ type ModelInfo struct {
 	IsDeprecated         bool     `json:"is_deprecated"`
 }
 
+// modelDB is the comprehensive model database based on Groq's 2025 catalog.
+var modelDB = map[string]ModelInfo{
+	// Featured OpenAI Models
+	"openai/gpt-oss-20b": {
+		ID: "openai/gpt-oss-20b", OwnedBy: "OpenAI", ContextWindow: 131072, MaxCompletionTokens: 65536,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"reasoning", "code", "search"}, PerformanceClass: "ultra-fast",
+	},
+	"openai/gpt-oss-120b": {
+		ID: "openai/gpt-oss-120b", OwnedBy: "OpenAI", ContextWindow: 131072, MaxCompletionTokens: 65536,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"reasoning", "code", "search"}, PerformanceClass: "fast",
+	},
+	
+	// Meta Llama Models - Production
+	"llama-3.3-70b-versatile": {
+		ID: "llama-3.3-70b-versatile", OwnedBy: "Meta", ContextWindow: 131072, MaxCompletionTokens: 32768,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"general", "reasoning", "code", "tools"}, PerformanceClass: "fast",
+	},
+	"llama-3.1-8b-instant": {
+		ID: "llama-3.1-8b-instant", OwnedBy: "Meta", ContextWindow: 131072, MaxCompletionTokens: 131072,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"general", "chat", "speed"}, PerformanceClass: "ultra-fast",
+	},
+	"llama3-8b-8192": {
+		ID: "llama3-8b-8192", OwnedBy: "Meta", ContextWindow: 8192, MaxCompletionTokens: 8192,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"general", "chat"}, PerformanceClass: "ultra-fast", IsDeprecated: true,
+	},
+	"llama3-70b-8192": {
+		ID: "llama3-70b-8192", OwnedBy: "Meta", ContextWindow: 8192, MaxCompletionTokens: 8192,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"general", "reasoning"}, PerformanceClass: "fast", IsDeprecated: true,
+	},
+	
+	// Vision Models - Llama 4 Series
+	"meta-llama/llama-4-scout-17b-16e-instruct": {
+		ID: "meta-llama/llama-4-scout-17b-16e-instruct", OwnedBy: "Meta", ContextWindow: 131072, MaxCompletionTokens: 8192,
+		SupportsVision: true, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"vision", "multimodal", "analysis"}, PerformanceClass: "fast",
+	},
+	"meta-llama/llama-4-maverick-17b-128e-instruct": {
+		ID: "meta-llama/llama-4-maverick-17b-128e-instruct", OwnedBy: "Meta", ContextWindow: 131072, MaxCompletionTokens: 8192,
+		SupportsVision: true, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"vision", "multimodal", "complex-analysis"}, PerformanceClass: "fast",
+	},
+	
+	// Reasoning Models
+	"deepseek-r1-distill-llama-70b": {
+		ID: "deepseek-r1-distill-llama-70b", OwnedBy: "DeepSeek / Meta", ContextWindow: 131072, MaxCompletionTokens: 131072,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"reasoning", "mathematics", "complex-problems"}, PerformanceClass: "balanced",
+	},
+	"moonshotai/kimi-k2-instruct": {
+		ID: "moonshotai/kimi-k2-instruct", OwnedBy: "Moonshot AI", ContextWindow: 131072, MaxCompletionTokens: 16384,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"reasoning", "tools", "agents"}, PerformanceClass: "ultra-fast",
+	},
+	"qwen/qwen3-32b": {
+		ID: "qwen/qwen3-32b", OwnedBy: "Alibaba Cloud", ContextWindow: 131072, MaxCompletionTokens: 40960,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"reasoning", "multilingual", "general"}, PerformanceClass: "fast",
+	},
+	
+	// Google Models
+	"gemma2-9b-it": {
+		ID: "gemma2-9b-it", OwnedBy: "Google", ContextWindow: 8192, MaxCompletionTokens: 8192,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"general", "chat", "instruction-following"}, PerformanceClass: "fast",
+	},
+	
+	// Audio Models
+	"whisper-large-v3": {
+		ID: "whisper-large-v3", OwnedBy: "OpenAI", ContextWindow: 448, MaxCompletionTokens: 448,
+		SupportsVision: false, SupportsTools: false, SupportsJSON: false, SupportsStreaming: false,
+		RecommendedFor: []string{"speech-to-text", "transcription", "translation"}, PerformanceClass: "fast",
+	},
+	"whisper-large-v3-turbo": {
+		ID: "whisper-large-v3-turbo", OwnedBy: "OpenAI", ContextWindow: 448, MaxCompletionTokens: 448,
+		SupportsVision: false, SupportsTools: false, SupportsJSON: false, SupportsStreaming: false,
+		RecommendedFor: []string{"speech-to-text", "transcription", "fast-audio"}, PerformanceClass: "ultra-fast",
+	},
+	
+	// Groq Systems
+	"compound-beta": {
+		ID: "compound-beta", OwnedBy: "Groq", ContextWindow: 131072, MaxCompletionTokens: 8192,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"experimental", "research", "beta-testing"}, PerformanceClass: "balanced",
+	},
+	"compound-beta-mini": {
+		ID: "compound-beta-mini", OwnedBy: "Groq", ContextWindow: 131072, MaxCompletionTokens: 8192,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"experimental", "fast-inference", "beta-testing"}, PerformanceClass: "ultra-fast",
+	},
+	
+	// Guard Models
+	"meta-llama/llama-guard-4-12b": {
+		ID: "meta-llama/llama-guard-4-12b", OwnedBy: "Meta", ContextWindow: 131072, MaxCompletionTokens: 1024,
+		SupportsVision: false, SupportsTools: false, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"safety", "content-moderation", "filtering"}, PerformanceClass: "fast",
+	},
+	"meta-llama/llama-prompt-guard-2-22m": {
+		ID: "meta-llama/llama-prompt-guard-2-22m", OwnedBy: "Meta", ContextWindow: 512, MaxCompletionTokens: 512,
+		SupportsVision: false, SupportsTools: false, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"prompt-injection-detection", "security"}, PerformanceClass: "ultra-fast",
+	},
+	"meta-llama/llama-prompt-guard-2-86m": {
+		ID: "meta-llama/llama-prompt-guard-2-86m", OwnedBy: "Meta", ContextWindow: 512, MaxCompletionTokens: 512,
+		SupportsVision: false, SupportsTools: false, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"prompt-injection-detection", "security"}, PerformanceClass: "ultra-fast",
+	},
+	
+	// Other Models
+	"allam-2-7b": {
+		ID: "allam-2-7b", OwnedBy: "SDAIA", ContextWindow: 4096, MaxCompletionTokens: 4096,
+		SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
+		RecommendedFor: []string{"arabic", "multilingual", "regional"}, PerformanceClass: "fast",
+	},
+	
+	// Text-to-Speech Models
+	"playai-tts": {
+		ID: "playai-tts", OwnedBy: "PlayAI", ContextWindow: 8192, MaxCompletionTokens: 8192,
+		SupportsVision: false, SupportsTools: false, SupportsJSON: false, SupportsStreaming: true,
+		RecommendedFor: []string{"text-to-speech", "voice-synthesis"}, PerformanceClass: "fast",
+	},
+	"playai-tts-arabic": {
+		ID: "playai-tts-arabic", OwnedBy: "PlayAI", ContextWindow: 8192, MaxCompletionTokens: 8192,
+		SupportsVision: false, SupportsTools: false, SupportsJSON: false, SupportsStreaming: true,
+		RecommendedFor: []string{"text-to-speech", "arabic", "voice-synthesis"}, PerformanceClass: "fast",
+	},
+}
+
+
 // getModelInfo returns detailed information about a specific model.
 func (p *Provider) getModelInfo(model string) ModelInfo {
-	// Comprehensive model database based on Groq's 2025 catalog
-	modelDB := map[string]ModelInfo{
-		// Featured OpenAI Models
-		"openai/gpt-oss-20b": {
-			ID: "openai/gpt-oss-20b", OwnedBy: "OpenAI", ContextWindow: 131072, MaxCompletionTokens: 65536,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"reasoning", "code", "search"}, PerformanceClass: "ultra-fast",
-		},
-		"openai/gpt-oss-120b": {
-			ID: "openai/gpt-oss-120b", OwnedBy: "OpenAI", ContextWindow: 131072, MaxCompletionTokens: 65536,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"reasoning", "code", "search"}, PerformanceClass: "fast",
-		},
-		
-		// Meta Llama Models - Production
-		"llama-3.3-70b-versatile": {
-			ID: "llama-3.3-70b-versatile", OwnedBy: "Meta", ContextWindow: 131072, MaxCompletionTokens: 32768,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"general", "reasoning", "code", "tools"}, PerformanceClass: "fast",
-		},
-		"llama-3.1-8b-instant": {
-			ID: "llama-3.1-8b-instant", OwnedBy: "Meta", ContextWindow: 131072, MaxCompletionTokens: 131072,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"general", "chat", "speed"}, PerformanceClass: "ultra-fast",
-		},
-		"llama3-8b-8192": {
-			ID: "llama3-8b-8192", OwnedBy: "Meta", ContextWindow: 8192, MaxCompletionTokens: 8192,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"general", "chat"}, PerformanceClass: "ultra-fast", IsDeprecated: true,
-		},
-		"llama3-70b-8192": {
-			ID: "llama3-70b-8192", OwnedBy: "Meta", ContextWindow: 8192, MaxCompletionTokens: 8192,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"general", "reasoning"}, PerformanceClass: "fast", IsDeprecated: true,
-		},
-		
-		// Vision Models - Llama 4 Series
-		"meta-llama/llama-4-scout-17b-16e-instruct": {
-			ID: "meta-llama/llama-4-scout-17b-16e-instruct", OwnedBy: "Meta", ContextWindow: 131072, MaxCompletionTokens: 8192,
-			SupportsVision: true, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"vision", "multimodal", "analysis"}, PerformanceClass: "fast",
-		},
-		"meta-llama/llama-4-maverick-17b-128e-instruct": {
-			ID: "meta-llama/llama-4-maverick-17b-128e-instruct", OwnedBy: "Meta", ContextWindow: 131072, MaxCompletionTokens: 8192,
-			SupportsVision: true, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"vision", "multimodal", "complex-analysis"}, PerformanceClass: "fast",
-		},
-		
-		// Reasoning Models
-		"deepseek-r1-distill-llama-70b": {
-			ID: "deepseek-r1-distill-llama-70b", OwnedBy: "DeepSeek / Meta", ContextWindow: 131072, MaxCompletionTokens: 131072,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"reasoning", "mathematics", "complex-problems"}, PerformanceClass: "balanced",
-		},
-		"moonshotai/kimi-k2-instruct": {
-			ID: "moonshotai/kimi-k2-instruct", OwnedBy: "Moonshot AI", ContextWindow: 131072, MaxCompletionTokens: 16384,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"reasoning", "tools", "agents"}, PerformanceClass: "ultra-fast",
-		},
-		"qwen/qwen3-32b": {
-			ID: "qwen/qwen3-32b", OwnedBy: "Alibaba Cloud", ContextWindow: 131072, MaxCompletionTokens: 40960,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"reasoning", "multilingual", "general"}, PerformanceClass: "fast",
-		},
-		
-		// Google Models
-		"gemma2-9b-it": {
-			ID: "gemma2-9b-it", OwnedBy: "Google", ContextWindow: 8192, MaxCompletionTokens: 8192,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"general", "chat", "instruction-following"}, PerformanceClass: "fast",
-		},
-		
-		// Audio Models
-		"whisper-large-v3": {
-			ID: "whisper-large-v3", OwnedBy: "OpenAI", ContextWindow: 448, MaxCompletionTokens: 448,
-			SupportsVision: false, SupportsTools: false, SupportsJSON: false, SupportsStreaming: false,
-			RecommendedFor: []string{"speech-to-text", "transcription", "translation"}, PerformanceClass: "fast",
-		},
-		"whisper-large-v3-turbo": {
-			ID: "whisper-large-v3-turbo", OwnedBy: "OpenAI", ContextWindow: 448, MaxCompletionTokens: 448,
-			SupportsVision: false, SupportsTools: false, SupportsJSON: false, SupportsStreaming: false,
-			RecommendedFor: []string{"speech-to-text", "transcription", "fast-audio"}, PerformanceClass: "ultra-fast",
-		},
-		
-		// Groq Systems
-		"compound-beta": {
-			ID: "compound-beta", OwnedBy: "Groq", ContextWindow: 131072, MaxCompletionTokens: 8192,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"experimental", "research", "beta-testing"}, PerformanceClass: "balanced",
-		},
-		"compound-beta-mini": {
-			ID: "compound-beta-mini", OwnedBy: "Groq", ContextWindow: 131072, MaxCompletionTokens: 8192,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"experimental", "fast-inference", "beta-testing"}, PerformanceClass: "ultra-fast",
-		},
-		
-		// Guard Models
-		"meta-llama/llama-guard-4-12b": {
-			ID: "meta-llama/llama-guard-4-12b", OwnedBy: "Meta", ContextWindow: 131072, MaxCompletionTokens: 1024,
-			SupportsVision: false, SupportsTools: false, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"safety", "content-moderation", "filtering"}, PerformanceClass: "fast",
-		},
-		"meta-llama/llama-prompt-guard-2-22m": {
-			ID: "meta-llama/llama-prompt-guard-2-22m", OwnedBy: "Meta", ContextWindow: 512, MaxCompletionTokens: 512,
-			SupportsVision: false, SupportsTools: false, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"prompt-injection-detection", "security"}, PerformanceClass: "ultra-fast",
-		},
-		"meta-llama/llama-prompt-guard-2-86m": {
-			ID: "meta-llama/llama-prompt-guard-2-86m", OwnedBy: "Meta", ContextWindow: 512, MaxCompletionTokens: 512,
-			SupportsVision: false, SupportsTools: false, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"prompt-injection-detection", "security"}, PerformanceClass: "ultra-fast",
-		},
-		
-		// Other Models
-		"allam-2-7b": {
-			ID: "allam-2-7b", OwnedBy: "SDAIA", ContextWindow: 4096, MaxCompletionTokens: 4096,
-			SupportsVision: false, SupportsTools: true, SupportsJSON: true, SupportsStreaming: true,
-			RecommendedFor: []string{"arabic", "multilingual", "regional"}, PerformanceClass: "fast",
-		},
-		
-		// Text-to-Speech Models
-		"playai-tts": {
-			ID: "playai-tts", OwnedBy: "PlayAI", ContextWindow: 8192, MaxCompletionTokens: 8192,
-			SupportsVision: false, SupportsTools: false, SupportsJSON: false, SupportsStreaming: true,
-			RecommendedFor: []string{"text-to-speech", "voice-synthesis"}, PerformanceClass: "fast",
-		},
-		"playai-tts-arabic": {
-			ID: "playai-tts-arabic", OwnedBy: "PlayAI", ContextWindow: 8192, MaxCompletionTokens: 8192,
-			SupportsVision: false, SupportsTools: false, SupportsJSON: false, SupportsStreaming: true,
-			RecommendedFor: []string{"text-to-speech", "arabic", "voice-synthesis"}, PerformanceClass: "fast",
-		},
-	}
-	
 	if info, exists := modelDB[model]; exists {
 		return info
 	}
@@ -419,6 +423,21 @@ func (p *Provider) isReasoningModel(model string) bool {
 	return false
 }
 
+// requestSetup returns how the provider prepares requests (see
+// core.PrepareRequest).
+func (p *Provider) requestSetup() core.RequestSetup {
+	return core.RequestSetup{
+		Provider:  "groq",
+		Validator: p,
+		Client:    p.client,
+	}
+}
+
+// RequestModel implements core.ModelResolver.
+func (p *Provider) RequestModel(ctx context.Context, req core.Request) string {
+	return p.getModel(core.PrepareModel(ctx, req, p.requestSetup()))
+}
+
 // getModel returns the model to use for the request.
 func (p *Provider) getModel(req core.Request) string {
 	if req.Model != "" {
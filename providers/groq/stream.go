@@ -18,6 +18,20 @@ import (
 
 // StreamText streams text generation with events.
 func (p *Provider) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	return core.StreamWithTimeout(ctx, req, func(ctx context.Context) (core.TextStream, error) {
+		return p.streamText(ctx, req)
+	})
+}
+
+// streamText implements StreamText within the request's timeouts.
+func (p *Provider) streamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	if p.apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
@@ -96,6 +110,7 @@ type groqTextStream struct {
 	mu       sync.Mutex
 	closed   bool
 	err      error
+	step     int         // Steps completed by in-stream tool execution
 	span     trace.Span  // For observability
 	system   string      // GenAI system identifier
 }
@@ -147,6 +162,7 @@ func (s *groqTextStream) processStream(ctx context.Context) {
 			s.sendEvent(core.Event{
 				Type:      core.EventError,
 				Err:       ctx.Err(),
+				ErrorCode: core.ErrorCodeOf(ctx.Err()),
 				Timestamp: time.Now(),
 			})
 			return
@@ -175,6 +191,7 @@ func (s *groqTextStream) processStream(ctx context.Context) {
 						s.sendEvent(core.Event{
 							Type:      core.EventError,
 							Err:       err,
+							ErrorCode: core.ErrorCodeOf(err),
 							Timestamp: time.Now(),
 						})
 						return
@@ -206,6 +223,7 @@ func (s *groqTextStream) processStream(ctx context.Context) {
 		s.sendEvent(core.Event{
 			Type:      core.EventError,
 			Err:       fmt.Errorf("stream reading error: %w", err),
+			ErrorCode: core.ErrorCodeOf(err),
 			Timestamp: time.Now(),
 		})
 	}
@@ -269,6 +287,7 @@ func (s *groqTextStream) processChunk(chunk streamChunk, currentToolCalls *[]too
 				s.sendEvent(core.Event{
 					Type:      core.EventError,
 					Err:       err,
+					ErrorCode: core.ErrorCodeOf(err),
 					Timestamp: time.Now(),
 				})
 			}
@@ -292,7 +311,16 @@ func (s *groqTextStream) processChunk(chunk streamChunk, currentToolCalls *[]too
 
 // executeToolCalls executes tool calls during streaming.
 func (s *groqTextStream) executeToolCalls(ctx context.Context, toolCalls []toolCall) error {
-	for _, tc := range toolCalls {
+	// Coalesce repeated calls to batch-capable tools into one execution each
+	calls := make([]core.ToolCall, len(toolCalls))
+	for i, tc := range toolCalls {
+		calls[i] = core.ToolCall{ID: tc.ID, Name: tc.Function.Name, Input: json.RawMessage(tc.Function.Arguments)}
+	}
+	batched := core.ExecuteBatchedCalls(ctx, s.tools, calls, map[string]interface{}{
+		"provider": "groq",
+	})
+
+	for i, tc := range toolCalls {
 		// Find the tool
 		var tool core.ToolHandle
 		for _, t := range s.tools {
@@ -323,11 +351,18 @@ func (s *groqTextStream) executeToolCalls(ctx context.Context, toolCalls []toolC
 			"provider": "groq",
 		}
 
-		result, err := tool.Exec(ctx, toolInput, meta)
+		var result any
+		var err error
+		if b, ok := batched[i]; ok {
+			result, err = b.Result, b.Err
+		} else {
+			result, err = tool.Exec(ctx, toolInput, meta)
+		}
 		if err != nil {
 			s.sendEvent(core.Event{
 				Type:      core.EventError,
 				Err:       fmt.Errorf("tool %s execution failed: %w", tc.Function.Name, err),
+				ErrorCode: core.ErrorCodeOf(err),
 				Timestamp: time.Now(),
 			})
 			continue
@@ -338,10 +373,19 @@ func (s *groqTextStream) executeToolCalls(ctx context.Context, toolCalls []toolC
 			Type:       core.EventToolResult,
 			ToolResult: result,
 			ToolName:   tc.Function.Name,
+			ToolID:     tc.ID,
 			Timestamp:  time.Now(),
 		})
 	}
 
+	// Close the step so consumers attribute the text streamed so far to it
+	s.step++
+	s.sendEvent(core.Event{
+		Type:       core.EventFinishStep,
+		StepNumber: s.step,
+		Timestamp:  time.Now(),
+	})
+
 	return nil
 }
 
@@ -377,8 +421,30 @@ type messageDelta struct {
 	ToolCalls []toolCall `json:"tool_calls,omitempty"`
 }
 
-// GenerateObject generates a structured object (not yet implemented for Groq).
+// GenerateObject generates a structured object conforming to the provided schema.
+// Outputs that fail JSON validation are retried up to req.MaxStructuredRetries times.
 func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.ObjectResult[any], error) {
+		return p.generateObjectWithRetry(ctx, req, schema)
+	})
+}
+
+// generateObjectWithRetry implements GenerateObject within the request's timeouts.
+func (p *Provider) generateObjectWithRetry(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.GenerateObjectWithRetry(ctx, req, func(ctx context.Context, req core.Request) (*core.ObjectResult[any], error) {
+		return p.generateObject(ctx, req, schema)
+	})
+}
+
+// generateObject performs a single structured output attempt.
+func (p *Provider) generateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
 	model := p.getModel(req)
 
 	// Convert ObjectResult to TextResult for observability compatibility
@@ -460,7 +526,7 @@ func (p *Provider) executeGenerateObject(ctx context.Context, req core.Request,
 		}
 	}
 	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		return nil, fmt.Errorf("parsing JSON response: %w", err)
+		return nil, core.NewValidationError(content, err)
 	}
 
 	return &core.ObjectResult[any]{
@@ -475,6 +541,20 @@ func (p *Provider) executeGenerateObject(ctx context.Context, req core.Request,
 
 // StreamObject streams generation of a structured object (placeholder implementation).
 func (p *Provider) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	return core.StreamObjectWithTimeout(ctx, req, func(ctx context.Context) (core.ObjectStream[any], error) {
+		return p.streamObject(ctx, req, schema)
+	})
+}
+
+// streamObject implements StreamObject within the request's timeouts.
+func (p *Provider) streamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	model := p.getModel(req)
 
 	// Use streaming GenAI observability wrapper
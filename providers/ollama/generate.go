@@ -14,6 +14,20 @@ import (
 // GenerateText implements the core.Provider interface for text generation.
 // It supports multi-step tool execution when tools are provided.
 func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.TextResult, error) {
+		return p.generateText(ctx, req)
+	})
+}
+
+// generateText implements GenerateText within the request's timeouts.
+func (p *Provider) generateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	// If tools are provided and multi-step execution is needed, use runner
 	if len(req.Tools) > 0 && req.StopWhen != nil {
 		return p.generateWithTools(ctx, req)
@@ -51,7 +65,8 @@ func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.Te
 			OutputTokens: completionTokens,
 			TotalTokens:  totalTokens,
 		},
-		Raw: chatResp,
+		Raw:       chatResp,
+		ModelUsed: chatResp.Model,
 	}
 
 	if chatResp.Message != nil {
@@ -62,6 +77,7 @@ func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.Te
 			step := core.Step{
 				Text:      result.Text,
 				ToolCalls: p.convertToolCallsFromAPI(chatResp.Message.ToolCalls),
+				Usage:     result.Usage,
 			}
 			result.Steps = append(result.Steps, step)
 		}
@@ -83,12 +99,13 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 	for stepCount < maxSteps {
 		// Make API request
 		chatReq, err := p.convertRequest(core.Request{
-			Model:       req.Model,
-			Messages:    messages,
-			Temperature: req.Temperature,
-			MaxTokens:   req.MaxTokens,
-			Tools:       req.Tools,
-			ToolChoice:  req.ToolChoice,
+			Model:        req.Model,
+			Messages:     messages,
+			Temperature:  req.Temperature,
+			MaxTokens:    core.OutputTokenLimit(req),
+			Tools:        req.Tools,
+			ToolChoice:   req.ToolChoice,
+			SpecificTool: req.SpecificTool,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("converting request for step %d: %w", stepCount, err)
@@ -114,9 +131,12 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 
 		// Update usage
 		promptTokens, completionTokens, _ := chatResp.GetUsage()
-		totalUsage.InputTokens += promptTokens
-		totalUsage.OutputTokens += completionTokens
-		totalUsage.TotalTokens += promptTokens + completionTokens
+		stepUsage := core.Usage{
+			InputTokens:  promptTokens,
+			OutputTokens: completionTokens,
+			TotalTokens:  promptTokens + completionTokens,
+		}
+		totalUsage.Add(stepUsage)
 
 		if chatResp.Message == nil {
 			break
@@ -128,6 +148,7 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 		step := core.Step{
 			Text:       text,
 			StepNumber: stepCount,
+			Usage:      stepUsage,
 		}
 
 		// Add assistant message to conversation
@@ -203,6 +224,11 @@ func (p *Provider) convertToolCallsFromAPI(toolCalls []toolCall) []core.ToolCall
 func (p *Provider) executeTools(ctx context.Context, tools []core.ToolHandle, calls []core.ToolCall, messages []core.Message) ([]core.ToolExecution, error) {
 	results := make([]core.ToolExecution, len(calls))
 
+	// Coalesce repeated calls to batch-capable tools into one execution each
+	batched := core.ExecuteBatchedCalls(ctx, tools, calls, map[string]interface{}{
+		"messages": messages,
+	})
+
 	// Execute tools sequentially for now (can be parallelized)
 	for i, call := range calls {
 		tool := p.findTool(tools, call.Name)
@@ -215,11 +241,17 @@ func (p *Provider) executeTools(ctx context.Context, tools []core.ToolHandle, ca
 			continue
 		}
 
-		// Execute tool
-		result, err := tool.Exec(ctx, call.Input, map[string]interface{}{
-			"messages": messages,
-			"call_id":  call.ID,
-		})
+		// Execute tool, using the batch result if the call was coalesced
+		var result any
+		var err error
+		if b, ok := batched[i]; ok {
+			result, err = b.Result, b.Err
+		} else {
+			result, err = tool.Exec(ctx, call.Input, map[string]interface{}{
+				"messages": messages,
+				"call_id":  call.ID,
+			})
+		}
 
 		if err != nil {
 			results[i] = core.ToolExecution{
@@ -249,26 +281,40 @@ func (p *Provider) findTool(tools []core.ToolHandle, name string) core.ToolHandl
 	return nil
 }
 
-// formatToolResult formats a tool execution result for the API.
+// formatToolResult formats a tool execution result for the API. The API has
+// no error flag, so errors are sent as {"error": "..."}.
 func (p *Provider) formatToolResult(result core.ToolExecution) string {
-	if result.Error != "" {
-		return fmt.Sprintf(`{"error": "%s"}`, result.Error)
-	}
+	return result.ToolResult().Content()
+}
+
+// GenerateObject generates a structured object conforming to the provided schema.
+// Outputs that fail JSON validation are retried up to req.MaxStructuredRetries times.
+func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.ObjectResult[any], error) {
+		return p.generateObjectWithRetry(ctx, req, schema)
+	})
+}
 
-	data, err := json.Marshal(result.Result)
+// generateObjectWithRetry implements GenerateObject within the request's timeouts.
+func (p *Provider) generateObjectWithRetry(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
 	if err != nil {
-		return fmt.Sprintf(`{"error": "failed to marshal result: %v"}`, err)
+		return nil, err
 	}
 
-	return string(data)
+	return core.GenerateObjectWithRetry(ctx, req, func(ctx context.Context, req core.Request) (*core.ObjectResult[any], error) {
+		return p.generateObject(ctx, req, schema)
+	})
 }
 
-// GenerateObject generates a structured object conforming to the provided schema.
-func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
-	// Convert schema to JSON Schema format
-	schemaBytes, err := json.Marshal(schema)
+// generateObject performs a single structured output attempt.
+func (p *Provider) generateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	// Convert schema to JSON Schema format, applying any ResponseSchema override
+	schemaBytes, err := core.MergeResponseSchema(schema, req.ResponseSchema)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling schema: %w", err)
+		return nil, err
 	}
 
 	// Prepare request with format
@@ -306,7 +352,7 @@ func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema
 	// Parse the JSON content
 	var value any
 	if err := json.Unmarshal([]byte(content), &value); err != nil {
-		return nil, fmt.Errorf("parsing JSON response: %w", err)
+		return nil, core.NewValidationError(content, err)
 	}
 
 	promptTokens, completionTokens, totalTokens := chatResp.GetUsage()
@@ -339,11 +385,11 @@ func (p *Provider) generateUsingGenerateAPI(ctx context.Context, req core.Reques
 		genReq.Options.Temperature = &req.Temperature
 	}
 	
-	if req.MaxTokens > 0 {
+	if maxTokens := core.OutputTokenLimit(req); maxTokens > 0 {
 		if genReq.Options == nil {
 			genReq.Options = &modelOptions{}
 		}
-		genReq.Options.NumPredict = &req.MaxTokens
+		genReq.Options.NumPredict = &maxTokens
 	}
 	
 	// Handle system message
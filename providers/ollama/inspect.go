@@ -0,0 +1,23 @@
+package ollama
+
+import (
+	"context"
+
+	"github.com/recera/gai/core"
+)
+
+// InspectRequest implements core.InspectableProvider. It checks req the way
+// GenerateText would, including converting it to the API format, without
+// sending it. Local models have no known limits, so only generic checks apply.
+func (p *Provider) InspectRequest(ctx context.Context, req core.Request) (core.InspectedRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return core.InspectedRequest{}, err
+	}
+	req.Model = core.ResolveModel(ctx, "ollama", req.Model)
+
+	inspected := core.InspectRequest(req, core.RequestConstraints{})
+	if _, err := p.convertRequest(req); err != nil {
+		inspected.ValidationErrors = append(inspected.ValidationErrors, err.Error())
+	}
+	return inspected, nil
+}
@@ -540,7 +540,7 @@ func (m *mockWeatherTool) Description() string {
 	return "Get current weather information for a location"
 }
 
-func (m *mockWeatherTool) InSchemaJSON() []byte {
+func (m *mockWeatherTool) InSchemaJSON() json.RawMessage {
 	return []byte(`{
 		"type": "object",
 		"properties": {
@@ -553,7 +553,7 @@ func (m *mockWeatherTool) InSchemaJSON() []byte {
 	}`)
 }
 
-func (m *mockWeatherTool) OutSchemaJSON() []byte {
+func (m *mockWeatherTool) OutSchemaJSON() json.RawMessage {
 	return []byte(`{
 		"type": "object",
 		"properties": {
@@ -134,6 +134,20 @@ func New(opts ...Option) *Provider {
 	return p
 }
 
+// requestSetup returns how the provider prepares requests (see
+// core.PrepareRequest).
+func (p *Provider) requestSetup() core.RequestSetup {
+	return core.RequestSetup{
+		Provider: "ollama",
+		Client:   p.client,
+	}
+}
+
+// RequestModel implements core.ModelResolver.
+func (p *Provider) RequestModel(ctx context.Context, req core.Request) string {
+	return p.getModel(core.PrepareModel(ctx, req, p.requestSetup()))
+}
+
 // getModel returns the model to use for the request.
 func (p *Provider) getModel(req core.Request) string {
 	if req.Model != "" {
@@ -170,16 +184,31 @@ func (p *Provider) convertRequest(req core.Request) (*chatRequest, error) {
 		chatReq = chatReq.WithTemperature(req.Temperature)
 	}
 	
-	if req.MaxTokens > 0 {
-		chatReq = chatReq.WithMaxTokens(req.MaxTokens)
+	if maxTokens := core.OutputTokenLimit(req); maxTokens > 0 {
+		chatReq = chatReq.WithMaxTokens(maxTokens)
 	}
 
-	// Convert tools if present
-	if len(req.Tools) > 0 {
+	// Convert tools if present. Ollama has no tool_choice parameter, so
+	// ToolNone is honored by not offering tools and ToolSpecific by offering
+	// only the named tool.
+	if len(req.Tools) > 0 && req.ToolChoice != core.ToolNone {
 		tools := p.convertTools(req.Tools)
+		if req.ToolChoice == core.ToolSpecific {
+			for _, t := range tools {
+				if t.Function.Name == req.SpecificTool {
+					tools = []chatTool{t}
+					break
+				}
+			}
+		}
 		chatReq = chatReq.WithTools(tools)
 	}
 
+	// Constrain the output to the response schema
+	if len(req.ResponseSchema) > 0 {
+		chatReq = chatReq.WithFormat(string(req.ResponseSchema))
+	}
+
 	// Handle provider-specific options
 	if opts, ok := req.ProviderOptions["ollama"].(map[string]interface{}); ok {
 		p.applyProviderOptions(chatReq, opts)
@@ -297,7 +326,13 @@ func (p *Provider) applyProviderOptions(req *chatRequest, opts map[string]interf
 func (p *Provider) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	var lastErr error
 
-	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+	maxRetries := p.maxRetries
+	policy, hasPolicy := core.RetryPolicyFromContext(ctx)
+	if hasPolicy {
+		maxRetries = policy.Retries(p.maxRetries)
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			// Exponential backoff with jitter
 			delay := p.retryDelay * time.Duration(1<<uint(attempt-1))
@@ -310,14 +345,28 @@ func (p *Provider) doRequest(ctx context.Context, method, path string, body inte
 
 		resp, err := p.doRequestOnce(ctx, method, path, body)
 		if err != nil {
+			if hasPolicy && !policy.ShouldRetry(err) {
+				return nil, err
+			}
 			lastErr = err
 			continue
 		}
 
+		// Per-request retry settings replace the status code checks below
+		if hasPolicy {
+			if attempt < maxRetries {
+				if retry, apiErr := policy.ShouldRetryResponse(resp, "ollama"); retry {
+					lastErr = apiErr
+					continue
+				}
+			}
+			return resp, nil
+		}
+
 		// Check if we should retry based on status code
 		if p.shouldRetry(resp.StatusCode) {
 			// Only retry if we have attempts left
-			if attempt < p.maxRetries {
+			if attempt < maxRetries {
 				bodyBytes, _ := io.ReadAll(resp.Body)
 				resp.Body.Close()
 				lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, bodyBytes)
@@ -328,7 +377,7 @@ func (p *Provider) doRequest(ctx context.Context, method, path string, body inte
 		return resp, nil
 	}
 
-	return nil, fmt.Errorf("after %d retries: %w", p.maxRetries, lastErr)
+	return nil, fmt.Errorf("after %d retries: %w", maxRetries, lastErr)
 }
 
 // doRequestOnce performs a single HTTP request.
@@ -402,6 +451,22 @@ func (p *Provider) ListModels(ctx context.Context) ([]model, error) {
 	return modelsResp.Models, nil
 }
 
+// Models implements core.ModelLister for the models installed on the server.
+// Ollama does not report context windows or capabilities in its model list,
+// so those fields are left empty.
+func (p *Provider) Models(ctx context.Context) ([]core.ModelInfo, error) {
+	installed, err := p.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]core.ModelInfo, 0, len(installed))
+	for _, m := range installed {
+		models = append(models, core.ModelInfo{ID: m.Name})
+	}
+	return models, nil
+}
+
 // IsModelAvailable checks if a specific model is available on the server.
 func (p *Provider) IsModelAvailable(ctx context.Context, modelName string) (bool, error) {
 	models, err := p.ListModels(ctx)
@@ -437,4 +502,58 @@ func (p *Provider) PullModel(ctx context.Context, modelName string) error {
 	// For now, we just start the pull. In a more complete implementation,
 	// you might want to stream the progress updates.
 	return nil
-}
\ No newline at end of file
+}
+// UnloadModel removes a model from memory immediately by sending a generate
+// request with an empty prompt and keep_alive set to "0". Use it to free
+// VRAM on memory-constrained devices without waiting for the keep-alive to
+// expire.
+func (p *Provider) UnloadModel(ctx context.Context, modelName string) error {
+	keepAlive := "0"
+	stream := false
+	unloadReq := &generateRequest{
+		Model:     modelName,
+		Stream:    &stream,
+		KeepAlive: &keepAlive,
+	}
+
+	resp, err := p.doRequest(ctx, "POST", "/api/generate", unloadReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return p.parseError(resp)
+	}
+	return nil
+}
+
+// ModelStatus reports whether a model is loaded into memory and how much
+// VRAM it uses, from the server's /api/ps endpoint.
+func (p *Provider) ModelStatus(ctx context.Context, modelName string) (ModelStatus, error) {
+	resp, err := p.doRequest(ctx, "GET", "/api/ps", nil)
+	if err != nil {
+		return ModelStatus{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ModelStatus{}, p.parseError(resp)
+	}
+
+	var running runningModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&running); err != nil {
+		return ModelStatus{}, fmt.Errorf("decoding running models response: %w", err)
+	}
+
+	for _, m := range running.Models {
+		name := m.Name
+		if name == "" {
+			name = m.Model
+		}
+		if name == modelName || strings.HasPrefix(name, modelName+":") {
+			return ModelStatus{Loaded: true, VRAM: m.SizeVRAM, ExpiresAt: m.ExpiresAt}, nil
+		}
+	}
+	return ModelStatus{}, nil
+}
@@ -524,6 +524,22 @@ func TestProvider_GenerateObject(t *testing.T) {
 	}
 }
 
+func TestProvider_convertRequest_ResponseSchema(t *testing.T) {
+	p := New()
+	schema := `{"type":"object","properties":{"answer":{"type":"string"}}}`
+
+	chatReq, err := p.convertRequest(core.Request{
+		Messages:       []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hi"}}}},
+		ResponseSchema: json.RawMessage(schema),
+	})
+	if err != nil {
+		t.Fatalf("convertRequest failed: %v", err)
+	}
+	if chatReq.Format != schema {
+		t.Errorf("expected format to be the response schema, got %q", chatReq.Format)
+	}
+}
+
 func TestProvider_ListModels(t *testing.T) {
 	mockResp := modelsResponse{
 		Models: []model{
@@ -616,6 +632,63 @@ func TestProvider_IsModelAvailable(t *testing.T) {
 	}
 }
 
+func TestProvider_UnloadModel(t *testing.T) {
+	var got generateRequest
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			t.Errorf("expected path /api/generate, got %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(generateResponse{Model: got.Model, Done: true})
+	})
+	defer server.Close()
+
+	p := New(WithBaseURL(server.URL))
+
+	if err := p.UnloadModel(context.Background(), "llama3.2"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Model != "llama3.2" || got.Prompt != "" {
+		t.Errorf("unexpected unload request: %+v", got)
+	}
+	if got.KeepAlive == nil || *got.KeepAlive != "0" {
+		t.Errorf("expected keep_alive \"0\", got %v", got.KeepAlive)
+	}
+}
+
+func TestProvider_ModelStatus(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := createMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/ps" {
+			t.Errorf("expected path /api/ps, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(runningModelsResponse{
+			Models: []runningModel{{Name: "llama3.2:latest", SizeVRAM: 2048, ExpiresAt: expires}},
+		})
+	})
+	defer server.Close()
+
+	p := New(WithBaseURL(server.URL))
+
+	status, err := p.ModelStatus(context.Background(), "llama3.2")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !status.Loaded || status.VRAM != 2048 || !status.ExpiresAt.Equal(expires) {
+		t.Errorf("unexpected status for loaded model: %+v", status)
+	}
+
+	status, err = p.ModelStatus(context.Background(), "mistral")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.Loaded {
+		t.Errorf("expected mistral not to be loaded, got %+v", status)
+	}
+}
+
 // Helper types for testing
 
 type mockToolHandle struct {
@@ -626,8 +699,8 @@ type mockToolHandle struct {
 
 func (m *mockToolHandle) Name() string                                           { return m.name }
 func (m *mockToolHandle) Description() string                                    { return m.desc }
-func (m *mockToolHandle) InSchemaJSON() []byte                                   { return m.inSchema }
-func (m *mockToolHandle) OutSchemaJSON() []byte                                  { return []byte(`{}`) }
+func (m *mockToolHandle) InSchemaJSON() json.RawMessage                          { return m.inSchema }
+func (m *mockToolHandle) OutSchemaJSON() json.RawMessage                         { return []byte(`{}`) }
 func (m *mockToolHandle) Exec(ctx context.Context, raw json.RawMessage, meta interface{}) (any, error) {
 	return map[string]string{"result": "mock"}, nil
 }
\ No newline at end of file
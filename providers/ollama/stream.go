@@ -38,6 +38,20 @@ type toolCallBuilder struct {
 
 // StreamText implements streaming text generation.
 func (p *Provider) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	return core.StreamWithTimeout(ctx, req, func(ctx context.Context) (core.TextStream, error) {
+		return p.streamText(ctx, req)
+	})
+}
+
+// streamText implements StreamText within the request's timeouts.
+func (p *Provider) streamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert request
 	chatReq, err := p.convertRequest(req)
 	if err != nil {
@@ -161,6 +175,7 @@ func (s *textStream) process(ctx context.Context, collector core.MetricsCollecto
 		s.sendEvent(core.Event{
 			Type:      core.EventError,
 			Err:       err,
+			ErrorCode: core.ErrorCodeOf(err),
 			Timestamp: time.Now(),
 		})
 		s.err = err
@@ -258,10 +273,24 @@ type objectStream struct {
 
 // StreamObject implements streaming generation of structured objects.
 func (p *Provider) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
-	// Convert schema to JSON Schema format
-	schemaBytes, err := json.Marshal(schema)
+	return core.StreamObjectWithTimeout(ctx, req, func(ctx context.Context) (core.ObjectStream[any], error) {
+		return p.streamObject(ctx, req, schema)
+	})
+}
+
+// streamObject implements StreamObject within the request's timeouts.
+func (p *Provider) streamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
 	if err != nil {
-		return nil, fmt.Errorf("marshaling schema: %w", err)
+		return nil, err
+	}
+
+	// Convert schema to JSON Schema format, applying any ResponseSchema override
+	schemaBytes, err := core.MergeResponseSchema(schema, req.ResponseSchema)
+	if err != nil {
+		return nil, err
 	}
 
 	// Prepare request with format
@@ -389,6 +418,7 @@ func (s *objectStream) processObject(ctx context.Context, collector core.Metrics
 		s.sendEvent(core.Event{
 			Type:      core.EventError,
 			Err:       err,
+			ErrorCode: core.ErrorCodeOf(err),
 			Timestamp: time.Now(),
 		})
 		s.err = err
@@ -431,6 +461,13 @@ func (s *objectStream) processObjectChunk(chunk chatResponse, totalUsage *core.U
 // StreamTextUsingGenerateAPI streams text using the /api/generate endpoint.
 // This is useful for models that work better with the generate API.
 func (p *Provider) StreamTextUsingGenerateAPI(ctx context.Context, req core.Request) (core.TextStream, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	// Build prompt from messages
 	prompt := p.buildPromptFromMessages(req.Messages)
 	
@@ -444,11 +481,11 @@ func (p *Provider) StreamTextUsingGenerateAPI(ctx context.Context, req core.Requ
 		genReq.Options.Temperature = &req.Temperature
 	}
 	
-	if req.MaxTokens > 0 {
+	if maxTokens := core.OutputTokenLimit(req); maxTokens > 0 {
 		if genReq.Options == nil {
 			genReq.Options = &modelOptions{}
 		}
-		genReq.Options.NumPredict = &req.MaxTokens
+		genReq.Options.NumPredict = &maxTokens
 	}
 	
 	// Handle system message
@@ -600,6 +637,7 @@ func (s *generateTextStream) processGenerate(ctx context.Context, collector core
 		s.sendEvent(core.Event{
 			Type:      core.EventError,
 			Err:       err,
+			ErrorCode: core.ErrorCodeOf(err),
 			Timestamp: time.Now(),
 		})
 		s.err = err
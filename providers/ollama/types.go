@@ -140,6 +140,30 @@ type model struct {
 	Details    map[string]string `json:"details"`
 }
 
+// runningModelsResponse represents the response from Ollama's /api/ps endpoint.
+type runningModelsResponse struct {
+	Models []runningModel `json:"models"`
+}
+
+// runningModel represents a model currently loaded into memory.
+type runningModel struct {
+	Name      string    `json:"name"`
+	Model     string    `json:"model"`
+	Size      int64     `json:"size"`
+	SizeVRAM  int64     `json:"size_vram"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ModelStatus reports whether a model is loaded into memory.
+type ModelStatus struct {
+	// Loaded is true while the model is held in memory
+	Loaded bool
+	// VRAM is the number of bytes of GPU memory the model occupies
+	VRAM int64
+	// ExpiresAt is when the model will be unloaded if it stays idle
+	ExpiresAt time.Time
+}
+
 // errorResponse represents an error response from Ollama.
 type errorResponse struct {
 	Error string `json:"error"`
@@ -248,10 +248,10 @@ type mockTool struct {
 	schema []byte
 }
 
-func (m *mockTool) Name() string               { return m.name }
-func (m *mockTool) Description() string        { return m.desc }
-func (m *mockTool) InSchemaJSON() []byte       { return m.schema }
-func (m *mockTool) OutSchemaJSON() []byte      { return m.schema }
+func (m *mockTool) Name() string                   { return m.name }
+func (m *mockTool) Description() string            { return m.desc }
+func (m *mockTool) InSchemaJSON() json.RawMessage  { return m.schema }
+func (m *mockTool) OutSchemaJSON() json.RawMessage { return m.schema }
 func (m *mockTool) Exec(ctx context.Context, raw json.RawMessage, meta interface{}) (any, error) {
 	return map[string]string{"result": "ok"}, nil
 }
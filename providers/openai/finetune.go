@@ -0,0 +1,203 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// fineTuneJob is the OpenAI fine-tuning job object.
+type fineTuneJob struct {
+	ID              string              `json:"id"`
+	Model           string              `json:"model"`
+	Status          string              `json:"status"`
+	FineTunedModel  *string             `json:"fine_tuned_model"`
+	TrainingFile    string              `json:"training_file"`
+	ValidationFile  *string             `json:"validation_file"`
+	Hyperparameters fineTuneHyperparams `json:"hyperparameters"`
+	CreatedAt       int64               `json:"created_at"`
+	FinishedAt      *int64              `json:"finished_at"`
+	Error           *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// fineTuneHyperparams holds hyperparameters, which OpenAI reports either as
+// numbers or as "auto".
+type fineTuneHyperparams struct {
+	NEpochs                autoNumber `json:"n_epochs,omitempty"`
+	BatchSize              autoNumber `json:"batch_size,omitempty"`
+	LearningRateMultiplier autoNumber `json:"learning_rate_multiplier,omitempty"`
+}
+
+// autoNumber is a hyperparameter value; "auto" decodes as zero.
+type autoNumber float64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *autoNumber) UnmarshalJSON(data []byte) error {
+	var f float64
+	if err := json.Unmarshal(data, &f); err != nil {
+		*n = 0
+		return nil
+	}
+	*n = autoNumber(f)
+	return nil
+}
+
+// fineTuneRequest is the body of a create job request.
+type fineTuneRequest struct {
+	Model           string               `json:"model"`
+	TrainingFile    string               `json:"training_file"`
+	ValidationFile  string               `json:"validation_file,omitempty"`
+	Hyperparameters *fineTuneHyperparams `json:"hyperparameters,omitempty"`
+}
+
+// CreateFineTuneJob implements core.FineTuneProvider.
+func (p *Provider) CreateFineTuneJob(ctx context.Context, opts core.FineTuneOpts) (core.FineTuneJob, error) {
+	if opts.TrainingFile == "" {
+		return core.FineTuneJob{}, core.NewError(core.ErrorInvalidRequest, "training file is required",
+			core.WithProvider("openai"))
+	}
+	if opts.BaseModel == "" {
+		return core.FineTuneJob{}, core.NewError(core.ErrorInvalidRequest, "base model is required",
+			core.WithProvider("openai"))
+	}
+
+	body := fineTuneRequest{
+		Model:          opts.BaseModel,
+		TrainingFile:   opts.TrainingFile,
+		ValidationFile: opts.ValidationFile,
+	}
+	if hp := opts.Hyperparameters; hp != (core.FineTuneHyperparams{}) {
+		body.Hyperparameters = &fineTuneHyperparams{
+			NEpochs:                autoNumber(hp.Epochs),
+			BatchSize:              autoNumber(hp.BatchSize),
+			LearningRateMultiplier: autoNumber(hp.LearningRateMultiplier),
+		}
+	}
+
+	// Creating a job is not idempotent: retrying after a timeout or a 5xx
+	// could start a second, billed job, so the request is sent only once
+	resp, err := p.doRequestOnce(ctx, "POST", "/fine_tuning/jobs", body)
+	if err != nil {
+		return core.FineTuneJob{}, err
+	}
+	var job fineTuneJob
+	if err := p.decodeFineTuneResponse(resp, &job); err != nil {
+		return core.FineTuneJob{}, err
+	}
+	return job.toCore(), nil
+}
+
+// GetFineTuneJob implements core.FineTuneProvider.
+func (p *Provider) GetFineTuneJob(ctx context.Context, id string) (core.FineTuneJob, error) {
+	var job fineTuneJob
+	if err := p.doFineTuneRequest(ctx, "GET", "/fine_tuning/jobs/"+url.PathEscape(id), nil, &job); err != nil {
+		return core.FineTuneJob{}, err
+	}
+	return job.toCore(), nil
+}
+
+// ListFineTuneJobs implements core.FineTuneProvider, following pagination
+// until every job has been returned.
+func (p *Provider) ListFineTuneJobs(ctx context.Context) ([]core.FineTuneJob, error) {
+	var jobs []core.FineTuneJob
+	after := ""
+	for {
+		path := "/fine_tuning/jobs?limit=100"
+		if after != "" {
+			path += "&after=" + url.QueryEscape(after)
+		}
+
+		var page struct {
+			Data    []fineTuneJob `json:"data"`
+			HasMore bool          `json:"has_more"`
+		}
+		if err := p.doFineTuneRequest(ctx, "GET", path, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, job := range page.Data {
+			jobs = append(jobs, job.toCore())
+		}
+		if !page.HasMore || len(page.Data) == 0 {
+			return jobs, nil
+		}
+		after = page.Data[len(page.Data)-1].ID
+	}
+}
+
+// CancelFineTuneJob implements core.FineTuneProvider.
+func (p *Provider) CancelFineTuneJob(ctx context.Context, id string) error {
+	return p.doFineTuneRequest(ctx, "POST", "/fine_tuning/jobs/"+url.PathEscape(id)+"/cancel", nil, nil)
+}
+
+// doFineTuneRequest sends a fine-tuning API request, retrying failures, and
+// decodes the response into out when it is not nil.
+func (p *Provider) doFineTuneRequest(ctx context.Context, method, path string, body, out interface{}) error {
+	resp, err := p.doRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return p.decodeFineTuneResponse(resp, out)
+}
+
+// decodeFineTuneResponse closes resp after decoding it into out when it is
+// not nil, or returns the API error it reports.
+func (p *Provider) decodeFineTuneResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return p.parseError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding fine-tuning response: %w", err)
+	}
+	return nil
+}
+
+// toCore converts an OpenAI job to a core.FineTuneJob.
+func (j fineTuneJob) toCore() core.FineTuneJob {
+	job := core.FineTuneJob{
+		ID:           j.ID,
+		Status:       j.Status,
+		BaseModel:    j.Model,
+		TrainingFile: j.TrainingFile,
+		Hyperparameters: core.FineTuneHyperparams{
+			Epochs:                 int(j.Hyperparameters.NEpochs),
+			BatchSize:              int(j.Hyperparameters.BatchSize),
+			LearningRateMultiplier: float64(j.Hyperparameters.LearningRateMultiplier),
+		},
+	}
+	if j.FineTunedModel != nil {
+		job.FineTunedModel = *j.FineTunedModel
+	}
+	if j.ValidationFile != nil {
+		job.ValidationFile = *j.ValidationFile
+	}
+	if j.CreatedAt > 0 {
+		job.CreatedAt = time.Unix(j.CreatedAt, 0)
+	}
+	if j.FinishedAt != nil {
+		job.FinishedAt = time.Unix(*j.FinishedAt, 0)
+	}
+	if j.Error != nil && j.Error.Message != "" {
+		job.Error = j.Error.Message
+	}
+	return job
+}
+
+// isFineTunedModel reports whether model names an OpenAI fine-tuned model,
+// such as "ft:gpt-4o-mini-2024-07-18:my-org::abc123".
+func isFineTunedModel(model string) bool {
+	return strings.HasPrefix(model, "ft:")
+}
@@ -13,6 +13,23 @@ import (
 // GenerateText implements the core.Provider interface for text generation.
 // It supports multi-step tool execution when tools are provided.
 func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.TextResult, error) {
+		return p.generateText(ctx, req)
+	})
+}
+
+// generateText implements GenerateText within the request's timeouts.
+func (p *Provider) generateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest). Request.N > 1 is returned
+	// as TextResult.Alternatives.
+	setup := p.requestSetup()
+	setup.MultipleCompletions = true
+	ctx, req, err := core.PrepareRequest(ctx, req, setup)
+	if err != nil {
+		return nil, err
+	}
+
 	model := p.getModel(req)
 
 	// Use comprehensive GenAI observability wrapper
@@ -25,6 +42,10 @@ func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.Te
 func (p *Provider) executeGenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
 	// If tools are provided and multi-step execution is needed, use runner
 	if len(req.Tools) > 0 && req.StopWhen != nil {
+		// Each step continues a single conversation
+		if err := core.CheckSingleCompletion(req, "openai"); err != nil {
+			return nil, err
+		}
 		return p.generateWithTools(ctx, req)
 	}
 
@@ -56,43 +77,57 @@ func (p *Provider) executeGenerateText(ctx context.Context, req core.Request) (*
 			OutputTokens: apiResp.Usage.CompletionTokens,
 			TotalTokens:  apiResp.Usage.TotalTokens,
 		},
-		Raw: apiResp,
+		Raw:            apiResp,
+		RejectedTokens: apiResp.Usage.rejectedPredictionTokens(),
+		ModelUsed:      apiResp.Model,
 	}
-
 	if len(apiResp.Choices) > 0 {
-		choice := apiResp.Choices[0]
-		
-		// Extract text content
-		switch content := choice.Message.Content.(type) {
-		case string:
-			result.Text = content
-		case []interface{}:
-			// Handle multipart content
-			text := ""
-			for _, part := range content {
-				if p, ok := part.(map[string]interface{}); ok {
-					if p["type"] == "text" {
-						if t, ok := p["text"].(string); ok {
-							text += t
-						}
+		p.applyChoice(result, apiResp.Choices[0])
+
+		// Additional completions requested with Request.N
+		for _, choice := range apiResp.Choices[1:] {
+			var alt core.TextResult
+			p.applyChoice(&alt, choice)
+			result.Alternatives = append(result.Alternatives, alt)
+		}
+	}
+
+	return result, nil
+}
+
+// applyChoice sets the text, tool calls and log probabilities of a
+// completion choice on result.
+func (p *Provider) applyChoice(result *core.TextResult, choice choice) {
+	result.TokenLogprobs = choice.LogProbs.toCore()
+
+	// Extract text content
+	switch content := choice.Message.Content.(type) {
+	case string:
+		result.Text = content
+	case []interface{}:
+		// Handle multipart content
+		text := ""
+		for _, part := range content {
+			if p, ok := part.(map[string]interface{}); ok {
+				if p["type"] == "text" {
+					if t, ok := p["text"].(string); ok {
+						text += t
 					}
 				}
 			}
-			result.Text = text
 		}
+		result.Text = text
+	}
 
-		// Handle tool calls if present
-		if len(choice.Message.ToolCalls) > 0 {
-			step := core.Step{
-				Text: result.Text,
-				ToolCalls: p.convertToolCallsFromAPI(choice.Message.ToolCalls),
-			}
-			result.Steps = append(result.Steps, step)
+	// Handle tool calls if present
+	if len(choice.Message.ToolCalls) > 0 {
+		step := core.Step{
+			Text: result.Text,
+			ToolCalls: p.convertToolCallsFromAPI(choice.Message.ToolCalls),
+			Usage: result.Usage,
 		}
+		result.Steps = append(result.Steps, step)
 	}
-
-
-	return result, nil
 }
 
 // generateWithTools handles multi-step execution with tools.
@@ -102,18 +137,26 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 	
 	var steps []core.Step
 	var totalUsage core.Usage
+	var modelUsed string
 	stepCount := 0
 	maxSteps := 10 // Safety limit
 
 	for stepCount < maxSteps {
+		// Forcing a tool call on every step would never let the model answer
+		toolChoice := req.ToolChoice
+		if stepCount > 0 && (toolChoice == core.ToolRequired || toolChoice == core.ToolSpecific) {
+			toolChoice = core.ToolAuto
+		}
+
 		// Make API request
 		apiReq, err := p.convertRequest(core.Request{
-			Model:       req.Model,
-			Messages:    messages,
-			Temperature: req.Temperature,
-			MaxTokens:   req.MaxTokens,
-			Tools:       req.Tools,
-			ToolChoice:  req.ToolChoice,
+			Model:        req.Model,
+			Messages:     messages,
+			Temperature:  req.Temperature,
+			MaxTokens:    core.OutputTokenLimit(req),
+			Tools:        req.Tools,
+			ToolChoice:   toolChoice,
+			SpecificTool: req.SpecificTool,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("converting request for step %d: %w", stepCount, err)
@@ -135,9 +178,13 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 		}
 
 		// Update usage
-		totalUsage.InputTokens += apiResp.Usage.PromptTokens
-		totalUsage.OutputTokens += apiResp.Usage.CompletionTokens
-		totalUsage.TotalTokens += apiResp.Usage.TotalTokens
+		stepUsage := core.Usage{
+			InputTokens:  apiResp.Usage.PromptTokens,
+			OutputTokens: apiResp.Usage.CompletionTokens,
+			TotalTokens:  apiResp.Usage.TotalTokens,
+		}
+		totalUsage.Add(stepUsage)
+		modelUsed = apiResp.Model
 
 		if len(apiResp.Choices) == 0 {
 			break
@@ -166,18 +213,22 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 		step := core.Step{
 			Text:       text,
 			StepNumber: stepCount,
+			Usage:      stepUsage,
+		}
+
+		if len(choice.Message.ToolCalls) > 0 {
+			step.ToolCalls = p.convertToolCallsFromAPI(choice.Message.ToolCalls)
 		}
 
 		// Add assistant message to conversation
 		messages = append(messages, core.Message{
 			Role: core.Assistant,
 			Parts: []core.Part{core.Text{Text: text}},
+			ToolCalls: step.ToolCalls,
 		})
 
 		// Handle tool calls
-		if len(choice.Message.ToolCalls) > 0 {
-			step.ToolCalls = p.convertToolCallsFromAPI(choice.Message.ToolCalls)
-			
+		if len(step.ToolCalls) > 0 {
 			// Execute tools
 			toolResults, err := p.executeTools(ctx, req.Tools, step.ToolCalls, messages)
 			if err != nil {
@@ -192,6 +243,7 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 					Parts: []core.Part{
 						core.Text{Text: p.formatToolResult(result)},
 					},
+					ToolCallID: result.ID,
 				}
 				messages = append(messages, toolMsg)
 			}
@@ -218,20 +270,23 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 	}
 
 	return &core.TextResult{
-		Text:  finalText,
-		Steps: steps,
-		Usage: totalUsage,
+		Text:      finalText,
+		Steps:     steps,
+		Usage:     totalUsage,
+		ModelUsed: modelUsed,
 	}, nil
 }
 
 // convertToolCallsFromAPI converts OpenAI tool calls to core format.
 func (p *Provider) convertToolCallsFromAPI(toolCalls []toolCall) []core.ToolCall {
 	result := make([]core.ToolCall, 0, len(toolCalls))
-	for _, tc := range toolCalls {
+	for i, tc := range toolCalls {
 		result = append(result, core.ToolCall{
 			ID:    tc.ID,
 			Name:  tc.Function.Name,
 			Input: json.RawMessage(tc.Function.Arguments),
+			// Position among the message's parallel tool calls
+			ProviderMeta: map[string]any{"openai.index": i},
 		})
 	}
 	return result
@@ -241,6 +296,11 @@ func (p *Provider) convertToolCallsFromAPI(toolCalls []toolCall) []core.ToolCall
 func (p *Provider) executeTools(ctx context.Context, tools []core.ToolHandle, calls []core.ToolCall, messages []core.Message) ([]core.ToolExecution, error) {
 	results := make([]core.ToolExecution, len(calls))
 	
+	// Coalesce repeated calls to batch-capable tools into one execution each
+	batched := core.ExecuteBatchedCalls(ctx, tools, calls, map[string]interface{}{
+		"messages": messages,
+	})
+	
 	// Execute tools sequentially for now (can be parallelized)
 	for i, call := range calls {
 		tool := p.findTool(tools, call.Name)
@@ -253,11 +313,17 @@ func (p *Provider) executeTools(ctx context.Context, tools []core.ToolHandle, ca
 			continue
 		}
 
-		// Execute tool
-		result, err := tool.Exec(ctx, call.Input, map[string]interface{}{
-			"messages": messages,
-			"call_id":  call.ID,
-		})
+		// Execute tool, using the batch result if the call was coalesced
+		var result any
+		var err error
+		if b, ok := batched[i]; ok {
+			result, err = b.Result, b.Err
+		} else {
+			result, err = tool.Exec(ctx, call.Input, map[string]interface{}{
+				"messages": messages,
+				"call_id":  call.ID,
+			})
+		}
 		
 		if err != nil {
 			results[i] = core.ToolExecution{
@@ -287,22 +353,36 @@ func (p *Provider) findTool(tools []core.ToolHandle, name string) core.ToolHandl
 	return nil
 }
 
-// formatToolResult formats a tool execution result for the API.
+// formatToolResult formats a tool execution result for the API. The API has
+// no error flag, so errors are sent as {"error": "..."}.
 func (p *Provider) formatToolResult(result core.ToolExecution) string {
-	if result.Error != "" {
-		return fmt.Sprintf(`{"error": "%s"}`, result.Error)
-	}
-	
-	data, err := json.Marshal(result.Result)
-	if err != nil {
-		return fmt.Sprintf(`{"error": "failed to marshal result: %v"}`, err)
-	}
-	
-	return string(data)
+	return result.ToolResult().Content()
 }
 
 // GenerateObject generates a structured object conforming to the provided schema.
+// Outputs that fail JSON validation are retried up to req.MaxStructuredRetries times.
 func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.ObjectResult[any], error) {
+		return p.generateObjectWithRetry(ctx, req, schema)
+	})
+}
+
+// generateObjectWithRetry implements GenerateObject within the request's timeouts.
+func (p *Provider) generateObjectWithRetry(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.GenerateObjectWithRetry(ctx, req, func(ctx context.Context, req core.Request) (*core.ObjectResult[any], error) {
+		return p.generateObject(ctx, req, schema)
+	})
+}
+
+// generateObject performs a single structured output attempt.
+func (p *Provider) generateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
 	model := p.getModel(req)
 
 	// Convert ObjectResult to TextResult for observability compatibility
@@ -340,26 +420,10 @@ func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema
 
 // executeGenerateObject handles the actual object generation logic (extracted for observability)
 func (p *Provider) executeGenerateObject(ctx context.Context, req core.Request, schema any) (*core.ObjectResult[any], error) {
-	// Convert schema to JSON Schema format
-	schemaBytes, err := json.Marshal(schema)
+	// Prepare request with response format for structured output
+	apiReq, err := p.convertObjectRequest(req, schema)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling schema: %w", err)
-	}
-
-	// Prepare request with response format
-	apiReq, err := p.convertRequest(req)
-	if err != nil {
-		return nil, fmt.Errorf("converting request: %w", err)
-	}
-
-	// Set response format for structured output
-	apiReq.ResponseFormat = &responseFormat{
-		Type: "json_schema",
-		JSONSchema: &jsonSchemaFormat{
-			Name:   "response",
-			Schema: schemaBytes,
-			Strict: true,
-		},
+		return nil, err
 	}
 
 	// Make API request
@@ -396,7 +460,7 @@ func (p *Provider) executeGenerateObject(ctx context.Context, req core.Request,
 	// Parse the JSON content
 	var value any
 	if err := json.Unmarshal([]byte(content), &value); err != nil {
-		return nil, fmt.Errorf("parsing JSON response: %w", err)
+		return nil, core.NewValidationError(content, err)
 	}
 
 	result := &core.ObjectResult[any]{
@@ -0,0 +1,30 @@
+package openai
+
+import (
+	"context"
+
+	"github.com/recera/gai/core"
+)
+
+// InspectRequest implements core.InspectableProvider. It checks req the way
+// GenerateText would, including validating the model and converting the
+// request to the API format, without sending it.
+func (p *Provider) InspectRequest(ctx context.Context, req core.Request) (core.InspectedRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return core.InspectedRequest{}, err
+	}
+	req.Model = core.ResolveModel(ctx, "openai", req.Model)
+	// Choose the model when the request asks for automatic selection
+	req = core.SelectModel(req, p.modelSelector)
+
+	inspected := core.InspectRequest(req, core.RequestConstraints{MaxTemperature: 2})
+	if req.Model != "" {
+		if err := p.ValidateModel(req.Model); err != nil {
+			inspected.ValidationErrors = append(inspected.ValidationErrors, err.Error())
+		}
+	}
+	if _, err := p.convertRequest(req); err != nil {
+		inspected.ValidationErrors = append(inspected.ValidationErrors, err.Error())
+	}
+	return inspected, nil
+}
@@ -0,0 +1,177 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/recera/gai/core"
+)
+
+// Named model constants for the OpenAI Chat Completions API.
+const (
+	// GPT-5 series (reasoning)
+	GPT5     = "gpt-5"
+	GPT5Mini = "gpt-5-mini"
+	GPT5Nano = "gpt-5-nano"
+
+	// GPT-4.1 series
+	GPT41     = "gpt-4.1"
+	GPT41Mini = "gpt-4.1-mini"
+	GPT41Nano = "gpt-4.1-nano"
+
+	// GPT-4o series
+	GPT4o     = "gpt-4o"
+	GPT4oMini = "gpt-4o-mini"
+
+	// Legacy GPT-4 and GPT-3.5 models
+	GPT4Turbo  = "gpt-4-turbo"
+	GPT4       = "gpt-4"
+	GPT35Turbo = "gpt-3.5-turbo"
+
+	// o-series reasoning models
+	O1     = "o1"
+	O1Mini = "o1-mini"
+	O3     = "o3"
+	O3Mini = "o3-mini"
+	O4Mini = "o4-mini"
+)
+
+// knownModels is the set of models accepted by ValidateModel.
+var knownModels = map[string]struct{}{
+	GPT5:       {},
+	GPT5Mini:   {},
+	GPT5Nano:   {},
+	GPT41:      {},
+	GPT41Mini:  {},
+	GPT41Nano:  {},
+	GPT4o:      {},
+	GPT4oMini:  {},
+	GPT4Turbo:  {},
+	GPT4:       {},
+	GPT35Turbo: {},
+	O1:         {},
+	O1Mini:     {},
+	O3:         {},
+	O3Mini:     {},
+	O4Mini:     {},
+}
+
+// chatCapabilities are the capabilities of current chat models.
+var chatCapabilities = []core.ModelCapability{core.CapabilityToolCalling, core.CapabilityVision, core.CapabilityJSONMode}
+
+// modelDetails holds the context window and capabilities of the known models.
+var modelDetails = map[string]core.ModelInfo{
+	GPT5:       {ContextWindow: 400000, Capabilities: chatCapabilities},
+	GPT5Mini:   {ContextWindow: 400000, Capabilities: chatCapabilities},
+	GPT5Nano:   {ContextWindow: 400000, Capabilities: chatCapabilities},
+	GPT41:      {ContextWindow: 1047576, Capabilities: chatCapabilities},
+	GPT41Mini:  {ContextWindow: 1047576, Capabilities: chatCapabilities},
+	GPT41Nano:  {ContextWindow: 1047576, Capabilities: chatCapabilities},
+	GPT4o:      {ContextWindow: 128000, Capabilities: chatCapabilities},
+	GPT4oMini:  {ContextWindow: 128000, Capabilities: chatCapabilities},
+	GPT4Turbo:  {ContextWindow: 128000, Capabilities: chatCapabilities},
+	GPT4:       {ContextWindow: 8192, Capabilities: []core.ModelCapability{core.CapabilityToolCalling}},
+	GPT35Turbo: {ContextWindow: 16385, Capabilities: []core.ModelCapability{core.CapabilityToolCalling, core.CapabilityJSONMode}},
+	O1:         {ContextWindow: 200000, Capabilities: chatCapabilities},
+	O1Mini:     {ContextWindow: 128000},
+	O3:         {ContextWindow: 200000, Capabilities: chatCapabilities},
+	O3Mini:     {ContextWindow: 200000, Capabilities: []core.ModelCapability{core.CapabilityToolCalling, core.CapabilityJSONMode}},
+	O4Mini:     {ContextWindow: 200000, Capabilities: chatCapabilities},
+}
+
+// Models implements core.ModelLister using the /models endpoint. Models
+// outside the built-in list, such as fine-tunes and embedding models, are
+// returned without a context window or capabilities.
+func (p *Provider) Models(ctx context.Context) ([]core.ModelInfo, error) {
+	resp, err := p.doRequest(ctx, "GET", "/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, p.parseError(resp)
+	}
+
+	var modelsResp struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
+		return nil, fmt.Errorf("decoding models response: %w", err)
+	}
+
+	models := make([]core.ModelInfo, 0, len(modelsResp.Data))
+	for _, m := range modelsResp.Data {
+		info := modelDetails[m.ID]
+		info.ID = m.ID
+		info.Capabilities = append([]core.ModelCapability(nil), info.Capabilities...)
+		models = append(models, info)
+	}
+	return models, nil
+}
+
+// WithAllowUnknownModels permits model names outside the built-in constant list.
+// Enable this for new, preview, or fine-tuned models that have not yet been added.
+func WithAllowUnknownModels(allow bool) Option {
+	return func(p *Provider) {
+		p.allowUnknownModels = allow
+	}
+}
+
+// WithModelSelector replaces DefaultModelSelector as the way models are
+// chosen for requests with Model set to core.AutoModel or AutoSelectModel set.
+// A nil selector restores the default.
+//
+// Example:
+//
+//	provider := openai.New(openai.WithModelSelector(func(req core.Request, needs core.ModelNeeds) string {
+//		if needs.Tools {
+//			return openai.GPT41
+//		}
+//		return openai.DefaultModelSelector(req, needs)
+//	}))
+func WithModelSelector(selector core.ModelSelector) Option {
+	return func(p *Provider) {
+		if selector == nil {
+			selector = DefaultModelSelector
+		}
+		p.modelSelector = selector
+	}
+}
+
+// DefaultModelSelector chooses gpt-4.1 for inputs above
+// core.LongContextThreshold, gpt-4o for requests with tools or images, and
+// gpt-4o-mini otherwise.
+func DefaultModelSelector(req core.Request, needs core.ModelNeeds) string {
+	switch {
+	case needs.LongContext:
+		return GPT41
+	case needs.Tools || needs.Vision:
+		return GPT4o
+	default:
+		return GPT4oMini
+	}
+}
+
+// ValidateModel implements core.ModelValidator.
+// It returns an AIError with ErrorUnknownModel for names not in the known model list,
+// unless unknown models were allowed with WithAllowUnknownModels. Fine-tuned
+// model IDs ("ft:...") are always valid.
+func (p *Provider) ValidateModel(model string) error {
+	if model == "" {
+		return core.NewError(core.ErrorUnknownModel, "model name is empty",
+			core.WithProvider("openai"))
+	}
+	// Fine-tuned models (see CreateFineTuneJob) are always accepted
+	if _, ok := knownModels[model]; ok || p.allowUnknownModels || isFineTunedModel(model) {
+		return nil
+	}
+	return core.NewError(core.ErrorUnknownModel,
+		fmt.Sprintf("unknown model %q (use WithAllowUnknownModels to permit it)", model),
+		core.WithProvider("openai"),
+		core.WithModel(model))
+}
@@ -33,7 +33,13 @@ type Provider struct {
 	org        string
 	project    string
 	collector  core.MetricsCollector
-	mu         sync.RWMutex
+	// allowUnknownModels skips known-model validation for Request.Model overrides
+	allowUnknownModels bool
+	// strictOutputs enforces object schemas with Structured Outputs strict mode
+	strictOutputs bool
+	// modelSelector chooses the model for requests with Model "auto"
+	modelSelector core.ModelSelector
+	mu            sync.RWMutex
 }
 
 // Option configures the OpenAI provider.
@@ -109,6 +115,9 @@ func New(opts ...Option) *Provider {
 		model:      "gpt-4o-mini",
 		maxRetries: 3,
 		retryDelay: 100 * time.Millisecond,
+
+		strictOutputs: true,
+		modelSelector: DefaultModelSelector,
 	}
 
 	for _, opt := range opts {
@@ -153,6 +162,17 @@ type chatCompletionRequest struct {
 	// Reasoning model parameters
 	ReasoningEffort *string `json:"reasoning_effort,omitempty"`
 	Verbosity       *string `json:"verbosity,omitempty"`
+	// Predicted output for faster edits of mostly known text
+	Prediction *prediction `json:"prediction,omitempty"`
+	// Token log probabilities
+	Logprobs    bool `json:"logprobs,omitempty"`
+	TopLogprobs *int `json:"top_logprobs,omitempty"`
+}
+
+// prediction is the expected completion sent with predicted outputs.
+type prediction struct {
+	Type    string        `json:"type"` // "content"
+	Content []contentPart `json:"content"`
 }
 
 // chatMessage represents a message in the chat conversation.
@@ -235,17 +255,83 @@ type chatCompletionResponse struct {
 
 // choice represents a completion choice.
 type choice struct {
-	Index        int         `json:"index"`
-	Message      chatMessage `json:"message"`
-	FinishReason string      `json:"finish_reason"`
-	LogProbs     interface{} `json:"logprobs"`
+	Index        int             `json:"index"`
+	Message      chatMessage     `json:"message"`
+	FinishReason string          `json:"finish_reason"`
+	LogProbs     *choiceLogprobs `json:"logprobs"`
+}
+
+// choiceLogprobs holds the token log probabilities of a choice.
+type choiceLogprobs struct {
+	Content []tokenLogprob `json:"content"`
+}
+
+// tokenLogprob is the log probability of one token. Bytes are sent as an
+// array of integers.
+type tokenLogprob struct {
+	Token       string         `json:"token"`
+	Logprob     float64        `json:"logprob"`
+	Bytes       []int          `json:"bytes"`
+	TopLogprobs []tokenLogprob `json:"top_logprobs,omitempty"`
+}
+
+// toCore converts logprobs to core format.
+func (l *choiceLogprobs) toCore() []core.TokenLogprob {
+	if l == nil || len(l.Content) == 0 {
+		return nil
+	}
+	result := make([]core.TokenLogprob, len(l.Content))
+	for i, t := range l.Content {
+		result[i] = core.TokenLogprob{
+			Token:   t.Token,
+			Logprob: t.Logprob,
+			Bytes:   logprobBytes(t.Bytes),
+		}
+		for _, top := range t.TopLogprobs {
+			result[i].TopLogprobs = append(result[i].TopLogprobs, core.TopLogprob{
+				Token:   top.Token,
+				Logprob: top.Logprob,
+				Bytes:   logprobBytes(top.Bytes),
+			})
+		}
+	}
+	return result
+}
+
+// logprobBytes converts the integer byte array sent by the API.
+func logprobBytes(values []int) []byte {
+	if values == nil {
+		return nil
+	}
+	b := make([]byte, len(values))
+	for i, v := range values {
+		b[i] = byte(v)
+	}
+	return b
 }
 
 // usage represents token usage information.
 type usage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens            int                      `json:"prompt_tokens"`
+	CompletionTokens        int                      `json:"completion_tokens"`
+	TotalTokens             int                      `json:"total_tokens"`
+	CompletionTokensDetails *completionTokensDetails `json:"completion_tokens_details,omitempty"`
+}
+
+// completionTokensDetails breaks down the completion tokens.
+type completionTokensDetails struct {
+	ReasoningTokens          int `json:"reasoning_tokens"`
+	AcceptedPredictionTokens int `json:"accepted_prediction_tokens"`
+	RejectedPredictionTokens int `json:"rejected_prediction_tokens"`
+}
+
+// rejectedPredictionTokens returns the number of predicted output tokens the
+// model did not use.
+func (u usage) rejectedPredictionTokens() int {
+	if u.CompletionTokensDetails == nil {
+		return 0
+	}
+	return u.CompletionTokensDetails.RejectedPredictionTokens
 }
 
 // streamChunk represents a chunk in the streaming response.
@@ -260,10 +346,10 @@ type streamChunk struct {
 
 // deltaChoice represents a streaming choice with delta content.
 type deltaChoice struct {
-	Index        int          `json:"index"`
-	Delta        messageDelta `json:"delta"`
-	FinishReason *string      `json:"finish_reason"`
-	LogProbs     interface{}  `json:"logprobs"`
+	Index        int             `json:"index"`
+	Delta        messageDelta    `json:"delta"`
+	FinishReason *string         `json:"finish_reason"`
+	LogProbs     *choiceLogprobs `json:"logprobs"`
 }
 
 // messageDelta represents incremental message content.
@@ -285,15 +371,20 @@ func (p *Provider) convertRequest(req core.Request) (*chatCompletionRequest, err
 	if req.Temperature > 0 && !p.isReasoningModel(model) {
 		ocr.Temperature = &req.Temperature
 	}
-	if req.MaxTokens > 0 {
-		// Use the appropriate token parameter based on model type
-		if p.isReasoningModel(model) {
-			ocr.MaxCompletionTokens = &req.MaxTokens
+	if maxTokens := core.OutputTokenLimit(req); maxTokens > 0 {
+		// max_completion_tokens replaces max_tokens, which legacy models still need
+		if isLegacyTokenModel(model) {
+			ocr.MaxTokens = &maxTokens
 		} else {
-			ocr.MaxTokens = &req.MaxTokens
+			ocr.MaxCompletionTokens = &maxTokens
 		}
 	}
 
+	// Without strict mode the response schema is described in a system message
+	if len(req.ResponseSchema) > 0 && !p.strictOutputs {
+		req.Messages = append([]core.Message{schemaInstruction(req.ResponseSchema)}, req.Messages...)
+	}
+
 	// Convert messages
 	messages, err := p.convertMessages(req.Messages)
 	if err != nil {
@@ -304,11 +395,41 @@ func (p *Provider) convertRequest(req core.Request) (*chatCompletionRequest, err
 	// Convert tools if present
 	if len(req.Tools) > 0 {
 		ocr.Tools = p.convertTools(req.Tools)
-		ocr.ToolChoice = p.convertToolChoice(req.ToolChoice)
+		ocr.ToolChoice = p.convertToolChoice(req.ToolChoice, req.SpecificTool)
 		parallelCalls := true
 		ocr.ParallelToolCalls = &parallelCalls
 	}
 
+	// Generate multiple completions in one call
+	if req.N > 1 {
+		ocr.N = req.N
+	}
+
+	// Request token log probabilities (opt-in, they enlarge the response)
+	if req.TopLogprobs != nil {
+		ocr.Logprobs = true
+		if *req.TopLogprobs > 0 {
+			ocr.TopLogprobs = req.TopLogprobs
+		}
+	}
+
+	// Send the predicted output for speculative decoding
+	if req.PredictedOutput != "" {
+		ocr.Prediction = &prediction{
+			Type:    "content",
+			Content: []contentPart{{Type: "text", Text: req.PredictedOutput}},
+		}
+	}
+
+	// Constrain the output to the response schema
+	if len(req.ResponseSchema) > 0 {
+		format, err := p.responseFormatFor(req.ResponseSchema)
+		if err != nil {
+			return nil, err
+		}
+		ocr.ResponseFormat = format
+	}
+
 	// Handle provider-specific options
 	if opts, ok := req.ProviderOptions["openai"].(map[string]interface{}); ok {
 		p.applyProviderOptions(ocr, opts)
@@ -317,6 +438,22 @@ func (p *Provider) convertRequest(req core.Request) (*chatCompletionRequest, err
 	return ocr, nil
 }
 
+// requestSetup returns how the provider prepares requests (see
+// core.PrepareRequest).
+func (p *Provider) requestSetup() core.RequestSetup {
+	return core.RequestSetup{
+		Provider:  "openai",
+		Selector:  p.modelSelector,
+		Validator: p,
+		Client:    p.client,
+	}
+}
+
+// RequestModel implements core.ModelResolver.
+func (p *Provider) RequestModel(ctx context.Context, req core.Request) string {
+	return p.getModel(core.PrepareModel(ctx, req, p.requestSetup()))
+}
+
 // getModel returns the model to use for the request.
 func (p *Provider) getModel(req core.Request) string {
 	if req.Model != "" {
@@ -350,6 +487,12 @@ func (p *Provider) isReasoningModel(model string) bool {
 	return false
 }
 
+// isLegacyTokenModel reports whether a model predates max_completion_tokens
+// and only accepts max_tokens: the GPT-3.5 and original GPT-4 series.
+func isLegacyTokenModel(model string) bool {
+	return strings.HasPrefix(model, "gpt-3.5") || model == "gpt-4" || strings.HasPrefix(model, "gpt-4-")
+}
+
 // convertMessages converts core messages to OpenAI format.
 func (p *Provider) convertMessages(messages []core.Message) ([]chatMessage, error) {
 	result := make([]chatMessage, 0, len(messages))
@@ -359,6 +502,12 @@ func (p *Provider) convertMessages(messages []core.Message) ([]chatMessage, erro
 			Role: string(msg.Role),
 			Name: msg.Name,
 		}
+		if msg.Role == core.Tool {
+			cm.ToolCallID = msg.ToolCallID
+		}
+		if msg.Role == core.Assistant && len(msg.ToolCalls) > 0 {
+			cm.ToolCalls = convertToolCallsToAPI(msg.ToolCalls)
+		}
 
 		// Handle multimodal content
 		if len(msg.Parts) == 1 {
@@ -388,6 +537,27 @@ func (p *Provider) convertMessages(messages []core.Message) ([]chatMessage, erro
 	return result, nil
 }
 
+// convertToolCallsToAPI converts the tool calls of an assistant message to
+// OpenAI format.
+func convertToolCallsToAPI(calls []core.ToolCall) []toolCall {
+	result := make([]toolCall, 0, len(calls))
+	for _, call := range calls {
+		args := string(call.Input)
+		if args == "" {
+			args = "{}"
+		}
+		result = append(result, toolCall{
+			ID:   call.ID,
+			Type: "function",
+			Function: functionCall{
+				Name:      call.Name,
+				Arguments: args,
+			},
+		})
+	}
+	return result
+}
+
 // convertParts converts message parts to OpenAI content parts.
 func (p *Provider) convertParts(parts []core.Part) ([]contentPart, error) {
 	result := make([]contentPart, 0, len(parts))
@@ -439,7 +609,7 @@ func (p *Provider) convertTools(tools []core.ToolHandle) []chatTool {
 }
 
 // convertToolChoice converts core tool choice to OpenAI format.
-func (p *Provider) convertToolChoice(choice core.ToolChoice) interface{} {
+func (p *Provider) convertToolChoice(choice core.ToolChoice, specificTool string) interface{} {
 	switch choice {
 	case core.ToolAuto:
 		return "auto"
@@ -447,6 +617,11 @@ func (p *Provider) convertToolChoice(choice core.ToolChoice) interface{} {
 		return "none"
 	case core.ToolRequired:
 		return "required"
+	case core.ToolSpecific:
+		return map[string]interface{}{
+			"type":     "function",
+			"function": map[string]string{"name": specificTool},
+		}
 	default:
 		return "auto"
 	}
@@ -478,7 +653,13 @@ func (p *Provider) applyProviderOptions(req *chatCompletionRequest, opts map[str
 func (p *Provider) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	var lastErr error
 
-	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+	maxRetries := p.maxRetries
+	policy, hasPolicy := core.RetryPolicyFromContext(ctx)
+	if hasPolicy {
+		maxRetries = policy.Retries(p.maxRetries)
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			// Exponential backoff with jitter
 			delay := p.retryDelay * time.Duration(1<<uint(attempt-1))
@@ -491,14 +672,28 @@ func (p *Provider) doRequest(ctx context.Context, method, path string, body inte
 
 		resp, err := p.doRequestOnce(ctx, method, path, body)
 		if err != nil {
+			if hasPolicy && !policy.ShouldRetry(err) {
+				return nil, err
+			}
 			lastErr = err
 			continue
 		}
 
+		// Per-request retry settings replace the status code checks below
+		if hasPolicy {
+			if attempt < maxRetries {
+				if retry, apiErr := policy.ShouldRetryResponse(resp, "openai"); retry {
+					lastErr = apiErr
+					continue
+				}
+			}
+			return resp, nil
+		}
+
 		// Check if we should retry based on status code
 		if p.shouldRetry(resp.StatusCode) {
 			// Only retry if we have attempts left
-			if attempt < p.maxRetries {
+			if attempt < maxRetries {
 				body, _ := io.ReadAll(resp.Body)
 				resp.Body.Close()
 				lastErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, body)
@@ -509,7 +704,7 @@ func (p *Provider) doRequest(ctx context.Context, method, path string, body inte
 		return resp, nil
 	}
 
-	return nil, fmt.Errorf("after %d retries: %w", p.maxRetries, lastErr)
+	return nil, fmt.Errorf("after %d retries: %w", maxRetries, lastErr)
 }
 
 // doRequestOnce performs a single HTTP request.
@@ -545,6 +740,11 @@ func (p *Provider) doRequestOnce(ctx context.Context, method, path string, body
 
 // shouldRetry determines if a request should be retried based on status code.
 func (p *Provider) shouldRetry(statusCode int) bool {
+	// Successful responses are never retried
+	if statusCode < http.StatusBadRequest {
+		return false
+	}
+
 	// Map the status code to our error taxonomy to determine if it's retryable
 	code := mapStatusCode(statusCode)
 	// Check if this error code is typically transient
@@ -3,12 +3,15 @@ package openai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -112,7 +115,7 @@ func (m *mockServer) handleNonStreamingResponse(w http.ResponseWriter, req chatC
 	}
 
 	// Handle structured output
-	if req.ResponseFormat != nil && req.ResponseFormat.Type == "json_schema" {
+	if req.ResponseFormat != nil && (req.ResponseFormat.Type == "json_schema" || req.ResponseFormat.Type == "json_object") {
 		resp.Choices[0].Message.Content = `{"result": "structured output test"}`
 	}
 
@@ -401,6 +404,393 @@ func TestGenerateTextWithTools(t *testing.T) {
 	}
 }
 
+func TestGenerateTextToolChoice(t *testing.T) {
+	type WeatherInput struct {
+		Location string `json:"location"`
+	}
+	weatherTool := tools.New[WeatherInput, string](
+		"get_weather",
+		"Get current weather for a location",
+		func(ctx context.Context, in WeatherInput, meta tools.Meta) (string, error) {
+			return "Sunny", nil
+		},
+	)
+
+	tests := []struct {
+		name          string
+		choice        core.ToolChoiceValue
+		want          interface{}
+		wantToolCalls bool
+	}{
+		{"required", core.ToolChoiceValue{Choice: core.ToolRequired}, "required", true},
+		{"none", core.ToolChoiceValue{Choice: core.ToolNone}, "none", false},
+		{"force", core.ToolForce("get_weather"), map[string]interface{}{
+			"type":     "function",
+			"function": map[string]interface{}{"name": "get_weather"},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockServer()
+			defer server.Close()
+
+			p := New(
+				WithAPIKey("test-key"),
+				WithBaseURL(server.URL),
+			)
+
+			req := core.Request{
+				Model: "gpt-4o-mini",
+				Messages: []core.Message{
+					{Role: core.User, Parts: []core.Part{core.Text{Text: "What's the weather in Paris?"}}},
+				},
+				Tools: []core.ToolHandle{tools.NewCoreAdapter(weatherTool)},
+			}
+			tt.choice.Apply(&req)
+
+			result, err := p.GenerateText(context.Background(), req)
+			if err != nil {
+				t.Fatalf("GenerateText failed: %v", err)
+			}
+
+			server.mu.Lock()
+			sent := server.requests[0].(map[string]interface{})
+			server.mu.Unlock()
+			if !reflect.DeepEqual(sent["tool_choice"], tt.want) {
+				t.Errorf("tool_choice = %v, want %v", sent["tool_choice"], tt.want)
+			}
+
+			gotToolCalls := len(result.Steps) > 0 && len(result.Steps[0].ToolCalls) > 0
+			if gotToolCalls != tt.wantToolCalls {
+				t.Errorf("tool calls returned = %v, want %v", gotToolCalls, tt.wantToolCalls)
+			}
+		})
+	}
+}
+
+func TestGenerateTextStepUsage(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	weatherTool := tools.New[struct{}, string](
+		"get_weather",
+		"Get current weather",
+		func(ctx context.Context, in struct{}, meta tools.Meta) (string, error) {
+			return "Sunny", nil
+		},
+	)
+
+	result, err := p.GenerateText(context.Background(), core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "What's the weather?"}}},
+		},
+		Tools:    []core.ToolHandle{tools.NewCoreAdapter(weatherTool)},
+		StopWhen: core.MaxSteps(2),
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	if len(result.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(result.Steps))
+	}
+	var sum core.Usage
+	for i, step := range result.Steps {
+		if step.Usage.TotalTokens != 18 {
+			t.Errorf("step %d usage = %+v, want 18 total tokens", i, step.Usage)
+		}
+		sum.Add(step.Usage)
+	}
+	if result.Usage != sum {
+		t.Errorf("result usage %+v does not match summed step usage %+v", result.Usage, sum)
+	}
+}
+
+func TestConvertToolCallsFromAPIProviderMeta(t *testing.T) {
+	p := New()
+	calls := p.convertToolCallsFromAPI([]toolCall{
+		{ID: "call_1", Type: "function", Function: functionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+		{ID: "call_2", Type: "function", Function: functionCall{Name: "get_weather", Arguments: `{"city":"Rome"}`}},
+	})
+	for i, call := range calls {
+		if index, ok := core.ToolCallMeta(call, "openai.index"); !ok || index != i {
+			t.Errorf("call %d index = %v, %v; want %d", i, index, ok, i)
+		}
+	}
+}
+
+func TestGenerateTextAutoModel(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	text := []core.Part{core.Text{Text: "Describe this"}}
+	image := []core.Part{core.Text{Text: "Describe this"}, core.ImageURL{URL: "https://example.com/cat.png"}}
+	custom := WithModelSelector(func(req core.Request, needs core.ModelNeeds) string {
+		return GPT41Nano
+	})
+
+	tests := []struct {
+		name string
+		opts []Option
+		req  core.Request
+		want string
+	}{
+		{"default", nil, core.Request{Model: core.AutoModel}, GPT4oMini},
+		{"vision", nil, core.Request{Model: core.AutoModel, Messages: []core.Message{{Role: core.User, Parts: image}}}, GPT4o},
+		{"flag", nil, core.Request{Model: GPT4, AutoSelectModel: true, Messages: []core.Message{{Role: core.User, Parts: image}}}, GPT4o},
+		{"custom selector", []Option{custom}, core.Request{Model: core.AutoModel}, GPT41Nano},
+		{"explicit model", []Option{custom}, core.Request{Model: GPT35Turbo}, GPT35Turbo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.requests = nil
+			p := New(append([]Option{WithAPIKey("test-key"), WithBaseURL(server.URL)}, tt.opts...)...)
+			if tt.req.Messages == nil {
+				tt.req.Messages = []core.Message{{Role: core.User, Parts: text}}
+			}
+			if _, err := p.GenerateText(context.Background(), tt.req); err != nil {
+				t.Fatalf("GenerateText failed: %v", err)
+			}
+			if got := server.requests[0].(map[string]interface{})["model"]; got != tt.want {
+				t.Errorf("model = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTextToolCallID(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	weatherTool := tools.New[struct{}, string](
+		"get_weather",
+		"Get current weather",
+		func(ctx context.Context, in struct{}, meta tools.Meta) (string, error) {
+			return "Sunny", nil
+		},
+	)
+
+	_, err := p.GenerateText(context.Background(), core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "What's the weather?"}}},
+		},
+		Tools:    []core.ToolHandle{tools.NewCoreAdapter(weatherTool)},
+		StopWhen: core.MaxSteps(2),
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	if len(server.requests) < 2 {
+		t.Fatalf("expected at least 2 requests, got %d", len(server.requests))
+	}
+	messages := server.requests[1].(map[string]interface{})["messages"].([]interface{})
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages in second request, got %d", len(messages))
+	}
+
+	// The tool result must answer the call made by the assistant message
+	assistant := messages[1].(map[string]interface{})
+	calls, _ := assistant["tool_calls"].([]interface{})
+	if len(calls) != 1 || calls[0].(map[string]interface{})["id"] != "call_test123" {
+		t.Errorf("assistant message tool_calls = %v", assistant["tool_calls"])
+	}
+	toolMsg := messages[2].(map[string]interface{})
+	if toolMsg["role"] != "tool" || toolMsg["tool_call_id"] != "call_test123" {
+		t.Errorf("tool message = %v, want tool_call_id call_test123", toolMsg)
+	}
+}
+
+func TestRunnerToolCallID(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	weatherTool := tools.New[struct{}, string](
+		"get_weather",
+		"Get current weather",
+		func(ctx context.Context, in struct{}, meta tools.Meta) (string, error) {
+			return "Sunny", nil
+		},
+	)
+
+	_, err := core.NewRunner(p).ExecuteRequest(context.Background(), core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "What's the weather?"}}},
+		},
+		Tools:    []core.ToolHandle{tools.NewCoreAdapter(weatherTool)},
+		StopWhen: core.MaxSteps(2),
+	})
+	if err != nil {
+		t.Fatalf("ExecuteRequest failed: %v", err)
+	}
+
+	if len(server.requests) < 2 {
+		t.Fatalf("expected at least 2 requests, got %d", len(server.requests))
+	}
+	messages := server.requests[1].(map[string]interface{})["messages"].([]interface{})
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages in second request, got %d", len(messages))
+	}
+
+	// The runner's second step replays the call the tool result answers
+	assistant := messages[1].(map[string]interface{})
+	calls, _ := assistant["tool_calls"].([]interface{})
+	if len(calls) != 1 || calls[0].(map[string]interface{})["id"] != "call_test123" {
+		t.Errorf("assistant message tool_calls = %v", assistant["tool_calls"])
+	}
+	toolMsg := messages[2].(map[string]interface{})
+	if toolMsg["role"] != "tool" || toolMsg["tool_call_id"] != "call_test123" {
+		t.Errorf("tool message = %v, want tool_call_id call_test123", toolMsg)
+	}
+}
+
+func TestGenerateTextPropagatesMetadata(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	type LookupInput struct {
+		Query string `json:"query"`
+	}
+
+	var seen map[string]any
+	lookupTool := tools.New[LookupInput, string](
+		"lookup",
+		"Look up a record",
+		func(ctx context.Context, in LookupInput, meta tools.Meta) (string, error) {
+			seen = core.MetadataFromContext(ctx)
+			return "found", nil
+		},
+	)
+
+	_, err := p.GenerateText(context.Background(), core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Find my record"}}},
+		},
+		Tools:    []core.ToolHandle{tools.NewCoreAdapter(lookupTool)},
+		StopWhen: core.MaxSteps(1),
+		Metadata: map[string]any{
+			core.MetadataTenantID: "acme",
+			core.MetadataUserID:   "user-1",
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	if seen[core.MetadataTenantID] != "acme" || seen[core.MetadataUserID] != "user-1" {
+		t.Errorf("Expected tool to see request metadata, got %v", seen)
+	}
+}
+
+func TestGenerateTextResolvesModelAlias(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	registry := core.NewModelRegistry()
+	registry.Register("fast", "openai", "gpt-4o-mini")
+	ctx := core.WithModelRegistry(context.Background(), registry)
+
+	_, err := p.GenerateText(ctx, core.Request{
+		Model: "fast",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(server.requests))
+	}
+	sent := server.requests[0].(map[string]interface{})
+	if sent["model"] != "gpt-4o-mini" {
+		t.Errorf("Expected alias to resolve to gpt-4o-mini, got %v", sent["model"])
+	}
+}
+
+func TestRequestModel(t *testing.T) {
+	p := New(WithAPIKey("test-key"), WithModel("gpt-4o"))
+
+	registry := core.NewModelRegistry()
+	registry.Register("fast", "openai", "gpt-4o-mini")
+	ctx := core.WithModelRegistry(context.Background(), registry)
+
+	var _ core.ModelResolver = p
+	if got := p.RequestModel(ctx, core.Request{}); got != "gpt-4o" {
+		t.Errorf("RequestModel with no model = %q, want the default gpt-4o", got)
+	}
+	if got := p.RequestModel(ctx, core.Request{Model: "fast"}); got != "gpt-4o-mini" {
+		t.Errorf("RequestModel with an alias = %q, want gpt-4o-mini", got)
+	}
+}
+
+func TestGenerateTextSystemPrompt(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	_, err := p.GenerateText(context.Background(), core.Request{
+		Model:        "gpt-4o-mini",
+		SystemPrompt: "Answer in French.",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	sent := server.requests[0].(map[string]interface{})
+	messages := sent["messages"].([]interface{})
+	if len(messages) != 2 {
+		t.Fatalf("Expected system and user messages, got %v", messages)
+	}
+	first := messages[0].(map[string]interface{})
+	if first["role"] != "system" || first["content"] != "Answer in French." {
+		t.Errorf("Expected system prompt first, got %v", first)
+	}
+}
+
 func TestStreamText(t *testing.T) {
 	server := newMockServer()
 	defer server.Close()
@@ -477,68 +867,427 @@ func TestStreamText(t *testing.T) {
 		}
 	}
 
-	if !hasStart {
-		t.Error("Missing start event")
+	if !hasStart {
+		t.Error("Missing start event")
+	}
+	if !hasTextDelta {
+		t.Error("Missing text delta events")
+	}
+	if !hasFinish {
+		t.Error("Missing finish event")
+	}
+}
+
+func TestStreamObjectPartials(t *testing.T) {
+	var sent chatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, fragment := range []string{`{"name":"Pan`, `cakes","servings"`, `:4}`} {
+			chunk := streamChunk{Choices: []deltaChoice{{Delta: messageDelta{Content: &fragment}}}}
+			data, _ := json.Marshal(chunk)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	type Recipe struct {
+		Name     string `json:"name"`
+		Servings int    `json:"servings"`
+	}
+	partials, err := core.StreamObject(context.Background(), p, core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "A pancake recipe"}}},
+		},
+		ResponseSchema: json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"},"servings":{"type":"integer"}}}`),
+	}, Recipe{})
+	if err != nil {
+		t.Fatalf("StreamObject failed: %v", err)
+	}
+
+	var names []string
+	var final core.Partial[Recipe]
+	for partial := range partials {
+		names = append(names, partial.Value.Name)
+		final = partial
+	}
+
+	if !sent.Stream || sent.ResponseFormat == nil || sent.ResponseFormat.Type != "json_schema" {
+		t.Errorf("expected streamed request with json_schema response format, got stream=%v format=%+v", sent.Stream, sent.ResponseFormat)
+	}
+	if len(names) < 2 || names[0] != "Pan" {
+		t.Errorf("expected partial names before completion, got %v", names)
+	}
+	if !final.Complete || final.Value != (Recipe{Name: "Pancakes", Servings: 4}) {
+		t.Errorf("final partial = %+v", final)
+	}
+}
+
+func TestStreamObjectChunks(t *testing.T) {
+	var sent chatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, fragment := range []string{`{"sentiment":"pos`, `itive"}`} {
+			chunk := streamChunk{Choices: []deltaChoice{{Delta: messageDelta{Content: &fragment}}}}
+			data, _ := json.Marshal(chunk)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	var _ core.StreamableObjectProvider = p
+	chunks, err := p.StreamObjectChunks(context.Background(), core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "I love it!"}}},
+		},
+	}, json.RawMessage(`{"type":"object","properties":{"sentiment":{"type":"string"}}}`))
+	if err != nil {
+		t.Fatalf("StreamObjectChunks failed: %v", err)
+	}
+
+	first := <-chunks
+	if string(first.JSON) != `{"sentiment":"pos"}` || first.Complete {
+		t.Errorf("first chunk = %+v", first)
+	}
+	data, err := core.ObjectAccumulate(chunks)
+	if err != nil || string(data) != `{"sentiment":"positive"}` {
+		t.Errorf("ObjectAccumulate = %s, %v", data, err)
+	}
+	if sent.ResponseFormat == nil || sent.ResponseFormat.Type != "json_schema" {
+		t.Errorf("expected json_schema response format, got %+v", sent.ResponseFormat)
+	}
+}
+
+func TestGenerateObject(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	type TestResponse struct {
+		Result string `json:"result"`
+	}
+
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"result": map[string]interface{}{
+				"type": "string",
+			},
+		},
+		"required": []string{"result"},
+	}
+
+	ctx := context.Background()
+	result, err := p.GenerateObject(ctx, core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{
+				Role: core.User,
+				Parts: []core.Part{
+					core.Text{Text: "Generate a structured response."},
+				},
+			},
+		},
+	}, schema)
+
+	if err != nil {
+		t.Fatalf("GenerateObject failed: %v", err)
+	}
+
+	if result == nil {
+		t.Fatal("GenerateObject returned nil result")
+	}
+
+	if result.Value == nil {
+		t.Error("GenerateObject returned nil value")
+	}
+
+	// Check usage
+	if result.Usage.TotalTokens == 0 {
+		t.Error("GenerateObject returned zero usage")
+	}
+}
+
+func TestGenerateTextPredictedOutput(t *testing.T) {
+	var sent chatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		json.NewEncoder(w).Encode(chatCompletionResponse{
+			ID:      "chatcmpl-prediction",
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   "gpt-4o",
+			Choices: []choice{
+				{Message: chatMessage{Role: "assistant", Content: "func add(a, b int) int { return a + b }"}},
+			},
+			Usage: usage{
+				PromptTokens:     20,
+				CompletionTokens: 15,
+				TotalTokens:      35,
+				CompletionTokensDetails: &completionTokensDetails{
+					AcceptedPredictionTokens: 10,
+					RejectedPredictionTokens: 3,
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	predicted := "func add(x, y int) int { return x + y }"
+	result, err := p.GenerateText(context.Background(), core.Request{
+		Model: "gpt-4o",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Rename the parameters to a and b."}}},
+		},
+		PredictedOutput: predicted,
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+
+	if sent.Prediction == nil || sent.Prediction.Type != "content" ||
+		len(sent.Prediction.Content) != 1 || sent.Prediction.Content[0].Text != predicted {
+		t.Errorf("unexpected prediction sent: %+v", sent.Prediction)
+	}
+	if result.RejectedTokens != 3 {
+		t.Errorf("RejectedTokens = %d, want 3", result.RejectedTokens)
+	}
+}
+
+func TestGenerateTextLogprobs(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		json.NewEncoder(w).Encode(chatCompletionResponse{
+			ID:      "chatcmpl-logprobs",
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   "gpt-4o-mini",
+			Choices: []choice{{
+				Message: chatMessage{Role: "assistant", Content: "Yes"},
+				LogProbs: &choiceLogprobs{Content: []tokenLogprob{{
+					Token:   "Yes",
+					Logprob: -0.01,
+					Bytes:   []int{89, 101, 115},
+					TopLogprobs: []tokenLogprob{
+						{Token: "Yes", Logprob: -0.01, Bytes: []int{89, 101, 115}},
+						{Token: "No", Logprob: -4.6, Bytes: []int{78, 111}},
+					},
+				}}},
+			}},
+			Usage: usage{TotalTokens: 10},
+		})
+	}))
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+	req := core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Is the sky blue?"}}},
+		},
+	}
+
+	// Logprobs are opt-in
+	if _, err := p.GenerateText(context.Background(), req); err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if _, ok := sent["logprobs"]; ok {
+		t.Error("logprobs requested without TopLogprobs")
+	}
+
+	top := 2
+	req.TopLogprobs = &top
+	result, err := p.GenerateText(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if sent["logprobs"] != true || sent["top_logprobs"] != float64(2) {
+		t.Errorf("logprobs = %v, top_logprobs = %v", sent["logprobs"], sent["top_logprobs"])
+	}
+
+	if len(result.TokenLogprobs) != 1 {
+		t.Fatalf("expected 1 token logprob, got %d", len(result.TokenLogprobs))
 	}
-	if !hasTextDelta {
-		t.Error("Missing text delta events")
+	token := result.TokenLogprobs[0]
+	if token.Token != "Yes" || token.Logprob != -0.01 || string(token.Bytes) != "Yes" {
+		t.Errorf("unexpected token logprob: %+v", token)
 	}
-	if !hasFinish {
-		t.Error("Missing finish event")
+	if len(token.TopLogprobs) != 2 || token.TopLogprobs[1].Token != "No" || string(token.TopLogprobs[1].Bytes) != "No" {
+		t.Errorf("unexpected top logprobs: %+v", token.TopLogprobs)
 	}
 }
 
-func TestGenerateObject(t *testing.T) {
-	server := newMockServer()
+func TestGenerateTextMultipleCompletions(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		json.NewEncoder(w).Encode(chatCompletionResponse{
+			ID:      "chatcmpl-n",
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   "gpt-4o-mini",
+			Choices: []choice{
+				{Index: 0, Message: chatMessage{Role: "assistant", Content: "Red"}},
+				{Index: 1, Message: chatMessage{Role: "assistant", Content: "Green"}},
+				{Index: 2, Message: chatMessage{Role: "assistant", Content: "Blue"}},
+			},
+			Usage: usage{PromptTokens: 10, CompletionTokens: 3, TotalTokens: 13},
+		})
+	}))
 	defer server.Close()
 
 	p := New(
 		WithAPIKey("test-key"),
 		WithBaseURL(server.URL),
 	)
+	req := core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Name a color"}}},
+		},
+		N: 3,
+	}
 
-	type TestResponse struct {
-		Result string `json:"result"`
+	result, err := p.GenerateText(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if sent["n"] != float64(3) {
+		t.Errorf("n = %v, want 3", sent["n"])
+	}
+	if result.Text != "Red" {
+		t.Errorf("Text = %q, want Red", result.Text)
+	}
+	if len(result.Alternatives) != 2 || result.Alternatives[0].Text != "Green" || result.Alternatives[1].Text != "Blue" {
+		t.Errorf("unexpected alternatives: %+v", result.Alternatives)
+	}
+	if result.Usage.TotalTokens != 13 {
+		t.Errorf("Usage.TotalTokens = %d, want 13", result.Usage.TotalTokens)
 	}
 
-	schema := map[string]interface{}{
-		"type": "object",
-		"properties": map[string]interface{}{
-			"result": map[string]interface{}{
-				"type": "string",
-			},
-		},
-		"required": []string{"result"},
+	// Streams cannot return alternatives
+	if _, err := p.StreamText(context.Background(), req); !core.IsUnsupported(err) {
+		t.Errorf("StreamText with N > 1: expected unsupported error, got %v", err)
 	}
+}
 
-	ctx := context.Background()
-	result, err := p.GenerateObject(ctx, core.Request{
+func TestGenerateTextModelUsed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(chatCompletionResponse{
+			ID:      "chatcmpl-model",
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   "gpt-4o-mini-2024-07-18",
+			Choices: []choice{{Message: chatMessage{Role: "assistant", Content: "Hi"}}},
+		})
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	result, err := p.GenerateText(context.Background(), core.Request{
+		Model:    "gpt-4o-mini",
+		Messages: []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if result.ModelUsed != "gpt-4o-mini-2024-07-18" {
+		t.Errorf("ModelUsed = %q, want gpt-4o-mini-2024-07-18", result.ModelUsed)
+	}
+}
+
+func TestGenerateObjectStructuredRetry(t *testing.T) {
+	var messageCounts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		messageCounts = append(messageCounts, len(req.Messages))
+
+		// First attempt returns malformed JSON
+		content := `{"result": "truncated`
+		if len(messageCounts) > 1 {
+			content = `{"result": "ok"}`
+		}
+		json.NewEncoder(w).Encode(chatCompletionResponse{
+			ID:      "chatcmpl-retry",
+			Object:  "chat.completion",
+			Created: time.Now().Unix(),
+			Model:   "gpt-4o-mini",
+			Choices: []choice{
+				{Message: chatMessage{Role: "assistant", Content: content}},
+			},
+			Usage: usage{TotalTokens: 10},
+		})
+	}))
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+	)
+
+	req := core.Request{
 		Model: "gpt-4o-mini",
 		Messages: []core.Message{
-			{
-				Role: core.User,
-				Parts: []core.Part{
-					core.Text{Text: "Generate a structured response."},
-				},
-			},
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Generate a structured response."}}},
 		},
-	}, schema)
+	}
+	schema := map[string]interface{}{"type": "object"}
+
+	// Without retries the validation error is returned
+	_, err := p.GenerateObject(context.Background(), req, schema)
+	var ve *core.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
 
+	messageCounts = nil
+	req.MaxStructuredRetries = 2
+	result, err := p.GenerateObject(context.Background(), req, schema)
 	if err != nil {
 		t.Fatalf("GenerateObject failed: %v", err)
 	}
 
-	if result == nil {
-		t.Fatal("GenerateObject returned nil result")
+	if len(messageCounts) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(messageCounts))
 	}
-
-	if result.Value == nil {
-		t.Error("GenerateObject returned nil value")
+	// Retry carries the failed output and a corrective message
+	if messageCounts[1] != messageCounts[0]+2 {
+		t.Errorf("Expected corrective messages on retry, got %v", messageCounts)
 	}
-
-	// Check usage
-	if result.Usage.TotalTokens == 0 {
-		t.Error("GenerateObject returned zero usage")
+	if len(result.Steps) != 2 {
+		t.Errorf("Expected 2 steps, got %d", len(result.Steps))
+	}
+	if value, ok := result.Value.(map[string]interface{}); !ok || value["result"] != "ok" {
+		t.Errorf("Unexpected value: %v", result.Value)
 	}
 }
 
@@ -607,6 +1356,28 @@ func TestRetryLogic(t *testing.T) {
 	}
 }
 
+func TestShouldRetry(t *testing.T) {
+	p := New(WithAPIKey("test-key"))
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		// Successful responses are returned, not retried
+		{http.StatusOK, false},
+		{http.StatusCreated, false},
+		{http.StatusBadRequest, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusServiceUnavailable, true},
+	}
+	for _, tt := range tests {
+		if got := p.shouldRetry(tt.status); got != tt.want {
+			t.Errorf("shouldRetry(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
 func TestErrorHandling(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -675,6 +1446,60 @@ func TestErrorHandling(t *testing.T) {
 	}
 }
 
+func TestValidateModel(t *testing.T) {
+	server := newMockServer()
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		model   string
+		wantErr bool
+	}{
+		{name: "known model", model: GPT4o, wantErr: false},
+		{name: "known reasoning model", model: O3Mini, wantErr: false},
+		{name: "unknown model", model: "gpt-unknown", wantErr: true},
+		{name: "empty model", model: "", wantErr: true},
+		{
+			name:    "unknown model allowed",
+			opts:    []Option{WithAllowUnknownModels(true)},
+			model:   "gpt-preview-xyz",
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(append([]Option{WithAPIKey("test-key"), WithBaseURL(server.URL)}, tt.opts...)...)
+			err := p.ValidateModel(tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateModel(%q) error = %v, wantErr %v", tt.model, err, tt.wantErr)
+			}
+			if err != nil && !core.IsUnknownModel(err) {
+				t.Errorf("Expected ErrorUnknownModel, got %v", err)
+			}
+		})
+	}
+
+	t.Run("request override rejected before HTTP call", func(t *testing.T) {
+		p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+		before := len(server.requests)
+
+		_, err := p.GenerateText(context.Background(), core.Request{
+			Model: "not-a-real-model",
+			Messages: []core.Message{
+				{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}},
+			},
+		})
+		if !core.IsUnknownModel(err) {
+			t.Fatalf("Expected ErrorUnknownModel, got %v", err)
+		}
+		if len(server.requests) != before {
+			t.Error("Expected no HTTP request for an unknown model")
+		}
+	})
+}
+
 // Helper functions
 func floatPtr(f float32) *float32 {
 	return &f
@@ -682,4 +1507,292 @@ func floatPtr(f float32) *float32 {
 
 func intPtr(i int) *int {
 	return &i
-}
\ No newline at end of file
+}
+func TestRequestRetryPolicy(t *testing.T) {
+	attemptCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	p := New(
+		WithAPIKey("test-key"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(3),
+		WithRetryDelay(time.Millisecond),
+	)
+
+	req := core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Test retry"}}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		maxRetries   int
+		retryOn      func(error) bool
+		wantAttempts int
+	}{
+		{name: "override count", maxRetries: 1, wantAttempts: 2},
+		{name: "disabled", maxRetries: -1, wantAttempts: 1},
+		{name: "retry on rejects", retryOn: func(err error) bool { return core.IsRateLimited(err) }, wantAttempts: 1},
+		{name: "retry on accepts", maxRetries: 2, retryOn: func(err error) bool { return core.IsOverloaded(err) || core.IsTransient(err) }, wantAttempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attemptCount = 0
+			r := req
+			r.MaxRetries = tt.maxRetries
+			r.RetryOn = tt.retryOn
+
+			if _, err := p.GenerateText(context.Background(), r); err == nil {
+				t.Fatal("Expected error from failing server")
+			}
+			if attemptCount != tt.wantAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.wantAttempts, attemptCount)
+			}
+		})
+	}
+}
+
+func TestInspectRequest(t *testing.T) {
+	p := New(WithAPIKey("test-key"))
+
+	req := core.Request{
+		Model:    "gpt-4o-mini",
+		Messages: []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}}},
+	}
+	inspected, err := p.InspectRequest(context.Background(), req)
+	if err != nil {
+		t.Fatalf("InspectRequest failed: %v", err)
+	}
+	if !inspected.Valid() {
+		t.Errorf("Expected valid request, got %v", inspected.ValidationErrors)
+	}
+
+	req.Model = "not-a-model"
+	req.Temperature = 2.5
+	inspected, err = p.InspectRequest(context.Background(), req)
+	if err != nil {
+		t.Fatalf("InspectRequest failed: %v", err)
+	}
+	if len(inspected.ValidationErrors) != 2 {
+		t.Errorf("Expected model and temperature errors, got %v", inspected.ValidationErrors)
+	}
+}
+
+func TestModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"object":"list","data":[{"id":"gpt-4o"},{"id":"ft:gpt-4o:acme"}]}`))
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	models, err := p.Models(context.Background())
+	if err != nil {
+		t.Fatalf("Models failed: %v", err)
+	}
+	if len(models) != 2 {
+		t.Fatalf("Expected 2 models, got %d", len(models))
+	}
+	if models[0].ID != "gpt-4o" || models[0].ContextWindow != 128000 || !models[0].Supports(core.CapabilityVision) {
+		t.Errorf("Unexpected details for gpt-4o: %+v", models[0])
+	}
+	if models[1].ID != "ft:gpt-4o:acme" || models[1].ContextWindow != 0 || len(models[1].Capabilities) != 0 {
+		t.Errorf("Expected unknown model without details, got %+v", models[1])
+	}
+}
+
+func TestFineTuneJobs(t *testing.T) {
+	var created map[string]any
+	var cancelled string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/fine_tuning/jobs":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Write([]byte(`{"id":"ftjob-1","model":"gpt-4o-mini","status":"queued","fine_tuned_model":null,"training_file":"file-train","validation_file":null,"hyperparameters":{"n_epochs":3,"batch_size":"auto","learning_rate_multiplier":"auto"},"created_at":1700000000,"finished_at":null}`))
+		case r.Method == "GET" && r.URL.Path == "/fine_tuning/jobs/ftjob-1":
+			w.Write([]byte(`{"id":"ftjob-1","model":"gpt-4o-mini","status":"succeeded","fine_tuned_model":"ft:gpt-4o-mini:acme::abc","training_file":"file-train","created_at":1700000000,"finished_at":1700003600}`))
+		case r.Method == "GET" && r.URL.Path == "/fine_tuning/jobs":
+			if r.URL.Query().Get("after") == "" {
+				w.Write([]byte(`{"data":[{"id":"ftjob-2","status":"running"}],"has_more":true}`))
+			} else {
+				w.Write([]byte(`{"data":[{"id":"ftjob-1","status":"succeeded"}],"has_more":false}`))
+			}
+		case r.Method == "POST" && r.URL.Path == "/fine_tuning/jobs/ftjob-2/cancel":
+			cancelled = "ftjob-2"
+			w.Write([]byte(`{"id":"ftjob-2","status":"cancelled"}`))
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	if _, err := p.CreateFineTuneJob(ctx, core.FineTuneOpts{BaseModel: "gpt-4o-mini"}); err == nil {
+		t.Error("Expected error without training file")
+	}
+
+	job, err := p.CreateFineTuneJob(ctx, core.FineTuneOpts{
+		TrainingFile:    "file-train",
+		BaseModel:       "gpt-4o-mini",
+		Hyperparameters: core.FineTuneHyperparams{Epochs: 3},
+	})
+	if err != nil {
+		t.Fatalf("CreateFineTuneJob failed: %v", err)
+	}
+	if created["model"] != "gpt-4o-mini" || created["training_file"] != "file-train" {
+		t.Errorf("Unexpected create request: %v", created)
+	}
+	if hp, _ := created["hyperparameters"].(map[string]any); hp["n_epochs"] != float64(3) || hp["batch_size"] != nil {
+		t.Errorf("Unexpected hyperparameters: %v", created["hyperparameters"])
+	}
+	if job.ID != "ftjob-1" || job.Status != "queued" || job.Hyperparameters.Epochs != 3 || !job.FinishedAt.IsZero() {
+		t.Errorf("Unexpected job: %+v", job)
+	}
+
+	job, err = p.GetFineTuneJob(ctx, "ftjob-1")
+	if err != nil {
+		t.Fatalf("GetFineTuneJob failed: %v", err)
+	}
+	if job.FineTunedModel != "ft:gpt-4o-mini:acme::abc" || job.FinishedAt.IsZero() {
+		t.Errorf("Unexpected job: %+v", job)
+	}
+	if err := p.ValidateModel(job.FineTunedModel); err != nil {
+		t.Errorf("Fine-tuned model should be usable as Request.Model: %v", err)
+	}
+
+	jobs, err := p.ListFineTuneJobs(ctx)
+	if err != nil {
+		t.Fatalf("ListFineTuneJobs failed: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != "ftjob-2" || jobs[1].ID != "ftjob-1" {
+		t.Errorf("Unexpected jobs: %+v", jobs)
+	}
+
+	if err := p.CancelFineTuneJob(ctx, "ftjob-2"); err != nil || cancelled != "ftjob-2" {
+		t.Errorf("CancelFineTuneJob failed: %v", err)
+	}
+	if _, err := p.GetFineTuneJob(ctx, "missing"); err == nil {
+		t.Error("Expected error for unknown job")
+	}
+}
+
+func TestCreateFineTuneJobIsNotRetried(t *testing.T) {
+	var posts, gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			posts.Add(1)
+		} else {
+			gets.Add(1)
+		}
+		http.Error(w, `{"error":{"message":"upstream timeout","type":"server_error"}}`, http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL), WithMaxRetries(2), WithRetryDelay(time.Millisecond))
+	ctx := context.Background()
+
+	if _, err := p.CreateFineTuneJob(ctx, core.FineTuneOpts{TrainingFile: "file-train", BaseModel: "gpt-4o-mini"}); err == nil {
+		t.Error("Expected error from CreateFineTuneJob")
+	}
+	if posts.Load() != 1 {
+		t.Errorf("Expected the create request to be sent once, got %d", posts.Load())
+	}
+
+	// Reads are still retried
+	if _, err := p.GetFineTuneJob(ctx, "ftjob-1"); err == nil {
+		t.Error("Expected error from GetFineTuneJob")
+	}
+	if gets.Load() != 3 {
+		t.Errorf("Expected 3 attempts for GetFineTuneJob, got %d", gets.Load())
+	}
+}
+
+func TestMaxOutputTokens(t *testing.T) {
+	p := New(WithAPIKey("test-key"))
+	tests := []struct {
+		name                string
+		req                 core.Request
+		wantMaxTokens       int
+		wantCompletionLimit int
+	}{
+		{"current model", core.Request{Model: "gpt-4o", MaxOutputTokens: 100}, 0, 100},
+		{"reasoning model", core.Request{Model: "o3-mini", MaxOutputTokens: 100}, 0, 100},
+		{"legacy model", core.Request{Model: "gpt-3.5-turbo", MaxOutputTokens: 100}, 100, 0},
+		{"deprecated alias", core.Request{Model: "gpt-4o", MaxTokens: 50}, 0, 50},
+		{"max output tokens wins", core.Request{Model: "gpt-4", MaxOutputTokens: 100, MaxTokens: 50}, 100, 0},
+		{"unset", core.Request{Model: "gpt-4o"}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiReq, err := p.convertRequest(tt.req)
+			if err != nil {
+				t.Fatalf("convertRequest failed: %v", err)
+			}
+			if got := derefInt(apiReq.MaxTokens); got != tt.wantMaxTokens {
+				t.Errorf("max_tokens = %d, want %d", got, tt.wantMaxTokens)
+			}
+			if got := derefInt(apiReq.MaxCompletionTokens); got != tt.wantCompletionLimit {
+				t.Errorf("max_completion_tokens = %d, want %d", got, tt.wantCompletionLimit)
+			}
+		})
+	}
+}
+
+func derefInt(n *int) int {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+func TestAbortAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Read the body so the server notices when the client disconnects
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	p := New(WithAPIKey("test-key"), WithBaseURL(server.URL))
+	req := core.Request{
+		Messages:   []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}}},
+		AbortAfter: 50 * time.Millisecond,
+	}
+
+	_, err := p.GenerateText(context.Background(), req)
+	var aiErr *core.AIError
+	if !errors.As(err, &aiErr) || aiErr.Code != core.ErrorTimeout || aiErr.Duration != req.AbortAfter {
+		t.Errorf("GenerateText: expected timeout error with Duration %v, got %v", req.AbortAfter, err)
+	}
+	// The stream is aborted when the timeout expires
+	stream, err := p.StreamText(context.Background(), req)
+	if err != nil {
+		if !core.IsTimeout(err) {
+			t.Fatalf("StreamText: expected timeout error, got %v", err)
+		}
+		return
+	}
+	defer stream.Close()
+	var last core.Event
+	for e := range stream.Events() {
+		last = e
+	}
+	if last.Type != core.EventError || !core.IsTimeout(last.Err) {
+		t.Errorf("StreamText: expected timeout error event, got %+v", last)
+	}
+}
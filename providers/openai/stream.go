@@ -40,6 +40,20 @@ type toolCallBuilder struct {
 
 // StreamText implements streaming text generation.
 func (p *Provider) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	return core.StreamWithTimeout(ctx, req, func(ctx context.Context) (core.TextStream, error) {
+		return p.streamText(ctx, req)
+	})
+}
+
+// streamText implements StreamText within the request's timeouts.
+func (p *Provider) streamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	model := p.getModel(req)
 
 	// Use streaming GenAI observability wrapper
@@ -164,6 +178,7 @@ func (s *textStream) process(ctx context.Context, collector core.MetricsCollecto
 				s.sendEvent(core.Event{
 					Type:      core.EventError,
 					Err:       err,
+					ErrorCode: core.ErrorCodeOf(err),
 					Timestamp: time.Now(),
 				})
 				s.err = err
@@ -225,11 +240,15 @@ func (s *textStream) processChunk(chunk streamChunk, totalUsage *core.Usage) {
 	for _, choice := range chunk.Choices {
 		// Handle text delta
 		if choice.Delta.Content != nil && *choice.Delta.Content != "" {
-			s.sendEvent(core.Event{
+			event := core.Event{
 				Type:      core.EventTextDelta,
 				TextDelta: *choice.Delta.Content,
 				Timestamp: time.Now(),
-			})
+			}
+			if logprobs := choice.LogProbs.toCore(); logprobs != nil {
+				event.Metadata = map[string]any{core.EventMetaLogprobs: logprobs}
+			}
+			s.sendEvent(event)
 		}
 
 		// Handle tool calls
@@ -313,6 +332,20 @@ type objectStream struct {
 
 // StreamObject implements streaming generation of structured objects.
 func (p *Provider) StreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	return core.StreamObjectWithTimeout(ctx, req, func(ctx context.Context) (core.ObjectStream[any], error) {
+		return p.streamObject(ctx, req, schema)
+	})
+}
+
+// streamObject implements StreamObject within the request's timeouts.
+func (p *Provider) streamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	model := p.getModel(req)
 
 	// Use streaming GenAI observability wrapper
@@ -329,26 +362,10 @@ func (p *Provider) StreamObject(ctx context.Context, req core.Request, schema an
 
 // executeStreamObject handles the actual streaming object logic (extracted for observability)
 func (p *Provider) executeStreamObject(ctx context.Context, req core.Request, schema any) (core.ObjectStream[any], error) {
-	// Convert schema to JSON Schema format
-	schemaBytes, err := json.Marshal(schema)
+	// Prepare request with response format for structured output
+	apiReq, err := p.convertObjectRequest(req, schema)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling schema: %w", err)
-	}
-
-	// Prepare request with response format
-	apiReq, err := p.convertRequest(req)
-	if err != nil {
-		return nil, fmt.Errorf("converting request: %w", err)
-	}
-
-	// Set response format for structured output
-	apiReq.ResponseFormat = &responseFormat{
-		Type: "json_schema",
-		JSONSchema: &jsonSchemaFormat{
-			Name:   "response",
-			Schema: schemaBytes,
-			Strict: true,
-		},
+		return nil, err
 	}
 
 	// Enable streaming
@@ -456,6 +473,7 @@ func (s *objectStream) processObject(ctx context.Context, collector core.Metrics
 				s.sendEvent(core.Event{
 					Type:      core.EventError,
 					Err:       err,
+					ErrorCode: core.ErrorCodeOf(err),
 					Timestamp: time.Now(),
 				})
 				s.err = err
@@ -0,0 +1,178 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/recera/gai/core"
+)
+
+// WithStrictStructuredOutputs controls how GenerateObject and StreamObject
+// constrain the response. When enabled (the default), the schema is sent with
+// Structured Outputs strict mode, which guarantees the response matches it.
+// When disabled, the provider uses JSON mode and describes the schema in a
+// system message instead, for models or compatible APIs without strict mode.
+func WithStrictStructuredOutputs(strict bool) Option {
+	return func(p *Provider) {
+		p.strictOutputs = strict
+	}
+}
+
+// convertObjectRequest converts req to an API request whose response format
+// constrains the output to schema, merged with any req.ResponseSchema.
+func (p *Provider) convertObjectRequest(req core.Request, schema any) (*chatCompletionRequest, error) {
+	merged, err := core.MergeResponseSchema(schema, req.ResponseSchema)
+	if err != nil {
+		return nil, err
+	}
+	req.ResponseSchema = merged
+
+	apiReq, err := p.convertRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("converting request: %w", err)
+	}
+	return apiReq, nil
+}
+
+// schemaInstruction returns the system message that describes schema when
+// strict mode is disabled.
+func schemaInstruction(schema []byte) core.Message {
+	return core.Message{
+		Role: core.System,
+		Parts: []core.Part{core.Text{Text: fmt.Sprintf(
+			"Respond only with a JSON object that conforms to this JSON schema:\n%s", schema)}},
+	}
+}
+
+// responseFormatFor returns the response format that constrains the output
+// to schema: Structured Outputs in strict mode, JSON mode otherwise.
+func (p *Provider) responseFormatFor(schema []byte) (*responseFormat, error) {
+	if !p.strictOutputs {
+		return &responseFormat{Type: "json_object"}, nil
+	}
+
+	strictSchema, err := toStrictSchema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("converting schema for strict mode: %w", err)
+	}
+	return &responseFormat{
+		Type: "json_schema",
+		JSONSchema: &jsonSchemaFormat{
+			Name:   "response",
+			Schema: strictSchema,
+			Strict: true,
+		},
+	}, nil
+}
+
+// toStrictSchema adapts a JSON schema to the subset accepted by strict mode:
+// every object disallows additional properties and lists all of its
+// properties as required, with originally optional properties made nullable.
+// Schemas that are not JSON objects are returned unchanged.
+func toStrictSchema(schema []byte) (json.RawMessage, error) {
+	var root map[string]any
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return schema, nil
+	}
+	strictify(root)
+	return json.Marshal(root)
+}
+
+// strictify rewrites a schema node and its subschemas in place.
+func strictify(node map[string]any) {
+	if props, ok := node["properties"].(map[string]any); ok {
+		required := make(map[string]bool)
+		if list, ok := node["required"].([]any); ok {
+			for _, name := range list {
+				if s, ok := name.(string); ok {
+					required[s] = true
+				}
+			}
+		}
+
+		names := make([]string, 0, len(props))
+		for name, prop := range props {
+			names = append(names, name)
+			sub, ok := prop.(map[string]any)
+			if !ok {
+				continue
+			}
+			strictify(sub)
+			if !required[name] {
+				props[name] = nullable(sub)
+			}
+		}
+		sort.Strings(names)
+
+		all := make([]any, len(names))
+		for i, name := range names {
+			all[i] = name
+		}
+		node["required"] = all
+		node["additionalProperties"] = false
+	} else if node["type"] == "object" {
+		node["additionalProperties"] = false
+	}
+
+	if items, ok := node["items"].(map[string]any); ok {
+		strictify(items)
+	}
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		if list, ok := node[key].([]any); ok {
+			for _, item := range list {
+				if sub, ok := item.(map[string]any); ok {
+					strictify(sub)
+				}
+			}
+		}
+	}
+	for _, key := range []string{"$defs", "definitions"} {
+		if defs, ok := node[key].(map[string]any); ok {
+			for _, def := range defs {
+				if sub, ok := def.(map[string]any); ok {
+					strictify(sub)
+				}
+			}
+		}
+	}
+}
+
+// nullable returns a schema that also accepts null.
+func nullable(schema map[string]any) map[string]any {
+	switch t := schema["type"].(type) {
+	case string:
+		if t != "null" {
+			schema["type"] = []any{t, "null"}
+		}
+		return schema
+	case []any:
+		for _, v := range t {
+			if v == "null" {
+				return schema
+			}
+		}
+		schema["type"] = append(t, "null")
+		return schema
+	}
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
+
+// StreamObjectChunks implements core.StreamableObjectProvider. The output is
+// constrained to schema, merged with any req.ResponseSchema, using Structured
+// Outputs, and each chunk holds the JSON streamed so far.
+func (p *Provider) StreamObjectChunks(ctx context.Context, req core.Request, schema json.RawMessage) (<-chan core.ObjectChunk, error) {
+	merged, err := core.MergeResponseSchema(schema, req.ResponseSchema)
+	if err != nil {
+		return nil, err
+	}
+	req.ResponseSchema = merged
+	req.Stream = true
+
+	stream, err := p.StreamText(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return core.ObjectChunks(ctx, stream), nil
+}
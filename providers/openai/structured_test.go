@@ -0,0 +1,187 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+func TestToStrictSchema(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"price": {"type": "number"},
+			"tags": {"type": "array", "items": {"type": "object", "properties": {"label": {"type": "string"}}}},
+			"owner": {"$ref": "#/$defs/Owner"}
+		},
+		"required": ["name"],
+		"$defs": {
+			"Owner": {"type": "object", "properties": {"id": {"type": ["string", "null"]}}, "required": ["id"]}
+		}
+	}`
+
+	out, err := toStrictSchema([]byte(schema))
+	if err != nil {
+		t.Fatalf("toStrictSchema failed: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("invalid schema output: %v", err)
+	}
+
+	if got["additionalProperties"] != false {
+		t.Error("Expected root to disallow additional properties")
+	}
+	wantRequired := []any{"name", "owner", "price", "tags"}
+	if !reflect.DeepEqual(got["required"], wantRequired) {
+		t.Errorf("required = %v, want %v", got["required"], wantRequired)
+	}
+
+	props := got["properties"].(map[string]any)
+	if props["name"].(map[string]any)["type"] != "string" {
+		t.Errorf("Expected required property to keep its type, got %v", props["name"])
+	}
+	if !reflect.DeepEqual(props["price"].(map[string]any)["type"], []any{"number", "null"}) {
+		t.Errorf("Expected optional property to be nullable, got %v", props["price"])
+	}
+	if _, ok := props["owner"].(map[string]any)["anyOf"]; !ok {
+		t.Errorf("Expected optional $ref to be wrapped in anyOf, got %v", props["owner"])
+	}
+
+	items := props["tags"].(map[string]any)["items"].(map[string]any)
+	if items["additionalProperties"] != false || !reflect.DeepEqual(items["required"], []any{"label"}) {
+		t.Errorf("Expected nested array items to be strict, got %v", items)
+	}
+
+	owner := got["$defs"].(map[string]any)["Owner"].(map[string]any)
+	if owner["additionalProperties"] != false {
+		t.Errorf("Expected definitions to be strict, got %v", owner)
+	}
+	if !reflect.DeepEqual(owner["properties"].(map[string]any)["id"].(map[string]any)["type"], []any{"string", "null"}) {
+		t.Errorf("Expected already nullable type to be unchanged, got %v", owner["properties"])
+	}
+}
+
+func TestGenerateObjectStructuredOutputModes(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"result": map[string]any{"type": "string"},
+		},
+	}
+	req := core.Request{
+		Model: "gpt-4o-mini",
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Generate a structured response."}}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		verify func(t *testing.T, sent map[string]any)
+	}{
+		{
+			name: "strict by default",
+			verify: func(t *testing.T, sent map[string]any) {
+				format := sent["response_format"].(map[string]any)
+				if format["type"] != "json_schema" {
+					t.Fatalf("Expected json_schema response format, got %v", format["type"])
+				}
+				jsonSchema := format["json_schema"].(map[string]any)
+				if jsonSchema["strict"] != true {
+					t.Error("Expected strict mode")
+				}
+				sentSchema := jsonSchema["schema"].(map[string]any)
+				if sentSchema["additionalProperties"] != false {
+					t.Errorf("Expected strict-compatible schema, got %v", sentSchema)
+				}
+				if len(sent["messages"].([]any)) != 1 {
+					t.Error("Expected no schema instruction in strict mode")
+				}
+			},
+		},
+		{
+			name: "json mode when disabled",
+			opts: []Option{WithStrictStructuredOutputs(false)},
+			verify: func(t *testing.T, sent map[string]any) {
+				format := sent["response_format"].(map[string]any)
+				if format["type"] != "json_object" {
+					t.Fatalf("Expected json_object response format, got %v", format["type"])
+				}
+				messages := sent["messages"].([]any)
+				first := messages[0].(map[string]any)
+				if len(messages) != 2 || first["role"] != "system" {
+					t.Fatalf("Expected schema instruction as system message, got %v", messages)
+				}
+				if content, _ := first["content"].(string); !strings.Contains(content, `"result"`) {
+					t.Errorf("Expected schema in instruction, got %q", content)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockServer()
+			defer server.Close()
+
+			p := New(append([]Option{WithAPIKey("test-key"), WithBaseURL(server.URL)}, tt.opts...)...)
+			result, err := p.GenerateObject(context.Background(), req, schema)
+			if err != nil {
+				t.Fatalf("GenerateObject failed: %v", err)
+			}
+			if result.Value == nil {
+				t.Error("GenerateObject returned nil value")
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			tt.verify(t, server.requests[0].(map[string]any))
+		})
+	}
+}
+
+func TestResponseSchema(t *testing.T) {
+	p := New(WithAPIKey("test-key"))
+	req := core.Request{
+		Messages:       []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Describe a product"}}}},
+		ResponseSchema: json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}},"required":["name"]}`),
+	}
+
+	// Plain requests use the schema as the response format
+	apiReq, err := p.convertRequest(req)
+	if err != nil {
+		t.Fatalf("convertRequest failed: %v", err)
+	}
+	if apiReq.ResponseFormat == nil || apiReq.ResponseFormat.Type != "json_schema" {
+		t.Fatalf("Expected json_schema response format, got %+v", apiReq.ResponseFormat)
+	}
+	if !strings.Contains(string(apiReq.ResponseFormat.JSONSchema.Schema), `"name"`) {
+		t.Errorf("Expected schema in response format, got %s", apiReq.ResponseFormat.JSONSchema.Schema)
+	}
+
+	// GenerateObject merges the override over its own schema
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"price": map[string]any{"type": "number"}},
+	}
+	apiReq, err = p.convertObjectRequest(req, schema)
+	if err != nil {
+		t.Fatalf("convertObjectRequest failed: %v", err)
+	}
+	var merged map[string]any
+	if err := json.Unmarshal(apiReq.ResponseFormat.JSONSchema.Schema, &merged); err != nil {
+		t.Fatalf("invalid merged schema: %v", err)
+	}
+	props := merged["properties"].(map[string]any)
+	if props["name"] == nil || props["price"] == nil {
+		t.Errorf("Expected properties from both schemas, got %v", props)
+	}
+}
@@ -59,6 +59,8 @@ func MapError(resp *http.Response, providerName string) error {
 			if seconds := parseRetryAfter(retryAfter); seconds > 0 {
 				opts = append(opts, core.WithRetryAfter(time.Duration(seconds)*time.Second))
 			}
+		} else if seconds := windowedRateLimitReset(resp.Header); seconds > 0 {
+			opts = append(opts, core.WithRetryAfter(time.Duration(seconds)*time.Second))
 		} else {
 			// Default retry after for rate limiting based on provider
 			retryDuration := getDefaultRetryAfter(providerName, code)
@@ -265,6 +267,27 @@ func parseRetryAfter(value string) int {
 	return 0
 }
 
+// windowedRateLimitHeaders are the reset headers of providers such as
+// Cerebras that limit tokens per minute and requests per day separately, in
+// the order they usually run out.
+var windowedRateLimitHeaders = []string{
+	"X-RateLimit-Reset-Tokens-Minute",
+	"X-RateLimit-Reset-Requests-Day",
+}
+
+// windowedRateLimitReset returns the seconds until the first windowed rate
+// limit resets, or 0 if no such header is present.
+func windowedRateLimitReset(header http.Header) int {
+	for _, name := range windowedRateLimitHeaders {
+		if value := header.Get(name); value != "" {
+			if seconds := parseRetryAfter(value); seconds > 0 {
+				return seconds
+			}
+		}
+	}
+	return 0
+}
+
 // getDefaultRetryAfter returns provider-specific default retry durations.
 func getDefaultRetryAfter(providerName string, code core.ErrorCode) time.Duration {
 	switch strings.ToLower(providerName) {
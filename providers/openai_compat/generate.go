@@ -14,6 +14,20 @@ import (
 // GenerateText implements the core.Provider interface for text generation.
 // It supports multi-step tool execution when tools are provided.
 func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.TextResult, error) {
+		return p.generateText(ctx, req)
+	})
+}
+
+// generateText implements GenerateText within the request's timeouts.
+func (p *Provider) generateText(ctx context.Context, req core.Request) (*core.TextResult, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	// Use metrics collector if available
 	if p.config.MetricsCollector != nil {
 		defer func(start int64) {
@@ -35,7 +49,7 @@ func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.Te
 	// Strip unsupported parameters
 	apiReq = p.stripUnsupportedParams(apiReq)
 	
-	resp, err := p.doRequest(ctx, "POST", "/chat/completions", apiReq)
+	resp, err := p.doRequest(ctx, req, "POST", "/chat/completions", apiReq)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +71,8 @@ func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.Te
 			OutputTokens: apiResp.Usage.CompletionTokens,
 			TotalTokens:  apiResp.Usage.TotalTokens,
 		},
-		Raw: apiResp,
+		Raw:       apiResp,
+		ModelUsed: apiResp.Model,
 	}
 	
 	if len(apiResp.Choices) > 0 {
@@ -87,6 +102,7 @@ func (p *Provider) GenerateText(ctx context.Context, req core.Request) (*core.Te
 			step := core.Step{
 				Text:      result.Text,
 				ToolCalls: p.convertToolCallsFromAPI(choice.Message.ToolCalls),
+				Usage:     result.Usage,
 			}
 			result.Steps = append(result.Steps, step)
 		}
@@ -120,9 +136,10 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 			Model:           req.Model,
 			Messages:        messages,
 			Temperature:     req.Temperature,
-			MaxTokens:       req.MaxTokens,
+			MaxTokens:       core.OutputTokenLimit(req),
 			Tools:           toolsToSend,
 			ToolChoice:      toolChoiceToSend,
+			SpecificTool:    req.SpecificTool,
 			ProviderOptions: req.ProviderOptions,
 		})
 		if err != nil {
@@ -132,7 +149,7 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 		// Strip unsupported parameters
 		apiReq = p.stripUnsupportedParams(apiReq)
 		
-		resp, err := p.doRequest(ctx, "POST", "/chat/completions", apiReq)
+		resp, err := p.doRequest(ctx, req, "POST", "/chat/completions", apiReq)
 		if err != nil {
 			return nil, fmt.Errorf("API request for step %d: %w", stepCount, err)
 		}
@@ -148,9 +165,12 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 		}
 		
 		// Update usage
-		totalUsage.InputTokens += apiResp.Usage.PromptTokens
-		totalUsage.OutputTokens += apiResp.Usage.CompletionTokens
-		totalUsage.TotalTokens += apiResp.Usage.TotalTokens
+		stepUsage := core.Usage{
+			InputTokens:  apiResp.Usage.PromptTokens,
+			OutputTokens: apiResp.Usage.CompletionTokens,
+			TotalTokens:  apiResp.Usage.TotalTokens,
+		}
+		totalUsage.Add(stepUsage)
 		
 		if len(apiResp.Choices) == 0 {
 			break
@@ -178,7 +198,7 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 		// Check for tool calls
 		if len(choice.Message.ToolCalls) == 0 {
 			// No tools called, this is the final response
-			steps = append(steps, core.Step{Text: text})
+			steps = append(steps, core.Step{Text: text, Usage: stepUsage})
 			break
 		}
 		
@@ -187,6 +207,10 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 		
 		// Execute tools
 		toolResults := make([]core.ToolExecution, len(toolCalls))
+		// Coalesce repeated calls to batch-capable tools into one execution each
+		batched := core.ExecuteBatchedCalls(ctx, req.Tools, toolCalls, map[string]interface{}{
+			"messages": messages,
+		})
 		for i, tc := range toolCalls {
 			// Find the tool
 			var tool core.ToolHandle
@@ -199,24 +223,33 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 			
 			if tool == nil {
 				toolResults[i] = core.ToolExecution{
-					Name:   tc.Name,
-					Result: map[string]string{"error": "tool not found"},
+					ID:    tc.ID,
+					Name:  tc.Name,
+					Error: "tool not found",
 				}
 				continue
 			}
 			
-			// Execute the tool
-			result, err := tool.Exec(ctx, tc.Input, map[string]interface{}{
-				"call_id": tc.ID,
-				"messages": messages,
-			})
+			// Execute the tool, using the batch result if the call was coalesced
+			var result any
+			var err error
+			if b, ok := batched[i]; ok {
+				result, err = b.Result, b.Err
+			} else {
+				result, err = tool.Exec(ctx, tc.Input, map[string]interface{}{
+					"call_id": tc.ID,
+					"messages": messages,
+				})
+			}
 			if err != nil {
 				toolResults[i] = core.ToolExecution{
-					Name:   tc.Name,
-					Result: map[string]string{"error": err.Error()},
+					ID:    tc.ID,
+					Name:  tc.Name,
+					Error: err.Error(),
 				}
 			} else {
 				toolResults[i] = core.ToolExecution{
+					ID:     tc.ID,
 					Name:   tc.Name,
 					Result: result,
 				}
@@ -228,6 +261,7 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 			Text:        text,
 			ToolCalls:   toolCalls,
 			ToolResults: toolResults,
+			Usage:       stepUsage,
 		}
 		steps = append(steps, step)
 		
@@ -240,6 +274,7 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 				Parts: []core.Part{
 					core.Text{Text: ""}, // Empty text when calling tools
 				},
+				ToolCalls: toolCalls,
 			})
 		} else if text != "" {
 			// Add assistant message with text if there is any
@@ -253,13 +288,12 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 		
 		// Add tool results as messages
 		for i, tr := range toolResults {
-			resultJSON, _ := json.Marshal(tr.Result)
 			messages = append(messages, core.Message{
 				Role: core.Tool,
 				Parts: []core.Part{
-					core.Text{Text: string(resultJSON)},
+					core.Text{Text: tr.ToolResult().Content()},
 				},
-				Name: toolCalls[i].ID, // Use tool call ID as message name
+				ToolCallID: toolCalls[i].ID,
 			})
 		}
 		
@@ -291,7 +325,29 @@ func (p *Provider) generateWithTools(ctx context.Context, req core.Request) (*co
 }
 
 // GenerateObject generates a structured object output.
+// Outputs that fail JSON validation are retried up to req.MaxStructuredRetries times.
 func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema interface{}) (*core.ObjectResult[any], error) {
+	return core.RunWithTimeout(ctx, req, func(ctx context.Context) (*core.ObjectResult[any], error) {
+		return p.generateObjectWithRetry(ctx, req, schema)
+	})
+}
+
+// generateObjectWithRetry implements GenerateObject within the request's timeouts.
+func (p *Provider) generateObjectWithRetry(ctx context.Context, req core.Request, schema interface{}) (*core.ObjectResult[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
+	return core.GenerateObjectWithRetry(ctx, req, func(ctx context.Context, req core.Request) (*core.ObjectResult[any], error) {
+		return p.generateObject(ctx, req, schema)
+	})
+}
+
+// generateObject performs a single structured output attempt.
+func (p *Provider) generateObject(ctx context.Context, req core.Request, schema interface{}) (*core.ObjectResult[any], error) {
 	// Generate JSON schema from the type
 	schemaBytes, err := p.generateJSONSchema(schema)
 	if err != nil {
@@ -332,7 +388,7 @@ func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema
 	apiReq = p.stripUnsupportedParams(apiReq)
 	
 	// Make API request
-	resp, err := p.doRequest(ctx, "POST", "/chat/completions", apiReq)
+	resp, err := p.doRequest(ctx, req, "POST", "/chat/completions", apiReq)
 	if err != nil {
 		return nil, err
 	}
@@ -363,7 +419,7 @@ func (p *Provider) GenerateObject(ctx context.Context, req core.Request, schema
 	// Parse and validate JSON
 	result := reflect.New(reflect.TypeOf(schema)).Interface()
 	if err := json.Unmarshal([]byte(jsonContent), result); err != nil {
-		return nil, fmt.Errorf("parsing JSON response: %w", err)
+		return nil, core.NewValidationError(jsonContent, err)
 	}
 	
 	return &core.ObjectResult[any]{
@@ -388,8 +444,8 @@ func (p *Provider) convertRequest(req core.Request) (*chatCompletionRequest, err
 	if req.Temperature > 0 {
 		apiReq.Temperature = &req.Temperature
 	}
-	if req.MaxTokens > 0 {
-		apiReq.MaxTokens = &req.MaxTokens
+	if maxTokens := core.OutputTokenLimit(req); maxTokens > 0 {
+		apiReq.MaxTokens = &maxTokens
 	}
 	
 	// Convert messages
@@ -422,6 +478,11 @@ func (p *Provider) convertRequest(req core.Request) (*chatCompletionRequest, err
 			apiReq.ToolChoice = "auto"
 		case core.ToolRequired:
 			apiReq.ToolChoice = "required"
+		case core.ToolSpecific:
+			apiReq.ToolChoice = map[string]interface{}{
+				"type":     "function",
+				"function": map[string]string{"name": req.SpecificTool},
+			}
 		default:
 			apiReq.ToolChoice = "auto"
 		}
@@ -467,6 +528,20 @@ func (p *Provider) convertMessages(messages []core.Message) ([]chatMessage, erro
 			Role: string(msg.Role),
 			Name: msg.Name,
 		}
+		if msg.Role == core.Tool {
+			apiMsg.ToolCallID = msg.ToolCallID
+		}
+		for _, call := range msg.ToolCalls {
+			args := string(call.Input)
+			if args == "" {
+				args = "{}"
+			}
+			apiMsg.ToolCalls = append(apiMsg.ToolCalls, toolCall{
+				ID:       call.ID,
+				Type:     "function",
+				Function: functionCall{Name: call.Name, Arguments: args},
+			})
+		}
 		
 		// Convert parts to content
 		if len(msg.Parts) == 1 {
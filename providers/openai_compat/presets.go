@@ -56,36 +56,66 @@ func Groq(opts ...Option) (*Provider, error) {
 	return provider, nil
 }
 
-// XAI creates a provider configured for xAI's API (Grok models).
-// xAI provides access to the Grok family of models.
-//
-// Models available:
-//   - grok-2-latest (default, most capable)
-//   - grok-2-1212
-//   - grok-beta
+// Named model constants for xAI's Grok models.
+const (
+	// XAIGrok2 is the latest Grok 2 model alias (128K context)
+	XAIGrok2 = "grok-2-latest"
+	// XAIGrok21212 is the December 2024 Grok 2 snapshot
+	XAIGrok21212 = "grok-2-1212"
+	// XAIGrok2Vision is the Grok 2 vision model
+	XAIGrok2Vision = "grok-2-vision-1212"
+	// XAIGrokBeta is the original Grok beta model
+	XAIGrokBeta = "grok-beta"
+)
+
+// XAIPreset returns CompatOpts configured for xAI's API (Grok models).
+// If model is empty, XAIGrok2 is used. The returned options can be adjusted
+// before being passed to New.
 //
 // Example:
 //
-//	provider := openai_compat.XAI()
-func XAI(opts ...Option) (*Provider, error) {
-	config := CompatOpts{
+//	opts := openai_compat.XAIPreset(os.Getenv("XAI_API_KEY"), openai_compat.XAIGrokBeta)
+//	provider, err := openai_compat.New(opts)
+func XAIPreset(apiKey string, model string) CompatOpts {
+	if model == "" {
+		model = XAIGrok2
+	}
+	
+	return CompatOpts{
 		BaseURL:      "https://api.x.ai/v1",
-		APIKey:       os.Getenv("XAI_API_KEY"),
-		DefaultModel: "grok-2-latest",
+		APIKey:       apiKey,
+		DefaultModel: model,
 		ProviderName: "xai",
 		MaxRetries:   3,
 		RetryDelay:   time.Second,
 		
-		// xAI supports most OpenAI features
+		// xAI supports most OpenAI features, but strict JSON schema
+		// enforcement is not available on all Grok models
 		DisableJSONStreaming:     false,
 		DisableParallelToolCalls: false,
-		DisableStrictJSONSchema:  false,
+		DisableStrictJSONSchema:  true,
 		DisableToolChoice:        false,
 		
 		CustomHeaders: map[string]string{
 			"X-Provider": "xai",
 		},
 	}
+}
+
+// XAI creates a provider configured for xAI's API (Grok models).
+// xAI provides access to the Grok family of models.
+//
+// Models available:
+//   - grok-2-latest (default, most capable)
+//   - grok-2-1212
+//   - grok-2-vision-1212
+//   - grok-beta
+//
+// Example:
+//
+//	provider := openai_compat.XAI()
+func XAI(opts ...Option) (*Provider, error) {
+	config := XAIPreset(os.Getenv("XAI_API_KEY"), XAIGrok2)
 	
 	// Create provider
 	provider, err := New(config)
@@ -101,34 +131,40 @@ func XAI(opts ...Option) (*Provider, error) {
 	return provider, nil
 }
 
-// Cerebras creates a provider configured for Cerebras' API.
-// Cerebras provides extremely fast inference with optimized hardware.
-//
-// Models available:
-//   - llama-3.3-70b (default)
-//   - llama-3.1-70b
-//   - llama-3.1-8b
-//
-// Note: Cerebras has some limitations:
-//   - JSON streaming is not supported
-//   - Parallel tool calls are not supported
-//   - Very fast but strict rate limits
+// Named model constants for Cerebras.
+const (
+	// CerebrasLlama38B is Llama 3.1 8B, the fastest Cerebras model
+	CerebrasLlama38B = "llama3.1-8b"
+	// CerebrasLlama370B is Llama 3.3 70B
+	CerebrasLlama370B = "llama-3.3-70b"
+)
+
+// CerebrasPreset returns CompatOpts configured for Cerebras' API. If model is
+// empty, CerebrasLlama370B is used. Cerebras returns complete responses
+// almost immediately, so JSON streaming is disabled; its windowed rate limit
+// headers are read by MapError. The returned options can be adjusted before
+// being passed to New.
 //
 // Example:
 //
-//	provider := openai_compat.Cerebras()
-func Cerebras(opts ...Option) (*Provider, error) {
-	config := CompatOpts{
+//	opts := openai_compat.CerebrasPreset(os.Getenv("CEREBRAS_API_KEY"), openai_compat.CerebrasLlama38B)
+//	provider, err := openai_compat.New(opts)
+func CerebrasPreset(apiKey string, model string) CompatOpts {
+	if model == "" {
+		model = CerebrasLlama370B
+	}
+	
+	return CompatOpts{
 		BaseURL:      "https://api.cerebras.ai/v1",
-		APIKey:       os.Getenv("CEREBRAS_API_KEY"),
-		DefaultModel: "llama-3.3-70b",
+		APIKey:       apiKey,
+		DefaultModel: model,
 		ProviderName: "cerebras",
 		MaxRetries:   3,
 		RetryDelay:   2 * time.Second, // Cerebras has strict rate limits
 		
 		// Cerebras limitations
-		DisableJSONStreaming:     true,  // Not supported
-		DisableParallelToolCalls: true,  // Not supported
+		DisableJSONStreaming:     true, // Responses come complete
+		DisableParallelToolCalls: true, // Not supported
 		DisableStrictJSONSchema:  false,
 		DisableToolChoice:        false,
 		
@@ -136,6 +172,25 @@ func Cerebras(opts ...Option) (*Provider, error) {
 			"X-Provider": "cerebras",
 		},
 	}
+}
+
+// Cerebras creates a provider configured for Cerebras' API.
+// Cerebras provides extremely fast inference with optimized hardware.
+//
+// Models available:
+//   - llama-3.3-70b (default)
+//   - llama3.1-8b
+//
+// Note: Cerebras has some limitations:
+//   - JSON streaming is not supported
+//   - Parallel tool calls are not supported
+//   - Very fast but strict rate limits
+//
+// Example:
+//
+//	provider := openai_compat.Cerebras()
+func Cerebras(opts ...Option) (*Provider, error) {
+	config := CerebrasPreset(os.Getenv("CEREBRAS_API_KEY"), CerebrasLlama370B)
 	
 	// Create provider
 	provider, err := New(config)
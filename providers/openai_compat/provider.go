@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -20,6 +21,9 @@ import (
 
 const (
 	defaultTimeout = 60 * time.Second
+
+	defaultMaxIdleConns    = 100
+	defaultIdleConnTimeout = 90 * time.Second
 )
 
 // Provider implements the core.Provider interface for OpenAI-compatible APIs.
@@ -40,8 +44,13 @@ type CompatOpts struct {
 	BaseURL string // Base URL for the API (e.g., "https://api.groq.com/openai/v1")
 	APIKey  string // API key for authentication
 	
+	// BaseURLFn optionally selects the base URL per request, e.g. to route
+	// tool-heavy requests to a different backend cluster. An empty return
+	// value falls back to BaseURL.
+	BaseURLFn func(req core.Request) string
+	
 	// Model configuration
-	DefaultModel string // Default model to use if not specified in request
+	DefaultModel string // Default model to use if not specified in request (overridden by core.Request.Model)
 	
 	// Feature toggles for provider limitations
 	DisableJSONStreaming      bool // Some providers don't support JSON streaming
@@ -59,6 +68,7 @@ type CompatOpts struct {
 	MaxRetries         int           // Maximum retry attempts (default: 3)
 	RetryDelay         time.Duration // Base delay between retries (default: 1s)
 	HTTPClient         *http.Client  // Custom HTTP client
+	HTTPClientOpts     HTTPClientOpts // Transport tuning for the default client (ignored with HTTPClient)
 	
 	// Observability
 	MetricsCollector core.MetricsCollector
@@ -67,6 +77,45 @@ type CompatOpts struct {
 	ProviderName string // e.g., "groq", "xai", "baseten", "cerebras"
 }
 
+// HTTPClientOpts tunes the transport of the default HTTP client. Zero values
+// keep the defaults, which match the client used before these options existed.
+type HTTPClientOpts struct {
+	// ResponseHeaderTimeout limits the wait for response headers after the
+	// request is sent, i.e. the time to first token when streaming (default:
+	// no limit beyond the overall 60s client timeout)
+	ResponseHeaderTimeout time.Duration
+	// IdleConnTimeout is how long idle keep-alive connections are kept
+	// (default: 90s)
+	IdleConnTimeout time.Duration
+	// DialTimeout limits establishing a TCP connection (default: no limit
+	// beyond the operating system's)
+	DialTimeout time.Duration
+	// MaxIdleConns caps idle keep-alive connections across all hosts
+	// (default: 100)
+	MaxIdleConns int
+}
+
+// newTransport creates the transport of the default HTTP client.
+func newTransport(opts HTTPClientOpts) *http.Transport {
+	transport := &http.Transport{
+		MaxIdleConns:          defaultMaxIdleConns,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       defaultIdleConnTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+		DisableCompression:    false,
+	}
+	if opts.MaxIdleConns > 0 {
+		transport.MaxIdleConns = opts.MaxIdleConns
+	}
+	if opts.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	if opts.DialTimeout > 0 {
+		transport.DialContext = (&net.Dialer{Timeout: opts.DialTimeout}).DialContext
+	}
+	return transport
+}
+
 // Capabilities represents the detected or configured capabilities of the provider.
 type Capabilities struct {
 	Models              []ModelInfo
@@ -95,22 +144,9 @@ func New(opts CompatOpts) (*Provider, error) {
 	}
 	
 	// Parse and validate base URL
-	baseURL, err := url.Parse(opts.BaseURL)
+	baseURL, err := parseBaseURL(opts.BaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid base URL: %w", err)
-	}
-	
-	// Validate that it's a proper URL with scheme
-	if baseURL.Scheme == "" || baseURL.Host == "" {
-		return nil, fmt.Errorf("invalid base URL: must include scheme and host")
-	}
-	
-	// Ensure base URL ends with /v1 or similar
-	if !strings.HasSuffix(baseURL.Path, "/v1") && !strings.HasSuffix(baseURL.Path, "/v1/") {
-		if !strings.HasSuffix(baseURL.Path, "/") {
-			baseURL.Path += "/"
-		}
-		baseURL.Path += "v1"
+		return nil, err
 	}
 	
 	// Set defaults
@@ -122,13 +158,8 @@ func New(opts CompatOpts) (*Provider, error) {
 	}
 	if opts.HTTPClient == nil {
 		opts.HTTPClient = &http.Client{
-			Timeout: defaultTimeout,
-			Transport: &http.Transport{
-				MaxIdleConns:        100,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-				DisableCompression:  false,
-			},
+			Timeout:   defaultTimeout,
+			Transport: newTransport(opts.HTTPClientOpts),
 		}
 	}
 	
@@ -154,6 +185,48 @@ func New(opts CompatOpts) (*Provider, error) {
 	return p, nil
 }
 
+// parseBaseURL parses and validates a base URL, ensuring it ends with /v1.
+func parseBaseURL(rawURL string) (*url.URL, error) {
+	baseURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	
+	// Validate that it's a proper URL with scheme
+	if baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid base URL: must include scheme and host")
+	}
+	
+	// Ensure base URL ends with /v1 or similar
+	if !strings.HasSuffix(baseURL.Path, "/v1") && !strings.HasSuffix(baseURL.Path, "/v1/") {
+		if !strings.HasSuffix(baseURL.Path, "/") {
+			baseURL.Path += "/"
+		}
+		baseURL.Path += "v1"
+	}
+	
+	return baseURL, nil
+}
+
+// resolveBaseURL returns the base URL to use for a request, consulting
+// BaseURLFn when configured.
+func (p *Provider) resolveBaseURL(req core.Request) (string, error) {
+	if p.config.BaseURLFn == nil {
+		return p.baseURL.String(), nil
+	}
+	
+	rawURL := p.config.BaseURLFn(req)
+	if rawURL == "" {
+		return p.baseURL.String(), nil
+	}
+	
+	baseURL, err := parseBaseURL(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("BaseURLFn returned %q: %w", rawURL, err)
+	}
+	return baseURL.String(), nil
+}
+
 // applyProviderDefaults applies known defaults for specific providers.
 func applyProviderDefaults(opts *CompatOpts) {
 	switch strings.ToLower(opts.ProviderName) {
@@ -167,7 +240,7 @@ func applyProviderDefaults(opts *CompatOpts) {
 	case "xai", "x.ai":
 		// xAI (Grok) configuration
 		if opts.DefaultModel == "" {
-			opts.DefaultModel = "grok-2-latest"
+			opts.DefaultModel = XAIGrok2
 		}
 		
 	case "cerebras":
@@ -289,7 +362,13 @@ func (p *Provider) setHeaders(req *http.Request) {
 }
 
 // doRequest performs an HTTP request with retry logic.
-func (p *Provider) doRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
+// The base URL is resolved from the originating core.Request.
+func (p *Provider) doRequest(ctx context.Context, coreReq core.Request, method, endpoint string, body interface{}) (*http.Response, error) {
+	baseURL, err := p.resolveBaseURL(coreReq)
+	if err != nil {
+		return nil, err
+	}
+	
 	var bodyReader io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -299,10 +378,16 @@ func (p *Provider) doRequest(ctx context.Context, method, endpoint string, body
 		bodyReader = bytes.NewReader(jsonBody)
 	}
 	
-	fullURL := p.baseURL.String() + endpoint
+	fullURL := baseURL + endpoint
+	
+	maxRetries := p.config.MaxRetries
+	policy, hasPolicy := core.RetryPolicyFromContext(ctx)
+	if hasPolicy {
+		maxRetries = policy.Retries(p.config.MaxRetries)
+	}
 	
 	var lastErr error
-	for attempt := 0; attempt <= p.config.MaxRetries; attempt++ {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			// Exponential backoff with jitter
 			delay := p.config.RetryDelay * time.Duration(1<<uint(attempt-1))
@@ -325,10 +410,24 @@ func (p *Provider) doRequest(ctx context.Context, method, endpoint string, body
 		
 		resp, err := p.client.Do(req)
 		if err != nil {
+			if hasPolicy && !policy.ShouldRetry(err) {
+				return nil, fmt.Errorf("http request: %w", err)
+			}
 			lastErr = fmt.Errorf("http request: %w", err)
 			continue
 		}
 		
+		// Per-request retry settings replace the status code checks below
+		if hasPolicy {
+			if attempt < maxRetries {
+				if retry, apiErr := policy.ShouldRetryResponse(resp, p.config.ProviderName); retry {
+					lastErr = apiErr
+					continue
+				}
+			}
+			return resp, nil
+		}
+		
 		// Success or client error (don't retry client errors)
 		if resp.StatusCode < 500 {
 			return resp, nil
@@ -341,7 +440,7 @@ func (p *Provider) doRequest(ctx context.Context, method, endpoint string, body
 		// Check if it's a retryable error
 		if resp.StatusCode == 503 || resp.StatusCode == 502 || resp.StatusCode == 504 {
 			// For 503, check if this is the last attempt
-			if attempt == p.config.MaxRetries {
+			if attempt == maxRetries {
 				// Return the response so it can be properly mapped to an error
 				resp.Body = io.NopCloser(bytes.NewReader(body))
 				return resp, nil
@@ -352,7 +451,7 @@ func (p *Provider) doRequest(ctx context.Context, method, endpoint string, body
 		
 		// For 500 errors, check if the error message suggests retry
 		if resp.StatusCode == 500 && strings.Contains(string(body), "temporarily") {
-			if attempt == p.config.MaxRetries {
+			if attempt == maxRetries {
 				resp.Body = io.NopCloser(bytes.NewReader(body))
 				return resp, nil
 			}
@@ -365,7 +464,21 @@ func (p *Provider) doRequest(ctx context.Context, method, endpoint string, body
 		return resp, nil
 	}
 	
-	return nil, fmt.Errorf("request failed after %d attempts: %w", p.config.MaxRetries+1, lastErr)
+	return nil, fmt.Errorf("request failed after %d attempts: %w", maxRetries+1, lastErr)
+}
+
+// requestSetup returns how the provider prepares requests (see
+// core.PrepareRequest).
+func (p *Provider) requestSetup() core.RequestSetup {
+	return core.RequestSetup{
+		Provider: p.config.ProviderName,
+		Client:   p.client,
+	}
+}
+
+// RequestModel implements core.ModelResolver.
+func (p *Provider) RequestModel(ctx context.Context, req core.Request) string {
+	return p.getModel(core.PrepareModel(ctx, req, p.requestSetup()))
 }
 
 // getModel returns the model to use for a request.
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -95,6 +97,36 @@ func TestProviderCreation(t *testing.T) {
 	}
 }
 
+func TestHTTPClientOpts(t *testing.T) {
+	p, err := New(CompatOpts{BaseURL: "https://api.example.com/v1"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	transport := p.client.Transport.(*http.Transport)
+	if transport.MaxIdleConns != 100 || transport.IdleConnTimeout != 90*time.Second ||
+		transport.ResponseHeaderTimeout != 0 || transport.DialContext != nil {
+		t.Errorf("unexpected default transport: %+v", transport)
+	}
+
+	p, err = New(CompatOpts{
+		BaseURL: "https://api.example.com/v1",
+		HTTPClientOpts: HTTPClientOpts{
+			ResponseHeaderTimeout: 5 * time.Second,
+			IdleConnTimeout:       time.Minute,
+			DialTimeout:           time.Second,
+			MaxIdleConns:          7,
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	transport = p.client.Transport.(*http.Transport)
+	if transport.MaxIdleConns != 7 || transport.IdleConnTimeout != time.Minute ||
+		transport.ResponseHeaderTimeout != 5*time.Second || transport.DialContext == nil {
+		t.Errorf("options not applied to transport: %+v", transport)
+	}
+}
+
 func TestGenerateText(t *testing.T) {
 	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/v1/chat/completions" {
@@ -174,6 +206,293 @@ func TestGenerateText(t *testing.T) {
 	}
 }
 
+func TestPerRequestModelAndBaseURL(t *testing.T) {
+	// newBackend returns a server that records the model it was asked for.
+	newBackend := func(name string, models *[]string) *httptest.Server {
+		return mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			var req chatCompletionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			*models = append(*models, req.Model)
+			
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(chatCompletionResponse{
+				ID:    "chatcmpl-test",
+				Model: req.Model,
+				Choices: []choice{
+					{
+						Message:      chatMessage{Role: "assistant", Content: name},
+						FinishReason: "stop",
+					},
+				},
+			})
+		})
+	}
+	
+	var defaultModels, toolModels []string
+	defaultServer := newBackend("default", &defaultModels)
+	defer defaultServer.Close()
+	toolServer := newBackend("tools", &toolModels)
+	defer toolServer.Close()
+	
+	provider, err := New(CompatOpts{
+		BaseURL:      defaultServer.URL,
+		APIKey:       "test-key",
+		DefaultModel: "default-model",
+		BaseURLFn: func(req core.Request) string {
+			if req.Metadata["route"] == "tools" {
+				return toolServer.URL
+			}
+			return ""
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	
+	messages := []core.Message{
+		{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}},
+	}
+	ctx := context.Background()
+	
+	// Default model and base URL
+	result, err := provider.GenerateText(ctx, core.Request{Messages: messages})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if result.Text != "default" {
+		t.Errorf("Expected request to reach default backend, got %q", result.Text)
+	}
+	
+	// Per-request model on the routed backend
+	result, err = provider.GenerateText(ctx, core.Request{
+		Model:    "qwen2.5-7b",
+		Messages: messages,
+		Metadata: map[string]any{"route": "tools"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	if result.Text != "tools" {
+		t.Errorf("Expected request to reach tools backend, got %q", result.Text)
+	}
+	
+	if len(defaultModels) != 1 || defaultModels[0] != "default-model" {
+		t.Errorf("Expected default backend to receive [default-model], got %v", defaultModels)
+	}
+	if len(toolModels) != 1 || toolModels[0] != "qwen2.5-7b" {
+		t.Errorf("Expected tools backend to receive [qwen2.5-7b], got %v", toolModels)
+	}
+	
+	// Invalid URL from BaseURLFn
+	bad, err := New(CompatOpts{
+		BaseURL:   defaultServer.URL,
+		APIKey:    "test-key",
+		BaseURLFn: func(core.Request) string { return "not a url" },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if _, err := bad.GenerateText(ctx, core.Request{Messages: messages}); err == nil {
+		t.Error("Expected error for invalid BaseURLFn result")
+	}
+}
+
+func TestXAIPreset(t *testing.T) {
+	opts := XAIPreset("xai-test-key", "")
+	if opts.BaseURL != "https://api.x.ai/v1" {
+		t.Errorf("Expected xAI base URL, got %s", opts.BaseURL)
+	}
+	if opts.DefaultModel != XAIGrok2 {
+		t.Errorf("Expected default model %s, got %s", XAIGrok2, opts.DefaultModel)
+	}
+	if !opts.DisableStrictJSONSchema {
+		t.Error("Expected strict JSON schema to be disabled for xAI")
+	}
+	
+	tests := []struct {
+		name          string
+		presetModel   string
+		requestModel  string
+		expectedModel string
+	}{
+		{"preset model", XAIGrokBeta, "", XAIGrokBeta},
+		{"request override", XAIGrokBeta, XAIGrok2, XAIGrok2},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// New probes capabilities in the background, so the handler
+			// may still run after GenerateText returns
+			var mu sync.Mutex
+			var gotAuth, gotModel string
+			server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				gotAuth = r.Header.Get("Authorization")
+				mu.Unlock()
+				
+				var req chatCompletionRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				mu.Lock()
+				gotModel = req.Model
+				mu.Unlock()
+				
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(chatCompletionResponse{
+					ID:    "chatcmpl-xai",
+					Model: req.Model,
+					Choices: []choice{
+						{
+							Message:      chatMessage{Role: "assistant", Content: "Hi from Grok"},
+							FinishReason: "stop",
+						},
+					},
+				})
+			})
+			defer server.Close()
+			
+			opts := XAIPreset("xai-test-key", tt.presetModel)
+			opts.BaseURL = server.URL
+			provider, err := New(opts)
+			if err != nil {
+				t.Fatalf("Failed to create provider: %v", err)
+			}
+			
+			_, err = provider.GenerateText(context.Background(), core.Request{
+				Model: tt.requestModel,
+				Messages: []core.Message{
+					{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}},
+				},
+			})
+			if err != nil {
+				t.Fatalf("GenerateText failed: %v", err)
+			}
+			
+			mu.Lock()
+			defer mu.Unlock()
+			if gotAuth != "Bearer xai-test-key" {
+				t.Errorf("Expected Authorization 'Bearer xai-test-key', got %q", gotAuth)
+			}
+			if gotModel != tt.expectedModel {
+				t.Errorf("Expected model %s, got %s", tt.expectedModel, gotModel)
+			}
+		})
+	}
+}
+
+func TestCerebrasPreset(t *testing.T) {
+	opts := CerebrasPreset("csk-test-key", "")
+	if opts.BaseURL != "https://api.cerebras.ai/v1" {
+		t.Errorf("Expected Cerebras base URL, got %s", opts.BaseURL)
+	}
+	if opts.DefaultModel != CerebrasLlama370B {
+		t.Errorf("Expected default model %s, got %s", CerebrasLlama370B, opts.DefaultModel)
+	}
+	if opts.ProviderName != "cerebras" {
+		t.Errorf("Expected provider name cerebras, got %s", opts.ProviderName)
+	}
+	if !opts.DisableJSONStreaming {
+		t.Error("Expected JSON streaming to be disabled for Cerebras")
+	}
+	
+	// New probes capabilities in the background, so the handler may still
+	// run after GenerateText returns
+	var mu sync.Mutex
+	var gotAuth, gotModel, gotProvider string
+	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		gotAuth = r.Header.Get("Authorization")
+		gotProvider = r.Header.Get("X-Provider")
+		mu.Unlock()
+		
+		var req chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		gotModel = req.Model
+		mu.Unlock()
+		
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(chatCompletionResponse{
+			ID:    "chatcmpl-cerebras",
+			Model: req.Model,
+			Choices: []choice{
+				{
+					Message:      chatMessage{Role: "assistant", Content: "Hi from Cerebras"},
+					FinishReason: "stop",
+				},
+			},
+		})
+	})
+	defer server.Close()
+	
+	opts = CerebrasPreset("csk-test-key", CerebrasLlama38B)
+	opts.BaseURL = server.URL
+	provider, err := New(opts)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	
+	_, err = provider.GenerateText(context.Background(), core.Request{
+		Messages: []core.Message{
+			{Role: core.User, Parts: []core.Part{core.Text{Text: "Hello"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateText failed: %v", err)
+	}
+	
+	mu.Lock()
+	defer mu.Unlock()
+	if gotAuth != "Bearer csk-test-key" {
+		t.Errorf("Expected Authorization 'Bearer csk-test-key', got %q", gotAuth)
+	}
+	if gotModel != CerebrasLlama38B {
+		t.Errorf("Expected model %s, got %s", CerebrasLlama38B, gotModel)
+	}
+	if gotProvider != "cerebras" {
+		t.Errorf("Expected X-Provider header cerebras, got %q", gotProvider)
+	}
+}
+
+func TestMapErrorWindowedRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected time.Duration
+	}{
+		{"tokens per minute", http.Header{"X-Ratelimit-Reset-Tokens-Minute": []string{"12"}}, 12 * time.Second},
+		{"requests per day", http.Header{"X-Ratelimit-Reset-Requests-Day": []string{"3600"}}, time.Hour},
+		{"retry-after wins", http.Header{"Retry-After": []string{"5"}, "X-Ratelimit-Reset-Tokens-Minute": []string{"12"}}, 5 * time.Second},
+		{"provider default", http.Header{}, 30 * time.Second},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     tt.header,
+				Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"rate limited","type":"rate_limit_error"}}`)),
+			}
+			
+			err := MapError(resp, "cerebras")
+			if !core.IsRateLimited(err) {
+				t.Fatalf("Expected rate limit error, got %v", err)
+			}
+			if got := core.GetRetryAfter(err); got != tt.expected {
+				t.Errorf("Expected retry after %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestStreamText(t *testing.T) {
 	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/v1/chat/completions" {
@@ -543,13 +862,18 @@ func TestProviderQuirks(t *testing.T) {
 	
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
 			var capturedReq *chatCompletionRequest
 			
 			server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
-				// Capture the request
+				// Capture the chat request, not the background capability probe
 				var req chatCompletionRequest
 				json.NewDecoder(r.Body).Decode(&req)
-				capturedReq = &req
+				if strings.HasSuffix(r.URL.Path, "/chat/completions") {
+					mu.Lock()
+					capturedReq = &req
+					mu.Unlock()
+				}
 				
 				// Return minimal response
 				resp := chatCompletionResponse{
@@ -595,6 +919,8 @@ func TestProviderQuirks(t *testing.T) {
 			ctx := context.Background()
 			provider.GenerateText(ctx, req)
 			
+			mu.Lock()
+			defer mu.Unlock()
 			if capturedReq != nil {
 				tt.checkFunc(t, capturedReq)
 			}
@@ -35,6 +35,20 @@ type toolCallBuilder struct {
 
 // StreamText implements streaming text generation.
 func (p *Provider) StreamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	return core.StreamWithTimeout(ctx, req, func(ctx context.Context) (core.TextStream, error) {
+		return p.streamText(ctx, req)
+	})
+}
+
+// streamText implements StreamText within the request's timeouts.
+func (p *Provider) streamText(ctx context.Context, req core.Request) (core.TextStream, error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	apiReq, err := p.convertRequest(req)
 	if err != nil {
 		return nil, fmt.Errorf("converting request: %w", err)
@@ -58,7 +72,7 @@ func (p *Provider) StreamText(ctx context.Context, req core.Request) (core.TextS
 	streamCtx, cancel := context.WithCancel(ctx)
 	
 	// Make streaming request
-	resp, err := p.doRequest(streamCtx, "POST", "/chat/completions", apiReq)
+	resp, err := p.doRequest(streamCtx, req, "POST", "/chat/completions", apiReq)
 	if err != nil {
 		cancel()
 		return nil, err
@@ -168,8 +182,9 @@ func (s *textStream) process() {
 		if err != nil {
 			if err != io.EOF {
 				s.sendEvent(core.Event{
-					Type: core.EventError,
-					Err:  fmt.Errorf("reading stream: %w", err),
+					Type:      core.EventError,
+					Err:       fmt.Errorf("reading stream: %w", err),
+					ErrorCode: core.ErrorCodeOf(err),
 				})
 			}
 			break
@@ -319,6 +334,20 @@ type objectStream struct {
 
 // StreamObject implements streaming structured output generation.
 func (p *Provider) StreamObject(ctx context.Context, req core.Request, schema interface{}) (core.ObjectStream[any], error) {
+	return core.StreamObjectWithTimeout(ctx, req, func(ctx context.Context) (core.ObjectStream[any], error) {
+		return p.streamObject(ctx, req, schema)
+	})
+}
+
+// streamObject implements StreamObject within the request's timeouts.
+func (p *Provider) streamObject(ctx context.Context, req core.Request, schema interface{}) (core.ObjectStream[any], error) {
+	// Attach metadata, apply the system prompt, resolve the model and
+	// inline images (see core.PrepareRequest)
+	ctx, req, err := core.PrepareRequest(ctx, req, p.requestSetup())
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate JSON schema from the type
 	schemaBytes, err := p.generateJSONSchema(schema)
 	if err != nil {
@@ -373,7 +402,7 @@ func (p *Provider) StreamObject(ctx context.Context, req core.Request, schema in
 	streamCtx, cancel := context.WithCancel(ctx)
 	
 	// Make streaming request
-	resp, err := p.doRequest(streamCtx, "POST", "/chat/completions", apiReq)
+	resp, err := p.doRequest(streamCtx, req, "POST", "/chat/completions", apiReq)
 	if err != nil {
 		cancel()
 		return nil, err
@@ -455,8 +484,9 @@ func (s *objectStream) process() {
 		if err != nil {
 			if err != io.EOF {
 				s.sendEvent(core.Event{
-					Type: core.EventError,
-					Err:  fmt.Errorf("reading stream: %w", err),
+					Type:      core.EventError,
+					Err:       fmt.Errorf("reading stream: %w", err),
+					ErrorCode: core.ErrorCodeOf(err),
 				})
 			}
 			break
@@ -522,8 +552,9 @@ func (s *objectStream) process() {
 		result := reflect.New(reflect.TypeOf(s.schema)).Interface()
 		if err := json.Unmarshal([]byte(s.accumulator.String()), result); err != nil {
 			s.sendEvent(core.Event{
-				Type: core.EventError,
-				Err:  fmt.Errorf("parsing JSON response: %w", err),
+				Type:      core.EventError,
+				Err:       fmt.Errorf("parsing JSON response: %w", err),
+				ErrorCode: core.ErrorCodeOf(err),
 			})
 		} else {
 			// Send the parsed object as raw data
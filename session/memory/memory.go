@@ -0,0 +1,54 @@
+// Package memory provides an in-process core.SessionStore.
+// It is intended for tests, prototypes and single-instance deployments;
+// history is lost when the process exits.
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/recera/gai/core"
+)
+
+// SessionStore keeps conversation history in memory.
+type SessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string][]core.Message
+}
+
+// InMemorySessionStore creates an empty in-memory session store.
+func InMemorySessionStore() *SessionStore {
+	return &SessionStore{
+		sessions: make(map[string][]core.Message),
+	}
+}
+
+// Save replaces the stored history for the session.
+func (s *SessionStore) Save(ctx context.Context, id string, messages []core.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[id] = append([]core.Message(nil), messages...)
+	return nil
+}
+
+// Load returns a copy of the stored history for the session, or nil.
+func (s *SessionStore) Load(ctx context.Context, id string) ([]core.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	messages, ok := s.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	return append([]core.Message(nil), messages...), nil
+}
+
+// Delete removes the stored history for the session.
+func (s *SessionStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+	return nil
+}
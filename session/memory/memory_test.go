@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+func TestInMemorySessionStore(t *testing.T) {
+	store := InMemorySessionStore()
+	ctx := context.Background()
+
+	saved := []core.Message{{Role: core.User, Parts: []core.Part{core.Text{Text: "Hi"}}}}
+	if err := store.Save(ctx, "s1", saved); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Changes to the caller's slice must not affect the store
+	saved[0] = core.Message{Role: core.System}
+
+	loaded, err := store.Load(ctx, "s1")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Role != core.User {
+		t.Errorf("Unexpected loaded messages: %+v", loaded)
+	}
+
+	if err := store.Delete(ctx, "s1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if loaded, _ := store.Load(ctx, "s1"); loaded != nil {
+		t.Errorf("Expected no messages after delete, got %+v", loaded)
+	}
+}
@@ -0,0 +1,105 @@
+// Package redis provides a core.SessionStore backed by Redis.
+//
+// To avoid a hard dependency on a particular Redis driver, the store talks
+// to Redis through the small Client interface. Adapting go-redis takes a few
+// lines:
+//
+//	type goRedisClient struct{ rdb *goredis.Client }
+//
+//	func (c goRedisClient) Get(ctx context.Context, key string) ([]byte, error) {
+//	    data, err := c.rdb.Get(ctx, key).Bytes()
+//	    if errors.Is(err, goredis.Nil) {
+//	        return nil, nil
+//	    }
+//	    return data, err
+//	}
+//
+//	func (c goRedisClient) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+//	    return c.rdb.Set(ctx, key, value, ttl).Err()
+//	}
+//
+//	store := redis.RedisSessionStore(goRedisClient{rdb}, redis.WithTTL(24*time.Hour))
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// Client is the subset of Redis commands used by the session store.
+type Client interface {
+	// Get returns the value stored at key, or nil if the key does not exist
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value at key with the given expiration (0 means no expiration)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// SessionStore stores conversation history as JSON in Redis.
+type SessionStore struct {
+	client    Client
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// Option configures a SessionStore.
+type Option func(*SessionStore)
+
+// WithKeyPrefix sets the prefix for session keys (default "gai:session:").
+func WithKeyPrefix(prefix string) Option {
+	return func(s *SessionStore) {
+		s.keyPrefix = prefix
+	}
+}
+
+// WithTTL sets how long sessions are kept after their last update.
+// The default of 0 keeps sessions indefinitely.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *SessionStore) {
+		s.ttl = ttl
+	}
+}
+
+// RedisSessionStore creates a session store using the given Redis client.
+func RedisSessionStore(client Client, opts ...Option) *SessionStore {
+	s := &SessionStore{
+		client:    client,
+		keyPrefix: "gai:session:",
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Save replaces the stored history for the session.
+func (s *SessionStore) Save(ctx context.Context, id string, messages []core.Message) error {
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("encoding session: %w", err)
+	}
+	if err := s.client.Set(ctx, s.keyPrefix+id, data, s.ttl); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+// Load returns the stored history for the session, or nil if there is none.
+func (s *SessionStore) Load(ctx context.Context, id string) ([]core.Message, error) {
+	data, err := s.client.Get(ctx, s.keyPrefix+id)
+	if err != nil {
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+
+	var messages []core.Message
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("decoding session: %w", err)
+	}
+	return messages, nil
+}
@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// fakeClient is an in-memory Client that records TTLs.
+type fakeClient struct {
+	data map[string][]byte
+	ttls map[string]time.Duration
+	err  error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{data: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (c *fakeClient) Get(ctx context.Context, key string) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.data[key], nil
+}
+
+func (c *fakeClient) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.data[key] = value
+	c.ttls[key] = ttl
+	return nil
+}
+
+func TestRedisSessionStore(t *testing.T) {
+	client := newFakeClient()
+	store := RedisSessionStore(client, WithKeyPrefix("app:"), WithTTL(time.Hour))
+	ctx := context.Background()
+
+	messages, err := store.Load(ctx, "s1")
+	if err != nil || messages != nil {
+		t.Fatalf("Expected empty session, got %v, %v", messages, err)
+	}
+
+	saved := []core.Message{
+		{Role: core.User, Parts: []core.Part{core.Text{Text: "Hi"}}},
+		{Role: core.Assistant, Parts: []core.Part{core.Text{Text: "Hello"}}},
+	}
+	if err := store.Save(ctx, "s1", saved); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if client.ttls["app:s1"] != time.Hour {
+		t.Errorf("Expected key app:s1 with 1h TTL, got %v", client.ttls)
+	}
+
+	loaded, err := store.Load(ctx, "s1")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded) != 2 || loaded[1].Parts[0].(core.Text).Text != "Hello" {
+		t.Errorf("Unexpected loaded messages: %+v", loaded)
+	}
+}
+
+func TestRedisSessionStoreErrors(t *testing.T) {
+	client := newFakeClient()
+	store := RedisSessionStore(client)
+	ctx := context.Background()
+
+	client.data["gai:session:bad"] = []byte("not json")
+	if _, err := store.Load(ctx, "bad"); err == nil {
+		t.Error("Expected decode error")
+	}
+
+	client.err = errors.New("connection refused")
+	if _, err := store.Load(ctx, "s1"); !errors.Is(err, client.err) {
+		t.Errorf("Expected wrapped client error, got %v", err)
+	}
+	if err := store.Save(ctx, "s1", nil); !errors.Is(err, client.err) {
+		t.Errorf("Expected wrapped client error, got %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+// Package stream provides streaming utilities for AI responses.
+// This file implements a bounded event buffer that protects handlers from
+// slow consumers by dropping old events or applying backpressure.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/recera/gai/core"
+)
+
+// BufferMode determines what an EventBuffer does when it is full.
+type BufferMode int
+
+const (
+	// BufferDropOldest discards the oldest buffered events to make room (lossy, default)
+	BufferDropOldest BufferMode = iota
+	// BufferBlock blocks Send until the consumer frees space (lossless)
+	BufferBlock
+)
+
+// errBufferClosed is returned by Send after Close.
+var errBufferClosed = errors.New("event buffer closed")
+
+// eventOverhead approximates the fixed serialized size of an event.
+const eventOverhead = 64
+
+// EventBuffer is a FIFO queue of stream events bounded by event count and
+// approximate size in bytes. It sits between a provider stream and a slow
+// HTTP writer so that memory use stays bounded. It is safe for concurrent use.
+type EventBuffer struct {
+	maxEvents int
+	maxBytes  int
+	mode      BufferMode
+
+	mu      sync.Mutex
+	events  []core.Event
+	sizes   []int
+	bytes   int
+	dropped int
+	closed  bool
+	changed chan struct{}
+}
+
+// NewEventBuffer creates a buffer holding at most maxEvents events and
+// maxBytes bytes; zero means no limit for that dimension. The mode defaults
+// to BufferDropOldest.
+//
+// Example:
+//
+//	buf := stream.NewEventBuffer(1000, 1<<20, stream.BufferBlock)
+//	err := stream.SSE(w, s, stream.SSEOptions{EventBuffer: buf})
+func NewEventBuffer(maxEvents, maxBytes int, mode ...BufferMode) *EventBuffer {
+	b := &EventBuffer{
+		maxEvents: maxEvents,
+		maxBytes:  maxBytes,
+		changed:   make(chan struct{}),
+	}
+	if len(mode) > 0 {
+		b.mode = mode[0]
+	}
+	return b
+}
+
+// Send adds an event to the buffer. When the buffer is full it drops the
+// oldest events (BufferDropOldest) or blocks until space is available or ctx
+// is done (BufferBlock). A single event larger than maxBytes is still accepted
+// once the buffer is empty.
+func (b *EventBuffer) Send(ctx context.Context, event core.Event) error {
+	size := eventSize(event)
+
+	b.mu.Lock()
+	for {
+		if b.closed {
+			b.mu.Unlock()
+			return errBufferClosed
+		}
+		if !b.full(size) {
+			break
+		}
+		if b.mode == BufferDropOldest {
+			b.removeFirst()
+			b.dropped++
+			continue
+		}
+
+		changed := b.changed
+		b.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		b.mu.Lock()
+	}
+
+	b.events = append(b.events, event)
+	b.sizes = append(b.sizes, size)
+	b.bytes += size
+	b.broadcast()
+	b.mu.Unlock()
+	return nil
+}
+
+// Receive removes and returns the oldest event, blocking until one is
+// available or ctx is done. It returns io.EOF once the buffer is closed and empty.
+func (b *EventBuffer) Receive(ctx context.Context) (core.Event, error) {
+	b.mu.Lock()
+	for len(b.events) == 0 {
+		if b.closed {
+			b.mu.Unlock()
+			return core.Event{}, io.EOF
+		}
+
+		changed := b.changed
+		b.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return core.Event{}, ctx.Err()
+		}
+		b.mu.Lock()
+	}
+
+	event := b.events[0]
+	b.removeFirst()
+	b.broadcast()
+	b.mu.Unlock()
+	return event, nil
+}
+
+// Close marks the end of the event sequence. Buffered events can still be
+// received; further sends fail.
+func (b *EventBuffer) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		b.broadcast()
+	}
+}
+
+// Len returns the number of buffered events.
+func (b *EventBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.events)
+}
+
+// DropCount returns how many events were dropped because the buffer was full.
+func (b *EventBuffer) DropCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
+
+// full reports whether adding an event of the given size exceeds a limit.
+func (b *EventBuffer) full(size int) bool {
+	if len(b.events) == 0 {
+		return false
+	}
+	if b.maxEvents > 0 && len(b.events) >= b.maxEvents {
+		return true
+	}
+	return b.maxBytes > 0 && b.bytes+size > b.maxBytes
+}
+
+// removeFirst drops the oldest event. The caller must hold mu.
+func (b *EventBuffer) removeFirst() {
+	b.bytes -= b.sizes[0]
+	b.events[0] = core.Event{}
+	b.events = b.events[1:]
+	b.sizes = b.sizes[1:]
+}
+
+// broadcast wakes all goroutines waiting for a change. The caller must hold mu.
+func (b *EventBuffer) broadcast() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// eventSize approximates the serialized size of an event in bytes.
+func eventSize(event core.Event) int {
+	size := eventOverhead + len(event.TextDelta) + len(event.AudioChunk) +
+		len(event.ToolName) + len(event.ToolID) + len(event.ToolInput)
+	if event.ToolResult != nil {
+		if data, err := json.Marshal(event.ToolResult); err == nil {
+			size += len(data)
+		}
+	}
+	return size
+}
+
+// BufferedStream returns a stream that reads events from stream into buf as
+// fast as they arrive and delivers them from buf to the consumer. Closing the
+// returned stream closes the underlying stream.
+func BufferedStream(stream core.TextStream, buf *EventBuffer) core.TextStream {
+	return newBufferedStream(stream, buf)
+}
+
+// newBufferedStream starts the goroutines that move events through buf.
+// Cancelling the stream's context stops them without closing the source.
+func newBufferedStream(stream core.TextStream, buf *EventBuffer) *bufferedStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &bufferedStream{
+		source: stream,
+		buf:    buf,
+		events: make(chan core.Event),
+		cancel: cancel,
+	}
+	go s.fill(ctx)
+	go s.drain(ctx)
+	return s
+}
+
+// bufferedStream delivers events from an EventBuffer.
+type bufferedStream struct {
+	source    core.TextStream
+	buf       *EventBuffer
+	events    chan core.Event
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+// fill copies events from the source stream into the buffer.
+func (s *bufferedStream) fill(ctx context.Context) {
+	defer s.buf.Close()
+	for event := range s.source.Events() {
+		if err := s.buf.Send(ctx, event); err != nil {
+			return
+		}
+	}
+}
+
+// drain delivers buffered events to the consumer.
+func (s *bufferedStream) drain(ctx context.Context) {
+	defer close(s.events)
+	for {
+		event, err := s.buf.Receive(ctx)
+		if err != nil {
+			return
+		}
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Events implements core.TextStream.
+func (s *bufferedStream) Events() <-chan core.Event {
+	return s.events
+}
+
+// Close implements core.TextStream.
+func (s *bufferedStream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.cancel()
+		err = s.source.Close()
+	})
+	return err
+}
+
+// HandlerOption configures SSEHandler and NDJSONHandler.
+type HandlerOption func(*handlerConfig)
+
+// handlerConfig holds the settings applied by HandlerOptions.
+type handlerConfig struct {
+	newBuffer func() *EventBuffer
+}
+
+// WithEventBuffer returns a HandlerOption that streams each response through
+// an EventBuffer created by newBuffer, bounding the memory held for clients
+// that read slower than the model generates. newBuffer is called once per
+// request, so it can also register the buffer for DropCount monitoring.
+//
+// Example:
+//
+//	handler := stream.SSEHandler(provider, prepare, stream.WithEventBuffer(func() *stream.EventBuffer {
+//		return stream.NewEventBuffer(1000, 1<<20)
+//	}))
+func WithEventBuffer(newBuffer func() *EventBuffer) HandlerOption {
+	return func(c *handlerConfig) {
+		c.newBuffer = newBuffer
+	}
+}
+
+// newHandlerConfig applies opts to an empty handler configuration.
+func newHandlerConfig(opts []HandlerOption) handlerConfig {
+	var c handlerConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// eventBuffer returns a new buffer for a request, or nil if none is configured.
+func (c handlerConfig) eventBuffer() *EventBuffer {
+	if c.newBuffer == nil {
+		return nil
+	}
+	return c.newBuffer()
+}
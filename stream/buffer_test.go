@@ -0,0 +1,189 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+func textEvent(text string) core.Event {
+	return core.Event{Type: core.EventTextDelta, TextDelta: text}
+}
+
+func TestEventBufferDropOldest(t *testing.T) {
+	ctx := context.Background()
+	buf := NewEventBuffer(2, 0)
+
+	for _, text := range []string{"a", "b", "c", "d"} {
+		if err := buf.Send(ctx, textEvent(text)); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+
+	if buf.Len() != 2 {
+		t.Errorf("Expected 2 buffered events, got %d", buf.Len())
+	}
+	if buf.DropCount() != 2 {
+		t.Errorf("Expected 2 dropped events, got %d", buf.DropCount())
+	}
+
+	buf.Close()
+	var got []string
+	for {
+		event, err := buf.Receive(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Receive failed: %v", err)
+		}
+		got = append(got, event.TextDelta)
+	}
+	if strings.Join(got, "") != "cd" {
+		t.Errorf("Expected newest events cd, got %v", got)
+	}
+
+	if err := buf.Send(ctx, textEvent("e")); err == nil {
+		t.Error("Expected Send after Close to fail")
+	}
+}
+
+func TestEventBufferByteLimit(t *testing.T) {
+	ctx := context.Background()
+	size := eventSize(textEvent("0123456789"))
+	buf := NewEventBuffer(0, 2*size)
+
+	for i := 0; i < 3; i++ {
+		buf.Send(ctx, textEvent("0123456789"))
+	}
+	if buf.Len() != 2 || buf.DropCount() != 1 {
+		t.Errorf("Expected 2 buffered and 1 dropped, got %d and %d", buf.Len(), buf.DropCount())
+	}
+
+	// An event larger than the limit is still accepted on its own
+	large := textEvent(strings.Repeat("x", 4*size))
+	if err := buf.Send(ctx, large); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if buf.Len() != 1 || buf.DropCount() != 3 {
+		t.Errorf("Expected only the large event, got %d buffered and %d dropped", buf.Len(), buf.DropCount())
+	}
+}
+
+func TestEventBufferBlock(t *testing.T) {
+	ctx := context.Background()
+	buf := NewEventBuffer(1, 0, BufferBlock)
+
+	if err := buf.Send(ctx, textEvent("a")); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	sent := make(chan error, 1)
+	go func() { sent <- buf.Send(ctx, textEvent("b")) }()
+
+	select {
+	case <-sent:
+		t.Fatal("Expected Send to block while the buffer is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	event, err := buf.Receive(ctx)
+	if err != nil || event.TextDelta != "a" {
+		t.Fatalf("Receive = %v, %v", event, err)
+	}
+	select {
+	case err := <-sent:
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Send to resume after Receive")
+	}
+	if buf.DropCount() != 0 {
+		t.Errorf("Expected no drops in block mode, got %d", buf.DropCount())
+	}
+
+	// A blocked Send honours its context
+	cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := buf.Send(cctx, textEvent("c")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestBufferedStream(t *testing.T) {
+	source := newMockTextStream()
+	buf := NewEventBuffer(0, 0)
+	stream := BufferedStream(source, buf)
+	defer stream.Close()
+
+	go func() {
+		for _, text := range []string{"Hello", " ", "world"} {
+			source.sendEvent(textEvent(text))
+		}
+		source.sendEvent(core.Event{Type: core.EventFinish})
+		source.Close()
+	}()
+
+	var text strings.Builder
+	var finished bool
+	for event := range stream.Events() {
+		text.WriteString(event.TextDelta)
+		finished = finished || event.Type == core.EventFinish
+	}
+	if text.String() != "Hello world" || !finished {
+		t.Errorf("Expected all events delivered, got %q (finished=%v)", text.String(), finished)
+	}
+}
+
+func TestHandlersWithEventBuffer(t *testing.T) {
+	handlers := map[string]func(core.Provider, func(*http.Request) (core.Request, error), ...HandlerOption) http.HandlerFunc{
+		"sse":    SSEHandler,
+		"ndjson": NDJSONHandler,
+	}
+
+	for name, newHandler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			provider := &mockProvider{
+				streamFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+					stream := newMockTextStream()
+					go func() {
+						stream.sendEvent(textEvent("Buffered response"))
+						stream.sendEvent(core.Event{Type: core.EventFinish})
+						stream.Close()
+					}()
+					return stream, nil
+				},
+			}
+
+			var buffers []*EventBuffer
+			handler := newHandler(provider, func(r *http.Request) (core.Request, error) {
+				return core.Request{}, nil
+			}, WithEventBuffer(func() *EventBuffer {
+				buf := NewEventBuffer(100, 1<<20)
+				buffers = append(buffers, buf)
+				return buf
+			}))
+
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest("GET", "/stream", nil))
+
+			if !strings.Contains(rec.Body.String(), "Buffered response") {
+				t.Errorf("Response doesn't contain expected text: %s", rec.Body.String())
+			}
+			if len(buffers) != 1 {
+				t.Fatalf("Expected one buffer per request, got %d", len(buffers))
+			}
+			if buffers[0].DropCount() != 0 {
+				t.Errorf("Expected no drops, got %d", buffers[0].DropCount())
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/recera/gai/core"
+)
+
+// ChatRequestSchema is the JSON schema that NewChatEndpoint validates request
+// bodies against.
+const ChatRequestSchema = `{
+	"type": "object",
+	"required": ["messages"],
+	"additionalProperties": false,
+	"properties": {
+		"messages": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"type": "object",
+				"required": ["role", "content"],
+				"additionalProperties": false,
+				"properties": {
+					"role": {"type": "string", "enum": ["system", "user", "assistant"]},
+					"content": {"type": "string"}
+				}
+			}
+		},
+		"model": {"type": "string"},
+		"temperature": {"type": "number", "minimum": 0, "maximum": 2},
+		"max_tokens": {"type": "integer", "minimum": 1},
+		"stream": {"type": "boolean"},
+		"tools": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+// ChatMessage is a single message in a ChatRequest.
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest is the request body accepted by NewChatEndpoint.
+type ChatRequest struct {
+	Messages    []ChatMessage `json:"messages"`
+	Model       string        `json:"model,omitempty"`
+	Temperature float32       `json:"temperature,omitempty"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+	// Tools names the tools from ChatEndpointOpts.Tools to enable
+	Tools []string `json:"tools,omitempty"`
+}
+
+// ChatResponse is the body returned by NewChatEndpoint for non-streaming requests.
+type ChatResponse struct {
+	Text  string     `json:"text"`
+	Usage core.Usage `json:"usage"`
+}
+
+// ChatEndpointOpts configures NewChatEndpoint.
+type ChatEndpointOpts struct {
+	// AllowedOrigins lists the origins allowed by CORS ("*" allows any).
+	// Empty keeps the permissive headers set by SSE and NDJSON.
+	AllowedOrigins []string
+	// Auth wraps the endpoint, for example to check an API key. CORS
+	// preflight requests are answered without calling it.
+	Auth func(http.Handler) http.Handler
+	// MaxBodyBytes limits the request body size (default 1 MiB)
+	MaxBodyBytes int64
+	// Tools are the tools a request may enable by name
+	Tools []core.ToolHandle
+	// HandlerOptions configure streaming, for example WithEventBuffer
+	HandlerOptions []HandlerOption
+}
+
+// defaultChatBodyLimit is the request body limit when MaxBodyBytes is unset.
+const defaultChatBodyLimit = 1 << 20
+
+// chatEndpoint implements the handler returned by NewChatEndpoint.
+type chatEndpoint struct {
+	provider core.Provider
+	opts     ChatEndpointOpts
+	config   handlerConfig
+	tools    map[string]core.ToolHandle
+	handler  http.Handler
+}
+
+// NewChatEndpoint creates an HTTP handler for a typical web chat backend. It
+// accepts POSTed ChatRequest bodies, validates them against
+// ChatRequestSchema and converts them to a core.Request. Streaming requests
+// are answered as SSE or NDJSON depending on the Accept header; other
+// requests receive a JSON ChatResponse. Invalid bodies get 400, oversized
+// bodies 413 and provider failures 500.
+//
+// Example:
+//
+//	http.Handle("/api/chat", stream.NewChatEndpoint(provider, stream.ChatEndpointOpts{
+//		AllowedOrigins: []string{"https://app.example.com"},
+//		Tools:          tools.ToCoreHandles([]tools.Handle{weatherTool}),
+//	}))
+func NewChatEndpoint(provider core.Provider, opts ChatEndpointOpts) http.Handler {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = defaultChatBodyLimit
+	}
+
+	e := &chatEndpoint{
+		provider: provider,
+		opts:     opts,
+		config:   newHandlerConfig(opts.HandlerOptions),
+		tools:    make(map[string]core.ToolHandle, len(opts.Tools)),
+	}
+	for _, tool := range opts.Tools {
+		e.tools[tool.Name()] = tool
+	}
+
+	e.handler = http.HandlerFunc(e.serveChat)
+	if opts.Auth != nil {
+		e.handler = opts.Auth(e.handler)
+	}
+	return e
+}
+
+// ServeHTTP implements http.Handler.
+func (e *chatEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !e.applyCORS(w, r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	e.handler.ServeHTTP(w, r)
+}
+
+// applyCORS sets CORS headers for the request's origin and reports whether
+// the origin is allowed.
+func (e *chatEndpoint) applyCORS(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if len(e.opts.AllowedOrigins) == 0 || origin == "" {
+		return true
+	}
+
+	for _, allowed := range e.opts.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			h := w.Header()
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			h.Add("Vary", "Origin")
+			return true
+		}
+	}
+	return false
+}
+
+// serveChat handles an authenticated chat request.
+func (e *chatEndpoint) serveChat(w http.ResponseWriter, r *http.Request) {
+	req, err := e.parseRequest(w, r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !req.Stream {
+		result, err := e.provider.GenerateText(r.Context(), req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{Text: result.Text, Usage: result.Usage})
+		return
+	}
+
+	stream, err := e.provider.StreamText(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer stream.Close()
+
+	w = streamingWriter(w, r)
+	if detectFormat(r) == "ndjson" {
+		options := DefaultNDJSONOptions()
+		options.EventBuffer = e.config.eventBuffer()
+		err = NDJSON(w, stream, options)
+	} else {
+		options := DefaultSSEOptions()
+		options.EventBuffer = e.config.eventBuffer()
+		err = SSE(w, stream, options)
+	}
+	if err != nil {
+		// Log error but don't write (headers already sent)
+		_ = err
+	}
+}
+
+// parseRequest reads, validates and converts the request body.
+func (e *chatEndpoint) parseRequest(w http.ResponseWriter, r *http.Request) (core.Request, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, e.opts.MaxBodyBytes))
+	if err != nil {
+		return core.Request{}, err
+	}
+	if err := core.ValidateJSON(body, []byte(ChatRequestSchema)); err != nil {
+		return core.Request{}, fmt.Errorf("invalid request: %w", err)
+	}
+
+	var chat ChatRequest
+	if err := json.Unmarshal(body, &chat); err != nil {
+		return core.Request{}, fmt.Errorf("invalid request: %w", err)
+	}
+	return e.toCoreRequest(chat)
+}
+
+// toCoreRequest converts a validated ChatRequest to a core.Request.
+func (e *chatEndpoint) toCoreRequest(chat ChatRequest) (core.Request, error) {
+	req := core.Request{
+		Model:           chat.Model,
+		Temperature:     chat.Temperature,
+		MaxOutputTokens: chat.MaxTokens,
+		Stream:          chat.Stream,
+		Messages:        make([]core.Message, 0, len(chat.Messages)),
+	}
+	for _, msg := range chat.Messages {
+		req.Messages = append(req.Messages, core.Message{
+			Role:  core.Role(msg.Role),
+			Parts: []core.Part{core.Text{Text: msg.Content}},
+		})
+	}
+	for _, name := range chat.Tools {
+		tool, ok := e.tools[name]
+		if !ok {
+			return core.Request{}, fmt.Errorf("invalid request: unknown tool %q", name)
+		}
+		req.Tools = append(req.Tools, tool)
+	}
+	return req, nil
+}
@@ -0,0 +1,162 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+func TestChatEndpoint_Generate(t *testing.T) {
+	var got core.Request
+	provider := &mockProvider{
+		generateFunc: func(ctx context.Context, req core.Request) (*core.TextResult, error) {
+			got = req
+			return &core.TextResult{Text: "Paris", Usage: core.Usage{InputTokens: 3, OutputTokens: 1, TotalTokens: 4}}, nil
+		},
+	}
+	handler := NewChatEndpoint(provider, ChatEndpointOpts{})
+
+	body := `{"messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"Capital of France?"}],"model":"gpt-4o","temperature":0.5,"max_tokens":50}`
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %q", rec.Code, rec.Body.String())
+	}
+	var resp ChatResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.Text != "Paris" || resp.Usage.TotalTokens != 4 {
+		t.Errorf("response = %+v", resp)
+	}
+
+	if got.Model != "gpt-4o" || got.Temperature != 0.5 || got.MaxOutputTokens != 50 {
+		t.Errorf("request parameters not converted: %+v", got)
+	}
+	if len(got.Messages) != 2 || got.Messages[0].Role != core.System || got.Messages[1].Role != core.User {
+		t.Fatalf("messages not converted: %+v", got.Messages)
+	}
+	if text := got.Messages[1].Parts[0].(core.Text).Text; text != "Capital of France?" {
+		t.Errorf("message text = %q", text)
+	}
+}
+
+func TestChatEndpoint_Stream(t *testing.T) {
+	provider := &mockProvider{
+		streamFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			stream := newMockTextStream()
+			stream.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "Hello"})
+			stream.sendEvent(core.Event{Type: core.EventFinish})
+			stream.Close()
+			return stream, nil
+		},
+	}
+	handler := NewChatEndpoint(provider, ChatEndpointOpts{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{"messages":[{"role":"user","content":"Hi"}],"stream":true}`))
+	req.Header.Set("Accept", "application/x-ndjson")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("content type = %q, want application/x-ndjson", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "Hello") {
+		t.Errorf("body does not contain streamed text: %q", rec.Body.String())
+	}
+}
+
+func TestChatEndpoint_RejectsInvalidRequests(t *testing.T) {
+	handler := NewChatEndpoint(&mockProvider{}, ChatEndpointOpts{
+		MaxBodyBytes: 256,
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+	}{
+		{"no messages", http.MethodPost, `{"messages":[]}`, http.StatusBadRequest},
+		{"bad role", http.MethodPost, `{"messages":[{"role":"robot","content":"hi"}]}`, http.StatusBadRequest},
+		{"temperature out of range", http.MethodPost, `{"messages":[{"role":"user","content":"hi"}],"temperature":3}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, `{"messages":[{"role":"user","content":"hi"}],"top_p":1}`, http.StatusBadRequest},
+		{"unknown tool", http.MethodPost, `{"messages":[{"role":"user","content":"hi"}],"tools":["search"]}`, http.StatusBadRequest},
+		{"malformed JSON", http.MethodPost, `{"messages":`, http.StatusBadRequest},
+		{"too large", http.MethodPost, `{"messages":[{"role":"user","content":"` + strings.Repeat("x", 300) + `"}]}`, http.StatusRequestEntityTooLarge},
+		{"wrong method", http.MethodGet, ``, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, "/api/chat", strings.NewReader(tt.body)))
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d (body %q)", rec.Code, tt.status, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestChatEndpoint_CORSAndAuth(t *testing.T) {
+	authCalls := 0
+	handler := NewChatEndpoint(&mockProvider{}, ChatEndpointOpts{
+		AllowedOrigins: []string{"https://app.example.com"},
+		Auth: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authCalls++
+				if r.Header.Get("Authorization") != "Bearer secret" {
+					http.Error(w, "unauthorized", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		},
+	})
+
+	// Preflight from an allowed origin skips auth
+	req := httptest.NewRequest(http.MethodOptions, "/api/chat", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("preflight status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("allow origin = %q", got)
+	}
+	if authCalls != 0 {
+		t.Errorf("auth called for preflight")
+	}
+
+	// Disallowed origin
+	req = httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{}`))
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("disallowed origin status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	// Missing credentials
+	req = httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{"messages":[{"role":"user","content":"hi"}]}`))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	// Authenticated
+	req = httptest.NewRequest(http.MethodPost, "/api/chat", strings.NewReader(`{"messages":[{"role":"user","content":"hi"}]}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("authenticated status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,89 @@
+// Package stream provides streaming utilities for AI responses.
+// This file implements Collect for consuming a stream synchronously.
+package stream
+
+import (
+	"context"
+	"errors"
+
+	"github.com/recera/gai/core"
+)
+
+// Collect drains a TextStream and assembles a TextResult equivalent to what
+// GenerateText would return. Text deltas are concatenated into Text, tool
+// calls and results are grouped into Steps at each EventFinishStep, and usage
+// is taken from the EventFinish event (or summed from step events).
+//
+// Collection ends at EventFinish or when the events channel is closed, and
+// the stream is closed before Collect returns. If ctx is cancelled first,
+// Collect stops reading and returns ctx.Err(). An EventError aborts collection
+// and returns its error.
+//
+// Example:
+//
+//	s, err := provider.StreamText(ctx, req)
+//	if err != nil {
+//	    return err
+//	}
+//	result, err := stream.Collect(ctx, s)
+func Collect(ctx context.Context, s core.TextStream) (*core.TextResult, error) {
+	defer s.Close()
+
+	c := &collector{}
+	events := s.Events()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case event, ok := <-events:
+			if !ok {
+				return c.result(), nil
+			}
+			if err := c.add(event); err != nil {
+				return nil, err
+			}
+			if event.Type == core.EventFinish {
+				return c.result(), nil
+			}
+		}
+	}
+}
+
+// collector accumulates stream events into a TextResult.
+type collector struct {
+	steps core.StepAccumulator
+}
+
+// add processes a single event.
+func (c *collector) add(event core.Event) error {
+	c.steps.Add(event)
+	if failed, err := c.steps.Failed(); failed {
+		if err != nil {
+			return err
+		}
+		return errors.New("stream error")
+	}
+	return nil
+}
+
+// result builds the final TextResult.
+func (c *collector) result() *core.TextResult {
+	result := c.steps.Result()
+
+	// Steps are only reported for multi-step runs, matching GenerateText
+	if len(result.Steps) <= 1 && !hasToolActivity(result.Steps) {
+		result.Steps = nil
+	}
+
+	return result
+}
+
+// hasToolActivity reports whether any step called a tool.
+func hasToolActivity(steps []core.Step) bool {
+	for _, step := range steps {
+		if len(step.ToolCalls) > 0 || len(step.ToolResults) > 0 {
+			return true
+		}
+	}
+	return false
+}
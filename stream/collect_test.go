@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+func TestCollect(t *testing.T) {
+	s := newMockTextStream()
+	s.sendEvent(core.Event{Type: core.EventStart})
+	s.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "Hello, "})
+	s.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "world!"})
+	s.sendEvent(core.Event{Type: core.EventFinish, Usage: &core.Usage{InputTokens: 5, OutputTokens: 3, TotalTokens: 8}})
+
+	result, err := Collect(context.Background(), s)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if result.Text != "Hello, world!" {
+		t.Errorf("expected 'Hello, world!', got %q", result.Text)
+	}
+	if result.Usage.TotalTokens != 8 {
+		t.Errorf("expected 8 total tokens, got %d", result.Usage.TotalTokens)
+	}
+	if len(result.Steps) != 0 {
+		t.Errorf("expected no steps for single-step stream, got %d", len(result.Steps))
+	}
+	if !s.closed {
+		t.Error("expected stream to be closed")
+	}
+}
+
+func TestCollectToolSteps(t *testing.T) {
+	s := newMockTextStream()
+	s.sendEvent(core.Event{Type: core.EventStart})
+	s.sendEvent(core.Event{Type: core.EventToolCall, ToolID: "call_1", ToolName: "get_weather", ToolInput: json.RawMessage(`{"city":"Paris"}`)})
+	s.sendEvent(core.Event{Type: core.EventToolResult, ToolID: "call_1", ToolName: "get_weather", ToolResult: map[string]any{"temp": 21}})
+	s.sendEvent(core.Event{Type: core.EventFinishStep, StepNumber: 1, Usage: &core.Usage{TotalTokens: 10}})
+	s.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "It is 21°C in Paris."})
+	s.sendEvent(core.Event{Type: core.EventFinishStep, StepNumber: 2, Usage: &core.Usage{TotalTokens: 15, CacheReadTokens: 4}})
+	s.sendEvent(core.Event{Type: core.EventFinish})
+
+	result, err := Collect(context.Background(), s)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if result.Text != "It is 21°C in Paris." {
+		t.Errorf("unexpected text: %q", result.Text)
+	}
+	if len(result.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(result.Steps))
+	}
+
+	first := result.Steps[0]
+	if len(first.ToolCalls) != 1 || first.ToolCalls[0].Name != "get_weather" || first.ToolCalls[0].ID != "call_1" {
+		t.Errorf("unexpected tool calls: %+v", first.ToolCalls)
+	}
+	if len(first.ToolResults) != 1 || first.ToolResults[0].Result == nil {
+		t.Errorf("unexpected tool results: %+v", first.ToolResults)
+	}
+	if result.Steps[1].Text != "It is 21°C in Paris." || result.Steps[1].StepNumber != 2 {
+		t.Errorf("unexpected second step: %+v", result.Steps[1])
+	}
+
+	// Without usage on the finish event, step usage is summed
+	if result.Usage.TotalTokens != 25 || result.Usage.CacheReadTokens != 4 {
+		t.Errorf("expected 25 total and 4 cache read tokens, got %+v", result.Usage)
+	}
+	if first.Usage.TotalTokens != 10 || result.Steps[1].Usage.CacheReadTokens != 4 {
+		t.Errorf("expected each step to keep its usage, got %+v and %+v", first.Usage, result.Steps[1].Usage)
+	}
+}
+
+func TestCollectError(t *testing.T) {
+	s := newMockTextStream()
+	s.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "partial"})
+	s.sendEvent(core.Event{Type: core.EventError, Err: errors.New("connection reset")})
+
+	_, err := Collect(context.Background(), s)
+	if err == nil || err.Error() != "connection reset" {
+		t.Errorf("expected stream error, got %v", err)
+	}
+}
+
+func TestCollectContextCancellation(t *testing.T) {
+	s := newMockTextStream()
+	s.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "never finishes"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := Collect(ctx, s)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if !s.closed {
+		t.Error("expected stream to be closed after cancellation")
+	}
+}
@@ -0,0 +1,94 @@
+// Package stream provides streaming utilities for AI responses.
+// This file implements flushing and response headers that keep streams
+// real-time over HTTP/2 and behind buffering proxies.
+package stream
+
+import (
+	"net/http"
+	"sync"
+)
+
+// connectionHeaders are HTTP/1.1 connection-specific headers, which are
+// forbidden in HTTP/2 responses (RFC 9113, section 8.2.2).
+var connectionHeaders = []string{"Connection", "Keep-Alive", "Transfer-Encoding"}
+
+// setNoBufferingHeaders sets headers that stop caches, proxies and
+// compression middleware from holding back streamed events.
+func setNoBufferingHeaders(h http.Header) {
+	h.Set("Cache-Control", "no-cache, no-transform")
+	h.Set("X-Accel-Buffering", "no") // Disable Nginx buffering
+	// Compressing middleware buffers output until it has a full block;
+	// most skip responses that already declare an encoding
+	h.Set("Content-Encoding", "identity")
+}
+
+// findFlusher returns the http.Flusher behind w, unwrapping ResponseWriters
+// that middleware wrapped with an Unwrap method, as http.ResponseController
+// does.
+func findFlusher(w http.ResponseWriter) (http.Flusher, bool) {
+	for {
+		if flusher, ok := w.(http.Flusher); ok {
+			return flusher, true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil, false
+		}
+		w = u.Unwrap()
+	}
+}
+
+// streamingWriter prepares w for streaming the response to r. HTTP/2
+// responses are wrapped so that HTTP/1.1 connection headers are dropped
+// before the headers are sent, and flushes reach the underlying writer.
+func streamingWriter(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	if r.ProtoMajor < 2 {
+		return w
+	}
+	flusher, ok := findFlusher(w)
+	if !ok {
+		return w
+	}
+	return &http2Writer{ResponseWriter: w, flusher: flusher}
+}
+
+// http2Writer is a streaming ResponseWriter for HTTP/2 requests.
+type http2Writer struct {
+	http.ResponseWriter
+	flusher    http.Flusher
+	headerOnce sync.Once
+}
+
+// stripConnectionHeaders removes headers HTTP/2 does not allow. It runs
+// once, before the headers are first sent.
+func (w *http2Writer) stripConnectionHeaders() {
+	w.headerOnce.Do(func() {
+		h := w.Header()
+		for _, key := range connectionHeaders {
+			h.Del(key)
+		}
+	})
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *http2Writer) WriteHeader(statusCode int) {
+	w.stripConnectionHeaders()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write implements http.ResponseWriter.
+func (w *http2Writer) Write(p []byte) (int, error) {
+	w.stripConnectionHeaders()
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher.
+func (w *http2Writer) Flush() {
+	w.stripConnectionHeaders()
+	w.flusher.Flush()
+}
+
+// Unwrap returns the wrapped ResponseWriter for http.ResponseController.
+func (w *http2Writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
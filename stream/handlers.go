@@ -3,6 +3,7 @@
 package stream
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -36,6 +37,13 @@ type StreamConfig struct {
 	// Options for SSE or NDJSON
 	SSEOptions    *SSEOptions
 	NDJSONOptions *NDJSONOptions
+	// MetricsCallback, if set, receives the stream's metrics when a
+	// normalized handler finishes streaming
+	MetricsCallback func(Metrics)
+	// Context is the request's context. The normalized handlers start their
+	// metrics span from it so the span joins the request's trace; nil uses
+	// context.Background(). The built-in handlers set it to r.Context().
+	Context context.Context
 }
 
 // SSENormalized streams events in normalized gai.events.v1 format via SSE.
@@ -49,11 +57,15 @@ func SSENormalized(w http.ResponseWriter, stream core.TextStream, config StreamC
 	normalizedStream := NewNormalizedStream(stream, normalizer)
 	defer normalizedStream.Close()
 
+	// Record stream metrics on completion
+	span := startMetricsSpan(config)
+	defer span.End()
+
 	// Set SSE headers
 	setSSEHeaders(w)
 
 	// Get flusher
-	flusher, ok := w.(http.Flusher)
+	flusher, ok := findFlusher(w)
 	if !ok {
 		return fmt.Errorf("streaming not supported: ResponseWriter does not support Flusher")
 	}
@@ -65,6 +77,7 @@ func SSENormalized(w http.ResponseWriter, stream core.TextStream, config StreamC
 			return err
 		}
 	}
+	reportMetrics(span, config, normalizedStream.Metrics())
 
 	// Send completion
 	fmt.Fprint(w, "event: done\n")
@@ -80,7 +93,7 @@ func SSEPassthroughOpenAI(w http.ResponseWriter, stream core.TextStream, config
 	setSSEHeaders(w)
 
 	// Get flusher
-	flusher, ok := w.(http.Flusher)
+	flusher, ok := findFlusher(w)
 	if !ok {
 		return fmt.Errorf("streaming not supported: ResponseWriter does not support Flusher")
 	}
@@ -110,11 +123,15 @@ func NDJSONNormalized(w http.ResponseWriter, stream core.TextStream, config Stre
 	normalizedStream := NewNormalizedStream(stream, normalizer)
 	defer normalizedStream.Close()
 
+	// Record stream metrics on completion
+	span := startMetricsSpan(config)
+	defer span.End()
+
 	// Set NDJSON headers
 	setNDJSONHeaders(w)
 
 	// Get flusher
-	flusher, ok := w.(http.Flusher)
+	flusher, ok := findFlusher(w)
 	if !ok {
 		return fmt.Errorf("streaming not supported: ResponseWriter does not support Flusher")
 	}
@@ -130,6 +147,7 @@ func NDJSONNormalized(w http.ResponseWriter, stream core.TextStream, config Stre
 		}
 		flusher.Flush()
 	}
+	reportMetrics(span, config, normalizedStream.Metrics())
 
 	// Send completion
 	completion := map[string]any{
@@ -148,7 +166,7 @@ func NDJSONPassthroughOpenAI(w http.ResponseWriter, stream core.TextStream, conf
 	setNDJSONHeaders(w)
 
 	// Get flusher
-	flusher, ok := w.(http.Flusher)
+	flusher, ok := findFlusher(w)
 	if !ok {
 		return fmt.Errorf("streaming not supported: ResponseWriter does not support Flusher")
 	}
@@ -178,6 +196,8 @@ func NDJSONPassthroughOpenAI(w http.ResponseWriter, stream core.TextStream, conf
 // UniversalHandler creates an HTTP handler that supports both normalized and passthrough modes.
 func UniversalHandler(provider core.Provider, prepareRequest func(*http.Request) (core.Request, StreamConfig, error)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		w = streamingWriter(w, r)
+
 		// Prepare request and config
 		req, config, err := prepareRequest(r)
 		if err != nil {
@@ -203,6 +223,7 @@ func UniversalHandler(provider core.Provider, prepareRequest func(*http.Request)
 		defer stream.Close()
 
 		// Update config with request metadata
+		config.Context = r.Context()
 		config.RequestID = req.RequestID
 		if config.Model == "" && req.Model != "" {
 			config.Model = req.Model
@@ -238,9 +259,8 @@ func UniversalHandler(provider core.Provider, prepareRequest func(*http.Request)
 func setSSEHeaders(w http.ResponseWriter) {
 	h := w.Header()
 	h.Set("Content-Type", "text/event-stream")
-	h.Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	setNoBufferingHeaders(h)
 	h.Set("Connection", "keep-alive")
-	h.Set("X-Accel-Buffering", "no")
 	h.Set("Access-Control-Allow-Origin", "*")
 	h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
 	h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Idempotency-Key")
@@ -249,9 +269,8 @@ func setSSEHeaders(w http.ResponseWriter) {
 func setNDJSONHeaders(w http.ResponseWriter) {
 	h := w.Header()
 	h.Set("Content-Type", "application/x-ndjson")
-	h.Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	setNoBufferingHeaders(h)
 	h.Set("Connection", "keep-alive")
-	h.Set("X-Accel-Buffering", "no")
 	h.Set("Transfer-Encoding", "chunked")
 	h.Set("Access-Control-Allow-Origin", "*")
 	h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
@@ -399,12 +418,12 @@ func OpenAICompatHandler(provider core.Provider) http.HandlerFunc {
 
 		// Build core request
 		req := core.Request{
-			IdempotencyKey: idempotencyKey,
-			Model:          body.Model,
-			Messages:       body.Messages,
-			Temperature:    body.Temperature,
-			MaxTokens:      body.MaxTokens,
-			Stream:         body.Stream,
+			IdempotencyKey:  idempotencyKey,
+			Model:           body.Model,
+			Messages:        body.Messages,
+			Temperature:     body.Temperature,
+			MaxOutputTokens: body.MaxTokens,
+			Stream:          body.Stream,
 		}
 
 		// Configure for passthrough mode
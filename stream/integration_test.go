@@ -746,4 +746,86 @@ func BenchmarkIntegrationNDJSON(b *testing.B) {
 		io.Copy(io.Discard, resp.Body)
 		resp.Body.Close()
 	}
-}
\ No newline at end of file
+}
+// TestIntegrationHTTP2Streaming tests that events reach an HTTP/2 client
+// as they are produced rather than when the stream ends.
+func TestIntegrationHTTP2Streaming(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		handler func(core.Provider, func(*http.Request) (core.Request, error), ...HandlerOption) http.HandlerFunc
+	}{
+		{"sse", SSEHandler},
+		{"ndjson", NDJSONHandler},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			provider := &mockProvider{
+				streamFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+					stream := newMockTextStream()
+					go func() {
+						stream.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "first"})
+						// Hold the stream open until the client has seen the first event
+						select {
+						case <-release:
+						case <-time.After(5 * time.Second):
+						}
+						stream.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "second"})
+						stream.Close()
+					}()
+					return stream, nil
+				},
+			}
+
+			server := httptest.NewUnstartedServer(tt.handler(provider, func(r *http.Request) (core.Request, error) {
+				return core.Request{}, nil
+			}))
+			server.EnableHTTP2 = true
+			server.StartTLS()
+			defer server.Close()
+
+			resp, err := server.Client().Get(server.URL)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.ProtoMajor != 2 {
+				t.Fatalf("expected HTTP/2, got %s", resp.Proto)
+			}
+			if got := resp.Header.Get("Cache-Control"); got != "no-cache, no-transform" {
+				t.Errorf("Cache-Control = %q", got)
+			}
+			if got := resp.Header.Get("X-Accel-Buffering"); got != "no" {
+				t.Errorf("X-Accel-Buffering = %q", got)
+			}
+			if got := resp.Header.Get("Connection"); got != "" {
+				t.Errorf("unexpected Connection header over HTTP/2: %q", got)
+			}
+
+			// The first event must arrive while the provider is still streaming
+			start := time.Now()
+			reader := bufio.NewReader(resp.Body)
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					t.Fatalf("stream ended before the first event: %v", err)
+				}
+				if strings.Contains(line, "first") {
+					break
+				}
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("first event was held back for %v", elapsed)
+			}
+			close(release)
+
+			rest, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("Failed to read stream: %v", err)
+			}
+			if !strings.Contains(string(rest), "second") {
+				t.Errorf("missing second event in %q", rest)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Package stream provides streaming utilities for AI responses.
+// This file implements streaming performance metrics.
+package stream
+
+import (
+	"context"
+	"time"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metrics describes the performance of a completed stream.
+type Metrics struct {
+	// StartTime is when the stream was created
+	StartTime time.Time
+	// FirstTokenTime is when the first text delta arrived (zero if none did)
+	FirstTokenTime time.Time
+	// EndTime is when the source stream ended (zero while streaming)
+	EndTime time.Time
+	// TotalTokens is the output token count reported by the provider, or an
+	// estimate of about four characters per token when none was reported
+	TotalTokens int
+	// TotalBytes is the size of the streamed text in bytes
+	TotalBytes int
+	// TotalEvents is the number of events received from the source
+	TotalEvents int
+}
+
+// TimeToFirstToken returns the delay before the first text delta, or zero if
+// no text was streamed.
+func (m Metrics) TimeToFirstToken() time.Duration {
+	if m.FirstTokenTime.IsZero() {
+		return 0
+	}
+	return m.FirstTokenTime.Sub(m.StartTime)
+}
+
+// Duration returns the total stream duration, or zero while streaming.
+func (m Metrics) Duration() time.Duration {
+	if m.EndTime.IsZero() {
+		return 0
+	}
+	return m.EndTime.Sub(m.StartTime)
+}
+
+// TokensPerSecond returns the output rate over the whole stream.
+func (m Metrics) TokensPerSecond() float64 {
+	d := m.Duration().Seconds()
+	if d <= 0 {
+		return 0
+	}
+	return float64(m.TotalTokens) / d
+}
+
+// metricsRecorder accumulates Metrics from source events.
+type metricsRecorder struct {
+	metrics      Metrics
+	stepTokens   int // output tokens summed over EventFinishStep
+	finishTokens int // output tokens reported by EventFinish
+}
+
+// newMetricsRecorder starts recording at the current time.
+func newMetricsRecorder() *metricsRecorder {
+	return &metricsRecorder{metrics: Metrics{StartTime: time.Now()}}
+}
+
+// record updates the metrics with a source event.
+func (r *metricsRecorder) record(event core.Event) {
+	r.metrics.TotalEvents++
+	switch event.Type {
+	case core.EventTextDelta:
+		if r.metrics.FirstTokenTime.IsZero() && event.TextDelta != "" {
+			r.metrics.FirstTokenTime = time.Now()
+		}
+		r.metrics.TotalBytes += len(event.TextDelta)
+	case core.EventFinishStep:
+		if event.Usage != nil {
+			r.stepTokens += event.Usage.OutputTokens
+		}
+	case core.EventFinish:
+		if event.Usage != nil {
+			r.finishTokens = event.Usage.OutputTokens
+		}
+	}
+}
+
+// finish marks the end of the stream and resolves the token count. Usage on
+// the final event wins over per-step usage, matching Collect.
+func (r *metricsRecorder) finish() {
+	r.metrics.EndTime = time.Now()
+	r.metrics.TotalTokens = r.finishTokens
+	if r.metrics.TotalTokens == 0 {
+		r.metrics.TotalTokens = r.stepTokens
+	}
+	if r.metrics.TotalTokens == 0 {
+		r.metrics.TotalTokens = (r.metrics.TotalBytes + 3) / 4
+	}
+}
+
+// startMetricsSpan starts the span that normalized handlers record stream
+// metrics on. It is a no-op span when tracing is disabled.
+func startMetricsSpan(config StreamConfig) trace.Span {
+	ctx := config.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, span := obs.StartStreamingSpan(ctx, obs.StreamingSpanOptions{
+		Provider:  config.Provider,
+		Model:     config.Model,
+		EventType: "normalized",
+	})
+	return span
+}
+
+// reportMetrics records m on span and passes it to the configured callback.
+func reportMetrics(span trace.Span, config StreamConfig, m Metrics) {
+	obs.RecordStreamingMetrics(span, m.TotalEvents, m.TotalBytes, m.Duration())
+	if config.MetricsCallback != nil {
+		config.MetricsCallback(m)
+	}
+}
@@ -0,0 +1,87 @@
+package stream
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestNormalizedStreamMetrics(t *testing.T) {
+	mock := newMockTextStream()
+	mock.sendEvent(core.Event{Type: core.EventStart})
+	mock.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "Hello"})
+	mock.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: " world"})
+	mock.sendEvent(core.Event{Type: core.EventFinish, Usage: &core.Usage{OutputTokens: 2}})
+	mock.Close()
+
+	var got *Metrics
+	config := StreamConfig{
+		RequestID: "req-1",
+		MetricsCallback: func(m Metrics) {
+			got = &m
+		},
+	}
+	if err := NDJSONNormalized(httptest.NewRecorder(), mock, config); err != nil {
+		t.Fatalf("NDJSONNormalized failed: %v", err)
+	}
+
+	if got == nil {
+		t.Fatal("MetricsCallback was not called")
+	}
+	if got.TotalEvents != 4 || got.TotalBytes != 11 || got.TotalTokens != 2 {
+		t.Errorf("unexpected counts: %+v", got)
+	}
+	if got.FirstTokenTime.Before(got.StartTime) || got.EndTime.Before(got.FirstTokenTime) {
+		t.Errorf("unexpected timestamps: %+v", got)
+	}
+	if got.TimeToFirstToken() < 0 || got.Duration() < got.TimeToFirstToken() {
+		t.Errorf("unexpected durations: ttft %v, total %v", got.TimeToFirstToken(), got.Duration())
+	}
+}
+
+func TestNormalizedStreamMetricsEstimatesTokens(t *testing.T) {
+	mock := newMockTextStream()
+	mock.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "12345678"})
+	mock.Close()
+
+	ns := NewNormalizedStream(mock, NewNormalizer("req-1", ""))
+	for range ns.Events() {
+	}
+
+	m := ns.Metrics()
+	if m.TotalTokens != 2 {
+		t.Errorf("expected estimated 2 tokens, got %d", m.TotalTokens)
+	}
+	if m.EndTime.IsZero() {
+		t.Error("expected EndTime after the stream drained")
+	}
+}
+
+func TestNormalizedStreamMetricsSpanParent(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	obs.SetGlobalTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer obs.SetGlobalTracerProvider(tracenoop.NewTracerProvider())
+
+	ctx, parent := obs.StartStreamingSpan(context.Background(), obs.StreamingSpanOptions{EventType: "request"})
+	mock := newMockTextStream()
+	mock.sendEvent(core.Event{Type: core.EventFinish})
+	mock.Close()
+	if err := NDJSONNormalized(httptest.NewRecorder(), mock, StreamConfig{RequestID: "req-1", Context: ctx}); err != nil {
+		t.Fatalf("NDJSONNormalized failed: %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected the metrics span to be a child of the request's span")
+	}
+}
@@ -0,0 +1,164 @@
+// Package stream provides streaming utilities for AI responses.
+// This file contains tests for msgpack event encoding.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+// TestMsgpackRoundTrip verifies events survive encoding and decoding.
+func TestMsgpackRoundTrip(t *testing.T) {
+	events := []NormalizedEvent{
+		{Schema: SchemaVersion, Type: EventTypeStart, Timestamp: 1700000000000, RequestID: "req_1", Provider: "openai", Model: "gpt-4o"},
+		{Type: EventTypeTextDelta, Timestamp: 1700000000001, Sequence: 2, Text: strings.Repeat("héllo ", 20)},
+		{Type: EventTypeToolCall, Sequence: 300, CallID: "call_1", ToolCall: &ToolCallData{Name: "search", Input: json.RawMessage(`{"limit":-5,"q":"go","score":0.5}`)}},
+		{Type: EventTypeAudioDelta, Sequence: 70000, Audio: &AudioData{Chunk: []byte{0, 1, 2, 255}, Format: "pcm"}},
+		{Type: EventTypeFinish, Usage: &UsageData{InputTokens: 10, OutputTokens: 5000000000, TotalTokens: 5000000010}, FinishReason: "stop"},
+		{Type: EventTypeError, Error: &ErrorData{Code: "rate_limited", Message: "slow down", Temporary: true, RetryAfter: 1000}},
+	}
+
+	for _, event := range events {
+		t.Run(string(event.Type), func(t *testing.T) {
+			data, err := event.MarshalMsgpack()
+			if err != nil {
+				t.Fatalf("MarshalMsgpack failed: %v", err)
+			}
+
+			decoded, err := ParseMsgpackEvent(data)
+			if err != nil {
+				t.Fatalf("ParseMsgpackEvent failed: %v", err)
+			}
+
+			want, _ := json.Marshal(event)
+			got, _ := json.Marshal(decoded)
+			if string(got) != string(want) {
+				t.Errorf("Round trip mismatch:\n got %s\nwant %s", got, want)
+			}
+
+			if len(data) >= len(want) {
+				t.Errorf("Expected msgpack (%d bytes) to be smaller than JSON (%d bytes)", len(data), len(want))
+			}
+		})
+	}
+}
+
+// TestMsgpackEncoding verifies the encoding matches the MessagePack spec.
+func TestMsgpackEncoding(t *testing.T) {
+	data, err := NormalizedEvent{Type: EventTypeTextDelta, Timestamp: 1, Schema: "s"}.MarshalMsgpack()
+	if err != nil {
+		t.Fatalf("MarshalMsgpack failed: %v", err)
+	}
+
+	// fixmap(3) with keys in field order: schema, type, ts
+	want := []byte{0x83,
+		0xa6, 's', 'c', 'h', 'e', 'm', 'a', 0xa1, 's',
+		0xa4, 't', 'y', 'p', 'e', 0xaa, 't', 'e', 'x', 't', '.', 'd', 'e', 'l', 't', 'a',
+		0xa2, 't', 's', 0x01,
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Unexpected encoding:\n got % x\nwant % x", data, want)
+	}
+}
+
+// TestParseMsgpackEventErrors verifies malformed input is rejected.
+func TestParseMsgpackEventErrors(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":      {},
+		"truncated":  {0x82, 0xa4, 't', 'y'},
+		"trailing":   {0x80, 0x80},
+		"bad key":    {0x81, 0x01, 0x01},
+		"ext type":   {0xd4, 0x01, 0x00},
+		"huge array": {0xdd, 0xff, 0xff, 0xff, 0xff},
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseMsgpackEvent(data); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+
+	// A deeply nested array is not an event
+	deep := []byte(strings.Repeat("\x91", 1000) + "\xc0")
+	if _, err := ParseMsgpackEvent(deep); err == nil {
+		t.Error("Expected an error for a nested array")
+	}
+}
+
+// TestAutoFormatHandler verifies content negotiation between NDJSON and msgpack.
+func TestAutoFormatHandler(t *testing.T) {
+	provider := &mockProvider{
+		streamFunc: func(ctx context.Context, req core.Request) (core.TextStream, error) {
+			stream := newMockTextStream()
+			go func() {
+				stream.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "Hello", Timestamp: time.Now()})
+				stream.sendEvent(core.Event{Type: core.EventFinish, Timestamp: time.Now()})
+				time.Sleep(10 * time.Millisecond)
+				stream.Close()
+			}()
+			return stream, nil
+		},
+	}
+
+	handler := AutoFormatHandler(provider, func(r *http.Request) (core.Request, error) {
+		return core.Request{Model: "test-model"}, nil
+	})
+
+	t.Run("msgpack", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/stream", nil)
+		req.Header.Set("Accept", MsgpackContentType)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		if ct := rec.Header().Get("Content-Type"); ct != MsgpackContentType {
+			t.Errorf("Expected Content-Type %s, got %s", MsgpackContentType, ct)
+		}
+
+		reader := NewMsgpackReader(rec.Body)
+		var types []NormalizedEventType
+		var text string
+		for {
+			event, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Read failed: %v", err)
+			}
+			types = append(types, event.Type)
+			text += event.Text
+		}
+
+		if text != "Hello" {
+			t.Errorf("Expected text Hello, got %q", text)
+		}
+		if len(types) != 2 || types[1] != EventTypeFinish {
+			t.Errorf("Unexpected event types: %v", types)
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/stream", nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("Expected Content-Type application/x-ndjson, got %s", ct)
+		}
+		if !strings.Contains(rec.Body.String(), `"text":"Hello"`) {
+			t.Errorf("Expected NDJSON text event, got %s", rec.Body.String())
+		}
+	})
+}
@@ -25,6 +25,9 @@ type NDJSONOptions struct {
 	CompactJSON bool
 	// IncludeTimestamp adds timestamps to each line
 	IncludeTimestamp bool
+	// EventBuffer, if set, decouples reading the stream from writing the
+	// response so a slow client cannot hold unbounded events (see EventBuffer)
+	EventBuffer *EventBuffer
 }
 
 // DefaultNDJSONOptions returns sensible defaults for NDJSON streaming.
@@ -49,6 +52,11 @@ func NDJSON(w http.ResponseWriter, stream core.TextStream, opts ...NDJSONOptions
 		options: options,
 	}
 	
+	if options.EventBuffer != nil {
+		buffered := newBufferedStream(stream, options.EventBuffer)
+		defer buffered.cancel()
+		return writer.Write(buffered)
+	}
 	return writer.Write(stream)
 }
 
@@ -67,7 +75,7 @@ func (n *ndjsonWriter) Write(stream core.TextStream) error {
 	n.setHeaders()
 	
 	// Get flusher for real-time streaming
-	flusher, ok := n.w.(http.Flusher)
+	flusher, ok := findFlusher(n.w)
 	if !ok {
 		return fmt.Errorf("streaming not supported: ResponseWriter does not support Flusher")
 	}
@@ -127,15 +135,16 @@ func (n *ndjsonWriter) Write(stream core.TextStream) error {
 func (n *ndjsonWriter) setHeaders() {
 	h := n.w.Header()
 	h.Set("Content-Type", "application/x-ndjson")
-	h.Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	setNoBufferingHeaders(h)
 	h.Set("Connection", "keep-alive")
-	h.Set("X-Accel-Buffering", "no") // Disable Nginx buffering
 	h.Set("Transfer-Encoding", "chunked")
 	
-	// CORS headers for browser compatibility
-	h.Set("Access-Control-Allow-Origin", "*")
-	h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	// CORS headers for browser compatibility, unless the handler set its own
+	if h.Get("Access-Control-Allow-Origin") == "" {
+		h.Set("Access-Control-Allow-Origin", "*")
+		h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	}
 }
 
 // periodicFlush flushes the buffer at regular intervals.
@@ -263,8 +272,12 @@ func (n *ndjsonWriter) eventToNDJSON(event core.Event) map[string]any {
 }
 
 // NDJSONHandler creates an HTTP handler that streams AI responses as NDJSON.
-func NDJSONHandler(provider core.Provider, prepareRequest func(*http.Request) (core.Request, error)) http.HandlerFunc {
+// Use WithEventBuffer to bound the events held for slow clients.
+func NDJSONHandler(provider core.Provider, prepareRequest func(*http.Request) (core.Request, error), opts ...HandlerOption) http.HandlerFunc {
+	config := newHandlerConfig(opts)
 	return func(w http.ResponseWriter, r *http.Request) {
+		w = streamingWriter(w, r)
+
 		// Prepare the AI request
 		req, err := prepareRequest(r)
 		if err != nil {
@@ -284,7 +297,9 @@ func NDJSONHandler(provider core.Provider, prepareRequest func(*http.Request) (c
 		defer stream.Close()
 		
 		// Stream as NDJSON
-		if err := NDJSON(w, stream); err != nil {
+		options := DefaultNDJSONOptions()
+		options.EventBuffer = config.eventBuffer()
+		if err := NDJSON(w, stream, options); err != nil {
 			// Log error but don't write to response (headers already sent)
 			// In production, this should use proper logging
 			_ = err
@@ -338,6 +353,7 @@ func StreamToChannel(ctx context.Context, r io.Reader) (<-chan core.Event, error
 					events <- core.Event{
 						Type:      core.EventError,
 						Err:       err,
+						ErrorCode: core.ErrorCodeOf(err),
 						Timestamp: time.Now(),
 					}
 				}
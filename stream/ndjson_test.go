@@ -48,7 +48,7 @@ func TestNDJSONHeaders(t *testing.T) {
 	
 	expectedHeaders := map[string]string{
 		"Content-Type":              "application/x-ndjson",
-		"Cache-Control":             "no-cache, no-store, must-revalidate",
+		"Cache-Control":             "no-cache, no-transform",
 		"Connection":                "keep-alive",
 		"X-Accel-Buffering":         "no",
 		"Transfer-Encoding":         "chunked",
@@ -3,8 +3,14 @@
 package stream
 
 import (
+	"bytes"
+	_ "embed"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -14,6 +20,40 @@ import (
 // SchemaVersion defines the current wire format version.
 const SchemaVersion = "gai.events.v1"
 
+// eventSchema is the JSON Schema of a NormalizedEvent in the SchemaVersion
+// wire format. It is unexported so callers cannot change what
+// ValidateSchema enforces; EventSchema returns a copy.
+//
+// It is checked with core.ValidateJSON rather than a full JSON Schema
+// validator such as santhosh-tekuri/jsonschema, which is not a dependency
+// of this module. ValidateJSON supports the type, required, properties,
+// additionalProperties, items, enum, const, anyOf/oneOf/allOf, length,
+// range, pattern and format keywords, so the schema must stick to those;
+// keywords such as $ref would be silently ignored.
+//
+//go:embed event_schema.json
+var eventSchema string
+
+// EventSchema returns the JSON Schema of a NormalizedEvent in the
+// SchemaVersion wire format, which ValidateSchema validates events against.
+// Publish it for clients that consume the normalized stream.
+func EventSchema() string {
+	return eventSchema
+}
+
+// StrictMode makes every NormalizedStream validate its events with
+// ValidateSchema, replacing a malformed event with an error event instead of
+// forwarding it. It is meant for tests and CI, where it catches provider bugs
+// and schema drift; it is read when a stream is created, so set it before
+// starting any streams.
+var StrictMode bool
+
+// StrictParsing makes parsing a NormalizedEvent, with ParseNormalizedEvent or
+// json.Unmarshal, fail on fields it does not know instead of capturing them
+// in NormalizedEvent.Extra. Leave it unset in clients so they keep working
+// when servers on a newer schema add fields.
+var StrictParsing bool
+
 // NormalizedEventType represents event types as strings for wire format.
 type NormalizedEventType string
 
@@ -80,6 +120,14 @@ type NormalizedEvent struct {
 	FinishReason string `json:"finish_reason,omitempty"`
 	// Error information
 	Error *ErrorData `json:"error,omitempty"`
+	// Provider-specific metadata (see core.Event.Metadata)
+	Meta map[string]any `json:"meta,omitempty"`
+
+	// Extra holds fields this version of the library does not know, such as
+	// fields added by a newer schema. They are captured when parsing and
+	// written back when the event is marshaled, so proxies pass them on.
+	// Parsing fails on them instead when StrictParsing is set.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // AudioData contains audio chunk information.
@@ -168,6 +216,9 @@ func (n *Normalizer) Normalize(event core.Event) NormalizedEvent {
 		TraceID:   n.traceID,
 		RequestID: n.requestID,
 	}
+	if len(event.Metadata) > 0 {
+		normalized.Meta = event.Metadata
+	}
 
 	// Map event type and populate specific fields
 	switch event.Type {
@@ -256,9 +307,13 @@ func (n *Normalizer) Normalize(event core.Event) NormalizedEvent {
 					normalized.Error.RetryAfter = int(aiErr.RetryAfter.Milliseconds())
 				}
 			} else {
-				// Generic error
+				// Generic error, categorized by the provider or best-effort
+				code := event.ErrorCode
+				if code == "" {
+					code = core.ErrorCodeOf(event.Err)
+				}
 				normalized.Error = &ErrorData{
-					Code:    "internal",
+					Code:    code,
 					Message: event.Err.Error(),
 				}
 			}
@@ -278,6 +333,9 @@ type NormalizedStream struct {
 	normalizer *Normalizer
 	events     chan NormalizedEvent
 	done       chan struct{}
+	mu         sync.Mutex
+	recorder   *metricsRecorder
+	strict     bool
 }
 
 // NewNormalizedStream creates a stream that emits normalized events.
@@ -287,6 +345,8 @@ func NewNormalizedStream(source core.TextStream, normalizer *Normalizer) *Normal
 		normalizer: normalizer,
 		events:     make(chan NormalizedEvent, 100),
 		done:       make(chan struct{}),
+		recorder:   newMetricsRecorder(),
+		strict:     StrictMode,
 	}
 
 	// Start normalization goroutine
@@ -299,9 +359,28 @@ func NewNormalizedStream(source core.TextStream, normalizer *Normalizer) *Normal
 func (ns *NormalizedStream) normalize() {
 	defer close(ns.events)
 	defer close(ns.done)
+	defer func() {
+		ns.mu.Lock()
+		ns.recorder.finish()
+		ns.mu.Unlock()
+	}()
 
 	for event := range ns.source.Events() {
+		ns.mu.Lock()
+		ns.recorder.record(event)
+		ns.mu.Unlock()
+
 		normalized := ns.normalizer.Normalize(event)
+		if ns.strict {
+			if err := ValidateSchema(normalized); err != nil {
+				normalized = ns.normalizer.Normalize(core.Event{
+					Type:      core.EventError,
+					Err:       core.NewError(core.ErrorInternal, fmt.Sprintf("malformed %s event: %v", normalized.Type, err)),
+					ErrorCode: string(core.ErrorInternal),
+					Timestamp: event.Timestamp,
+				})
+			}
+		}
 		select {
 		case ns.events <- normalized:
 		case <-ns.done:
@@ -315,6 +394,15 @@ func (ns *NormalizedStream) Events() <-chan NormalizedEvent {
 	return ns.events
 }
 
+// Metrics returns the stream's performance metrics. They are complete once
+// the events channel has been drained; before that EndTime is zero and the
+// counts reflect the events seen so far.
+func (ns *NormalizedStream) Metrics() Metrics {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	return ns.recorder.metrics
+}
+
 // Close stops the normalization process.
 func (ns *NormalizedStream) Close() error {
 	select {
@@ -390,12 +478,17 @@ func (e NormalizedEvent) CompactJSON() []byte {
 		}
 	}
 
+	if len(e.Meta) > 0 {
+		obj["meta"] = e.Meta
+	}
+
 	// Marshal to compact JSON
 	data, _ := json.Marshal(obj)
 	return data
 }
 
 // ParseNormalizedEvent parses a JSON byte slice into a NormalizedEvent.
+// Unknown fields are kept in Extra, or rejected when StrictParsing is set.
 func ParseNormalizedEvent(data []byte) (*NormalizedEvent, error) {
 	var event NormalizedEvent
 	if err := json.Unmarshal(data, &event); err != nil {
@@ -404,11 +497,107 @@ func ParseNormalizedEvent(data []byte) (*NormalizedEvent, error) {
 	return &event, nil
 }
 
-// ValidateSchema checks if an event has the expected schema version.
+// wireEvent is NormalizedEvent without its JSON methods.
+type wireEvent NormalizedEvent
+
+// knownEventFields holds the lowercased JSON names of NormalizedEvent's
+// fields; encoding/json matches names case-insensitively.
+var knownEventFields = func() map[string]bool {
+	known := make(map[string]bool)
+	t := reflect.TypeOf(NormalizedEvent{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			known[strings.ToLower(name)] = true
+		}
+	}
+	return known
+}()
+
+// UnmarshalJSON implements json.Unmarshaler. Known fields are decoded as
+// usual; the rest are stored in Extra, or rejected when StrictParsing is set.
+func (e *NormalizedEvent) UnmarshalJSON(data []byte) error {
+	var event wireEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for key := range fields {
+		if knownEventFields[strings.ToLower(key)] {
+			delete(fields, key)
+		}
+	}
+	if len(fields) > 0 {
+		if StrictParsing {
+			unknown := make([]string, 0, len(fields))
+			for key := range fields {
+				unknown = append(unknown, key)
+			}
+			sort.Strings(unknown)
+			return fmt.Errorf("unknown event fields: %s", strings.Join(unknown, ", "))
+		}
+		event.Extra = fields
+	}
+	*e = NormalizedEvent(event)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing the fields in Extra after
+// the known ones. Extra fields that collide with known fields are dropped.
+func (e NormalizedEvent) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(wireEvent(e))
+	if err != nil || len(e.Extra) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(e.Extra))
+	for key := range e.Extra {
+		if !knownEventFields[strings.ToLower(key)] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, key := range keys {
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(e.Extra[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// HasExtra reports whether the event carries fields unknown to this version
+// of the library, which usually means it came from a newer schema.
+func (e NormalizedEvent) HasExtra() bool {
+	return len(e.Extra) > 0
+}
+
+// ValidateSchema checks that an event conforms to EventSchema and, for start
+// events, that it has the expected schema version.
 func ValidateSchema(event NormalizedEvent) error {
 	if event.Type == EventTypeStart && event.Schema != SchemaVersion {
 		return fmt.Errorf("unsupported schema version: %s (expected %s)", event.Schema, SchemaVersion)
 	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := core.ValidateJSON(data, []byte(eventSchema)); err != nil {
+		return fmt.Errorf("invalid %s event: %w", event.Type, err)
+	}
 	return nil
 }
 
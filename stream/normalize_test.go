@@ -4,6 +4,7 @@ package stream
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -98,6 +99,25 @@ func TestNormalizedEventSchema(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "generic_error_event",
+			event: core.Event{
+				Type:      core.EventError,
+				Err:       errors.New("connection reset"),
+				ErrorCode: "network",
+				Timestamp: ts,
+			},
+			expected: NormalizedEvent{
+				Schema:    SchemaVersion,
+				Type:      EventTypeError,
+				Timestamp: ts.UnixMilli(),
+				Sequence:  1,
+				Error: &ErrorData{
+					Code:    "network",
+					Message: "connection reset",
+				},
+			},
+		},
 		{
 			name: "finish_event",
 			event: core.Event{
@@ -484,6 +504,87 @@ func TestParseNormalizedEvent(t *testing.T) {
 	}
 }
 
+// TestParseNormalizedEventUnknownFields verifies that fields from newer
+// schemas are preserved, or rejected with StrictParsing.
+func TestParseNormalizedEventUnknownFields(t *testing.T) {
+	data := []byte(`{"schema":"gai.events.v1","type":"text.delta","ts":1705314600000,"text":"Hi","lang":"en","span":{"start":0}}`)
+
+	event, err := ParseNormalizedEvent(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event.Text != "Hi" || !event.HasExtra() || len(event.Extra) != 2 {
+		t.Fatalf("Expected known fields parsed and 2 extra fields, got %+v", event)
+	}
+	if string(event.Extra["lang"]) != `"en"` {
+		t.Errorf("Unexpected extra field: %s", event.Extra["lang"])
+	}
+
+	// Extra fields are written back after the known ones
+	out, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"schema":"gai.events.v1","type":"text.delta","ts":1705314600000,"text":"Hi","lang":"en","span":{"start":0}}`
+	if string(out) != want {
+		t.Errorf("Round trip mismatch:\n got %s\nwant %s", out, want)
+	}
+
+	// Events without unknown fields have no Extra
+	event, err = ParseNormalizedEvent([]byte(`{"type":"text.delta","text":"Hi"}`))
+	if err != nil || event.HasExtra() || event.Extra != nil {
+		t.Errorf("Expected no extra fields, got %+v, %v", event, err)
+	}
+
+	StrictParsing = true
+	defer func() { StrictParsing = false }()
+	if _, err := ParseNormalizedEvent(data); err == nil || !strings.Contains(err.Error(), "lang, span") {
+		t.Errorf("Expected unknown field error with StrictParsing, got %v", err)
+	}
+}
+
+// TestEventSchemaKeywords verifies EventSchema only uses keywords that
+// core.ValidateJSON enforces.
+func TestEventSchemaKeywords(t *testing.T) {
+	supported := map[string]bool{
+		"$schema": true, "$id": true, "title": true, "description": true,
+		"type": true, "required": true, "properties": true, "additionalProperties": true,
+		"items": true, "minItems": true, "maxItems": true, "minLength": true, "maxLength": true,
+		"pattern": true, "format": true, "enum": true, "minimum": true, "maximum": true,
+		"exclusiveMinimum": true, "exclusiveMaximum": true,
+		"const": true, "anyOf": true, "oneOf": true, "allOf": true,
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(EventSchema()), &schema); err != nil {
+		t.Fatalf("EventSchema is not valid JSON: %v", err)
+	}
+
+	var check func(path string, node map[string]any)
+	check = func(path string, node map[string]any) {
+		for key, value := range node {
+			if !supported[key] {
+				t.Errorf("%s: keyword %q is not enforced by core.ValidateJSON", path, key)
+			}
+			switch key {
+			case "properties":
+				for name, prop := range value.(map[string]any) {
+					check(path+"."+name, prop.(map[string]any))
+				}
+			case "items":
+				if items, ok := value.(map[string]any); ok {
+					check(path+"[]", items)
+				}
+			case "anyOf", "oneOf", "allOf":
+				for i, sub := range value.([]any) {
+					check(fmt.Sprintf("%s.%s[%d]", path, key, i), sub.(map[string]any))
+				}
+			}
+		}
+	}
+	check("$", schema)
+}
+
 // TestSchemaValidation verifies schema version checking.
 func TestSchemaValidation(t *testing.T) {
 	tests := []struct {
@@ -513,7 +614,41 @@ func TestSchemaValidation(t *testing.T) {
 				Type:   EventTypeTextDelta,
 				Schema: "anything",
 			},
-			wantErr: false, // Only start events are validated
+			wantErr: false, // Only start events are version checked
+		},
+		{
+			name: "unknown_type",
+			event: NormalizedEvent{
+				Type:   "text",
+				Schema: SchemaVersion,
+			},
+			wantErr: true,
+		},
+		{
+			name: "tool_call_without_name",
+			event: NormalizedEvent{
+				Type:     EventTypeToolCall,
+				Schema:   SchemaVersion,
+				ToolCall: &ToolCallData{Input: json.RawMessage(`{}`)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "safety_score_out_of_range",
+			event: NormalizedEvent{
+				Type:   EventTypeSafety,
+				Schema: SchemaVersion,
+				Safety: &SafetyData{Category: "harassment", Action: "block", Score: 1.5},
+			},
+			wantErr: true,
+		},
+		{
+			name: "raw_passthrough",
+			event: NormalizedEvent{
+				Type:   "raw.42",
+				Schema: SchemaVersion,
+			},
+			wantErr: false,
 		},
 	}
 
@@ -527,6 +662,35 @@ func TestSchemaValidation(t *testing.T) {
 	}
 }
 
+// TestStrictMode verifies that malformed events are replaced with errors.
+func TestStrictMode(t *testing.T) {
+	StrictMode = true
+	defer func() { StrictMode = false }()
+
+	mock := newMockTextStream()
+	mock.sendEvent(core.Event{Type: core.EventStart, Timestamp: time.Now()})
+	mock.sendEvent(core.Event{Type: core.EventToolCall, ToolID: "call_1", ToolInput: json.RawMessage(`{}`), Timestamp: time.Now()})
+	mock.sendEvent(core.Event{Type: core.EventFinish, Timestamp: time.Now()})
+	mock.Close()
+
+	ns := NewNormalizedStream(mock, NewNormalizer("req_test", ""))
+	var events []NormalizedEvent
+	for event := range ns.Events() {
+		events = append(events, event)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	bad := events[1]
+	if bad.Type != EventTypeError || bad.Error == nil || !strings.Contains(bad.Error.Message, "malformed tool.call event") {
+		t.Errorf("expected error event for malformed tool call, got %+v", bad)
+	}
+	if events[0].Type != EventTypeStart || events[2].Type != EventTypeFinish {
+		t.Errorf("valid events should be forwarded, got %s and %s", events[0].Type, events[2].Type)
+	}
+}
+
 // TestRequestIDGeneration verifies request ID generation.
 func TestRequestIDGeneration(t *testing.T) {
 	gen := &DefaultRequestIDGenerator{}
@@ -702,4 +866,36 @@ func Example_compactJSON() {
 	compact := event.CompactJSON()
 	fmt.Printf("Compact JSON: %s\n", string(compact))
 	// Output: Compact JSON: {"seq":5,"text":"Example text","type":"text.delta"}
-}
\ No newline at end of file
+}
+func TestNormalizeMetadata(t *testing.T) {
+	normalizer := NewNormalizer("req_meta", "")
+
+	event := normalizer.Normalize(core.Event{
+		Type:      core.EventTextDelta,
+		TextDelta: "",
+		Metadata:  map[string]any{core.EventMetaRefusal: "I can't help with that"},
+		Timestamp: time.Now(),
+	})
+	if event.Meta[core.EventMetaRefusal] != "I can't help with that" {
+		t.Errorf("Expected refusal metadata, got %v", event.Meta)
+	}
+
+	var compact map[string]any
+	if err := json.Unmarshal(event.CompactJSON(), &compact); err != nil {
+		t.Fatalf("Failed to parse compact JSON: %v", err)
+	}
+	meta, ok := compact["meta"].(map[string]any)
+	if !ok || meta[core.EventMetaRefusal] != "I can't help with that" {
+		t.Errorf("Expected meta in compact JSON, got %v", compact)
+	}
+
+	// Events without metadata omit the field
+	plain := normalizer.Normalize(core.Event{Type: core.EventTextDelta, TextDelta: "hi", Timestamp: time.Now()})
+	data, err := json.Marshal(plain)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if strings.Contains(string(data), `"meta"`) {
+		t.Errorf("Expected no meta field, got %s", data)
+	}
+}
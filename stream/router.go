@@ -0,0 +1,151 @@
+// Package stream provides streaming utilities for AI responses.
+// This file implements an event router that fans one stream out to several
+// consumers, such as an HTTP response, a database logger and a webhook.
+package stream
+
+import (
+	"sync"
+
+	"github.com/recera/gai/core"
+)
+
+// routerBufferSize is the number of events buffered for each subscriber.
+const routerBufferSize = 64
+
+// EventRouter broadcasts the normalized events of one stream to every
+// subscriber. A single goroutine reads the source and sends each event to
+// all subscribers in turn without blocking: a subscriber whose buffer is
+// full misses the event, which is counted by DroppedEvents, so one slow
+// consumer cannot stall the others.
+type EventRouter struct {
+	source     core.TextStream
+	normalizer *Normalizer
+
+	mu          sync.Mutex
+	subscribers []chan NormalizedEvent
+	dropped     int
+	started     bool
+	finished    bool
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewEventRouter creates a router for source. Events are not read until
+// Start is called, so that all consumers can subscribe first.
+//
+// Example:
+//
+//	router := stream.NewEventRouter(s)
+//	client := router.Subscribe()
+//	logger := router.Subscribe()
+//	router.Start()
+//	go logEvents(logger)
+//	for event := range client { ... }
+func NewEventRouter(source core.TextStream) *EventRouter {
+	gen := &DefaultRequestIDGenerator{}
+	return &EventRouter{
+		source:     source,
+		normalizer: NewNormalizer(gen.Generate(), ""),
+		done:       make(chan struct{}),
+	}
+}
+
+// Subscribe adds a consumer and returns its event channel. Subscribers added
+// after Start receive only the events routed after they subscribed. The
+// channel is closed when the source stream ends or the router is closed.
+func (r *EventRouter) Subscribe() <-chan NormalizedEvent {
+	ch := make(chan NormalizedEvent, routerBufferSize)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.finished {
+		close(ch)
+		return ch
+	}
+	r.subscribers = append(r.subscribers, ch)
+	return ch
+}
+
+// Start begins routing events from the source. Calling it more than once has
+// no effect.
+func (r *EventRouter) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.started || r.finished {
+		return
+	}
+	r.started = true
+	go r.run()
+}
+
+// DroppedEvents returns the total number of events that were not delivered
+// because a subscriber's buffer was full.
+func (r *EventRouter) DroppedEvents() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dropped
+}
+
+// Close stops routing, closes the source stream and closes all subscriber
+// channels.
+func (r *EventRouter) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.done)
+		err = r.source.Close()
+
+		r.mu.Lock()
+		started := r.started
+		r.mu.Unlock()
+		if !started {
+			r.finish()
+		}
+	})
+	return err
+}
+
+// run broadcasts source events until the source ends or the router is closed.
+func (r *EventRouter) run() {
+	defer r.finish()
+
+	source := r.source.Events()
+	for {
+		select {
+		case event, ok := <-source:
+			if !ok {
+				return
+			}
+			r.broadcast(r.normalizer.Normalize(event))
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// broadcast sends event to every subscriber without blocking.
+func (r *EventRouter) broadcast(event NormalizedEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ch := range r.subscribers {
+		select {
+		case ch <- event:
+		default:
+			r.dropped++
+		}
+	}
+}
+
+// finish closes all subscriber channels.
+func (r *EventRouter) finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.finished {
+		return
+	}
+	r.finished = true
+	for _, ch := range r.subscribers {
+		close(ch)
+	}
+	r.subscribers = nil
+}
@@ -0,0 +1,88 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+func TestEventRouterBroadcast(t *testing.T) {
+	source := newMockTextStream()
+	source.sendEvent(core.Event{Type: core.EventStart})
+	source.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "Hello"})
+	source.sendEvent(core.Event{Type: core.EventFinish})
+	source.Close()
+
+	router := NewEventRouter(source)
+	defer router.Close()
+	subs := []<-chan NormalizedEvent{router.Subscribe(), router.Subscribe()}
+	router.Start()
+
+	for i, sub := range subs {
+		var types []NormalizedEventType
+		for event := range sub {
+			types = append(types, event.Type)
+		}
+		if len(types) != 3 || types[0] != EventTypeStart || types[1] != EventTypeTextDelta || types[2] != EventTypeFinish {
+			t.Errorf("subscriber %d received %v", i, types)
+		}
+	}
+	if n := router.DroppedEvents(); n != 0 {
+		t.Errorf("DroppedEvents() = %d, want 0", n)
+	}
+}
+
+func TestEventRouterDropsForSlowConsumer(t *testing.T) {
+	source := newMockTextStream()
+	for i := 0; i < routerBufferSize+5; i++ {
+		source.sendEvent(core.Event{Type: core.EventTextDelta, TextDelta: "x"})
+	}
+	source.Close()
+
+	router := NewEventRouter(source)
+	defer router.Close()
+	slow := router.Subscribe()
+	router.Start()
+
+	deadline := time.Now().Add(time.Second)
+	for router.DroppedEvents() < 5 {
+		if time.Now().After(deadline) {
+			t.Fatalf("DroppedEvents() = %d, want 5", router.DroppedEvents())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	count := 0
+	for range slow {
+		count++
+	}
+	if count != routerBufferSize {
+		t.Errorf("slow subscriber received %d events, want %d", count, routerBufferSize)
+	}
+	if n := router.DroppedEvents(); n != 5 {
+		t.Errorf("DroppedEvents() = %d, want 5", n)
+	}
+}
+
+func TestEventRouterClose(t *testing.T) {
+	source := newMockTextStream()
+	router := NewEventRouter(source)
+	sub := router.Subscribe()
+
+	if err := router.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	select {
+	case _, ok := <-sub:
+		if ok {
+			t.Error("expected subscriber channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber channel was not closed")
+	}
+	if _, ok := <-router.Subscribe(); ok {
+		t.Error("expected Subscribe after Close to return a closed channel")
+	}
+}
@@ -26,6 +26,9 @@ type SSEOptions struct {
 	BufferSize int
 	// IncludeID adds event IDs for client-side replay
 	IncludeID bool
+	// EventBuffer, if set, decouples reading the stream from writing the
+	// response so a slow client cannot hold unbounded events (see EventBuffer)
+	EventBuffer *EventBuffer
 }
 
 // DefaultSSEOptions returns sensible defaults for SSE streaming.
@@ -52,6 +55,11 @@ func SSE(w http.ResponseWriter, stream core.TextStream, opts ...SSEOptions) erro
 		eventID: 0,
 	}
 	
+	if options.EventBuffer != nil {
+		buffered := newBufferedStream(stream, options.EventBuffer)
+		defer buffered.cancel()
+		return writer.Write(buffered)
+	}
 	return writer.Write(stream)
 }
 
@@ -69,7 +77,7 @@ func (s *sseWriter) Write(stream core.TextStream) error {
 	s.setHeaders()
 	
 	// Get flusher for real-time streaming
-	flusher, ok := s.w.(http.Flusher)
+	flusher, ok := findFlusher(s.w)
 	if !ok {
 		return fmt.Errorf("streaming not supported: ResponseWriter does not support Flusher")
 	}
@@ -122,14 +130,15 @@ func (s *sseWriter) Write(stream core.TextStream) error {
 func (s *sseWriter) setHeaders() {
 	h := s.w.Header()
 	h.Set("Content-Type", "text/event-stream")
-	h.Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	setNoBufferingHeaders(h)
 	h.Set("Connection", "keep-alive")
-	h.Set("X-Accel-Buffering", "no") // Disable Nginx buffering
 	
-	// CORS headers for browser compatibility
-	h.Set("Access-Control-Allow-Origin", "*")
-	h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	// CORS headers for browser compatibility, unless the handler set its own
+	if h.Get("Access-Control-Allow-Origin") == "" {
+		h.Set("Access-Control-Allow-Origin", "*")
+		h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	}
 }
 
 // sendHeartbeats sends periodic keep-alive messages.
@@ -295,8 +304,12 @@ func (s *sseWriter) eventToSSE(event core.Event) sseEventData {
 }
 
 // SSEHandler creates an HTTP handler that streams AI responses as SSE.
-func SSEHandler(provider core.Provider, prepareRequest func(*http.Request) (core.Request, error)) http.HandlerFunc {
+// Use WithEventBuffer to bound the events held for slow clients.
+func SSEHandler(provider core.Provider, prepareRequest func(*http.Request) (core.Request, error), opts ...HandlerOption) http.HandlerFunc {
+	config := newHandlerConfig(opts)
 	return func(w http.ResponseWriter, r *http.Request) {
+		w = streamingWriter(w, r)
+
 		// Prepare the AI request
 		req, err := prepareRequest(r)
 		if err != nil {
@@ -316,7 +329,9 @@ func SSEHandler(provider core.Provider, prepareRequest func(*http.Request) (core
 		defer stream.Close()
 		
 		// Stream as SSE
-		if err := SSE(w, stream); err != nil {
+		options := DefaultSSEOptions()
+		options.EventBuffer = config.eventBuffer()
+		if err := SSE(w, stream, options); err != nil {
 			// Log error but don't write to response (headers already sent)
 			// In production, this should use proper logging
 			_ = err
@@ -84,7 +84,7 @@ func TestSSEHeaders(t *testing.T) {
 	
 	expectedHeaders := map[string]string{
 		"Content-Type":              "text/event-stream",
-		"Cache-Control":             "no-cache, no-store, must-revalidate",
+		"Cache-Control":             "no-cache, no-transform",
 		"Connection":                "keep-alive",
 		"X-Accel-Buffering":         "no",
 		"Access-Control-Allow-Origin": "*",
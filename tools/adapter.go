@@ -6,6 +6,9 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 	
 	"github.com/recera/gai/core"
 )
@@ -17,8 +20,55 @@ type CoreToolAdapter struct {
 }
 
 // NewCoreAdapter creates an adapter that wraps a tools.Handle for use with core.
+// Batch tools are wrapped so that they also implement core.BatchToolHandle, and
+// tools with a non-empty output schema so that they also implement
+// core.ToolHandleWithOutputSchema.
 func NewCoreAdapter(tool Handle) core.ToolHandle {
-	return &CoreToolAdapter{tool: tool}
+	adapter := &CoreToolAdapter{tool: tool}
+	batch, isBatch := tool.(BatchHandle)
+	hasOutput := tool != nil && len(tool.OutSchemaJSON()) > 0
+	switch {
+	case isBatch && hasOutput:
+		return &outputSchemaBatchCoreToolAdapter{&batchCoreToolAdapter{CoreToolAdapter: adapter, batch: batch}}
+	case isBatch:
+		return &batchCoreToolAdapter{CoreToolAdapter: adapter, batch: batch}
+	case hasOutput:
+		return &outputSchemaCoreToolAdapter{adapter}
+	}
+	return adapter
+}
+
+// batchCoreToolAdapter wraps a BatchHandle to implement core.BatchToolHandle.
+type batchCoreToolAdapter struct {
+	*CoreToolAdapter
+	batch BatchHandle
+}
+
+// outputSchemaCoreToolAdapter wraps a Handle with an output schema to
+// implement core.ToolHandleWithOutputSchema.
+type outputSchemaCoreToolAdapter struct {
+	*CoreToolAdapter
+}
+
+// OutSchemaJSON returns the output schema.
+func (a *outputSchemaCoreToolAdapter) OutSchemaJSON() json.RawMessage {
+	return a.tool.OutSchemaJSON()
+}
+
+// outputSchemaBatchCoreToolAdapter wraps a BatchHandle with an output schema
+// to implement both core.BatchToolHandle and core.ToolHandleWithOutputSchema.
+type outputSchemaBatchCoreToolAdapter struct {
+	*batchCoreToolAdapter
+}
+
+// OutSchemaJSON returns the output schema.
+func (a *outputSchemaBatchCoreToolAdapter) OutSchemaJSON() json.RawMessage {
+	return a.tool.OutSchemaJSON()
+}
+
+// ExecBatch executes the batch tool, converting the meta interface{} to our Meta type.
+func (a *batchCoreToolAdapter) ExecBatch(ctx context.Context, inputs []json.RawMessage, metaInterface interface{}) []core.BatchResult {
+	return a.batch.ExecBatch(ctx, inputs, metaFromInterface(metaInterface))
 }
 
 // Name returns the tool's name.
@@ -32,19 +82,21 @@ func (a *CoreToolAdapter) Description() string {
 }
 
 // InSchemaJSON returns the input schema.
-func (a *CoreToolAdapter) InSchemaJSON() []byte {
+func (a *CoreToolAdapter) InSchemaJSON() json.RawMessage {
 	return a.tool.InSchemaJSON()
 }
 
-// OutSchemaJSON returns the output schema.
-func (a *CoreToolAdapter) OutSchemaJSON() []byte {
-	return a.tool.OutSchemaJSON()
-}
-
 // Exec executes the tool, converting the meta interface{} to our Meta type.
 func (a *CoreToolAdapter) Exec(ctx context.Context, raw json.RawMessage, metaInterface interface{}) (any, error) {
-	// Convert the interface{} meta to our Meta struct
-	meta := Meta{}
+	// Execute the underlying tool
+	return a.tool.Exec(ctx, raw, metaFromInterface(metaInterface))
+}
+
+// metaFromInterface converts the meta map passed by core and the providers
+// to our Meta struct.
+func metaFromInterface(metaInterface interface{}) Meta {
+	// Providers execute tool calls sequentially unless they say otherwise
+	meta := Meta{ParallelCallCount: 1}
 	
 	// Try to extract fields from the meta interface
 	if m, ok := metaInterface.(map[string]interface{}); ok {
@@ -63,10 +115,14 @@ func (a *CoreToolAdapter) Exec(ctx context.Context, raw json.RawMessage, metaInt
 		if metadata, ok := m["metadata"].(map[string]any); ok {
 			meta.Metadata = metadata
 		}
+		if count, ok := m["parallel_call_count"].(int); ok && count > 0 {
+			meta.ParallelCallCount = count
+		}
+		if index, ok := m["parallel_call_index"].(int); ok {
+			meta.ParallelCallIndex = index
+		}
 	}
-	
-	// Execute the underlying tool
-	return a.tool.Exec(ctx, raw, meta)
+	return meta
 }
 
 // ToHandles converts a slice of core.ToolHandle to tools.Handle.
@@ -75,7 +131,14 @@ func ToHandles(coreTools []core.ToolHandle) []Handle {
 	handles := make([]Handle, 0, len(coreTools))
 	for _, ct := range coreTools {
 		// Check if it's already an adapter
-		if adapter, ok := ct.(*CoreToolAdapter); ok {
+		switch adapter := ct.(type) {
+		case *CoreToolAdapter:
+			handles = append(handles, adapter.tool)
+		case *batchCoreToolAdapter:
+			handles = append(handles, adapter.tool)
+		case *outputSchemaCoreToolAdapter:
+			handles = append(handles, adapter.tool)
+		case *outputSchemaBatchCoreToolAdapter:
 			handles = append(handles, adapter.tool)
 		}
 		// Otherwise, create a generic handle wrapper
@@ -92,4 +155,94 @@ func ToCoreHandles(tools []Handle) []core.ToolHandle {
 		coreTools[i] = NewCoreAdapter(tool)
 	}
 	return coreTools
-}
\ No newline at end of file
+}
+
+// ValidateHandle checks that a core.ToolHandle is well-formed before it is
+// registered or passed to a provider. It reports every violation found:
+// a nil handle (Exec cannot be called), an empty name, and input or output
+// schemas that are not valid JSON objects.
+func ValidateHandle(h core.ToolHandle) error {
+	if h == nil {
+		return fmt.Errorf("invalid tool handle: handle is nil, Exec cannot be called")
+	}
+	if v := reflect.ValueOf(h); v.Kind() == reflect.Ptr && v.IsNil() {
+		return fmt.Errorf("invalid tool handle: %T is a nil pointer, Exec cannot be called", h)
+	}
+	if adapter, ok := h.(*CoreToolAdapter); ok && adapter.tool == nil {
+		return fmt.Errorf("invalid tool handle: adapter wraps a nil tools.Handle, Exec cannot be called")
+	}
+	
+	var problems []string
+	
+	name, err := callSafely(h.Name)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("Name() %v", err))
+	} else if name == "" {
+		problems = append(problems, "name is empty")
+	}
+	
+	inSchema, err := callSafely(h.InSchemaJSON)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("InSchemaJSON() %v", err))
+	} else if msg := checkSchemaJSON(inSchema, true); msg != "" {
+		problems = append(problems, "input schema "+msg)
+	}
+	
+	if out, ok := h.(core.ToolHandleWithOutputSchema); ok {
+		outSchema, err := callSafely(out.OutSchemaJSON)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("OutSchemaJSON() %v", err))
+		} else if msg := checkSchemaJSON(outSchema, false); msg != "" {
+			problems = append(problems, "output schema "+msg)
+		}
+	}
+	
+	if len(problems) == 0 {
+		return nil
+	}
+	if name == "" {
+		return fmt.Errorf("invalid tool handle: %s", strings.Join(problems, "; "))
+	}
+	return fmt.Errorf("invalid tool handle %q: %s", name, strings.Join(problems, "; "))
+}
+
+// MustToolHandle returns h unchanged if it passes ValidateHandle and panics otherwise.
+// It is intended for package-level tool declarations so that registration bugs
+// surface at startup rather than during the first tool call:
+//
+//	var weatherTool = tools.MustToolHandle(tools.NewCoreAdapter(weather))
+func MustToolHandle(h core.ToolHandle) core.ToolHandle {
+	if err := ValidateHandle(h); err != nil {
+		panic("tools.MustToolHandle: " + err.Error())
+	}
+	return h
+}
+
+// callSafely invokes fn and converts a panic into an error.
+func callSafely[T any](fn func() T) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panicked: %v", r)
+		}
+	}()
+	return fn(), nil
+}
+
+// checkSchemaJSON returns a description of what is wrong with a schema,
+// or an empty string if the schema is acceptable.
+func checkSchemaJSON(schema []byte, required bool) string {
+	if len(schema) == 0 {
+		if required {
+			return "is empty"
+		}
+		return ""
+	}
+	if !json.Valid(schema) {
+		return "is not valid JSON"
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(schema, &obj); err != nil {
+		return "is not a JSON object"
+	}
+	return ""
+}
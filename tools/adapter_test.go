@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+// rawHandle is a hand-rolled core.ToolHandle used to exercise validation.
+type rawHandle struct {
+	name      string
+	inSchema  []byte
+	outSchema []byte
+}
+
+func (h *rawHandle) Name() string                   { return h.name }
+func (h *rawHandle) Description() string            { return "raw handle" }
+func (h *rawHandle) InSchemaJSON() json.RawMessage  { return h.inSchema }
+func (h *rawHandle) OutSchemaJSON() json.RawMessage { return h.outSchema }
+func (h *rawHandle) Exec(ctx context.Context, raw json.RawMessage, meta interface{}) (any, error) {
+	return nil, nil
+}
+
+func TestValidateHandle(t *testing.T) {
+	valid := New[SimpleInput, SimpleOutput](
+		"greet",
+		"Greets a user",
+		func(ctx context.Context, in SimpleInput, meta Meta) (SimpleOutput, error) {
+			return SimpleOutput{}, nil
+		},
+	)
+
+	var nilHandle *rawHandle
+
+	tests := []struct {
+		name     string
+		handle   core.ToolHandle
+		wantErrs []string
+	}{
+		{
+			name:   "valid adapter",
+			handle: NewCoreAdapter(valid),
+		},
+		{
+			name:   "valid raw handle",
+			handle: &rawHandle{name: "raw", inSchema: []byte(`{"type":"object"}`)},
+		},
+		{
+			name:     "nil pointer",
+			handle:   nilHandle,
+			wantErrs: []string{"nil pointer"},
+		},
+		{
+			name:     "adapter wrapping nil",
+			handle:   NewCoreAdapter(nil),
+			wantErrs: []string{"nil tools.Handle"},
+		},
+		{
+			name:     "empty name",
+			handle:   &rawHandle{inSchema: []byte(`{"type":"object"}`)},
+			wantErrs: []string{"name is empty"},
+		},
+		{
+			name:     "invalid input schema",
+			handle:   &rawHandle{name: "bad", inSchema: []byte(`{"type":`)},
+			wantErrs: []string{"input schema is not valid JSON"},
+		},
+		{
+			name:     "non-object input schema",
+			handle:   &rawHandle{name: "bad", inSchema: []byte(`["type"]`)},
+			wantErrs: []string{"input schema is not a JSON object"},
+		},
+		{
+			name:     "multiple violations",
+			handle:   &rawHandle{outSchema: []byte(`nope`)},
+			wantErrs: []string{"name is empty", "input schema is empty", "output schema is not valid JSON"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHandle(tt.handle)
+
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error to contain %q, got %q", want, err.Error())
+				}
+			}
+		})
+	}
+
+	if err := ValidateHandle(nil); err == nil {
+		t.Error("Expected error for nil handle")
+	}
+}
+
+func TestMustToolHandle(t *testing.T) {
+	h := &rawHandle{name: "raw", inSchema: []byte(`{"type":"object"}`)}
+	if got := MustToolHandle(h); got != h {
+		t.Error("Expected MustToolHandle to return the same handle")
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected panic for invalid handle")
+		}
+		if !strings.Contains(r.(string), "tools.MustToolHandle") {
+			t.Errorf("Unexpected panic message: %v", r)
+		}
+	}()
+	MustToolHandle(&rawHandle{inSchema: []byte(`{`)})
+}
+
+func TestAdapterParallelCallMeta(t *testing.T) {
+	var got Meta
+	tool := New[SimpleInput, SimpleOutput]("meta_tool", "Captures meta",
+		func(ctx context.Context, in SimpleInput, meta Meta) (SimpleOutput, error) {
+			got = meta
+			return SimpleOutput{}, nil
+		})
+	adapter := NewCoreAdapter(tool)
+	input := json.RawMessage(`{"name":"Test","age":30}`)
+
+	// Providers that execute tools sequentially pass no parallel fields
+	if _, err := adapter.Exec(context.Background(), input, map[string]interface{}{"call_id": "call_1"}); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if got.ParallelCallCount != 1 || got.ParallelCallIndex != 0 {
+		t.Errorf("Sequential call: got count %d index %d, want 1 and 0", got.ParallelCallCount, got.ParallelCallIndex)
+	}
+
+	meta := map[string]interface{}{"parallel_call_count": 3, "parallel_call_index": 2}
+	if _, err := adapter.Exec(context.Background(), input, meta); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if got.ParallelCallCount != 3 || got.ParallelCallIndex != 2 {
+		t.Errorf("Parallel call: got count %d index %d, want 3 and 2", got.ParallelCallCount, got.ParallelCallIndex)
+	}
+}
+
+// noOutputHandle is a Handle that does not describe its output.
+type noOutputHandle struct {
+	Handle
+}
+
+func (noOutputHandle) OutSchemaJSON() []byte { return nil }
+
+func TestAdapterOutputSchema(t *testing.T) {
+	tool := New[SimpleInput, SimpleOutput]("simple", "Simple tool",
+		func(ctx context.Context, in SimpleInput, meta Meta) (SimpleOutput, error) {
+			return SimpleOutput{}, nil
+		})
+	batch := NewBatch[SimpleInput, SimpleOutput]("lookup", "Batch tool",
+		func(ctx context.Context, in []SimpleInput, meta Meta) ([]SimpleOutput, error) {
+			return make([]SimpleOutput, len(in)), nil
+		})
+
+	tests := []struct {
+		name       string
+		handle     Handle
+		wantOutput bool
+		wantBatch  bool
+	}{
+		{name: "tool", handle: tool, wantOutput: true},
+		{name: "batch tool", handle: batch, wantOutput: true, wantBatch: true},
+		{name: "no output schema", handle: noOutputHandle{tool}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := NewCoreAdapter(tt.handle)
+			out, ok := adapter.(core.ToolHandleWithOutputSchema)
+			if ok != tt.wantOutput {
+				t.Fatalf("implements core.ToolHandleWithOutputSchema = %v, want %v", ok, tt.wantOutput)
+			}
+			if ok && string(out.OutSchemaJSON()) != string(tt.handle.OutSchemaJSON()) {
+				t.Errorf("OutSchemaJSON = %s, want %s", out.OutSchemaJSON(), tt.handle.OutSchemaJSON())
+			}
+			if _, ok := adapter.(core.BatchToolHandle); ok != tt.wantBatch {
+				t.Errorf("implements core.BatchToolHandle = %v, want %v", ok, tt.wantBatch)
+			}
+			if err := ValidateHandle(adapter); err != nil {
+				t.Errorf("ValidateHandle: %v", err)
+			}
+			if handles := ToHandles([]core.ToolHandle{adapter}); len(handles) != 1 {
+				t.Errorf("ToHandles returned %d handles, want 1", len(handles))
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Package tools provides typed tool definitions and execution for AI frameworks.
+// This file implements batch tools, which process every call the model makes
+// to them in a step with a single function call.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+)
+
+// BatchHandle is implemented by tools that can execute several calls at once.
+// The agentic loops detect it (through core.BatchToolHandle) and coalesce
+// calls to the tool within a step.
+type BatchHandle interface {
+	Handle
+	// ExecBatch executes the tool once for all raw inputs and returns one
+	// result per input, in the same order
+	ExecBatch(ctx context.Context, inputs []json.RawMessage, meta Meta) []core.BatchResult
+}
+
+// BatchTool is a typed tool whose function receives a slice of inputs and
+// returns the outputs in the same order. A single call is executed as a batch
+// of one, so a BatchTool can be used anywhere a Handle is accepted.
+type BatchTool[I any, O any] struct {
+	*Tool[I, O]
+	executeBatch func(context.Context, []I, Meta) ([]O, error)
+}
+
+// NewBatch creates a tool backed by a batch function, for APIs that support
+// batch operations such as embeddings or lookups by multiple IDs. When the
+// model calls the tool several times in one step, fn is called once with all
+// of the inputs and each output is matched back to its call. fn must return
+// exactly one output per input. The Meta passed to a batch has no CallID.
+//
+// Example:
+//
+//	lookup := tools.NewBatch("get_user", "Look up a user by ID",
+//		func(ctx context.Context, in []UserQuery, meta tools.Meta) ([]User, error) {
+//			return db.UsersByIDs(ctx, ids(in))
+//		})
+func NewBatch[I any, O any](
+	name string,
+	description string,
+	fn func(context.Context, []I, Meta) ([]O, error),
+	opts ...ToolOption[I, O],
+) Handle {
+	if fn == nil {
+		panic("tools.NewBatch: execute function cannot be nil")
+	}
+
+	b := &BatchTool[I, O]{executeBatch: fn}
+	b.Tool = NewWithOptions[I, O](name, description, b.executeOne, opts...).(*Tool[I, O])
+	return b
+}
+
+// executeOne runs a single input as a batch of one.
+func (b *BatchTool[I, O]) executeOne(ctx context.Context, input I, meta Meta) (O, error) {
+	var zero O
+	outputs, err := b.executeBatch(ctx, []I{input}, meta)
+	if err != nil {
+		return zero, err
+	}
+	if len(outputs) != 1 {
+		return zero, fmt.Errorf("batch returned %d outputs for 1 input", len(outputs))
+	}
+	return outputs[0], nil
+}
+
+// ExecBatch decodes and validates each input, executes the valid ones in a
+// single call and validates each output. Inputs or outputs that fail
+// validation produce an error for their call only; an error from the batch
+// function fails every call in the batch.
+func (b *BatchTool[I, O]) ExecBatch(ctx context.Context, raws []json.RawMessage, meta Meta) []core.BatchResult {
+	startTime := time.Now()
+	inputSize := 0
+	for _, raw := range raws {
+		inputSize += len(raw)
+	}
+	ctx, span := obs.StartToolSpan(ctx, obs.ToolSpanOptions{
+		ToolName:    b.name,
+		InputSize:   inputSize,
+		StepNumber:  meta.StepNumber,
+		Timeout:     time.Duration(b.timeout) * time.Second,
+		Description: b.description,
+		InputSchema: b.InSchemaJSON(),
+	})
+	defer span.End()
+
+	results := make([]core.BatchResult, len(raws))
+	inputs := make([]I, 0, len(raws))
+	indexes := make([]int, 0, len(raws))
+	for i, raw := range raws {
+		input, _, err := b.decodeInput(raw)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		inputs = append(inputs, input)
+		indexes = append(indexes, i)
+	}
+	if len(inputs) == 0 {
+		return results
+	}
+
+	outputs, err := b.executeBatch(ctx, inputs, meta)
+	if err == nil && len(outputs) != len(inputs) {
+		err = fmt.Errorf("batch returned %d outputs for %d inputs", len(outputs), len(inputs))
+	}
+	if err != nil {
+		err = fmt.Errorf("tool %s execution failed: %w", b.name, err)
+		obs.RecordError(span, err, "Tool execution failed")
+		obs.RecordToolResult(span, false, 0, time.Since(startTime))
+		obs.RecordToolExecution(ctx, b.name, false, time.Since(startTime))
+		for _, idx := range indexes {
+			results[idx].Err = err
+		}
+		return results
+	}
+
+	outputSize := 0
+	for j, idx := range indexes {
+		size, err := b.checkOutput(outputs[j])
+		if err != nil {
+			results[idx].Err = err
+			continue
+		}
+		outputSize += size
+		results[idx].Result = outputs[j]
+	}
+
+	obs.RecordToolResult(span, true, outputSize, time.Since(startTime))
+	obs.RecordToolExecution(ctx, b.name, true, time.Since(startTime))
+	return results
+}
+
+// checkOutput applies the output validator and size limit to one output and
+// returns its serialized size.
+func (b *BatchTool[I, O]) checkOutput(output O) (int, error) {
+	if b.validateOutput != nil {
+		if err := b.validateOutput(output); err != nil {
+			return 0, fmt.Errorf("output validation failed for tool %s: %w", b.name, asToolValidationError(err))
+		}
+	}
+
+	outputJSON, err := json.Marshal(output)
+	if err != nil {
+		if b.maxOutputSize > 0 {
+			return 0, fmt.Errorf("failed to marshal output for tool %s: %w", b.name, err)
+		}
+		return 0, nil
+	}
+	if b.maxOutputSize > 0 && len(outputJSON) > b.maxOutputSize {
+		return 0, fmt.Errorf("output size %d exceeds maximum %d", len(outputJSON), b.maxOutputSize)
+	}
+	return len(outputJSON), nil
+}
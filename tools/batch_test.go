@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/recera/gai/core"
+)
+
+func newGreetBatch(calls *int) Handle {
+	return NewBatch[SimpleInput, SimpleOutput](
+		"greet",
+		"Greets people",
+		func(ctx context.Context, in []SimpleInput, meta Meta) ([]SimpleOutput, error) {
+			*calls++
+			out := make([]SimpleOutput, len(in))
+			for i, person := range in {
+				out[i] = SimpleOutput{Message: "Hello, " + person.Name, Success: true}
+			}
+			return out, nil
+		},
+	)
+}
+
+func TestBatchToolExec(t *testing.T) {
+	calls := 0
+	tool := newGreetBatch(&calls)
+
+	result, err := tool.Exec(context.Background(), json.RawMessage(`{"name":"Ada","age":36}`), Meta{})
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if out := result.(SimpleOutput); out.Message != "Hello, Ada" {
+		t.Errorf("Unexpected output: %+v", out)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 batch call, got %d", calls)
+	}
+}
+
+func TestBatchToolExecBatch(t *testing.T) {
+	calls := 0
+	tool := newGreetBatch(&calls).(BatchHandle)
+
+	results := tool.ExecBatch(context.Background(), []json.RawMessage{
+		json.RawMessage(`{"name":"Ada","age":36}`),
+		json.RawMessage(`not json`),
+		json.RawMessage(`{"name":"Alan","age":41}`),
+	}, Meta{})
+
+	if calls != 1 {
+		t.Errorf("Expected 1 batch call, got %d", calls)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].Result.(SimpleOutput).Message != "Hello, Ada" {
+		t.Errorf("Unexpected first result: %+v", results[0])
+	}
+	if results[1].Err == nil {
+		t.Error("Expected invalid input to fail its own call")
+	}
+	if results[2].Err != nil || results[2].Result.(SimpleOutput).Message != "Hello, Alan" {
+		t.Errorf("Unexpected third result: %+v", results[2])
+	}
+}
+
+func TestBatchToolErrors(t *testing.T) {
+	failing := NewBatch[SimpleInput, SimpleOutput]("fail", "Always fails",
+		func(ctx context.Context, in []SimpleInput, meta Meta) ([]SimpleOutput, error) {
+			return nil, errors.New("backend down")
+		},
+	).(BatchHandle)
+	short := NewBatch[SimpleInput, SimpleOutput]("short", "Drops outputs",
+		func(ctx context.Context, in []SimpleInput, meta Meta) ([]SimpleOutput, error) {
+			return []SimpleOutput{{}}, nil
+		},
+	).(BatchHandle)
+
+	inputs := []json.RawMessage{
+		json.RawMessage(`{"name":"Ada","age":36}`),
+		json.RawMessage(`{"name":"Alan","age":41}`),
+	}
+	for _, tool := range []BatchHandle{failing, short} {
+		for i, result := range tool.ExecBatch(context.Background(), inputs, Meta{}) {
+			if result.Err == nil {
+				t.Errorf("%s: expected error for call %d", tool.Name(), i)
+			}
+		}
+	}
+}
+
+func TestExecuteBatchedCallsWithAdapter(t *testing.T) {
+	calls := 0
+	var single int
+	handles := ToCoreHandles([]Handle{
+		newGreetBatch(&calls),
+		New[SimpleInput, SimpleOutput]("echo", "Echoes input",
+			func(ctx context.Context, in SimpleInput, meta Meta) (SimpleOutput, error) {
+				single++
+				return SimpleOutput{Message: in.Name}, nil
+			},
+		),
+	})
+	if _, ok := handles[0].(core.BatchToolHandle); !ok {
+		t.Fatal("Expected batch tool adapter to implement core.BatchToolHandle")
+	}
+	if _, ok := handles[1].(core.BatchToolHandle); ok {
+		t.Error("Expected regular tool adapter not to implement core.BatchToolHandle")
+	}
+	if got := ToHandles(handles); len(got) != 2 {
+		t.Errorf("Expected ToHandles to unwrap both adapters, got %d", len(got))
+	}
+
+	toolCalls := []core.ToolCall{
+		{ID: "1", Name: "greet", Input: json.RawMessage(`{"name":"Ada","age":36}`)},
+		{ID: "2", Name: "echo", Input: json.RawMessage(`{"name":"Grace","age":85}`)},
+		{ID: "3", Name: "greet", Input: json.RawMessage(`{"name":"Alan","age":41}`)},
+	}
+	batched := core.ExecuteBatchedCalls(context.Background(), handles, toolCalls, nil)
+
+	if calls != 1 {
+		t.Errorf("Expected 1 batch call, got %d", calls)
+	}
+	if len(batched) != 2 {
+		t.Fatalf("Expected 2 batched results, got %d", len(batched))
+	}
+	for idx, name := range map[int]string{0: "Ada", 2: "Alan"} {
+		out := fmt.Sprint(batched[idx].Result)
+		if !strings.Contains(out, name) {
+			t.Errorf("Expected result %d to greet %s, got %s", idx, name, out)
+		}
+	}
+	if _, ok := batched[1]; ok {
+		t.Error("Expected non-batch call to be left for individual execution")
+	}
+	if single != 0 {
+		t.Error("Expected ExecuteBatchedCalls not to run non-batch tools")
+	}
+}
+
+func TestBatchToolExecBatchSanitizesInput(t *testing.T) {
+	var got []SimpleInput
+	tool := NewBatch[SimpleInput, SimpleOutput]("greet", "Greets people",
+		func(ctx context.Context, in []SimpleInput, meta Meta) ([]SimpleOutput, error) {
+			got = in
+			return make([]SimpleOutput, len(in)), nil
+		},
+		WithInputSanitizer[SimpleInput, SimpleOutput](func(in SimpleInput) (SimpleInput, error) {
+			in.Name = strings.TrimSpace(in.Name)
+			if in.Name == "" {
+				return in, errors.New("name is required")
+			}
+			return in, nil
+		}),
+	).(BatchHandle)
+
+	results := tool.ExecBatch(context.Background(), []json.RawMessage{
+		json.RawMessage(`{"name":"  Ada  ","age":36}`),
+		json.RawMessage(`{"name":"   ","age":41}`),
+	}, Meta{})
+
+	if len(got) != 1 || got[0].Name != "Ada" {
+		t.Errorf("Expected the sanitized input only, got %+v", got)
+	}
+	if results[0].Err != nil || results[1].Err == nil {
+		t.Errorf("Expected only the second call to fail, got %+v", results)
+	}
+}
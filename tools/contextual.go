@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+)
+
+// RequestContext carries request-level identity for tool implementations.
+// It lets tools act on behalf of the current user or tenant without
+// encoding that information in the input schema shown to the model.
+type RequestContext struct {
+	// UserID identifies the end user making the request
+	UserID string
+	// TenantID identifies the tenant making the request
+	TenantID string
+	// SessionID identifies the conversation or session
+	SessionID string
+	// Metadata contains all request metadata and telemetry tags
+	Metadata map[string]any
+}
+
+// RequestContextFromContext extracts the RequestContext from ctx.
+// Values come from the telemetry tags set by middleware.WithMetricsTags and
+// from the request metadata set by providers via core.WithMetadata; request
+// metadata wins when both define the same key.
+func RequestContextFromContext(ctx context.Context) RequestContext {
+	tags := obs.TagsFromContext(ctx)
+	meta := core.MetadataFromContext(ctx)
+
+	merged := make(map[string]any, len(tags)+len(meta))
+	for k, v := range tags {
+		merged[k] = v
+	}
+	for k, v := range meta {
+		merged[k] = v
+	}
+
+	return RequestContext{
+		UserID:    stringValue(merged[core.MetadataUserID]),
+		TenantID:  stringValue(merged[core.MetadataTenantID]),
+		SessionID: stringValue(merged[core.MetadataSessionID]),
+		Metadata:  merged,
+	}
+}
+
+// stringValue formats a metadata value as a string, treating nil as empty.
+func stringValue(v any) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// ContextualTool creates a typed tool whose execution function also receives
+// the RequestContext of the current request. The input schema is generated
+// from I alone, so user and tenant information never appears to the model.
+//
+// Example:
+//
+//	tool := tools.ContextualTool[OrderInput, OrderOutput](
+//	    "list_orders",
+//	    "List the current user's orders",
+//	    func(ctx context.Context, in OrderInput, meta tools.Meta, rc tools.RequestContext) (OrderOutput, error) {
+//	        return store.ListOrders(ctx, rc.TenantID, rc.UserID, in.Status)
+//	    },
+//	)
+func ContextualTool[I any, O any](
+	name string,
+	description string,
+	fn func(ctx context.Context, input I, meta Meta, requestCtx RequestContext) (O, error),
+) Handle {
+	if fn == nil {
+		panic("tools.ContextualTool: execute function cannot be nil")
+	}
+
+	return New[I, O](name, description, func(ctx context.Context, input I, meta Meta) (O, error) {
+		return fn(ctx, input, meta, RequestContextFromContext(ctx))
+	})
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+)
+
+func TestContextualTool(t *testing.T) {
+	var got RequestContext
+	tool := ContextualTool[SimpleInput, SimpleOutput](
+		"greet",
+		"Greet the current user",
+		func(ctx context.Context, in SimpleInput, meta Meta, rc RequestContext) (SimpleOutput, error) {
+			got = rc
+			return SimpleOutput{Message: "Hello " + in.Name}, nil
+		},
+	)
+
+	// Request identity must not leak into the schema shown to the model
+	if strings.Contains(string(tool.InSchemaJSON()), "tenant") {
+		t.Errorf("Input schema should only describe the input type: %s", tool.InSchemaJSON())
+	}
+
+	ctx := obs.ContextWithTags(context.Background(), map[string]string{
+		core.MetadataTenantID: "tag-tenant",
+		"environment":         "prod",
+	})
+	ctx = core.WithMetadata(ctx, map[string]any{
+		core.MetadataTenantID: "acme",
+		core.MetadataUserID:   42,
+		"correlation_id":      "abc",
+	})
+
+	result, err := tool.Exec(ctx, json.RawMessage(`{"name":"Ada","age":36}`), Meta{CallID: "call_1"})
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if result.(SimpleOutput).Message != "Hello Ada" {
+		t.Errorf("Unexpected result: %v", result)
+	}
+
+	if got.TenantID != "acme" {
+		t.Errorf("Expected request metadata to win over tags, got tenant %q", got.TenantID)
+	}
+	if got.UserID != "42" {
+		t.Errorf("Expected user 42, got %q", got.UserID)
+	}
+	if got.SessionID != "" {
+		t.Errorf("Expected empty session, got %q", got.SessionID)
+	}
+	if got.Metadata["environment"] != "prod" || got.Metadata["correlation_id"] != "abc" {
+		t.Errorf("Expected tags and metadata merged, got %v", got.Metadata)
+	}
+}
+
+func TestRequestContextFromContextEmpty(t *testing.T) {
+	rc := RequestContextFromContext(context.Background())
+	if rc.UserID != "" || rc.TenantID != "" || rc.SessionID != "" || len(rc.Metadata) != 0 {
+		t.Errorf("Expected empty request context, got %+v", rc)
+	}
+}
@@ -0,0 +1,198 @@
+// Package tools provides documentation generation for tools.
+// This file implements Markdown and OpenAPI 3.0 reference generation from
+// tool handles and their JSON schemas.
+
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ToolDoc describes a tool for user-facing documentation.
+type ToolDoc struct {
+	Name         string
+	Description  string
+	InputSchema  string
+	OutputSchema string
+	Examples     []ToolExample
+}
+
+// ToolExample is a sample invocation of a tool.
+type ToolExample struct {
+	// Description explains what the example demonstrates
+	Description string
+	// Input is the JSON input passed to the tool
+	Input json.RawMessage
+	// Output is the JSON result returned by the tool (optional)
+	Output json.RawMessage
+}
+
+// OpenAPIInfo contains the document-level metadata for GenerateOpenAPI.
+type OpenAPIInfo struct {
+	Title       string
+	Version     string
+	Description string
+	// ServerURL is the base URL under which /tools/{name} is served (optional)
+	ServerURL string
+}
+
+// DefaultDocumentation derives a ToolDoc from the handle's name, description
+// and schemas. Custom Handle implementations can return it from Documentation.
+func DefaultDocumentation(h Handle) ToolDoc {
+	return ToolDoc{
+		Name:         h.Name(),
+		Description:  h.Description(),
+		InputSchema:  string(h.InSchemaJSON()),
+		OutputSchema: string(h.OutSchemaJSON()),
+	}
+}
+
+// GenerateMarkdown produces a Markdown reference with one section per tool,
+// in the order given.
+func GenerateMarkdown(handles []Handle) string {
+	var b strings.Builder
+	b.WriteString("# Tool Reference\n")
+
+	for _, h := range handles {
+		doc := h.Documentation()
+
+		fmt.Fprintf(&b, "\n## %s\n\n", doc.Name)
+		if doc.Description != "" {
+			b.WriteString(doc.Description + "\n\n")
+		}
+
+		b.WriteString("### Input\n\n")
+		writeJSONBlock(&b, []byte(doc.InputSchema))
+
+		if doc.OutputSchema != "" {
+			b.WriteString("\n### Output\n\n")
+			writeJSONBlock(&b, []byte(doc.OutputSchema))
+		}
+
+		if len(doc.Examples) > 0 {
+			b.WriteString("\n### Examples\n")
+			for i, ex := range doc.Examples {
+				title := ex.Description
+				if title == "" {
+					title = fmt.Sprintf("Example %d", i+1)
+				}
+				fmt.Fprintf(&b, "\n**%s**\n\nInput:\n\n", title)
+				writeJSONBlock(&b, ex.Input)
+				if len(ex.Output) > 0 {
+					b.WriteString("\nOutput:\n\n")
+					writeJSONBlock(&b, ex.Output)
+				}
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// writeJSONBlock writes indented JSON in a fenced code block.
+func writeJSONBlock(b *strings.Builder, data []byte) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		out.Reset()
+		out.Write(data)
+	}
+	b.WriteString("```json\n")
+	b.Write(out.Bytes())
+	b.WriteString("\n```\n")
+}
+
+// GenerateOpenAPI produces an OpenAPI 3.0 document in which each tool is a
+// POST operation at /tools/{name}. The request body is the tool's input
+// schema and the 200 response is its output schema; the first example with
+// an input is attached to the request body.
+func GenerateOpenAPI(handles []Handle, info OpenAPIInfo) ([]byte, error) {
+	if info.Title == "" {
+		info.Title = "Tools"
+	}
+	if info.Version == "" {
+		info.Version = "1.0.0"
+	}
+
+	docInfo := map[string]any{
+		"title":   info.Title,
+		"version": info.Version,
+	}
+	if info.Description != "" {
+		docInfo["description"] = info.Description
+	}
+
+	paths := make(map[string]any, len(handles))
+	for _, h := range handles {
+		doc := h.Documentation()
+
+		input, err := openAPISchema(doc.InputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: invalid input schema: %w", doc.Name, err)
+		}
+		output, err := openAPISchema(doc.OutputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: invalid output schema: %w", doc.Name, err)
+		}
+
+		requestContent := map[string]any{"schema": input}
+		for _, ex := range doc.Examples {
+			if len(ex.Input) > 0 {
+				requestContent["example"] = ex.Input
+				break
+			}
+		}
+
+		path := "/tools/" + doc.Name
+		if _, exists := paths[path]; exists {
+			return nil, fmt.Errorf("duplicate tool name %q", doc.Name)
+		}
+		paths[path] = map[string]any{
+			"post": map[string]any{
+				"operationId": doc.Name,
+				"summary":     doc.Description,
+				"requestBody": map[string]any{
+					"required": true,
+					"content":  map[string]any{"application/json": requestContent},
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "Tool result",
+						"content": map[string]any{
+							"application/json": map[string]any{"schema": output},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	spec := map[string]any{
+		"openapi": "3.0.3",
+		"info":    docInfo,
+		"paths":   paths,
+	}
+	if info.ServerURL != "" {
+		spec["servers"] = []map[string]any{{"url": info.ServerURL}}
+	}
+
+	return json.MarshalIndent(spec, "", "  ")
+}
+
+// openAPISchema converts a JSON Schema document to an OpenAPI schema object
+// by dropping the JSON Schema meta keywords OpenAPI 3.0 does not accept.
+func openAPISchema(schema string) (map[string]any, error) {
+	if schema == "" {
+		return map[string]any{}, nil
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal([]byte(schema), &m); err != nil {
+		return nil, err
+	}
+	delete(m, "$schema")
+	delete(m, "$id")
+	return m, nil
+}
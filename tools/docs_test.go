@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newDocTool() Handle {
+	return NewWithOptions[SimpleInput, SimpleOutput](
+		"greet",
+		"Greets a user by name",
+		func(ctx context.Context, in SimpleInput, meta Meta) (SimpleOutput, error) {
+			return SimpleOutput{Message: "Hello " + in.Name, Success: true}, nil
+		},
+		Examples[SimpleInput, SimpleOutput](ToolExample{
+			Description: "Greet Ada",
+			Input:       json.RawMessage(`{"name":"Ada","age":36}`),
+			Output:      json.RawMessage(`{"message":"Hello Ada","success":true}`),
+		}),
+	)
+}
+
+func TestDocumentation(t *testing.T) {
+	doc := newDocTool().Documentation()
+
+	if doc.Name != "greet" || doc.Description != "Greets a user by name" {
+		t.Errorf("Unexpected name or description: %+v", doc)
+	}
+	if !strings.Contains(doc.InputSchema, `"name"`) || !strings.Contains(doc.OutputSchema, `"message"`) {
+		t.Errorf("Expected schema-derived docs, got input %s output %s", doc.InputSchema, doc.OutputSchema)
+	}
+	if len(doc.Examples) != 1 || doc.Examples[0].Description != "Greet Ada" {
+		t.Errorf("Expected example in docs, got %+v", doc.Examples)
+	}
+}
+
+func TestGenerateMarkdown(t *testing.T) {
+	md := GenerateMarkdown([]Handle{newDocTool()})
+
+	for _, want := range []string{
+		"# Tool Reference\n",
+		"## greet\n\nGreets a user by name\n",
+		"### Input\n\n```json\n{\n",
+		"### Output\n",
+		"**Greet Ada**",
+		"\"name\": \"Ada\"",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestGenerateOpenAPI(t *testing.T) {
+	data, err := GenerateOpenAPI([]Handle{newDocTool()}, OpenAPIInfo{Title: "Greeter", ServerURL: "https://api.example.com"})
+	if err != nil {
+		t.Fatalf("GenerateOpenAPI failed: %v", err)
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Title   string `json:"title"`
+			Version string `json:"version"`
+		} `json:"info"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]struct {
+			Post struct {
+				OperationID string `json:"operationId"`
+				RequestBody struct {
+					Content map[string]struct {
+						Schema  map[string]any `json:"schema"`
+						Example map[string]any `json:"example"`
+					} `json:"content"`
+				} `json:"requestBody"`
+				Responses map[string]any `json:"responses"`
+			} `json:"post"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	if spec.OpenAPI != "3.0.3" || spec.Info.Title != "Greeter" || spec.Info.Version != "1.0.0" {
+		t.Errorf("Unexpected header: %s %+v", spec.OpenAPI, spec.Info)
+	}
+	if len(spec.Servers) != 1 || spec.Servers[0].URL != "https://api.example.com" {
+		t.Errorf("Unexpected servers: %+v", spec.Servers)
+	}
+
+	op, ok := spec.Paths["/tools/greet"]
+	if !ok {
+		t.Fatalf("Expected /tools/greet path, got %v", spec.Paths)
+	}
+	if op.Post.OperationID != "greet" {
+		t.Errorf("Expected operationId greet, got %s", op.Post.OperationID)
+	}
+	body := op.Post.RequestBody.Content["application/json"]
+	if _, ok := body.Schema["$schema"]; ok {
+		t.Error("OpenAPI schema must not contain $schema")
+	}
+	if body.Schema["type"] != "object" || body.Example["name"] != "Ada" {
+		t.Errorf("Unexpected request body: %+v", body)
+	}
+	if _, ok := op.Post.Responses["200"]; !ok {
+		t.Error("Expected 200 response")
+	}
+
+	if _, err := GenerateOpenAPI([]Handle{newDocTool(), newDocTool()}, OpenAPIInfo{}); err == nil {
+		t.Error("Expected error for duplicate tool names")
+	}
+}
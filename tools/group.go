@@ -0,0 +1,87 @@
+// Package tools provides namespacing for tool names.
+// This file prefixes tool names with a group so that applications with many
+// tools can avoid name collisions.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+)
+
+// groupSeparator separates a tool's group from its name. It uses only
+// characters that provider APIs accept in tool names.
+const groupSeparator = obs.ToolGroupSeparator
+
+// NewGrouped returns handles whose names are prefixed with group, such as
+// "crm__get_customer". Everything else, including execution, is delegated to
+// the original handles; tool spans record the prefixed name and the group
+// as tool.group. Register the returned handles so that tool calls, which use
+// the prefixed name, dispatch to them. Batch tools stay batch tools.
+func NewGrouped(group string, handles ...Handle) []Handle {
+	grouped := make([]Handle, len(handles))
+	for i, h := range handles {
+		g := &groupedHandle{
+			Handle: h,
+			group:  group,
+			name:   group + groupSeparator + h.Name(),
+		}
+		if batch, ok := h.(BatchHandle); ok {
+			grouped[i] = &groupedBatchHandle{groupedHandle: g, batch: batch}
+			continue
+		}
+		grouped[i] = g
+	}
+	return grouped
+}
+
+// UnwrapGroup splits a grouped tool name into its group and name. Names
+// without a group return an empty group. For names grouped more than once,
+// such as "a__b__tool", the group is everything before the last separator.
+func UnwrapGroup(prefixedName string) (group, name string) {
+	i := strings.LastIndex(prefixedName, groupSeparator)
+	if i < 0 {
+		return "", prefixedName
+	}
+	return prefixedName[:i], prefixedName[i+len(groupSeparator):]
+}
+
+// groupedHandle is a Handle whose name is prefixed with a group.
+type groupedHandle struct {
+	Handle
+	group string
+	name  string
+}
+
+// Name returns the group-prefixed name.
+func (h *groupedHandle) Name() string {
+	return h.name
+}
+
+// Documentation returns the wrapped handle's documentation under the
+// group-prefixed name.
+func (h *groupedHandle) Documentation() ToolDoc {
+	doc := h.Handle.Documentation()
+	doc.Name = h.name
+	return doc
+}
+
+// Exec runs the wrapped handle, marking its span with the group.
+func (h *groupedHandle) Exec(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+	return h.Handle.Exec(obs.ContextWithToolGroup(ctx, h.group), raw, meta)
+}
+
+// groupedBatchHandle is a grouped BatchHandle.
+type groupedBatchHandle struct {
+	*groupedHandle
+	batch BatchHandle
+}
+
+// ExecBatch runs the wrapped batch handle, marking its span with the group.
+func (h *groupedBatchHandle) ExecBatch(ctx context.Context, inputs []json.RawMessage, meta Meta) []core.BatchResult {
+	return h.batch.ExecBatch(obs.ContextWithToolGroup(ctx, h.group), inputs, meta)
+}
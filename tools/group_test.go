@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/recera/gai/obs"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestNewGrouped(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	obs.SetGlobalTracerProvider(trace.NewTracerProvider(trace.WithSyncer(exporter)))
+	defer obs.SetGlobalTracerProvider(tracenoop.NewTracerProvider())
+
+	grouped := NewGrouped("crm", newEchoTool())
+	if len(grouped) != 1 {
+		t.Fatalf("got %d handles, want 1", len(grouped))
+	}
+	tool := grouped[0]
+	if tool.Name() != "crm__echo" {
+		t.Errorf("Name = %q, want crm__echo", tool.Name())
+	}
+	if doc := tool.Documentation(); doc.Name != "crm__echo" {
+		t.Errorf("Documentation().Name = %q, want crm__echo", doc.Name)
+	}
+
+	// Dispatch by the prefixed name
+	registry := NewRegistry()
+	if err := registry.Register(tool); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	found, ok := registry.Get("crm__echo")
+	if !ok {
+		t.Fatal("crm__echo not found")
+	}
+	out, err := found.Exec(context.Background(), json.RawMessage(`{"text":"hi"}`), Meta{})
+	if err != nil || out != "hi" {
+		t.Fatalf("Exec = %v, %v", out, err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	attrs := map[string]string{}
+	for _, attr := range spans[0].Attributes {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attrs["tool.name"] != "crm__echo" || attrs["tool.group"] != "crm" {
+		t.Errorf("tool.name = %q, tool.group = %q", attrs["tool.name"], attrs["tool.group"])
+	}
+}
+
+func TestUnwrapGroup(t *testing.T) {
+	tests := []struct {
+		in, group, name string
+	}{
+		{"crm__get_customer", "crm", "get_customer"},
+		{"get_customer", "", "get_customer"},
+		{"billing__eu__create_invoice", "billing__eu", "create_invoice"},
+	}
+	for _, tt := range tests {
+		group, name := UnwrapGroup(tt.in)
+		if group != tt.group || name != tt.name {
+			t.Errorf("UnwrapGroup(%q) = %q, %q; want %q, %q", tt.in, group, name, tt.group, tt.name)
+		}
+	}
+
+	// Nested groups join
+	nested := NewGrouped("billing", NewGrouped("eu", newEchoTool())...)
+	if nested[0].Name() != "billing__eu__echo" {
+		t.Errorf("nested Name = %q, want billing__eu__echo", nested[0].Name())
+	}
+}
+
+func TestNewGroupedBatch(t *testing.T) {
+	calls := 0
+	grouped := NewGrouped("people", newGreetBatch(&calls))
+	batch, ok := grouped[0].(BatchHandle)
+	if !ok {
+		t.Fatal("grouped batch tool does not implement BatchHandle")
+	}
+	if batch.Name() != "people__greet" {
+		t.Errorf("Name = %q, want people__greet", batch.Name())
+	}
+	// Provider APIs accept only letters, digits, "_" and "-"
+	if !regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`).MatchString(batch.Name()) {
+		t.Errorf("grouped name %q is not a valid provider tool name", batch.Name())
+	}
+
+	results := batch.ExecBatch(context.Background(), []json.RawMessage{
+		json.RawMessage(`{"name":"Ada","age":36}`),
+		json.RawMessage(`{"name":"Alan","age":41}`),
+	}, Meta{})
+	if calls != 1 || len(results) != 2 || results[1].Result.(SimpleOutput).Message != "Hello, Alan" {
+		t.Errorf("ExecBatch = %+v after %d calls", results, calls)
+	}
+}
@@ -0,0 +1,183 @@
+// Package tools provides runtime inspection of tool schemas.
+// This file implements InspectHandle and PrintHandle, which decode a tool's
+// JSON Schemas for debugging schemas that a provider rejects or misreads.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// HandleInspection is the decoded schema information of a tool.
+type HandleInspection struct {
+	Name        string
+	Description string
+	// InputSchema and OutputSchema are the decoded JSON Schemas, or nil if
+	// a schema is not valid JSON (see ParseErrors)
+	InputSchema  map[string]any
+	OutputSchema map[string]any
+	// RequiredFields and OptionalFields are the input properties, sorted.
+	// Nested object properties use dotted paths such as "address.city"; a
+	// nested property is required if its parent object lists it.
+	RequiredFields []string
+	OptionalFields []string
+	// EnumFields maps each input property with an enum to its allowed values
+	EnumFields map[string][]string
+	// ParseErrors describes schemas that could not be decoded
+	ParseErrors []string
+}
+
+// schemaField is an input property found while walking a schema.
+type schemaField struct {
+	path     string
+	typ      string
+	required bool
+	enum     []string
+}
+
+// InspectHandle decodes the input and output schemas of h. Schemas that are
+// not valid JSON objects are reported in ParseErrors rather than failing,
+// since those are usually what is being debugged.
+//
+// Example:
+//
+//	inspection := tools.InspectHandle(handle)
+//	if len(inspection.RequiredFields) == 0 {
+//		log.Printf("%s has no required inputs", inspection.Name)
+//	}
+func InspectHandle(h Handle) HandleInspection {
+	inspection := HandleInspection{
+		Name:        h.Name(),
+		Description: h.Description(),
+		EnumFields:  make(map[string][]string),
+	}
+
+	var err error
+	if inspection.InputSchema, err = decodeSchema(h.InSchemaJSON()); err != nil {
+		inspection.ParseErrors = append(inspection.ParseErrors, fmt.Sprintf("input schema: %v", err))
+	}
+	if inspection.OutputSchema, err = decodeSchema(h.OutSchemaJSON()); err != nil {
+		inspection.ParseErrors = append(inspection.ParseErrors, fmt.Sprintf("output schema: %v", err))
+	}
+
+	for _, field := range schemaFields(inspection.InputSchema) {
+		if field.required {
+			inspection.RequiredFields = append(inspection.RequiredFields, field.path)
+		} else {
+			inspection.OptionalFields = append(inspection.OptionalFields, field.path)
+		}
+		if field.enum != nil {
+			inspection.EnumFields[field.path] = field.enum
+		}
+	}
+	return inspection
+}
+
+// PrintHandle writes a human-readable summary of h's schemas to w: each
+// input property with its type, whether it is required and its allowed
+// values, followed by the output type and any schema parse errors.
+func PrintHandle(h Handle, w io.Writer) {
+	inspection := InspectHandle(h)
+
+	fmt.Fprintf(w, "Tool: %s\n", inspection.Name)
+	if inspection.Description != "" {
+		fmt.Fprintf(w, "Description: %s\n", inspection.Description)
+	}
+
+	fields := schemaFields(inspection.InputSchema)
+	if len(fields) == 0 {
+		fmt.Fprintf(w, "Input: %s (no properties)\n", schemaType(inspection.InputSchema))
+	} else {
+		fmt.Fprintln(w, "Input:")
+		for _, field := range fields {
+			line := fmt.Sprintf("  %s: %s", field.path, field.typ)
+			if field.required {
+				line += " (required)"
+			}
+			if field.enum != nil {
+				line += " one of " + strings.Join(field.enum, ", ")
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+	fmt.Fprintf(w, "Output: %s\n", schemaType(inspection.OutputSchema))
+
+	for _, msg := range inspection.ParseErrors {
+		fmt.Fprintf(w, "Error: %s\n", msg)
+	}
+}
+
+// decodeSchema parses a JSON Schema document into a map.
+func decodeSchema(data []byte) (map[string]any, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("schema is empty")
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// schemaFields returns the properties of an object schema and of its nested
+// object properties, sorted by path.
+func schemaFields(schema map[string]any) []schemaField {
+	var fields []schemaField
+	collectFields(schema, "", &fields)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].path < fields[j].path })
+	return fields
+}
+
+// collectFields appends the properties of schema, prefixing their names.
+func collectFields(schema map[string]any, prefix string, fields *[]schemaField) {
+	properties, _ := schema["properties"].(map[string]any)
+	required := make(map[string]bool)
+	if list, ok := schema["required"].([]any); ok {
+		for _, name := range list {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	for name, raw := range properties {
+		prop, _ := raw.(map[string]any)
+		field := schemaField{path: prefix + name, typ: schemaType(prop), required: required[name]}
+		if values, ok := prop["enum"].([]any); ok {
+			field.enum = make([]string, len(values))
+			for i, v := range values {
+				field.enum[i] = fmt.Sprint(v)
+			}
+		}
+		*fields = append(*fields, field)
+
+		if _, nested := prop["properties"]; nested {
+			collectFields(prop, field.path+".", fields)
+		}
+	}
+}
+
+// schemaType describes the type a schema accepts.
+func schemaType(schema map[string]any) string {
+	if schema == nil {
+		return "invalid"
+	}
+	switch t := schema["type"].(type) {
+	case string:
+		if items, ok := schema["items"].(map[string]any); ok && t == "array" {
+			return "array of " + schemaType(items)
+		}
+		return t
+	case []any:
+		types := make([]string, len(t))
+		for i, v := range t {
+			types[i] = fmt.Sprint(v)
+		}
+		return strings.Join(types, " | ")
+	}
+	return "any"
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type inspectAddress struct {
+	City    string `json:"city" jsonschema:"required"`
+	Country string `json:"country,omitempty"`
+}
+
+type inspectInput struct {
+	Query   string         `json:"query" jsonschema:"required"`
+	Units   string         `json:"units,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+	Tags    []string       `json:"tags,omitempty"`
+	Address inspectAddress `json:"address,omitempty"`
+}
+
+// brokenSchemaHandle returns an input schema that is not valid JSON.
+type brokenSchemaHandle struct {
+	Handle
+}
+
+func (h brokenSchemaHandle) InSchemaJSON() []byte {
+	return []byte(`{"type":"object",`)
+}
+
+func TestInspectHandle(t *testing.T) {
+	tool := New[inspectInput, SimpleOutput]("lookup", "Look something up",
+		func(ctx context.Context, in inspectInput, meta Meta) (SimpleOutput, error) {
+			return SimpleOutput{}, nil
+		})
+
+	inspection := InspectHandle(tool)
+	if inspection.Name != "lookup" || inspection.Description != "Look something up" {
+		t.Errorf("unexpected name or description: %+v", inspection)
+	}
+	if inspection.InputSchema["type"] != "object" || inspection.OutputSchema == nil {
+		t.Errorf("schemas were not decoded: %+v", inspection)
+	}
+	if want := []string{"address.city", "query"}; !reflect.DeepEqual(inspection.RequiredFields, want) {
+		t.Errorf("RequiredFields = %v, want %v", inspection.RequiredFields, want)
+	}
+	if want := []string{"address", "address.country", "tags", "units"}; !reflect.DeepEqual(inspection.OptionalFields, want) {
+		t.Errorf("OptionalFields = %v, want %v", inspection.OptionalFields, want)
+	}
+	if want := map[string][]string{"units": {"celsius", "fahrenheit"}}; !reflect.DeepEqual(inspection.EnumFields, want) {
+		t.Errorf("EnumFields = %v, want %v", inspection.EnumFields, want)
+	}
+	if len(inspection.ParseErrors) != 0 {
+		t.Errorf("unexpected parse errors: %v", inspection.ParseErrors)
+	}
+}
+
+func TestInspectHandleParseErrors(t *testing.T) {
+	inspection := InspectHandle(brokenSchemaHandle{NewFromStruct[SimpleInput]("broken", "")})
+	if inspection.InputSchema != nil || len(inspection.RequiredFields) != 0 {
+		t.Errorf("expected no input information, got %+v", inspection)
+	}
+	if len(inspection.ParseErrors) != 1 || !strings.HasPrefix(inspection.ParseErrors[0], "input schema:") {
+		t.Errorf("ParseErrors = %v", inspection.ParseErrors)
+	}
+}
+
+func TestPrintHandle(t *testing.T) {
+	tool := New[inspectInput, SimpleOutput]("lookup", "Look something up",
+		func(ctx context.Context, in inspectInput, meta Meta) (SimpleOutput, error) {
+			return SimpleOutput{}, nil
+		})
+
+	var b strings.Builder
+	PrintHandle(tool, &b)
+	out := b.String()
+	for _, want := range []string{
+		"Tool: lookup\n",
+		"  query: string (required)\n",
+		"  units: string one of celsius, fahrenheit\n",
+		"  tags: array of string\n",
+		"  address.city: string (required)\n",
+		"Output: object\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	b.Reset()
+	PrintHandle(brokenSchemaHandle{NewFromStruct[SimpleInput]("broken", "")}, &b)
+	if out := b.String(); !strings.Contains(out, "Input: invalid") || !strings.Contains(out, "Error: input schema:") {
+		t.Errorf("expected parse error in output:\n%s", out)
+	}
+}
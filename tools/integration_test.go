@@ -80,8 +80,11 @@ func TestIntegrationWithRunner(t *testing.T) {
 		t.Error("Input schema is empty")
 	}
 
-	outSchema := coreTool.OutSchemaJSON()
-	if len(outSchema) == 0 {
+	withOutput, ok := coreTool.(core.ToolHandleWithOutputSchema)
+	if !ok {
+		t.Fatal("Adapter does not implement core.ToolHandleWithOutputSchema")
+	}
+	if len(withOutput.OutSchemaJSON()) == 0 {
 		t.Error("Output schema is empty")
 	}
 
@@ -0,0 +1,175 @@
+// Package tools provides middleware for tool handles.
+// This file lets applications add cross-cutting behavior such as
+// authorization, logging and metrics to tools without changing them.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/recera/gai/core"
+	"github.com/recera/gai/obs"
+)
+
+// HandleMiddleware wraps a Handle with additional behavior.
+type HandleMiddleware func(Handle) Handle
+
+// Compose chains middlewares into a single HandleMiddleware. The first
+// middleware is the outermost layer, so it sees each call first.
+//
+// Example:
+//
+//	mw := tools.Compose(
+//		tools.WithToolAuth(checkAccess),
+//		tools.WithToolLogging(logger),
+//	)
+//	handle = mw(handle)
+func Compose(middlewares ...HandleMiddleware) HandleMiddleware {
+	return func(h Handle) Handle {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			h = middlewares[i](h)
+		}
+		return h
+	}
+}
+
+// ExecFunc is the signature of Handle.Exec.
+type ExecFunc func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error)
+
+// WrapExec returns middleware that replaces Exec with wrap, which receives
+// the wrapped handle's Exec as next. Name, schemas and documentation are
+// those of the wrapped handle.
+//
+// Batch tools stay batch tools: wrap runs once around each batch, with the
+// inputs as a JSON array and next returning the []core.BatchResult. If wrap
+// fails without calling next, every call in the batch fails with its error.
+func WrapExec(wrap func(h Handle, next ExecFunc) ExecFunc) HandleMiddleware {
+	return func(h Handle) Handle {
+		wrapped := &wrappedHandle{Handle: h, exec: wrap(h, h.Exec)}
+		if batch, ok := h.(BatchHandle); ok {
+			return &wrappedBatchHandle{wrappedHandle: wrapped, batch: batch, wrap: wrap}
+		}
+		return wrapped
+	}
+}
+
+// wrappedHandle is a Handle whose Exec is replaced by middleware.
+type wrappedHandle struct {
+	Handle
+	exec ExecFunc
+}
+
+// Exec runs the middleware's execution function.
+func (h *wrappedHandle) Exec(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+	return h.exec(ctx, raw, meta)
+}
+
+// wrappedBatchHandle is a wrapped BatchHandle.
+type wrappedBatchHandle struct {
+	*wrappedHandle
+	batch BatchHandle
+	wrap  func(h Handle, next ExecFunc) ExecFunc
+}
+
+// ExecBatch runs the middleware once around the wrapped batch handle.
+func (h *wrappedBatchHandle) ExecBatch(ctx context.Context, inputs []json.RawMessage, meta Meta) []core.BatchResult {
+	var results []core.BatchResult
+	ran := false
+	exec := h.wrap(h.batch, func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+		ran = true
+		results = h.batch.ExecBatch(ctx, inputs, meta)
+		for _, r := range results {
+			if r.Err != nil {
+				return results, r.Err
+			}
+		}
+		return results, nil
+	})
+
+	// Invalid inputs leave raw empty; the batch handle reports them per call
+	raw, _ := json.Marshal(inputs)
+	output, err := exec(ctx, raw, meta)
+	if ran {
+		return results
+	}
+	results = make([]core.BatchResult, len(inputs))
+	for i := range results {
+		results[i] = core.BatchResult{Result: output, Err: err}
+	}
+	return results
+}
+
+// WithToolAuth creates middleware that calls authFn before each execution
+// and refuses to run the tool if it returns an error.
+func WithToolAuth(authFn func(ctx context.Context, callID string) error) HandleMiddleware {
+	return WrapExec(func(h Handle, next ExecFunc) ExecFunc {
+		return func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+			if authFn != nil {
+				if err := authFn(ctx, meta.CallID); err != nil {
+					return nil, fmt.Errorf("tool %s not authorized: %w", h.Name(), err)
+				}
+			}
+			return next(ctx, raw, meta)
+		}
+	})
+}
+
+// WithToolLogging creates middleware that logs each execution's input,
+// output, duration and error to logger. A nil logger uses slog.Default().
+func WithToolLogging(logger *slog.Logger) HandleMiddleware {
+	return WrapExec(func(h Handle, next ExecFunc) ExecFunc {
+		return func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+			l := logger
+			if l == nil {
+				l = slog.Default()
+			}
+
+			start := time.Now()
+			output, err := next(ctx, raw, meta)
+			attrs := []any{
+				slog.String("tool", h.Name()),
+				slog.String("call_id", meta.CallID),
+				slog.String("input", string(raw)),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if err != nil {
+				l.ErrorContext(ctx, "tool execution failed", append(attrs, slog.Any("error", err))...)
+			} else {
+				l.InfoContext(ctx, "tool executed", append(attrs, slog.Any("output", output))...)
+			}
+			return output, err
+		}
+	})
+}
+
+// WithToolMetrics creates middleware that reports each execution's duration
+// and error to collector. A nil collector records to the obs package's
+// global metrics.
+func WithToolMetrics(collector core.MetricsCollector) HandleMiddleware {
+	return WrapExec(func(h Handle, next ExecFunc) ExecFunc {
+		return func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+			start := time.Now()
+			output, err := next(ctx, raw, meta)
+			if collector != nil {
+				collector.RecordToolExecution(h.Name(), time.Since(start), err)
+			} else {
+				obs.RecordToolExecution(ctx, h.Name(), err == nil, time.Since(start))
+			}
+			return output, err
+		}
+	})
+}
+
+// ApplyMiddleware wraps every registered tool with m.
+func (r *Registry) ApplyMiddleware(m HandleMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, tool := range r.tools {
+		r.tools[name] = m(tool)
+	}
+}
@@ -0,0 +1,181 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recera/gai/core"
+)
+
+type echoInput struct {
+	Text string `json:"text"`
+}
+
+func newEchoTool() Handle {
+	return New[echoInput, string]("echo", "Echo text", func(ctx context.Context, in echoInput, meta Meta) (string, error) {
+		return in.Text, nil
+	})
+}
+
+func TestCompose(t *testing.T) {
+	var order []string
+	tag := func(name string) HandleMiddleware {
+		return WrapExec(func(h Handle, next ExecFunc) ExecFunc {
+			return func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+				order = append(order, name)
+				return next(ctx, raw, meta)
+			}
+		})
+	}
+
+	tool := Compose(tag("outer"), tag("inner"))(newEchoTool())
+	if tool.Name() != "echo" {
+		t.Errorf("Name = %q, want echo", tool.Name())
+	}
+	out, err := tool.Exec(context.Background(), json.RawMessage(`{"text":"hi"}`), Meta{})
+	if err != nil || out != "hi" {
+		t.Fatalf("Exec = %v, %v", out, err)
+	}
+	if strings.Join(order, ",") != "outer,inner" {
+		t.Errorf("order = %v, want outer,inner", order)
+	}
+}
+
+func TestWithToolAuth(t *testing.T) {
+	tool := WithToolAuth(func(ctx context.Context, callID string) error {
+		if callID != "allowed" {
+			return errors.New("denied")
+		}
+		return nil
+	})(newEchoTool())
+
+	if _, err := tool.Exec(context.Background(), json.RawMessage(`{"text":"hi"}`), Meta{CallID: "allowed"}); err != nil {
+		t.Errorf("authorized call failed: %v", err)
+	}
+	if _, err := tool.Exec(context.Background(), json.RawMessage(`{"text":"hi"}`), Meta{CallID: "other"}); err == nil ||
+		!strings.Contains(err.Error(), "not authorized") {
+		t.Errorf("expected authorization error, got %v", err)
+	}
+}
+
+func TestWithToolLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	tool := WithToolLogging(logger)(newEchoTool())
+	if _, err := tool.Exec(context.Background(), json.RawMessage(`{"text":"hi"}`), Meta{CallID: "call_1"}); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	logged := buf.String()
+	for _, want := range []string{"tool=echo", "call_id=call_1", "output=hi"} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("log %q missing %q", logged, want)
+		}
+	}
+}
+
+type toolRecorder struct {
+	mu    sync.Mutex
+	names []string
+	errs  []error
+}
+
+func (r *toolRecorder) RecordStep(core.Step, time.Duration)     {}
+func (r *toolRecorder) RecordTotalExecution(int, time.Duration) {}
+func (r *toolRecorder) RecordToolExecution(name string, _ time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names = append(r.names, name)
+	r.errs = append(r.errs, err)
+}
+
+func TestWithToolMetrics(t *testing.T) {
+	recorder := &toolRecorder{}
+	tool := WithToolMetrics(recorder)(newEchoTool())
+
+	tool.Exec(context.Background(), json.RawMessage(`{"text":"hi"}`), Meta{})
+	tool.Exec(context.Background(), json.RawMessage(`not json`), Meta{})
+
+	if len(recorder.names) != 2 || recorder.names[0] != "echo" {
+		t.Fatalf("recorded %v", recorder.names)
+	}
+	if recorder.errs[0] != nil || recorder.errs[1] == nil {
+		t.Errorf("recorded errors %v", recorder.errs)
+	}
+}
+
+func TestWrapExecBatch(t *testing.T) {
+	calls := 0
+	var inputs []string
+	record := WrapExec(func(h Handle, next ExecFunc) ExecFunc {
+		return func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+			inputs = append(inputs, string(raw))
+			return next(ctx, raw, meta)
+		}
+	})
+
+	batch, ok := Compose(record, WithToolMetrics(nil))(newGreetBatch(&calls)).(BatchHandle)
+	if !ok {
+		t.Fatal("wrapped batch tool does not implement BatchHandle")
+	}
+	results := batch.ExecBatch(context.Background(), []json.RawMessage{
+		json.RawMessage(`{"name":"Ada","age":36}`),
+		json.RawMessage(`{"name":"Alan","age":41}`),
+	}, Meta{})
+	if calls != 1 || len(results) != 2 {
+		t.Fatalf("calls = %d, results = %+v; want one batch call with 2 results", calls, results)
+	}
+	if out := results[1].Result.(SimpleOutput); out.Message != "Hello, Alan" {
+		t.Errorf("Unexpected output: %+v", out)
+	}
+	if len(inputs) != 1 || !strings.HasPrefix(inputs[0], "[") {
+		t.Errorf("middleware saw %v, want the batch once as a JSON array", inputs)
+	}
+
+	// Middleware that refuses the batch fails every call
+	deny := WithToolAuth(func(ctx context.Context, callID string) error { return errors.New("denied") })
+	results = deny(newGreetBatch(&calls)).(BatchHandle).ExecBatch(context.Background(), []json.RawMessage{
+		json.RawMessage(`{"name":"Ada","age":36}`),
+		json.RawMessage(`{"name":"Alan","age":41}`),
+	}, Meta{})
+	if calls != 1 {
+		t.Errorf("denied batch ran the tool")
+	}
+	for i, r := range results {
+		if r.Err == nil {
+			t.Errorf("result %d: expected the auth error", i)
+		}
+	}
+}
+
+func TestRegistryApplyMiddleware(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(newEchoTool())
+
+	calls := 0
+	registry.ApplyMiddleware(WrapExec(func(h Handle, next ExecFunc) ExecFunc {
+		return func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+			calls++
+			return next(ctx, raw, meta)
+		}
+	}))
+
+	tool, ok := registry.Get("echo")
+	if !ok {
+		t.Fatal("echo not registered")
+	}
+	if _, err := tool.Exec(context.Background(), json.RawMessage(`{"text":"hi"}`), Meta{}); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("middleware ran %d times, want 1", calls)
+	}
+}
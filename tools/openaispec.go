@@ -0,0 +1,139 @@
+// Package tools provides tools defined by OpenAI-format JSON specs.
+// This file lets applications migrating from raw OpenAI client code reuse
+// their existing tool definitions.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/recera/gai/obs"
+)
+
+// openAIToolSpec is a tool definition in OpenAI's chat completions format.
+type openAIToolSpec struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Parameters  json.RawMessage `json:"parameters"`
+	} `json:"function"`
+}
+
+// specTool is a tool whose input schema comes from a JSON spec rather than a
+// Go type. Its input is passed to the execution function as raw JSON.
+type specTool struct {
+	name        string
+	description string
+	inSchema    []byte
+	execute     func(context.Context, json.RawMessage, Meta) (any, error)
+}
+
+// NewFromOpenAISpec creates a tool from an OpenAI tool definition such as
+//
+//	{"type": "function", "function": {"name": "...", "description": "...", "parameters": {...}}}
+//
+// InSchemaJSON returns the parameters object, and Exec validates the input
+// against it before calling execFn with the raw JSON arguments. A missing
+// parameters object means the tool takes no arguments.
+func NewFromOpenAISpec(spec []byte, execFn func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error)) (Handle, error) {
+	if execFn == nil {
+		return nil, fmt.Errorf("tools.NewFromOpenAISpec: execute function cannot be nil")
+	}
+
+	var s openAIToolSpec
+	if err := json.Unmarshal(spec, &s); err != nil {
+		return nil, fmt.Errorf("invalid OpenAI tool spec: %w", err)
+	}
+	if s.Type != "function" {
+		return nil, fmt.Errorf("unsupported OpenAI tool type %q, expected \"function\"", s.Type)
+	}
+	if s.Function.Name == "" {
+		return nil, fmt.Errorf("OpenAI tool spec is missing function.name")
+	}
+
+	params := []byte(`{"type":"object","properties":{}}`)
+	if len(s.Function.Parameters) > 0 && string(s.Function.Parameters) != "null" {
+		var obj map[string]any
+		if err := json.Unmarshal(s.Function.Parameters, &obj); err != nil {
+			return nil, fmt.Errorf("parameters of OpenAI tool %s must be a JSON object: %w", s.Function.Name, err)
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, s.Function.Parameters); err != nil {
+			return nil, fmt.Errorf("invalid parameters for OpenAI tool %s: %w", s.Function.Name, err)
+		}
+		params = buf.Bytes()
+	}
+
+	return &specTool{
+		name:        s.Function.Name,
+		description: s.Function.Description,
+		inSchema:    params,
+		execute:     execFn,
+	}, nil
+}
+
+// Name returns the function name from the spec.
+func (t *specTool) Name() string {
+	return t.name
+}
+
+// Description returns the function description from the spec.
+func (t *specTool) Description() string {
+	return t.description
+}
+
+// InSchemaJSON returns the parameters object from the spec.
+func (t *specTool) InSchemaJSON() []byte {
+	return t.inSchema
+}
+
+// OutSchemaJSON returns a schema accepting any output, since OpenAI specs
+// don't describe results.
+func (t *specTool) OutSchemaJSON() []byte {
+	return []byte(`{}`)
+}
+
+// Documentation returns schema-derived documentation.
+func (t *specTool) Documentation() ToolDoc {
+	return DefaultDocumentation(t)
+}
+
+// Exec validates raw against the parameters schema and calls the execution
+// function.
+func (t *specTool) Exec(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+	startTime := time.Now()
+	ctx, span := obs.StartToolSpanFromHandle(ctx, t, meta.CallID, raw)
+	obs.RecordToolStep(span, meta.StepNumber)
+	defer span.End()
+
+	if err := ValidateJSON(raw, t.inSchema); err != nil {
+		err = fmt.Errorf("input validation failed for tool %s: %w", t.name, err)
+		obs.RecordError(span, err, "Schema validation failed")
+		return nil, err
+	}
+	meta.Input = raw
+
+	output, err := t.execute(ctx, raw, meta)
+	if err != nil {
+		err = fmt.Errorf("tool %s execution failed: %w", t.name, err)
+		obs.RecordError(span, err, "Tool execution failed")
+		obs.RecordToolResult(span, false, 0, time.Since(startTime))
+		obs.RecordToolResultContent(span, nil, err)
+		return nil, err
+	}
+
+	outputSize := 0
+	if outputJSON, err := json.Marshal(output); err == nil {
+		outputSize = len(outputJSON)
+	}
+	obs.RecordToolResult(span, true, outputSize, time.Since(startTime))
+	obs.RecordToolResultContent(span, output, nil)
+	obs.RecordToolExecution(ctx, t.name, true, time.Since(startTime))
+
+	return output, nil
+}
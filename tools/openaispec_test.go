@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewFromOpenAISpec(t *testing.T) {
+	spec := []byte(`{
+		"type": "function",
+		"function": {
+			"name": "get_weather",
+			"description": "Get the current weather",
+			"parameters": {
+				"type": "object",
+				"properties": {"city": {"type": "string"}},
+				"required": ["city"]
+			}
+		}
+	}`)
+
+	var gotRaw json.RawMessage
+	tool, err := NewFromOpenAISpec(spec, func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+		gotRaw = raw
+		return map[string]any{"temp": 21}, nil
+	})
+	if err != nil {
+		t.Fatalf("NewFromOpenAISpec failed: %v", err)
+	}
+
+	if tool.Name() != "get_weather" || tool.Description() != "Get the current weather" {
+		t.Errorf("unexpected name or description: %q, %q", tool.Name(), tool.Description())
+	}
+	want := `{"type":"object","properties":{"city":{"type":"string"}},"required":["city"]}`
+	if got := string(tool.InSchemaJSON()); got != want {
+		t.Errorf("InSchemaJSON = %s, want %s", got, want)
+	}
+
+	result, err := tool.Exec(context.Background(), json.RawMessage(`{"city":"Paris"}`), Meta{CallID: "call_1"})
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if string(gotRaw) != `{"city":"Paris"}` {
+		t.Errorf("execFn got %s", gotRaw)
+	}
+	if result.(map[string]any)["temp"] != 21 {
+		t.Errorf("unexpected result: %v", result)
+	}
+
+	// Input is validated against the parameters schema
+	if _, err := tool.Exec(context.Background(), json.RawMessage(`{}`), Meta{}); err == nil ||
+		!strings.Contains(err.Error(), `missing required field "city"`) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+
+	// The handle works with the core adapter
+	if err := ValidateHandle(NewCoreAdapter(tool)); err != nil {
+		t.Errorf("ValidateHandle failed: %v", err)
+	}
+}
+
+func TestNewFromOpenAISpecErrors(t *testing.T) {
+	exec := func(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) { return nil, nil }
+
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"invalid JSON", `{`},
+		{"wrong type", `{"type":"retrieval","function":{"name":"x"}}`},
+		{"missing name", `{"type":"function","function":{"description":"x"}}`},
+		{"non-object parameters", `{"type":"function","function":{"name":"x","parameters":[1]}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFromOpenAISpec([]byte(tt.spec), exec); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if _, err := NewFromOpenAISpec([]byte(`{"type":"function","function":{"name":"x"}}`), nil); err == nil {
+		t.Error("expected error for nil execute function")
+	}
+
+	// Tools without parameters take an empty object
+	tool, err := NewFromOpenAISpec([]byte(`{"type":"function","function":{"name":"ping"}}`), exec)
+	if err != nil {
+		t.Fatalf("NewFromOpenAISpec failed: %v", err)
+	}
+	if _, err := tool.Exec(context.Background(), json.RawMessage(`{}`), Meta{}); err != nil {
+		t.Errorf("Exec failed: %v", err)
+	}
+}
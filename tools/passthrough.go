@@ -0,0 +1,43 @@
+// Package tools provides passthrough tools built from a struct alone.
+// This file implements NewFromStruct, for schema-first design and for
+// placeholder tools whose handler has not been written yet.
+
+package tools
+
+import "context"
+
+// NewFromStruct creates a tool whose input schema is generated from T and
+// whose Exec returns the decoded input unchanged. Its output schema is {}.
+// It is useful for inspecting the schema a struct produces, for mocks in
+// tests, and as a placeholder until the real handler is implemented; inputs
+// are validated and decoded exactly as they would be by New.
+//
+// Example:
+//
+//	placeholder := tools.NewFromStruct[BookingRequest]("book_flight", "Book a flight")
+//	fmt.Println(string(placeholder.InSchemaJSON()))
+func NewFromStruct[T any](name, description string) Handle {
+	return &passthroughHandle{
+		Handle: New[T, T](name, description, func(ctx context.Context, input T, meta Meta) (T, error) {
+			return input, nil
+		}),
+	}
+}
+
+// passthroughHandle is an identity tool that does not describe its output.
+type passthroughHandle struct {
+	Handle
+}
+
+// OutSchemaJSON returns the empty schema, which accepts any output.
+func (h *passthroughHandle) OutSchemaJSON() []byte {
+	return []byte(`{}`)
+}
+
+// Documentation returns the wrapped tool's documentation with the empty
+// output schema.
+func (h *passthroughHandle) Documentation() ToolDoc {
+	doc := h.Handle.Documentation()
+	doc.OutputSchema = string(h.OutSchemaJSON())
+	return doc
+}
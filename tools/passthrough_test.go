@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewFromStruct(t *testing.T) {
+	tool := NewFromStruct[SimpleInput]("echo", "Echo the input")
+
+	if tool.Name() != "echo" || tool.Description() != "Echo the input" {
+		t.Errorf("unexpected name or description: %q, %q", tool.Name(), tool.Description())
+	}
+	if !strings.Contains(string(tool.InSchemaJSON()), `"name"`) {
+		t.Errorf("input schema should describe SimpleInput: %s", tool.InSchemaJSON())
+	}
+	if got := string(tool.OutSchemaJSON()); got != `{}` {
+		t.Errorf("OutSchemaJSON = %s, want {}", got)
+	}
+	if got := tool.Documentation().OutputSchema; got != `{}` {
+		t.Errorf("documented output schema = %s, want {}", got)
+	}
+
+	result, err := tool.Exec(context.Background(), json.RawMessage(`{"name":"Ada","age":36}`), Meta{CallID: "call_1"})
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if got, ok := result.(SimpleInput); !ok || got.Name != "Ada" || got.Age != 36 {
+		t.Errorf("Exec result = %#v, want the decoded input", result)
+	}
+
+	if _, err := tool.Exec(context.Background(), json.RawMessage(`{"name":`), Meta{}); err == nil {
+		t.Error("expected an error for malformed input")
+	}
+}
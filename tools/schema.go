@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/invopop/jsonschema"
+	"github.com/recera/gai/core"
 )
 
 // schemaCache stores generated schemas to avoid redundant reflection.
@@ -60,6 +61,23 @@ func (c *schemaCacheImpl) size() int {
 
 // GenerateSchema generates a JSON Schema for the given Go type.
 // The schema is cached for performance.
+//
+// Struct fields can carry constraints in their jsonschema tag, which are
+// forwarded to the provider and enforced by ValidateJSON:
+//
+//	Age   int    `json:"age" jsonschema:"minimum=0,maximum=100"`
+//	Name  string `json:"name" jsonschema:"minLength=1,maxLength=256"`
+//	Slug  string `json:"slug" jsonschema:"pattern=^[a-z]+$"`
+//	Link  string `json:"link" jsonschema:"format=uri"`
+//	Color string `json:"color" jsonschema:"enum=red,enum=green"`
+//
+// time.Time fields are RFC 3339 strings (format date-time, or date with
+// `jsonschema:"format=date"`). time.Duration fields are numbers of
+// nanoseconds, or duration strings such as "1h30m" when tagged
+// `jsonschema:"type=string,format=duration"`.
+//
+// Commas inside a tag value (for example a pattern quantifier) must be
+// escaped with a backslash, e.g. `jsonschema:"pattern=^[a-z]{1\\,8}$"`.
 func GenerateSchema(t reflect.Type) ([]byte, error) {
 	// Check cache first
 	if schema, ok := schemaCache.get(t); ok {
@@ -74,6 +92,8 @@ func GenerateSchema(t reflect.Type) ([]byte, error) {
 		RequiredFromJSONSchemaTags: true,
 		// Don't create references for top-level definitions
 		DoNotReference: true,
+		// Give any-typed fields explicit schemas
+		Mapper: mapAnyTypes,
 	}
 	
 	// Handle special cases
@@ -90,6 +110,9 @@ func GenerateSchema(t reflect.Type) ([]byte, error) {
 		}
 	}
 	
+	// Document the encoding of time.Duration fields
+	describeDurations(schema, t)
+	
 	// Set schema metadata
 	if schema.Title == "" {
 		schema.Title = t.Name()
@@ -107,6 +130,34 @@ func GenerateSchema(t reflect.Type) ([]byte, error) {
 	return schemaJSON, nil
 }
 
+// Schema returns the JSON Schema for type T, generated the same way as tool
+// input and output schemas. It is useful wherever a schema is needed without
+// a tool, such as core.Request.ResponseSchema or API documentation. If the
+// schema cannot be generated, Schema returns the minimal schema
+// {"type":"object"}, as tools do; use MustSchema to fail instead.
+//
+// Example:
+//
+//	req.ResponseSchema = tools.Schema[Recipe]()
+func Schema[T any]() json.RawMessage {
+	schema, err := GenerateSchema(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return json.RawMessage(`{"type":"object"}`)
+	}
+	return schema
+}
+
+// MustSchema is like Schema but panics if the schema cannot be generated.
+// It is intended for package-level variables.
+func MustSchema[T any]() json.RawMessage {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	schema, err := GenerateSchema(t)
+	if err != nil {
+		panic(fmt.Sprintf("tools: schema for %s: %v", t, err))
+	}
+	return schema
+}
+
 // handleSpecialTypes provides custom schema handling for specific types.
 func handleSpecialTypes(t reflect.Type, r *jsonschema.Reflector) *jsonschema.Schema {
 	// Handle empty interface{}
@@ -137,13 +188,10 @@ func handleSpecialTypes(t reflect.Type, r *jsonschema.Reflector) *jsonschema.Sch
 	}
 	
 	// Handle slices of any
-	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Interface {
+	if t.Kind() == reflect.Slice && isEmptyInterface(t.Elem()) {
 		return &jsonschema.Schema{
-			Type: "array",
-			Items: &jsonschema.Schema{
-				Type:                 "object",
-				AdditionalProperties: jsonschema.TrueSchema,
-			},
+			Type:        "array",
+			Items:       anySchema(),
 			Description: "Array of any values",
 		}
 	}
@@ -151,167 +199,51 @@ func handleSpecialTypes(t reflect.Type, r *jsonschema.Reflector) *jsonschema.Sch
 	return nil
 }
 
-// ValidateJSON validates JSON data against a JSON Schema.
-// This is used for runtime validation when providers don't support strict mode.
-func ValidateJSON(data json.RawMessage, schema []byte) error {
-	if len(data) == 0 {
-		return fmt.Errorf("empty JSON data")
-	}
-	
-	if len(schema) == 0 {
-		return fmt.Errorf("empty schema")
-	}
-	
-	// Parse the schema
-	var schemaObj map[string]interface{}
-	if err := json.Unmarshal(schema, &schemaObj); err != nil {
-		return fmt.Errorf("invalid schema: %w", err)
-	}
-	
-	// Parse the data
-	var dataObj interface{}
-	if err := json.Unmarshal(data, &dataObj); err != nil {
-		return fmt.Errorf("invalid JSON data: %w", err)
-	}
-	
-	// Perform basic validation
-	// Note: For production, we would integrate a full JSON Schema validator
-	// For now, we do basic type checking
-	return validateBasic(dataObj, schemaObj)
-}
-
-// validateBasic performs basic type validation.
-// This is a simplified validator for Phase 2.
-// A full implementation would use a complete JSON Schema validator library.
-func validateBasic(data interface{}, schema map[string]interface{}) error {
-	schemaType, ok := schema["type"].(string)
-	if !ok {
-		// No type specified, allow any
-		return nil
-	}
-	
-	switch schemaType {
-	case "object":
-		obj, ok := data.(map[string]interface{})
-		if !ok {
-			return fmt.Errorf("expected object, got %T", data)
-		}
-		
-		// Validate required fields
-		if required, ok := schema["required"].([]interface{}); ok {
-			for _, req := range required {
-				if reqStr, ok := req.(string); ok {
-					if _, exists := obj[reqStr]; !exists {
-						return fmt.Errorf("missing required field: %s", reqStr)
-					}
-				}
-			}
-		}
-		
-		// Validate properties
-		if properties, ok := schema["properties"].(map[string]interface{}); ok {
-			for key, value := range obj {
-				if propSchema, ok := properties[key].(map[string]interface{}); ok {
-					if err := validateBasic(value, propSchema); err != nil {
-						return fmt.Errorf("field %s: %w", key, err)
-					}
-				} else if additionalProps, ok := schema["additionalProperties"].(bool); ok && !additionalProps {
-					return fmt.Errorf("unexpected field: %s", key)
-				}
-			}
-		}
-		
-	case "array":
-		arr, ok := data.([]interface{})
-		if !ok {
-			return fmt.Errorf("expected array, got %T", data)
-		}
-		
-		// Validate items
-		if itemSchema, ok := schema["items"].(map[string]interface{}); ok {
-			for i, item := range arr {
-				if err := validateBasic(item, itemSchema); err != nil {
-					return fmt.Errorf("item %d: %w", i, err)
-				}
-			}
-		}
-		
-		// Validate array constraints
-		if minItems, ok := schema["minItems"].(float64); ok && len(arr) < int(minItems) {
-			return fmt.Errorf("array has %d items, minimum %d required", len(arr), int(minItems))
-		}
-		if maxItems, ok := schema["maxItems"].(float64); ok && len(arr) > int(maxItems) {
-			return fmt.Errorf("array has %d items, maximum %d allowed", len(arr), int(maxItems))
-		}
-		
-	case "string":
-		str, ok := data.(string)
-		if !ok {
-			return fmt.Errorf("expected string, got %T", data)
-		}
-		
-		// Validate string constraints
-		if minLength, ok := schema["minLength"].(float64); ok && len(str) < int(minLength) {
-			return fmt.Errorf("string length %d is less than minimum %d", len(str), int(minLength))
-		}
-		if maxLength, ok := schema["maxLength"].(float64); ok && len(str) > int(maxLength) {
-			return fmt.Errorf("string length %d exceeds maximum %d", len(str), int(maxLength))
-		}
-		
-		// Validate enum
-		if enum, ok := schema["enum"].([]interface{}); ok {
-			found := false
-			for _, e := range enum {
-				if e == str {
-					found = true
-					break
-				}
-			}
-			if !found {
-				return fmt.Errorf("value %q not in enum", str)
-			}
-		}
-		
-	case "number", "integer":
-		num, ok := data.(float64)
-		if !ok {
-			// Try to convert from int
-			if intVal, ok := data.(int); ok {
-				num = float64(intVal)
-			} else {
-				return fmt.Errorf("expected number, got %T", data)
-			}
-		}
-		
-		if schemaType == "integer" && num != float64(int64(num)) {
-			return fmt.Errorf("expected integer, got float %v", num)
-		}
-		
-		// Validate number constraints
-		if minimum, ok := schema["minimum"].(float64); ok && num < minimum {
-			return fmt.Errorf("value %v is less than minimum %v", num, minimum)
-		}
-		if maximum, ok := schema["maximum"].(float64); ok && num > maximum {
-			return fmt.Errorf("value %v exceeds maximum %v", num, maximum)
-		}
-		
-	case "boolean":
-		if _, ok := data.(bool); !ok {
-			return fmt.Errorf("expected boolean, got %T", data)
+// mapAnyTypes gives struct fields of type any, map[string]any and []any
+// schemas that provider tool validation accepts. By default the reflector
+// emits the boolean schema true for any, which OpenAI rejects, and drops
+// additionalProperties from maps of any. It also maps time.Duration to a
+// number of nanoseconds.
+func mapAnyTypes(t reflect.Type) *jsonschema.Schema {
+	switch {
+	case t == durationType:
+		return durationSchema()
+	case isEmptyInterface(t):
+		return anySchema()
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && isEmptyInterface(t.Elem()):
+		return &jsonschema.Schema{
+			Type:                 "object",
+			AdditionalProperties: jsonschema.TrueSchema,
 		}
-		
-	case "null":
-		if data != nil {
-			return fmt.Errorf("expected null, got %T", data)
+	case t.Kind() == reflect.Slice && isEmptyInterface(t.Elem()):
+		return &jsonschema.Schema{
+			Type:  "array",
+			Items: anySchema(),
 		}
-		
-	default:
-		return fmt.Errorf("unknown schema type: %s", schemaType)
 	}
-	
 	return nil
 }
 
+// isEmptyInterface reports whether t is interface{} (any).
+func isEmptyInterface(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && t.NumMethod() == 0
+}
+
+// anySchema returns a schema that marshals to {}, accepting any JSON value.
+// A zero jsonschema.Schema would marshal to the boolean schema true instead,
+// so a non-nil Extras map is set to tell them apart.
+func anySchema() *jsonschema.Schema {
+	return &jsonschema.Schema{Extras: map[string]any{}}
+}
+
+// ValidateJSON validates JSON data against a JSON Schema.
+// This is used for runtime validation when providers don't support strict mode.
+// It is core.ValidateJSON, so tool inputs are checked like every other schema
+// in the module.
+func ValidateJSON(data json.RawMessage, schema []byte) error {
+	return core.ValidateJSON(data, schema)
+}
+
 // RepairJSON attempts to fix common JSON errors and make it conform to a schema.
 // This is useful for providers that don't have strict JSON mode.
 func RepairJSON(data json.RawMessage, schema []byte) (json.RawMessage, error) {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // Test structures for schema generation
@@ -42,6 +43,15 @@ type StructWithTags struct {
 	Description  string  `json:"description" jsonschema:"description=This is a description field"`
 }
 
+type StructWithConstraints struct {
+	Age      int     `json:"age" jsonschema:"required,minimum=0,maximum=150"`
+	Ratio    float64 `json:"ratio,omitempty" jsonschema:"exclusiveMinimum=0,exclusiveMaximum=1"`
+	Username string  `json:"username" jsonschema:"required,minLength=1,maxLength=16,pattern=^[a-z]+$"`
+	Code     string  `json:"code,omitempty" jsonschema:"pattern=^[A-Z]{2\\,3}$"`
+	Homepage string  `json:"homepage,omitempty" jsonschema:"format=uri"`
+	Priority int     `json:"priority,omitempty" jsonschema:"enum=1,enum=2,enum=3"`
+}
+
 type RecursiveStruct struct {
 	Name     string           `json:"name"`
 	Children []RecursiveStruct `json:"children"`
@@ -194,6 +204,33 @@ func TestGenerateSchemaWithNested(t *testing.T) {
 	}
 }
 
+func TestSchema(t *testing.T) {
+	type Recipe struct {
+		Name        string   `json:"name"`
+		Ingredients []string `json:"ingredients"`
+	}
+
+	want, err := GenerateSchema(reflect.TypeOf(Recipe{}))
+	if err != nil {
+		t.Fatalf("GenerateSchema failed: %v", err)
+	}
+	if got := Schema[Recipe](); string(got) != string(want) {
+		t.Errorf("Schema[Recipe]() = %s, want %s", got, want)
+	}
+	if got := MustSchema[Recipe](); string(got) != string(want) {
+		t.Errorf("MustSchema[Recipe]() = %s, want %s", got, want)
+	}
+
+	// Interface types are reflected by type rather than by value
+	var schema map[string]any
+	if err := json.Unmarshal(Schema[any](), &schema); err != nil {
+		t.Fatalf("Schema[any]() is not valid JSON: %v", err)
+	}
+	if schema["type"] != "object" {
+		t.Errorf("Schema[any]() type = %v, want object", schema["type"])
+	}
+}
+
 func TestSchemaCache(t *testing.T) {
 	// Clear cache first
 	ClearSchemaCache()
@@ -448,6 +485,133 @@ func TestValidateJSONEnum(t *testing.T) {
 	}
 }
 
+func TestGenerateSchemaWithConstraints(t *testing.T) {
+	schemaJSON, err := GenerateSchema(reflect.TypeOf(StructWithTags{}))
+	if err != nil {
+		t.Fatalf("GenerateSchema failed: %v", err)
+	}
+	
+	var schema map[string]interface{}
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		t.Fatalf("Failed to unmarshal schema: %v", err)
+	}
+	props := schema["properties"].(map[string]interface{})
+	
+	tests := []struct {
+		field    string
+		keyword  string
+		expected interface{}
+	}{
+		{"min_max_int", "minimum", 1.0},
+		{"min_max_int", "maximum", 100.0},
+		{"min_max_float", "minimum", 0.0},
+		{"min_max_float", "maximum", 1.0},
+		{"pattern_field", "pattern", "^[A-Z][a-z]+$"},
+		{"description", "description", "This is a description field"},
+	}
+	
+	for _, tt := range tests {
+		prop := props[tt.field].(map[string]interface{})
+		if got := prop[tt.keyword]; got != tt.expected {
+			t.Errorf("%s.%s = %v, want %v", tt.field, tt.keyword, got, tt.expected)
+		}
+	}
+	
+	enum := props["enum_field"].(map[string]interface{})["enum"].([]interface{})
+	if len(enum) != 3 || enum[0] != "red" {
+		t.Errorf("Unexpected enum: %v", enum)
+	}
+	
+	// Escaped commas, string lengths and formats
+	schemaJSON, err = GenerateSchema(reflect.TypeOf(StructWithConstraints{}))
+	if err != nil {
+		t.Fatalf("GenerateSchema failed: %v", err)
+	}
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		t.Fatalf("Failed to unmarshal schema: %v", err)
+	}
+	props = schema["properties"].(map[string]interface{})
+	
+	username := props["username"].(map[string]interface{})
+	if username["minLength"] != 1.0 || username["maxLength"] != 16.0 {
+		t.Errorf("Expected username length bounds, got %v", username)
+	}
+	if code := props["code"].(map[string]interface{}); code["pattern"] != "^[A-Z]{2,3}$" {
+		t.Errorf("Expected escaped comma in pattern, got %v", code["pattern"])
+	}
+	if homepage := props["homepage"].(map[string]interface{}); homepage["format"] != "uri" {
+		t.Errorf("Expected uri format, got %v", homepage["format"])
+	}
+}
+
+func TestValidateJSONConstraints(t *testing.T) {
+	schema, err := GenerateSchema(reflect.TypeOf(StructWithConstraints{}))
+	if err != nil {
+		t.Fatalf("GenerateSchema failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name:    "Valid input",
+			data:    `{"age": 30, "ratio": 0.5, "username": "alice", "code": "ABC", "homepage": "https://example.com", "priority": 2}`,
+			wantErr: false,
+		},
+		{
+			name:    "Below minimum",
+			data:    `{"age": -1, "username": "alice"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Exclusive bound",
+			data:    `{"age": 30, "ratio": 1, "username": "alice"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Empty string below minLength",
+			data:    `{"age": 30, "username": ""}`,
+			wantErr: true,
+		},
+		{
+			name:    "String above maxLength",
+			data:    `{"age": 30, "username": "abcdefghijklmnopq"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Pattern mismatch",
+			data:    `{"age": 30, "username": "Alice"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Pattern with quantifier",
+			data:    `{"age": 30, "username": "alice", "code": "ABCD"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid uri",
+			data:    `{"age": 30, "username": "alice", "homepage": "not a uri"}`,
+			wantErr: true,
+		},
+		{
+			name:    "Integer not in enum",
+			data:    `{"age": 30, "username": "alice", "priority": 5}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSON(json.RawMessage(tt.data), schema)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestRepairJSON(t *testing.T) {
 	schema := []byte(`{
 		"type": "object",
@@ -568,6 +732,96 @@ func TestSpecialTypeSchemas(t *testing.T) {
 	}
 }
 
+// StructWithAnyFields uses the flexible field types common in tool inputs.
+type StructWithAnyFields struct {
+	Data    map[string]interface{} `json:"data"`
+	Value   any                    `json:"value"`
+	Items   []interface{}          `json:"items"`
+	Options *map[string]any        `json:"options,omitempty"`
+}
+
+func TestGenerateSchemaWithAnyFields(t *testing.T) {
+	schema, err := GenerateSchema(reflect.TypeOf(StructWithAnyFields{}))
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	var schemaMap map[string]interface{}
+	if err := json.Unmarshal(schema, &schemaMap); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+	properties := schemaMap["properties"].(map[string]interface{})
+
+	// Every property must be a schema object; OpenAI rejects boolean schemas
+	for name, prop := range properties {
+		if _, ok := prop.(map[string]interface{}); !ok {
+			t.Errorf("Property %s should be a schema object, got %v", name, prop)
+		}
+	}
+
+	for _, name := range []string{"data", "options"} {
+		field := properties[name].(map[string]interface{})
+		if field["type"] != "object" || field["additionalProperties"] != true {
+			t.Errorf("%s should be an object with additionalProperties, got %v", name, field)
+		}
+	}
+	if value := properties["value"].(map[string]interface{}); len(value) != 0 {
+		t.Errorf("value should be the empty schema, got %v", value)
+	}
+	items := properties["items"].(map[string]interface{})
+	if items["type"] != "array" || !reflect.DeepEqual(items["items"], map[string]interface{}{}) {
+		t.Errorf("items should be an array of any, got %v", items)
+	}
+
+	// The schema accepts arbitrary values in the flexible fields
+	data := json.RawMessage(`{"data":{"a":1,"b":[true]},"value":"x","items":[1,"two",{"three":3}]}`)
+	if err := ValidateJSON(data, schema); err != nil {
+		t.Errorf("Expected flexible data to validate: %v", err)
+	}
+}
+
+// StructWithTimeFields uses the time types with special schema handling
+type StructWithTimeFields struct {
+	At      time.Time     `json:"at"`
+	Day     time.Time     `json:"day" jsonschema:"format=date"`
+	Timeout time.Duration `json:"timeout"`
+	Every   time.Duration `json:"every" jsonschema:"type=string,format=duration"`
+	Nested  struct {
+		Wait *time.Duration `json:"wait,omitempty"`
+	} `json:"nested"`
+}
+
+func TestGenerateSchemaWithTimeFields(t *testing.T) {
+	schema, err := GenerateSchema(reflect.TypeOf(StructWithTimeFields{}))
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+
+	var schemaMap map[string]interface{}
+	if err := json.Unmarshal(schema, &schemaMap); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+	properties := schemaMap["properties"].(map[string]interface{})
+	nested := properties["nested"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	tests := []struct {
+		name string
+		prop interface{}
+		want map[string]interface{}
+	}{
+		{"at", properties["at"], map[string]interface{}{"type": "string", "format": "date-time"}},
+		{"day", properties["day"], map[string]interface{}{"type": "string", "format": "date"}},
+		{"timeout", properties["timeout"], map[string]interface{}{"type": "number", "description": "duration in nanoseconds"}},
+		{"every", properties["every"], map[string]interface{}{"type": "string", "format": "duration"}},
+		{"nested.wait", nested["wait"], map[string]interface{}{"type": "number", "description": "duration in nanoseconds"}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.prop, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.prop, tt.want)
+		}
+	}
+}
+
 func TestGetDefaultValue(t *testing.T) {
 	tests := []struct {
 		name     string
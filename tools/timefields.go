@@ -0,0 +1,234 @@
+// Package tools provides schema and input handling for time types.
+// This file describes time.Duration fields in generated schemas and converts
+// human-readable dates and durations in tool inputs before they are decoded.
+
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/invopop/jsonschema"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// durationDescription documents the default encoding of time.Duration.
+const durationDescription = "duration in nanoseconds"
+
+// durationSchema is the schema for time.Duration fields. Tag a field with
+// `jsonschema:"type=string,format=duration"` to accept strings such as
+// "1h30m" instead; Exec converts them before decoding.
+func durationSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "number"}
+}
+
+// describeDurations adds a description to time.Duration properties of s,
+// which the reflector clears when applying field tags.
+func describeDurations(s *jsonschema.Schema, t reflect.Type) {
+	walkTimeFields(t, s, map[reflect.Type]bool{}, func(f reflect.StructField, prop *jsonschema.Schema) {
+		if derefType(f.Type) == durationType && prop.Type == "number" && prop.Description == "" {
+			prop.Description = durationDescription
+		}
+	})
+}
+
+// walkTimeFields calls fn for every struct field reachable from t, together
+// with its property schema in s.
+func walkTimeFields(t reflect.Type, s *jsonschema.Schema, seen map[reflect.Type]bool, fn func(reflect.StructField, *jsonschema.Schema)) {
+	t = derefType(t)
+	if s == nil || seen[t] {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		walkTimeFields(t.Elem(), s.Items, seen, fn)
+	case reflect.Struct:
+		if t == timeType || s.Properties == nil {
+			return
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, ok := jsonFieldName(f)
+			if !ok {
+				continue
+			}
+			if name == "" {
+				// Embedded structs are flattened into the parent
+				walkTimeFields(f.Type, s, seen, fn)
+				continue
+			}
+			prop, ok := s.Properties.Get(name)
+			if !ok {
+				continue
+			}
+			fn(f, prop)
+			walkTimeFields(f.Type, prop, seen, fn)
+		}
+	}
+}
+
+// jsonFieldName returns the JSON name of an exported field. An empty name
+// means an embedded struct whose fields are promoted.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		if f.Anonymous && derefType(f.Type).Kind() == reflect.Struct {
+			return "", true
+		}
+		name = f.Name
+	}
+	return name, true
+}
+
+// derefType strips pointer indirections from t.
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// needsTimeNormalization caches, per input type, whether any field is a
+// date-formatted time.Time or a time.Duration.
+var needsTimeNormalization sync.Map
+
+// normalizeTimeInput rewrites values that encoding/json cannot decode into
+// time fields of t: "2006-01-02" dates in time.Time fields tagged
+// format=date become RFC 3339 timestamps, and duration strings such as
+// "1h30m" in time.Duration fields become nanoseconds. Other input is
+// returned unchanged.
+func normalizeTimeInput(raw json.RawMessage, t reflect.Type) json.RawMessage {
+	needed, ok := needsTimeNormalization.Load(t)
+	if !ok {
+		needed = hasTimeFields(t, map[reflect.Type]bool{})
+		needsTimeNormalization.Store(t, needed)
+	}
+	if !needed.(bool) {
+		return raw
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return raw
+	}
+	if !normalizeTimeValue(value, t) {
+		return raw
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return raw
+	}
+	return normalized
+}
+
+// hasTimeFields reports whether t contains a field normalizeTimeValue may
+// rewrite.
+func hasTimeFields(t reflect.Type, seen map[reflect.Type]bool) bool {
+	t = derefType(t)
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return hasTimeFields(t.Elem(), seen)
+	case reflect.Struct:
+		if t == timeType {
+			return false
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if _, ok := jsonFieldName(f); !ok {
+				continue
+			}
+			ft := derefType(f.Type)
+			if ft == durationType || (ft == timeType && hasDateFormat(f)) || hasTimeFields(ft, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeTimeValue rewrites time values in a decoded JSON value of type t
+// in place and reports whether anything changed.
+func normalizeTimeValue(value any, t reflect.Type) bool {
+	t = derefType(t)
+	changed := false
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		items, _ := value.([]any)
+		for _, item := range items {
+			changed = normalizeTimeValue(item, t.Elem()) || changed
+		}
+	case reflect.Map:
+		obj, _ := value.(map[string]any)
+		for _, item := range obj {
+			changed = normalizeTimeValue(item, t.Elem()) || changed
+		}
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok || t == timeType {
+			return false
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, ok := jsonFieldName(f)
+			if !ok {
+				continue
+			}
+			if name == "" {
+				changed = normalizeTimeValue(obj, f.Type) || changed
+				continue
+			}
+			v, ok := obj[name]
+			if !ok {
+				continue
+			}
+			ft := derefType(f.Type)
+			switch s, isString := v.(string); {
+			case ft == timeType && isString && hasDateFormat(f):
+				if date, err := time.Parse(time.DateOnly, s); err == nil {
+					obj[name] = date.Format(time.RFC3339)
+					changed = true
+				}
+			case ft == durationType && isString:
+				if d, err := time.ParseDuration(s); err == nil {
+					obj[name] = int64(d)
+					changed = true
+				}
+			default:
+				changed = normalizeTimeValue(v, f.Type) || changed
+			}
+		}
+	}
+	return changed
+}
+
+// hasDateFormat reports whether f is tagged `jsonschema:"format=date"`.
+func hasDateFormat(f reflect.StructField) bool {
+	for _, tag := range strings.Split(f.Tag.Get("jsonschema"), ",") {
+		if tag == "format=date" {
+			return true
+		}
+	}
+	return false
+}
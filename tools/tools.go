@@ -34,6 +34,19 @@ type Meta struct {
 	Provider string
 	// Metadata contains arbitrary key-value pairs for telemetry
 	Metadata map[string]any
+	// Input is the decoded input passed to the tool function, after any
+	// input sanitizer has run. Use it for logging instead of the raw JSON.
+	Input any
+	// ParallelCallCount is the number of tool calls issued in this step that
+	// the runner executes in parallel, including this one; 1 when calls run
+	// sequentially. It is not the effective concurrency: the runner's
+	// parallelism limit may run fewer of them at once. Use it to coordinate
+	// access to shared resources such as database locks.
+	ParallelCallCount int
+	// ParallelCallIndex is the 0-based position of this call among the
+	// ParallelCallCount calls issued in this step; 0 when calls run
+	// sequentially.
+	ParallelCallIndex int
 }
 
 // Handle is the interface that all tools must implement.
@@ -49,6 +62,8 @@ type Handle interface {
 	OutSchemaJSON() []byte
 	// Exec executes the tool with raw JSON input and returns the result
 	Exec(ctx context.Context, raw json.RawMessage, meta Meta) (any, error)
+	// Documentation returns a description of the tool for reference docs
+	Documentation() ToolDoc
 }
 
 // Tool represents a typed tool with specific input and output types.
@@ -66,6 +81,9 @@ type Tool[I any, O any] struct {
 	cacheable      bool // whether results can be cached
 	maxInputSize   int  // maximum input size in bytes, 0 means no limit
 	maxOutputSize  int  // maximum output size in bytes, 0 means no limit
+	examples       []ToolExample // usage examples for documentation
+	validateOutput func(O) error // business validation of successful results
+	sanitizeInput  func(I) (I, error) // normalization of decoded inputs
 }
 
 // New creates a new typed tool with the given name, description, and execution function.
@@ -166,44 +184,57 @@ func (t *Tool[I, O]) OutSchemaJSON() []byte {
 	return t.outSchema
 }
 
-// Exec executes the tool with the given raw JSON input.
-// It handles JSON unmarshaling, type validation, execution, and result marshaling.
-// It also records observability metrics if configured.
-func (t *Tool[I, O]) Exec(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
-	// Start tool span for observability
-	startTime := time.Now()
-	ctx, span := obs.StartToolSpan(ctx, obs.ToolSpanOptions{
-		ToolName:   t.name,
-		ToolID:     meta.CallID,
-		InputSize:  len(raw),
-		StepNumber: meta.StepNumber,
-		Parallel:   false, // Will be set by runner if parallel
-		RetryCount: 0,     // Will be incremented on retries
-		Timeout:    time.Duration(t.timeout) * time.Second,
-	})
-	defer span.End()
+// decodeInput applies the size limit, unmarshals, validates and sanitizes
+// one raw input. Exec and ExecBatch share it so a tool handles its input the
+// same way whether or not its calls are batched. On failure it also returns
+// the failed stage for the tool span.
+func (t *Tool[I, O]) decodeInput(raw json.RawMessage) (I, string, error) {
+	var input I
 	
 	// Check input size limit
 	if t.maxInputSize > 0 && len(raw) > t.maxInputSize {
-		err := fmt.Errorf("input size %d exceeds maximum %d", len(raw), t.maxInputSize)
-		obs.RecordError(span, err, "Input size validation failed")
-		return nil, err
+		return input, "Input size validation failed", fmt.Errorf("input size %d exceeds maximum %d", len(raw), t.maxInputSize)
 	}
 	
-	// Unmarshal input
-	var input I
-	if err := json.Unmarshal(raw, &input); err != nil {
-		err = fmt.Errorf("failed to unmarshal input for tool %s: %w", t.name, err)
-		obs.RecordError(span, err, "Input unmarshaling failed")
-		return nil, err
+	// Unmarshal input, accepting the date and duration strings the schema allows
+	if err := json.Unmarshal(normalizeTimeInput(raw, reflect.TypeOf((*I)(nil)).Elem()), &input); err != nil {
+		return input, "Input unmarshaling failed", fmt.Errorf("failed to unmarshal input for tool %s: %w", t.name, err)
 	}
 	
 	// Validate input against schema if strict validation is enabled
 	if err := ValidateJSON(raw, t.InSchemaJSON()); err != nil {
-		err = fmt.Errorf("input validation failed for tool %s: %w", t.name, err)
-		obs.RecordError(span, err, "Schema validation failed")
+		return input, "Schema validation failed", fmt.Errorf("input validation failed for tool %s: %w", t.name, err)
+	}
+	
+	// Normalize the decoded input before it reaches the tool function
+	if t.sanitizeInput != nil {
+		sanitized, err := t.sanitizeInput(input)
+		if err != nil {
+			return input, "Input sanitization failed", fmt.Errorf("input sanitization failed for tool %s: %w", t.name, err)
+		}
+		input = sanitized
+	}
+	return input, "", nil
+}
+
+// Exec executes the tool with the given raw JSON input.
+// It handles JSON unmarshaling, type validation, execution, and result marshaling.
+// It also records observability metrics if configured.
+func (t *Tool[I, O]) Exec(ctx context.Context, raw json.RawMessage, meta Meta) (any, error) {
+	// Start tool span for observability
+	startTime := time.Now()
+	// Name, description, schema and input are recorded immediately
+	ctx, span := obs.StartToolSpanFromHandle(ctx, t, meta.CallID, raw)
+	obs.RecordToolStep(span, meta.StepNumber)
+	defer span.End()
+	
+	// Decode, validate and sanitize the input
+	input, stage, err := t.decodeInput(raw)
+	if err != nil {
+		obs.RecordError(span, err, stage)
 		return nil, err
 	}
+	meta.Input = input
 	
 	// Execute the tool
 	output, err := t.execute(ctx, input, meta)
@@ -212,12 +243,23 @@ func (t *Tool[I, O]) Exec(ctx context.Context, raw json.RawMessage, meta Meta) (
 		obs.RecordError(span, err, "Tool execution failed")
 		obs.RecordToolResult(span, false, 0, time.Since(startTime))
 		
-		// Record tool content with error for Braintrust display
-		obs.RecordToolContent(span, t.name, raw, nil, err)
+		// Record tool error for Braintrust display
+		obs.RecordToolResultContent(span, nil, err)
 		
 		return nil, err
 	}
 	
+	// Validate output against business rules
+	if t.validateOutput != nil {
+		if err := t.validateOutput(output); err != nil {
+			err = fmt.Errorf("output validation failed for tool %s: %w", t.name, asToolValidationError(err))
+			obs.RecordError(span, err, "Output validation failed")
+			obs.RecordToolResult(span, false, 0, time.Since(startTime))
+			obs.RecordToolResultContent(span, nil, err)
+			return nil, err
+		}
+	}
+	
 	// Check output size if needed (marshal to check size)
 	outputSize := 0
 	if t.maxOutputSize > 0 {
@@ -243,8 +285,8 @@ func (t *Tool[I, O]) Exec(ctx context.Context, raw json.RawMessage, meta Meta) (
 	// Record successful execution
 	obs.RecordToolResult(span, true, outputSize, time.Since(startTime))
 	
-	// Record tool content for Braintrust display
-	obs.RecordToolContent(span, t.name, raw, output, nil)
+	// Record tool output for Braintrust display
+	obs.RecordToolResultContent(span, output, nil)
 	
 	// Record metrics
 	obs.RecordToolExecution(ctx, t.name, true, time.Since(startTime))
@@ -282,6 +324,19 @@ func (t *Tool[I, O]) WithMaxOutputSize(bytes int) *Tool[I, O] {
 	return t
 }
 
+// WithExamples adds usage examples to the tool's documentation.
+func (t *Tool[I, O]) WithExamples(examples ...ToolExample) *Tool[I, O] {
+	t.examples = append(t.examples, examples...)
+	return t
+}
+
+// Documentation returns schema-derived documentation including any examples.
+func (t *Tool[I, O]) Documentation() ToolDoc {
+	doc := DefaultDocumentation(t)
+	doc.Examples = append([]ToolExample(nil), t.examples...)
+	return doc
+}
+
 // IsRetryable returns whether the tool can be safely retried.
 func (t *Tool[I, O]) IsRetryable() bool {
 	return t.retryable
@@ -349,6 +404,13 @@ func MaxOutputSize[I any, O any](bytes int) ToolOption[I, O] {
 	}
 }
 
+// Examples returns a ToolOption that adds documentation examples.
+func Examples[I any, O any](examples ...ToolExample) ToolOption[I, O] {
+	return func(t *Tool[I, O]) {
+		t.examples = append(t.examples, examples...)
+	}
+}
+
 // Registry manages a collection of tools and provides lookup capabilities.
 type Registry struct {
 	tools map[string]Handle
@@ -446,4 +508,4 @@ func List() []string {
 // All returns all tools from the default registry.
 func All() []Handle {
 	return DefaultRegistry.All()
-}
\ No newline at end of file
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -223,6 +224,120 @@ func TestToolOutputSizeLimit(t *testing.T) {
 	}
 }
 
+func TestToolOutputValidator(t *testing.T) {
+	tool := NewWithOptions[SimpleInput, SimpleOutput](
+		"validated_tool",
+		"Tool with output validation",
+		func(ctx context.Context, in SimpleInput, meta Meta) (SimpleOutput, error) {
+			return SimpleOutput{Message: in.Name, Success: in.Age >= 0}, nil
+		},
+		WithOutputValidator[SimpleInput](func(out SimpleOutput) error {
+			if out.Message == "" {
+				return &ToolValidationError{Field: "message", Message: "must not be empty"}
+			}
+			if !out.Success {
+				return errors.New("operation was not successful")
+			}
+			return nil
+		}),
+	)
+
+	result, err := tool.Exec(context.Background(), json.RawMessage(`{"name": "Alice", "age": 30}`), Meta{})
+	if err != nil {
+		t.Fatalf("Unexpected error for valid output: %v", err)
+	}
+	if result.(SimpleOutput).Message != "Alice" {
+		t.Errorf("Unexpected result: %v", result)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		field   string
+		message string
+	}{
+		{"field error", `{"name": "", "age": 30}`, "message", "must not be empty"},
+		{"plain error", `{"name": "Bob", "age": -1}`, "", "operation was not successful"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tool.Exec(context.Background(), json.RawMessage(tt.input), Meta{})
+			var ve *ToolValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("Expected ToolValidationError, got %v", err)
+			}
+			if ve.Field != tt.field || ve.Message != tt.message {
+				t.Errorf("Got field %q message %q, want %q %q", ve.Field, ve.Message, tt.field, tt.message)
+			}
+		})
+	}
+}
+
+func TestToolInputSanitizer(t *testing.T) {
+	var gotMeta Meta
+	tool := NewWithOptions[SimpleInput, SimpleOutput](
+		"sanitized_tool",
+		"Tool with input sanitization",
+		func(ctx context.Context, in SimpleInput, meta Meta) (SimpleOutput, error) {
+			gotMeta = meta
+			return SimpleOutput{Message: in.Name, Success: true}, nil
+		},
+		WithInputSanitizer[SimpleInput, SimpleOutput](func(in SimpleInput) (SimpleInput, error) {
+			if in.Age < 0 {
+				return in, errors.New("age must not be negative")
+			}
+			in.Name = strings.TrimSpace(in.Name)
+			return in, nil
+		}),
+	)
+
+	result, err := tool.Exec(context.Background(), json.RawMessage(`{"name": "  Alice ", "age": 30}`), Meta{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.(SimpleOutput).Message != "Alice" {
+		t.Errorf("Expected sanitized name, got %q", result.(SimpleOutput).Message)
+	}
+	if in, ok := gotMeta.Input.(SimpleInput); !ok || in.Name != "Alice" {
+		t.Errorf("Expected sanitized input in meta, got %#v", gotMeta.Input)
+	}
+
+	_, err = tool.Exec(context.Background(), json.RawMessage(`{"name": "Bob", "age": -1}`), Meta{})
+	if err == nil || !strings.Contains(err.Error(), "age must not be negative") {
+		t.Errorf("Expected sanitizer error, got %v", err)
+	}
+}
+
+func TestToolExecTimeInputs(t *testing.T) {
+	type Input struct {
+		At    time.Time     `json:"at"`
+		Day   time.Time     `json:"day" jsonschema:"format=date"`
+		Wait  time.Duration `json:"wait"`
+		Every time.Duration `json:"every" jsonschema:"type=string,format=duration"`
+	}
+	var got Input
+	tool := New[Input, bool]("schedule", "Schedule a job",
+		func(ctx context.Context, in Input, meta Meta) (bool, error) {
+			got = in
+			return true, nil
+		})
+
+	raw := json.RawMessage(`{"at":"2024-03-01T09:30:00+02:00","day":"2024-03-02","wait":1500000000,"every":"1h30m"}`)
+	if _, err := tool.Exec(context.Background(), raw, Meta{}); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	if want := time.Date(2024, 3, 1, 7, 30, 0, 0, time.UTC); !got.At.Equal(want) {
+		t.Errorf("At = %v, want %v", got.At, want)
+	}
+	if want := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC); !got.Day.Equal(want) {
+		t.Errorf("Day = %v, want %v", got.Day, want)
+	}
+	if got.Wait != 1500*time.Millisecond || got.Every != 90*time.Minute {
+		t.Errorf("unexpected durations: wait %v, every %v", got.Wait, got.Every)
+	}
+}
+
 func TestToolContextCancellation(t *testing.T) {
 	tool := New[SimpleInput, SimpleOutput](
 		"slow_tool",
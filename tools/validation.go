@@ -0,0 +1,74 @@
+package tools
+
+import "errors"
+
+// ToolValidationError reports a tool result that is well-formed but violates
+// business rules, such as a negative price or an empty required string. It is
+// returned from Exec like any other tool error, so the model sees it as a
+// failed call and can retry with corrected parameters.
+type ToolValidationError struct {
+	// Field is the output field that failed validation (optional)
+	Field string
+	// Message describes why the output is invalid
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ToolValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// WithOutputValidator returns a ToolOption that calls fn on every successful
+// result. When fn returns an error, Exec fails with a *ToolValidationError;
+// errors that are not already a ToolValidationError become its Message.
+//
+// Example:
+//
+//	tool := tools.NewWithOptions("get_price", "Look up a product price", getPrice,
+//		tools.WithOutputValidator[PriceInput](func(out PriceOutput) error {
+//			if out.Price < 0 {
+//				return &tools.ToolValidationError{Field: "price", Message: "must not be negative"}
+//			}
+//			return nil
+//		}),
+//	)
+func WithOutputValidator[I any, O any](fn func(O) error) ToolOption[I, O] {
+	return func(t *Tool[I, O]) {
+		t.validateOutput = fn
+	}
+}
+
+// WithInputSanitizer returns a ToolOption that runs fn on every decoded input
+// before the tool function is called, separating input cleanup such as
+// trimming whitespace or normalizing URLs from business logic. The sanitized
+// input is passed to the tool function and exposed as Meta.Input. When fn
+// returns an error, Exec fails with it and the tool function is not called.
+//
+// Example:
+//
+//	tool := tools.NewWithOptions("fetch_page", "Fetch a web page", fetchPage,
+//		tools.WithInputSanitizer[FetchInput, FetchOutput](func(in FetchInput) (FetchInput, error) {
+//			in.URL = strings.TrimSpace(in.URL)
+//			if !strings.Contains(in.URL, "://") {
+//				in.URL = "https://" + in.URL
+//			}
+//			return in, nil
+//		}),
+//	)
+func WithInputSanitizer[I any, O any](fn func(I) (I, error)) ToolOption[I, O] {
+	return func(t *Tool[I, O]) {
+		t.sanitizeInput = fn
+	}
+}
+
+// asToolValidationError converts a validator error to a *ToolValidationError.
+func asToolValidationError(err error) *ToolValidationError {
+	var ve *ToolValidationError
+	if errors.As(err, &ve) {
+		return ve
+	}
+	return &ToolValidationError{Message: err.Error()}
+}